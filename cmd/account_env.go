@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// envAccountName is the name of the account built from the environment
+// variables when no configuration file account is available.
+const envAccountName = "<environment variables>"
+
+// accountEnv represents the account settings overridden by environment
+// variables.
+type accountEnv struct {
+	Key         string
+	Secret      string
+	Environment string
+	Endpoint    string
+	SosEndpoint string
+	Zone        string
+}
+
+// readAccountEnv returns the account settings set in the environment
+// variables, supporting the legacy variable names.
+func readAccountEnv() accountEnv {
+	return accountEnv{
+		Key: readFromEnv(
+			"EXOSCALE_API_KEY",
+			"EXOSCALE_KEY",
+			"CLOUDSTACK_KEY",
+			"CLOUDSTACK_API_KEY",
+		),
+		Secret: readFromEnv(
+			"EXOSCALE_API_SECRET",
+			"EXOSCALE_SECRET",
+			"EXOSCALE_SECRET_KEY",
+			"CLOUDSTACK_SECRET",
+			"CLOUDSTACK_SECRET_KEY",
+		),
+		Environment: readFromEnv("EXOSCALE_API_ENVIRONMENT"),
+		Endpoint: readFromEnv(
+			"EXOSCALE_API_ENDPOINT",
+			"EXOSCALE_COMPUTE_API_ENDPOINT",
+			"EXOSCALE_ENDPOINT",
+			"EXOSCALE_COMPUTE_ENDPOINT",
+			"CLOUDSTACK_ENDPOINT",
+		),
+		SosEndpoint: readFromEnv(
+			"EXOSCALE_STORAGE_API_ENDPOINT",
+			"EXOSCALE_SOS_ENDPOINT",
+		),
+		Zone: readFromEnv("EXOSCALE_ZONE"),
+	}
+}
+
+// hasCredentials returns true if both the API key and secret are set, in
+// which case no configuration file is required.
+func (e accountEnv) hasCredentials() bool {
+	return e.Key != "" && e.Secret != ""
+}
+
+// isSet returns true if at least one account setting is overridden.
+func (e accountEnv) isSet() bool {
+	return e != accountEnv{}
+}
+
+// apply overrides the settings of the account acc with the ones set in the
+// environment variables. The API credentials are only overridden if both the
+// key and secret are set.
+func (e accountEnv) apply(acc *account) {
+	if e.hasCredentials() {
+		acc.Key = e.Key
+		acc.Secret = e.Secret
+		acc.SecretCommand = nil
+		acc.SecretStore = ""
+	}
+
+	if e.Environment != "" {
+		acc.Environment = e.Environment
+	}
+
+	if e.Endpoint != "" {
+		acc.Endpoint = e.Endpoint
+		// The DNS endpoint is derived from the overridden API endpoint.
+		acc.DNSEndpoint = ""
+	}
+
+	if e.SosEndpoint != "" {
+		acc.SosEndpoint = e.SosEndpoint
+	}
+
+	if e.Zone != "" {
+		acc.DefaultZone = e.Zone
+	}
+}
+
+// setAccountDefaults sets the default value of the account acc settings
+// left unset.
+func setAccountDefaults(acc *account) {
+	if acc.Endpoint == "" {
+		if acc.ComputeEndpoint != "" {
+			acc.Endpoint = acc.ComputeEndpoint
+		} else {
+			acc.Endpoint = defaultEndpoint
+		}
+	}
+
+	if acc.Environment == "" {
+		acc.Environment = defaultEnvironment
+	}
+
+	if acc.DefaultZone == "" {
+		acc.DefaultZone = defaultZone
+	}
+
+	if acc.DNSEndpoint == "" {
+		acc.DNSEndpoint = buildDNSAPIEndpoint(acc.Endpoint)
+	}
+
+	if acc.DefaultTemplate == "" {
+		acc.DefaultTemplate = defaultTemplate
+	}
+
+	if acc.SosEndpoint == "" {
+		acc.SosEndpoint = defaultSosEndpoint
+	}
+
+	if acc.RunstatusEndpoint == "" {
+		acc.RunstatusEndpoint = defaultRunstatusEndpoint
+	}
+
+	acc.Endpoint = strings.TrimRight(acc.Endpoint, "/")
+	acc.DNSEndpoint = strings.TrimRight(acc.DNSEndpoint, "/")
+	acc.SosEndpoint = strings.TrimRight(acc.SosEndpoint, "/")
+	acc.RunstatusEndpoint = strings.TrimRight(acc.RunstatusEndpoint, "/")
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// setAccountEnv sets the environment variables vars for the duration of the
+// test t, unsetting all the other account environment variables.
+func setAccountEnv(t *testing.T, vars map[string]string) {
+	for _, k := range []string{
+		"EXOSCALE_API_KEY", "EXOSCALE_KEY", "CLOUDSTACK_KEY", "CLOUDSTACK_API_KEY",
+		"EXOSCALE_API_SECRET", "EXOSCALE_SECRET", "EXOSCALE_SECRET_KEY", "CLOUDSTACK_SECRET", "CLOUDSTACK_SECRET_KEY",
+		"EXOSCALE_API_ENVIRONMENT",
+		"EXOSCALE_API_ENDPOINT", "EXOSCALE_COMPUTE_API_ENDPOINT", "EXOSCALE_ENDPOINT", "EXOSCALE_COMPUTE_ENDPOINT",
+		"CLOUDSTACK_ENDPOINT",
+		"EXOSCALE_STORAGE_API_ENDPOINT", "EXOSCALE_SOS_ENDPOINT",
+		"EXOSCALE_ZONE",
+		"EXOSCALE_CONFIG", "EXOSCALE_ACCOUNT",
+	} {
+		k := k
+		if v, ok := os.LookupEnv(k); ok {
+			t.Cleanup(func() { _ = os.Setenv(k, v) })
+		} else {
+			t.Cleanup(func() { _ = os.Unsetenv(k) })
+		}
+		require.NoError(t, os.Unsetenv(k))
+	}
+
+	for k, v := range vars {
+		require.NoError(t, os.Setenv(k, v))
+	}
+}
+
+func Test_readAccountEnv(t *testing.T) {
+	setAccountEnv(t, map[string]string{
+		"EXOSCALE_API_KEY":         "EXOkey",
+		"CLOUDSTACK_SECRET":        "secret",
+		"EXOSCALE_API_ENVIRONMENT": "ppapi",
+		"EXOSCALE_SOS_ENDPOINT":    "https://sos-de-fra-1.exo.io",
+		"EXOSCALE_ZONE":            "de-fra-1",
+	})
+
+	env := readAccountEnv()
+	require.Equal(t, accountEnv{
+		Key:         "EXOkey",
+		Secret:      "secret",
+		Environment: "ppapi",
+		SosEndpoint: "https://sos-de-fra-1.exo.io",
+		Zone:        "de-fra-1",
+	}, env)
+	require.True(t, env.hasCredentials())
+	require.True(t, env.isSet())
+
+	setAccountEnv(t, nil)
+	require.False(t, readAccountEnv().isSet())
+}
+
+func Test_accountEnv_apply(t *testing.T) {
+	configured := func() *account {
+		return &account{
+			Name:          "prod",
+			Key:           "EXOconfig",
+			SecretCommand: []string{"pass", "exoscale"},
+			Endpoint:      "https://api.exoscale.com/v1",
+			DNSEndpoint:   "https://api.exoscale.com/dns",
+			DefaultZone:   "ch-gva-2",
+		}
+	}
+
+	t.Run("full override", func(t *testing.T) {
+		acc := configured()
+		accountEnv{
+			Key:         "EXOenv",
+			Secret:      "secret",
+			Environment: "ppapi",
+			Endpoint:    "https://ppapi.exoscale.com/v1/",
+			SosEndpoint: "https://sos-de-fra-1.exo.io",
+			Zone:        "de-fra-1",
+		}.apply(acc)
+		setAccountDefaults(acc)
+
+		require.Equal(t, "prod", acc.Name)
+		require.Equal(t, "EXOenv", acc.Key)
+		require.Equal(t, "secret", acc.APISecret())
+		require.Equal(t, "ppapi", acc.Environment)
+		require.Equal(t, "https://ppapi.exoscale.com/v1", acc.Endpoint)
+		require.Equal(t, "https://ppapi.exoscale.com/dns", acc.DNSEndpoint)
+		require.Equal(t, "https://sos-de-fra-1.exo.io", acc.SosEndpoint)
+		require.Equal(t, "de-fra-1", acc.DefaultZone)
+	})
+
+	t.Run("partial credentials are ignored", func(t *testing.T) {
+		acc := configured()
+		accountEnv{Key: "EXOenv"}.apply(acc)
+
+		require.Equal(t, "EXOconfig", acc.Key)
+		require.Equal(t, []string{"pass", "exoscale"}, acc.SecretCommand)
+	})
+
+	t.Run("config and defaults", func(t *testing.T) {
+		acc := configured()
+		accountEnv{}.apply(acc)
+		setAccountDefaults(acc)
+
+		require.Equal(t, "ch-gva-2", acc.DefaultZone)
+		require.Equal(t, defaultEnvironment, acc.Environment)
+		require.Equal(t, defaultSosEndpoint, acc.SosEndpoint)
+		require.Equal(t, defaultTemplate, acc.DefaultTemplate)
+	})
+}
+
+func Test_accountEnv_precedence(t *testing.T) {
+	savedAccount := gCurrentAccount
+	t.Cleanup(func() { gCurrentAccount = savedAccount })
+
+	gCurrentAccount = &account{DefaultZone: "ch-gva-2"}
+	accountEnv{Zone: "de-fra-1"}.apply(gCurrentAccount)
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("zone", "", "")
+		return cmd
+	}
+
+	// Environment variables take precedence over the configuration file...
+	cmd := newCmd()
+	cmdSetZoneFlagFromDefault(cmd)
+	require.Equal(t, "de-fra-1", cmd.Flag("zone").Value.String())
+
+	// ...and command flags over environment variables.
+	cmd = newCmd()
+	require.NoError(t, cmd.Flags().Set("zone", "at-vie-1"))
+	cmdSetZoneFlagFromDefault(cmd)
+	require.Equal(t, "at-vie-1", cmd.Flag("zone").Value.String())
+}
+
+func Test_initConfig_withoutConfigFile(t *testing.T) {
+	savedAccount, savedAllAccount, savedConfig := gCurrentAccount, gAllAccount, gConfig
+	savedConfigFilePath, savedConfigFolder, savedOutputFormat := gConfigFilePath, gConfigFolder, gOutputFormat
+	t.Cleanup(func() {
+		gCurrentAccount, gAllAccount, gConfig = savedAccount, savedAllAccount, savedConfig
+		gConfigFilePath, gConfigFolder, gOutputFormat = savedConfigFilePath, savedConfigFolder, savedOutputFormat
+	})
+
+	setAccountEnv(t, map[string]string{
+		"EXOSCALE_API_KEY":    "EXOenv",
+		"EXOSCALE_API_SECRET": "secret",
+		"EXOSCALE_ZONE":       "de-fra-1",
+	})
+
+	// The root persistent flags are merged by Cobra when parsing the command line.
+	RootCmd.Flags().AddFlagSet(RootCmd.PersistentFlags())
+
+	gConfig = viper.New()
+	gConfigFilePath = filepath.Join(t.TempDir(), "missing.toml")
+	gCurrentAccount = &account{}
+	gOutputFormat = ""
+
+	initConfig()
+
+	require.Equal(t, envAccountName, gCurrentAccount.Name)
+	require.Equal(t, "EXOenv", gCurrentAccount.Key)
+	require.Equal(t, "de-fra-1", gCurrentAccount.DefaultZone)
+	require.Equal(t, defaultEndpoint, gCurrentAccount.Endpoint)
+	require.Equal(t, defaultEnvironment, gCurrentAccount.Environment)
+	require.Equal(t, defaultSosEndpoint, gCurrentAccount.SosEndpoint)
+	require.Equal(t, defaultOutputFormat, gOutputFormat)
+	require.Equal(t, []account{*gCurrentAccount}, gAllAccount.Accounts)
+}
+
+func Test_initConfig_envOverridesConfigFile(t *testing.T) {
+	savedAccount, savedAllAccount, savedConfig, savedAccountName := gCurrentAccount, gAllAccount, gConfig, gAccountName
+	savedConfigFilePath, savedConfigFolder, savedOutputFormat := gConfigFilePath, gConfigFolder, gOutputFormat
+	t.Cleanup(func() {
+		gCurrentAccount, gAllAccount, gConfig, gAccountName = savedAccount, savedAllAccount, savedConfig, savedAccountName
+		gConfigFilePath, gConfigFolder, gOutputFormat = savedConfigFilePath, savedConfigFolder, savedOutputFormat
+	})
+
+	setAccountEnv(t, map[string]string{
+		"EXOSCALE_API_ENVIRONMENT": "ppapi",
+		"EXOSCALE_ZONE":            "de-fra-1",
+	})
+
+	configFile := filepath.Join(t.TempDir(), "exoscale.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`defaultaccount = "prod"
+
+[[accounts]]
+name = "prod"
+key = "EXOconfig"
+secret = "secret"
+defaultZone = "ch-gva-2"
+`), 0o600))
+
+	RootCmd.Flags().AddFlagSet(RootCmd.PersistentFlags())
+
+	gConfig = viper.New()
+	gConfigFilePath = configFile
+	gAccountName = ""
+	gCurrentAccount = &account{}
+	gOutputFormat = ""
+
+	initConfig()
+
+	require.Equal(t, "prod", gCurrentAccount.Name)
+	require.Equal(t, "EXOconfig", gCurrentAccount.Key)
+	require.Equal(t, "ppapi", gCurrentAccount.Environment)
+	require.Equal(t, "de-fra-1", gCurrentAccount.DefaultZone)
+
+	// The configured account must not be altered by the environment variables.
+	require.Equal(t, "ch-gva-2", gAllAccount.Accounts[0].DefaultZone)
+	require.Equal(t, "", gAllAccount.Accounts[0].Environment)
+}
@@ -28,6 +28,12 @@ var affinitygroupDeleteCmd = &cobra.Command{
 				return err
 			}
 
+			err = checkResourceReferences(antiAffinityGroupReferencesFinder, allZones,
+				"Anti-Affinity Group", cmd.Name, cmd.ID.String(), force)
+			if err != nil {
+				return err
+			}
+
 			if !force {
 				if !askQuestion(fmt.Sprintf("Are you sure you want to delete Anti-Affinity Group %q?", arg)) {
 					continue
@@ -1,17 +1,51 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/exoscale/egoscale"
 	exov2 "github.com/exoscale/egoscale/v2"
 )
 
+// operationPollInterval is the interval between two polls of an API V2 async
+// operation state when waiting for it to complete.
+const operationPollInterval = 3 * time.Second
+
+// csHTTPClient is the HTTP client of the API V2 client, used to perform the
+// requests to the API endpoints not supported by egoscale.
+var csHTTPClient *http.Client
+
+// apiRequestIDKey is the context key of the failed API request ID recorded by
+// the cliRoundTripper for the apiRequestIDTransport.
+type apiRequestIDKey struct{}
+
+// apiRequestIDTransport is an http.RoundTripper annotating the errors
+// returned for failed API requests with the request ID reported by the API
+// (see apiRequestIDError). It wraps the egoscale transport converting the API
+// error responses into errors, itself wrapping the cliRoundTripper recording
+// the ID of the failed request.
+type apiRequestIDTransport struct {
+	next http.RoundTripper
+}
+
+func (t *apiRequestIDTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var requestID string
+	res, err := t.next.RoundTrip(r.WithContext(context.WithValue(r.Context(), apiRequestIDKey{}, &requestID)))
+
+	return res, withAPIRequestID(err, requestID)
+}
+
 // cliRoundTripper implements the http.RoundTripper interface and allows client
 // request customization, such as HTTP headers injection. If provided with a
 // non-nil next parameter, it will wrap around it when performing requests.
+// The requests performed are recorded for the "--profile" flag report, and
+// traced if the "--debug" flag is set. Idempotent requests failing with a
+// transient error are retried according to the "--api-retries" and
+// "--api-retry-max-wait" flags.
 type cliRoundTripper struct {
 	next http.RoundTripper
 
@@ -39,8 +73,53 @@ func newCLIRoundTripper(next http.RoundTripper, headers map[string]string) cliRo
 }
 
 func (rt cliRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
-	r.Header = rt.reqHeaders
-	return rt.next.RoundTrip(r)
+	r = r.Clone(r.Context())
+	for k, v := range rt.reqHeaders {
+		r.Header[k] = v
+	}
+
+	var reqBody []byte
+	if gDebugLog != nil {
+		reqBody = gDebugLog.readRequestBody(r)
+	}
+
+	retries := 0
+	if isRetryableAPIRequest(r) {
+		retries = gAPIRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		res, err := rt.next.RoundTrip(r)
+		gProfile.record(r, res, err, start)
+		if gDebugLog != nil {
+			gDebugLog.log(r, reqBody, res, err, start)
+		}
+
+		if attempt >= retries || !isTransientAPIError(res, err) {
+			if requestID, ok := r.Context().Value(apiRequestIDKey{}).(*string); ok &&
+				res != nil && res.StatusCode >= http.StatusBadRequest {
+				*requestID = apiRequestID(res)
+			}
+			if err == nil {
+				recordAsyncOperationSubmitted(r, res)
+				recordAsyncOperationState(r, res)
+				recordAsyncOperationID(r, res)
+			}
+			return res, err
+		}
+
+		// The request is retried after a transient error, unless it gets
+		// cancelled (e.g. by the user hitting Ctrl-C) in the meantime.
+		wait := apiRetryWait(attempt, res)
+		_ = res.Body.Close()
+
+		select {
+		case <-time.After(wait):
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		}
+	}
 }
 
 func buildClient() {
@@ -65,17 +144,14 @@ func buildClient() {
 	// V2 client independently from the V1 client because of HTTP middleware
 	// (http.Transport) clashes.
 	// This can be removed once the only API used is V2.
+	csHTTPClient = &http.Client{
+		Transport: newCLIRoundTripper(http.DefaultTransport, gCurrentAccount.CustomHeaders),
+	}
 	clientExoV2, err := exov2.NewClient(
 		gCurrentAccount.Key,
 		gCurrentAccount.APISecret(),
 		exov2.ClientOptWithAPIEndpoint(gCurrentAccount.Endpoint),
-		exov2.ClientOptWithHTTPClient(func() *http.Client {
-			hc := &http.Client{Transport: http.DefaultTransport}
-			if gCurrentAccount.CustomHeaders != nil {
-				hc.Transport = newCLIRoundTripper(hc.Transport, gCurrentAccount.CustomHeaders)
-			}
-			return hc
-		}()),
+		exov2.ClientOptWithHTTPClient(csHTTPClient),
 		exov2.ClientOptCond(func() bool {
 			if v := os.Getenv("EXOSCALE_TRACE"); v != "" {
 				return true
@@ -88,11 +164,43 @@ func buildClient() {
 	}
 	cs.Client = clientExoV2
 
+	// The API V2 client HTTP client transport is set up by egoscale, the
+	// errors it returns are annotated with the failed API requests ID.
+	csHTTPClient.Transport = &apiRequestIDTransport{next: csHTTPClient.Transport}
+
 	csDNS = egoscale.NewClient(gCurrentAccount.DNSEndpoint,
 		gCurrentAccount.Key,
-		gCurrentAccount.APISecret())
+		gCurrentAccount.APISecret(),
+		egoscale.WithHTTPClient(&http.Client{
+			Transport: newCLIRoundTripper(http.DefaultTransport, gCurrentAccount.CustomHeaders),
+		}))
 
 	csRunstatus = egoscale.NewClient(gCurrentAccount.RunstatusEndpoint,
 		gCurrentAccount.Key,
-		gCurrentAccount.APISecret())
+		gCurrentAccount.APISecret(),
+		egoscale.WithHTTPClient(&http.Client{
+			Transport: newCLIRoundTripper(http.DefaultTransport, gCurrentAccount.CustomHeaders),
+		}))
+}
+
+// waitForOperation waits for the API V2 async operation id to complete. It is
+// intended for API calls performed using the low-level API client methods, the
+// egoscale high-level methods waiting for their operations to complete.
+func waitForOperation(ctx context.Context, zone, id string) error {
+	poll := cs.OperationPoller(zone, id)
+	for {
+		done, _, err := poll(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-time.After(operationPollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 }
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const cloudInitConfigHeader = "#cloud-config"
+
+// cloudInitFilesHelp is the help section describing the "--file" flag.
+const cloudInitFilesHelp = `Using the "--file LOCAL:PATH[:MODE]" flag (can be specified multiple times),
+the content of the LOCAL file is written to PATH on the instances at boot
+time, with the optional MODE octal permissions (e.g. 0644): the files are
+added to the "write_files" section of the cloud-init user data, merged with
+the user data specified with the "--cloud-init" or "--cloud-init-template"
+flags if any (which must then be a "#cloud-config" document).`
+
+// cloudInitFile represents a local file to be written on an instance using
+// the cloud-init "write_files" module.
+type cloudInitFile struct {
+	local       string
+	path        string
+	permissions string
+}
+
+// parseCloudInitFile parses a "--file" flag value in the LOCAL:PATH[:MODE]
+// format.
+func parseCloudInitFile(v string) (*cloudInitFile, error) {
+	parts := strings.Split(v, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid value %q, expected format LOCAL:PATH[:MODE]", v)
+	}
+
+	if !strings.HasPrefix(parts[1], "/") {
+		return nil, fmt.Errorf("invalid value %q: target path must be absolute", v)
+	}
+
+	file := cloudInitFile{local: parts[0], path: parts[1]}
+	if len(parts) == 3 {
+		if _, err := strconv.ParseUint(parts[2], 8, 12); err != nil {
+			return nil, fmt.Errorf("invalid value %q: invalid octal mode %q", v, parts[2])
+		}
+		file.permissions = parts[2]
+	}
+
+	return &file, nil
+}
+
+// addCloudInitFiles returns the cloud-init user data userData (possibly
+// empty) with the files specified as "--file" flag values added to its
+// "write_files" section. Files whose target path is already written by
+// userData are rejected.
+func addCloudInitFiles(userData []byte, files []string) ([]byte, error) {
+	var doc yaml.Node
+	if len(bytes.TrimSpace(userData)) > 0 {
+		if !bytes.HasPrefix(userData, []byte(cloudInitConfigHeader)) {
+			return nil, fmt.Errorf("user data must be a %q document to add files to", cloudInitConfigHeader)
+		}
+		if err := yaml.Unmarshal(userData, &doc); err != nil {
+			return nil, fmt.Errorf("unable to parse user data: %w", err)
+		}
+	}
+
+	var root *yaml.Node
+	switch {
+	case len(doc.Content) == 0:
+		root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+	case doc.Content[0].Kind == yaml.MappingNode:
+		root = doc.Content[0]
+	default:
+		return nil, errors.New("user data must be a YAML mapping")
+	}
+
+	var writeFiles *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "write_files" {
+			writeFiles = root.Content[i+1]
+			break
+		}
+	}
+	if writeFiles == nil {
+		writeFiles = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "write_files"}, writeFiles)
+	}
+	if writeFiles.Kind != yaml.SequenceNode {
+		return nil, errors.New(`invalid user data: "write_files" must be a list`)
+	}
+
+	paths := make(map[string]struct{})
+	for _, entry := range writeFiles.Content {
+		var f struct {
+			Path string `yaml:"path"`
+		}
+		if err := entry.Decode(&f); err == nil && f.Path != "" {
+			paths[f.Path] = struct{}{}
+		}
+	}
+
+	for _, v := range files {
+		file, err := parseCloudInitFile(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := paths[file.path]; ok {
+			return nil, fmt.Errorf("conflicting target path %q", file.path)
+		}
+		paths[file.path] = struct{}{}
+
+		content, err := ioutil.ReadFile(file.local)
+		if err != nil {
+			return nil, err
+		}
+
+		var node yaml.Node
+		if err := node.Encode(struct {
+			Path        string `yaml:"path"`
+			Permissions string `yaml:"permissions,omitempty"`
+			Encoding    string `yaml:"encoding"`
+			Content     string `yaml:"content"`
+		}{
+			Path:        file.path,
+			Permissions: file.permissions,
+			Encoding:    "b64",
+			Content:     base64.StdEncoding.EncodeToString(content),
+		}); err != nil {
+			return nil, err
+		}
+		writeFiles.Content = append(writeFiles.Content, &node)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	out := buf.Bytes()
+
+	// The header of the user data is preserved as a comment when parsed.
+	if !bytes.HasPrefix(out, []byte(cloudInitConfigHeader)) {
+		out = append([]byte(cloudInitConfigHeader+"\n"), out...)
+	}
+
+	return out, nil
+}
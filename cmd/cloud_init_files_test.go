@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseCloudInitFile(t *testing.T) {
+	file, err := parseCloudInitFile("local.conf:/etc/app/local.conf:0644")
+	require.NoError(t, err)
+	require.Equal(t, &cloudInitFile{local: "local.conf", path: "/etc/app/local.conf", permissions: "0644"}, file)
+
+	file, err = parseCloudInitFile("local.conf:/etc/app/local.conf")
+	require.NoError(t, err)
+	require.Equal(t, "", file.permissions)
+
+	for _, v := range []string{"local.conf", "local.conf:etc/app.conf", "local.conf:/etc/app.conf:0999", ":/etc/app.conf"} {
+		_, err := parseCloudInitFile(v)
+		require.Error(t, err, v)
+	}
+}
+
+func Test_addCloudInitFiles(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(local, []byte("secret"), 0o600))
+
+	out, err := addCloudInitFiles([]byte(`#cloud-config
+hostname: web
+write_files:
+  - path: /etc/app.conf
+    content: foo
+`), []string{local + ":/etc/app/token:0600"})
+	require.NoError(t, err)
+	require.Equal(t, `#cloud-config
+hostname: web
+write_files:
+  - path: /etc/app.conf
+    content: foo
+  - path: /etc/app/token
+    permissions: "0600"
+    encoding: b64
+    content: c2VjcmV0
+`, string(out))
+
+	out, err = addCloudInitFiles(nil, []string{local + ":/etc/app/token"})
+	require.NoError(t, err)
+	require.Equal(t, `#cloud-config
+write_files:
+  - path: /etc/app/token
+    encoding: b64
+    content: c2VjcmV0
+`, string(out))
+}
+
+func Test_addCloudInitFiles_errors(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(local, []byte("secret"), 0o600))
+
+	_, err := addCloudInitFiles([]byte("#cloud-config\nwrite_files:\n  - path: /etc/app/token\n"),
+		[]string{local + ":/etc/app/token"})
+	require.EqualError(t, err, `conflicting target path "/etc/app/token"`)
+
+	_, err = addCloudInitFiles(nil, []string{local + ":/etc/a", local + ":/etc/a"})
+	require.EqualError(t, err, `conflicting target path "/etc/a"`)
+
+	_, err = addCloudInitFiles([]byte("#!/bin/sh\necho hello\n"), []string{local + ":/etc/a"})
+	require.Error(t, err)
+}
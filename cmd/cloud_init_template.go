@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const cloudInitTemplateName = "cloud-init"
+
+var cloudInitTemplateMissingKeyRe = regexp.MustCompile(
+	`^template: ` + cloudInitTemplateName + `:(\d+):\d+: executing .*map has no entry for key "(.*)"$`)
+
+// cloudInitTemplateHelp returns the help section describing the cloud-init
+// user data templating flags, listing the specified built-in variables.
+func cloudInitTemplateHelp(builtins ...string) string {
+	return fmt.Sprintf(`Using the "--cloud-init-template" flag, the cloud-init user data file is
+rendered as a Go text/template (https://pkg.go.dev/text/template) before
+being sent: variables are referenced as {{ .NAME }}, and their value is set
+using "--cloud-init-var NAME=VALUE" (can be specified multiple times). The
+following built-in variables are also available: %s. Referencing an
+undefined variable is an error. The "--dry-run" flag prints the rendered user
+data instead of performing the operation.`,
+		strings.Join(builtins, ", "))
+}
+
+// renderCloudInitTemplate renders the cloud-init user data template file path
+// using the specified variables and built-in variables. User variables can't
+// override built-in ones.
+func renderCloudInitTemplate(path string, vars, builtins map[string]string) ([]byte, error) {
+	data := make(map[string]string, len(vars)+len(builtins))
+	for k, v := range builtins {
+		data[k] = v
+	}
+	for k, v := range vars {
+		if _, ok := builtins[k]; ok {
+			names := make([]string, 0, len(builtins))
+			for name := range builtins {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("variable %q is reserved (built-in variables: %s)", k, strings.Join(names, ", "))
+		}
+		data[k] = v
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tpl, err := template.New(cloudInitTemplateName).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %s", err)
+	}
+
+	var out bytes.Buffer
+	if err := tpl.Execute(&out, data); err != nil {
+		if m := cloudInitTemplateMissingKeyRe.FindStringSubmatch(err.Error()); m != nil {
+			return nil, fmt.Errorf("undefined variable %q at line %s", m[2], m[1])
+		}
+		return nil, fmt.Errorf("error rendering template: %s", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+// validateCloudInitFlags checks the consistency of the cloud-init user data
+// related flags values.
+func validateCloudInitFlags(file, template string, vars map[string]string, dryRun bool) error {
+	switch {
+	case file != "" && template != "":
+		return fmt.Errorf("--cloud-init and --cloud-init-template flags are mutually exclusive")
+	case template == "" && len(vars) > 0:
+		return fmt.Errorf("--cloud-init-var flag requires --cloud-init-template")
+	case template == "" && dryRun:
+		return fmt.Errorf("--dry-run flag requires --cloud-init-template")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestCloudInitTemplate(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "cloud-init.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func Test_renderCloudInitTemplate(t *testing.T) {
+	path := writeTestCloudInitTemplate(t, `#cloud-config
+hostname: {{ .name }}
+write_files:
+  - path: /etc/app.conf
+    content: "zone={{ .zone }} env={{ .env }}"
+`)
+
+	out, err := renderCloudInitTemplate(path,
+		map[string]string{"env": "prod"},
+		map[string]string{"zone": "ch-gva-2", "name": "web"})
+	require.NoError(t, err)
+	require.Equal(t, `#cloud-config
+hostname: web
+write_files:
+  - path: /etc/app.conf
+    content: "zone=ch-gva-2 env=prod"
+`, string(out))
+}
+
+func Test_renderCloudInitTemplate_undefinedVariable(t *testing.T) {
+	path := writeTestCloudInitTemplate(t, "#cloud-config\nhostname: {{ .name }}\nruncmd:\n  - echo {{ .env }}\n")
+
+	_, err := renderCloudInitTemplate(path, nil, map[string]string{"name": "web"})
+	require.EqualError(t, err, `undefined variable "env" at line 4`)
+}
+
+func Test_renderCloudInitTemplate_reservedVariable(t *testing.T) {
+	path := writeTestCloudInitTemplate(t, "#cloud-config\n")
+
+	_, err := renderCloudInitTemplate(path,
+		map[string]string{"zone": "de-fra-1"},
+		map[string]string{"zone": "ch-gva-2", "name": "web"})
+	require.EqualError(t, err, `variable "zone" is reserved (built-in variables: name, zone)`)
+}
+
+func Test_validateCloudInitFlags(t *testing.T) {
+	require.NoError(t, validateCloudInitFlags("", "", nil, false))
+	require.NoError(t, validateCloudInitFlags("user-data.yaml", "", nil, false))
+	require.NoError(t, validateCloudInitFlags("", "user-data.tpl", map[string]string{"k": "v"}, true))
+	require.Error(t, validateCloudInitFlags("user-data.yaml", "user-data.tpl", nil, false))
+	require.Error(t, validateCloudInitFlags("", "", map[string]string{"k": "v"}, false))
+	require.Error(t, validateCloudInitFlags("user-data.yaml", "", nil, true))
+}
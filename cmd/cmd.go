@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -71,15 +72,25 @@ func cmdSetZoneFlagFromDefault(cmd *cobra.Command) {
 	}
 }
 
+// cmdExitOnUsageError reports the command usage error (see
+// writeCommandError()) followed by the command usage, and exits the CLI. The
+// usage is omitted if the error is reported as JSON, in order to keep the
+// standard error parsable.
 func cmdExitOnUsageError(cmd *cobra.Command, reason string) {
-	cmd.PrintErrln(fmt.Sprintf("error: %s", reason))
-	cmd.Usage() // nolint:errcheck
+	writeCommandError(cmd.ErrOrStderr(), cmd, errors.New(reason))
+	if !commandErrorJSON() {
+		cmd.Usage() // nolint:errcheck
+	}
 	os.Exit(1)
 }
 
 // completeVMNames is a Cobra Command.ValidArgsFunction that returns the list of Compute instance names belonging to
 // the current user for shell auto-completion.
 func completeVMNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if cs == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
 	list, err := listVMs()
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveError
@@ -234,6 +245,9 @@ func cliCommandFlagSet(c cliCommand) (*pflag.FlagSet, error) {
 		case reflect.Int64:
 			fs.Int64P(flagName, flagShort, flagDefaultValue.(int64), flagUsage)
 
+		case reflect.Float64:
+			fs.Float64P(flagName, flagShort, flagDefaultValue.(float64), flagUsage)
+
 		case reflect.Bool:
 			fs.BoolP(flagName, flagShort, flagDefaultValue.(bool), flagUsage)
 
@@ -358,6 +372,8 @@ func cliCommandUse(c cliCommand) (string, error) {
 // struct flags/args fields from a cobra.Command and args provided, and set
 // corresponding fields on the struct implementing the cliCommand interface.
 func cliCommandDefaultPreRun(c cliCommand, cmd *cobra.Command, args []string) error {
+	cmdSetForceFromFlag(cmd)
+
 	cv := reflect.ValueOf(c)
 
 	if cv.Kind() == reflect.Ptr {
@@ -407,7 +423,18 @@ func cliCommandDefaultPreRun(c cliCommand, cmd *cobra.Command, args []string) er
 				if argMode == "#" {
 					// Required arg
 					if argp >= len(args) {
-						return fmt.Errorf("missing arguments, run with --help for usage")
+						resource, ok := cTypeField.Tag.Lookup("cli-pick")
+						if !ok || !isInteractive() {
+							return fmt.Errorf("missing arguments, run with --help for usage")
+						}
+
+						v, err := pickResource(cmd, resource)
+						if err != nil {
+							return err
+						}
+						cField.SetString(v)
+						argp++
+						continue
 					}
 					cField.SetString(args[argp])
 				} else if argMode == "?" {
@@ -471,6 +498,13 @@ func cliCommandDefaultPreRun(c cliCommand, cmd *cobra.Command, args []string) er
 			}
 			cField.SetInt(v)
 
+		case reflect.Float64:
+			v, err := cmd.Flags().GetFloat64(flagName)
+			if err != nil {
+				return fmt.Errorf("error retrieving value for flag --%s: %s", flagName, err)
+			}
+			cField.SetFloat(v)
+
 		case reflect.Bool:
 			v, err := cmd.Flags().GetBool(flagName)
 			if err != nil {
@@ -520,6 +554,111 @@ func cliCommandDefaultPreRun(c cliCommand, cmd *cobra.Command, args []string) er
 	return nil
 }
 
+// cliCommandCompletion sets the cobra.Command shell completion of the
+// positional arguments from the specified cliCommand struct tags, and checks
+// the resources to pick interactively. Supported tags are:
+//   * cli-complete:"<resource type>": complete the positional argument with
+//     the names of the resources of the specified type (see
+//     completionResources), e.g. cli-complete:"instance".
+//   * cli-pick:"<resource type>": if the required positional argument is
+//     omitted when running interactively, prompt the user to select one of
+//     the existing resources of the specified type instead of failing.
+func cliCommandCompletion(c cliCommand, cmd *cobra.Command) error {
+	cv := reflect.ValueOf(c)
+
+	if cv.Kind() == reflect.Ptr {
+		cv = cv.Elem()
+	}
+
+	argIndex := 0
+	for i := 0; i < cv.NumField(); i++ {
+		cTypeField := cv.Type().Field(i)
+
+		if _, ok := cTypeField.Tag.Lookup("cli-arg"); !ok {
+			continue
+		}
+
+		for _, tag := range []string{"cli-complete", "cli-pick"} {
+			if resource, ok := cTypeField.Tag.Lookup(tag); ok {
+				if _, ok := completionResources[resource]; !ok {
+					return cliCommandImplemError{fmt.Sprintf(
+						"unsupported `%s` tag value %q on field %s.%s",
+						tag,
+						resource,
+						cv.Type(),
+						cTypeField.Name,
+					)}
+				}
+			}
+		}
+
+		if resource, ok := cTypeField.Tag.Lookup("cli-complete"); ok {
+			cmd.ValidArgsFunction = completeResourceNames(resource, argIndex)
+		}
+
+		argIndex++
+	}
+
+	return nil
+}
+
+// cliCommandDeprecations applies the deprecations declared in the specified
+// cliCommand struct tags to the cobra.Command. Supported tags are:
+//   * cli-deprecated:"<removal version>:<replacement>": mark the command (if
+//     set on the `cli-cmd` field) or the flag as deprecated in favor of the
+//     replacement, e.g. cli-deprecated:"1.42.0:exo compute instance scale".
+//   * cli-renamed-from:"<removal version>:<old flag name>": declare a
+//     deprecated alias for the flag, e.g. following a flag rename.
+func cliCommandDeprecations(c cliCommand, cmd *cobra.Command) error {
+	cv := reflect.ValueOf(c)
+
+	if cv.Kind() == reflect.Ptr {
+		cv = cv.Elem()
+	}
+
+	parseTag := func(field reflect.StructField, tag string) (string, string, bool, error) {
+		v, ok := field.Tag.Lookup(tag)
+		if !ok {
+			return "", "", false, nil
+		}
+
+		parts := strings.SplitN(v, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", false, cliCommandImplemError{
+				fmt.Sprintf("invalid `%s` tag value %q on field %s.%s", tag, v, cv.Type(), field.Name),
+			}
+		}
+
+		return parts[0], parts[1], true, nil
+	}
+
+	for i := 0; i < cv.NumField(); i++ {
+		cTypeField := cv.Type().Field(i)
+
+		removal, replacement, ok, err := parseTag(cTypeField, "cli-deprecated")
+		if err != nil {
+			return err
+		}
+		if ok {
+			if _, isCmd := cTypeField.Tag.Lookup("cli-cmd"); isCmd {
+				cmdDeprecateCommand(cmd, replacement, removal)
+			} else {
+				cmdDeprecateFlag(cmd, mustCLICommandFlagName(c, cv.Field(i).Addr().Interface()), replacement, removal)
+			}
+		}
+
+		removal, oldName, ok, err := parseTag(cTypeField, "cli-renamed-from")
+		if err != nil {
+			return err
+		}
+		if ok {
+			cmdRenameFlag(cmd, mustCLICommandFlagName(c, cv.Field(i).Addr().Interface()), oldName, removal)
+		}
+	}
+
+	return nil
+}
+
 // registerCLICommand registers the specified cliCommand instance to the
 // current CLI framework (currently Cobra).
 func registerCLICommand(parent *cobra.Command, c cliCommand) error {
@@ -537,6 +676,10 @@ func registerCLICommand(parent *cobra.Command, c cliCommand) error {
 		RunE:    c.cmdRun,
 	}
 
+	if _, ok := c.(cliCommandDryRunner); ok {
+		cmd.Annotations = map[string]string{cmdAnnotationDryRun: ""}
+	}
+
 	cmdFlags, err := cliCommandFlagSet(c)
 	if err != nil {
 		return fmt.Errorf("error initializing CLI command: %s", err)
@@ -549,5 +692,13 @@ func registerCLICommand(parent *cobra.Command, c cliCommand) error {
 
 	parent.AddCommand(cmd)
 
+	if err := cliCommandDeprecations(c, cmd); err != nil {
+		return fmt.Errorf("error initializing CLI command: %s", err)
+	}
+
+	if err := cliCommandCompletion(c, cmd); err != nil {
+		return fmt.Errorf("error initializing CLI command: %s", err)
+	}
+
 	return nil
 }
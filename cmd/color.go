@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/exoscale/cli/table"
+)
+
+// gNoColor disables the decorations of the CLI output (colors, spinners,
+// progress bars and rich table borders). It is set by the global
+// "--no-color" flag, and computed once by initColor() from the NO_COLOR
+// environment variable (https://no-color.org/) and the standard output type.
+var gNoColor bool
+
+// initColor decides whether the CLI output is to be decorated: decorations
+// are disabled if the "--no-color" flag is set, if the NO_COLOR environment
+// variable is set to a non-empty value or if the standard output is not a
+// terminal (e.g. when captured in CI logs).
+func initColor() {
+	if os.Getenv("NO_COLOR") != "" || !term.IsTerminal(int(os.Stdout.Fd())) {
+		gNoColor = true
+	}
+
+	table.Plain = gNoColor
+}
+
+// decorationsDisabled returns true if the progress feedback (spinners and
+// progress bars) must not be rendered, i.e. in quiet or no-color mode.
+func decorationsDisabled() bool {
+	return gQuiet || gNoColor
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/exoscale/cli/table"
+)
+
+func Test_initColor(t *testing.T) {
+	savedNoColor, savedPlain := gNoColor, table.Plain
+	defer func() { gNoColor, table.Plain = savedNoColor, savedPlain }()
+
+	// The standard output of the tests is not a terminal.
+	gNoColor = false
+	initColor()
+	require.True(t, gNoColor)
+	require.True(t, table.Plain)
+
+}
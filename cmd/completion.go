@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// completionTimeout is the maximum duration of the API requests
+	// performed to complete resource names.
+	completionTimeout = 3 * time.Second
+
+	// completionCacheTTL is the duration during which the resource names
+	// retrieved for completion are reused.
+	completionCacheTTL = 10 * time.Second
+)
+
+// completionResources are the functions listing the names of the resources
+// supported by the `cli-complete` struct tag, by resource type.
+var completionResources = map[string]func(ctx context.Context, zone string) ([]string, error){
+	"instance": func(ctx context.Context, zone string) ([]string, error) {
+		list, err := cs.ListInstances(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list))
+		for _, v := range list {
+			names = append(names, defaultString(v.Name, ""))
+		}
+		return names, nil
+	},
+
+	"instance-pool": func(ctx context.Context, zone string) ([]string, error) {
+		list, err := cs.ListInstancePools(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list))
+		for _, v := range list {
+			names = append(names, defaultString(v.Name, ""))
+		}
+		return names, nil
+	},
+
+	"nlb": func(ctx context.Context, zone string) ([]string, error) {
+		list, err := cs.ListNetworkLoadBalancers(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list))
+		for _, v := range list {
+			names = append(names, defaultString(v.Name, ""))
+		}
+		return names, nil
+	},
+
+	"security-group": func(ctx context.Context, zone string) ([]string, error) {
+		list, err := cs.ListSecurityGroups(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list))
+		for _, v := range list {
+			names = append(names, defaultString(v.Name, ""))
+		}
+		return names, nil
+	},
+
+	"sks-cluster": func(ctx context.Context, zone string) ([]string, error) {
+		list, err := cs.ListSKSClusters(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list))
+		for _, v := range list {
+			names = append(names, defaultString(v.Name, ""))
+		}
+		return names, nil
+	},
+}
+
+// completeResourceNames returns a Cobra Command.ValidArgsFunction completing
+// the positional argument at position argIndex (or any position if negative)
+// with the names of the resources of the specified type, in the zone
+// specified with the "--zone" flag (or the account default zone). Completion
+// silently falls back to no suggestions in case of error.
+func completeResourceNames(resource string, argIndex int) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if (argIndex >= 0 && len(args) != argIndex) || gCurrentAccount == nil || cs == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names, err := listResourceNamesCached(resource, cmdResourceZone(cmd))
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions := make([]string, 0, len(names))
+		for _, name := range names {
+			if strings.HasPrefix(name, toComplete) {
+				completions = append(completions, name)
+			}
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// cmdResourceZone returns the zone of the resources handled by the command:
+// the value of the "--zone" flag if set, otherwise the account default zone.
+func cmdResourceZone(cmd *cobra.Command) string {
+	if flag := cmd.Flags().Lookup("zone"); flag != nil && flag.Value.String() != "" {
+		return flag.Value.String()
+	}
+
+	return gCurrentAccount.DefaultZone
+}
+
+// listResourceNamesCached returns the names of the resources of the
+// specified type in the specified zone, reusing the names retrieved during
+// the last completionCacheTTL if any.
+func listResourceNamesCached(resource, zone string) ([]string, error) {
+	list, ok := completionResources[resource]
+	if !ok {
+		return nil, fmt.Errorf("unsupported resource type %q", resource)
+	}
+
+	cacheFile := completionCacheFile(gAccountName, resource, zone)
+	if names, ok := completionFromCache(cacheFile, time.Now()); ok {
+		return names, nil
+	}
+
+	ctx, cancel := context.WithTimeout(
+		exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone)),
+		completionTimeout,
+	)
+	defer cancel()
+
+	names, err := list(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	// Failing to cache the names is not fatal, they'll just be retrieved
+	// again next time.
+	if data, err := json.Marshal(names); err == nil {
+		_ = completionToCache(cacheFile, data)
+	}
+
+	return names, nil
+}
+
+// completionCacheFile returns the path of the file caching the names of the
+// resources matching the specified parameters.
+func completionCacheFile(account, resource, zone string) string {
+	key := sha256.Sum256([]byte(strings.Join([]string{account, resource, zone}, "\x00")))
+
+	return path.Join(gConfigFolder, "completion", fmt.Sprintf("%x.json", key))
+}
+
+// completionFromCache returns the resource names stored in the specified
+// cache file if it is more recent than completionCacheTTL at time now.
+func completionFromCache(file string, now time.Time) ([]string, bool) {
+	info, err := os.Stat(file)
+	if err != nil || now.Sub(info.ModTime()) > completionCacheTTL {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, false
+	}
+
+	return names, true
+}
+
+// completionToCache stores resource names in the specified cache file,
+// readable by the current user only.
+func completionToCache(file string, names []byte) error {
+	if err := os.MkdirAll(path.Dir(file), 0o700); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(file, names, 0o600)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_completionCache(t *testing.T) {
+	file := completionCacheFile("test", "instance", "ch-gva-2")
+	require.NotEqual(t, file, completionCacheFile("test", "instance", "de-fra-1"))
+
+	savedConfigFolder := gConfigFolder
+	defer func() { gConfigFolder = savedConfigFolder }()
+	gConfigFolder = t.TempDir()
+	file = completionCacheFile("test", "instance", "ch-gva-2")
+
+	_, ok := completionFromCache(file, time.Now())
+	require.False(t, ok)
+
+	require.NoError(t, completionToCache(file, []byte(`["a","b"]`)))
+
+	names, ok := completionFromCache(file, time.Now())
+	require.True(t, ok)
+	require.Equal(t, []string{"a", "b"}, names)
+
+	_, ok = completionFromCache(file, time.Now().Add(completionCacheTTL+time.Second))
+	require.False(t, ok)
+}
+
+type testCompletionCLICmd struct {
+	_ bool `cli-cmd:"completion"`
+
+	Cluster  string `cli-arg:"#"`
+	Instance string `cli-arg:"#" cli-complete:"instance"`
+
+	Zone string `cli-short:"z"`
+}
+
+func (c *testCompletionCLICmd) cmdAliases() []string                         { return nil }
+func (c *testCompletionCLICmd) cmdShort() string                             { return "" }
+func (c *testCompletionCLICmd) cmdLong() string                              { return "" }
+func (c *testCompletionCLICmd) cmdPreRun(_ *cobra.Command, _ []string) error { return nil }
+func (c *testCompletionCLICmd) cmdRun(_ *cobra.Command, _ []string) error    { return nil }
+
+func Test_cliCommandCompletion(t *testing.T) {
+	savedConfigFolder, savedAccount, savedAccountName, savedCS := gConfigFolder, gCurrentAccount, gAccountName, cs
+	defer func() {
+		gConfigFolder, gCurrentAccount, gAccountName, cs = savedConfigFolder, savedAccount, savedAccountName, savedCS
+	}()
+	gConfigFolder = t.TempDir()
+	gCurrentAccount = &account{DefaultZone: "ch-gva-2"}
+	gAccountName = "test"
+	cs = &egoscale.Client{}
+
+	for zone, names := range map[string][]string{
+		"ch-gva-2": {"web1", "web2", "db1"},
+		"de-fra-1": {"web3"},
+	} {
+		data, err := json.Marshal(names)
+		require.NoError(t, err)
+		require.NoError(t, completionToCache(completionCacheFile("test", "instance", zone), data))
+	}
+
+	root := &cobra.Command{Use: "root"}
+	require.NoError(t, registerCLICommand(root, &testCompletionCLICmd{}))
+	cmd, _, err := root.Find([]string{"completion"})
+	require.NoError(t, err)
+	require.NotNil(t, cmd.ValidArgsFunction)
+
+	completions, directive := cmd.ValidArgsFunction(cmd, []string{}, "")
+	require.Empty(t, completions)
+	require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	completions, _ = cmd.ValidArgsFunction(cmd, []string{"cluster"}, "web")
+	require.Equal(t, []string{"web1", "web2"}, completions)
+
+	require.NoError(t, cmd.Flags().Set("zone", "de-fra-1"))
+	completions, _ = cmd.ValidArgsFunction(cmd, []string{"cluster"}, "")
+	require.Equal(t, []string{"web3"}, completions)
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	computeLookupAddressElastic = "elastic"
+	computeLookupAddressIPv6    = "ipv6"
+	computeLookupAddressPrivate = "private"
+	computeLookupAddressPublic  = "public"
+)
+
+type computeLookupItemOutput struct {
+	IPAddress   string `json:"ip_address"`
+	AddressType string `json:"address_type"`
+	Type        string `json:"type"`
+	Zone        string `json:"zone"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+}
+
+type computeLookupOutput []computeLookupItemOutput
+
+func (o *computeLookupOutput) toJSON()  { outputJSON(o) }
+func (o *computeLookupOutput) toText()  { outputText(o) }
+func (o *computeLookupOutput) toTable() { outputTable(o) }
+
+type computeLookupCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"lookup"`
+
+	IPAddress string `cli-arg:"#" cli-usage:"IP-ADDRESS"`
+
+	Prefix bool   `cli-usage:"match the addresses starting with IP-ADDRESS (e.g. \"89.145.160.\"), or belonging to the IP-ADDRESS CIDR network (e.g. \"89.145.160.0/24\")"`
+	Zone   string `cli-short:"z" cli-usage:"zone to restrict the lookup to"`
+}
+
+func (c *computeLookupCmd) cmdAliases() []string { return nil }
+
+func (c *computeLookupCmd) cmdShort() string {
+	return "Find the Compute resources using an IP address"
+}
+
+func (c *computeLookupCmd) cmdLong() string {
+	return fmt.Sprintf(`This command looks up the Compute resources using an IP address in all
+zones (or the zone specified using the "--zone" flag):
+
+  * Compute instances public IPv4/IPv6 addresses
+  * Compute instances addresses in managed Private Networks
+  * Elastic IPs, along with the Compute instances they are attached to
+  * Network Load Balancers
+
+Using the "--prefix" flag, all the addresses starting with the specified
+value or belonging to the specified CIDR network are matched, e.g. to sweep a
+subnet.
+
+The command exits with status %d if no matching resource is found.
+
+Supported output template annotations: %s`,
+		exitCodeNotFound,
+		strings.Join(outputterTemplateAnnotations(&computeLookupItemOutput{}), ", "))
+}
+
+func (c *computeLookupCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *computeLookupCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	match, err := computeLookupMatcher(c.IPAddress, c.Prefix)
+	if err != nil {
+		return err
+	}
+
+	zones := allZones
+	if c.Zone != "" {
+		zones = []string{c.Zone}
+	}
+
+	var (
+		out = make(computeLookupOutput, 0)
+		mu  sync.Mutex
+	)
+
+	err = forEachZone(zones, func(zone string) error {
+		found, err := lookupZoneIPAddress(zone, match)
+		if err != nil {
+			return fmt.Errorf("unable to look up zone %s: %v", zone, err)
+		}
+
+		mu.Lock()
+		out = append(out, found...)
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		// Not finding anything in an incomplete lookup doesn't mean the
+		// address is not used.
+		if len(out) == 0 {
+			return err
+		}
+		_, _ = fmt.Fprintf(os.Stderr,
+			"warning: errors during lookup, results might be incomplete.\n%s\n", err) // nolint:golint
+	}
+
+	if len(out) == 0 {
+		if c.Prefix {
+			return notFoundError(fmt.Sprintf("no Compute resource found using an IP address matching %q", c.IPAddress))
+		}
+		return notFoundError(fmt.Sprintf("no Compute resource found using IP address %s", c.IPAddress))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Zone != out[j].Zone {
+			return out[i].Zone < out[j].Zone
+		}
+		if out[i].IPAddress != out[j].IPAddress {
+			return out[i].IPAddress < out[j].IPAddress
+		}
+		return out[i].Type < out[j].Type
+	})
+
+	return c.outputFunc(&out, nil)
+}
+
+// computeLookupMatcher returns a function reporting whether an IP address
+// matches the address looked up. In prefix mode, the address can be either a
+// CIDR network or the beginning of the textual representation of addresses.
+func computeLookupMatcher(address string, prefix bool) (func(net.IP) bool, error) {
+	if prefix {
+		if _, network, err := net.ParseCIDR(address); err == nil {
+			return network.Contains, nil
+		}
+
+		if address == "" {
+			return nil, fmt.Errorf("invalid IP address prefix %q", address)
+		}
+
+		return func(ip net.IP) bool { return strings.HasPrefix(ip.String(), address) }, nil
+	}
+
+	target := net.ParseIP(address)
+	if target == nil {
+		return nil, fmt.Errorf("invalid IP address %q", address)
+	}
+
+	return target.Equal, nil
+}
+
+// lookupZoneIPAddress returns the Compute resources of a zone using an IP
+// address matching the function match.
+func lookupZoneIPAddress(zone string, match func(net.IP) bool) ([]computeLookupItemOutput, error) {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+	instances, err := cs.ListInstances(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	elasticIPs, err := cs.ListElasticIPs(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	nlbs, err := cs.ListNetworkLoadBalancers(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	privateNetworks, err := cs.ListPrivateNetworks(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	// The leases of managed Private Networks are only reported when
+	// retrieving the networks individually.
+	for i, pn := range privateNetworks {
+		if pn.StartIP == nil || pn.Leases != nil {
+			continue
+		}
+
+		if privateNetworks[i], err = cs.GetPrivateNetwork(ctx, zone, *pn.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return matchZoneIPAddresses(zone, match, instances, elasticIPs, nlbs, privateNetworks), nil
+}
+
+// matchZoneIPAddresses returns the resources of a zone using an IP address
+// matching the function match.
+func matchZoneIPAddresses(
+	zone string,
+	match func(net.IP) bool,
+	instances []*exov2.Instance,
+	elasticIPs []*exov2.ElasticIP,
+	nlbs []*exov2.NetworkLoadBalancer,
+	privateNetworks []*exov2.PrivateNetwork,
+) []computeLookupItemOutput {
+	found := make([]computeLookupItemOutput, 0)
+
+	instanceItem := func(instance *exov2.Instance, ip *net.IP, addressType string) computeLookupItemOutput {
+		return computeLookupItemOutput{
+			IPAddress:   ip.String(),
+			AddressType: addressType,
+			Type:        "instance",
+			Zone:        zone,
+			ID:          *instance.ID,
+			Name:        defaultString(instance.Name, ""),
+			State:       defaultString(instance.State, ""),
+		}
+	}
+
+	instancesByID := make(map[string]*exov2.Instance, len(instances))
+	instancesByElasticIP := make(map[string][]*exov2.Instance)
+	for _, instance := range instances {
+		instancesByID[*instance.ID] = instance
+
+		if instance.PublicIPAddress != nil && match(*instance.PublicIPAddress) {
+			found = append(found, instanceItem(instance, instance.PublicIPAddress, computeLookupAddressPublic))
+		}
+
+		if instance.IPv6Address != nil && match(*instance.IPv6Address) {
+			found = append(found, instanceItem(instance, instance.IPv6Address, computeLookupAddressIPv6))
+		}
+
+		if instance.ElasticIPIDs != nil {
+			for _, id := range *instance.ElasticIPIDs {
+				instancesByElasticIP[id] = append(instancesByElasticIP[id], instance)
+			}
+		}
+	}
+
+	for _, eip := range elasticIPs {
+		if eip.IPAddress == nil || !match(*eip.IPAddress) {
+			continue
+		}
+
+		state := "detached"
+		if len(instancesByElasticIP[*eip.ID]) > 0 {
+			state = "attached"
+		}
+
+		found = append(found, computeLookupItemOutput{
+			IPAddress:   eip.IPAddress.String(),
+			AddressType: computeLookupAddressElastic,
+			Type:        computeOrphanTypeElasticIP,
+			Zone:        zone,
+			ID:          *eip.ID,
+			Name:        defaultString(eip.Description, ""),
+			State:       state,
+		})
+
+		for _, instance := range instancesByElasticIP[*eip.ID] {
+			found = append(found, instanceItem(instance, eip.IPAddress, computeLookupAddressElastic))
+		}
+	}
+
+	for _, nlb := range nlbs {
+		if nlb.IPAddress == nil || !match(*nlb.IPAddress) {
+			continue
+		}
+
+		found = append(found, computeLookupItemOutput{
+			IPAddress:   nlb.IPAddress.String(),
+			AddressType: computeLookupAddressPublic,
+			Type:        "nlb",
+			Zone:        zone,
+			ID:          *nlb.ID,
+			Name:        defaultString(nlb.Name, ""),
+			State:       defaultString(nlb.State, ""),
+		})
+	}
+
+	for _, pn := range privateNetworks {
+		for _, lease := range pn.Leases {
+			if lease.IPAddress == nil || lease.InstanceID == nil || !match(*lease.IPAddress) {
+				continue
+			}
+
+			instance, ok := instancesByID[*lease.InstanceID]
+			if !ok {
+				continue
+			}
+
+			item := instanceItem(instance, lease.IPAddress, computeLookupAddressPrivate)
+			if pn.Name != nil {
+				item.AddressType += " (" + *pn.Name + ")"
+			}
+			found = append(found, item)
+		}
+	}
+
+	return found
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(computeCmd, &computeLookupCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_computeLookupMatcher(t *testing.T) {
+	match, err := computeLookupMatcher("89.145.160.12", false)
+	require.NoError(t, err)
+	require.True(t, match(net.ParseIP("89.145.160.12")))
+	require.False(t, match(net.ParseIP("89.145.160.120")))
+
+	match, err = computeLookupMatcher("89.145.160.1", true)
+	require.NoError(t, err)
+	require.True(t, match(net.ParseIP("89.145.160.12")))
+	require.True(t, match(net.ParseIP("89.145.160.120")))
+	require.False(t, match(net.ParseIP("89.145.161.12")))
+
+	match, err = computeLookupMatcher("2a04:c43:e00::/48", true)
+	require.NoError(t, err)
+	require.True(t, match(net.ParseIP("2a04:c43:e00:1::1")))
+	require.False(t, match(net.ParseIP("2a04:c43:e01::1")))
+
+	_, err = computeLookupMatcher("89.145.160.", false)
+	require.EqualError(t, err, `invalid IP address "89.145.160."`)
+
+	_, err = computeLookupMatcher("", true)
+	require.Error(t, err)
+}
+
+func Test_matchZoneIPAddresses(t *testing.T) {
+	var (
+		str = func(s string) *string { return &s }
+		ip  = func(s string) *net.IP { v := net.ParseIP(s); return &v }
+	)
+
+	instances := []*exov2.Instance{
+		{
+			ID:              str("i-1"),
+			Name:            str("web"),
+			State:           str("running"),
+			PublicIPAddress: ip("89.145.160.12"),
+			ElasticIPIDs:    &[]string{"eip-1"},
+		},
+		{
+			ID:              str("i-2"),
+			Name:            str("db"),
+			State:           str("stopped"),
+			PublicIPAddress: ip("89.145.160.13"),
+		},
+	}
+	elasticIPs := []*exov2.ElasticIP{
+		{ID: str("eip-1"), IPAddress: ip("89.145.161.1")},
+		{ID: str("eip-2"), IPAddress: ip("89.145.161.2"), Description: str("spare")},
+	}
+	nlbs := []*exov2.NetworkLoadBalancer{
+		{ID: str("nlb-1"), Name: str("lb"), State: str("running"), IPAddress: ip("89.145.162.1")},
+	}
+	privateNetworks := []*exov2.PrivateNetwork{{
+		ID:   str("pn-1"),
+		Name: str("backend"),
+		Leases: []*exov2.PrivateNetworkLease{
+			{InstanceID: str("i-2"), IPAddress: ip("10.0.0.13")},
+		},
+	}}
+
+	lookup := func(address string, prefix bool) []computeLookupItemOutput {
+		match, err := computeLookupMatcher(address, prefix)
+		require.NoError(t, err)
+		return matchZoneIPAddresses("ch-gva-2", match, instances, elasticIPs, nlbs, privateNetworks)
+	}
+
+	require.Equal(t, []computeLookupItemOutput{{
+		IPAddress:   "89.145.160.13",
+		AddressType: computeLookupAddressPublic,
+		Type:        "instance",
+		Zone:        "ch-gva-2",
+		ID:          "i-2",
+		Name:        "db",
+		State:       "stopped",
+	}}, lookup("89.145.160.13", false))
+
+	// Elastic IPs are reported along with the instances they are
+	// attached to.
+	require.Equal(t, []computeLookupItemOutput{
+		{
+			IPAddress:   "89.145.161.1",
+			AddressType: computeLookupAddressElastic,
+			Type:        computeOrphanTypeElasticIP,
+			Zone:        "ch-gva-2",
+			ID:          "eip-1",
+			State:       "attached",
+		},
+		{
+			IPAddress:   "89.145.161.1",
+			AddressType: computeLookupAddressElastic,
+			Type:        "instance",
+			Zone:        "ch-gva-2",
+			ID:          "i-1",
+			Name:        "web",
+			State:       "running",
+		},
+	}, lookup("89.145.161.1", false))
+
+	require.Equal(t, "private (backend)", lookup("10.0.0.13", false)[0].AddressType)
+	require.Equal(t, "nlb-1", lookup("89.145.162.1", false)[0].ID)
+	require.Len(t, lookup("89.145.16", true), 6)
+	require.Empty(t, lookup("192.0.2.1", false))
+}
+
+func Test_exitCode_notFound(t *testing.T) {
+	require.Equal(t, exitCodeNotFound, exitCode(context.Background(), notFoundError("no resource found")))
+}
@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	computeOrphanTypeAntiAffinityGroup = "anti-affinity-group"
+	computeOrphanTypeElasticIP         = "eip"
+	computeOrphanTypePrivateNetwork    = "private-network"
+	computeOrphanTypeSecurityGroup     = "security-group"
+	computeOrphanTypeTemplate          = "template"
+)
+
+var computeOrphanTypes = []string{
+	computeOrphanTypeAntiAffinityGroup,
+	computeOrphanTypeElasticIP,
+	computeOrphanTypePrivateNetwork,
+	computeOrphanTypeSecurityGroup,
+	computeOrphanTypeTemplate,
+}
+
+type computeOrphansItemOutput struct {
+	Type string `json:"type"`
+	Zone string `json:"zone"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Age  string `json:"age"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty" output:"-"`
+}
+
+type computeOrphansOutput []computeOrphansItemOutput
+
+func (o *computeOrphansOutput) toJSON()  { outputJSON(o) }
+func (o *computeOrphansOutput) toText()  { outputText(o) }
+func (o *computeOrphansOutput) toTable() { outputTable(o) }
+
+type computeOrphansCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"orphans"`
+
+	Delete bool     `cli-usage:"delete the orphaned resources found (after confirmation)"`
+	Force  bool     `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Types  []string `cli-flag:"type" cli-short:"t" cli-usage:"resource type to restrict the scan to (anti-affinity-group|eip|private-network|security-group|template). Can be repeated or comma-separated"`
+	Zone   string   `cli-short:"z" cli-usage:"zone to restrict the scan to"`
+}
+
+func (c *computeOrphansCmd) cmdAliases() []string { return nil }
+
+func (c *computeOrphansCmd) cmdShort() string {
+	return "Find orphaned Compute resources"
+}
+
+func (c *computeOrphansCmd) cmdLong() string {
+	return fmt.Sprintf(`This command scans the Organization for Compute resources that are not
+used anymore, typically left behind after deleting Instance Pools or Compute
+instances:
+
+  * Elastic IPs not attached to any Compute instance or Instance Pool
+  * Security Groups not used by any Compute instance or Instance Pool, nor
+    referenced by other Security Groups rules ("default" excluded)
+  * Anti-Affinity Groups not used by any Compute instance or Instance Pool
+  * Private templates not used by any Compute instance or Instance Pool
+  * Private Networks without any attached Compute instance or Instance Pool
+
+Using the "--delete" flag, the orphaned resources found are deleted after
+confirmation. The "--type" flag restricts the scan (and deletion) to the
+specified resource types, and is required when using "--delete" (e.g.
+"--delete --type eip,template").
+
+Note: the resource age is only reported for resources types exposing their
+creation date.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&computeOrphansItemOutput{}), ", "))
+}
+
+func (c *computeOrphansCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	// Deleting all the orphaned resource types at once would also delete
+	// private templates that are merely not in use at the moment.
+	if c.Delete && len(c.Types) == 0 {
+		cmdExitOnUsageError(cmd, fmt.Sprintf(
+			"--%s flag requires the --%s flag",
+			mustCLICommandFlagName(c, &c.Delete),
+			mustCLICommandFlagName(c, &c.Types),
+		))
+	}
+
+	if gNoWait && c.Delete {
+		return errNoWaitUnsupported(fmt.Sprintf("with the --%s flag", mustCLICommandFlagName(c, &c.Delete)))
+	}
+
+	return nil
+}
+
+func (c *computeOrphansCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	types, err := parseComputeOrphanTypes(c.Types)
+	if err != nil {
+		return err
+	}
+
+	zones := allZones
+	if c.Zone != "" {
+		zones = []string{c.Zone}
+	}
+
+	var (
+		out = make(computeOrphansOutput, 0)
+		mu  sync.Mutex
+	)
+
+	err = forEachZone(zones, func(zone string) error {
+		orphans, err := findComputeOrphans(zone, types)
+		if err != nil {
+			return fmt.Errorf("unable to scan zone %s: %v", zone, err)
+		}
+
+		mu.Lock()
+		out = append(out, orphans...)
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		// Deleting resources based on an incomplete scan is not safe, as
+		// the missing information could reference them.
+		if c.Delete {
+			return err
+		}
+		_, _ = fmt.Fprintf(os.Stderr,
+			"warning: errors during scan, results might be incomplete.\n%s\n", err) // nolint:golint
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Type != out[j].Type {
+			return out[i].Type < out[j].Type
+		}
+		if out[i].Zone != out[j].Zone {
+			return out[i].Zone < out[j].Zone
+		}
+		return out[i].Name < out[j].Name
+	})
+
+	if !c.Delete {
+		return output(&out, nil)
+	}
+
+	if len(out) == 0 {
+		if !gQuiet {
+			fmt.Println("No orphaned resources found.")
+		}
+		return nil
+	}
+
+	if !c.Force {
+		out.toTable()
+		if !askQuestion(fmt.Sprintf("Are you sure you want to delete these %d resources?", len(out))) {
+			return nil
+		}
+	}
+
+	var failed int
+	for _, orphan := range out {
+		err = decorateAsyncOperation(fmt.Sprintf("Deleting %s %q...", orphan.Type, orphan.Name), func() error {
+			return deleteComputeOrphan(orphan)
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to delete %s %q: %s\n", orphan.Type, orphan.Name, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d resources could not be deleted", failed)
+	}
+
+	return nil
+}
+
+// parseComputeOrphanTypes returns the set of orphaned resource types to
+// look for, all of them if none is specified.
+func parseComputeOrphanTypes(v []string) (map[string]bool, error) {
+	types := make(map[string]bool)
+
+	for _, list := range v {
+		for _, t := range strings.Split(list, ",") {
+			t = strings.TrimSpace(t)
+			supported := false
+			for _, st := range computeOrphanTypes {
+				if t == st {
+					supported = true
+					break
+				}
+			}
+			if !supported {
+				return nil, fmt.Errorf("unsupported resource type %q (supported: %s)",
+					t, strings.Join(computeOrphanTypes, ", "))
+			}
+			types[t] = true
+		}
+	}
+
+	if len(types) == 0 {
+		for _, t := range computeOrphanTypes {
+			types[t] = true
+		}
+	}
+
+	return types, nil
+}
+
+// computeZoneResources represents the Compute resources of a zone considered
+// when looking for orphaned resources.
+type computeZoneResources struct {
+	instances          []*exov2.Instance
+	instancePools      []*exov2.InstancePool
+	antiAffinityGroups []*exov2.AntiAffinityGroup
+	elasticIPs         []*exov2.ElasticIP
+	privateNetworks    []*exov2.PrivateNetwork
+	securityGroups     []*exov2.SecurityGroup
+	templates          []*exov2.Template
+}
+
+// findComputeOrphans returns the orphaned resources of the specified types
+// in a zone.
+func findComputeOrphans(zone string, types map[string]bool) ([]computeOrphansItemOutput, error) {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+	var (
+		res computeZoneResources
+		err error
+	)
+
+	if res.instances, err = cs.ListInstances(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	if res.instancePools, err = cs.ListInstancePools(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	if types[computeOrphanTypeAntiAffinityGroup] {
+		if res.antiAffinityGroups, err = cs.ListAntiAffinityGroups(ctx, zone); err != nil {
+			return nil, err
+		}
+	}
+
+	if types[computeOrphanTypeElasticIP] {
+		if res.elasticIPs, err = cs.ListElasticIPs(ctx, zone); err != nil {
+			return nil, err
+		}
+	}
+
+	if types[computeOrphanTypePrivateNetwork] {
+		if res.privateNetworks, err = cs.ListPrivateNetworks(ctx, zone); err != nil {
+			return nil, err
+		}
+	}
+
+	if types[computeOrphanTypeSecurityGroup] {
+		if res.securityGroups, err = cs.ListSecurityGroups(ctx, zone); err != nil {
+			return nil, err
+		}
+	}
+
+	if types[computeOrphanTypeTemplate] {
+		if res.templates, err = cs.ListTemplates(ctx, zone, "private", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return matchComputeOrphans(zone, types, &res), nil
+}
+
+// matchComputeOrphans returns the resources of the specified types of a zone
+// not referenced by any Compute instance, Instance Pool or (for Security
+// Groups) other Security Group rule.
+func matchComputeOrphans(zone string, types map[string]bool, res *computeZoneResources) []computeOrphansItemOutput {
+	orphans := make([]computeOrphansItemOutput, 0)
+
+	// Collect the resources referenced by Compute instances and Instance Pools.
+	used := make(map[string]bool)
+	markUsed := func(ids *[]string) {
+		if ids != nil {
+			for _, id := range *ids {
+				used[id] = true
+			}
+		}
+	}
+
+	for _, i := range res.instances {
+		markUsed(i.AntiAffinityGroupIDs)
+		markUsed(i.ElasticIPIDs)
+		markUsed(i.PrivateNetworkIDs)
+		markUsed(i.SecurityGroupIDs)
+		if i.TemplateID != nil {
+			used[*i.TemplateID] = true
+		}
+	}
+
+	for _, p := range res.instancePools {
+		markUsed(p.AntiAffinityGroupIDs)
+		markUsed(p.ElasticIPIDs)
+		markUsed(p.PrivateNetworkIDs)
+		markUsed(p.SecurityGroupIDs)
+		if p.TemplateID != nil {
+			used[*p.TemplateID] = true
+		}
+	}
+
+	// Security Groups can be referenced by other Security Groups rules.
+	for _, r := range res.securityGroups {
+		for _, rule := range r.Rules {
+			if rule.SecurityGroupID != nil && *rule.SecurityGroupID != *r.ID {
+				used[*rule.SecurityGroupID] = true
+			}
+		}
+	}
+
+	orphan := func(orphanType, id string, name *string, createdAt *time.Time) {
+		if !types[orphanType] || used[id] {
+			return
+		}
+
+		o := computeOrphansItemOutput{
+			Type:      orphanType,
+			Zone:      zone,
+			ID:        id,
+			Name:      defaultString(name, id),
+			Age:       "n/a",
+			CreatedAt: createdAt,
+		}
+		if createdAt != nil {
+			o.Age = humanize.Time(*createdAt)
+		}
+		orphans = append(orphans, o)
+	}
+
+	for _, r := range res.antiAffinityGroups {
+		orphan(computeOrphanTypeAntiAffinityGroup, *r.ID, r.Name, nil)
+	}
+
+	for _, r := range res.elasticIPs {
+		ip := r.IPAddress.String()
+		orphan(computeOrphanTypeElasticIP, *r.ID, &ip, nil)
+	}
+
+	for _, r := range res.privateNetworks {
+		orphan(computeOrphanTypePrivateNetwork, *r.ID, r.Name, nil)
+	}
+
+	for _, r := range res.securityGroups {
+		if defaultString(r.Name, "") != "default" {
+			orphan(computeOrphanTypeSecurityGroup, *r.ID, r.Name, nil)
+		}
+	}
+
+	for _, r := range res.templates {
+		orphan(computeOrphanTypeTemplate, *r.ID, r.Name, r.CreatedAt)
+	}
+
+	return orphans
+}
+
+// deleteComputeOrphan deletes an orphaned resource.
+func deleteComputeOrphan(o computeOrphansItemOutput) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, o.Zone))
+
+	deleteFunc := map[string]func(context.Context, string, string) error{
+		computeOrphanTypeAntiAffinityGroup: cs.DeleteAntiAffinityGroup,
+		computeOrphanTypeElasticIP:         cs.DeleteElasticIP,
+		computeOrphanTypePrivateNetwork:    cs.DeletePrivateNetwork,
+		computeOrphanTypeSecurityGroup:     cs.DeleteSecurityGroup,
+		computeOrphanTypeTemplate:          cs.DeleteTemplate,
+	}[o.Type]
+	if deleteFunc == nil {
+		return errors.New("unsupported resource type")
+	}
+
+	return deleteFunc(ctx, o.Zone, o.ID)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(computeCmd, &computeOrphansCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"net"
+	"sort"
+	"testing"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseComputeOrphanTypes(t *testing.T) {
+	types, err := parseComputeOrphanTypes(nil)
+	require.NoError(t, err)
+	require.Len(t, types, len(computeOrphanTypes))
+
+	types, err = parseComputeOrphanTypes([]string{"eip, template", "security-group"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{
+		computeOrphanTypeElasticIP:     true,
+		computeOrphanTypeSecurityGroup: true,
+		computeOrphanTypeTemplate:      true,
+	}, types)
+
+	_, err = parseComputeOrphanTypes([]string{"eip,instance"})
+	require.Error(t, err)
+}
+
+func Test_matchComputeOrphans(t *testing.T) {
+	var (
+		str  = func(s string) *string { return &s }
+		strs = func(s ...string) *[]string { return &s }
+		ip   = func(s string) *net.IP { v := net.ParseIP(s); return &v }
+	)
+
+	res := computeZoneResources{
+		instances: []*exov2.Instance{{
+			ID:                   str("i1"),
+			AntiAffinityGroupIDs: strs("aag-used"),
+			ElasticIPIDs:         strs("eip-used"),
+			SecurityGroupIDs:     strs("sg-used"),
+			TemplateID:           str("tpl-used"),
+		}},
+		instancePools: []*exov2.InstancePool{{
+			ID:                str("p1"),
+			PrivateNetworkIDs: strs("pn-used"),
+			TemplateID:        str("tpl-pool"),
+		}},
+		antiAffinityGroups: []*exov2.AntiAffinityGroup{
+			{ID: str("aag-used"), Name: str("aag-used")},
+			{ID: str("aag-orphan"), Name: str("aag-orphan")},
+		},
+		elasticIPs: []*exov2.ElasticIP{
+			{ID: str("eip-used"), IPAddress: ip("194.182.160.1")},
+			{ID: str("eip-orphan"), IPAddress: ip("194.182.160.2")},
+		},
+		privateNetworks: []*exov2.PrivateNetwork{
+			{ID: str("pn-used"), Name: str("pn-used")},
+			{ID: str("pn-orphan"), Name: str("pn-orphan")},
+		},
+		securityGroups: []*exov2.SecurityGroup{
+			{ID: str("sg-default"), Name: str("default")},
+			{ID: str("sg-used"), Name: str("sg-used"), Rules: []*exov2.SecurityGroupRule{
+				{SecurityGroupID: str("sg-referenced")},
+			}},
+			{ID: str("sg-referenced"), Name: str("sg-referenced")},
+			{ID: str("sg-self"), Name: str("sg-self"), Rules: []*exov2.SecurityGroupRule{
+				{SecurityGroupID: str("sg-self")},
+			}},
+			{ID: str("sg-orphan"), Name: str("sg-orphan")},
+		},
+		templates: []*exov2.Template{
+			{ID: str("tpl-used"), Name: str("tpl-used")},
+			{ID: str("tpl-pool"), Name: str("tpl-pool")},
+			{ID: str("tpl-orphan"), Name: str("tpl-orphan")},
+		},
+	}
+
+	orphanIDs := func(orphans []computeOrphansItemOutput) []string {
+		ids := make([]string, 0)
+		for _, o := range orphans {
+			require.Equal(t, "ch-gva-2", o.Zone)
+			ids = append(ids, o.Type+"/"+o.ID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	types, err := parseComputeOrphanTypes(nil)
+	require.NoError(t, err)
+	orphans := matchComputeOrphans("ch-gva-2", types, &res)
+	require.Equal(t, []string{
+		"anti-affinity-group/aag-orphan",
+		"eip/eip-orphan",
+		"private-network/pn-orphan",
+		"security-group/sg-orphan",
+		"security-group/sg-self",
+		"template/tpl-orphan",
+	}, orphanIDs(orphans))
+
+	for _, o := range orphans {
+		if o.Type == computeOrphanTypeElasticIP {
+			require.Equal(t, "194.182.160.2", o.Name)
+		}
+	}
+
+	// Resources of the types not requested are never reported, even if
+	// they were listed.
+	orphans = matchComputeOrphans("ch-gva-2", map[string]bool{computeOrphanTypeTemplate: true}, &res)
+	require.Equal(t, []string{"template/tpl-orphan"}, orphanIDs(orphans))
+}
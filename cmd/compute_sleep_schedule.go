@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var computeSleepScheduleCmd = &cobra.Command{
+	Use:   "sleep-schedule",
+	Short: "Enforce Compute instances sleep schedule",
+	Long: `These commands allow you to enforce the sleep schedule of Compute instances
+(see "exo compute instance sleep-schedule").`,
+}
+
+func init() {
+	computeCmd.AddCommand(computeSleepScheduleCmd)
+}
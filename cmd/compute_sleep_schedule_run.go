@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	sleepScheduleResultDone     = "done"
+	sleepScheduleResultDryRun   = "dry-run"
+	sleepScheduleResultFailed   = "failed"
+	sleepScheduleResultSkipped  = "skipped"
+	sleepScheduleResultUpToDate = "up-to-date"
+)
+
+type computeSleepScheduleRunItemOutput struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Zone   string `json:"zone"`
+	State  string `json:"state"`
+	Action string `json:"action"`
+	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type computeSleepScheduleRunOutput []computeSleepScheduleRunItemOutput
+
+func (o *computeSleepScheduleRunOutput) toJSON()  { outputJSON(o) }
+func (o *computeSleepScheduleRunOutput) toText()  { outputText(o) }
+func (o *computeSleepScheduleRunOutput) toTable() { outputTable(o) }
+
+type computeSleepScheduleRunCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"run"`
+
+	DryRun bool   `cli-usage:"only report the actions due, without performing them"`
+	Zone   string `cli-short:"z" cli-usage:"zone to filter instances to"`
+}
+
+func (c *computeSleepScheduleRunCmd) cmdAliases() []string { return nil }
+
+func (c *computeSleepScheduleRunCmd) cmdShort() string {
+	return "Stop/start Compute instances according to their sleep schedule"
+}
+
+func (c *computeSleepScheduleRunCmd) cmdLong() string {
+	return fmt.Sprintf(`This command evaluates the sleep schedule of Compute instances (see
+"exo compute instance sleep-schedule set"), and stops or starts the instances
+according to the last scheduled action due. It is intended to be run
+periodically, for example from a cron or CI job:
+
+    */15 * * * * exo compute sleep-schedule run --quiet
+
+The command is idempotent: instances already in the expected state are left
+untouched, as are instances in a transitional state (e.g. "starting"). Note
+that an instance manually started outside of its schedule will be stopped
+again on the next run, until the next scheduled start.
+
+The command exits with an error status if any of the operations failed.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&computeSleepScheduleRunItemOutput{}), ", "))
+}
+
+func (c *computeSleepScheduleRunCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if gNoWait && !c.DryRun {
+		return errNoWaitUnsupported("when performing the scheduled actions")
+	}
+
+	return nil
+}
+
+func (c *computeSleepScheduleRunCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	zones := allZones
+	if c.Zone != "" {
+		zones = []string{c.Zone}
+	}
+
+	instances, err := listSleepScheduledInstances(zones)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	failed := 0
+	out := make(computeSleepScheduleRunOutput, 0, len(instances))
+	for _, i := range instances {
+		instance := i.instance
+
+		item := computeSleepScheduleRunItemOutput{
+			ID:     *instance.ID,
+			Name:   *instance.Name,
+			Zone:   i.zone,
+			State:  *instance.State,
+			Action: "none",
+		}
+
+		if i.err != nil {
+			item.Result = sleepScheduleResultFailed
+			item.Reason = fmt.Sprintf("invalid schedule: %s", i.err)
+			failed++
+			out = append(out, item)
+			continue
+		}
+
+		var reason string
+		item.Action, item.Result, reason = sleepScheduleDueAction(i.schedule, *instance.State, now)
+		item.Reason = reason
+
+		if item.Result == "" {
+			if c.DryRun {
+				item.Result = sleepScheduleResultDryRun
+			} else {
+				ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, i.zone))
+
+				if item.Action == sleepScheduleActionStop {
+					err = decorateAsyncOperation(fmt.Sprintf("Stopping instance %q...", *instance.Name), func() error {
+						return instance.Stop(ctx)
+					})
+				} else {
+					err = decorateAsyncOperation(fmt.Sprintf("Starting instance %q...", *instance.Name), func() error {
+						return instance.Start(ctx)
+					})
+				}
+
+				item.Result = sleepScheduleResultDone
+				if err != nil {
+					item.Result = sleepScheduleResultFailed
+					item.Reason = err.Error()
+					failed++
+				}
+			}
+		}
+
+		out = append(out, item)
+	}
+
+	if err := c.outputFunc(&out, nil); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d sleep schedule operation(s) failed", failed)
+	}
+
+	return nil
+}
+
+// sleepScheduleDueAction returns the action due for an instance in the
+// specified state according to its sleep schedule, along with the result
+// and its reason if no operation is to be performed (an empty result means
+// the action has to be performed).
+func sleepScheduleDueAction(schedule *sleepSchedule, state string, now time.Time) (string, string, string) {
+	last := schedule.last(now)
+	if last == nil {
+		return "none", sleepScheduleResultUpToDate, "no scheduled action during the past week"
+	}
+
+	reason := fmt.Sprintf("scheduled %s at %s", last.action, last.at.Format("Mon 2006-01-02 15:04 MST"))
+
+	expected := "running"
+	if last.action == sleepScheduleActionStop {
+		expected = "stopped"
+	}
+
+	switch state {
+	case expected:
+		return last.action, sleepScheduleResultUpToDate, reason
+	case "running", "stopped":
+		return last.action, "", reason
+	default:
+		return last.action, sleepScheduleResultSkipped, fmt.Sprintf("instance is %s", state)
+	}
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(computeSleepScheduleCmd, &computeSleepScheduleRunCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
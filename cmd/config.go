@@ -2,22 +2,112 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
-	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/exoscale/egoscale"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
 )
 
 type config struct {
 	DefaultAccount      string
 	DefaultOutputFormat string
+	APIRetries          *int
+	APIRetryMaxWait     string
+	Timeout             string
 	Accounts            []account
+
+	// loaded are the accounts and default account as loaded from the
+	// configuration file, see mergeAccounts().
+	loaded        map[string]account
+	loadedDefault string
+}
+
+// snapshot records the accounts as currently loaded, so that the changes
+// made to them by this process can be told apart from the ones made to the
+// configuration file by other exo processes in the meantime.
+func (c *config) snapshot(defaultAccount string) {
+	c.loaded = make(map[string]account, len(c.Accounts))
+	for _, acc := range c.Accounts {
+		c.loaded[acc.Name] = acc
+	}
+	c.loadedDefault = defaultAccount
+}
+
+// mergeAccounts returns the accounts of the configuration current merged with
+// the ones of the configuration saved, read from the configuration file
+// before overwriting it. The accounts added, modified or deleted by this
+// process since the configuration was loaded prevail, the other changes made
+// to the file in the meantime are retained. If current wasn't loaded from
+// the file, all the saved accounts it doesn't define are retained.
+func mergeAccounts(current, saved *config) []account {
+	savedAccounts := make(map[string]account, len(saved.Accounts))
+	for _, acc := range saved.Accounts {
+		savedAccounts[acc.Name] = acc
+	}
+
+	accounts := make([]account, 0, len(current.Accounts)+len(saved.Accounts))
+	currentAccounts := make(map[string]struct{}, len(current.Accounts))
+	for _, acc := range current.Accounts {
+		currentAccounts[acc.Name] = struct{}{}
+
+		loaded, wasLoaded := current.loaded[acc.Name]
+		if !wasLoaded || !reflect.DeepEqual(acc, loaded) {
+			accounts = append(accounts, acc)
+			continue
+		}
+
+		// Unchanged by this process: the saved account is retained as is,
+		// unless it has been deleted in the meantime.
+		if savedAcc, ok := savedAccounts[acc.Name]; ok {
+			accounts = append(accounts, savedAcc)
+		}
+	}
+
+	for _, acc := range saved.Accounts {
+		if _, ok := currentAccounts[acc.Name]; ok {
+			continue
+		}
+
+		// Accounts deleted by this process aren't retained.
+		if _, wasLoaded := current.loaded[acc.Name]; !wasLoaded {
+			accounts = append(accounts, acc)
+		}
+	}
+
+	return accounts
+}
+
+// readConfigFile reads the configuration file filePath, a missing file
+// being read as an empty configuration.
+func readConfigFile(filePath string) (*config, error) {
+	conf := &config{}
+
+	v := viper.New()
+	v.SetConfigType("toml")
+	v.SetConfigFile(filePath)
+	if err := v.ReadInConfig(); err != nil {
+		if os.IsNotExist(err) {
+			return conf, nil
+		}
+		return nil, fmt.Errorf("unable to read configuration file %q: %w", filePath, err)
+	}
+
+	if err := v.Unmarshal(conf); err != nil {
+		return nil, fmt.Errorf("unable to read configuration file %q: %w", filePath, err)
+	}
+
+	return conf, nil
 }
 
 type account struct {
@@ -32,26 +122,44 @@ type account struct {
 	Key                  string
 	Secret               string
 	SecretCommand        []string
+	SecretStore          string
 	DefaultZone          string
 	DefaultSSHKey        string
 	DefaultTemplate      string
 	DefaultRunstatusPage string
+	DefaultOutputFormat  string
+	Defaults             map[string]string
 	CustomHeaders        map[string]string
 }
 
 func (a account) APISecret() string {
+	secret, err := a.apiSecret()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return secret
+}
+
+// apiSecret returns the API secret of the account, retrieving it using the
+// account secret command or from its secret store if needed.
+func (a account) apiSecret() (string, error) {
 	if len(a.SecretCommand) != 0 {
 		cmd := exec.Command(a.SecretCommand[0], a.SecretCommand[1:]...)
 		cmd.Stdin = os.Stdin
 		cmd.Stderr = os.Stderr
 		out, err := cmd.Output()
 		if err != nil {
-			log.Fatal(err)
+			return "", err
 		}
-		return strings.TrimRight(string(out), "\n")
+		return strings.TrimRight(string(out), "\n"), nil
+	}
+
+	if err := a.resolveSecret(); err != nil {
+		return "", err
 	}
 
-	return a.Secret
+	return a.Secret, nil
 }
 
 func (a account) AccountName() string {
@@ -91,7 +199,41 @@ const (
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Exoscale CLI configuration management",
-	RunE:  configCmdRun,
+	Long: `This command manages the Exoscale CLI configuration file accounts. Without
+subcommand, the configured accounts are listed to select the default one.
+
+The configuration file is "exoscale.toml" in the exoscale directory of the
+user configuration directory, unless an alternative TOML file is specified
+using the "--config" flag or, if the flag is not set, the EXOSCALE_CONFIG
+environment variable ("~" is expanded to the home directory). All the "exo
+config" subcommands operate on the selected file, which is created by
+"exo config add" if it doesn't exist.
+
+In addition to the API credentials, the accounts of the configuration file
+support the following default settings, applied when the matching flags are
+not specified on the command line:
+
+  * defaultZone: the default zone
+  * defaultTemplate: the Compute instance template of the creation commands
+  * defaultOutputFormat: the output format (overriding the global
+    defaultOutputFormat setting)
+  * defaults: a table of default flag values, applied to every command
+    supporting the flag as if it was specified on the command line
+
+Example:
+
+    [[accounts]]
+    name = "prod"
+    key = "EXO..."
+    secret = "..."
+    defaultZone = "ch-gva-2"
+    defaultOutputFormat = "json"
+
+    [accounts.defaults]
+    instance-type = "standard.small"
+    disk-size = "20"
+`,
+	RunE: configCmdRun,
 }
 
 func configCmdRun(cmd *cobra.Command, _ []string) error {
@@ -148,17 +290,52 @@ Exoscale API credentials from your organization's IAM:
 }
 
 func saveConfig(filePath string, newAccounts *config) error {
-	accountsSize := 0
-	currentAccounts := []account{}
-	if gAllAccount != nil {
-		accountsSize = len(gAllAccount.Accounts)
-		currentAccounts = gAllAccount.Accounts
+	// The configuration file is read again once locked, as other exo
+	// processes might have modified it since it was loaded.
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	saved, err := readConfigFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	current := gAllAccount
+	if current == nil {
+		current = &config{}
 	}
 
 	newAccountsSize := 0
+	newAccountNames := map[string]struct{}{}
 
 	if newAccounts != nil {
 		newAccountsSize = len(newAccounts.Accounts)
+		for _, acc := range newAccounts.Accounts {
+			newAccountNames[acc.Name] = struct{}{}
+		}
+	}
+
+	currentAccounts := []account{}
+	for _, acc := range mergeAccounts(current, saved) {
+		if _, ok := newAccountNames[acc.Name]; !ok {
+			currentAccounts = append(currentAccounts, acc)
+		}
+	}
+	accountsSize := len(currentAccounts)
+
+	// The default account set by another process is retained, unless this
+	// process changed it too.
+	if defaultAccount := gConfig.GetString("defaultAccount"); defaultAccount == current.loadedDefault &&
+		saved.DefaultAccount != "" && saved.DefaultAccount != defaultAccount {
+		for _, acc := range currentAccounts {
+			if acc.Name == saved.DefaultAccount {
+				gConfig.Set("defaultAccount", saved.DefaultAccount)
+				break
+			}
+		}
 	}
 
 	accounts := make([]map[string]interface{}, accountsSize+newAccountsSize)
@@ -182,9 +359,21 @@ func saveConfig(filePath string, newAccounts *config) error {
 		if acc.DefaultTemplate != "" {
 			accounts[i]["defaultTemplate"] = acc.DefaultTemplate
 		}
-		if len(acc.SecretCommand) != 0 {
+		if acc.SosEndpoint != "" && acc.SosEndpoint != defaultSosEndpoint {
+			accounts[i]["sosEndpoint"] = acc.SosEndpoint
+		}
+		if acc.DefaultOutputFormat != "" {
+			accounts[i]["defaultOutputFormat"] = acc.DefaultOutputFormat
+		}
+		if len(acc.Defaults) > 0 {
+			accounts[i]["defaults"] = acc.Defaults
+		}
+		switch {
+		case len(acc.SecretCommand) != 0:
 			accounts[i]["secretCommand"] = acc.SecretCommand
-		} else {
+		case acc.SecretStore != "":
+			accounts[i]["secretStore"] = acc.SecretStore
+		default:
 			accounts[i]["secret"] = acc.Secret
 		}
 		accounts[i]["account"] = acc.Account
@@ -199,7 +388,11 @@ func saveConfig(filePath string, newAccounts *config) error {
 			accounts[accountsSize+i]["name"] = acc.Name
 			accounts[accountsSize+i]["endpoint"] = acc.Endpoint
 			accounts[accountsSize+i]["key"] = acc.Key
-			accounts[accountsSize+i]["secret"] = acc.Secret
+			if acc.SecretStore != "" {
+				accounts[accountsSize+i]["secretStore"] = acc.SecretStore
+			} else {
+				accounts[accountsSize+i]["secret"] = acc.Secret
+			}
 			accounts[accountsSize+i]["defaultZone"] = acc.DefaultZone
 			accounts[accountsSize+i]["environment"] = acc.Environment
 			if acc.DefaultSSHKey != "" {
@@ -208,6 +401,12 @@ func saveConfig(filePath string, newAccounts *config) error {
 			if acc.DefaultRunstatusPage != "" {
 				accounts[accountsSize+i]["defaultRunstatusPage"] = acc.DefaultRunstatusPage
 			}
+			if acc.DefaultOutputFormat != "" {
+				accounts[accountsSize+i]["defaultOutputFormat"] = acc.DefaultOutputFormat
+			}
+			if len(acc.Defaults) > 0 {
+				accounts[accountsSize+i]["defaults"] = acc.Defaults
+			}
 			accounts[accountsSize+i]["account"] = acc.Account
 			conf.Accounts = append(conf.Accounts, acc)
 		}
@@ -218,41 +417,47 @@ func saveConfig(filePath string, newAccounts *config) error {
 
 	gConfig.Set("accounts", accounts)
 
-	if err := gConfig.WriteConfig(); err != nil {
+	if err := writeFileAtomicFunc(filePath, 0o600, gConfig.WriteConfigAs); err != nil {
 		return err
 	}
 
-	conf.DefaultAccount = gConfig.Get("defaultAccount").(string)
+	conf.DefaultAccount = gConfig.GetString("defaultAccount")
+	conf.snapshot(conf.DefaultAccount)
 	gAllAccount = conf
 
 	return nil
 }
 
+// createConfigFile creates the configuration file selected using the
+// "--config" flag (or EXOSCALE_CONFIG environment variable) if any,
+// otherwise the file fileName in the configuration folder, returning its
+// path.
 func createConfigFile(fileName string) (string, error) {
-	if _, err := os.Stat(gConfigFolder); os.IsNotExist(err) {
-		if err := os.MkdirAll(gConfigFolder, os.ModePerm); err != nil {
-			return "", err
+	filePath := gConfig.ConfigFileUsed()
+	if filePath == "" {
+		filePath = filepath.Join(gConfigFolder, fileName+".toml")
+
+		if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+			return "", fmt.Errorf("%q exists already", filePath)
 		}
 	}
 
-	filepath := path.Join(gConfigFolder, fileName+".toml")
-
-	if gConfig.ConfigFileUsed() == "" {
-		if _, err := os.Stat(filepath); !os.IsNotExist(err) {
-			return "", fmt.Errorf("%q exists already", filepath)
-		}
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return "", err
 	}
 
-	fp, err := os.OpenFile(filepath, os.O_RDONLY|os.O_CREATE, 0600)
+	fp, err := os.OpenFile(filePath, os.O_RDONLY|os.O_CREATE, 0600)
 	if err != nil {
 		return "", err
 	}
 	defer fp.Close() // nolint: errcheck
 
-	return filepath, nil
+	return filePath, nil
 }
 
 func readInput(reader *bufio.Reader, text, def string) (string, error) {
+	defer suspendCommandTimeout()()
+
 	if def == "" {
 		fmt.Printf("[+] %s [%s]: ", text, "none")
 	} else {
@@ -285,15 +490,116 @@ func readInput(reader *bufio.Reader, text, def string) (string, error) {
 	return input, nil
 }
 
+// exitCodeNonInteractive is the exit code of the CLI when a command requires
+// a confirmation from the user but cannot prompt for it.
+const exitCodeNonInteractive = 4
+
+var errNonInteractive = errors.New("confirmation required but the standard input is not a terminal: " +
+	`use the "--force" flag or set the EXO_FORCE environment variable to "true" to run non-interactively`)
+
+// gStdin is the file confirmation prompts read user input from.
+var gStdin = os.Stdin
+
+// gForce is set by the global "--force" flag to skip confirmation prompts.
+var gForce bool
+
+// cmdSetForceFromFlag sets gForce if the "--force" flag of the command is
+// set. This flag is either the global one or a command-local flag shadowing
+// it (e.g. to provide the "-f" shorthand, which cannot be global as some
+// commands use it for other flags).
+func cmdSetForceFromFlag(cmd *cobra.Command) {
+	if force, err := cmd.Flags().GetBool("force"); err == nil && force {
+		gForce = true
+	}
+}
+
+// forced returns true if confirmations are to be granted without prompting,
+// i.e. if the "--force" flag is set or if the EXO_FORCE environment variable
+// is set to a true value.
+func forced() bool {
+	if gForce {
+		return true
+	}
+
+	force, err := strconv.ParseBool(os.Getenv("EXO_FORCE"))
+	return err == nil && force
+}
+
+// confirm prompts the user for a yes/no confirmation. If forced() returns
+// true, the confirmation is implicitly granted without prompting; if the
+// standard input is not a terminal (e.g. when running under cron),
+// errNonInteractive is returned instead of waiting for a user input that
+// will never come.
+func confirm(text string) (bool, error) {
+	if forced() {
+		return true, nil
+	}
+
+	if !term.IsTerminal(int(gStdin.Fd())) {
+		return false, errNonInteractive
+	}
+
+	resp, err := readInput(bufio.NewReader(gStdin), text, "yN")
+	if err != nil {
+		return false, err
+	}
+
+	return strings.ToLower(resp) == "y" || strings.ToLower(resp) == "yes", nil
+}
+
+// askQuestion prompts the user for a yes/no confirmation, and returns true
+// if the user confirmed. If the confirmation cannot be prompted for, the
+// program exits with the exitCodeNonInteractive status code.
 func askQuestion(text string) bool {
-	reader := bufio.NewReader(os.Stdin)
+	ok, err := confirm(text)
+	if err != nil {
+		exitOnConfirmError(err)
+	}
+
+	return ok
+}
 
-	resp, err := readInput(reader, text, "yN")
+// exitOnConfirmError reports an error occurring while prompting the user for
+// a confirmation (see writeCommandError()) and exits the CLI, with the
+// exitCodeNonInteractive status code if the confirmation cannot be prompted
+// for.
+func exitOnConfirmError(err error) {
+	writeCommandError(os.Stderr, nil, err)
+	if errors.Is(err, errNonInteractive) {
+		os.Exit(exitCodeNonInteractive)
+	}
+	os.Exit(1)
+}
+
+// confirmTyped prompts the user to confirm a destructive operation by typing
+// the expected value (e.g. the name of the resource to delete), with the same
+// "--force"/EXO_FORCE and non-interactive behavior as confirm().
+func confirmTyped(text, expected string) (bool, error) {
+	if forced() {
+		return true, nil
+	}
+
+	if !term.IsTerminal(int(gStdin.Fd())) {
+		return false, errNonInteractive
+	}
+
+	fmt.Println(text)
+	resp, err := readInput(bufio.NewReader(gStdin), fmt.Sprintf("Type %q to confirm", expected), "")
 	if err != nil {
-		log.Fatal(err)
+		return false, err
+	}
+
+	return resp == expected, nil
+}
+
+// askTypedQuestion is the confirmTyped() variant of askQuestion().
+func askTypedQuestion(text, expected string) bool {
+	ok, err := confirmTyped(text, expected)
+	if err != nil {
+		exitOnConfirmError(err)
 	}
 
-	return (strings.ToLower(resp) == "y" || strings.ToLower(resp) == "yes")
+	return ok
 }
 
 func listAccounts(defaultAccountMark string) []string {
@@ -2,32 +2,297 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/exoscale/egoscale"
 	"github.com/spf13/cobra"
 )
 
-func init() {
-	configCmd.AddCommand(&cobra.Command{
-		Use:   "add",
-		Short: "Add a new account to configuration",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			newAccount, err := promptAccountInformation()
-			if err != nil {
-				return err
-			}
+// configAddAccountFlags are the flags defining an account non-interactively.
+var configAddAccountFlags = []string{
+	"name",
+	"account",
+	"api-key",
+	"api-secret",
+	"api-endpoint",
+	"environment",
+	"sos-endpoint",
+	"default-zone",
+	"default-template",
+	"default-ssh-key",
+}
+
+var configAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a new account to configuration",
+	Long: `This command adds a new account to the configuration, prompting for the
+account information.
+
+Using the "--non-interactive" flag, or as soon as one of the account flags
+is specified, the account is defined by the flags only, without any prompt:
+
+    exo config add --name prod --api-key EXO... --api-secret - --default-zone ch-gva-2
+
+The "--name", "--api-key" and "--api-secret" flags are then required, the
+API secret being read from the standard input if set to "-" (so that it
+doesn't appear in the shell history or the processes list). An existing
+account is only replaced if the "--force" flag is set. If the configuration
+file doesn't exist it is created, and the first account added is set as
+default account.
+
+By default the API secret is stored in the configuration file. Using the
+"--secret-store keychain" flag, it is stored in the OS keychain instead
+(macOS Keychain, Windows Credential Manager or Secret Service on other
+systems, through the "secret-tool" command), the configuration file only
+referencing it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configAddNonInteractive(cmd) {
+			return addConfigAccountFromFlags(cmd)
+		}
+
+		secretStore, err := cmd.Flags().GetString("secret-store")
+		if err != nil {
+			return err
+		}
+		if secretStore, err = validateSecretStore(secretStore); err != nil {
+			return err
+		}
+
+		newAccount, err := promptAccountInformation()
+		if err != nil {
+			return err
+		}
+
+		newAccount.SecretStore = secretStore
+		if err := newAccount.storeSecret(); err != nil {
+			return err
+		}
+
+		config := &config{Accounts: []account{*newAccount}}
+		if askQuestion("Set [" + newAccount.Name + "] as default account?") {
+			config.DefaultAccount = newAccount.Name
+			gConfig.Set("defaultAccount", newAccount.Name)
+		}
+
+		filePath, err := configFileForSave()
+		if err != nil {
+			return err
+		}
+
+		return saveConfig(filePath, config)
+	},
+}
+
+// configAddNonInteractive returns true if the account is to be defined by
+// the "exo config add" command flags instead of prompts.
+func configAddNonInteractive(cmd *cobra.Command) bool {
+	if gNonInteractive {
+		return true
+	}
+
+	for _, flag := range configAddAccountFlags {
+		if cmd.Flags().Changed(flag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// accountFromFlags returns the account defined by the "exo config add"
+// command flags, reading the API secret from stdin if set to "-".
+func accountFromFlags(cmd *cobra.Command, stdin io.Reader) (*account, error) {
+	if err := cmdCheckRequiredFlags(cmd, []string{"name", "api-key", "api-secret"}); err != nil {
+		return nil, err
+	}
+
+	flags := make(map[string]string)
+	for _, flag := range configAddAccountFlags {
+		v, err := cmd.Flags().GetString(flag)
+		if err != nil {
+			return nil, err
+		}
+		flags[flag] = strings.TrimSpace(v)
+	}
+
+	secretStore, err := cmd.Flags().GetString("secret-store")
+	if err != nil {
+		return nil, err
+	}
+	if secretStore, err = validateSecretStore(secretStore); err != nil {
+		return nil, err
+	}
+
+	acc := &account{
+		Name:            flags["name"],
+		Account:         flags["account"],
+		Key:             flags["api-key"],
+		Secret:          flags["api-secret"],
+		Endpoint:        strings.TrimRight(flags["api-endpoint"], "/"),
+		Environment:     flags["environment"],
+		SosEndpoint:     strings.TrimRight(flags["sos-endpoint"], "/"),
+		DefaultZone:     flags["default-zone"],
+		DefaultTemplate: flags["default-template"],
+		DefaultSSHKey:   flags["default-ssh-key"],
+		SecretStore:     secretStore,
+	}
+
+	if acc.Secret == "-" {
+		secret, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("unable to read the API secret from standard input: %s", err)
+		}
+		if acc.Secret = strings.TrimSpace(secret); acc.Secret == "" {
+			return nil, errors.New("no API secret read from standard input")
+		}
+	}
+
+	if acc.Account == "" {
+		acc.Account = acc.Name
+	}
+
+	if acc.Endpoint == "" {
+		acc.Endpoint = defaultEndpoint
+	} else if err := validateAPIEndpointURL(acc.Endpoint); err != nil {
+		return nil, err
+	}
+	acc.DNSEndpoint = buildDNSAPIEndpoint(acc.Endpoint)
+
+	if acc.Environment == "" {
+		acc.Environment = defaultEnvironment
+	} else if strings.ContainsAny(acc.Environment, "./:") {
+		return nil, fmt.Errorf("invalid API environment %q", acc.Environment)
+	}
+
+	if acc.DefaultZone == "" {
+		acc.DefaultZone = defaultZone
+	} else if !isInList(allZones, acc.DefaultZone) {
+		return nil, fmt.Errorf("invalid zone %q (supported: %s)", acc.DefaultZone, strings.Join(allZones, ", "))
+	}
 
-			config := &config{Accounts: []account{*newAccount}}
-			if askQuestion("Set [" + newAccount.Name + "] as default account?") {
-				config.DefaultAccount = newAccount.Name
-				gConfig.Set("defaultAccount", newAccount.Name)
+	if acc.SosEndpoint != "" {
+		if err := validateAPIEndpointURL(strings.Replace(acc.SosEndpoint, "{zone}", acc.DefaultZone, 1)); err != nil {
+			return nil, err
+		}
+	}
+
+	return acc, nil
+}
+
+// addAccountToConfig adds the account acc to the configuration conf, an
+// existing account with the same name being replaced only if replace is true.
+func addAccountToConfig(conf *config, acc account, replace bool) error {
+	for i := range conf.Accounts {
+		if conf.Accounts[i].Name == acc.Name {
+			if !replace {
+				return fmt.Errorf("account %q already exists, use --force to overwrite it", acc.Name)
 			}
+			conf.Accounts[i] = acc
+			return nil
+		}
+	}
+
+	conf.Accounts = append(conf.Accounts, acc)
+	return nil
+}
+
+// addConfigAccountFromFlags adds the account defined by the "exo config add"
+// command flags to the configuration, without prompting.
+func addConfigAccountFromFlags(cmd *cobra.Command) error {
+	acc, err := accountFromFlags(cmd, gStdin)
+	if err != nil {
+		return err
+	}
+
+	setDefault, err := cmd.Flags().GetBool("set-default")
+	if err != nil {
+		return err
+	}
+
+	if err := saveAccount(acc, setDefault, forced()); err != nil {
+		return err
+	}
+
+	if !gQuiet {
+		return output(showConfig(acc.Name))
+	}
+
+	return nil
+}
+
+// saveAccount adds the account acc to the configuration file, creating the
+// file if it doesn't exist. The account is set as default account if
+// setDefault is true or if there is no default account yet, and an existing
+// account with the same name is only replaced if replace is true.
+func saveAccount(acc *account, setDefault, replace bool) error {
+	// Without configuration file, the current accounts are the one defined
+	// by the environment variables, which must not be saved.
+	if gConfigFilePath == envAccountName {
+		gAllAccount = nil
+	}
+
+	filePath, err := configFileForSave()
+	if err != nil {
+		return err
+	}
+
+	if gAllAccount == nil {
+		gAllAccount = &config{}
+	}
+
+	if err := addAccountToConfig(gAllAccount, *acc, replace); err != nil {
+		return err
+	}
+
+	if err := acc.storeSecret(); err != nil {
+		return err
+	}
 
-			return saveConfig(gConfig.ConfigFileUsed(), config)
-		},
-	})
+	if setDefault || gConfig.GetString("defaultAccount") == "" {
+		gConfig.Set("defaultAccount", acc.Name)
+	}
+
+	if err := saveConfig(filePath, nil); err != nil {
+		return err
+	}
+	gConfigFilePath = filePath
+
+	return nil
+}
+
+// configFileForSave returns the path of the configuration file to save the
+// accounts to, creating it if it doesn't exist (e.g. a file selected using
+// the "--config" flag).
+func configFileForSave() (string, error) {
+	filePath := gConfig.ConfigFileUsed()
+	if filePath != "" {
+		if _, err := os.Stat(filePath); err == nil {
+			return filePath, nil
+		}
+	}
+
+	return createConfigFile(defaultConfigFileName)
+}
+
+func init() {
+	configAddCmd.Flags().String("name", "", "account name")
+	configAddCmd.Flags().String("account", "", "Exoscale organization name (default: the account name)")
+	configAddCmd.Flags().String("api-key", "", "API key")
+	configAddCmd.Flags().String("api-secret", "", `API secret ("-" to read it from the standard input)`)
+	configAddCmd.Flags().String("api-endpoint", "", "Compute API endpoint URL (default: "+defaultEndpoint+")")
+	configAddCmd.Flags().String("environment", "", "API environment (default: "+defaultEnvironment+")")
+	configAddCmd.Flags().String("sos-endpoint", "", "Storage API endpoint URL (default: "+defaultSosEndpoint+")")
+	configAddCmd.Flags().String("default-zone", "", "default zone (default: "+defaultZone+")")
+	configAddCmd.Flags().String("default-template", "", "default Compute instance template")
+	configAddCmd.Flags().String("default-ssh-key", "", "default SSH key")
+	configAddCmd.Flags().Bool("set-default", false, "set the account as default account")
+	configAddCmd.Flags().String("secret-store", secretStoreFile,
+		fmt.Sprintf("API secret storage (%s)", strings.Join(secretStores, "|")))
+	configCmd.AddCommand(configAddCmd)
 }
 
 func addConfigAccount(firstRun bool) error {
@@ -44,12 +44,19 @@ var configDeleteCmd = &cobra.Command{
 			}
 		}
 
+		deleted := gAllAccount.Accounts[pos]
 		gAllAccount.Accounts = append(gAllAccount.Accounts[:pos], gAllAccount.Accounts[pos+1:]...)
 
 		if err := saveConfig(gConfig.ConfigFileUsed(), nil); err != nil {
 			return err
 		}
 
+		// The API secret is only removed from its secret store once the
+		// account is no longer referencing it.
+		if err := deleted.deleteSecret(); err != nil {
+			return err
+		}
+
 		println(args[0])
 		return nil
 	},
@@ -10,8 +10,9 @@ import (
 )
 
 type configListItemOutput struct {
-	Name    string `json:"name"`
-	Default bool   `json:"default"`
+	Name        string `json:"name"`
+	Default     bool   `json:"default"`
+	DefaultZone string `json:"default_zone"`
 }
 
 type configListOutput []configListItemOutput
@@ -22,7 +23,7 @@ func (o *configListOutput) toText() { outputText(o) }
 
 func (o *configListOutput) toTable() {
 	t := table.NewTable(os.Stdout)
-	t.SetHeader([]string{"Accounts"})
+	t.SetHeader([]string{"Accounts", "Default Zone"})
 
 	for _, i := range *o {
 		a := i.Name
@@ -30,7 +31,7 @@ func (o *configListOutput) toTable() {
 			a += "*"
 		}
 
-		t.Append([]string{a})
+		t.Append([]string{a, i.DefaultZone})
 	}
 
 	t.Render()
@@ -40,7 +41,8 @@ func init() {
 	configCmd.AddCommand(&cobra.Command{
 		Use:   "list",
 		Short: "List available accounts",
-		Long: fmt.Sprintf(`This command lists configured Exoscale accounts. The default account is marked with (*).
+		Long: fmt.Sprintf(`This command lists configured Exoscale accounts along with their default
+zone. The default account is marked with (*).
 
 Supported output template annotations: %s`,
 			strings.Join(outputterTemplateAnnotations(&configListOutput{}), ", ")),
@@ -59,9 +61,15 @@ func listConfigs() outputter {
 	}
 
 	for _, a := range gAllAccount.Accounts {
+		zone := a.DefaultZone
+		if zone == "" {
+			zone = defaultZone
+		}
+
 		out = append(out, configListItemOutput{
-			Name:    a.Name,
-			Default: a.IsDefault(),
+			Name:        a.Name,
+			Default:     a.IsDefault(),
+			DefaultZone: zone,
 		})
 	}
 
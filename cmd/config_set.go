@@ -2,13 +2,27 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 var configSetCmd = &cobra.Command{
-	Use:   "set NAME",
-	Short: "Set an account as default account",
+	Use:     "set NAME",
+	Short:   "Set an account as default account",
+	Aliases: []string{"set-account"},
+	Long: `This command sets the specified account as default account, updating the
+configuration file atomically.
+
+Using the "--print-env" flag, the configuration file is left untouched and
+the environment variables defining the account are printed as shell "export"
+commands instead, so that a shell can temporarily use another account:
+
+    eval "$(exo config set-account staging --print-env)"
+
+Note: the printed commands include the account API secret.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return cmd.Usage()
@@ -17,10 +31,29 @@ var configSetCmd = &cobra.Command{
 			return fmt.Errorf("no accounts configured")
 		}
 
-		if a := getAccountByName(args[0]); a == nil {
+		a := getAccountByName(args[0])
+		if a == nil {
 			return fmt.Errorf("account %q does not exist", args[0])
 		}
 
+		printEnv, err := cmd.Flags().GetBool("print-env")
+		if err != nil {
+			return err
+		}
+
+		if printEnv {
+			acc := *a
+			setAccountDefaults(&acc)
+
+			secret, err := acc.apiSecret()
+			if err != nil {
+				return err
+			}
+
+			printAccountEnv(os.Stdout, acc, secret)
+			return nil
+		}
+
 		gConfig.Set("defaultAccount", args[0])
 
 		if err := saveConfig(gConfig.ConfigFileUsed(), nil); err != nil {
@@ -33,6 +66,27 @@ var configSetCmd = &cobra.Command{
 	},
 }
 
+// printAccountEnv writes to w the shell commands exporting the environment
+// variables defining the account acc, using the API secret secret.
+func printAccountEnv(w io.Writer, acc account, secret string) {
+	vars := []struct{ name, value string }{
+		{"EXOSCALE_API_KEY", acc.Key},
+		{"EXOSCALE_API_SECRET", secret},
+		{"EXOSCALE_API_ENVIRONMENT", acc.Environment},
+		{"EXOSCALE_API_ENDPOINT", acc.Endpoint},
+		{"EXOSCALE_STORAGE_API_ENDPOINT", acc.SosEndpoint},
+		{"EXOSCALE_ZONE", acc.DefaultZone},
+	}
+
+	for _, v := range vars {
+		if v.value != "" {
+			fmt.Fprintf(w, "export %s='%s'\n", v.name, strings.ReplaceAll(v.value, "'", `'\''`))
+		}
+	}
+}
+
 func init() {
+	configSetCmd.Flags().Bool("print-env", false,
+		"print the shell commands exporting the account environment variables instead of setting the default account")
 	configCmd.AddCommand(configSetCmd)
 }
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/exoscale/egoscale"
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+// validateAPIEndpointURL checks that the specified value is an absolute
+// HTTP(S) URL.
+func validateAPIEndpointURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid endpoint URL %q", v)
+	}
+	return nil
+}
+
+// checkAccountAPIEndpoints performs lightweight authenticated API calls using
+// the specified account endpoints settings, to ensure they are valid before
+// saving them.
+func checkAccountAPIEndpoints(a *account, checkAPI, checkSOS bool) error {
+	if checkAPI {
+		client := egoscale.NewClient(a.Endpoint, a.Key, a.APISecret())
+		if _, err := client.RequestWithContext(gContext, egoscale.ListZones{}); err != nil {
+			return fmt.Errorf("unable to reach API endpoint %s: %s", a.Endpoint, err)
+		}
+
+		clientV2, err := exov2.NewClient(a.Key, a.APISecret(), exov2.ClientOptWithAPIEndpoint(a.Endpoint))
+		if err != nil {
+			return err
+		}
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(a.Environment, a.DefaultZone))
+		if _, err := clientV2.ListZones(ctx); err != nil {
+			return fmt.Errorf("unable to reach API environment %q: %s", a.Environment, err)
+		}
+	}
+
+	if checkSOS {
+		storage, err := newStorageClient(storageClientOptWithAccount(a))
+		if err != nil {
+			return err
+		}
+		if _, err := storage.ListBuckets(gContext, &s3.ListBucketsInput{}); err != nil {
+			return fmt.Errorf("unable to reach Storage API endpoint %s: %s", a.SosEndpoint, err)
+		}
+	}
+
+	return nil
+}
+
+var configSetEndpointCmd = &cobra.Command{
+	Use:   "set-endpoint [NAME]",
+	Short: "Set an account API endpoints",
+	Long: `This command overrides the API endpoints of an account (by default the
+current account), e.g. to use a testing or dedicated API environment.
+
+  * --api-endpoint: the Compute API endpoint URL (default: ` + defaultEndpoint + `)
+  * --environment: the API environment, i.e. the prefix of the zone API
+    hosts "<ENVIRONMENT>-<ZONE>.exoscale.com" (default: ` + defaultEnvironment + `)
+  * --sos-endpoint: the Storage API endpoint URL, where "{zone}" is replaced
+    by the bucket zone (default: ` + defaultSosEndpoint + `)
+
+Specifying an empty value resets the setting to its default. The new settings
+are checked by performing authenticated API calls before being saved.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+
+		for _, flag := range []string{"api-endpoint", "environment", "sos-endpoint"} {
+			if cmd.Flags().Changed(flag) {
+				return nil
+			}
+		}
+
+		cmdExitOnUsageError(cmd, "at least one of --api-endpoint, --environment or --sos-endpoint must be specified")
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if gAllAccount == nil || gConfig.ConfigFileUsed() == "" {
+			return fmt.Errorf("no accounts configured")
+		}
+
+		name := gCurrentAccount.AccountName()
+		if len(args) > 0 {
+			name = args[0]
+		}
+		a := getAccountByName(name)
+		if a == nil {
+			return fmt.Errorf("account %q does not exist", name)
+		}
+
+		updated := *a
+		if updated.Endpoint == "" {
+			if updated.Endpoint = updated.ComputeEndpoint; updated.Endpoint == "" {
+				updated.Endpoint = defaultEndpoint
+			}
+		}
+		if updated.Environment == "" {
+			updated.Environment = defaultEnvironment
+		}
+		if updated.SosEndpoint == "" {
+			updated.SosEndpoint = defaultSosEndpoint
+		}
+		if updated.DefaultZone == "" {
+			updated.DefaultZone = defaultZone
+		}
+
+		var checkAPI, checkSOS bool
+
+		if cmd.Flags().Changed("api-endpoint") {
+			v, _ := cmd.Flags().GetString("api-endpoint")
+			if v = strings.TrimRight(v, "/"); v == "" {
+				v = defaultEndpoint
+			}
+			if err := validateAPIEndpointURL(v); err != nil {
+				return err
+			}
+			updated.Endpoint = v
+			updated.DNSEndpoint = buildDNSAPIEndpoint(v)
+			checkAPI = true
+		}
+
+		if cmd.Flags().Changed("environment") {
+			v, _ := cmd.Flags().GetString("environment")
+			if v == "" {
+				v = defaultEnvironment
+			}
+			if strings.ContainsAny(v, "./:") {
+				return fmt.Errorf("invalid API environment %q", v)
+			}
+			updated.Environment = v
+			checkAPI = true
+		}
+
+		if cmd.Flags().Changed("sos-endpoint") {
+			v, _ := cmd.Flags().GetString("sos-endpoint")
+			if v = strings.TrimRight(v, "/"); v == "" {
+				v = defaultSosEndpoint
+			}
+			if err := validateAPIEndpointURL(strings.Replace(v, "{zone}", updated.DefaultZone, 1)); err != nil {
+				return err
+			}
+			updated.SosEndpoint = v
+			checkSOS = true
+		}
+
+		var err error
+		err = decorateAsyncOperation("Checking API endpoints...", func() error {
+			return checkAccountAPIEndpoints(&updated, checkAPI, checkSOS)
+		})
+		if err != nil {
+			return err
+		}
+
+		a.Endpoint = updated.Endpoint
+		a.DNSEndpoint = updated.DNSEndpoint
+		a.Environment = updated.Environment
+		a.SosEndpoint = updated.SosEndpoint
+
+		if err := saveConfig(gConfig.ConfigFileUsed(), nil); err != nil {
+			return err
+		}
+
+		if !gQuiet {
+			return output(showConfig(name))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	configSetEndpointCmd.Flags().String("api-endpoint", "", "Compute API endpoint URL")
+	configSetEndpointCmd.Flags().String("environment", "", "API environment")
+	configSetEndpointCmd.Flags().String("sos-endpoint", "", "Storage API endpoint URL")
+	configCmd.AddCommand(configSetEndpointCmd)
+}
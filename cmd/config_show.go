@@ -8,15 +8,19 @@ import (
 )
 
 type configShowOutput struct {
-	Name               string `json:"name"`
-	APIKey             string `json:"api_key"`
-	APISecret          string `json:"api_secret"`
-	DefaultZone        string `json:"default_zone"`
-	DefaultTemplate    string `json:"default_template,omitempty"`
-	ComputeAPIEndpoint string `json:"compute_api_endpoint,omitempty"`
-	StorageAPIEndpoint string `json:"storage_api_endpoint,omitempty"`
-	DNSAPIEndpoint     string `json:"dns_api_endpoint,omitempty" outputLabel:"DNS API Endpoint"`
-	ConfigFile         string `json:"config_file" outputLabel:"Configuration File"`
+	Name                string            `json:"name"`
+	Organization        string            `json:"organization,omitempty"`
+	APIKey              string            `json:"api_key"`
+	APISecret           string            `json:"api_secret"`
+	DefaultZone         string            `json:"default_zone"`
+	DefaultTemplate     string            `json:"default_template,omitempty"`
+	DefaultOutputFormat string            `json:"default_output_format,omitempty"`
+	APIEnvironment      string            `json:"api_environment" outputLabel:"API Environment"`
+	ComputeAPIEndpoint  string            `json:"compute_api_endpoint,omitempty"`
+	StorageAPIEndpoint  string            `json:"storage_api_endpoint,omitempty"`
+	DNSAPIEndpoint      string            `json:"dns_api_endpoint,omitempty" outputLabel:"DNS API Endpoint"`
+	Defaults            map[string]string `json:"defaults,omitempty" outputLabel:"Default Flags"`
+	ConfigFile          string            `json:"config_file" outputLabel:"Configuration File"`
 }
 
 func (o *configShowOutput) Type() string { return "Account" }
@@ -26,9 +30,11 @@ func (o *configShowOutput) toTable()     { outputTable(o) }
 
 func init() {
 	configCmd.AddCommand(&cobra.Command{
-		Use:   "show NAME",
+		Use:   "show [NAME]",
 		Short: "Show an account details",
-		Long: fmt.Sprintf(`This command shows an Exoscale account details.
+		Long: fmt.Sprintf(`This command shows an Exoscale account details. Without account name, the
+account currently used by the CLI is shown, as resolved from the flags,
+environment variables and configuration file (see "exo environment").
 
 Supported output template annotations: %s`,
 			strings.Join(outputterTemplateAnnotations(&configShowOutput{}), ", ")),
@@ -37,13 +43,14 @@ Supported output template annotations: %s`,
 			if gAllAccount == nil {
 				return fmt.Errorf("no accounts configured")
 			}
-			name := gCurrentAccount.AccountName()
 
-			if len(args) > 0 {
-				name = args[0]
+			// The current account is shown as resolved, e.g. including
+			// the settings overridden by the environment variables.
+			if len(args) == 0 || args[0] == gCurrentAccount.Name {
+				return output(showAccount(gCurrentAccount), nil)
 			}
 
-			return output(showConfig(name))
+			return output(showConfig(args[0]))
 		},
 	})
 }
@@ -54,22 +61,50 @@ func showConfig(name string) (outputter, error) {
 		return nil, fmt.Errorf("account %q was not found", name)
 	}
 
-	secret := strings.Repeat("×", len(account.Key))
-	if len(account.SecretCommand) > 0 {
+	return showAccount(account), nil
+}
+
+func showAccount(account *account) outputter {
+	secret := maskSecret(account.Secret)
+	switch {
+	case len(account.SecretCommand) > 0:
 		secret = strings.Join(account.SecretCommand, " ")
+	case account.SecretStore != "" && account.Secret == "":
+		secret = fmt.Sprintf("(stored in %s)", account.SecretStore)
+	}
+
+	environment := account.Environment
+	if environment == "" {
+		environment = defaultEnvironment
 	}
 
 	out := configShowOutput{
-		Name:               account.Name,
-		ConfigFile:         gConfigFilePath,
-		APIKey:             account.Key,
-		APISecret:          secret,
-		DefaultZone:        account.DefaultZone,
-		DefaultTemplate:    account.DefaultTemplate,
-		ComputeAPIEndpoint: account.Endpoint,
-		StorageAPIEndpoint: account.SosEndpoint,
-		DNSAPIEndpoint:     account.DNSEndpoint,
+		Name:                account.Name,
+		Organization:        account.Account,
+		ConfigFile:          gConfigFilePath,
+		APIKey:              account.Key,
+		APISecret:           secret,
+		DefaultZone:         account.DefaultZone,
+		DefaultTemplate:     account.DefaultTemplate,
+		DefaultOutputFormat: account.DefaultOutputFormat,
+		Defaults:            account.Defaults,
+		APIEnvironment:      environment,
+		ComputeAPIEndpoint:  account.Endpoint,
+		StorageAPIEndpoint:  account.SosEndpoint,
+		DNSAPIEndpoint:      account.DNSEndpoint,
+	}
+
+	return &out
+}
+
+// maskSecret returns the secret s masked except for its last 4 characters,
+// or entirely masked if it is too short to reveal any of it.
+func maskSecret(s string) string {
+	const visible = 4
+
+	if len(s) <= 2*visible {
+		return strings.Repeat("×", len(s))
 	}
 
-	return &out, nil
+	return strings.Repeat("×", len(s)-visible) + s[len(s)-visible:]
 }
@@ -0,0 +1,432 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_confirm(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, r.Close())
+
+	stdin := gStdin
+	gStdin = r
+	defer func() { gStdin = stdin }()
+
+	t.Run("closed stdin", func(t *testing.T) {
+		_ = os.Unsetenv("EXO_FORCE")
+		ok, err := confirm("Are you sure?")
+		require.False(t, ok)
+		require.True(t, errors.Is(err, errNonInteractive))
+	})
+
+	t.Run("EXO_FORCE", func(t *testing.T) {
+		require.NoError(t, os.Setenv("EXO_FORCE", "true"))
+		defer os.Unsetenv("EXO_FORCE") // nolint:errcheck
+		ok, err := confirm("Are you sure?")
+		require.True(t, ok)
+		require.NoError(t, err)
+	})
+
+	t.Run("--force", func(t *testing.T) {
+		_ = os.Unsetenv("EXO_FORCE")
+		gForce = true
+		defer func() { gForce = false }()
+		ok, err := confirm("Are you sure?")
+		require.True(t, ok)
+		require.NoError(t, err)
+		ok, err = confirmTyped("Domain will be deleted.", "example.net")
+		require.True(t, ok)
+		require.NoError(t, err)
+	})
+
+	t.Run("typed closed stdin", func(t *testing.T) {
+		_ = os.Unsetenv("EXO_FORCE")
+		ok, err := confirmTyped("Domain will be deleted.", "example.net")
+		require.False(t, ok)
+		require.True(t, errors.Is(err, errNonInteractive))
+	})
+
+	t.Run("typed EXO_FORCE", func(t *testing.T) {
+		require.NoError(t, os.Setenv("EXO_FORCE", "true"))
+		defer os.Unsetenv("EXO_FORCE") // nolint:errcheck
+		ok, err := confirmTyped("Domain will be deleted.", "example.net")
+		require.True(t, ok)
+		require.NoError(t, err)
+	})
+}
+
+// Test_askQuestion_nonInteractive runs destructive commands without the
+// "--force" flag in a sub-process with a closed standard input, and checks
+// that they fail immediately with the expected exit code instead of
+// blocking on the confirmation prompt.
+func Test_askQuestion_nonInteractive(t *testing.T) {
+	commands := map[string]cliCommand{
+		"nlb_delete":         &nlbDeleteCmd{NetworkLoadBalancer: "test"},
+		"instancepool_scale": &instancePoolScaleCmd{InstancePool: "test", Size: 2},
+	}
+
+	if name := os.Getenv("EXO_TEST_NONINTERACTIVE_CMD"); name != "" {
+		gOutputFormat = os.Getenv("EXO_TEST_NONINTERACTIVE_FORMAT")
+		_ = commands[name].cmdRun(&cobra.Command{}, nil)
+		os.Exit(0)
+	}
+
+	for name := range commands {
+		for _, format := range []string{"", "json"} {
+			t.Run(name+"/"+format, func(t *testing.T) {
+				cmd := exec.Command(os.Args[0], "-test.run=^Test_askQuestion_nonInteractive$")
+				cmd.Env = append(os.Environ(),
+					"EXO_TEST_NONINTERACTIVE_CMD="+name,
+					"EXO_TEST_NONINTERACTIVE_FORMAT="+format,
+					"EXO_FORCE=")
+				cmd.Stdin = nil
+
+				var stderr strings.Builder
+				cmd.Stderr = &stderr
+
+				err := cmd.Run()
+				var exitErr *exec.ExitError
+				require.True(t, errors.As(err, &exitErr), "expected command to fail, got: %v", err)
+				require.Equal(t, exitCodeNonInteractive, exitErr.ExitCode())
+				if format == "json" {
+					var out commandErrorOutput
+					require.NoError(t, json.Unmarshal([]byte(stderr.String()), &out))
+					require.Equal(t, errNonInteractive.Error(), out.Error)
+				} else {
+					require.Contains(t, stderr.String(), errNonInteractive.Error())
+				}
+			})
+		}
+	}
+}
+
+func Test_cmdSetForceFromFlag(t *testing.T) {
+	defer func() { gForce = false }()
+
+	root := &cobra.Command{Use: "root"}
+	root.PersistentFlags().BoolVar(&gForce, "force", false, "")
+	local := &cobra.Command{Use: "local", Run: func(*cobra.Command, []string) {}}
+	local.Flags().BoolP("force", "f", false, "")
+	global := &cobra.Command{Use: "global", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(local, global)
+
+	require.NoError(t, local.ParseFlags([]string{"-f"}))
+	cmdSetForceFromFlag(local)
+	require.True(t, gForce)
+
+	gForce = false
+	require.NoError(t, global.ParseFlags(nil))
+	cmdSetForceFromFlag(global)
+	require.False(t, gForce)
+
+	require.NoError(t, global.ParseFlags([]string{"--force"}))
+	cmdSetForceFromFlag(global)
+	require.True(t, gForce)
+}
+
+func Test_validateAPIEndpointURL(t *testing.T) {
+	for _, v := range []string{"https://api.exoscale.com/v1", "http://localhost:8080", "https://sos-ch-gva-2.exo.io"} {
+		require.NoError(t, validateAPIEndpointURL(v), v)
+	}
+	for _, v := range []string{"", "api.exoscale.com", "ftp://api.exoscale.com", "https://"} {
+		require.Error(t, validateAPIEndpointURL(v), v)
+	}
+}
+
+func Test_accountFromFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().AddFlagSet(configAddCmd.Flags())
+		resetCommandFlags(cmd)
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	acc, err := accountFromFlags(newCmd(
+		"--name", "prod",
+		"--api-key", "EXOabc",
+		"--api-secret", "-",
+		"--default-zone", "ch-gva-2",
+		"--environment", "api",
+	), strings.NewReader("s3cr3t\n"))
+	require.NoError(t, err)
+	require.Equal(t, &account{
+		Name:        "prod",
+		Account:     "prod",
+		Key:         "EXOabc",
+		Secret:      "s3cr3t",
+		Endpoint:    defaultEndpoint,
+		DNSEndpoint: buildDNSAPIEndpoint(defaultEndpoint),
+		Environment: "api",
+		DefaultZone: "ch-gva-2",
+	}, acc)
+
+	_, err = accountFromFlags(newCmd("--name", "prod", "--api-key", "EXOabc", "--api-secret", "-"),
+		strings.NewReader(""))
+	require.EqualError(t, err, "no API secret read from standard input")
+
+	_, err = accountFromFlags(newCmd("--name", "prod"), strings.NewReader(""))
+	require.Error(t, err)
+
+	_, err = accountFromFlags(newCmd("--name", "prod", "--api-key", "EXOabc", "--api-secret", "x",
+		"--default-zone", "xx-yyy-1"), strings.NewReader(""))
+	require.Error(t, err)
+
+	_, err = accountFromFlags(newCmd("--name", "prod", "--api-key", "EXOabc", "--api-secret", "x",
+		"--environment", "api.exoscale.com"), strings.NewReader(""))
+	require.EqualError(t, err, `invalid API environment "api.exoscale.com"`)
+}
+
+func Test_addAccountToConfig(t *testing.T) {
+	conf := &config{Accounts: []account{{Name: "prod", Key: "EXOold"}}}
+
+	require.NoError(t, addAccountToConfig(conf, account{Name: "dev", Key: "EXOdev"}, false))
+	require.Len(t, conf.Accounts, 2)
+
+	err := addAccountToConfig(conf, account{Name: "prod", Key: "EXOnew"}, false)
+	require.EqualError(t, err, `account "prod" already exists, use --force to overwrite it`)
+	require.Equal(t, "EXOold", conf.Accounts[0].Key)
+
+	require.NoError(t, addAccountToConfig(conf, account{Name: "prod", Key: "EXOnew"}, true))
+	require.Len(t, conf.Accounts, 2)
+	require.Equal(t, "EXOnew", conf.Accounts[0].Key)
+}
+
+func Test_maskSecret(t *testing.T) {
+	require.Equal(t, "", maskSecret(""))
+	require.Equal(t, "××××××", maskSecret("s3cr3t"))
+	require.Equal(t, "××××××××××Ab12", maskSecret("abcdefghijAb12"))
+}
+
+func Test_validateAccount(t *testing.T) {
+	savedContext := gContext
+	t.Cleanup(func() { gContext = savedContext })
+	gContext = context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Query().Get("apikey") == "EXObad":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errorresponse":{"errorcode":401,"errortext":"unable to verify user credentials"}}`))
+
+		case r.URL.Query().Get("apikey") == "EXOrestricted" && r.URL.Query().Get("command") == "listAccounts":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errorresponse":{"errorcode":403,"errortext":"operation not allowed"}}`))
+
+		case r.URL.Query().Get("command") == "listAccounts":
+			_, _ = w.Write([]byte(`{"listaccountsresponse":{"count":1,"account":[{"name":"my-org"}]}}`))
+
+		case r.URL.Query().Get("command") == "listZones":
+			_, _ = w.Write([]byte(`{"listzonesresponse":{"count":1,"zone":[{"name":"ch-gva-2"}]}}`))
+
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	require.Equal(t, configValidateItemOutput{Name: "prod", Organization: "my-org", Status: "OK"},
+		validateAccount(account{Name: "prod", Endpoint: server.URL, Key: "EXOgood", Secret: "secret"}))
+
+	require.Equal(t, configValidateItemOutput{Name: "ci", Organization: "ci-org", Status: "OK"},
+		validateAccount(account{Name: "ci", Account: "ci-org", Endpoint: server.URL, Key: "EXOrestricted", Secret: "secret"}))
+
+	out := validateAccount(account{Name: "old", Endpoint: server.URL, Key: "EXObad", Secret: "secret"})
+	require.Equal(t, "failed", out.Status)
+	require.Contains(t, out.Error, "unable to verify user credentials")
+
+	out = validateAccount(account{Name: "cmd", Endpoint: server.URL, Key: "EXOgood", SecretCommand: []string{"false"}})
+	require.Equal(t, "failed", out.Status)
+	require.NotEmpty(t, out.Error)
+}
+
+func Test_printAccountEnv(t *testing.T) {
+	acc := account{Name: "staging", Key: "EXOabc", DefaultZone: "de-fra-1"}
+	setAccountDefaults(&acc)
+
+	var buf bytes.Buffer
+	printAccountEnv(&buf, acc, `s3'cr$et`)
+	require.Equal(t, `export EXOSCALE_API_KEY='EXOabc'
+export EXOSCALE_API_SECRET='s3'\''cr$et'
+export EXOSCALE_API_ENVIRONMENT='api'
+export EXOSCALE_API_ENDPOINT='https://api.exoscale.com/v1'
+export EXOSCALE_STORAGE_API_ENDPOINT='https://sos-{zone}.exo.io'
+export EXOSCALE_ZONE='de-fra-1'
+`, buf.String())
+
+	// The exported variables define the account once evaluated by a shell.
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no shell available")
+	}
+	out, err := exec.Command(sh, "-c", buf.String()+`printf %s "$EXOSCALE_API_SECRET"`).Output()
+	require.NoError(t, err)
+	require.Equal(t, `s3'cr$et`, string(out))
+}
+
+func Test_listConfigs(t *testing.T) {
+	savedAllAccount := gAllAccount
+	t.Cleanup(func() { gAllAccount = savedAllAccount })
+
+	gAllAccount = &config{
+		DefaultAccount: "staging",
+		Accounts: []account{
+			{Name: "prod", DefaultZone: "ch-gva-2"},
+			{Name: "staging"},
+		},
+	}
+
+	require.Equal(t, &configListOutput{
+		{Name: "prod", DefaultZone: "ch-gva-2"},
+		{Name: "staging", Default: true, DefaultZone: defaultZone},
+	}, listConfigs())
+}
+
+func Test_mergeAccounts(t *testing.T) {
+	current := &config{Accounts: []account{
+		{Name: "unchanged", Key: "EXOunchanged"},
+		{Name: "modified", Key: "EXOmodified"},
+		{Name: "deleted", Key: "EXOdeleted"},
+	}}
+	current.snapshot("unchanged")
+
+	// Changes made by this process.
+	current.Accounts[1].DefaultZone = "de-fra-1"
+	current.Accounts = append(current.Accounts[:2], account{Name: "added", Key: "EXOadded"})
+
+	// Changes made to the file by another process in the meantime.
+	saved := &config{Accounts: []account{
+		{Name: "unchanged", Key: "EXOunchanged", DefaultZone: "at-vie-1"},
+		{Name: "modified", Key: "EXOmodified"},
+		{Name: "deleted", Key: "EXOdeleted"},
+		{Name: "concurrent", Key: "EXOconcurrent"},
+	}}
+
+	require.Equal(t, []account{
+		{Name: "unchanged", Key: "EXOunchanged", DefaultZone: "at-vie-1"},
+		{Name: "modified", Key: "EXOmodified", DefaultZone: "de-fra-1"},
+		{Name: "added", Key: "EXOadded"},
+		{Name: "concurrent", Key: "EXOconcurrent"},
+	}, mergeAccounts(current, saved))
+
+	// Accounts deleted by another process and unchanged by this one remain
+	// deleted.
+	saved.Accounts = saved.Accounts[1:]
+	require.Equal(t, []account{
+		{Name: "modified", Key: "EXOmodified", DefaultZone: "de-fra-1"},
+		{Name: "added", Key: "EXOadded"},
+		{Name: "concurrent", Key: "EXOconcurrent"},
+	}, mergeAccounts(current, saved))
+
+	// Without loaded configuration, all the saved accounts are retained.
+	require.Equal(t, []account{
+		{Name: "added", Key: "EXOadded"},
+		{Name: "modified", Key: "EXOmodified"},
+		{Name: "deleted", Key: "EXOdeleted"},
+		{Name: "concurrent", Key: "EXOconcurrent"},
+	}, mergeAccounts(&config{Accounts: []account{{Name: "added", Key: "EXOadded"}}}, saved))
+}
+
+// Test_saveConfig_concurrentWriters runs exo processes adding an account to
+// the same configuration file simultaneously, each of them having loaded the
+// configuration file before any of the others saved it.
+func Test_saveConfig_concurrentWriters(t *testing.T) {
+	if configFile := os.Getenv("EXO_TEST_CONFIG_FILE"); configFile != "" {
+		name := os.Getenv("EXO_TEST_ACCOUNT_NAME")
+
+		_ = os.Unsetenv("EXOSCALE_CONFIG")
+		RootCmd.Flags().AddFlagSet(RootCmd.PersistentFlags())
+		gConfig = viper.New()
+		gConfigFilePath = configFile
+		initConfig()
+
+		// All the processes are started once they have all loaded the
+		// configuration file.
+		if err := os.WriteFile(configFile+"."+name+".ready", nil, 0o600); err != nil {
+			log.Fatal(err)
+		}
+		for {
+			if _, err := os.Stat(configFile + ".start"); err == nil {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		err := saveAccount(&account{
+			Name:        name,
+			Key:         "EXO" + name,
+			Secret:      "secret",
+			Environment: defaultEnvironment,
+			DefaultZone: defaultZone,
+		}, name == "writer-0", false)
+		if err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	const writers = 8
+
+	configFile := filepath.Join(t.TempDir(), "exoscale.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`defaultaccount = "initial"
+
+[[accounts]]
+name = "initial"
+key = "EXOinitial"
+secret = "secret"
+`), 0o600))
+
+	cmds := make([]*exec.Cmd, writers)
+	stderr := make([]strings.Builder, writers)
+	for i := range cmds {
+		name := "writer-" + strconv.Itoa(i)
+		cmds[i] = exec.Command(os.Args[0], "-test.run=^Test_saveConfig_concurrentWriters$")
+		cmds[i].Env = append(os.Environ(), "EXO_TEST_CONFIG_FILE="+configFile, "EXO_TEST_ACCOUNT_NAME="+name)
+		cmds[i].Stderr = &stderr[i]
+		require.NoError(t, cmds[i].Start())
+	}
+
+	require.Eventually(t, func() bool {
+		matches, err := filepath.Glob(configFile + ".*.ready")
+		return err == nil && len(matches) == writers
+	}, 30*time.Second, 10*time.Millisecond)
+	require.NoError(t, os.WriteFile(configFile+".start", nil, 0o600))
+
+	for i, cmd := range cmds {
+		require.NoError(t, cmd.Wait(), stderr[i].String())
+	}
+
+	// All the writers' accounts survived.
+	saved, err := readConfigFile(configFile)
+	require.NoError(t, err)
+	names := make([]string, len(saved.Accounts))
+	for i, acc := range saved.Accounts {
+		names[i] = acc.Name
+	}
+	require.ElementsMatch(t, names, []string{
+		"initial", "writer-0", "writer-1", "writer-2", "writer-3",
+		"writer-4", "writer-5", "writer-6", "writer-7",
+	})
+	require.Equal(t, "writer-0", saved.DefaultAccount)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+)
+
+type configValidateItemOutput struct {
+	Name         string `json:"name"`
+	Organization string `json:"organization"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
+
+type configValidateOutput []configValidateItemOutput
+
+func (o *configValidateOutput) toJSON()  { outputJSON(o) }
+func (o *configValidateOutput) toText()  { outputText(o) }
+func (o *configValidateOutput) toTable() { outputTable(o) }
+
+func init() {
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configured accounts credentials",
+		Long: fmt.Sprintf(`This command checks the API credentials of every configured account by
+performing an authenticated API request, and reports the result for each
+account. The command exits with an error if at least one account is invalid.
+
+Supported output template annotations: %s`,
+			strings.Join(outputterTemplateAnnotations(&configValidateItemOutput{}), ", ")),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if gAllAccount == nil {
+				return fmt.Errorf("no accounts configured")
+			}
+
+			accounts := gAllAccount.Accounts
+			out := make(configValidateOutput, len(accounts))
+			failed := 0
+
+			err := decorateAsyncOperation("Validating accounts...", func() error {
+				for i := range accounts {
+					acc := accounts[i]
+					// The current account is checked as resolved,
+					// e.g. with the environment variables overrides.
+					if acc.Name == gCurrentAccount.Name {
+						acc = *gCurrentAccount
+					}
+
+					out[i] = validateAccount(acc)
+					if out[i].Error != "" {
+						failed++
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := output(&out, nil); err != nil {
+				return err
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d account(s) out of %d failed validation", failed, len(out))
+			}
+
+			return nil
+		},
+	})
+}
+
+// validateAccount checks the API credentials of the account acc.
+func validateAccount(acc account) configValidateItemOutput {
+	out := configValidateItemOutput{Name: acc.Name, Organization: acc.Account}
+
+	secret, err := acc.apiSecret()
+	if err == nil {
+		endpoint := acc.Endpoint
+		if endpoint == "" {
+			endpoint = defaultEndpoint
+		}
+
+		var organization string
+		if organization, err = checkAPICredentials(egoscale.NewClient(endpoint, acc.Key, secret)); organization != "" {
+			out.Organization = organization
+		}
+	}
+
+	if err != nil {
+		// The request URL of transport errors is irrelevant (and long).
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		out.Status = "failed"
+		out.Error = err.Error()
+		return out
+	}
+
+	out.Status = "OK"
+	return out
+}
+
+// checkAPICredentials checks the API credentials of the client by
+// performing a lightweight authenticated API request, returning the name
+// of the organization they belong to if it can be retrieved.
+func checkAPICredentials(client *egoscale.Client) (string, error) {
+	res, err := client.GetWithContext(gContext, egoscale.Account{})
+	if err != nil {
+		// Restricted API keys are not allowed to retrieve the
+		// organization information: the credentials are checked by
+		// listing the zones instead.
+		var apiErr *egoscale.ErrorResponse
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode != egoscale.ErrorCode(403) {
+			return "", err
+		}
+		_, err = client.RequestWithContext(gContext, egoscale.ListZones{})
+		return "", err
+	}
+
+	return res.(*egoscale.Account).Name, nil
+}
@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -42,3 +46,208 @@ var dbCmd = &cobra.Command{
 func init() {
 	labCmd.AddCommand(dbCmd)
 }
+
+// dbSetting represents a Database Service type setting, as described in
+// the type user configuration JSON schema.
+type dbSetting struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default"`
+	Min         string   `json:"min"`
+	Max         string   `json:"max"`
+	Description string   `json:"description"`
+	Enum        []string `json:"enum,omitempty" output:"wide" outputLabel:"Values"`
+}
+
+// dbSettingsFromSchema returns the list of settings described in a Database
+// Service type user configuration JSON schema, sorted by name. Nested
+// settings names are prefixed by their parent object name using a "."
+// separator (e.g. "pg.max_connections").
+func dbSettingsFromSchema(schema map[string]interface{}) []dbSetting {
+	settings := make([]dbSetting, 0)
+	dbWalkSettingsSchema("", schema, &settings)
+
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Name < settings[j].Name })
+
+	return settings
+}
+
+func dbWalkSettingsSchema(prefix string, schema map[string]interface{}, settings *[]dbSetting) {
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for name, v := range properties {
+		property, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		settingType := dbSettingSchemaType(property)
+		if settingType == "object" {
+			if _, ok := property["properties"]; ok {
+				dbWalkSettingsSchema(name, property, settings)
+				continue
+			}
+		}
+
+		setting := dbSetting{
+			Name: name,
+			Type: settingType,
+			Description: func() string {
+				if d, ok := property["description"].(string); ok {
+					return d
+				}
+				if d, ok := property["title"].(string); ok {
+					return d
+				}
+				return ""
+			}(),
+		}
+
+		if d, ok := property["default"]; ok && d != nil {
+			setting.Default = fmt.Sprint(d)
+		}
+
+		if min, ok := property["minimum"].(float64); ok {
+			setting.Min = strconv.FormatFloat(min, 'f', -1, 64)
+		}
+
+		if max, ok := property["maximum"].(float64); ok {
+			setting.Max = strconv.FormatFloat(max, 'f', -1, 64)
+		}
+
+		if enum, ok := property["enum"].([]interface{}); ok {
+			for _, v := range enum {
+				if v != nil {
+					setting.Enum = append(setting.Enum, fmt.Sprint(v))
+				}
+			}
+		}
+
+		*settings = append(*settings, setting)
+	}
+}
+
+// dbSettingSchemaType returns the JSON schema type of a property, ignoring
+// the "null" type in case of multiple types.
+func dbSettingSchemaType(property map[string]interface{}) string {
+	switch t := property["type"].(type) {
+	case string:
+		return t
+
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+
+	return ""
+}
+
+// dbApplySettings validates the specified settings (formatted as a
+// "name" -> "value" map) against a Database Service type user configuration
+// JSON schema, and sets their values converted to the type expected in the
+// user configuration specified.
+func dbApplySettings(
+	schema map[string]interface{},
+	settings map[string]string,
+	userConfig map[string]interface{},
+) error {
+	known := make(map[string]dbSetting)
+	names := make([]string, 0)
+	for _, s := range dbSettingsFromSchema(schema) {
+		known[s.Name] = s
+		names = append(names, s.Name)
+	}
+
+	for name, rawValue := range settings {
+		setting, ok := known[name]
+		if !ok {
+			if suggestion := closestMatch(name, names); suggestion != "" {
+				return fmt.Errorf("unknown setting %q, did you mean %q?", name, suggestion)
+			}
+			return fmt.Errorf("unknown setting %q", name)
+		}
+
+		if len(setting.Enum) > 0 && !isInList(setting.Enum, rawValue) {
+			return fmt.Errorf("invalid value %q for setting %q: supported values are %s",
+				rawValue, name, strings.Join(setting.Enum, ", "))
+		}
+
+		var value interface{}
+		switch setting.Type {
+		case "integer":
+			v, err := strconv.ParseInt(rawValue, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for setting %q: expected an integer", rawValue, name)
+			}
+			if err := setting.checkBounds(float64(v)); err != nil {
+				return err
+			}
+			value = v
+
+		case "number":
+			v, err := strconv.ParseFloat(rawValue, 64)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for setting %q: expected a number", rawValue, name)
+			}
+			if err := setting.checkBounds(v); err != nil {
+				return err
+			}
+			value = v
+
+		case "boolean":
+			v, err := strconv.ParseBool(rawValue)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for setting %q: expected a boolean", rawValue, name)
+			}
+			value = v
+
+		case "string":
+			value = rawValue
+
+		default:
+			return fmt.Errorf(
+				`setting %q of type %s cannot be set using the "--setting" flag, use a user config file instead`,
+				name,
+				setting.Type,
+			)
+		}
+
+		parts := strings.Split(name, ".")
+		parent := userConfig
+		for _, p := range parts[:len(parts)-1] {
+			child, ok := parent[p].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				parent[p] = child
+			}
+			parent = child
+		}
+		parent[parts[len(parts)-1]] = value
+	}
+
+	return nil
+}
+
+// checkBounds returns an error if the value v is outside of the setting
+// minimum/maximum bounds.
+func (s dbSetting) checkBounds(v float64) error {
+	if min, err := strconv.ParseFloat(s.Min, 64); err == nil && v < min {
+		return fmt.Errorf("invalid value for setting %q: minimum is %s", s.Name, s.Min)
+	}
+
+	if max, err := strconv.ParseFloat(s.Max, 64); err == nil && v > max {
+		return fmt.Errorf("invalid value for setting %q: maximum is %s", s.Name, s.Max)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbCACertificateCmd struct {
+	_ bool `cli-cmd:"ca-certificate"`
+
+	Zone string `cli-short:"z" cli-usage:"Database Service zone"`
+}
+
+func (c *dbCACertificateCmd) cmdAliases() []string { return []string{"ca-cert"} }
+
+func (c *dbCACertificateCmd) cmdShort() string {
+	return "Retrieve the Database Services CA certificate"
+}
+
+func (c *dbCACertificateCmd) cmdLong() string {
+	return `This command retrieves the certificate of the Certificate Authority (CA)
+signing the Database Services certificates in the specified zone, in PEM
+format. The CA certificate is required by the external clients connecting to
+Database Services using TLS, e.g.:
+
+    exo lab database ca-certificate -z de-fra-1 > ca.pem
+`
+}
+
+func (c *dbCACertificateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbCACertificateCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	res, err := cs.GetDbaasCaCertificateWithResponse(ctx)
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil || res.JSON200.Certificate == nil {
+		return errors.New("no CA certificate returned by the API")
+	}
+
+	fmt.Print(*res.JSON200.Certificate)
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbCmd, &dbCACertificateCmd{}))
+}
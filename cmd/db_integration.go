@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+var dbIntegrationCmd = &cobra.Command{
+	Use:   "integration",
+	Short: "Database Services integrations management",
+	Long: `This command manages the integrations between Database Services, such as the
+forwarding of a service metrics or logs to another service (e.g. PostgreSQL
+metrics to a Grafana service).`,
+}
+
+// dbIntegrationType represents a Database Service integration type, as
+// returned by the "/dbaas-integration-types" API endpoint.
+type dbIntegrationType struct {
+	Type       string                 `json:"type"`
+	SourceType string                 `json:"source-type"`
+	DestType   string                 `json:"dest-type"`
+	Settings   map[string]interface{} `json:"settings"`
+}
+
+// dbIntegrationAPIRequest performs a request to the Database Services
+// integrations API, which the egoscale client doesn't support, and decodes the
+// JSON response body into out (if not nil).
+func dbIntegrationAPIRequest(ctx context.Context, zone, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	endpoint := exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		method,
+		fmt.Sprintf("https://%s/%s%s", endpoint.Host(), exoapi.Prefix, path),
+		reqBody,
+	)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	signer, err := exoapi.NewSecurityProvider(gCurrentAccount.Key, gCurrentAccount.APISecret())
+	if err != nil {
+		return err
+	}
+	if err := signer.Intercept(ctx, req); err != nil {
+		return err
+	}
+
+	res, err := csHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("API error: %s: %s", res.Status, strings.TrimSpace(string(data)))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return fmt.Errorf("unable to decode API response: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// dbIntegrationOperation performs a Database Services integrations API
+// request returning an async operation, and waits for the operation to
+// complete.
+func dbIntegrationOperation(ctx context.Context, zone, method, path string, body interface{}) error {
+	var op struct {
+		ID string `json:"id"`
+	}
+	if err := dbIntegrationAPIRequest(ctx, zone, method, path, body, &op); err != nil {
+		return err
+	}
+	if op.ID == "" {
+		return fmt.Errorf("API error: no operation returned by %s %s", method, path)
+	}
+
+	return waitForOperation(ctx, zone, op.ID)
+}
+
+// listDBIntegrationTypes returns the Database Service integration types.
+func listDBIntegrationTypes(ctx context.Context, zone string) ([]dbIntegrationType, error) {
+	var types struct {
+		Types []dbIntegrationType `json:"dbaas-integration-types"`
+	}
+	if err := dbIntegrationAPIRequest(ctx, zone, http.MethodGet, "/dbaas-integration-types", nil, &types); err != nil {
+		return nil, err
+	}
+
+	return types.Types, nil
+}
+
+// findDBIntegrationType returns the integration type typ between services
+// of types sourceType and destType, or an error listing the supported
+// integration types between such services if there is none.
+func findDBIntegrationType(types []dbIntegrationType, typ, sourceType, destType string) (*dbIntegrationType, error) {
+	supported := make([]string, 0)
+	for i, t := range types {
+		if t.SourceType != sourceType || t.DestType != destType {
+			continue
+		}
+		if t.Type == typ {
+			return &types[i], nil
+		}
+		supported = append(supported, t.Type)
+	}
+
+	if len(supported) == 0 {
+		return nil, fmt.Errorf("no integration is supported from %s to %s Database Services", sourceType, destType)
+	}
+
+	sort.Strings(supported)
+	return nil, fmt.Errorf("unsupported integration type %q from %s to %s Database Services (supported: %s)",
+		typ, sourceType, destType, strings.Join(supported, ", "))
+}
+
+// dbIntegrationStatus returns the status of an integration from its
+// enabled/active states.
+func dbIntegrationStatus(enabled, active bool) string {
+	switch {
+	case !enabled:
+		return "disabled"
+	case active:
+		return "active"
+	default:
+		return "pending"
+	}
+}
+
+func init() {
+	dbCmd.AddCommand(dbIntegrationCmd)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbIntegrationCreateCmd struct {
+	_ bool `cli-cmd:"create"`
+
+	Destination string            `cli-usage:"destination Database Service name"`
+	Settings    map[string]string `cli-flag:"setting" cli-usage:"integration setting (format: name=value, can be specified multiple times)"`
+	Source      string            `cli-usage:"source Database Service name"`
+	Type        string            `cli-usage:"integration type (e.g. metrics, logs, datasource)"`
+	Zone        string            `cli-short:"z" cli-usage:"Database Services zone"`
+}
+
+func (c *dbIntegrationCreateCmd) cmdAliases() []string { return gCreateAlias }
+
+func (c *dbIntegrationCreateCmd) cmdShort() string { return "Create a Database Services integration" }
+
+func (c *dbIntegrationCreateCmd) cmdLong() string {
+	return fmt.Sprintf(`This command creates an integration between two Database Services of a zone,
+e.g. the forwarding of a PostgreSQL service metrics to a Grafana service:
+
+    exo lab database integration create --type metrics --source my-pg --destination my-grafana
+
+The integration type must be supported between the types of the source and
+destination services, and the settings specified using the "--setting" flag
+are validated against the integration type settings before submission.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&dbIntegrationListItemOutput{}), ", "))
+}
+
+func (c *dbIntegrationCreateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	if err := cmdCheckRequiredFlags(cmd, []string{"type", "source", "destination"}); err != nil {
+		return err
+	}
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbIntegrationCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	source, err := cs.GetDatabaseService(ctx, c.Zone, c.Source)
+	if err != nil {
+		return fmt.Errorf("error retrieving source Database Service: %s", err)
+	}
+
+	destination, err := cs.GetDatabaseService(ctx, c.Zone, c.Destination)
+	if err != nil {
+		return fmt.Errorf("error retrieving destination Database Service: %s", err)
+	}
+
+	types, err := listDBIntegrationTypes(ctx, c.Zone)
+	if err != nil {
+		return fmt.Errorf("error retrieving Database Service integration types: %s", err)
+	}
+
+	integrationType, err := findDBIntegrationType(types, c.Type, *source.Type, *destination.Type)
+	if err != nil {
+		return err
+	}
+
+	settings := make(map[string]interface{})
+	if err := dbApplySettings(integrationType.Settings, c.Settings, settings); err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"integration-type": c.Type,
+		"source-service":   c.Source,
+		"dest-service":     c.Destination,
+	}
+	if len(settings) > 0 {
+		body["settings"] = settings
+	}
+
+	err = decorateAsyncOperation(fmt.Sprintf("Creating Database Services integration %q...", c.Type), func() error {
+		return dbIntegrationOperation(ctx, c.Zone, http.MethodPost, "/dbaas-integration", body)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !gQuiet {
+		integrations, err := listDBIntegrations(ctx, c.Zone)
+		if err != nil {
+			return err
+		}
+
+		out := make(dbIntegrationListOutput, 0)
+		for _, i := range integrations {
+			if i.Type == c.Type && i.Source == c.Source && i.Destination == c.Destination {
+				out = append(out, i)
+			}
+		}
+
+		return output(&out, nil)
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbIntegrationCmd, &dbIntegrationCreateCmd{}))
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbIntegrationDeleteCmd struct {
+	_ bool `cli-cmd:"delete"`
+
+	ID string `cli-arg:"#"`
+
+	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Zone  string `cli-short:"z" cli-usage:"Database Services zone"`
+}
+
+func (c *dbIntegrationDeleteCmd) cmdAliases() []string { return gRemoveAlias }
+
+func (c *dbIntegrationDeleteCmd) cmdShort() string { return "Delete a Database Services integration" }
+
+func (c *dbIntegrationDeleteCmd) cmdLong() string {
+	return `This command deletes an integration between Database Services.
+
+Deleting an integration used as a Grafana data source breaks the dashboards
+relying on it: a warning is displayed, but the deletion is not prevented.`
+}
+
+func (c *dbIntegrationDeleteCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbIntegrationDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	integrations, err := listDBIntegrations(ctx, c.Zone)
+	if err != nil {
+		return err
+	}
+
+	var integration *dbIntegrationListItemOutput
+	for i := range integrations {
+		if integrations[i].ID == c.ID {
+			integration = &integrations[i]
+			break
+		}
+	}
+	if integration == nil {
+		return fmt.Errorf("Database Services integration %q not found", c.ID) // nolint:golint
+	}
+
+	if dbIntegrationUsedByDashboards(integration) {
+		fmt.Fprintf(os.Stderr,
+			"warning: integration %s is a data source of the Grafana service %q, the dashboards using it will stop working\n",
+			c.ID, integration.Destination)
+	}
+
+	if !c.Force {
+		if !askQuestion(fmt.Sprintf("Are you sure you want to delete %s integration from %q to %q?",
+			integration.Type, integration.Source, integration.Destination)) {
+			return nil
+		}
+	}
+
+	return decorateAsyncOperation(fmt.Sprintf("Deleting Database Services integration %q...", c.ID), func() error {
+		return dbIntegrationOperation(ctx, c.Zone, http.MethodDelete, "/dbaas-integration/"+c.ID, nil)
+	})
+}
+
+// dbIntegrationUsedByDashboards returns true if the integration i feeds a
+// Grafana service, whose dashboards may rely on it.
+func dbIntegrationUsedByDashboards(i *dbIntegrationListItemOutput) bool {
+	return i.Type == "datasource" || i.DestinationType == "grafana"
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbIntegrationCmd, &dbIntegrationDeleteCmd{}))
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbIntegrationListItemOutput struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	Source          string `json:"source"`
+	SourceType      string `json:"source_type" output:"wide"`
+	Destination     string `json:"destination"`
+	DestinationType string `json:"destination_type" output:"wide"`
+	Status          string `json:"status"`
+	Description     string `json:"description" output:"wide"`
+}
+
+type dbIntegrationListOutput []dbIntegrationListItemOutput
+
+func (o *dbIntegrationListOutput) toJSON()  { outputJSON(o) }
+func (o *dbIntegrationListOutput) toText()  { outputText(o) }
+func (o *dbIntegrationListOutput) toTable() { outputTable(o) }
+
+type dbIntegrationListCmd struct {
+	_ bool `cli-cmd:"list"`
+
+	Service string `cli-usage:"list only the integrations of the specified Database Service"`
+	Zone    string `cli-short:"z" cli-usage:"Database Services zone"`
+}
+
+func (c *dbIntegrationListCmd) cmdAliases() []string { return gListAlias }
+
+func (c *dbIntegrationListCmd) cmdShort() string { return "List Database Services integrations" }
+
+func (c *dbIntegrationListCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists the integrations between the Database Services of a zone.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&dbIntegrationListItemOutput{}), ", "))
+}
+
+func (c *dbIntegrationListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbIntegrationListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	integrations, err := listDBIntegrations(ctx, c.Zone)
+	if err != nil {
+		return err
+	}
+
+	out := make(dbIntegrationListOutput, 0)
+	for _, i := range integrations {
+		if c.Service == "" || i.Source == c.Service || i.Destination == c.Service {
+			out = append(out, i)
+		}
+	}
+
+	return output(&out, nil)
+}
+
+// listDBIntegrations returns the integrations between the Database Services
+// of a zone, sorted by source service.
+func listDBIntegrations(ctx context.Context, zone string) ([]dbIntegrationListItemOutput, error) {
+	// The integrations are not exposed by the egoscale Database Service
+	// abstraction, so they are retrieved from the raw API response.
+	res, err := cs.ListDbaasServicesWithResponse(exoapi.WithZone(ctx, zone))
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, fmt.Errorf("API error: %s: %s", res.Status(), strings.TrimSpace(string(res.Body)))
+	}
+
+	list := make([]dbIntegrationListItemOutput, 0)
+	if res.JSON200.DbaasServices == nil {
+		return list, nil
+	}
+
+	// An integration is reported by both its source and destination
+	// services.
+	seen := make(map[string]bool)
+	for _, s := range *res.JSON200.DbaasServices {
+		if s.Integrations == nil {
+			continue
+		}
+
+		for _, i := range *s.Integrations {
+			if seen[i.ServiceIntegrationId] {
+				continue
+			}
+			seen[i.ServiceIntegrationId] = true
+
+			list = append(list, dbIntegrationListItemOutput{
+				ID:              i.ServiceIntegrationId,
+				Type:            i.IntegrationType,
+				Source:          i.SourceService,
+				SourceType:      string(i.SourceServiceType),
+				Destination:     i.DestService,
+				DestinationType: string(i.DestServiceType),
+				Status:          dbIntegrationStatus(i.Enabled, i.Active),
+				Description:     i.Description,
+			})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Source != list[j].Source {
+			return list[i].Source < list[j].Source
+		}
+		return list[i].ID < list[j].ID
+	})
+
+	return list, nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbIntegrationCmd, &dbIntegrationListCmd{}))
+}
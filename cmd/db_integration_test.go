@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_findDBIntegrationType(t *testing.T) {
+	types := []dbIntegrationType{
+		{Type: "metrics", SourceType: "pg", DestType: "grafana"},
+		{Type: "datasource", SourceType: "pg", DestType: "grafana"},
+		{Type: "logs", SourceType: "pg", DestType: "opensearch"},
+	}
+
+	it, err := findDBIntegrationType(types, "datasource", "pg", "grafana")
+	require.NoError(t, err)
+	require.Equal(t, "datasource", it.Type)
+
+	_, err = findDBIntegrationType(types, "logs", "pg", "grafana")
+	require.EqualError(t, err,
+		`unsupported integration type "logs" from pg to grafana Database Services (supported: datasource, metrics)`)
+
+	_, err = findDBIntegrationType(types, "metrics", "redis", "grafana")
+	require.EqualError(t, err, "no integration is supported from redis to grafana Database Services")
+}
+
+func Test_dbIntegrationStatus(t *testing.T) {
+	require.Equal(t, "disabled", dbIntegrationStatus(false, true))
+	require.Equal(t, "active", dbIntegrationStatus(true, true))
+	require.Equal(t, "pending", dbIntegrationStatus(true, false))
+}
+
+func TestIntegrationDBIntegrationCreate(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	out, code := runCLI(t, "lab", "database", "integration", "create",
+		"--type", "metrics", "--source", "my-pg", "--destination", "my-grafana",
+		"--setting", "retention_days=7", "-z", "ch-gva-2", "-O", "json")
+	require.Equal(t, 0, code)
+
+	server.request(http.MethodPost, "/dbaas-integration", 0)
+	require.JSONEq(t, `[{
+		"id": "9a8b7c6d-5e4f-4a3b-2c1d-0e9f8a7b6c5d",
+		"type": "metrics",
+		"source": "my-pg",
+		"source_type": "pg",
+		"destination": "my-grafana",
+		"destination_type": "grafana",
+		"status": "active",
+		"description": "Send service metrics to a Grafana service"
+	}]`, outputJSONLine(t, out))
+}
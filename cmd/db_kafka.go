@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var dbKafkaCmd = &cobra.Command{
+	Use:   "kafka",
+	Short: "Kafka Database Services management",
+}
+
+var dbKafkaACLCmd = &cobra.Command{
+	Use:   "acl",
+	Short: "Kafka Database Services ACL management",
+}
+
+func init() {
+	dbCmd.AddCommand(dbKafkaCmd)
+	dbKafkaCmd.AddCommand(dbKafkaACLCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbKafkaACLListItemOutput struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	Topic      string `json:"topic"`
+	Permission string `json:"permission"`
+}
+
+type dbKafkaACLListOutput []dbKafkaACLListItemOutput
+
+func (o *dbKafkaACLListOutput) toJSON()  { outputJSON(o) }
+func (o *dbKafkaACLListOutput) toText()  { outputText(o) }
+func (o *dbKafkaACLListOutput) toTable() { outputTable(o) }
+
+type dbKafkaACLListCmd struct {
+	_ bool `cli-cmd:"list"`
+
+	Name string `cli-arg:"#" cli-usage:"SERVICE-NAME"`
+
+	Zone string `cli-short:"z" cli-usage:"Database Service zone"`
+}
+
+func (c *dbKafkaACLListCmd) cmdAliases() []string { return gListAlias }
+
+func (c *dbKafkaACLListCmd) cmdShort() string { return "List Kafka Database Service ACL entries" }
+
+func (c *dbKafkaACLListCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists the ACL entries of a Kafka Database Service.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&dbKafkaACLListItemOutput{}), ", "))
+}
+
+func (c *dbKafkaACLListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbKafkaACLListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	// The ACL entries are not exposed by the egoscale Database Service
+	// abstraction, so they are retrieved from the raw API response.
+	res, err := cs.GetDbaasServiceWithResponse(ctx, c.Name)
+	if err != nil {
+		return err
+	}
+
+	if res.JSON200.Type != "kafka" {
+		return fmt.Errorf("%q is not a Kafka Database Service", c.Name)
+	}
+
+	out := make(dbKafkaACLListOutput, 0)
+	if res.JSON200.Acl != nil {
+		for _, acl := range *res.JSON200.Acl {
+			out = append(out, dbKafkaACLListItemOutput{
+				ID:         defaultString(acl.Id, ""),
+				Username:   acl.Username,
+				Topic:      acl.Topic,
+				Permission: string(acl.Permission),
+			})
+		}
+	}
+
+	return output(&out, nil)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbKafkaACLCmd, &dbKafkaACLListCmd{}))
+}
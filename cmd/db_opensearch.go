@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var dbOpenSearchCmd = &cobra.Command{
+	Use:   "opensearch",
+	Short: "OpenSearch Database Services management",
+}
+
+var dbOpenSearchSettingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "OpenSearch Database Services index settings management",
+}
+
+func init() {
+	dbCmd.AddCommand(dbOpenSearchCmd)
+	dbOpenSearchCmd.AddCommand(dbOpenSearchSettingsCmd)
+}
+
+const (
+	dbOpenSearchMaxIndexCount = "max_index_count"
+	dbOpenSearchIndexPatterns = "index_patterns"
+)
+
+// dbOpenSearchIndexPattern represents an OpenSearch Database Service index
+// pattern retention rule.
+type dbOpenSearchIndexPattern struct {
+	Pattern       string `json:"pattern"`
+	MaxIndexCount int64  `json:"max_index_count"`
+}
+
+// parseDBOpenSearchIndexPattern parses an index pattern retention rule
+// formatted as "PATTERN:MAX-INDEX-COUNT" (e.g. "logs-*:7").
+func parseDBOpenSearchIndexPattern(v string) (*dbOpenSearchIndexPattern, error) {
+	i := strings.LastIndex(v, ":")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid index pattern %q: expected format PATTERN:MAX-INDEX-COUNT", v)
+	}
+
+	count, err := strconv.ParseInt(v[i+1:], 10, 64)
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid index pattern %q: max index count must be a positive integer", v)
+	}
+
+	return &dbOpenSearchIndexPattern{Pattern: v[:i], MaxIndexCount: count}, nil
+}
+
+// dbOpenSearchIndexPatternsFromUserConfig returns the index pattern rules
+// set in an OpenSearch Database Service user config.
+func dbOpenSearchIndexPatternsFromUserConfig(userConfig map[string]interface{}) []dbOpenSearchIndexPattern {
+	patterns := make([]dbOpenSearchIndexPattern, 0)
+
+	list, _ := userConfig[dbOpenSearchIndexPatterns].([]interface{})
+	for _, v := range list {
+		item, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		pattern, _ := item["pattern"].(string)
+		count, _ := item[dbOpenSearchMaxIndexCount].(float64)
+		patterns = append(patterns, dbOpenSearchIndexPattern{Pattern: pattern, MaxIndexCount: int64(count)})
+	}
+
+	return patterns
+}
+
+// dbOpenSearchUpdateIndexPatterns returns the index pattern rules of an
+// OpenSearch Database Service user config (as found in the "index_patterns"
+// setting) after setting and removing the specified rules, and whether the
+// rules have been modified. Existing rules properties not managed by the CLI
+// (e.g. "sorting_algorithm") are preserved.
+func dbOpenSearchUpdateIndexPatterns(
+	current []interface{},
+	set []*dbOpenSearchIndexPattern,
+	remove []string,
+) ([]interface{}, bool, error) {
+	updated := make([]interface{}, 0, len(current))
+	changed := false
+
+	toRemove := make(map[string]bool)
+	for _, p := range remove {
+		toRemove[p] = true
+	}
+
+	for _, v := range current {
+		item, ok := v.(map[string]interface{})
+		if !ok {
+			updated = append(updated, v)
+			continue
+		}
+
+		pattern, _ := item["pattern"].(string)
+		if toRemove[pattern] {
+			delete(toRemove, pattern)
+			changed = true
+			continue
+		}
+
+		updated = append(updated, item)
+	}
+
+	if len(toRemove) > 0 {
+		missing := make([]string, 0, len(toRemove))
+		for p := range toRemove {
+			missing = append(missing, p)
+		}
+		sort.Strings(missing)
+		return nil, false, fmt.Errorf("index pattern(s) not found: %s", strings.Join(missing, ", "))
+	}
+
+	for _, p := range set {
+		found := false
+		for i, v := range updated {
+			item, ok := v.(map[string]interface{})
+			if !ok || item["pattern"] != p.Pattern {
+				continue
+			}
+
+			found = true
+			if count, _ := item[dbOpenSearchMaxIndexCount].(float64); int64(count) != p.MaxIndexCount {
+				patched := make(map[string]interface{}, len(item))
+				for k, v := range item {
+					patched[k] = v
+				}
+				patched[dbOpenSearchMaxIndexCount] = p.MaxIndexCount
+				updated[i] = patched
+				changed = true
+			}
+		}
+
+		if !found {
+			updated = append(updated, map[string]interface{}{
+				"pattern":                 p.Pattern,
+				dbOpenSearchMaxIndexCount: p.MaxIndexCount,
+			})
+			changed = true
+		}
+	}
+
+	return updated, changed, nil
+}
+
+// dbServiceComponent represents a Database Service component endpoint.
+type dbServiceComponent struct {
+	Component string
+	Host      string
+	Port      int64
+	Path      string
+	Route     string
+	SSL       bool
+}
+
+// dbOpenSearchDashboardURL returns the URL of the OpenSearch Dashboards
+// endpoint from a Database Service components list.
+func dbOpenSearchDashboardURL(components []dbServiceComponent) (string, error) {
+	for _, c := range components {
+		if c.Component != "opensearch_dashboards" || c.Route != "dynamic" {
+			continue
+		}
+
+		u := url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("%s:%d", c.Host, c.Port),
+			Path:   c.Path,
+		}
+		if !c.SSL {
+			u.Scheme = "http"
+		}
+
+		return u.String(), nil
+	}
+
+	return "", errors.New("no OpenSearch Dashboards endpoint found")
+}
+
+// openBrowser opens the specified URL in the user's default web browser.
+func openBrowser(u string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+
+	return cmd.Start()
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbOpenSearchDashboardURLCmd struct {
+	_ bool `cli-cmd:"dashboard-url"`
+
+	Name string `cli-arg:"#" cli-usage:"SERVICE-NAME"`
+
+	Open bool   `cli-usage:"open the OpenSearch Dashboards in the default web browser"`
+	Zone string `cli-short:"z" cli-usage:"Database Service zone"`
+}
+
+func (c *dbOpenSearchDashboardURLCmd) cmdAliases() []string { return nil }
+
+func (c *dbOpenSearchDashboardURLCmd) cmdShort() string {
+	return "Print an OpenSearch Database Service Dashboards URL"
+}
+
+func (c *dbOpenSearchDashboardURLCmd) cmdLong() string {
+	return `This command prints the URL of the OpenSearch Dashboards endpoint of an
+OpenSearch Database Service. The "--open" flag opens the URL in the default
+web browser.`
+}
+
+func (c *dbOpenSearchDashboardURLCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbOpenSearchDashboardURLCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	// The service components are not exposed by the egoscale Database Service
+	// abstraction, so they are retrieved from the raw API response.
+	res, err := cs.GetDbaasServiceWithResponse(ctx, c.Name)
+	if err != nil {
+		return err
+	}
+
+	if res.JSON200.Type != "opensearch" {
+		return fmt.Errorf("%q is not an OpenSearch Database Service", c.Name)
+	}
+
+	components := make([]dbServiceComponent, 0)
+	if res.JSON200.Components != nil {
+		for _, v := range *res.JSON200.Components {
+			components = append(components, dbServiceComponent{
+				Component: v.Component,
+				Host:      v.Host,
+				Port:      v.Port,
+				Path:      defaultString(v.Path, ""),
+				Route:     string(v.Route),
+				SSL:       defaultBool(v.Ssl, true),
+			})
+		}
+	}
+
+	u, err := dbOpenSearchDashboardURL(components)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(u)
+
+	if c.Open {
+		if err := openBrowser(u); err != nil {
+			return fmt.Errorf("unable to open web browser: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbOpenSearchCmd, &dbOpenSearchDashboardURLCmd{}))
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/exoscale/cli/table"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbOpenSearchSettingsShowOutput struct {
+	MaxIndexCount int64                      `json:"max_index_count"`
+	IndexPatterns []dbOpenSearchIndexPattern `json:"index_patterns"`
+}
+
+func (o *dbOpenSearchSettingsShowOutput) toJSON() { outputJSON(o) }
+func (o *dbOpenSearchSettingsShowOutput) toText() { outputText(o) }
+func (o *dbOpenSearchSettingsShowOutput) toTable() {
+	t := table.NewTable(os.Stdout)
+	defer t.Render()
+
+	t.SetHeader([]string{"OpenSearch Settings"})
+
+	t.Append([]string{"Max Index Count", func() string {
+		if o.MaxIndexCount == 0 {
+			return "unlimited"
+		}
+		return fmt.Sprint(o.MaxIndexCount)
+	}()})
+
+	t.Append([]string{"Index Patterns", func() string {
+		if len(o.IndexPatterns) == 0 {
+			return "n/a"
+		}
+
+		patterns := make([]string, len(o.IndexPatterns))
+		for i, p := range o.IndexPatterns {
+			patterns[i] = fmt.Sprintf("%s: %d", p.Pattern, p.MaxIndexCount)
+		}
+		return strings.Join(patterns, "\n")
+	}()})
+}
+
+type dbOpenSearchSettingsShowCmd struct {
+	_ bool `cli-cmd:"show"`
+
+	Name string `cli-arg:"#" cli-usage:"SERVICE-NAME"`
+
+	Zone string `cli-short:"z" cli-usage:"Database Service zone"`
+}
+
+func (c *dbOpenSearchSettingsShowCmd) cmdAliases() []string { return gShowAlias }
+
+func (c *dbOpenSearchSettingsShowCmd) cmdShort() string {
+	return "Show OpenSearch Database Service index settings"
+}
+
+func (c *dbOpenSearchSettingsShowCmd) cmdLong() string {
+	return fmt.Sprintf(`This command shows the index retention settings of an OpenSearch
+Database Service: the maximum number of indices kept (0 meaning unlimited),
+and the per index pattern maximum number of indices kept.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&dbOpenSearchSettingsShowOutput{}), ", "))
+}
+
+func (c *dbOpenSearchSettingsShowCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbOpenSearchSettingsShowCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	return output(showDBOpenSearchSettings(c.Zone, c.Name))
+}
+
+func showDBOpenSearchSettings(zone, name string) (outputter, error) {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+	databaseService, err := cs.GetDatabaseService(ctx, zone, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if *databaseService.Type != "opensearch" {
+		return nil, fmt.Errorf("%q is not an OpenSearch Database Service", name)
+	}
+
+	userConfig := make(map[string]interface{})
+	if databaseService.UserConfig != nil {
+		userConfig = *databaseService.UserConfig
+	}
+
+	out := dbOpenSearchSettingsShowOutput{IndexPatterns: dbOpenSearchIndexPatternsFromUserConfig(userConfig)}
+	if v, ok := userConfig[dbOpenSearchMaxIndexCount].(float64); ok {
+		out.MaxIndexCount = int64(v)
+	}
+
+	return &out, nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbOpenSearchSettingsCmd, &dbOpenSearchSettingsShowCmd{}))
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbOpenSearchSettingsUpdateCmd struct {
+	_ bool `cli-cmd:"update"`
+
+	Name string `cli-arg:"#" cli-usage:"SERVICE-NAME"`
+
+	MaxIndexCount  int64    `cli-usage:"maximum number of indices to keep (0: unlimited)"`
+	Patterns       []string `cli-flag:"pattern" cli-usage:"index pattern retention rule (format: PATTERN:MAX-INDEX-COUNT, can be specified multiple times)"`
+	RemovePatterns []string `cli-flag:"remove-pattern" cli-usage:"index pattern retention rule to remove (can be specified multiple times)"`
+	Zone           string   `cli-short:"z" cli-usage:"Database Service zone"`
+}
+
+func (c *dbOpenSearchSettingsUpdateCmd) cmdAliases() []string { return nil }
+
+func (c *dbOpenSearchSettingsUpdateCmd) cmdShort() string {
+	return "Update OpenSearch Database Service index settings"
+}
+
+func (c *dbOpenSearchSettingsUpdateCmd) cmdLong() string {
+	return fmt.Sprintf(`This command updates the index retention settings of an OpenSearch
+Database Service. Index pattern retention rules are set using the "--pattern"
+flag, e.g. "--pattern 'logs-*:7'" keeps at most 7 indices matching the
+"logs-*" pattern. Existing rules for other patterns are left untouched.
+
+Only the settings actually modified are sent to the API.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&dbOpenSearchSettingsShowOutput{}), ", "))
+}
+
+func (c *dbOpenSearchSettingsUpdateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbOpenSearchSettingsUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	patterns := make([]*dbOpenSearchIndexPattern, len(c.Patterns))
+	for i, v := range c.Patterns {
+		p, err := parseDBOpenSearchIndexPattern(v)
+		if err != nil {
+			return err
+		}
+		patterns[i] = p
+	}
+
+	if c.MaxIndexCount < 0 {
+		return fmt.Errorf("invalid max index count %d", c.MaxIndexCount)
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	databaseService, err := cs.GetDatabaseService(ctx, c.Zone, c.Name)
+	if err != nil {
+		return err
+	}
+
+	if *databaseService.Type != "opensearch" {
+		return fmt.Errorf("%q is not an OpenSearch Database Service", c.Name)
+	}
+
+	current := make(map[string]interface{})
+	if databaseService.UserConfig != nil {
+		current = *databaseService.UserConfig
+	}
+
+	// Only the modified settings are sent to the API.
+	changes := make(map[string]interface{})
+
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.MaxIndexCount)) {
+		if v, _ := current[dbOpenSearchMaxIndexCount].(float64); int64(v) != c.MaxIndexCount {
+			changes[dbOpenSearchMaxIndexCount] = c.MaxIndexCount
+		}
+	}
+
+	if len(patterns) > 0 || len(c.RemovePatterns) > 0 {
+		list, _ := current[dbOpenSearchIndexPatterns].([]interface{})
+		updated, changed, err := dbOpenSearchUpdateIndexPatterns(list, patterns, c.RemovePatterns)
+		if err != nil {
+			return err
+		}
+		if changed {
+			changes[dbOpenSearchIndexPatterns] = updated
+		}
+	}
+
+	if len(changes) > 0 {
+		err = decorateAsyncOperation(fmt.Sprintf("Updating Database Service %q...", c.Name), func() error {
+			return cs.UpdateDatabaseService(ctx, c.Zone, &egoscale.DatabaseService{
+				Name:       databaseService.Name,
+				UserConfig: &changes,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !gQuiet {
+		return output(showDBOpenSearchSettings(c.Zone, c.Name))
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbOpenSearchSettingsCmd, &dbOpenSearchSettingsUpdateCmd{}))
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseDBOpenSearchIndexPattern(t *testing.T) {
+	p, err := parseDBOpenSearchIndexPattern("logs-*:7")
+	require.NoError(t, err)
+	require.Equal(t, &dbOpenSearchIndexPattern{Pattern: "logs-*", MaxIndexCount: 7}, p)
+
+	p, err = parseDBOpenSearchIndexPattern("a:b:0")
+	require.NoError(t, err)
+	require.Equal(t, &dbOpenSearchIndexPattern{Pattern: "a:b", MaxIndexCount: 0}, p)
+
+	for _, v := range []string{"logs-*", ":7", "logs-*:", "logs-*:-1", "logs-*:lots"} {
+		_, err = parseDBOpenSearchIndexPattern(v)
+		require.Error(t, err, v)
+	}
+}
+
+func Test_dbOpenSearchUpdateIndexPatterns(t *testing.T) {
+	current := []interface{}{
+		map[string]interface{}{"pattern": "logs-*", "max_index_count": float64(7), "sorting_algorithm": "creation_date"},
+		map[string]interface{}{"pattern": "metrics-*", "max_index_count": float64(3)},
+	}
+
+	updated, changed, err := dbOpenSearchUpdateIndexPatterns(current, []*dbOpenSearchIndexPattern{
+		{Pattern: "logs-*", MaxIndexCount: 7},
+	}, nil)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, current, updated)
+
+	updated, changed, err = dbOpenSearchUpdateIndexPatterns(current, []*dbOpenSearchIndexPattern{
+		{Pattern: "logs-*", MaxIndexCount: 14},
+		{Pattern: "traces-*", MaxIndexCount: 1},
+	}, []string{"metrics-*"})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"pattern": "logs-*", "max_index_count": int64(14), "sorting_algorithm": "creation_date"},
+		map[string]interface{}{"pattern": "traces-*", "max_index_count": int64(1)},
+	}, updated)
+	require.Equal(t, float64(7), current[0].(map[string]interface{})["max_index_count"])
+
+	_, _, err = dbOpenSearchUpdateIndexPatterns(current, nil, []string{"nope-*"})
+	require.EqualError(t, err, "index pattern(s) not found: nope-*")
+}
+
+func Test_dbOpenSearchDashboardURL(t *testing.T) {
+	u, err := dbOpenSearchDashboardURL([]dbServiceComponent{
+		{Component: "opensearch", Host: "os.example.net", Port: 21699, Route: "dynamic", SSL: true},
+		{Component: "opensearch_dashboards", Host: "os.example.net", Port: 443, Route: "dynamic", SSL: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://os.example.net:443", u)
+
+	_, err = dbOpenSearchDashboardURL([]dbServiceComponent{
+		{Component: "opensearch", Host: "os.example.net", Port: 21699, Route: "dynamic", SSL: true},
+	})
+	require.Error(t, err)
+}
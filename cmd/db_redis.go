@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+var dbRedisCmd = &cobra.Command{
+	Use:   "redis",
+	Short: "Redis Database Services management",
+}
+
+var dbRedisSettingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Redis Database Services settings management",
+}
+
+var dbRedisUserCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Redis Database Services users management",
+}
+
+func init() {
+	dbCmd.AddCommand(dbRedisCmd)
+	dbRedisCmd.AddCommand(dbRedisSettingsCmd)
+	dbRedisCmd.AddCommand(dbRedisUserCmd)
+}
+
+const (
+	dbRedisMaxMemoryPolicy = "redis_maxmemory_policy"
+	dbRedisPersistence     = "redis_persistence"
+)
+
+// dbRedisEndpoint returns the endpoint (HOST:PORT) of a Redis Database
+// Service from its connection URI, and whether TLS is required to connect
+// to it (i.e. if the URI scheme is "rediss").
+func dbRedisEndpoint(u *url.URL) (string, bool) {
+	if u == nil {
+		return "", false
+	}
+
+	return u.Host, u.Scheme == "rediss"
+}
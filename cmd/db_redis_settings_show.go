@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbRedisSettingsShowOutput struct {
+	MaxMemoryPolicy string `json:"maxmemory_policy" outputLabel:"Max Memory Policy"`
+	Persistence     string `json:"persistence"`
+}
+
+func (o *dbRedisSettingsShowOutput) Type() string { return "Redis Settings" }
+func (o *dbRedisSettingsShowOutput) toJSON()      { outputJSON(o) }
+func (o *dbRedisSettingsShowOutput) toText()      { outputText(o) }
+func (o *dbRedisSettingsShowOutput) toTable()     { outputTable(o) }
+
+type dbRedisSettingsShowCmd struct {
+	_ bool `cli-cmd:"show"`
+
+	Name string `cli-arg:"#" cli-usage:"SERVICE-NAME"`
+
+	Zone string `cli-short:"z" cli-usage:"Database Service zone"`
+}
+
+func (c *dbRedisSettingsShowCmd) cmdAliases() []string { return gShowAlias }
+
+func (c *dbRedisSettingsShowCmd) cmdShort() string {
+	return "Show Redis Database Service settings"
+}
+
+func (c *dbRedisSettingsShowCmd) cmdLong() string {
+	return fmt.Sprintf(`This command shows the eviction policy and the persistence settings of a
+Redis Database Service, the Database Service type default values being
+reported for the settings not set explicitly.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&dbRedisSettingsShowOutput{}), ", "))
+}
+
+func (c *dbRedisSettingsShowCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbRedisSettingsShowCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	return output(showDBRedisSettings(c.Zone, c.Name))
+}
+
+func showDBRedisSettings(zone, name string) (outputter, error) {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+	databaseService, err := cs.GetDatabaseService(ctx, zone, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if *databaseService.Type != "redis" {
+		return nil, fmt.Errorf("%q is not a Redis Database Service", name)
+	}
+
+	databaseServiceType, err := cs.GetDatabaseServiceType(ctx, zone, *databaseService.Type)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving Database Service type: %s", err)
+	}
+
+	settings := make(map[string]string)
+	for _, s := range dbSettingsFromSchema(databaseServiceType.UserConfigSchema) {
+		settings[s.Name] = s.Default
+	}
+	if databaseService.UserConfig != nil {
+		for _, k := range []string{dbRedisMaxMemoryPolicy, dbRedisPersistence} {
+			if v, ok := (*databaseService.UserConfig)[k]; ok && v != nil {
+				settings[k] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	return &dbRedisSettingsShowOutput{
+		MaxMemoryPolicy: settings[dbRedisMaxMemoryPolicy],
+		Persistence:     settings[dbRedisPersistence],
+	}, nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbRedisSettingsCmd, &dbRedisSettingsShowCmd{}))
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbRedisSettingsUpdateCmd struct {
+	_ bool `cli-cmd:"update"`
+
+	Name string `cli-arg:"#" cli-usage:"SERVICE-NAME"`
+
+	MaxMemoryPolicy string `cli-flag:"maxmemory-policy" cli-usage:"keys eviction policy when the maximum memory is reached (e.g. allkeys-lru, noeviction)"`
+	Persistence     string `cli-usage:"persistence mode (rdb|off)"`
+	Zone            string `cli-short:"z" cli-usage:"Database Service zone"`
+}
+
+func (c *dbRedisSettingsUpdateCmd) cmdAliases() []string { return nil }
+
+func (c *dbRedisSettingsUpdateCmd) cmdShort() string {
+	return "Update Redis Database Service settings"
+}
+
+func (c *dbRedisSettingsUpdateCmd) cmdLong() string {
+	return fmt.Sprintf(`This command updates the eviction policy and the persistence settings of a
+Redis Database Service. The values are validated against the Redis Database
+Service type settings before submission, which can be listed using the
+"exo lab database types show --settings redis -O wide" command.
+
+Only the settings actually modified are sent to the API.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&dbRedisSettingsShowOutput{}), ", "))
+}
+
+func (c *dbRedisSettingsUpdateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbRedisSettingsUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	databaseService, err := cs.GetDatabaseService(ctx, c.Zone, c.Name)
+	if err != nil {
+		return err
+	}
+
+	if *databaseService.Type != "redis" {
+		return fmt.Errorf("%q is not a Redis Database Service", c.Name)
+	}
+
+	current := make(map[string]interface{})
+	if databaseService.UserConfig != nil {
+		current = *databaseService.UserConfig
+	}
+
+	settings := make(map[string]string)
+	for flag, setting := range map[*string]string{
+		&c.MaxMemoryPolicy: dbRedisMaxMemoryPolicy,
+		&c.Persistence:     dbRedisPersistence,
+	} {
+		if cmd.Flags().Changed(mustCLICommandFlagName(c, flag)) && fmt.Sprint(current[setting]) != *flag {
+			settings[setting] = *flag
+		}
+	}
+
+	if len(settings) > 0 {
+		databaseServiceType, err := cs.GetDatabaseServiceType(ctx, c.Zone, *databaseService.Type)
+		if err != nil {
+			return fmt.Errorf("error retrieving Database Service type: %s", err)
+		}
+
+		// Only the modified settings are sent to the API.
+		changes := make(map[string]interface{})
+		if err := dbApplySettings(databaseServiceType.UserConfigSchema, settings, changes); err != nil {
+			return err
+		}
+
+		err = decorateAsyncOperation(fmt.Sprintf("Updating Database Service %q...", c.Name), func() error {
+			return cs.UpdateDatabaseService(ctx, c.Zone, &egoscale.DatabaseService{
+				Name:       databaseService.Name,
+				UserConfig: &changes,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !gQuiet {
+		return output(showDBRedisSettings(c.Zone, c.Name))
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbRedisSettingsCmd, &dbRedisSettingsUpdateCmd{}))
+}
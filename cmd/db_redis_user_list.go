@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type dbRedisUserListItemOutput struct {
+	Username   string   `json:"username"`
+	Type       string   `json:"type"`
+	Categories []string `json:"categories"`
+	Commands   []string `json:"commands"`
+	Keys       []string `json:"keys"`
+}
+
+type dbRedisUserListOutput []dbRedisUserListItemOutput
+
+func (o *dbRedisUserListOutput) toJSON()  { outputJSON(o) }
+func (o *dbRedisUserListOutput) toText()  { outputText(o) }
+func (o *dbRedisUserListOutput) toTable() { outputTable(o) }
+
+type dbRedisUserListCmd struct {
+	_ bool `cli-cmd:"list"`
+
+	Name string `cli-arg:"#" cli-usage:"SERVICE-NAME"`
+
+	Zone string `cli-short:"z" cli-usage:"Database Service zone"`
+}
+
+func (c *dbRedisUserListCmd) cmdAliases() []string { return gListAlias }
+
+func (c *dbRedisUserListCmd) cmdShort() string { return "List Redis Database Service users" }
+
+func (c *dbRedisUserListCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists the users of a Redis Database Service, along with their
+ACL rules: the command categories (e.g. "+@read"), the individual commands
+(e.g. "-flushall") and the keys patterns they are allowed to access.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&dbRedisUserListItemOutput{}), ", "))
+}
+
+func (c *dbRedisUserListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *dbRedisUserListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	// The users access control rules are not exposed by the egoscale
+	// Database Service abstraction, so they are retrieved from the raw API
+	// response.
+	res, err := cs.GetDbaasServiceWithResponse(ctx, c.Name)
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil {
+		return fmt.Errorf("API error: %s: %s", res.Status(), strings.TrimSpace(string(res.Body)))
+	}
+
+	if res.JSON200.Type != "redis" {
+		return fmt.Errorf("%q is not a Redis Database Service", c.Name)
+	}
+
+	list := func(v *[]string) []string {
+		if v == nil {
+			return []string{}
+		}
+		return *v
+	}
+
+	out := make(dbRedisUserListOutput, 0)
+	if res.JSON200.Users != nil {
+		for _, u := range *res.JSON200.Users {
+			item := dbRedisUserListItemOutput{
+				Username:   u.Username,
+				Type:       u.Type,
+				Categories: []string{},
+				Commands:   []string{},
+				Keys:       []string{},
+			}
+			if u.AccessControl != nil {
+				item.Categories = list(u.AccessControl.RedisAclCategories)
+				item.Commands = list(u.AccessControl.RedisAclCommands)
+				item.Keys = list(u.AccessControl.RedisAclKeys)
+			}
+			out = append(out, item)
+		}
+	}
+
+	return output(&out, nil)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(dbRedisUserCmd, &dbRedisUserListCmd{}))
+}
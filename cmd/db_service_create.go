@@ -16,11 +16,12 @@ type dbServiceCreateCmd struct {
 	Plan string `cli-arg:"#"`
 	Name string `cli-arg:"#"`
 
-	MaintenanceDOW        string `cli-flag:"maintenance-dow" cli-usage:"automated Database Service maintenance day-of-week"`
-	MaintenanceTime       string `cli-usage:"automated Database Service maintenance time (format HH:MM)"`
-	TerminationProtection bool   `cli-usage:"enable Database Service termination protection"`
-	UserConfigFile        string `cli-flag:"user-config" cli-short:"c" cli-usage:"path to JSON user config file"`
-	Zone                  string `cli-short:"z" cli-usage:"Database Service zone"`
+	MaintenanceDOW        string            `cli-flag:"maintenance-dow" cli-usage:"automated Database Service maintenance day-of-week"`
+	MaintenanceTime       string            `cli-usage:"automated Database Service maintenance time (format HH:MM)"`
+	Settings              map[string]string `cli-flag:"setting" cli-usage:"Database Service setting (format: name=value, can be specified multiple times)"`
+	TerminationProtection bool              `cli-usage:"enable Database Service termination protection"`
+	UserConfigFile        string            `cli-flag:"user-config" cli-short:"c" cli-usage:"path to JSON user config file"`
+	Zone                  string            `cli-short:"z" cli-usage:"Database Service zone"`
 }
 
 func (c *dbServiceCreateCmd) cmdAliases() []string { return gCreateAlias }
@@ -32,6 +33,12 @@ func (c *dbServiceCreateCmd) cmdLong() string {
 
 Supported values for --maintenance-dow: %s
 
+The settings supported by a Database Service type can be listed using the
+"exo lab database types show --settings TYPE" command. Settings specified
+using the "--setting" flag are validated against the Database Service type
+settings before submission, and are applied on top of the user config
+provided with the "--user-config" flag if any.
+
 Supported output template annotations: %s`,
 		strings.Join(dbServiceMaintenanceDOWs, ", "),
 		strings.Join(outputterTemplateAnnotations(&dbServiceShowOutput{}), ", "))
@@ -69,8 +76,28 @@ func (c *dbServiceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		databaseService.UserConfig = &userConfig
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Creating Database Service %q...", *databaseService.Name), func() {
+	if len(c.Settings) > 0 {
+		databaseServiceType, err := cs.GetDatabaseServiceType(ctx, c.Zone, c.Type)
+		if err != nil {
+			return fmt.Errorf("error retrieving Database Service type: %s", err)
+		}
+
+		if databaseService.UserConfig == nil {
+			databaseService.UserConfig = &map[string]interface{}{}
+		}
+
+		if err := dbApplySettings(
+			databaseServiceType.UserConfigSchema,
+			c.Settings,
+			*databaseService.UserConfig,
+		); err != nil {
+			return err
+		}
+	}
+
+	err = decorateAsyncOperation(fmt.Sprintf("Creating Database Service %q...", *databaseService.Name), func() error {
 		databaseService, err = cs.CreateDatabaseService(ctx, c.Zone, databaseService)
+		return err
 	})
 	if err != nil {
 		return err
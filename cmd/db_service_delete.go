@@ -37,8 +37,8 @@ func (c *dbServiceDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	}
 
 	var err error
-	decorateAsyncOperation(fmt.Sprintf("Deleting Database Service %q...", c.Name), func() {
-		err = cs.DeleteDatabaseService(ctx, c.Zone, c.Name)
+	err = decorateAsyncOperation(fmt.Sprintf("Deleting Database Service %q...", c.Name), func() error {
+		return cs.DeleteDatabaseService(ctx, c.Zone, c.Name)
 	})
 	if err != nil {
 		return err
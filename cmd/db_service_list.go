@@ -56,12 +56,13 @@ func (c *dbServiceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	out := make(dbServiceListOutput, 0)
 	res := make(chan dbServiceListItemOutput)
-	defer close(res)
+	done := make(chan struct{})
 
 	go func() {
 		for dbService := range res {
 			out = append(out, dbService)
 		}
+		close(done)
 	}()
 	err := forEachZone(zones, func(zone string) error {
 		list, err := cs.ListDatabaseServices(ctx, zone)
@@ -80,6 +81,8 @@ func (c *dbServiceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 		return nil
 	})
+	close(res)
+	<-done
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr,
 			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
@@ -53,6 +53,8 @@ type dbServiceShowOutput struct {
 	Users                 []dbServiceUserShowOutput       `json:"users"`
 	Features              map[string]interface{}          `json:"features"`
 	Metadata              map[string]interface{}          `json:"metadata"`
+	Endpoint              string                          `json:"endpoint,omitempty"`
+	TLSRequired           *bool                           `json:"tls_required,omitempty"`
 	Zone                  string                          `json:"zone"`
 }
 
@@ -75,6 +77,13 @@ func (o *dbServiceShowOutput) toTable() {
 	t.Append([]string{"State", o.State})
 	t.Append([]string{"Termination Protected", fmt.Sprint(o.TerminationProtection)})
 
+	if o.Endpoint != "" {
+		t.Append([]string{"Endpoint", o.Endpoint})
+	}
+	if o.TLSRequired != nil {
+		t.Append([]string{"TLS Required", fmt.Sprint(*o.TLSRequired)})
+	}
+
 	t.Append([]string{"Maintenance", func() string {
 		if o.Maintenance != nil {
 			return fmt.Sprintf("%s (%s)", o.Maintenance.DOW, o.Maintenance.Time)
@@ -253,6 +262,11 @@ func showDatabaseService(zone, name string) (outputter, error) {
 		Zone: zone,
 	}
 
+	if out.Type == "redis" {
+		endpoint, tls := dbRedisEndpoint(databaseService.URI)
+		out.Endpoint, out.TLSRequired = endpoint, &tls
+	}
+
 	return &out, nil
 }
 
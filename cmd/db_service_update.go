@@ -14,12 +14,13 @@ type dbServiceUpdateCmd struct {
 
 	Name string `cli-arg:"#"`
 
-	MaintenanceDOW        string `cli-flag:"maintenance-dow" cli-usage:"automated Database Service maintenance day-of-week"`
-	MaintenanceTime       string `cli-usage:"automated Database Service maintenance time (format HH:MM:SS)"`
-	Plan                  string `cli-usage:"Database Service plan"`
-	TerminationProtection bool   `cli-usage:"enable Database Service termination protection"`
-	UserConfigFile        string `cli-flag:"user-config" cli-short:"c" cli-usage:"path to JSON user config file"`
-	Zone                  string `cli-short:"z" cli-usage:"Database Service zone"`
+	MaintenanceDOW        string            `cli-flag:"maintenance-dow" cli-usage:"automated Database Service maintenance day-of-week"`
+	MaintenanceTime       string            `cli-usage:"automated Database Service maintenance time (format HH:MM:SS)"`
+	Plan                  string            `cli-usage:"Database Service plan"`
+	Settings              map[string]string `cli-flag:"setting" cli-usage:"Database Service setting (format: name=value, can be specified multiple times)"`
+	TerminationProtection bool              `cli-usage:"enable Database Service termination protection"`
+	UserConfigFile        string            `cli-flag:"user-config" cli-short:"c" cli-usage:"path to JSON user config file"`
+	Zone                  string            `cli-short:"z" cli-usage:"Database Service zone"`
 }
 
 func (c *dbServiceUpdateCmd) cmdAliases() []string { return nil }
@@ -31,6 +32,12 @@ func (c *dbServiceUpdateCmd) cmdLong() string {
 
 Supported values for --maintenance-dow: %s
 
+The settings supported by a Database Service type can be listed using the
+"exo lab database types show --settings TYPE" command. Settings specified
+using the "--setting" flag are validated against the Database Service type
+settings before submission, and are applied on top of the user config
+provided with the "--user-config" flag if any.
+
 Supported output template annotations: %s`,
 		strings.Join(dbServiceMaintenanceDOWs, ", "),
 		strings.Join(outputterTemplateAnnotations(&dbServiceShowOutput{}), ", "),
@@ -71,6 +78,26 @@ func (c *dbServiceUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		updated = true
 	}
 
+	if len(c.Settings) > 0 {
+		databaseServiceType, err := cs.GetDatabaseServiceType(ctx, c.Zone, *databaseService.Type)
+		if err != nil {
+			return fmt.Errorf("error retrieving Database Service type: %s", err)
+		}
+
+		if databaseService.UserConfig == nil {
+			databaseService.UserConfig = &map[string]interface{}{}
+		}
+
+		if err := dbApplySettings(
+			databaseServiceType.UserConfigSchema,
+			c.Settings,
+			*databaseService.UserConfig,
+		); err != nil {
+			return err
+		}
+		updated = true
+	}
+
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.MaintenanceDOW)) &&
 		cmd.Flags().Changed(mustCLICommandFlagName(c, &c.MaintenanceTime)) {
 		databaseService.Maintenance = &egoscale.DatabaseServiceMaintenance{
@@ -80,12 +107,11 @@ func (c *dbServiceUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		updated = true
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Updating Database Service %q...", *databaseService.Name), func() {
+	err = decorateAsyncOperation(fmt.Sprintf("Updating Database Service %q...", *databaseService.Name), func() error {
 		if updated {
-			if err = cs.UpdateDatabaseService(ctx, c.Zone, databaseService); err != nil {
-				return
-			}
+			return cs.UpdateDatabaseService(ctx, c.Zone, databaseService)
 		}
+		return nil
 	})
 	if err != nil {
 		return err
@@ -59,10 +59,18 @@ func (o *dbTypeShowOutput) toTable() {
 	}()})
 }
 
+type dbTypeSettingsOutput []dbSetting
+
+func (o *dbTypeSettingsOutput) toJSON()  { outputJSON(o) }
+func (o *dbTypeSettingsOutput) toText()  { outputText(o) }
+func (o *dbTypeSettingsOutput) toTable() { outputTable(o) }
+
 type dbTypeShowCmd struct {
 	_ bool `cli-cmd:"show"`
 
 	Name string `cli-arg:"#"`
+
+	ShowSettings bool `cli-flag:"settings" cli-usage:"show the Database Service type supported settings"`
 }
 
 func (c *dbTypeShowCmd) cmdAliases() []string { return gShowAlias }
@@ -72,8 +80,15 @@ func (c *dbTypeShowCmd) cmdShort() string { return "Show a Database Service type
 func (c *dbTypeShowCmd) cmdLong() string {
 	return fmt.Sprintf(`This command shows a Database Service type details.
 
-	Supported output template annotations: %s`,
-		strings.Join(outputterTemplateAnnotations(&dbTypeShowOutput{}), ", "))
+Using the "--settings" flag, the command lists the settings supported by the
+Database Service type instead, which can be set using the "--setting" flag of
+the "create" and "update" commands.
+
+	Supported output template annotations: %s
+
+	Supported output template annotations (--settings): %s`,
+		strings.Join(outputterTemplateAnnotations(&dbTypeShowOutput{}), ", "),
+		strings.Join(outputterTemplateAnnotations(&dbSetting{}), ", "))
 }
 
 func (c *dbTypeShowCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
@@ -91,6 +106,11 @@ func (c *dbTypeShowCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if c.ShowSettings {
+		out := dbTypeSettingsOutput(dbSettingsFromSchema(dt.UserConfigSchema))
+		return output(&out, nil)
+	}
+
 	return output(&dbTypeShowOutput{
 		Name:           *dt.Name,
 		Description:    defaultString(dt.Description, ""),
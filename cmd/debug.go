@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// debugRedacted replaces the credentials in the HTTP requests/responses
+// traces.
+const debugRedacted = "REDACTED"
+
+// gDebug, gDebugBody and gDebugFile are set by the global "--debug",
+// "--debug-body" and "--debug-file" flags, and gDebugLog is the resulting
+// HTTP requests trace log (nil if tracing is disabled).
+var (
+	gDebug     bool
+	gDebugBody bool
+	gDebugFile string
+	gDebugLog  *debugLog
+)
+
+// debugCredentialParams are the query/form parameters containing credentials
+// (API V1 request signature).
+var debugCredentialParams = []string{"apikey", "signature"}
+
+// debugCredentialFieldRe matches JSON fields containing credentials,
+// including the SKS clusters kubeconfig files.
+var debugCredentialFieldRe = regexp.MustCompile(
+	`(?i)("[^"]*(?:secret|password|passwd|token|private[-_]?key|kubeconfig)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// debugURLUserinfoRe matches the userinfo part of URLs, e.g. the credentials
+// embedded in the Database Services URIs.
+var debugURLUserinfoRe = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/?#@\s"']+@`)
+
+// debugPEMPrivateKeyRe matches PEM-encoded private keys, possibly embedded
+// in JSON strings (i.e. with escaped newlines).
+var debugPEMPrivateKeyRe = regexp.MustCompile(
+	`(?s)-----BEGIN ([A-Z0-9 ]*PRIVATE KEY)-----.*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)
+
+// debugLog writes a trace of the HTTP requests performed by the clients using
+// the cliRoundTripper.
+type debugLog struct {
+	mu   sync.Mutex
+	w    io.Writer
+	body bool
+}
+
+// initDebugLog sets up the HTTP requests trace log according to the debug
+// flags, or the EXOSCALE_DEBUG environment variable ("1"/"true" to enable
+// tracing, "body" to also trace the requests/responses bodies).
+func initDebugLog() {
+	switch v := strings.ToLower(os.Getenv("EXOSCALE_DEBUG")); v {
+	case "body":
+		gDebugBody = true
+	case "1", "true":
+		gDebug = true
+	}
+
+	if !gDebug && !gDebugBody && gDebugFile == "" {
+		return
+	}
+
+	gDebugLog = &debugLog{w: os.Stderr, body: gDebugBody}
+
+	if gDebugFile != "" {
+		f, err := os.OpenFile(gDebugFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: unable to open debug file: %s\n", err)
+			os.Exit(1)
+		}
+		gDebugLog.w = f
+	}
+}
+
+// readRequestBody returns the body of the request r if bodies are traced,
+// replacing it so that it can still be sent.
+func (l *debugLog) readRequestBody(r *http.Request) []byte {
+	if !l.body || r.Body == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	return body
+}
+
+// log writes the trace of a request r performed at the time start, with the
+// request body reqBody (if bodies are traced).
+func (l *debugLog) log(r *http.Request, reqBody []byte, res *http.Response, err error, start time.Time) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[debug] %s %s %s", start.Format(time.RFC3339), r.Method, debugRequestURL(r.URL))
+	switch {
+	case err != nil:
+		fmt.Fprintf(&b, " -> error: %s", err)
+	case res != nil:
+		fmt.Fprintf(&b, " -> %s", res.Status)
+	}
+	fmt.Fprintf(&b, " (%s)\n", time.Since(start).Round(time.Millisecond))
+
+	if l.body {
+		if len(reqBody) > 0 {
+			fmt.Fprintf(&b, "> %s\n", debugRedactBody(reqBody, r.Header.Get("Content-Type")))
+		}
+
+		if res != nil && res.Body != nil {
+			resBody, readErr := io.ReadAll(res.Body)
+			_ = res.Body.Close()
+			res.Body = io.NopCloser(bytes.NewReader(resBody))
+			if readErr == nil && len(resBody) > 0 {
+				fmt.Fprintf(&b, "< %s\n", debugRedactBody(resBody, res.Header.Get("Content-Type")))
+			}
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.w, b.String())
+}
+
+// debugRequestURL returns the URL u with the credentials redacted from its
+// query string.
+func debugRequestURL(u *url.URL) string {
+	redacted := *u
+	if u.RawQuery != "" {
+		redacted.RawQuery = debugRedactParams(u.Query()).Encode()
+	}
+
+	return redacted.String()
+}
+
+// debugRedactParams redacts the credentials from query/form parameters.
+func debugRedactParams(params url.Values) url.Values {
+	for k := range params {
+		if isInList(debugCredentialParams, strings.ToLower(k)) {
+			params.Set(k, debugRedacted)
+		}
+	}
+
+	return params
+}
+
+// debugRedactBody returns the request/response body with the credentials
+// redacted, either from form parameters or JSON fields depending on the
+// body content type.
+func debugRedactBody(body []byte, contentType string) string {
+	v := strings.TrimSpace(string(body))
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if params, err := url.ParseQuery(v); err == nil {
+			return debugRedactParams(params).Encode()
+		}
+	}
+
+	v = debugCredentialFieldRe.ReplaceAllString(v, `$1"`+debugRedacted+`"`)
+	v = debugURLUserinfoRe.ReplaceAllString(v, `${1}`+debugRedacted+`@`)
+	v = debugPEMPrivateKeyRe.ReplaceAllString(v, `-----BEGIN $1-----`+debugRedacted+`-----END $1-----`)
+
+	return v
+}
@@ -58,12 +58,13 @@ func (c *deployTargetListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	out := make(deployTargetListOutput, 0)
 	res := make(chan deployTargetListItemOutput)
-	defer close(res)
+	done := make(chan struct{})
 
 	go func() {
 		for dt := range res {
 			out = append(out, dt)
 		}
+		close(done)
 	}()
 	err := forEachZone(zones, func(zone string) error {
 		list, err := cs.ListDeployTargets(ctx, zone)
@@ -82,6 +83,8 @@ func (c *deployTargetListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 		return nil
 	})
+	close(res)
+	<-done
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr,
 			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// cliDeprecation represents a deprecated CLI command or flag: it remains
+// usable (printing a warning on the standard error) and visible in the help
+// until the removal version is released, after which it is hidden.
+type cliDeprecation struct {
+	cmd         *cobra.Command
+	flag        string
+	replacement string
+	removal     string
+	warned      bool
+}
+
+// gDeprecations is the registry of the deprecated CLI commands and flags.
+var gDeprecations []*cliDeprecation
+
+func (d *cliDeprecation) subject() string {
+	if d.flag != "" {
+		return fmt.Sprintf("the %q flag of the %q command", "--"+d.flag, d.cmd.CommandPath())
+	}
+	return fmt.Sprintf("the %q command", d.cmd.CommandPath())
+}
+
+// warn prints the deprecation warning, only once per CLI execution.
+func (d *cliDeprecation) warn() {
+	if d.warned {
+		return
+	}
+	d.warned = true
+
+	fmt.Fprintf(os.Stderr,
+		"WARNING: %s is deprecated and replaced by %q, it will be removed in version %s.\n",
+		d.subject(), d.replacement, d.removal)
+}
+
+// expired returns true if the current CLI version is past the removal version.
+func (d *cliDeprecation) expired() bool {
+	return versionAtLeast(gVersion, d.removal)
+}
+
+// cmdDeprecateCommand marks the command (and its sub-commands) as deprecated
+// in favor of the replacement, to be removed in the specified version.
+func cmdDeprecateCommand(cmd *cobra.Command, replacement, removal string) {
+	d := &cliDeprecation{cmd: cmd, replacement: replacement, removal: removal}
+	gDeprecations = append(gDeprecations, d)
+
+	cmdChainPersistentPreRun(cmd, func(_ *cobra.Command) { d.warn() })
+}
+
+// cmdDeprecateFlag marks the command flag as deprecated in favor of the
+// replacement, to be removed in the specified version.
+func cmdDeprecateFlag(cmd *cobra.Command, name, replacement, removal string) {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		panic(cliCommandImplemError{fmt.Sprintf("cmdDeprecateFlag: flag --%s not declared", name)})
+	}
+	flag.Usage = fmt.Sprintf("%s (deprecated, use %q instead)", flag.Usage, replacement)
+
+	d := &cliDeprecation{cmd: cmd, flag: name, replacement: replacement, removal: removal}
+	gDeprecations = append(gDeprecations, d)
+
+	cmdChainPersistentPreRun(cmd, func(c *cobra.Command) {
+		if c == cmd && c.Flags().Changed(name) {
+			d.warn()
+		}
+	})
+}
+
+// cmdRenameFlag declares oldName as a deprecated alias of the command flag
+// name, to be removed in the specified version. Setting the deprecated flag
+// sets the value of the new flag, as if it had been specified instead.
+func cmdRenameFlag(cmd *cobra.Command, name, oldName, removal string) {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		panic(cliCommandImplemError{fmt.Sprintf("cmdRenameFlag: flag --%s not declared", name)})
+	}
+
+	cmd.Flags().AddFlag(&pflag.Flag{
+		Name:     oldName,
+		Usage:    fmt.Sprintf("deprecated, use %q instead", "--"+name),
+		Value:    flag.Value,
+		DefValue: flag.DefValue,
+	})
+
+	d := &cliDeprecation{cmd: cmd, flag: oldName, replacement: "--" + name, removal: removal}
+	gDeprecations = append(gDeprecations, d)
+
+	cmdChainPersistentPreRun(cmd, func(c *cobra.Command) {
+		if c == cmd && c.Flags().Changed(oldName) {
+			c.Flags().Lookup(name).Changed = true
+			d.warn()
+		}
+	})
+}
+
+// cmdChainPersistentPreRun sets a persistent pre-run hook on the command
+// calling fn before the persistent pre-run hook that would have been executed
+// otherwise, i.e. the command's own or its closest parent's.
+func cmdChainPersistentPreRun(cmd *cobra.Command, fn func(*cobra.Command)) {
+	preRun, preRunE := cmd.PersistentPreRun, cmd.PersistentPreRunE
+
+	cmd.PersistentPreRun = nil
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		fn(c)
+
+		switch {
+		case preRunE != nil:
+			return preRunE(c, args)
+		case preRun != nil:
+			preRun(c, args)
+			return nil
+		}
+
+		for p := cmd.Parent(); p != nil; p = p.Parent() {
+			if p.PersistentPreRunE != nil {
+				return p.PersistentPreRunE(c, args)
+			}
+			if p.PersistentPreRun != nil {
+				p.PersistentPreRun(c, args)
+				return nil
+			}
+		}
+
+		return nil
+	}
+}
+
+// hideExpiredDeprecations hides from the help the deprecated commands and
+// flags which should have been removed in the current CLI version.
+func hideExpiredDeprecations() {
+	for _, d := range gDeprecations {
+		if !d.expired() {
+			continue
+		}
+
+		if d.flag != "" {
+			d.cmd.Flags().Lookup(d.flag).Hidden = true
+		} else {
+			d.cmd.Hidden = true
+		}
+	}
+}
+
+// parseVersion returns the numeric components of a "[v]X.Y.Z" version
+// string, ignoring any pre-release or build metadata suffix.
+func parseVersion(v string) ([3]int, error) {
+	var parts [3]int
+
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	fields := strings.Split(v, ".")
+	if len(fields) > len(parts) {
+		return parts, fmt.Errorf("invalid version %q", v)
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, fmt.Errorf("invalid version %q", v)
+		}
+		parts[i] = n
+	}
+
+	return parts, nil
+}
+
+// versionAtLeast returns true if the version v is greater than or equal to
+// the version min. Development builds (i.e. non-release versions such as
+// "dev") are never considered past any version.
+func versionAtLeast(v, min string) bool {
+	pv, err := parseVersion(v)
+	if err != nil {
+		return false
+	}
+	pmin, err := parseVersion(min)
+	if err != nil {
+		return false
+	}
+
+	for i := range pv {
+		if pv[i] != pmin[i] {
+			return pv[i] > pmin[i]
+		}
+	}
+
+	return true
+}
+
+type deprecationsItemOutput struct {
+	Command        string `json:"command"`
+	Flag           string `json:"flag,omitempty"`
+	Replacement    string `json:"replacement"`
+	RemovalVersion string `json:"removal_version"`
+}
+
+type deprecationsOutput []deprecationsItemOutput
+
+func (o *deprecationsOutput) toJSON()  { outputJSON(o) }
+func (o *deprecationsOutput) toText()  { outputText(o) }
+func (o *deprecationsOutput) toTable() { outputTable(o) }
+
+type deprecationsCmd struct {
+	_ bool `cli-cmd:"deprecations"`
+}
+
+func (c *deprecationsCmd) cmdAliases() []string { return nil }
+
+func (c *deprecationsCmd) cmdShort() string { return "List deprecated commands and flags" }
+
+func (c *deprecationsCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists the currently deprecated commands and flags, along
+with their replacement and the version they will be removed in.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&deprecationsItemOutput{}), ", "))
+}
+
+func (c *deprecationsCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *deprecationsCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	out := make(deprecationsOutput, 0, len(gDeprecations))
+
+	for _, d := range gDeprecations {
+		item := deprecationsItemOutput{
+			Command:        d.cmd.CommandPath(),
+			Replacement:    d.replacement,
+			RemovalVersion: d.removal,
+		}
+		if d.flag != "" {
+			item.Flag = "--" + d.flag
+		}
+		out = append(out, item)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Command != out[j].Command {
+			return out[i].Command < out[j].Command
+		}
+		return out[i].Flag < out[j].Flag
+	})
+
+	return output(&out, nil)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(RootCmd, &deprecationsCmd{}))
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_versionAtLeast(t *testing.T) {
+	tests := []struct {
+		v, min string
+		want   bool
+	}{
+		{"1.42.0", "1.42.0", true},
+		{"v1.42.1", "1.42.0", true},
+		{"1.43.0-rc1", "1.42.0", true},
+		{"2.0.0", "1.42.0", true},
+		{"1.41.9", "1.42.0", false},
+		{"1.9.0", "1.42.0", false},
+		{"dev", "1.42.0", false},
+		{"", "1.42.0", false},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, versionAtLeast(tt.v, tt.min), "%s >= %s", tt.v, tt.min)
+	}
+}
+
+type testDeprecatedCLICmd struct {
+	_ bool `cli-cmd:"deprecated" cli-deprecated:"1.42.0:exo new"`
+
+	NewName string `cli-renamed-from:"1.42.0:old-name"`
+	Legacy  bool   `cli-deprecated:"1.42.0:exo other"`
+
+	run func(*testDeprecatedCLICmd) `cli:"-"`
+}
+
+func (c *testDeprecatedCLICmd) cmdAliases() []string { return nil }
+func (c *testDeprecatedCLICmd) cmdShort() string     { return "" }
+func (c *testDeprecatedCLICmd) cmdLong() string      { return "" }
+func (c *testDeprecatedCLICmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+func (c *testDeprecatedCLICmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	c.run(c)
+	return nil
+}
+
+func Test_cliCommandDeprecations(t *testing.T) {
+	savedDeprecations, savedVersion := gDeprecations, gVersion
+	defer func() { gDeprecations, gVersion = savedDeprecations, savedVersion }()
+	gDeprecations = nil
+
+	var parentPreRun bool
+	root := &cobra.Command{
+		Use:              "root",
+		PersistentPreRun: func(_ *cobra.Command, _ []string) { parentPreRun = true },
+		SilenceUsage:     true,
+		SilenceErrors:    true,
+	}
+
+	var (
+		newName   string
+		changed   bool
+		legacySet bool
+	)
+	c := &testDeprecatedCLICmd{run: func(c *testDeprecatedCLICmd) { newName, legacySet = c.NewName, c.Legacy }}
+	require.NoError(t, registerCLICommand(root, c))
+	cmd, _, err := root.Find([]string{"deprecated"})
+	require.NoError(t, err)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		changed = cmd.Flags().Changed("new-name")
+		return c.cmdRun(cmd, args)
+	}
+
+	require.Len(t, gDeprecations, 3)
+
+	root.SetArgs([]string{"deprecated", "--old-name", "value", "--legacy"})
+	require.NoError(t, root.Execute())
+	require.Equal(t, "value", newName)
+	require.True(t, changed)
+	require.True(t, legacySet)
+	require.True(t, parentPreRun, "parent persistent pre-run hook not executed")
+	for _, d := range gDeprecations {
+		require.True(t, d.warned, "no warning for %s", d.subject())
+	}
+
+	gVersion = "1.41.0"
+	hideExpiredDeprecations()
+	require.False(t, cmd.Hidden)
+	require.False(t, cmd.Flags().Lookup("old-name").Hidden)
+
+	gVersion = "1.42.0"
+	hideExpiredDeprecations()
+	require.True(t, cmd.Hidden)
+	require.True(t, cmd.Flags().Lookup("old-name").Hidden)
+	require.True(t, cmd.Flags().Lookup("legacy").Hidden)
+	require.False(t, cmd.Flags().Lookup("new-name").Hidden)
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+// diffEntry represents a single difference between a current and a desired
+// value, identified by its path in the compared values.
+type diffEntry struct {
+	Path string      `json:"path"`
+	Type string      `json:"type"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// diffOutput represents the differences between a current and a desired
+// value, as returned by computeDiff().
+type diffOutput []diffEntry
+
+func (o *diffOutput) toJSON() { outputJSON(o) }
+func (o *diffOutput) toText() { outputText(o) }
+func (o *diffOutput) toTable() {
+	renderDiff(os.Stdout, *o, !gNoColor)
+}
+
+// diffOptions represents the options of a values comparison.
+type diffOptions struct {
+	// unorderedSlices makes slices to be compared as sets (i.e. regardless
+	// of their items order), reporting added and removed items only.
+	unorderedSlices bool
+}
+
+// diffNormalize returns a generic representation of the value v suitable for
+// comparison: pointers are dereferenced (nil pointers resulting in a nil
+// value), structs exported fields and maps are converted to
+// map[string]interface{}, slices to []interface{}, and types implementing the
+// fmt.Stringer interface to their string representation. Normalizing an
+// already normalized value returns an identical value, so it is possible to
+// take a snapshot of a value before modifying it in place.
+func diffNormalize(v interface{}) interface{} {
+	return diffNormalizeValue(reflect.ValueOf(v))
+}
+
+func diffNormalizeValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+	}
+
+	if v.CanInterface() {
+		if s, ok := v.Interface().(fmt.Stringer); ok && v.Kind() != reflect.Map {
+			return s.String()
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return diffNormalizeValue(v.Elem())
+
+	case reflect.Struct:
+		out := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			out[v.Type().Field(i).Name] = diffNormalizeValue(v.Field(i))
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{})
+		for _, k := range v.MapKeys() {
+			out[fmt.Sprint(k.Interface())] = diffNormalizeValue(v.MapIndex(k))
+		}
+		return out
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = diffNormalizeValue(v.Index(i))
+		}
+		return out
+
+	default:
+		return v.Interface()
+	}
+}
+
+// computeDiff returns the field-by-field differences between the current and
+// desired values, which can be structs, maps or pointers to those.
+func computeDiff(current, desired interface{}, opts diffOptions) diffOutput {
+	out := make(diffOutput, 0)
+	diffValues("", diffNormalize(current), diffNormalize(desired), opts, &out)
+	return out
+}
+
+func diffValues(path string, a, b interface{}, opts diffOptions, out *diffOutput) {
+	switch {
+	case a == nil && b == nil:
+		return
+
+	case a == nil:
+		*out = append(*out, diffEntry{Path: path, Type: diffAdded, New: b})
+		return
+
+	case b == nil:
+		*out = append(*out, diffEntry{Path: path, Type: diffRemoved, Old: a})
+		return
+	}
+
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := make(map[string]struct{})
+		for k := range am {
+			keys[k] = struct{}{}
+		}
+		for k := range bm {
+			keys[k] = struct{}{}
+		}
+
+		sortedKeys := make([]string, 0, len(keys))
+		for k := range keys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+
+		for _, k := range sortedKeys {
+			diffValues(diffJoinPath(path, k), am[k], bm[k], opts, out)
+		}
+		return
+	}
+
+	as, aIsSlice := a.([]interface{})
+	bs, bIsSlice := b.([]interface{})
+	if aIsSlice && bIsSlice {
+		if opts.unorderedSlices {
+			diffUnorderedSlices(path, as, bs, out)
+			return
+		}
+
+		for i := 0; i < len(as) || i < len(bs); i++ {
+			var ai, bi interface{}
+			if i < len(as) {
+				ai = as[i]
+			}
+			if i < len(bs) {
+				bi = bs[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), ai, bi, opts, out)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*out = append(*out, diffEntry{Path: path, Type: diffChanged, Old: a, New: b})
+	}
+}
+
+// diffUnorderedSlices compares slices a and b as multisets, reporting the
+// items missing from b as removed and the items missing from a as added.
+func diffUnorderedSlices(path string, a, b []interface{}, out *diffOutput) {
+	count := make(map[string]int)
+	for _, v := range b {
+		count[fmt.Sprint(v)]++
+	}
+
+	for _, v := range a {
+		k := fmt.Sprint(v)
+		if count[k] > 0 {
+			count[k]--
+			continue
+		}
+		*out = append(*out, diffEntry{Path: path + "[]", Type: diffRemoved, Old: v})
+	}
+
+	count = make(map[string]int)
+	for _, v := range a {
+		count[fmt.Sprint(v)]++
+	}
+
+	for _, v := range b {
+		k := fmt.Sprint(v)
+		if count[k] > 0 {
+			count[k]--
+			continue
+		}
+		*out = append(*out, diffEntry{Path: path + "[]", Type: diffAdded, New: v})
+	}
+}
+
+func diffJoinPath(path, k string) string {
+	if path == "" {
+		return k
+	}
+	return path + "." + k
+}
+
+// renderDiff writes a human-readable rendering of the differences d to w,
+// aligned on the values paths. If color is true, ANSI escape sequences are
+// used to colorize the entries according to their type.
+func renderDiff(w io.Writer, d diffOutput, color bool) {
+	if len(d) == 0 {
+		_, _ = fmt.Fprintln(w, "No changes.")
+		return
+	}
+
+	var width int
+	for _, e := range d {
+		if len(e.Path) > width {
+			width = len(e.Path)
+		}
+	}
+
+	for _, e := range d {
+		var marker, line, colorCode string
+
+		switch e.Type {
+		case diffAdded:
+			marker, colorCode = "+", "\033[32m"
+			line = diffFormatValue(e.New)
+		case diffRemoved:
+			marker, colorCode = "-", "\033[31m"
+			line = diffFormatValue(e.Old)
+		default:
+			marker, colorCode = "~", "\033[33m"
+			line = diffFormatValue(e.Old) + " => " + diffFormatValue(e.New)
+		}
+
+		line = fmt.Sprintf("%s %-*s  %s", marker, width, e.Path, line)
+		if color {
+			line = colorCode + line + "\033[0m"
+		}
+
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
+
+func diffFormatValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return fmt.Sprintf("%q", t)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = k + ":" + diffFormatValue(t[k])
+		}
+		return "{" + strings.Join(items, " ") + "}"
+	default:
+		return fmt.Sprint(t)
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testDiffNested struct {
+	Interval *time.Duration
+	Port     *uint16
+}
+
+type testDiffStruct struct {
+	Name        *string
+	Description *string
+	Labels      map[string]string
+	Settings    map[string]interface{}
+	Tags        []string
+	Nested      *testDiffNested
+	unexported  string // nolint:structcheck,unused
+}
+
+func Test_computeDiff(t *testing.T) {
+	var (
+		name1     = "a"
+		name2     = "b"
+		interval1 = 10 * time.Second
+		interval2 = 20 * time.Second
+		port      = uint16(80)
+	)
+
+	t.Run("no changes", func(t *testing.T) {
+		v := testDiffStruct{Name: &name1, Tags: []string{"x"}}
+		require.Empty(t, computeDiff(&v, &v, diffOptions{}))
+	})
+
+	t.Run("pointer fields", func(t *testing.T) {
+		d := computeDiff(
+			&testDiffStruct{Name: &name1, Nested: &testDiffNested{Interval: &interval1}},
+			&testDiffStruct{Name: &name2, Description: &name1, Nested: &testDiffNested{Interval: &interval2, Port: &port}},
+			diffOptions{},
+		)
+		require.Equal(t, diffOutput{
+			{Path: "Description", Type: diffAdded, New: "a"},
+			{Path: "Name", Type: diffChanged, Old: "a", New: "b"},
+			{Path: "Nested.Interval", Type: diffChanged, Old: "10s", New: "20s"},
+			{Path: "Nested.Port", Type: diffAdded, New: uint16(80)},
+		}, d)
+	})
+
+	t.Run("nested maps", func(t *testing.T) {
+		d := computeDiff(
+			map[string]interface{}{
+				"labels": map[string]string{"env": "prod", "team": "web"},
+				"pg":     map[string]interface{}{"max_connections": 100, "timezone": "UTC"},
+			},
+			map[string]interface{}{
+				"labels": map[string]string{"env": "dev", "owner": "alice"},
+				"pg":     map[string]interface{}{"max_connections": 200, "timezone": "UTC"},
+			},
+			diffOptions{},
+		)
+		require.Equal(t, diffOutput{
+			{Path: "labels.env", Type: diffChanged, Old: "prod", New: "dev"},
+			{Path: "labels.owner", Type: diffAdded, New: "alice"},
+			{Path: "labels.team", Type: diffRemoved, Old: "web"},
+			{Path: "pg.max_connections", Type: diffChanged, Old: 100, New: 200},
+		}, d)
+	})
+
+	t.Run("ordered slices", func(t *testing.T) {
+		d := computeDiff(
+			&testDiffStruct{Tags: []string{"a", "b"}},
+			&testDiffStruct{Tags: []string{"b", "a", "c"}},
+			diffOptions{},
+		)
+		require.Equal(t, diffOutput{
+			{Path: "Tags[0]", Type: diffChanged, Old: "a", New: "b"},
+			{Path: "Tags[1]", Type: diffChanged, Old: "b", New: "a"},
+			{Path: "Tags[2]", Type: diffAdded, New: "c"},
+		}, d)
+	})
+
+	t.Run("unordered slices", func(t *testing.T) {
+		d := computeDiff(
+			&testDiffStruct{Tags: []string{"a", "b", "d"}},
+			&testDiffStruct{Tags: []string{"b", "a", "c"}},
+			diffOptions{unorderedSlices: true},
+		)
+		require.Equal(t, diffOutput{
+			{Path: "Tags[]", Type: diffRemoved, Old: "d"},
+			{Path: "Tags[]", Type: diffAdded, New: "c"},
+		}, d)
+	})
+
+	t.Run("snapshot before in-place modification", func(t *testing.T) {
+		v := testDiffStruct{Name: &name1, Labels: map[string]string{"env": "prod"}}
+		before := diffNormalize(&v)
+		v.Name = &name2
+		v.Labels["env"] = "dev"
+
+		require.Equal(t, diffOutput{
+			{Path: "Labels.env", Type: diffChanged, Old: "prod", New: "dev"},
+			{Path: "Name", Type: diffChanged, Old: "a", New: "b"},
+		}, computeDiff(before, &v, diffOptions{}))
+	})
+}
+
+func Test_renderDiff(t *testing.T) {
+	d := diffOutput{
+		{Path: "Name", Type: diffChanged, Old: "a", New: "b"},
+		{Path: "Nested.Port", Type: diffAdded, New: uint16(80)},
+		{Path: "Tags[]", Type: diffRemoved, Old: "x"},
+	}
+
+	var buf bytes.Buffer
+	renderDiff(&buf, d, false)
+	require.Equal(t, `~ Name         "a" => "b"
++ Nested.Port  80
+- Tags[]       "x"
+`, buf.String())
+
+	buf.Reset()
+	renderDiff(&buf, d[:1], true)
+	require.Equal(t, "\033[33m~ Name  \"a\" => \"b\"\033[0m\n", buf.String())
+
+	buf.Reset()
+	renderDiff(&buf, diffOutput{}, false)
+	require.Equal(t, "No changes.\n", buf.String())
+}
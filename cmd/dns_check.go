@@ -0,0 +1,457 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+)
+
+// dnsCheckPollInterval is the interval between two checks of a domain
+// records in "--wait" mode.
+const dnsCheckPollInterval = 10 * time.Second
+
+// dnsCheckTimeout is the maximum duration of the DNS queries performed to
+// check a single record.
+const dnsCheckTimeout = 5 * time.Second
+
+// Records check statuses.
+const (
+	dnsCheckStatusOK       = "ok"
+	dnsCheckStatusMismatch = "mismatch"
+	dnsCheckStatusMissing  = "missing"
+	dnsCheckStatusSkipped  = "skipped"
+)
+
+// dnsCheckDefaultResolvers are the public DNS resolvers queried by default.
+var dnsCheckDefaultResolvers = []string{"1.1.1.1", "8.8.8.8"}
+
+// dnsCheckSupportedTypes are the record types which can be checked.
+var dnsCheckSupportedTypes = []string{"A", "AAAA", "ALIAS", "CNAME", "MX", "NS", "SRV", "TXT"}
+
+// dnsLookuper is the interface implemented by the DNS resolvers queried to
+// check the records, e.g. *net.Resolver.
+type dnsLookuper interface {
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+type dnsCheckItemOutput struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	TTL      int      `json:"ttl"`
+	Resolver string   `json:"resolver"`
+	Expected []string `json:"expected"`
+	Actual   []string `json:"actual"`
+	Status   string   `json:"status"`
+}
+
+type dnsCheckOutput []dnsCheckItemOutput
+
+func (o *dnsCheckOutput) toJSON()  { outputJSON(o) }
+func (o *dnsCheckOutput) toText()  { outputText(o) }
+func (o *dnsCheckOutput) toTable() { outputTable(o) }
+
+// failed returns the number of records not matching the live DNS.
+func (o *dnsCheckOutput) failed() int {
+	n := 0
+	for _, i := range *o {
+		if i.Status == dnsCheckStatusMismatch || i.Status == dnsCheckStatusMissing {
+			n++
+		}
+	}
+
+	return n
+}
+
+var dnsCheckCmd = &cobra.Command{
+	Use:   "check DOMAIN-NAME|ID",
+	Short: "Check a domain records against the live DNS",
+	Long: fmt.Sprintf(`This command compares the records configured for a domain with the
+answers of public DNS resolvers (set with the "--resolver" flag), and reports
+for each record set whether it matches, mismatches or is missing from the
+live DNS. The command fails if any record set doesn't match.
+
+Using the "--wait" flag, the check is repeated until all the record sets
+match or the "--max-wait" duration has elapsed (e.g. to wait for the DNS
+propagation after a migration).
+
+Notes:
+
+  * ALIAS records are checked against the addresses their target resolves
+    to, and CNAME records against their target canonical name.
+  * The records TTL cannot be retrieved from the resolvers: the configured
+    TTL is reported for information only.
+  * Supported record types: %s (other types are skipped).
+
+Supported output template annotations: %s`,
+		strings.Join(dnsCheckSupportedTypes, ", "),
+		strings.Join(outputterTemplateAnnotations(&dnsCheckItemOutput{}), ", ")),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return cmd.Usage()
+		}
+
+		resolvers, err := cmd.Flags().GetStringSlice("resolver")
+		if err != nil {
+			return err
+		}
+
+		wait, err := cmd.Flags().GetBool("wait")
+		if err != nil {
+			return err
+		}
+
+		maxWait, err := cmd.Flags().GetDuration("max-wait")
+		if err != nil {
+			return err
+		}
+
+		domain, err := csDNS.GetDomain(gContext, args[0])
+		if err != nil {
+			return err
+		}
+
+		records, err := csDNS.GetRecords(gContext, domain.Name)
+		if err != nil {
+			return err
+		}
+
+		lookupers := make(map[string]dnsLookuper, len(resolvers))
+		for _, r := range resolvers {
+			lookupers[r] = newDNSCheckResolver(r)
+		}
+
+		deadline := time.Now().Add(maxWait)
+		for {
+			out := checkDNSRecords(gContext, domain.Name, records, lookupers)
+
+			failed := out.failed()
+			if failed == 0 || !wait || time.Now().Add(dnsCheckPollInterval).After(deadline) {
+				if err := output(&out, nil); err != nil {
+					return err
+				}
+				if failed > 0 {
+					return fmt.Errorf("%d record set(s) not matching the live DNS", failed)
+				}
+				return nil
+			}
+
+			if !gQuiet {
+				fmt.Fprintf(os.Stderr, "%d record set(s) not matching the live DNS yet, retrying in %s...\n",
+					failed, dnsCheckPollInterval)
+			}
+
+			select {
+			case <-time.After(dnsCheckPollInterval):
+			case <-gContext.Done():
+				return gContext.Err()
+			}
+		}
+	},
+}
+
+// newDNSCheckResolver returns a DNS resolver querying the specified resolver
+// address (port 53 unless specified).
+func newDNSCheckResolver(address string) *net.Resolver {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "53")
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// dnsCheckRecordSet represents the records of a domain sharing the same name
+// and type.
+type dnsCheckRecordSet struct {
+	fqdn       string
+	recordType string
+	ttl        int
+	contents   []string
+}
+
+// dnsCheckRecordSets groups the records of a domain by name and type.
+func dnsCheckRecordSets(domain string, records []egoscale.DNSRecord) []*dnsCheckRecordSet {
+	sets := make([]*dnsCheckRecordSet, 0)
+	index := make(map[string]*dnsCheckRecordSet)
+
+	for _, r := range records {
+		if r.RecordType == "SOA" {
+			continue
+		}
+
+		fqdn := dnsNormalizeName(domain)
+		if r.Name != "" && r.Name != "@" {
+			fqdn = dnsNormalizeName(r.Name + "." + domain)
+		}
+
+		content := r.Content
+		switch r.RecordType {
+		case "MX", "SRV":
+			content = fmt.Sprintf("%d %s", r.Prio, content)
+		}
+
+		key := fqdn + "/" + r.RecordType
+		set, ok := index[key]
+		if !ok {
+			set = &dnsCheckRecordSet{fqdn: fqdn, recordType: r.RecordType, ttl: r.TTL}
+			index[key] = set
+			sets = append(sets, set)
+		}
+		set.contents = append(set.contents, dnsNormalizeContent(r.RecordType, content))
+	}
+
+	return sets
+}
+
+// checkDNSRecords checks the records of the domain against the answers of
+// the specified resolvers.
+func checkDNSRecords(
+	ctx context.Context,
+	domain string,
+	records []egoscale.DNSRecord,
+	lookupers map[string]dnsLookuper,
+) dnsCheckOutput {
+	out := make(dnsCheckOutput, 0)
+
+	resolvers := make([]string, 0, len(lookupers))
+	for r := range lookupers {
+		resolvers = append(resolvers, r)
+	}
+	sort.Strings(resolvers)
+
+	for _, set := range dnsCheckRecordSets(domain, records) {
+		expected := append([]string(nil), set.contents...)
+		sort.Strings(expected)
+
+		for _, resolver := range resolvers {
+			item := dnsCheckItemOutput{
+				Name:     set.fqdn,
+				Type:     set.recordType,
+				TTL:      set.ttl,
+				Resolver: resolver,
+				Expected: expected,
+				Actual:   []string{},
+			}
+
+			if !isInList(dnsCheckSupportedTypes, set.recordType) {
+				item.Status = dnsCheckStatusSkipped
+				out = append(out, item)
+				continue
+			}
+
+			lookupCtx, cancel := context.WithTimeout(ctx, dnsCheckTimeout)
+			actual, err := dnsLookupRecordSet(lookupCtx, lookupers[resolver], set)
+			cancel()
+
+			switch {
+			case err != nil:
+				item.Status = dnsCheckStatusMissing
+				item.Actual = []string{fmt.Sprintf("error: %s", err)}
+			case len(actual) == 0:
+				item.Status = dnsCheckStatusMissing
+			case dnsCheckMatch(expected, actual):
+				item.Status = dnsCheckStatusOK
+				item.Actual = actual
+			default:
+				item.Status = dnsCheckStatusMismatch
+				item.Actual = actual
+			}
+
+			// CNAME records are resolved up to the final canonical name:
+			// the configured target matches if it resolves to the same name.
+			if item.Status == dnsCheckStatusMismatch && set.recordType == "CNAME" && len(expected) == 1 {
+				lookupCtx, cancel := context.WithTimeout(ctx, dnsCheckTimeout)
+				target, err := lookupers[resolver].LookupCNAME(lookupCtx, expected[0])
+				cancel()
+				if err == nil && dnsCheckMatch([]string{dnsNormalizeName(target)}, actual) {
+					item.Status = dnsCheckStatusOK
+				}
+			}
+
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
+
+// dnsLookupRecordSet returns the normalized live records matching the
+// record set using the specified resolver. A non-existent name or record type
+// results in an empty list of records.
+func dnsLookupRecordSet(ctx context.Context, r dnsLookuper, set *dnsCheckRecordSet) ([]string, error) {
+	actual := make([]string, 0)
+
+	var err error
+	switch set.recordType {
+	case "A", "AAAA":
+		actual, err = dnsLookupIPs(ctx, r, set.fqdn, set.recordType)
+
+	case "ALIAS":
+		// ALIAS records are flattened by the DNS servers, which answer with
+		// the addresses of the target: all the addresses the target resolves
+		// to are expected.
+		actual, err = dnsLookupIPs(ctx, r, set.fqdn, "")
+		if err == nil && len(set.contents) == 1 {
+			target, targetErr := dnsLookupIPs(ctx, r, set.contents[0], "")
+			if targetErr == nil && dnsCheckMatch(target, actual) {
+				actual = []string{set.contents[0]}
+			}
+		}
+
+	case "CNAME":
+		var cname string
+		if cname, err = r.LookupCNAME(ctx, set.fqdn); err == nil && dnsNormalizeName(cname) != set.fqdn {
+			actual = append(actual, dnsNormalizeName(cname))
+		}
+
+	case "MX":
+		var mxs []*net.MX
+		if mxs, err = r.LookupMX(ctx, set.fqdn); err == nil {
+			for _, mx := range mxs {
+				actual = append(actual, fmt.Sprintf("%d %s", mx.Pref, dnsNormalizeName(mx.Host)))
+			}
+		}
+
+	case "NS":
+		var nss []*net.NS
+		if nss, err = r.LookupNS(ctx, set.fqdn); err == nil {
+			for _, ns := range nss {
+				actual = append(actual, dnsNormalizeName(ns.Host))
+			}
+		}
+
+	case "SRV":
+		var srvs []*net.SRV
+		if _, srvs, err = r.LookupSRV(ctx, "", "", set.fqdn); err == nil {
+			for _, srv := range srvs {
+				actual = append(actual, fmt.Sprintf("%d %d %d %s",
+					srv.Priority, srv.Weight, srv.Port, dnsNormalizeName(srv.Target)))
+			}
+		}
+
+	case "TXT":
+		var txts []string
+		if txts, err = r.LookupTXT(ctx, set.fqdn); err == nil {
+			for _, txt := range txts {
+				actual = append(actual, dnsNormalizeContent("TXT", txt))
+			}
+		}
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(actual)
+
+	return actual, nil
+}
+
+// dnsLookupIPs returns the IPv4 ("A"), IPv6 ("AAAA") or all addresses of
+// the specified host.
+func dnsLookupIPs(ctx context.Context, r dnsLookuper, host, recordType string) ([]string, error) {
+	network := "ip"
+	switch recordType {
+	case "A":
+		network = "ip4"
+	case "AAAA":
+		network = "ip6"
+	}
+
+	ips, err := r.LookupIP(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, len(ips))
+	for i, ip := range ips {
+		addresses[i] = ip.String()
+	}
+	sort.Strings(addresses)
+
+	return addresses, nil
+}
+
+// dnsCheckMatch returns true if the sorted expected and actual records are
+// identical.
+func dnsCheckMatch(expected, actual []string) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for i := range expected {
+		if expected[i] != actual[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dnsNormalizeName returns the lowercase domain name without trailing dot.
+func dnsNormalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// dnsNormalizeContent returns the normalized content of a record of the
+// specified type, so that configured and live records can be compared.
+func dnsNormalizeContent(recordType, content string) string {
+	content = strings.TrimSpace(content)
+
+	switch recordType {
+	case "A", "AAAA":
+		if ip := net.ParseIP(content); ip != nil {
+			return ip.String()
+		}
+
+	case "ALIAS", "CNAME", "NS":
+		return dnsNormalizeName(content)
+
+	case "MX", "SRV":
+		// The last field is the target host name.
+		fields := strings.Fields(content)
+		if len(fields) > 0 {
+			fields[len(fields)-1] = dnsNormalizeName(fields[len(fields)-1])
+		}
+		return strings.Join(fields, " ")
+
+	case "TXT":
+		// Long TXT records are split in multiple quoted strings.
+		if strings.HasPrefix(content, `"`) && strings.HasSuffix(content, `"`) {
+			parts := strings.Split(strings.Trim(content, `"`), `" "`)
+			return strings.Join(parts, "")
+		}
+	}
+
+	return content
+}
+
+func init() {
+	dnsCheckCmd.Flags().StringSlice("resolver", dnsCheckDefaultResolvers,
+		"DNS resolver ADDRESS[:PORT] to query (can be specified multiple times)")
+	dnsCheckCmd.Flags().Bool("wait", false, "Repeat the check until all the records match the live DNS")
+	dnsCheckCmd.Flags().Duration("max-wait", 15*time.Minute, "Maximum time to wait for the records to match in --wait mode")
+	dnsCmd.AddCommand(dnsCheckCmd)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/exoscale/egoscale"
+	"github.com/stretchr/testify/require"
+)
+
+// testDNSLookuper is a dnsLookuper answering from static records.
+type testDNSLookuper struct {
+	ips    map[string][]string
+	cnames map[string]string
+	mxs    map[string][]*net.MX
+	txts   map[string][]string
+}
+
+func (l *testDNSLookuper) LookupIP(_ context.Context, network, host string) ([]net.IP, error) {
+	if cname, ok := l.cnames[host]; ok {
+		host = cname
+	}
+
+	ips := make([]net.IP, 0)
+	for _, v := range l.ips[host] {
+		ip := net.ParseIP(v)
+		if network == "ip" || (network == "ip4") == (ip.To4() != nil) {
+			ips = append(ips, ip)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return ips, nil
+}
+
+func (l *testDNSLookuper) LookupCNAME(_ context.Context, host string) (string, error) {
+	for {
+		cname, ok := l.cnames[host]
+		if !ok {
+			return host + ".", nil
+		}
+		host = cname
+	}
+}
+
+func (l *testDNSLookuper) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	return l.mxs[name], nil
+}
+
+func (l *testDNSLookuper) LookupNS(_ context.Context, _ string) ([]*net.NS, error) {
+	return nil, nil
+}
+
+func (l *testDNSLookuper) LookupSRV(_ context.Context, _, _, _ string) (string, []*net.SRV, error) {
+	return "", nil, nil
+}
+
+func (l *testDNSLookuper) LookupTXT(_ context.Context, name string) ([]string, error) {
+	return l.txts[name], nil
+}
+
+func Test_dnsNormalizeContent(t *testing.T) {
+	require.Equal(t, "example.net", dnsNormalizeContent("CNAME", "Example.NET."))
+	require.Equal(t, "10 mx.example.net", dnsNormalizeContent("MX", "10 mx.example.net."))
+	require.Equal(t, "v=spf1 -all", dnsNormalizeContent("TXT", `"v=spf1 " "-all"`))
+	require.Equal(t, "2001:db8::1", dnsNormalizeContent("AAAA", "2001:0db8::0001"))
+}
+
+func Test_checkDNSRecords(t *testing.T) {
+	records := []egoscale.DNSRecord{
+		{Name: "", RecordType: "SOA", Content: "ns1.exoscale.ch"},
+		{Name: "", RecordType: "ALIAS", Content: "lb.example.net."},
+		{Name: "www", RecordType: "A", Content: "192.0.2.1", TTL: 3600},
+		{Name: "www", RecordType: "A", Content: "192.0.2.2", TTL: 3600},
+		{Name: "app", RecordType: "CNAME", Content: "app.example.net."},
+		{Name: "old", RecordType: "A", Content: "192.0.2.9"},
+		{Name: "", RecordType: "MX", Content: "mx.example.com.", Prio: 10},
+		{Name: "", RecordType: "TXT", Content: `"v=spf1 -all"`},
+		{Name: "", RecordType: "CAA", Content: `0 issue "letsencrypt.org"`},
+		{Name: "ipv6", RecordType: "AAAA", Content: "2001:db8::1"},
+	}
+
+	lookuper := &testDNSLookuper{
+		ips: map[string][]string{
+			"example.com":      {"198.51.100.1"},
+			"lb.example.net":   {"198.51.100.1"},
+			"www.example.com":  {"192.0.2.2", "192.0.2.1"},
+			"edge.example.net": {"203.0.113.1"},
+			"ipv6.example.com": {"2001:db8::2"},
+		},
+		cnames: map[string]string{
+			"app.example.com": "app.example.net",
+			"app.example.net": "edge.example.net",
+		},
+		mxs:  map[string][]*net.MX{"example.com": {{Host: "MX.example.com.", Pref: 10}}},
+		txts: map[string][]string{"example.com": {"v=spf1 -all"}},
+	}
+
+	out := checkDNSRecords(context.Background(), "example.com.", records,
+		map[string]dnsLookuper{"192.0.2.53": lookuper})
+
+	statuses := make(map[string]string)
+	for _, i := range out {
+		statuses[i.Name+"/"+i.Type] = i.Status
+	}
+
+	require.Equal(t, map[string]string{
+		"example.com/ALIAS":     dnsCheckStatusOK,
+		"www.example.com/A":     dnsCheckStatusOK,
+		"app.example.com/CNAME": dnsCheckStatusOK,
+		"old.example.com/A":     dnsCheckStatusMissing,
+		"example.com/MX":        dnsCheckStatusOK,
+		"example.com/TXT":       dnsCheckStatusOK,
+		"example.com/CAA":       dnsCheckStatusSkipped,
+		"ipv6.example.com/AAAA": dnsCheckStatusMismatch,
+	}, statuses)
+	require.Equal(t, 2, out.failed())
+}
@@ -2,32 +2,102 @@ package cmd
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/exoscale/egoscale"
 	"github.com/spf13/cobra"
 )
 
+var dnsDomainLabelRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+type dnsDomainOutput struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	UnicodeName string `json:"unicode_name,omitempty" output:"-"`
+	State       string `json:"state"`
+	RecordCount int64  `json:"record_count"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func (o *dnsDomainOutput) toJSON()  { outputJSON(o) }
+func (o *dnsDomainOutput) toText()  { outputText(o) }
+func (o *dnsDomainOutput) toTable() { outputTable(o) }
+
 var dnsCreateCmd = &cobra.Command{
-	Use:     "create DOMAIN",
-	Short:   "Create a domain",
+	Use:   "create DOMAIN",
+	Short: "Create a domain",
+	Long: fmt.Sprintf(`This command creates a DNS domain.
+
+Internationalized domain names must be specified in their ASCII ("xn--")
+form.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&dnsDomainOutput{}), ", ")),
 	Aliases: gCreateAlias,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return cmd.Usage()
 		}
-		resp, err := createDomain(args[0])
+
+		name := strings.ToLower(strings.TrimSuffix(args[0], "."))
+		if err := validateDNSDomainName(name); err != nil {
+			return err
+		}
+
+		resp, err := createDomain(name)
 		if err != nil {
 			return err
 		}
 
 		if !gQuiet {
-			fmt.Printf("Domain %q was created successfully\n", resp.Name)
+			return output(newDNSDomainOutput(resp), nil)
 		}
 
 		return nil
 	},
 }
 
+// validateDNSDomainName checks that name is a syntactically valid domain
+// name, i.e. made of at least 2 dot-separated labels of 1 to 63 letters,
+// digits or hyphens (not starting nor ending with a hyphen), the top-level
+// domain not being all-numeric.
+func validateDNSDomainName(name string) error {
+	if name == "" || len(name) > 253 {
+		return fmt.Errorf("invalid domain name %q: length must be between 1 and 253 characters", name)
+	}
+
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("invalid domain name %q: a domain name must contain at least 2 labels (e.g. example.net)", name)
+	}
+
+	for _, label := range labels {
+		if !dnsDomainLabelRe.MatchString(label) {
+			return fmt.Errorf("invalid domain name %q: invalid label %q "+
+				"(labels must be 1 to 63 letters, digits or hyphens, and must not start or end with a hyphen)",
+				name, label)
+		}
+	}
+
+	if tld := labels[len(labels)-1]; strings.Trim(tld, "0123456789") == "" {
+		return fmt.Errorf("invalid domain name %q: top-level domain cannot be all-numeric", name)
+	}
+
+	return nil
+}
+
+func newDNSDomainOutput(d *egoscale.DNSDomain) *dnsDomainOutput {
+	return &dnsDomainOutput{
+		ID:          d.ID,
+		Name:        d.Name,
+		UnicodeName: d.UnicodeName,
+		State:       d.State,
+		RecordCount: d.RecordCount,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
 func createDomain(domainName string) (*egoscale.DNSDomain, error) {
 	return csDNS.CreateDomain(gContext, domainName)
 }
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateDNSDomainName(t *testing.T) {
+	for _, name := range []string{
+		"example.net",
+		"sub.example.net",
+		"my-domain.co.uk",
+		"xn--bcher-kva.ch",
+		"123.example.net",
+	} {
+		require.NoError(t, validateDNSDomainName(name), name)
+	}
+
+	for _, name := range []string{
+		"",
+		"example",
+		"example..net",
+		"-example.net",
+		"example-.net",
+		"exa_mple.net",
+		"example.123",
+		strings.Repeat("a", 64) + ".net",
+		strings.Repeat("a.", 127) + "net",
+	} {
+		require.Error(t, validateDNSDomainName(name), name)
+	}
+}
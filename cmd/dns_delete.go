@@ -2,36 +2,84 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+// dnsDNSSECRecordTypes are the record types indicating that DNSSEC is
+// enabled on a domain, as the DNS API doesn't report it otherwise.
+var dnsDNSSECRecordTypes = []string{"CDNSKEY", "CDS", "DNSKEY", "DS"}
+
+type dnsDeleteOutput struct {
+	Name           string `json:"name"`
+	RecordsDeleted int    `json:"records_deleted"`
+}
+
+func (o *dnsDeleteOutput) toJSON()  { outputJSON(o) }
+func (o *dnsDeleteOutput) toText()  { outputText(o) }
+func (o *dnsDeleteOutput) toTable() { outputTable(o) }
+
 var dnsDeleteCmd = &cobra.Command{
-	Use:     "delete DOMAIN",
-	Short:   "Delete a domain",
+	Use:   "delete DOMAIN",
+	Short: "Delete a domain",
+	Long: fmt.Sprintf(`This command deletes a DNS domain and all its records.
+
+The number of records to be deleted is displayed, and the deletion must be
+confirmed by typing the domain name (unless the "--force" flag is specified).
+
+If the domain has DNSSEC-related records (%s), the deletion is
+refused unless the "--dnssec-acknowledged" flag is specified: deleting a
+domain while DNSSEC is still enabled at the registrar makes it unresolvable.
+
+Supported output template annotations: %s`,
+		strings.Join(dnsDNSSECRecordTypes, ", "),
+		strings.Join(outputterTemplateAnnotations(&dnsDeleteOutput{}), ", ")),
 	Aliases: gDeleteAlias,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return cmd.Usage()
 		}
+		name := args[0]
 
 		force, err := cmd.Flags().GetBool("force")
 		if err != nil {
 			return err
 		}
 
+		dnssecAcknowledged, err := cmd.Flags().GetBool("dnssec-acknowledged")
+		if err != nil {
+			return err
+		}
+
+		records, err := csDNS.GetRecords(gContext, name)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range records {
+			if isInList(dnsDNSSECRecordTypes, r.RecordType) && !dnssecAcknowledged {
+				return fmt.Errorf("domain %q has DNSSEC enabled (%s record found): "+
+					"disable DNSSEC at the registrar first, or use --dnssec-acknowledged to delete it anyway",
+					name, r.RecordType)
+			}
+		}
+
 		if !force {
-			if !askQuestion(fmt.Sprintf("Are you sure you want to delete %q domain?", args[0])) {
+			if !askTypedQuestion(
+				fmt.Sprintf("Domain %q and its %d record(s) will be deleted.", name, len(records)),
+				name,
+			) {
 				return nil
 			}
 		}
 
-		if err := deleteDomain(args[0]); err != nil {
+		if err := deleteDomain(name); err != nil {
 			return err
 		}
 
 		if !gQuiet {
-			fmt.Printf("Domain %q was deleted successfully\n", args[0])
+			return output(&dnsDeleteOutput{Name: name, RecordsDeleted: len(records)}, nil)
 		}
 
 		return nil
@@ -45,4 +93,5 @@ func deleteDomain(domainName string) error {
 func init() {
 	dnsCmd.AddCommand(dnsDeleteCmd)
 	dnsDeleteCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
+	dnsDeleteCmd.Flags().Bool("dnssec-acknowledged", false, "delete the domain even if DNSSEC is enabled")
 }
@@ -1,17 +1,32 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path"
 
+	"github.com/exoscale/egoscale"
 	"github.com/spf13/cobra"
 )
 
 var dnsRemoveCmd = &cobra.Command{
-	Use:     "remove DOMAIN RECORD-NAME|ID",
-	Short:   "Remove a domain record",
+	Use:   "remove DOMAIN [RECORD-NAME|ID]",
+	Short: "Remove a domain record",
+	Long: `This command removes a DNS domain record.
+
+Instead of specifying a record name or ID, it is possible to remove all the
+records matching the criteria specified using the "--type" and/or "--name"
+flags, the latter supporting shell-style wildcards, e.g.:
+
+    exo dns remove example.net --type TXT --name '_acme-challenge*'
+
+The matching records are listed before being removed. Using the
+"--ignore-missing" flag, the command doesn't fail if no records match the
+criteria.`,
 	Aliases: gRemoveAlias,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) < 2 {
+		if len(args) < 1 {
 			return cmd.Usage()
 		}
 
@@ -20,6 +35,33 @@ var dnsRemoveCmd = &cobra.Command{
 			return err
 		}
 
+		recordType, err := cmd.Flags().GetString("type")
+		if err != nil {
+			return err
+		}
+
+		namePattern, err := cmd.Flags().GetString("name")
+		if err != nil {
+			return err
+		}
+
+		ignoreMissing, err := cmd.Flags().GetBool("ignore-missing")
+		if err != nil {
+			return err
+		}
+
+		if len(args) < 2 {
+			if recordType == "" && namePattern == "" {
+				return cmd.Usage()
+			}
+
+			return removeRecordsMatching(args[0], recordType, namePattern, force, ignoreMissing)
+		}
+
+		if recordType != "" || namePattern != "" {
+			return errors.New(`"--type" and "--name" flags cannot be used with a RECORD-NAME|ID argument`)
+		}
+
 		if !force {
 			if !askQuestion(fmt.Sprintf("Are you sure you want to remove record %q?", args[1])) {
 				return nil
@@ -50,7 +92,83 @@ func removeRecord(domainName, record string) (int64, error) {
 	return id, nil
 }
 
+// removeRecordsMatching removes the records of a domain matching the
+// specified type and name pattern, after listing them and asking for
+// confirmation unless force is true.
+func removeRecordsMatching(domainName, recordType, namePattern string, force, ignoreMissing bool) error {
+	if _, err := path.Match(namePattern, ""); err != nil {
+		return fmt.Errorf("invalid record name pattern %q: %s", namePattern, err)
+	}
+
+	records, err := csDNS.GetRecordsWithFilters(gContext, domainName, "", recordType)
+	if err != nil {
+		return err
+	}
+
+	matching := make([]egoscale.DNSRecord, 0)
+	for _, record := range records {
+		if namePattern != "" {
+			if ok, _ := path.Match(namePattern, record.Name); !ok {
+				continue
+			}
+		}
+		matching = append(matching, record)
+	}
+
+	if len(matching) == 0 {
+		if ignoreMissing {
+			if !gQuiet {
+				_, _ = fmt.Fprintf(os.Stderr, "No records matching the criteria found in %q\n", domainName)
+			}
+			return nil
+		}
+		return fmt.Errorf("no records matching the criteria found in %q", domainName)
+	}
+
+	list := make(dnsShowOutput, len(matching))
+	for i, record := range matching {
+		list[i] = dnsShowItemOutput{
+			ID:         record.ID,
+			Name:       record.Name,
+			RecordType: record.RecordType,
+			Content:    record.Content,
+			TTL:        record.TTL,
+			Prio:       record.Prio,
+		}
+	}
+
+	if !force {
+		list.toTable()
+		if !askQuestion(fmt.Sprintf("Are you sure you want to remove these %d records?", len(matching))) {
+			return nil
+		}
+	}
+
+	var removed int
+	for _, record := range matching {
+		if err := csDNS.DeleteRecord(gContext, domainName, record.ID); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to remove record %d (%s %s): %s\n",
+				record.ID, record.RecordType, record.Name, err)
+			continue
+		}
+		removed++
+	}
+
+	if !gQuiet {
+		fmt.Printf("%d/%d records removed successfully from %q\n", removed, len(matching), domainName)
+	}
+
+	if removed < len(matching) {
+		return fmt.Errorf("%d records could not be removed", len(matching)-removed)
+	}
+
+	return nil
+}
+
 func init() {
 	dnsRemoveCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
+	dnsRemoveCmd.Flags().StringP("type", "t", "", "remove all records of this type (e.g. TXT)")
+	dnsRemoveCmd.Flags().StringP("name", "n", "", "remove all records whose name matches this pattern (e.g. '_acme-challenge*')")
+	dnsRemoveCmd.Flags().Bool("ignore-missing", false, "don't fail if no records match the criteria")
 	dnsCmd.AddCommand(dnsRemoveCmd)
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/exoscale/egoscale"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDNSClient sets the global DNS API client up to send its requests
+// to the HTTP handler h.
+func newTestDNSClient(t *testing.T, h http.HandlerFunc) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		h(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	savedDNS, savedContext := csDNS, gContext
+	t.Cleanup(func() { csDNS, gContext = savedDNS, savedContext })
+	csDNS = egoscale.NewClient(server.URL, "EXOtest", "test", egoscale.WithoutV2Client())
+	gContext = context.Background()
+}
+
+func Test_removeRecordsMatching(t *testing.T) {
+	var (
+		removed []string
+		mu      sync.Mutex
+	)
+
+	newTestDNSClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/domains/example.net/records" &&
+			r.URL.Query().Get("record_type") == "TXT":
+			fmt.Fprint(w, `[
+				{"record":{"id":1,"name":"_acme-challenge","record_type":"TXT","content":"a"}},
+				{"record":{"id":2,"name":"_acme-challenge.www","record_type":"TXT","content":"b"}},
+				{"record":{"id":3,"name":"","record_type":"TXT","content":"v=spf1 -all"}}
+			]`)
+
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1/domains/example.net/records/"):
+			mu.Lock()
+			removed = append(removed, path.Base(r.URL.Path))
+			mu.Unlock()
+			fmt.Fprint(w, `{}`)
+
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":"unexpected request %s %s"}`, r.Method, r.URL)
+		}
+	})
+
+	savedQuiet := gQuiet
+	t.Cleanup(func() { gQuiet = savedQuiet })
+	gQuiet = true
+
+	require.NoError(t, removeRecordsMatching("example.net", "TXT", "_acme-challenge*", true, false))
+	require.Equal(t, []string{"1", "2"}, removed)
+
+	// No records match the criteria.
+	removed = nil
+	require.Error(t, removeRecordsMatching("example.net", "TXT", "www", true, false))
+	require.NoError(t, removeRecordsMatching("example.net", "TXT", "www", true, true))
+	require.Empty(t, removed)
+
+	require.Error(t, removeRecordsMatching("example.net", "TXT", "[", true, false))
+}
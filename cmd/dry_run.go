@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// cmdAnnotationDryRun is the cobra.Command annotation marking the commands
+// supporting the global "--dry-run" flag.
+const cmdAnnotationDryRun = "exo/dry-run"
+
+// gDryRun is set by the global "--dry-run" flag: the commands supporting it
+// build their API request payload as usual, then print it instead of
+// sending it.
+var gDryRun bool
+
+// cliCommandDryRunner is implemented by the cliCommand types supporting the
+// global "--dry-run" flag. Such commands must check gDryRun once their API
+// request payload is built, and return outputDryRun(payload) instead of
+// performing the request.
+type cliCommandDryRunner interface {
+	cmdDryRunSupported()
+}
+
+// enforceDryRunSupport sets a pre-run hook on cmd and its sub-commands not
+// supporting the global "--dry-run" flag, returning an error if the flag is
+// set so that they don't silently perform the operation. Commands defining
+// their own "--dry-run" flag shadow the global one and are not affected.
+func enforceDryRunSupport(cmd *cobra.Command) {
+	if _, ok := cmd.Annotations[cmdAnnotationDryRun]; !ok && cmd.Runnable() {
+		preRun, preRunE := cmd.PreRun, cmd.PreRunE
+
+		cmd.PreRun = nil
+		cmd.PreRunE = func(c *cobra.Command, args []string) error {
+			if gDryRun {
+				return fmt.Errorf("command %q doesn't support the --dry-run flag", c.CommandPath())
+			}
+
+			switch {
+			case preRunE != nil:
+				return preRunE(c, args)
+			case preRun != nil:
+				preRun(c, args)
+			}
+
+			return nil
+		}
+	}
+
+	for _, c := range cmd.Commands() {
+		enforceDryRunSupport(c)
+	}
+}
+
+// dryRunOutput is the output of a command executed in "--dry-run" mode,
+// i.e. the API request payload it would have sent.
+type dryRunOutput struct {
+	payload interface{}
+}
+
+func (o *dryRunOutput) MarshalJSON() ([]byte, error) { return json.Marshal(o.payload) }
+
+func (o *dryRunOutput) toJSON()  { outputJSON(o) }
+func (o *dryRunOutput) toText()  { outputJSON(o) }
+func (o *dryRunOutput) toTable() { outputJSON(o) }
+
+// outputDryRun prints the API request payload of a command executed in
+// "--dry-run" mode.
+func outputDryRun(payload interface{}) error {
+	return output(&dryRunOutput{payload: payload}, nil)
+}
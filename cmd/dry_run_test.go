@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+type testDryRunCLICmd struct {
+	_ bool `cli-cmd:"create"`
+
+	Name string `cli-arg:"#"`
+
+	payload string `cli:"-"`
+}
+
+func (c *testDryRunCLICmd) cmdAliases() []string { return nil }
+func (c *testDryRunCLICmd) cmdShort() string     { return "" }
+func (c *testDryRunCLICmd) cmdLong() string      { return "" }
+func (c *testDryRunCLICmd) cmdDryRunSupported()  {}
+func (c *testDryRunCLICmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *testDryRunCLICmd) cmdRun(_ *cobra.Command, _ []string) error {
+	if gDryRun {
+		c.payload = c.Name
+	}
+	return nil
+}
+
+func Test_enforceDryRunSupport(t *testing.T) {
+	defer func(v bool) { gDryRun = v }(gDryRun)
+
+	var deleted, preRun bool
+	root := &cobra.Command{Use: "root", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().BoolVar(&gDryRun, "dry-run", false, "")
+	root.AddCommand(&cobra.Command{
+		Use:    "delete",
+		PreRun: func(_ *cobra.Command, _ []string) { preRun = true },
+		Run:    func(_ *cobra.Command, _ []string) { deleted = true },
+	})
+	c := &testDryRunCLICmd{}
+	require.NoError(t, registerCLICommand(root, c))
+	enforceDryRunSupport(root)
+
+	root.SetArgs([]string{"delete"})
+	require.NoError(t, root.Execute())
+	require.True(t, preRun)
+	require.True(t, deleted)
+
+	deleted = false
+	root.SetArgs([]string{"delete", "--dry-run"})
+	require.EqualError(t, root.Execute(), `command "root delete" doesn't support the --dry-run flag`)
+	require.False(t, deleted)
+
+	root.SetArgs([]string{"create", "test", "--dry-run"})
+	require.NoError(t, root.Execute())
+	require.Equal(t, "test", c.payload)
+}
@@ -32,6 +32,16 @@ func getElasticIPByAddressOrID(v string) (*egoscale.IPAddress, error) {
 	return nil, fmt.Errorf("Elastic IP %q not found", v) // nolint
 }
 
+// eipAddressFamily returns the address family ("inet4" or "inet6") of an
+// Elastic IP address.
+func eipAddressFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return "inet4"
+	}
+
+	return "inet6"
+}
+
 func init() {
 	RootCmd.AddCommand(eipCmd)
 }
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+)
+
+// eipConfigScriptOSes lists the operating systems supported by the
+// "exo eip config-script" command.
+var eipConfigScriptOSes = []string{"ubuntu", "alpine"}
+
+// eipConfigScriptHealthcheckDir is the directory served by the healthcheck
+// endpoint set up by the generated configuration scripts.
+const eipConfigScriptHealthcheckDir = "/var/lib/exoscale-eip"
+
+// eipConfigScriptParams represents the parameters of the configuration
+// script templates.
+type eipConfigScriptParams struct {
+	Name            string
+	Label           string
+	Address         string
+	Family          string
+	PrefixLen       int
+	Mode            string
+	Port            int64
+	Path            string
+	HealthcheckDir  string
+	HealthcheckFile string
+}
+
+const eipConfigScriptUbuntu = `#!/bin/sh
+# Managed Elastic IP {{ .Address }} configuration for Ubuntu, generated by
+# "exo eip config-script". Run this script as root on every instance the
+# Elastic IP is associated to.
+#
+# Changes:
+#   * /etc/netplan/60-{{ .Name }}.yaml: Elastic IP address on the loopback interface
+{{- if .HealthcheckFile }}
+#   * /etc/systemd/system/{{ .Name }}-healthcheck.service: {{ .Mode }} healthcheck endpoint on port {{ .Port }}
+{{- end }}
+#
+# To revert:
+{{- if .HealthcheckFile }}
+#   systemctl disable --now {{ .Name }}-healthcheck
+#   rm -rf /etc/systemd/system/{{ .Name }}-healthcheck.service {{ .HealthcheckDir }}
+{{- end }}
+#   rm /etc/netplan/60-{{ .Name }}.yaml && netplan apply
+{{- if eq .Mode "https" }}
+#
+# The Elastic IP healthcheck is in "https" mode: the endpoint
+# https://<instance>:{{ .Port }}{{ .Path }} must be served by your application.
+{{- end }}
+
+set -e
+
+cat > /etc/netplan/60-{{ .Name }}.yaml <<'EOF'
+network:
+  version: 2
+  ethernets:
+    lo:
+      match:
+        name: lo
+      addresses:
+        - {{ .Address }}/{{ .PrefixLen }}
+EOF
+chmod 600 /etc/netplan/60-{{ .Name }}.yaml
+netplan apply
+{{- if .HealthcheckFile }}
+
+mkdir -p {{ .HealthcheckDir }}{{ dir .Path }}
+echo OK > {{ .HealthcheckDir }}{{ .HealthcheckFile }}
+
+cat > /etc/systemd/system/{{ .Name }}-healthcheck.service <<'EOF'
+[Unit]
+Description=Exoscale Elastic IP {{ .Address }} healthcheck endpoint
+After=network-online.target
+
+[Service]
+ExecStart=/usr/bin/python3 -m http.server {{ .Port }} --directory {{ .HealthcheckDir }}
+Restart=always
+DynamicUser=yes
+
+[Install]
+WantedBy=multi-user.target
+EOF
+systemctl daemon-reload
+systemctl enable --now {{ .Name }}-healthcheck
+{{- end }}
+`
+
+const eipConfigScriptAlpine = `#!/bin/sh
+# Managed Elastic IP {{ .Address }} configuration for Alpine Linux, generated
+# by "exo eip config-script". Run this script as root on every instance the
+# Elastic IP is associated to.
+#
+# Changes:
+#   * /etc/network/interfaces: "lo:{{ .Label }}" interface with the Elastic IP address
+{{- if .HealthcheckFile }}
+#   * /etc/init.d/{{ .Name }}-healthcheck: {{ .Mode }} healthcheck endpoint on port {{ .Port }} (OpenRC service)
+{{- end }}
+#
+# To revert:
+{{- if .HealthcheckFile }}
+#   rc-service {{ .Name }}-healthcheck stop && rc-update del {{ .Name }}-healthcheck
+#   rm -rf /etc/init.d/{{ .Name }}-healthcheck {{ .HealthcheckDir }}
+{{- end }}
+#   ifdown lo:{{ .Label }}, then remove its section from /etc/network/interfaces
+{{- if eq .Mode "https" }}
+#
+# The Elastic IP healthcheck is in "https" mode: the endpoint
+# https://<instance>:{{ .Port }}{{ .Path }} must be served by your application.
+{{- end }}
+
+set -e
+
+cat >> /etc/network/interfaces <<'EOF'
+
+auto lo:{{ .Label }}
+iface lo:{{ .Label }} {{ .Family }} static
+	address {{ .Address }}
+	netmask {{ .PrefixLen }}
+EOF
+ifup lo:{{ .Label }}
+{{- if .HealthcheckFile }}
+
+apk add --no-cache python3
+mkdir -p {{ .HealthcheckDir }}{{ dir .Path }}
+echo OK > {{ .HealthcheckDir }}{{ .HealthcheckFile }}
+
+cat > /etc/init.d/{{ .Name }}-healthcheck <<'EOF'
+#!/sbin/openrc-run
+description="Exoscale Elastic IP {{ .Address }} healthcheck endpoint"
+command=/usr/bin/python3
+command_args="-m http.server {{ .Port }} --directory {{ .HealthcheckDir }}"
+command_background=true
+command_user=nobody
+pidfile=/run/${RC_SVCNAME}.pid
+depend() { need net; }
+EOF
+chmod 755 /etc/init.d/{{ .Name }}-healthcheck
+rc-update add {{ .Name }}-healthcheck default
+rc-service {{ .Name }}-healthcheck start
+{{- end }}
+`
+
+var eipConfigScriptTemplates = map[string]*template.Template{
+	"ubuntu": template.Must(template.New("ubuntu").
+		Funcs(template.FuncMap{"dir": path.Dir}).
+		Parse(eipConfigScriptUbuntu)),
+	"alpine": template.Must(template.New("alpine").
+		Funcs(template.FuncMap{"dir": path.Dir}).
+		Parse(eipConfigScriptAlpine)),
+}
+
+var eipConfigScriptCmd = &cobra.Command{
+	Use:   "config-script IP-ADDRESS|ID",
+	Short: "Generate the instance configuration script of a managed Elastic IP",
+	Long: fmt.Sprintf(`This command generates the shell script configuring a Compute instance to
+receive the traffic of a managed Elastic IP (i.e. an Elastic IP with a
+healthcheck): the script configures the Elastic IP address on the loopback
+interface, and in "tcp" or "http" healthcheck mode sets up a minimal service
+responding to the healthcheck. The generated script documents the changes it
+performs and how to revert them.
+
+Supported operating systems: %s`,
+		strings.Join(eipConfigScriptOSes, ", ")),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		osName, err := cmd.Flags().GetString("os")
+		if err != nil {
+			return err
+		}
+
+		eip, err := getElasticIPByAddressOrID(args[0])
+		if err != nil {
+			return err
+		}
+
+		script, err := eipConfigScript(eip.IPAddress, eip.Healthcheck, osName)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(script)
+
+		return nil
+	},
+}
+
+// eipConfigScript returns the configuration script for the operating system
+// osName of the managed Elastic IP ip with the healthcheck hc.
+func eipConfigScript(ip net.IP, hc *egoscale.Healthcheck, osName string) (string, error) {
+	tpl, ok := eipConfigScriptTemplates[osName]
+	if !ok {
+		return "", fmt.Errorf("unsupported operating system %q (supported: %s)",
+			osName, strings.Join(eipConfigScriptOSes, ", "))
+	}
+
+	if hc == nil || hc.Mode == "" {
+		return "", fmt.Errorf("Elastic IP %s is not managed (no healthcheck configured)", ip) // nolint:golint
+	}
+
+	// Network interface labels are limited to 15 characters, the label of
+	// the loopback interface alias is derived from a hash of the address.
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(ip.String()))
+
+	params := eipConfigScriptParams{
+		Name:           "exoscale-eip-" + strings.NewReplacer(".", "-", ":", "-").Replace(ip.String()),
+		Label:          fmt.Sprintf("eip%08x", h.Sum32()),
+		Address:        ip.String(),
+		Family:         "inet",
+		PrefixLen:      32,
+		Mode:           hc.Mode,
+		Port:           hc.Port,
+		Path:           "/",
+		HealthcheckDir: eipConfigScriptHealthcheckDir,
+	}
+
+	if ip.To4() == nil {
+		params.Family = "inet6"
+		params.PrefixLen = 128
+	}
+
+	switch hc.Mode {
+	case "tcp":
+		params.HealthcheckFile = "/index.html"
+
+	case "http":
+		params.Path = "/" + strings.TrimPrefix(hc.Path, "/")
+		params.HealthcheckFile = params.Path
+		if strings.HasSuffix(params.HealthcheckFile, "/") {
+			params.HealthcheckFile += "index.html"
+		}
+
+	case "https":
+		params.Path = "/" + strings.TrimPrefix(hc.Path, "/")
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, params); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func init() {
+	eipConfigScriptCmd.Flags().String("os", "ubuntu",
+		fmt.Sprintf("instances operating system (%s)", strings.Join(eipConfigScriptOSes, "|")))
+	eipCmd.AddCommand(eipConfigScriptCmd)
+}
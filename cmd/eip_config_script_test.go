@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/exoscale/egoscale"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_eipConfigScript(t *testing.T) {
+	ip := net.ParseIP("198.51.100.1")
+
+	script, err := eipConfigScript(ip, &egoscale.Healthcheck{Mode: "http", Port: 8080, Path: "status/health"}, "ubuntu")
+	require.NoError(t, err)
+	require.Contains(t, script, "cat > /etc/netplan/60-exoscale-eip-198-51-100-1.yaml <<'EOF'")
+	require.Contains(t, script, "        - 198.51.100.1/32\n")
+	require.Contains(t, script, "mkdir -p /var/lib/exoscale-eip/status\n")
+	require.Contains(t, script, "echo OK > /var/lib/exoscale-eip/status/health\n")
+	require.Contains(t, script, "python3 -m http.server 8080 --directory /var/lib/exoscale-eip\n")
+	require.Contains(t, script, "#   systemctl disable --now exoscale-eip-198-51-100-1-healthcheck\n")
+
+	script, err = eipConfigScript(net.ParseIP("2001:db8::1"), &egoscale.Healthcheck{Mode: "https", Port: 443}, "alpine")
+	require.NoError(t, err)
+	require.Contains(t, script, "iface lo:eip")
+	require.Contains(t, script, " inet6 static\n\taddress 2001:db8::1\n\tnetmask 128\n")
+	require.Contains(t, script, "https://<instance>:443/ must be served by your application")
+	require.NotContains(t, script, "openrc-run")
+
+	_, err = eipConfigScript(ip, nil, "ubuntu")
+	require.EqualError(t, err, "Elastic IP 198.51.100.1 is not managed (no healthcheck configured)")
+
+	_, err = eipConfigScript(ip, &egoscale.Healthcheck{Mode: "tcp", Port: 22}, "debian")
+	require.EqualError(t, err, `unsupported operating system "debian" (supported: ubuntu, alpine)`)
+}
@@ -1,19 +1,38 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/exoscale/egoscale"
 	"github.com/spf13/cobra"
 )
 
+// eipRDNSResolverAddress is the address of the public DNS resolver used to
+// check the Elastic IP addresses reverse DNS.
+var eipRDNSResolverAddress = "1.1.1.1:53"
+
+// Elastic IP addresses reverse DNS check statuses.
+const (
+	eipRDNSStatusOK        = "ok"
+	eipRDNSStatusMismatch  = "mismatch"
+	eipRDNSStatusDuplicate = "duplicate"
+	eipRDNSStatusError     = "error"
+)
+
 type eipListItemOutput struct {
-	ID          string `json:"id"`
-	Zone        string `json:"zone"`
-	IPAddress   string `json:"ip_address"`
-	Description string `json:"description"`
-	Managed     bool   `json:"managed"`
+	ID            string `json:"id"`
+	Zone          string `json:"zone"`
+	IPAddress     string `json:"ip_address"`
+	AddressFamily string `json:"address_family"`
+	Description   string `json:"description"`
+	Managed       bool   `json:"managed"`
 }
 
 type eipListOutput []eipListItemOutput
@@ -22,14 +41,48 @@ func (o *eipListOutput) toJSON()  { outputJSON(o) }
 func (o *eipListOutput) toText()  { outputText(o) }
 func (o *eipListOutput) toTable() { outputTable(o) }
 
+type eipListRDNSItemOutput struct {
+	ID             string   `json:"id"`
+	Zone           string   `json:"zone"`
+	IPAddress      string   `json:"ip_address"`
+	ReverseDNS     string   `json:"reverse_dns" outputLabel:"Reverse DNS"`
+	LiveReverseDNS []string `json:"live_reverse_dns" outputLabel:"Live Reverse DNS"`
+	RDNSStatus     string   `json:"rdns_status" outputLabel:"rDNS Status"`
+	RDNSError      string   `json:"rdns_error,omitempty" output:"-"`
+}
+
+type eipListRDNSOutput []eipListRDNSItemOutput
+
+func (o *eipListRDNSOutput) toJSON()  { outputJSON(o) }
+func (o *eipListRDNSOutput) toText()  { outputText(o) }
+func (o *eipListRDNSOutput) toTable() { outputTable(o) }
+
 func init() {
 	eipListCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List Elastic IP addresses",
 		Long: fmt.Sprintf(`This command lists existing Elastic IP addresses.
 
-Supported output template annotations: %s`,
-			strings.Join(outputterTemplateAnnotations(&eipListOutput{}), ", ")),
+Elastic IP addresses are listed across all zones (fetched concurrently)
+unless the "--zone" flag is specified, which can be made explicit in scripts
+using the "--all-zones" flag.
+
+Using the "--check-rdns" flag, the reverse DNS configured for each Elastic IP
+address is compared with the PTR records returned by a public DNS resolver
+(%s), reporting a "%s" status if they differ (e.g. stale records after a
+migration), or a "%s" status if the same reverse DNS is configured for
+several Elastic IP addresses across zones. Failing DNS lookups are reported
+with an "%s" status without aborting the listing.
+
+Supported output template annotations: %s
+
+Supported output template annotations (--check-rdns): %s`,
+			eipRDNSResolverAddress,
+			eipRDNSStatusMismatch,
+			eipRDNSStatusDuplicate,
+			eipRDNSStatusError,
+			strings.Join(outputterTemplateAnnotations(&eipListOutput{}), ", "),
+			strings.Join(outputterTemplateAnnotations(&eipListRDNSOutput{}), ", ")),
 		Aliases: gListAlias,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			zone, err := cmd.Flags().GetString("zone")
@@ -37,54 +90,204 @@ Supported output template annotations: %s`,
 				return err
 			}
 
-			return output(listEIP(zone))
+			allZones, err := cmd.Flags().GetBool("all-zones")
+			if err != nil {
+				return err
+			}
+
+			if allZones && zone != "" {
+				return errors.New(`"--all-zones" and "--zone" flags are mutually exclusive`)
+			}
+
+			checkRDNS, err := cmd.Flags().GetBool("check-rdns")
+			if err != nil {
+				return err
+			}
+
+			return output(listEIP(zone, checkRDNS))
 		},
 	}
 
 	eipListCmd.Flags().StringP("zone", "z", "", "Show IPs from given zone")
+	eipListCmd.Flags().Bool("all-zones", false, "Show IPs from all zones")
+	eipListCmd.Flags().Bool("check-rdns", false, "Check the configured reverse DNS against live DNS PTR records")
 	eipCmd.AddCommand(eipListCmd)
 }
 
-func listEIP(zone string) (outputter, error) {
-	out := eipListOutput{}
-
+func listEIP(zone string, checkRDNS bool) (outputter, error) {
 	zones, err := cs.ListWithContext(gContext, &egoscale.Zone{})
 	if err != nil {
 		return nil, err
 	}
 
+	zonesByName := make(map[string]*egoscale.Zone)
 	for _, z := range zones {
 		if zone != "" && z.(*egoscale.Zone).Name != zone {
 			continue
 		}
+		zonesByName[z.(*egoscale.Zone).Name] = z.(*egoscale.Zone)
+	}
 
-		req := egoscale.IPAddress{
-			ZoneID:    z.(*egoscale.Zone).ID,
-			IsElastic: true,
-		}
+	zoneNames := make([]string, 0, len(zonesByName))
+	for name := range zonesByName {
+		zoneNames = append(zoneNames, name)
+	}
+
+	var (
+		eips   = make([]*egoscale.IPAddress, 0)
+		eipsMu sync.Mutex
+	)
 
-		ips, err := cs.ListWithContext(gContext, &req)
+	err = forEachZone(zoneNames, func(zoneName string) error {
+		ips, err := cs.ListWithContext(gContext, &egoscale.IPAddress{
+			ZoneID:    zonesByName[zoneName].ID,
+			IsElastic: true,
+		})
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("unable to list Elastic IP addresses in zone %s: %v", zoneName, err)
 		}
 
+		eipsMu.Lock()
 		for _, ip := range ips {
 			eip := ip.(*egoscale.IPAddress)
+			eip.ZoneName = zoneName
+			eips = append(eips, eip)
+		}
+		eipsMu.Unlock()
 
-			o := eipListItemOutput{
-				Description: eip.Description,
-				ID:          eip.ID.String(),
-				IPAddress:   eip.IPAddress.String(),
-				Zone:        z.(*egoscale.Zone).Name,
-			}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			if eip.Healthcheck != nil {
-				o.Managed = true
-			}
+	sort.Slice(eips, func(i, j int) bool {
+		if eips[i].ZoneName != eips[j].ZoneName {
+			return eips[i].ZoneName < eips[j].ZoneName
+		}
+		return eips[i].IPAddress.String() < eips[j].IPAddress.String()
+	})
+
+	if checkRDNS {
+		out := make(eipListRDNSOutput, len(eips))
+
+		var wg sync.WaitGroup
+		for i := range eips {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				out[i] = checkEIPReverseDNS(gContext, eips[i])
+			}(i)
+		}
+		wg.Wait()
 
-			out = append(out, o)
+		eipRDNSFlagDuplicates(out)
+
+		return &out, nil
+	}
+
+	out := make(eipListOutput, len(eips))
+	for i, eip := range eips {
+		out[i] = eipListItemOutput{
+			AddressFamily: eipAddressFamily(eip.IPAddress),
+			Description:   eip.Description,
+			ID:            eip.ID.String(),
+			IPAddress:     eip.IPAddress.String(),
+			Managed:       eip.Healthcheck != nil,
+			Zone:          eip.ZoneName,
 		}
 	}
 
 	return &out, nil
 }
+
+// checkEIPReverseDNS compares the reverse DNS configured for the Elastic IP
+// address with the PTR records returned by a public DNS resolver. Failures
+// are reported in the returned output instead of an error, so that a single
+// failing lookup doesn't abort the listing.
+func checkEIPReverseDNS(ctx context.Context, eip *egoscale.IPAddress) eipListRDNSItemOutput {
+	out := eipListRDNSItemOutput{
+		ID:             eip.ID.String(),
+		Zone:           eip.ZoneName,
+		IPAddress:      eip.IPAddress.String(),
+		LiveReverseDNS: []string{},
+	}
+
+	res, err := cs.RequestWithContext(ctx, &egoscale.QueryReverseDNSForPublicIPAddress{ID: eip.ID})
+	if err != nil {
+		out.RDNSStatus = eipRDNSStatusError
+		out.RDNSError = fmt.Sprintf("unable to retrieve configured reverse DNS: %s", err)
+		fmt.Fprintf(os.Stderr, "warning: %s: %s\n", out.IPAddress, out.RDNSError)
+		return out
+	}
+	if rdns := res.(*egoscale.IPAddress).ReverseDNS; len(rdns) > 0 {
+		out.ReverseDNS = rdns[0].DomainName
+	}
+
+	resolver := net.Resolver{
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, eipRDNSResolverAddress)
+		},
+		PreferGo: true,
+	}
+
+	names, err := resolver.LookupAddr(ctx, out.IPAddress)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			out.RDNSStatus = eipRDNSStatusError
+			out.RDNSError = fmt.Sprintf("PTR lookup failed: %s", err)
+			fmt.Fprintf(os.Stderr, "warning: %s: %s\n", out.IPAddress, out.RDNSError)
+			return out
+		}
+	}
+	for _, name := range names {
+		out.LiveReverseDNS = append(out.LiveReverseDNS, strings.TrimSuffix(name, "."))
+	}
+
+	out.RDNSStatus = eipRDNSStatus(out.ReverseDNS, out.LiveReverseDNS)
+
+	return out
+}
+
+// eipRDNSFlagDuplicates sets the "duplicate" status of the Elastic IP
+// addresses sharing the same configured reverse DNS, which is expected to be
+// unique across zones.
+func eipRDNSFlagDuplicates(out eipListRDNSOutput) {
+	count := make(map[string]int)
+	for _, eip := range out {
+		if eip.ReverseDNS != "" {
+			count[strings.ToLower(strings.TrimSuffix(eip.ReverseDNS, "."))]++
+		}
+	}
+
+	for i, eip := range out {
+		if eip.RDNSStatus == eipRDNSStatusOK && count[strings.ToLower(strings.TrimSuffix(eip.ReverseDNS, "."))] > 1 {
+			out[i].RDNSStatus = eipRDNSStatusDuplicate
+		}
+	}
+}
+
+// eipRDNSStatus returns the reverse DNS check status of an Elastic IP
+// address given its configured reverse DNS and its live PTR records.
+func eipRDNSStatus(configured string, live []string) string {
+	normalize := func(name string) string {
+		return strings.ToLower(strings.TrimSuffix(name, "."))
+	}
+
+	if configured == "" {
+		if len(live) == 0 {
+			return eipRDNSStatusOK
+		}
+		return eipRDNSStatusMismatch
+	}
+
+	for _, name := range live {
+		if normalize(name) == normalize(configured) {
+			return eipRDNSStatusOK
+		}
+	}
+
+	return eipRDNSStatusMismatch
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_eipRDNSStatus(t *testing.T) {
+	require.Equal(t, eipRDNSStatusOK, eipRDNSStatus("", nil))
+	require.Equal(t, eipRDNSStatusOK, eipRDNSStatus("www.example.net.", []string{"WWW.example.net"}))
+	require.Equal(t, eipRDNSStatusOK, eipRDNSStatus("www.example.net", []string{"a.example.net", "www.example.net"}))
+	require.Equal(t, eipRDNSStatusMismatch, eipRDNSStatus("www.example.net", nil))
+	require.Equal(t, eipRDNSStatusMismatch, eipRDNSStatus("", []string{"old.example.net"}))
+	require.Equal(t, eipRDNSStatusMismatch, eipRDNSStatus("www.example.net", []string{"old.example.net"}))
+}
+
+func Test_eipRDNSFlagDuplicates(t *testing.T) {
+	out := eipListRDNSOutput{
+		{Zone: "ch-gva-2", ReverseDNS: "www.example.net", RDNSStatus: eipRDNSStatusOK},
+		{Zone: "de-fra-1", ReverseDNS: "www.example.net.", RDNSStatus: eipRDNSStatusOK},
+		{Zone: "de-fra-1", ReverseDNS: "api.example.net", RDNSStatus: eipRDNSStatusOK},
+		{Zone: "at-vie-1", RDNSStatus: eipRDNSStatusOK},
+		{Zone: "at-vie-1", RDNSStatus: eipRDNSStatusOK},
+		{Zone: "at-vie-1", ReverseDNS: "api.example.net", RDNSStatus: eipRDNSStatusError},
+	}
+
+	eipRDNSFlagDuplicates(out)
+	require.Equal(t, []string{
+		eipRDNSStatusDuplicate,
+		eipRDNSStatusDuplicate,
+		eipRDNSStatusDuplicate,
+		eipRDNSStatusOK,
+		eipRDNSStatusOK,
+		eipRDNSStatusError,
+	}, func() []string {
+		statuses := make([]string, len(out))
+		for i := range out {
+			statuses[i] = out[i].RDNSStatus
+		}
+		return statuses
+	}())
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+)
+
+var eipPromoteCmd = &cobra.Command{
+	Use:   "promote IP-ADDRESS|ID INSTANCE-NAME|ID",
+	Short: "Route the traffic of a managed Elastic IP to a single Compute instance",
+	Long: `This command routes the traffic of a managed Elastic IP to a single Compute
+instance, for example during the maintenance of the other instances.
+
+The healthcheck of a managed Elastic IP applies to all the instances it is
+associated to, and the API doesn't allow targeting a specific instance: the
+traffic is forced toward the promoted instance by dissociating the Elastic IP
+from the other instances. The command prints the instances the Elastic IP has
+been dissociated from, and the command to run to associate it back.
+`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+		cmdSetForceFromFlag(cmd)
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		eip, err := getElasticIPByAddressOrID(args[0])
+		if err != nil {
+			return err
+		}
+
+		promoted, err := getVirtualMachineByNameOrID(args[1])
+		if err != nil {
+			return err
+		}
+
+		vms, err := getElasticIPInstances(eip)
+		if err != nil {
+			return err
+		}
+
+		others, err := eipPromoteOthers(eip, promoted, vms)
+		if err != nil {
+			return err
+		}
+		if len(others) == 0 {
+			fmt.Printf("Elastic IP %s is only associated to instance %q, nothing to do\n",
+				eip.IPAddress, promoted.Name)
+			return nil
+		}
+
+		names := make([]string, len(others))
+		tasks := make([]task, len(others))
+		for i, vm := range others {
+			names[i] = vm.Name
+
+			req, err := prepareDissociateIP(vm, eip.IPAddress)
+			if err != nil {
+				return err
+			}
+			tasks[i] = task{req, fmt.Sprintf("Dissociating Elastic IP %s from %s", eip.IPAddress, vm.Name)}
+		}
+
+		if !gForce && !askQuestion(fmt.Sprintf(
+			"Are you sure you want to dissociate Elastic IP %s from instance(s) %s?",
+			eip.IPAddress, strings.Join(names, ", "))) {
+			return nil
+		}
+
+		if errs := filterErrors(asyncTasks(tasks)); len(errs) > 0 {
+			return errs[0]
+		}
+
+		fmt.Printf("Elastic IP %s traffic is now routed to instance %q only, dissociated from: %s\n",
+			eip.IPAddress, promoted.Name, strings.Join(names, ", "))
+		fmt.Printf("To revert, run: exo eip associate %s %s\n", eip.IPAddress, strings.Join(names, " "))
+
+		return nil
+	},
+}
+
+// eipPromoteOthers returns the instances among vms the managed Elastic IP eip
+// must be dissociated from to route its traffic to the instance promoted.
+func eipPromoteOthers(
+	eip *egoscale.IPAddress,
+	promoted *egoscale.VirtualMachine,
+	vms []*egoscale.VirtualMachine,
+) ([]*egoscale.VirtualMachine, error) {
+	if eip.Healthcheck == nil || eip.Healthcheck.Mode == "" {
+		return nil, fmt.Errorf("Elastic IP %s is not managed (no healthcheck configured)", eip.IPAddress) // nolint:golint
+	}
+
+	others := make([]*egoscale.VirtualMachine, 0, len(vms))
+	associated := false
+	for _, vm := range vms {
+		if vm.ID.Equal(*promoted.ID) {
+			associated = true
+			continue
+		}
+		others = append(others, vm)
+	}
+
+	if !associated {
+		return nil, fmt.Errorf("Elastic IP %s is not associated to instance %q", eip.IPAddress, promoted.Name) // nolint:golint
+	}
+
+	return others, nil
+}
+
+func init() {
+	eipPromoteCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
+	eipCmd.AddCommand(eipPromoteCmd)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/exoscale/egoscale"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_eipPromoteOthers(t *testing.T) {
+	newVM := func(id, name string) *egoscale.VirtualMachine {
+		return &egoscale.VirtualMachine{ID: egoscale.MustParseUUID(id), Name: name}
+	}
+
+	eip := &egoscale.IPAddress{
+		IPAddress:   net.ParseIP("198.51.100.1"),
+		Healthcheck: &egoscale.Healthcheck{Mode: "tcp", Port: 22},
+	}
+	var (
+		web1 = newVM("a3a6b2a6-2c4a-4b55-9c8b-6a3f1f0b5c01", "web1")
+		web2 = newVM("a3a6b2a6-2c4a-4b55-9c8b-6a3f1f0b5c02", "web2")
+		web3 = newVM("a3a6b2a6-2c4a-4b55-9c8b-6a3f1f0b5c03", "web3")
+	)
+
+	others, err := eipPromoteOthers(eip, web2, []*egoscale.VirtualMachine{web1, web2, web3})
+	require.NoError(t, err)
+	require.Equal(t, []*egoscale.VirtualMachine{web1, web3}, others)
+
+	_, err = eipPromoteOthers(eip, web3, []*egoscale.VirtualMachine{web1, web2})
+	require.EqualError(t, err, `Elastic IP 198.51.100.1 is not associated to instance "web3"`)
+
+	_, err = eipPromoteOthers(&egoscale.IPAddress{IPAddress: eip.IPAddress}, web1, nil)
+	require.Error(t, err)
+}
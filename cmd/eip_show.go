@@ -23,12 +23,13 @@ type eipHealthcheckShowOutput struct {
 }
 
 type eipShowOutput struct {
-	ID          string                    `json:"id"`
-	Zone        string                    `json:"zone"`
-	IPAddress   string                    `json:"ip_address"`
-	Description string                    `json:"description"`
-	Healthcheck *eipHealthcheckShowOutput `json:"healthcheck"`
-	Instances   []string                  `json:"instances"`
+	ID            string                    `json:"id"`
+	Zone          string                    `json:"zone"`
+	IPAddress     string                    `json:"ip_address"`
+	AddressFamily string                    `json:"address_family"`
+	Description   string                    `json:"description"`
+	Healthcheck   *eipHealthcheckShowOutput `json:"healthcheck"`
+	Instances     []string                  `json:"instances"`
 }
 
 func (o *eipShowOutput) toJSON() { outputJSON(o) }
@@ -42,6 +43,7 @@ func (o *eipShowOutput) toTable() {
 	t.Append([]string{"ID", o.ID})
 	t.Append([]string{"Zone", o.Zone})
 	t.Append([]string{"IP Address", o.IPAddress})
+	t.Append([]string{"Address Family", o.AddressFamily})
 	t.Append([]string{"Description", o.Description})
 
 	if o.Healthcheck != nil {
@@ -93,10 +95,11 @@ func showEIP(v string) (outputter, error) {
 	}
 
 	out := eipShowOutput{
-		ID:          eip.ID.String(),
-		Zone:        eip.ZoneName,
-		Description: eip.Description,
-		IPAddress:   eip.IPAddress.String(),
+		AddressFamily: eipAddressFamily(eip.IPAddress),
+		ID:            eip.ID.String(),
+		Zone:          eip.ZoneName,
+		Description:   eip.Description,
+		IPAddress:     eip.IPAddress.String(),
 	}
 
 	if eip.Healthcheck != nil {
@@ -113,11 +116,26 @@ func showEIP(v string) (outputter, error) {
 		}
 	}
 
+	vms, err := getElasticIPInstances(eip)
+	if err != nil {
+		return nil, err
+	}
+	for _, vm := range vms {
+		out.Instances = append(out.Instances, vm.Name)
+	}
+
+	return &out, nil
+}
+
+// getElasticIPInstances returns the Compute instances the Elastic IP eip is
+// associated to.
+func getElasticIPInstances(eip *egoscale.IPAddress) ([]*egoscale.VirtualMachine, error) {
 	res, err := cs.ListWithContext(gContext, &egoscale.VirtualMachine{ZoneID: eip.ZoneID})
 	if err != nil {
 		return nil, err
 	}
 
+	vms := make([]*egoscale.VirtualMachine, 0)
 	for _, item := range res {
 		vm := item.(*egoscale.VirtualMachine)
 		nic := vm.DefaultNic()
@@ -127,10 +145,10 @@ func showEIP(v string) (outputter, error) {
 
 		for _, sIP := range nic.SecondaryIP {
 			if sIP.IPAddress.Equal(eip.IPAddress) {
-				out.Instances = append(out.Instances, vm.Name)
+				vms = append(vms, vm)
 			}
 		}
 	}
 
-	return &out, nil
+	return vms, nil
 }
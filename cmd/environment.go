@@ -40,9 +40,18 @@ variables supported:
   * EXOSCALE_API_KEY: the Exoscale client API key
   * EXOSCALE_API_SECRET: the Exoscale client API secret
   * EXOSCALE_API_ENDPOINT: the Exoscale (Compute) API endpoint to use
+  * EXOSCALE_API_ENVIRONMENT: the Exoscale API environment to use
+  * EXOSCALE_ZONE: the default zone
+  * EXOSCALE_SOS_ENDPOINT: the Exoscale Object Storage (SOS) endpoint to use
 
 Note: to override the current profile API credentials, *both* EXOSCALE_API_KEY
-and EXOSCALE_API_SECRET variables have to be set.
+and EXOSCALE_API_SECRET variables have to be set. In this case no
+configuration file is required: if none exists, the account is entirely
+defined by the environment variables.
+
+The account settings are applied in the following order of precedence:
+command flags (e.g. "--zone"), environment variables, configuration file
+account, defaults.
 `,
 	},
 	)
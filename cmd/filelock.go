@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const fileLockRetryInterval = 50 * time.Millisecond
+
+// fileLockTimeout is the maximum duration to wait for a file lock held by
+// another process to be released.
+var fileLockTimeout = 5 * time.Second
+
+// errFileLocked is returned by tryLockFile() if the lock is already held.
+var errFileLocked = errors.New("file locked")
+
+// lockFile acquires an exclusive advisory lock on the file at path, allowing
+// concurrent CLI invocations to serialize their writes to configuration and
+// cache files. The lock is held on a "<path>.lock" file storing the PID of
+// the lock holder, and acquiring it is retried for up to fileLockTimeout.
+// The returned function releases the lock.
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+
+	lock, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(fileLockTimeout)
+	for {
+		err = tryLockFile(lock)
+		if err == nil {
+			break
+		}
+
+		if !errors.Is(err, errFileLocked) || time.Now().After(deadline) {
+			owner := ""
+			if errors.Is(err, errFileLocked) {
+				owner = fileLockOwner(lock)
+			}
+			_ = lock.Close()
+
+			if owner != "" {
+				return nil, fmt.Errorf("unable to lock %s: locked by another exo process (PID %s)", path, owner)
+			}
+			return nil, fmt.Errorf("unable to lock %s: %w", path, err)
+		}
+
+		time.Sleep(fileLockRetryInterval)
+	}
+
+	if err := lock.Truncate(0); err == nil {
+		_, _ = lock.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+
+	return func() {
+		_ = lock.Truncate(0)
+		_ = unlockFile(lock)
+		_ = lock.Close()
+	}, nil
+}
+
+// fileLockOwner returns the PID of the process holding the lock, or an empty
+// string if it cannot be determined.
+func fileLockOwner(lock *os.File) string {
+	buf := make([]byte, 32)
+	n, _ := lock.ReadAt(buf, 0)
+	pid := strings.TrimSpace(string(buf[:n]))
+
+	if _, err := strconv.Atoi(pid); err != nil {
+		return ""
+	}
+
+	return pid
+}
+
+// writeFileAtomic writes data to the file at path via a temporary file in the
+// same directory renamed once fully written, so that concurrent readers never
+// read a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeFileAtomicFunc(path, perm, func(tmp string) error {
+		return os.WriteFile(tmp, data, perm)
+	})
+}
+
+// writeFileAtomicFunc is similar to writeFileAtomic, the temporary file
+// content being written by the function write.
+func writeFileAtomicFunc(path string, perm os.FileMode, write func(tmp string) error) error {
+	// The temporary file keeps the target file extension (or lack thereof),
+	// as some writers (e.g. viper) infer the file format from it.
+	ext := filepath.Ext(path)
+	pattern := strings.TrimSuffix(filepath.Base(path), ext) + ".*.tmp" + ext
+	if ext == "" {
+		pattern = filepath.Base(path) + "-*-tmp"
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), pattern)
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_ = f.Close()
+
+	if err := os.Chmod(tmp, perm); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err := write(tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lockFile_concurrentWriters(t *testing.T) {
+	const (
+		writers = 16
+		writes  = 20
+	)
+
+	file := filepath.Join(t.TempDir(), "counter.txt")
+	require.NoError(t, writeFileAtomic(file, []byte("0\n"), 0o600))
+
+	var (
+		wg       sync.WaitGroup
+		errs     = make(chan error, writers*writes)
+		done     = make(chan struct{})
+		readerWg sync.WaitGroup
+	)
+
+	// Readers must never observe a partially written file.
+	readerWg.Add(1)
+	go func() {
+		defer readerWg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !strings.HasSuffix(string(data), "\n") {
+				errs <- os.ErrInvalid
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < writes; j++ {
+				unlock, err := lockFile(file)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				data, err := os.ReadFile(file)
+				if err == nil {
+					var n int
+					if n, err = strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+						err = writeFileAtomic(file, []byte(strconv.Itoa(n+1)+"\n"), 0o600)
+					}
+				}
+				unlock()
+
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	readerWg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, strconv.Itoa(writers*writes)+"\n", string(data))
+
+	// No temporary files must be left behind.
+	entries, err := os.ReadDir(filepath.Dir(file))
+	require.NoError(t, err)
+	for _, e := range entries {
+		require.False(t, strings.Contains(e.Name(), ".tmp"), e.Name())
+	}
+}
+
+func Test_lockFile_timeout(t *testing.T) {
+	timeout := fileLockTimeout
+	fileLockTimeout = 200 * time.Millisecond
+	defer func() { fileLockTimeout = timeout }()
+
+	file := filepath.Join(t.TempDir(), "config.toml")
+
+	unlock, err := lockFile(file)
+	require.NoError(t, err)
+	defer unlock()
+
+	_, err = lockFile(file)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "locked by another exo process (PID "+strconv.Itoa(os.Getpid())+")")
+}
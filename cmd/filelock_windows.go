@@ -0,0 +1,35 @@
+//go:build windows
+// +build windows
+
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// fileLockOffset is the offset of the byte range locked in the lock file:
+// Windows locks are mandatory, locking a range beyond the lock file content
+// keeps the lock holder PID readable by the other processes.
+const fileLockOffset = 1 << 30
+
+func tryLockFile(f *os.File) error {
+	err := windows.LockFileEx(
+		windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0,
+		1,
+		0,
+		&windows.Overlapped{Offset: fileLockOffset},
+	)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errFileLocked
+	}
+	return err
+}
+
+func unlockFile(f *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &windows.Overlapped{Offset: fileLockOffset})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/exoscale/egoscale"
 	"github.com/spf13/cobra"
@@ -14,6 +15,24 @@ var (
 	defaultCIDR6 = egoscale.MustParseCIDR("::/0")
 )
 
+// defaultSecurityGroupName is the name of the Security Group created for
+// every organization, in which instances are placed when created without
+// any Security Group specified.
+const defaultSecurityGroupName = "default"
+
+// firewallDefaultsFlag is the flag required to alter the default Security
+// Group in a way likely to break the behaviors depending on it.
+const firewallDefaultsFlag = "i-understand-this-breaks-defaults"
+
+// defaultSecurityGroupDependents lists the behaviors depending on the default
+// Security Group.
+var defaultSecurityGroupDependents = []string{
+	"Compute instances created without any Security Group are placed in it",
+	"Instance Pools and SKS Nodepools created without any Security Group have their members placed in it",
+	"its ingress rules (e.g. SSH, ping) define how such instances can be reached",
+	"rules of other Security Groups referencing it allow traffic from its members",
+}
+
 var firewallCmd = &cobra.Command{
 	Use:   "firewall",
 	Short: "Security Groups management",
@@ -87,6 +106,37 @@ func getSecurityGroupByNameOrID(v string) (*egoscale.SecurityGroup, error) {
 	}
 }
 
+// isDefaultSecurityGroup returns true if sg is the default Security Group.
+func isDefaultSecurityGroup(sg *egoscale.SecurityGroup) bool {
+	return sg.Name == defaultSecurityGroupName
+}
+
+// checkDefaultSecurityGroupChange returns an error describing the behaviors
+// depending on the default Security Group if sg is the default Security Group
+// and the user didn't explicitly acknowledge it using the firewallDefaultsFlag
+// flag.
+func checkDefaultSecurityGroupChange(cmd *cobra.Command, sg *egoscale.SecurityGroup, action string) error {
+	if !isDefaultSecurityGroup(sg) {
+		return nil
+	}
+
+	acknowledged, err := cmd.Flags().GetBool(firewallDefaultsFlag)
+	if err != nil {
+		return err
+	}
+
+	if acknowledged {
+		return nil
+	}
+
+	return fmt.Errorf("refusing to %s the %q Security Group, the following behaviors depend on it:\n  * %s\n"+
+		"Use the \"--%s\" flag to proceed anyway, and \"exo firewall restore-default\" to restore its conventional rules",
+		action,
+		defaultSecurityGroupName,
+		strings.Join(defaultSecurityGroupDependents, "\n  * "),
+		firewallDefaultsFlag)
+}
+
 func getSecurityGroupIDs(params []string) ([]egoscale.UUID, error) {
 	ids := make([]egoscale.UUID, len(params))
 
@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+)
+
+// firewallSensitivePorts are the ports of services which should never be
+// exposed to the whole Internet.
+var firewallSensitivePorts = map[uint16]string{
+	22:   "SSH",
+	3306: "MySQL",
+	3389: "RDP",
+}
+
+var firewallFindingSeverities = []string{"low", "medium", "high"}
+
+func firewallFindingSeverityLevel(severity string) int {
+	for i, s := range firewallFindingSeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// firewallRule is the normalized representation of a Security Group rule
+// used by the rules analysis.
+type firewallRule struct {
+	ID            string
+	Direction     string
+	Protocol      string
+	CIDR          *net.IPNet
+	SecurityGroup string
+	StartPort     uint16
+	EndPort       uint16
+	ICMPType      int
+	ICMPCode      int
+}
+
+func (r *firewallRule) source() string {
+	if r.CIDR != nil {
+		return "CIDR " + r.CIDR.String()
+	}
+	return "SG " + r.SecurityGroup
+}
+
+func (r *firewallRule) hasPorts() bool {
+	return r.Protocol == "tcp" || r.Protocol == "udp"
+}
+
+func (r *firewallRule) isICMP() bool {
+	return r.Protocol == "icmp" || r.Protocol == "icmpv6"
+}
+
+func (r *firewallRule) equals(o *firewallRule) bool {
+	return r.Direction == o.Direction &&
+		r.Protocol == o.Protocol &&
+		r.source() == o.source() &&
+		r.StartPort == o.StartPort &&
+		r.EndPort == o.EndPort &&
+		r.ICMPType == o.ICMPType &&
+		r.ICMPCode == o.ICMPCode
+}
+
+// covers returns true if the rule r matches all the traffic matched by the
+// rule o.
+func (r *firewallRule) covers(o *firewallRule) bool {
+	if r.Direction != o.Direction {
+		return false
+	}
+
+	// Rules matching another Security Group members only cover rules
+	// referencing the same Security Group.
+	if r.CIDR == nil || o.CIDR == nil {
+		if r.CIDR != nil || o.CIDR != nil || r.SecurityGroup != o.SecurityGroup {
+			return false
+		}
+	} else {
+		rOnes, rBits := r.CIDR.Mask.Size()
+		oOnes, oBits := o.CIDR.Mask.Size()
+		if rBits != oBits || rOnes > oOnes || !r.CIDR.Contains(o.CIDR.IP) {
+			return false
+		}
+	}
+
+	if r.Protocol == "all" {
+		return true
+	}
+	if r.Protocol != o.Protocol {
+		return false
+	}
+
+	switch {
+	case r.hasPorts():
+		return r.StartPort <= o.StartPort && r.EndPort >= o.EndPort
+
+	case r.isICMP():
+		return r.ICMPType == -1 ||
+			(r.ICMPType == o.ICMPType && (r.ICMPCode == -1 || r.ICMPCode == o.ICMPCode))
+	}
+
+	return true
+}
+
+// exposedSensitivePorts returns the sensitive ports exposed to the whole
+// Internet by the rule, sorted.
+func (r *firewallRule) exposedSensitivePorts() []uint16 {
+	ports := make([]uint16, 0)
+
+	if r.Direction != "ingress" || r.CIDR == nil {
+		return ports
+	}
+	if ones, _ := r.CIDR.Mask.Size(); ones != 0 {
+		return ports
+	}
+	if r.Protocol != "all" && r.Protocol != "tcp" && r.Protocol != "udp" {
+		return ports
+	}
+
+	for port := range firewallSensitivePorts {
+		if r.Protocol == "all" || (r.StartPort <= port && r.EndPort >= port) {
+			ports = append(ports, port)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	return ports
+}
+
+type firewallFinding struct {
+	Severity      string `json:"severity"`
+	Type          string `json:"type"`
+	RuleID        string `json:"rule_id"`
+	RelatedRuleID string `json:"related_rule_id,omitempty"`
+	Description   string `json:"description"`
+}
+
+type firewallAnalyzeOutput []firewallFinding
+
+func (o *firewallAnalyzeOutput) toJSON()  { outputJSON(o) }
+func (o *firewallAnalyzeOutput) toText()  { outputText(o) }
+func (o *firewallAnalyzeOutput) toTable() { outputTable(o) }
+
+// analyzeFirewallRules returns the findings of the analysis of a Security
+// Group rules, sorted by decreasing severity:
+//   - exact duplicate rules (low)
+//   - rules fully covered by a broader rule (medium)
+//   - sensitive ports exposed to the whole Internet (high)
+func analyzeFirewallRules(rules []*firewallRule) []firewallFinding {
+	findings := make([]firewallFinding, 0)
+
+	for i, r := range rules {
+		// Only the first occurrence of duplicate rules is considered when
+		// looking for broader rules.
+		duplicate := false
+		for _, o := range rules[:i] {
+			if o.equals(r) {
+				findings = append(findings, firewallFinding{
+					Severity:      "low",
+					Type:          "duplicate",
+					RuleID:        r.ID,
+					RelatedRuleID: o.ID,
+					Description:   fmt.Sprintf("%s rule is identical to rule %s", r.Direction, o.ID),
+				})
+				duplicate = true
+				break
+			}
+		}
+
+		if !duplicate {
+			for _, o := range rules {
+				if o == r || o.equals(r) || !o.covers(r) {
+					continue
+				}
+
+				findings = append(findings, firewallFinding{
+					Severity:      "medium",
+					Type:          "shadowed",
+					RuleID:        r.ID,
+					RelatedRuleID: o.ID,
+					Description: fmt.Sprintf("%s rule (%s) is covered by broader rule %s (%s)",
+						r.Direction, r.source(), o.ID, o.source()),
+				})
+				break
+			}
+		}
+
+		for _, port := range r.exposedSensitivePorts() {
+			findings = append(findings, firewallFinding{
+				Severity: "high",
+				Type:     "exposure",
+				RuleID:   r.ID,
+				Description: fmt.Sprintf("%s (port %d) exposed to the Internet (%s)",
+					firewallSensitivePorts[port], port, r.source()),
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return firewallFindingSeverityLevel(findings[i].Severity) > firewallFindingSeverityLevel(findings[j].Severity)
+	})
+
+	return findings
+}
+
+func firewallRulesFromSecurityGroup(sg *egoscale.SecurityGroup) []*firewallRule {
+	rules := make([]*firewallRule, 0)
+
+	add := func(direction string, rule egoscale.IngressRule) {
+		r := firewallRule{
+			ID:            rule.RuleID.String(),
+			Direction:     direction,
+			Protocol:      rule.Protocol,
+			SecurityGroup: rule.SecurityGroupName,
+			StartPort:     rule.StartPort,
+			EndPort:       rule.EndPort,
+			ICMPType:      rule.IcmpType,
+			ICMPCode:      rule.IcmpCode,
+		}
+		if rule.CIDR != nil {
+			r.CIDR = &rule.CIDR.IPNet
+		}
+		rules = append(rules, &r)
+	}
+
+	for _, rule := range sg.IngressRule {
+		add("ingress", rule)
+	}
+	for _, rule := range sg.EgressRule {
+		add("egress", (egoscale.IngressRule)(rule))
+	}
+
+	return rules
+}
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "analyze NAME|ID",
+		Short: "Analyze a Security Group rules",
+		Long: fmt.Sprintf(`This command analyzes a Security Group rules, reporting:
+
+  * exact duplicate rules (severity: low)
+  * rules fully covered by a broader rule, e.g. 10.0.0.0/8 covering
+    10.1.0.0/16 on the same port and protocol (severity: medium)
+  * sensitive ports (%s) exposed to the whole Internet (severity: high)
+
+Using the "--fail-on" flag, the command exits with a non-zero status if
+findings of the specified severity or above are reported, e.g. for CI policy
+checks.
+
+Supported output template annotations: %s`,
+			func() string {
+				ports := make([]string, 0, len(firewallSensitivePorts))
+				for port, name := range firewallSensitivePorts {
+					ports = append(ports, fmt.Sprintf("%s/%d", name, port))
+				}
+				sort.Strings(ports)
+				return strings.Join(ports, ", ")
+			}(),
+			strings.Join(outputterTemplateAnnotations(&firewallFinding{}), ", ")),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				cmdExitOnUsageError(cmd, "invalid arguments")
+			}
+
+			failOn, err := cmd.Flags().GetString("fail-on")
+			if err != nil {
+				return err
+			}
+			if failOn != "" && firewallFindingSeverityLevel(failOn) < 0 {
+				return fmt.Errorf("invalid severity %q, supported values are: %s",
+					failOn, strings.Join(firewallFindingSeverities, ", "))
+			}
+
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			failOn, err := cmd.Flags().GetString("fail-on")
+			if err != nil {
+				return err
+			}
+
+			sg, err := getSecurityGroupByNameOrID(args[0])
+			if err != nil {
+				return err
+			}
+
+			findings := firewallAnalyzeOutput(analyzeFirewallRules(firewallRulesFromSecurityGroup(sg)))
+			if err := output(&findings, nil); err != nil {
+				return err
+			}
+
+			if failOn != "" {
+				failed := 0
+				for _, f := range findings {
+					if firewallFindingSeverityLevel(f.Severity) >= firewallFindingSeverityLevel(failOn) {
+						failed++
+					}
+				}
+				if failed > 0 {
+					return fmt.Errorf("%d finding(s) of severity %s or above", failed, failOn)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("fail-on", "",
+		fmt.Sprintf("exit with an error if findings of this severity or above are reported (%s)",
+			strings.Join(firewallFindingSeverities, "|")))
+
+	firewallCmd.AddCommand(cmd)
+}
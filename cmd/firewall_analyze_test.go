@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testFirewallRule(id, direction, protocol, cidr string, startPort, endPort uint16) *firewallRule {
+	r := firewallRule{
+		ID:        id,
+		Direction: direction,
+		Protocol:  protocol,
+		StartPort: startPort,
+		EndPort:   endPort,
+	}
+	if cidr != "" {
+		_, r.CIDR, _ = net.ParseCIDR(cidr)
+	}
+	return &r
+}
+
+func Test_analyzeFirewallRules(t *testing.T) {
+	findings := analyzeFirewallRules([]*firewallRule{
+		testFirewallRule("1", "ingress", "tcp", "10.0.0.0/8", 80, 80),
+		testFirewallRule("2", "ingress", "tcp", "10.1.0.0/16", 80, 80),
+		testFirewallRule("3", "ingress", "tcp", "10.0.0.0/8", 80, 80),
+		testFirewallRule("4", "ingress", "tcp", "0.0.0.0/0", 20, 25),
+		testFirewallRule("5", "ingress", "tcp", "10.1.0.0/16", 443, 443),
+		testFirewallRule("6", "egress", "tcp", "10.1.0.0/16", 80, 80),
+		testFirewallRule("7", "ingress", "udp", "10.1.0.0/16", 80, 80),
+	})
+
+	require.Equal(t, []firewallFinding{
+		{
+			Severity:    "high",
+			Type:        "exposure",
+			RuleID:      "4",
+			Description: "SSH (port 22) exposed to the Internet (CIDR 0.0.0.0/0)",
+		},
+		{
+			Severity:      "medium",
+			Type:          "shadowed",
+			RuleID:        "2",
+			RelatedRuleID: "1",
+			Description:   "ingress rule (CIDR 10.1.0.0/16) is covered by broader rule 1 (CIDR 10.0.0.0/8)",
+		},
+		{
+			Severity:      "low",
+			Type:          "duplicate",
+			RuleID:        "3",
+			RelatedRuleID: "1",
+			Description:   "ingress rule is identical to rule 1",
+		},
+	}, findings)
+}
+
+func Test_firewallRule_covers(t *testing.T) {
+	all := testFirewallRule("1", "ingress", "all", "0.0.0.0/0", 0, 0)
+	require.True(t, all.covers(testFirewallRule("2", "ingress", "udp", "192.168.0.0/24", 53, 53)))
+	require.False(t, all.covers(testFirewallRule("3", "ingress", "tcp", "::/0", 22, 22)))
+
+	sg := &firewallRule{ID: "4", Direction: "ingress", Protocol: "tcp", SecurityGroup: "web", StartPort: 1, EndPort: 1024}
+	require.True(t, sg.covers(&firewallRule{ID: "5", Direction: "ingress", Protocol: "tcp", SecurityGroup: "web", StartPort: 80, EndPort: 80}))
+	require.False(t, sg.covers(&firewallRule{ID: "6", Direction: "ingress", Protocol: "tcp", SecurityGroup: "db", StartPort: 80, EndPort: 80}))
+
+	icmp := &firewallRule{ID: "7", Direction: "ingress", Protocol: "icmp", CIDR: all.CIDR, ICMPType: -1, ICMPCode: -1}
+	require.True(t, icmp.covers(&firewallRule{ID: "8", Direction: "ingress", Protocol: "icmp", CIDR: all.CIDR, ICMPType: 8, ICMPCode: 0}))
+}
@@ -8,18 +8,16 @@ import (
 )
 
 var firewallDeleteCmd = &cobra.Command{
-	Use:     "delete NAME|ID",
-	Short:   "Delete a Security Group",
-	Aliases: gDeleteAlias,
+	Use:               "delete NAME|ID",
+	Short:             "Delete a Security Group",
+	Aliases:           gDeleteAlias,
+	ValidArgsFunction: completeResourceNames("security-group", -1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return cmd.Usage()
 		}
 
-		force, err := cmd.Flags().GetBool("force")
-		if err != nil {
-			return err
-		}
+		cmdSetForceFromFlag(cmd)
 
 		tasks := make([]task, 0, len(args))
 		for _, arg := range args {
@@ -28,8 +26,18 @@ var firewallDeleteCmd = &cobra.Command{
 				return err
 			}
 
+			if err = checkDefaultSecurityGroupChange(cmd, sg, "delete"); err != nil {
+				return err
+			}
+
+			err = checkResourceReferences(securityGroupReferencesFinder, allZones,
+				"Security Group", sg.Name, sg.ID.String(), forced())
+			if err != nil {
+				return err
+			}
+
 			q := fmt.Sprintf("Are you sure you want to delete the Security Group %q?", sg.Name)
-			if !force && !askQuestion(q) {
+			if !askQuestion(q) {
 				continue
 			}
 
@@ -51,5 +59,6 @@ var firewallDeleteCmd = &cobra.Command{
 
 func init() {
 	firewallDeleteCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
+	firewallDeleteCmd.Flags().Bool(firewallDefaultsFlag, false, "Allow deleting the default Security Group")
 	firewallCmd.AddCommand(firewallDeleteCmd)
 }
@@ -0,0 +1,530 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/exoscale/cli/table"
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+)
+
+// Supported Security Group rules import formats.
+const (
+	firewallImportFormatAWS = "aws"
+	firewallImportFormatCSV = "csv"
+)
+
+// firewallImportProtocols maps the AWS protocol names and numbers to the
+// Security Group rules protocols.
+var firewallImportProtocols = map[string]string{
+	"-1":     "all",
+	"all":    "all",
+	"tcp":    "tcp",
+	"6":      "tcp",
+	"udp":    "udp",
+	"17":     "udp",
+	"icmp":   "icmp",
+	"1":      "icmp",
+	"icmpv6": "icmpv6",
+	"58":     "icmpv6",
+	"ah":     "ah",
+	"51":     "ah",
+	"esp":    "esp",
+	"50":     "esp",
+	"gre":    "gre",
+	"47":     "gre",
+	"ipip":   "ipip",
+	"4":      "ipip",
+}
+
+// firewallImportRule represents a Security Group rule translated from a
+// foreign format.
+type firewallImportRule struct {
+	direction   string
+	protocol    string
+	startPort   uint16
+	endPort     uint16
+	icmpType    int
+	icmpCode    int
+	cidr        *egoscale.CIDR
+	description string
+}
+
+func (r *firewallImportRule) ports() string {
+	switch r.protocol {
+	case "tcp", "udp":
+		if r.startPort == r.endPort {
+			return fmt.Sprint(r.startPort)
+		}
+		return fmt.Sprintf("%d-%d", r.startPort, r.endPort)
+
+	case "icmp", "icmpv6":
+		return fmt.Sprintf("%d:%d", r.icmpType, r.icmpCode)
+	}
+
+	return ""
+}
+
+// matches returns true if the existing Security Group rule in is
+// equivalent to the rule.
+func (r *firewallImportRule) matches(in *egoscale.IngressRule) bool {
+	if in.CIDR == nil || !in.CIDR.Equal(*r.cidr) || !strings.EqualFold(in.Protocol, r.protocol) {
+		return false
+	}
+
+	switch r.protocol {
+	case "tcp", "udp":
+		return in.StartPort == r.startPort && in.EndPort == r.endPort
+	case "icmp", "icmpv6":
+		return in.IcmpType == r.icmpType && in.IcmpCode == r.icmpCode
+	}
+
+	return true
+}
+
+// authorizeRequest returns the API request creating the rule in the
+// specified Security Group.
+func (r *firewallImportRule) authorizeRequest(sg *egoscale.SecurityGroup) egoscale.AuthorizeSecurityGroupIngress {
+	req := egoscale.AuthorizeSecurityGroupIngress{
+		SecurityGroupID: sg.ID,
+		Protocol:        r.protocol,
+		CIDRList:        []egoscale.CIDR{*r.cidr},
+		Description:     r.description,
+	}
+
+	switch r.protocol {
+	case "tcp", "udp":
+		req.StartPort, req.EndPort = r.startPort, r.endPort
+	case "icmp", "icmpv6":
+		req.IcmpType, req.IcmpCode = r.icmpType, r.icmpCode
+	}
+
+	return req
+}
+
+// firewallImport represents the result of the translation of Security Group
+// rules from a foreign format: the translated rules, and the description of
+// the constructs that couldn't be translated.
+type firewallImport struct {
+	rules   []*firewallImportRule
+	skipped []string
+}
+
+// parseFirewallImportPorts sets the ports (or ICMP type and code for ICMP
+// rules) of the rule r from the AWS FromPort and ToPort values.
+func parseFirewallImportPorts(r *firewallImportRule, from, to int) error {
+	switch r.protocol {
+	case "tcp", "udp":
+		if from <= 0 && (to <= 0 || to == 65535) {
+			from, to = 1, 65535
+		}
+		if from < 1 || from > 65535 || to < from || to > 65535 {
+			return fmt.Errorf("invalid port range %d-%d", from, to)
+		}
+		r.startPort, r.endPort = uint16(from), uint16(to)
+
+	case "icmp", "icmpv6":
+		// AWS uses -1 to mean any ICMP type or code, as Exoscale does.
+		if from < -1 || from > 255 || to < -1 || to > 255 {
+			return fmt.Errorf("invalid ICMP type/code %d:%d", from, to)
+		}
+		r.icmpType, r.icmpCode = from, to
+	}
+
+	return nil
+}
+
+type awsSecurityGroupsExport struct {
+	SecurityGroups []awsSecurityGroup `json:"SecurityGroups"`
+}
+
+type awsSecurityGroup struct {
+	GroupID             string             `json:"GroupId"`
+	GroupName           string             `json:"GroupName"`
+	IPPermissions       []awsIPPermissions `json:"IpPermissions"`
+	IPPermissionsEgress []awsIPPermissions `json:"IpPermissionsEgress"`
+}
+
+type awsIPPermissions struct {
+	IPProtocol string `json:"IpProtocol"`
+	FromPort   *int   `json:"FromPort"`
+	ToPort     *int   `json:"ToPort"`
+	IPRanges   []struct {
+		CidrIP      string `json:"CidrIp"`
+		Description string `json:"Description"`
+	} `json:"IpRanges"`
+	IPv6Ranges []struct {
+		CidrIPv6    string `json:"CidrIpv6"`
+		Description string `json:"Description"`
+	} `json:"Ipv6Ranges"`
+	PrefixListIDs []struct {
+		PrefixListID string `json:"PrefixListId"`
+	} `json:"PrefixListIds"`
+	UserIDGroupPairs []struct {
+		GroupID   string `json:"GroupId"`
+		GroupName string `json:"GroupName"`
+	} `json:"UserIdGroupPairs"`
+}
+
+// isAllowAll returns true if the permissions allow all the traffic from/to
+// anywhere (over IPv4).
+func (p *awsIPPermissions) isAllowAll() bool {
+	if p.IPProtocol != "-1" {
+		return false
+	}
+
+	for _, r := range p.IPRanges {
+		if r.CidrIP == "0.0.0.0/0" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseFirewallImportAWS translates the rules of the Security Group group
+// (name or ID, optional if the export contains a single Security Group) of
+// an AWS "aws ec2 describe-security-groups" JSON export.
+func parseFirewallImportAWS(data []byte, group string) (*firewallImport, error) {
+	var export awsSecurityGroupsExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("unable to parse AWS Security Groups export: %w", err)
+	}
+
+	var sg *awsSecurityGroup
+	names := make([]string, 0, len(export.SecurityGroups))
+	for i, v := range export.SecurityGroups {
+		names = append(names, v.GroupName)
+		if group == "" || v.GroupName == group || v.GroupID == group {
+			if sg != nil {
+				return nil, fmt.Errorf("multiple Security Groups in the AWS export (%s), use the --aws-group flag",
+					strings.Join(names, ", "))
+			}
+			sg = &export.SecurityGroups[i]
+		}
+	}
+	if sg == nil {
+		if group != "" {
+			return nil, fmt.Errorf("Security Group %q not found in the AWS export", group)
+		}
+		return nil, errors.New("no Security Group found in the AWS export")
+	}
+
+	out := firewallImport{rules: make([]*firewallImportRule, 0), skipped: make([]string, 0)}
+
+	translate := func(direction string, permissions []awsIPPermissions) {
+		for _, p := range permissions {
+			protocol, ok := firewallImportProtocols[strings.ToLower(p.IPProtocol)]
+			if !ok {
+				out.skipped = append(out.skipped, fmt.Sprintf("%s rule: unsupported protocol %q", direction, p.IPProtocol))
+				continue
+			}
+
+			for _, v := range p.PrefixListIDs {
+				out.skipped = append(out.skipped, fmt.Sprintf("%s %s rule: prefix list %s is not supported",
+					direction, protocol, v.PrefixListID))
+			}
+
+			for _, v := range p.UserIDGroupPairs {
+				name := v.GroupName
+				if name == "" {
+					name = v.GroupID
+				}
+				out.skipped = append(out.skipped, fmt.Sprintf("%s %s rule: referenced Security Group %s is not supported",
+					direction, protocol, name))
+			}
+
+			sources := make([][2]string, 0, len(p.IPRanges)+len(p.IPv6Ranges))
+			for _, r := range p.IPRanges {
+				sources = append(sources, [2]string{r.CidrIP, r.Description})
+			}
+			for _, r := range p.IPv6Ranges {
+				sources = append(sources, [2]string{r.CidrIPv6, r.Description})
+			}
+
+			for _, source := range sources {
+				rule := firewallImportRule{direction: direction, protocol: protocol, description: source[1]}
+
+				cidr, err := egoscale.ParseCIDR(source[0])
+				if err != nil {
+					out.skipped = append(out.skipped, fmt.Sprintf("%s %s rule: invalid CIDR %q", direction, protocol, source[0]))
+					continue
+				}
+				rule.cidr = cidr
+
+				from, to := -1, -1
+				if p.FromPort != nil {
+					from = *p.FromPort
+				}
+				if p.ToPort != nil {
+					to = *p.ToPort
+				}
+				if err := parseFirewallImportPorts(&rule, from, to); err != nil {
+					out.skipped = append(out.skipped, fmt.Sprintf("%s %s rule: %s", direction, protocol, err))
+					continue
+				}
+
+				out.rules = append(out.rules, &rule)
+			}
+		}
+	}
+
+	translate("ingress", sg.IPPermissions)
+
+	// AWS Security Groups allow all egress traffic by default using an
+	// explicit rule, whereas Exoscale Security Groups allow all egress
+	// traffic as long as they have no egress rules.
+	allowAllEgress := false
+	for i := range sg.IPPermissionsEgress {
+		if sg.IPPermissionsEgress[i].isAllowAll() {
+			allowAllEgress = true
+			break
+		}
+	}
+	if allowAllEgress {
+		out.skipped = append(out.skipped,
+			"egress rules: all egress traffic is allowed, which is the default without egress rules")
+	} else {
+		translate("egress", sg.IPPermissionsEgress)
+	}
+
+	return &out, nil
+}
+
+// parseFirewallImportCSV translates the rules of a CSV document, each line
+// being a rule in the "direction,protocol,port,source,description" format.
+// The port is either a single port or a range ("START-END") for TCP and UDP
+// rules, and "TYPE:CODE" for ICMP rules (empty for any). The source must be
+// a CIDR. Empty lines, lines starting with "#" and a header line are ignored.
+func parseFirewallImportCSV(data []byte) (*firewallImport, error) {
+	out := firewallImport{rules: make([]*firewallImportRule, 0), skipped: make([]string, 0)}
+
+	for i, text := range strings.Split(string(data), "\n") {
+		line := i + 1
+
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		r := csv.NewReader(strings.NewReader(text))
+		r.TrimLeadingSpace = true
+		record, err := r.Read()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: unable to parse CSV: %w", line, err)
+		}
+
+		if strings.EqualFold(record[0], "direction") {
+			continue
+		}
+
+		if len(record) < 4 || len(record) > 5 {
+			return nil, fmt.Errorf("line %d: expected format direction,protocol,port,source,description", line)
+		}
+
+		rule := firewallImportRule{direction: strings.ToLower(record[0])}
+		if len(record) == 5 {
+			rule.description = record[4]
+		}
+
+		if rule.direction != "ingress" && rule.direction != "egress" {
+			return nil, fmt.Errorf("line %d: invalid direction %q (expected ingress or egress)", line, record[0])
+		}
+
+		protocol, ok := firewallImportProtocols[strings.ToLower(record[1])]
+		if !ok {
+			out.skipped = append(out.skipped, fmt.Sprintf("line %d: unsupported protocol %q", line, record[1]))
+			continue
+		}
+		rule.protocol = protocol
+
+		cidr, err := egoscale.ParseCIDR(record[3])
+		if err != nil {
+			out.skipped = append(out.skipped, fmt.Sprintf("line %d: source %q is not a CIDR", line, record[3]))
+			continue
+		}
+		rule.cidr = cidr
+
+		from, to := -1, -1
+		if port := strings.TrimSpace(record[2]); port != "" && port != "*" {
+			sep := "-"
+			if protocol == "icmp" || protocol == "icmpv6" {
+				sep = ":"
+			}
+
+			parts := strings.SplitN(port, sep, 2)
+			if from, err = strconv.Atoi(parts[0]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid port %q", line, port)
+			}
+			to = from
+			if len(parts) == 2 {
+				if to, err = strconv.Atoi(parts[1]); err != nil {
+					return nil, fmt.Errorf("line %d: invalid port %q", line, port)
+				}
+			} else if sep == ":" {
+				to = -1
+			}
+		}
+		if err := parseFirewallImportPorts(&rule, from, to); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+
+		out.rules = append(out.rules, &rule)
+	}
+
+	return &out, nil
+}
+
+var firewallImportCmd = &cobra.Command{
+	Use:   "import SECURITY-GROUP-NAME|ID FILE",
+	Short: "Import rules into a Security Group",
+	Long: `This command imports the rules defined in a file into a Security Group.
+
+Supported formats (see the "--format" flag):
+
+  * aws: the JSON output of the "aws ec2 describe-security-groups" command.
+    If it contains several Security Groups, the one to import is specified
+    using the "--aws-group" flag.
+  * csv: one rule per line, in the "direction,protocol,port,source,description"
+    format, e.g. "ingress,tcp,8000-8080,10.0.0.0/8,web". For ICMP rules, the
+    port column is "TYPE:CODE". Lines starting with "#" are ignored.
+
+Only rules whose source is a CIDR can be imported: the constructs that can't
+be translated (e.g. AWS prefix lists or references to other Security Groups)
+are reported and skipped. The rules already present in the Security Group are
+skipped as well. The rules to be created are displayed for confirmation before
+being applied.
+`,
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+
+		return nil
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmdSetForceFromFlag(cmd)
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		awsGroup, err := cmd.Flags().GetString("aws-group")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+
+		var imported *firewallImport
+		switch format {
+		case firewallImportFormatAWS:
+			imported, err = parseFirewallImportAWS(data, awsGroup)
+		case firewallImportFormatCSV:
+			imported, err = parseFirewallImportCSV(data)
+		default:
+			return fmt.Errorf("unsupported format %q (supported formats: %s, %s)",
+				format, firewallImportFormatAWS, firewallImportFormatCSV)
+		}
+		if err != nil {
+			return err
+		}
+
+		sg, err := getSecurityGroupByNameOrID(args[0])
+		if err != nil {
+			return err
+		}
+
+		rules := make([]*firewallImportRule, 0, len(imported.rules))
+	next:
+		for _, r := range imported.rules {
+			existing := make([]egoscale.IngressRule, 0)
+			if r.direction == "ingress" {
+				existing = append(existing, sg.IngressRule...)
+			} else {
+				for _, e := range sg.EgressRule {
+					existing = append(existing, egoscale.IngressRule(e))
+				}
+			}
+
+			for i := range existing {
+				if r.matches(&existing[i]) {
+					continue next
+				}
+			}
+			rules = append(rules, r)
+		}
+
+		for _, s := range imported.skipped {
+			fmt.Fprintf(os.Stderr, "warning: skipped %s\n", s)
+		}
+
+		if len(rules) == 0 {
+			fmt.Fprintf(os.Stderr, "No rules to import into Security Group %q\n", sg.Name)
+			return nil
+		}
+
+		sort.SliceStable(rules, func(i, j int) bool { return rules[i].direction > rules[j].direction })
+
+		t := table.NewTable(os.Stdout)
+		t.SetHeader([]string{"Direction", "Protocol", "Ports", "Source", "Description"})
+		for _, r := range rules {
+			t.Append([]string{r.direction, r.protocol, r.ports(), r.cidr.String(), r.description})
+		}
+		t.Render()
+
+		if dryRun {
+			return nil
+		}
+
+		if !askQuestion(fmt.Sprintf("Are you sure you want to add these %d rules to Security Group %q?",
+			len(rules), sg.Name)) {
+			return nil
+		}
+
+		tasks := make([]task, len(rules))
+		for i, r := range rules {
+			tasks[i] = newFirewallRuleTask(
+				r.authorizeRequest(sg),
+				fmt.Sprintf("Add %s %s rule (%s) for %q", r.direction, r.protocol, r.cidr, sg.Name),
+				r.direction == "egress",
+			)
+		}
+
+		resps := asyncTasks(tasks)
+		errs := filterErrors(resps)
+		if len(errs) > 0 {
+			return errs[0]
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	firewallImportCmd.Flags().String("aws-group", "",
+		"name or ID of the Security Group to import if the AWS export contains several Security Groups")
+	firewallImportCmd.Flags().Bool("dry-run", false, "only display the rules to import")
+	firewallImportCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
+	firewallImportCmd.Flags().String("format", firewallImportFormatAWS,
+		fmt.Sprintf("rules file format (%s|%s)", firewallImportFormatAWS, firewallImportFormatCSV))
+	firewallCmd.AddCommand(firewallImportCmd)
+}
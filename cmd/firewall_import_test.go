@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/exoscale/egoscale"
+	"github.com/stretchr/testify/require"
+)
+
+func testFirewallImportRule(direction, protocol, cidr string, start, end uint16, description string) *firewallImportRule {
+	return &firewallImportRule{
+		direction:   direction,
+		protocol:    protocol,
+		startPort:   start,
+		endPort:     end,
+		cidr:        egoscale.MustParseCIDR(cidr),
+		description: description,
+	}
+}
+
+// testFirewallImportWebRules are the expected rules translated from the
+// "web" Security Group of the AWS export sample.
+var testFirewallImportWebRules = []*firewallImportRule{
+	testFirewallImportRule("ingress", "tcp", "0.0.0.0/0", 443, 443, "HTTPS"),
+	testFirewallImportRule("ingress", "tcp", "::/0", 443, 443, "HTTPS"),
+	testFirewallImportRule("ingress", "tcp", "10.0.0.0/8", 8000, 8080, ""),
+	{direction: "ingress", protocol: "icmp", icmpType: 8, icmpCode: -1, cidr: egoscale.MustParseCIDR("192.0.2.0/24"), description: "ping"},
+}
+
+func Test_parseFirewallImportAWS(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/firewall_import/aws-security-groups.json")
+	require.NoError(t, err)
+
+	_, err = parseFirewallImportAWS(data, "")
+	require.EqualError(t, err, "multiple Security Groups in the AWS export (web, db), use the --aws-group flag")
+
+	_, err = parseFirewallImportAWS(data, "unknown")
+	require.Error(t, err)
+
+	imported, err := parseFirewallImportAWS(data, "web")
+	require.NoError(t, err)
+	require.Equal(t, testFirewallImportWebRules, imported.rules)
+	require.Equal(t, []string{
+		"ingress tcp rule: prefix list pl-12345678 is not supported",
+		"ingress tcp rule: referenced Security Group sg-0a1b2c3d4e5f60718 is not supported",
+		"egress rules: all egress traffic is allowed, which is the default without egress rules",
+	}, imported.skipped)
+
+	imported, err = parseFirewallImportAWS(data, "sg-0fedcba9876543210")
+	require.NoError(t, err)
+	require.Equal(t, []*firewallImportRule{
+		testFirewallImportRule("ingress", "tcp", "10.0.0.0/8", 5432, 5432, "PostgreSQL"),
+		testFirewallImportRule("egress", "tcp", "0.0.0.0/0", 443, 443, "updates"),
+	}, imported.rules)
+	require.Equal(t, []string{`egress rule: unsupported protocol "132"`}, imported.skipped)
+}
+
+func Test_parseFirewallImportCSV(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/firewall_import/web.csv")
+	require.NoError(t, err)
+
+	// The CSV sample is the translation of the "web" AWS Security Group,
+	// plus an untranslatable rule.
+	imported, err := parseFirewallImportCSV(data)
+	require.NoError(t, err)
+	require.Equal(t, testFirewallImportWebRules, imported.rules)
+	require.Equal(t, []string{`line 7: source "bastion" is not a CIDR`}, imported.skipped)
+
+	_, err = parseFirewallImportCSV([]byte("inbound,tcp,22,0.0.0.0/0\n"))
+	require.Error(t, err)
+
+	_, err = parseFirewallImportCSV([]byte("ingress,tcp,80-70,0.0.0.0/0\n"))
+	require.Error(t, err)
+
+	imported, err = parseFirewallImportCSV([]byte("egress,all,,0.0.0.0/0\ningress,udp,*,10.0.0.0/8\n"))
+	require.NoError(t, err)
+	require.Equal(t, []*firewallImportRule{
+		{direction: "egress", protocol: "all", icmpType: 0, cidr: egoscale.MustParseCIDR("0.0.0.0/0")},
+		testFirewallImportRule("ingress", "udp", "10.0.0.0/8", 1, 65535, ""),
+	}, imported.rules)
+}
+
+func Test_firewallImportRule_matches(t *testing.T) {
+	rule := testFirewallImportRule("ingress", "tcp", "10.0.0.0/8", 8000, 8080, "")
+
+	require.True(t, rule.matches(&egoscale.IngressRule{
+		Protocol: "TCP", CIDR: egoscale.MustParseCIDR("10.0.0.0/8"), StartPort: 8000, EndPort: 8080,
+	}))
+	require.False(t, rule.matches(&egoscale.IngressRule{
+		Protocol: "tcp", CIDR: egoscale.MustParseCIDR("10.0.0.0/8"), StartPort: 8000, EndPort: 8000,
+	}))
+	require.False(t, rule.matches(&egoscale.IngressRule{Protocol: "tcp", SecurityGroupName: "web"}))
+}
@@ -31,6 +31,10 @@ var firewallRemoveCmd = &cobra.Command{
 			return errGet
 		}
 
+		if err = checkDefaultSecurityGroupChange(cmd, sg, "remove rules from"); err != nil {
+			return err
+		}
+
 		if len(args) == 1 && deleteAll {
 			count := len(sg.IngressRule) + len(sg.EgressRule)
 			if !force {
@@ -179,5 +183,6 @@ func init() {
 	firewallRemoveCmd.Flags().BoolP("ipv6", "6", false, "Remove rule with any IPv6 source")
 	firewallRemoveCmd.Flags().BoolP("my-ip", "m", false, "Remove rule with my IP as a source")
 	firewallRemoveCmd.Flags().BoolP("all", "a", false, "Remove all rules")
+	firewallRemoveCmd.Flags().Bool(firewallDefaultsFlag, false, "Allow removing rules from the default Security Group")
 	firewallCmd.AddCommand(firewallRemoveCmd)
 }
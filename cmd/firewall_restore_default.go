@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+)
+
+// defaultSecurityGroupRule is a conventional rule of the default Security
+// Group, as a predefined rule name (see getDefaultRule()) and its source.
+type defaultSecurityGroupRule struct {
+	name string
+	cidr *egoscale.CIDR
+}
+
+// defaultSecurityGroupRules are the conventional rules of the default
+// Security Group: SSH and ping access from anywhere.
+var defaultSecurityGroupRules = []defaultSecurityGroupRule{
+	{name: "ssh", cidr: defaultCIDR},
+	{name: "ssh", cidr: defaultCIDR6},
+	{name: "ping", cidr: defaultCIDR},
+	{name: "ping6", cidr: defaultCIDR6},
+}
+
+var firewallRestoreDefaultCmd = &cobra.Command{
+	Use:   "restore-default",
+	Short: "Restore the conventional rules of the default Security Group",
+	Long: fmt.Sprintf(`This command recreates the conventional ingress rules of the %q
+Security Group (SSH and ping access from anywhere, over IPv4 and IPv6) that
+are missing from it. Existing rules are left untouched.
+`, defaultSecurityGroupName),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmdSetForceFromFlag(cmd)
+
+		sg, err := getSecurityGroupByNameOrID(defaultSecurityGroupName)
+		if err != nil {
+			return err
+		}
+
+		missing, err := missingDefaultSecurityGroupRules(sg)
+		if err != nil {
+			return err
+		}
+
+		if len(missing) == 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "The %q Security Group already has all the conventional rules\n",
+				defaultSecurityGroupName)
+			return nil
+		}
+
+		names := make([]string, len(missing))
+		for i, r := range missing {
+			names[i] = fmt.Sprintf("%s (%s)", r.name, r.cidr)
+		}
+
+		if !askQuestion(fmt.Sprintf("Are you sure you want to add the following rules to the %q Security Group: %s?",
+			defaultSecurityGroupName, strings.Join(names, ", "))) {
+			return nil
+		}
+
+		tasks := make([]task, len(missing))
+		for i, r := range missing {
+			rule, _ := getDefaultRule(r.name)
+			rule.SecurityGroupID = sg.ID
+			rule.CIDRList = []egoscale.CIDR{*r.cidr}
+			tasks[i] = task{*rule, fmt.Sprintf("Add %q rule (%s) for %q", r.name, r.cidr, sg.Name)}
+		}
+
+		resps := asyncTasks(tasks)
+		errs := filterErrors(resps)
+		if len(errs) > 0 {
+			return errs[0]
+		}
+
+		return nil
+	},
+}
+
+// missingDefaultSecurityGroupRules returns the conventional rules of the
+// default Security Group missing from the Security Group sg.
+func missingDefaultSecurityGroupRules(sg *egoscale.SecurityGroup) ([]defaultSecurityGroupRule, error) {
+	missing := make([]defaultSecurityGroupRule, 0)
+
+	for _, r := range defaultSecurityGroupRules {
+		rule, err := getDefaultRule(r.name)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for i := range sg.IngressRule {
+			if sg.IngressRule[i].CIDR != nil && isDefaultSecurityGroupRule(&sg.IngressRule[i], rule, r.cidr) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, r)
+		}
+	}
+
+	return missing, nil
+}
+
+// isDefaultSecurityGroupRule returns true if the ingress rule matches the
+// predefined rule with the specified source.
+func isDefaultSecurityGroupRule(in *egoscale.IngressRule, rule *egoscale.AuthorizeSecurityGroupIngress, cidr *egoscale.CIDR) bool {
+	if !strings.EqualFold(in.Protocol, rule.Protocol) || !in.CIDR.Equal(*cidr) {
+		return false
+	}
+
+	if strings.HasPrefix(strings.ToLower(rule.Protocol), "icmp") {
+		return in.IcmpType == rule.IcmpType && in.IcmpCode == rule.IcmpCode
+	}
+
+	return in.StartPort == rule.StartPort && in.EndPort == rule.EndPort
+}
+
+func init() {
+	firewallRestoreDefaultCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
+	firewallCmd.AddCommand(firewallRestoreDefaultCmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_missingDefaultSecurityGroupRules(t *testing.T) {
+	sg := &egoscale.SecurityGroup{
+		Name: defaultSecurityGroupName,
+		IngressRule: []egoscale.IngressRule{
+			{Protocol: "tcp", CIDR: defaultCIDR, StartPort: 22, EndPort: 22},
+			{Protocol: "icmp", CIDR: defaultCIDR, IcmpType: 8, IcmpCode: 0},
+			{Protocol: "tcp", CIDR: defaultCIDR6, StartPort: 80, EndPort: 80},
+		},
+	}
+
+	missing, err := missingDefaultSecurityGroupRules(sg)
+	require.NoError(t, err)
+	require.Equal(t, []defaultSecurityGroupRule{
+		{name: "ssh", cidr: defaultCIDR6},
+		{name: "ping6", cidr: defaultCIDR6},
+	}, missing)
+}
+
+func Test_checkDefaultSecurityGroupChange(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool(firewallDefaultsFlag, false, "")
+		return cmd
+	}
+
+	require.NoError(t, checkDefaultSecurityGroupChange(newCmd(), &egoscale.SecurityGroup{Name: "web"}, "delete"))
+
+	err := checkDefaultSecurityGroupChange(newCmd(), &egoscale.SecurityGroup{Name: defaultSecurityGroupName}, "delete")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--"+firewallDefaultsFlag)
+
+	cmd := newCmd()
+	require.NoError(t, cmd.Flags().Set(firewallDefaultsFlag, "true"))
+	require.NoError(t, checkDefaultSecurityGroupChange(cmd, &egoscale.SecurityGroup{Name: defaultSecurityGroupName}, "delete"))
+}
@@ -32,7 +32,8 @@ func init() {
 
 Supported output template annotations: %s`,
 			strings.Join(outputterTemplateAnnotations(&securityGroupShowOutput{}), ", ")),
-		Aliases: gListAlias,
+		Aliases:           gListAlias,
+		ValidArgsFunction: completeResourceNames("security-group", 0),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return errors.New("show expects one Security Group by name or id")
@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	graphNodeTypeNLB            = "nlb"
+	graphNodeTypeNLBService     = "nlb-service"
+	graphNodeTypeInstancePool   = "instance-pool"
+	graphNodeTypeInstance       = "instance"
+	graphNodeTypeTemplate       = "template"
+	graphNodeTypeSecurityGroup  = "security-group"
+	graphNodeTypePrivateNetwork = "private-network"
+)
+
+// graphNodeTypes lists the graph node types in display order.
+var graphNodeTypes = []string{
+	graphNodeTypeNLB,
+	graphNodeTypeNLBService,
+	graphNodeTypeInstancePool,
+	graphNodeTypeInstance,
+	graphNodeTypeTemplate,
+	graphNodeTypeSecurityGroup,
+	graphNodeTypePrivateNetwork,
+}
+
+// graphNodeShapes are the Graphviz node shapes per node type.
+var graphNodeShapes = map[string]string{
+	graphNodeTypeNLB:            "doubleoctagon",
+	graphNodeTypeNLBService:     "octagon",
+	graphNodeTypeInstancePool:   "box3d",
+	graphNodeTypeInstance:       "box",
+	graphNodeTypeTemplate:       "note",
+	graphNodeTypeSecurityGroup:  "hexagon",
+	graphNodeTypePrivateNetwork: "ellipse",
+}
+
+type graphNode struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// graphEdge represents a dependency of the resource From on the resource To,
+// i.e. From uses or manages To.
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type resourceGraph struct {
+	Nodes []graphNode `json:"nodes"`
+	Edges []graphEdge `json:"edges"`
+
+	index map[string]int
+}
+
+func newResourceGraph() *resourceGraph {
+	return &resourceGraph{
+		Nodes: make([]graphNode, 0),
+		Edges: make([]graphEdge, 0),
+		index: make(map[string]int),
+	}
+}
+
+func (g *resourceGraph) addNode(typ, id, name string) {
+	if _, ok := g.index[id]; ok {
+		return
+	}
+	if name == "" {
+		name = id
+	}
+
+	g.index[id] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, graphNode{ID: id, Type: typ, Name: name})
+}
+
+func (g *resourceGraph) addEdge(from, to string) {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to {
+			return
+		}
+	}
+
+	g.Edges = append(g.Edges, graphEdge{From: from, To: to})
+}
+
+// sort sorts the graph nodes by type then name, and edges by nodes order.
+func (g *resourceGraph) sort() {
+	typeOrder := make(map[string]int)
+	for i, t := range graphNodeTypes {
+		typeOrder[t] = i
+	}
+
+	sort.SliceStable(g.Nodes, func(i, j int) bool {
+		a, b := g.Nodes[i], g.Nodes[j]
+		if a.Type != b.Type {
+			return typeOrder[a.Type] < typeOrder[b.Type]
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	for i, n := range g.Nodes {
+		g.index[n.ID] = i
+	}
+
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return g.index[a.From] < g.index[b.From]
+		}
+		return g.index[a.To] < g.index[b.To]
+	})
+}
+
+// findNode returns the ID of the node matching the specified resource name
+// or ID.
+func (g *resourceGraph) findNode(v string) (string, error) {
+	if _, ok := g.index[v]; ok {
+		return v, nil
+	}
+
+	found := make([]graphNode, 0)
+	for _, n := range g.Nodes {
+		if n.Name == v {
+			found = append(found, n)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("resource %q not found", v)
+	case 1:
+		return found[0].ID, nil
+	default:
+		return "", fmt.Errorf("found multiple resources named %q, please specify an ID instead", v)
+	}
+}
+
+// focus returns the subgraph made of the node id, the nodes it transitively
+// depends on and the nodes transitively depending on it.
+func (g *resourceGraph) focus(id string) *resourceGraph {
+	keep := map[string]bool{id: true}
+
+	walk := func(next func(e graphEdge) (string, string)) {
+		queue := []string{id}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			for _, e := range g.Edges {
+				if from, to := next(e); from == cur && !keep[to] {
+					keep[to] = true
+					queue = append(queue, to)
+				}
+			}
+		}
+	}
+	walk(func(e graphEdge) (string, string) { return e.From, e.To }) // Dependencies
+	walk(func(e graphEdge) (string, string) { return e.To, e.From }) // Dependents
+
+	sub := newResourceGraph()
+	for _, n := range g.Nodes {
+		if keep[n.ID] {
+			sub.addNode(n.Type, n.ID, n.Name)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			sub.addEdge(e.From, e.To)
+		}
+	}
+
+	return sub
+}
+
+// writeDOT writes the graph in Graphviz DOT format to w.
+func (g *resourceGraph) writeDOT(w io.Writer) {
+	fmt.Fprintln(w, "digraph exoscale {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(w, "  %q [label=%q, shape=%s];\n", n.ID, n.Type+"\n"+n.Name, graphNodeShapes[n.Type])
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  %q -> %q;\n", e.From, e.To)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// graphResources represents the resources of a zone to build a dependency
+// graph from.
+type graphResources struct {
+	nlbs            []*exov2.NetworkLoadBalancer
+	instancePools   []*exov2.InstancePool
+	instances       []*exov2.Instance
+	securityGroups  map[string]string
+	privateNetworks map[string]string
+	templates       map[string]string
+}
+
+// buildResourceGraph builds the dependency graph of the specified resources.
+func buildResourceGraph(r *graphResources) *resourceGraph {
+	g := newResourceGraph()
+
+	addDependencies := func(from string, templateID *string, securityGroupIDs, privateNetworkIDs *[]string) {
+		if templateID != nil {
+			g.addNode(graphNodeTypeTemplate, *templateID, r.templates[*templateID])
+			g.addEdge(from, *templateID)
+		}
+		if securityGroupIDs != nil {
+			for _, id := range *securityGroupIDs {
+				g.addNode(graphNodeTypeSecurityGroup, id, r.securityGroups[id])
+				g.addEdge(from, id)
+			}
+		}
+		if privateNetworkIDs != nil {
+			for _, id := range *privateNetworkIDs {
+				g.addNode(graphNodeTypePrivateNetwork, id, r.privateNetworks[id])
+				g.addEdge(from, id)
+			}
+		}
+	}
+
+	instanceNames := make(map[string]string)
+	for _, i := range r.instances {
+		instanceNames[*i.ID] = defaultString(i.Name, "")
+	}
+
+	for _, nlb := range r.nlbs {
+		g.addNode(graphNodeTypeNLB, *nlb.ID, defaultString(nlb.Name, ""))
+		for _, svc := range nlb.Services {
+			g.addNode(graphNodeTypeNLBService, *svc.ID, defaultString(svc.Name, ""))
+			g.addEdge(*nlb.ID, *svc.ID)
+			if svc.InstancePoolID != nil {
+				g.addNode(graphNodeTypeInstancePool, *svc.InstancePoolID, "")
+				g.addEdge(*svc.ID, *svc.InstancePoolID)
+			}
+		}
+	}
+
+	for _, p := range r.instancePools {
+		// Instance Pools referenced by NLB services have been added with an
+		// empty name, which is fixed here.
+		if i, ok := g.index[*p.ID]; ok {
+			g.Nodes[i].Name = defaultString(p.Name, *p.ID)
+		} else {
+			g.addNode(graphNodeTypeInstancePool, *p.ID, defaultString(p.Name, ""))
+		}
+
+		addDependencies(*p.ID, p.TemplateID, p.SecurityGroupIDs, p.PrivateNetworkIDs)
+		if p.InstanceIDs != nil {
+			for _, id := range *p.InstanceIDs {
+				g.addNode(graphNodeTypeInstance, id, instanceNames[id])
+				g.addEdge(*p.ID, id)
+			}
+		}
+	}
+
+	for _, i := range r.instances {
+		g.addNode(graphNodeTypeInstance, *i.ID, defaultString(i.Name, ""))
+		addDependencies(*i.ID, i.TemplateID, i.SecurityGroupIDs, i.PrivateNetworkIDs)
+	}
+
+	g.sort()
+
+	return g
+}
+
+type graphCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"graph"`
+
+	Focus  string `cli-usage:"restrict the graph to the resources depending on/depended on by this resource (name or ID)"`
+	Format string `cli-usage:"graph output format (dot|json)"`
+	Zone   string `cli-short:"z" cli-usage:"zone to build the graph of"`
+}
+
+func (c *graphCmd) cmdAliases() []string { return nil }
+
+func (c *graphCmd) cmdShort() string { return "Output a Compute resources dependency graph" }
+
+func (c *graphCmd) cmdLong() string {
+	return `This command outputs the dependency graph of the Compute resources of a zone:
+Network Load Balancers, their services, the Instance Pools they target, and
+the Compute instances, templates, Security Groups and Private Networks used
+by Instance Pools and Compute instances. An edge "A -> B" means that the
+resource A uses (or manages, for Instance Pools members) the resource B.
+
+The graph is output in Graphviz DOT format (e.g. to be rendered using
+"exo graph -z ch-gva-2 | dot -Tsvg > graph.svg"), or in JSON format using
+"--format json".
+
+Using the "--focus" flag, the graph is restricted to the specified resource,
+the resources it transitively depends on and the resources transitively
+depending on it, which is useful to plan the deletion of a resource.
+`
+}
+
+func (c *graphCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *graphCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	if c.Format != "dot" && c.Format != "json" {
+		cmdExitOnUsageError(cmd, fmt.Sprintf("unsupported format %q (supported formats: dot, json)", c.Format))
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	var (
+		r   = graphResources{templates: make(map[string]string)}
+		err error
+	)
+
+	if r.nlbs, err = cs.ListNetworkLoadBalancers(ctx, c.Zone); err != nil {
+		return fmt.Errorf("unable to list Network Load Balancers: %v", err)
+	}
+
+	if r.instancePools, err = cs.ListInstancePools(ctx, c.Zone); err != nil {
+		return fmt.Errorf("unable to list Instance Pools: %v", err)
+	}
+
+	if r.instances, err = cs.ListInstances(ctx, c.Zone); err != nil {
+		return fmt.Errorf("unable to list Compute instances: %v", err)
+	}
+
+	securityGroups, err := cs.ListSecurityGroups(ctx, c.Zone)
+	if err != nil {
+		return fmt.Errorf("unable to list Security Groups: %v", err)
+	}
+	r.securityGroups = make(map[string]string)
+	for _, sg := range securityGroups {
+		r.securityGroups[*sg.ID] = *sg.Name
+	}
+
+	privateNetworks, err := cs.ListPrivateNetworks(ctx, c.Zone)
+	if err != nil {
+		return fmt.Errorf("unable to list Private Networks: %v", err)
+	}
+	r.privateNetworks = make(map[string]string)
+	for _, pn := range privateNetworks {
+		r.privateNetworks[*pn.ID] = *pn.Name
+	}
+
+	// Templates are retrieved individually, as listing them would return
+	// all the public templates.
+	templateIDs := make([]*string, 0)
+	for _, p := range r.instancePools {
+		templateIDs = append(templateIDs, p.TemplateID)
+	}
+	for _, i := range r.instances {
+		templateIDs = append(templateIDs, i.TemplateID)
+	}
+	for _, id := range templateIDs {
+		if id == nil {
+			continue
+		}
+		if _, ok := r.templates[*id]; ok {
+			continue
+		}
+		// Templates can be deleted while still referenced, in which case the
+		// template ID is used as name.
+		r.templates[*id] = ""
+		if t, err := cs.GetTemplate(ctx, c.Zone, *id); err == nil {
+			r.templates[*id] = *t.Name
+		}
+	}
+
+	g := buildResourceGraph(&r)
+
+	if c.Focus != "" {
+		id, err := g.findNode(c.Focus)
+		if err != nil {
+			return err
+		}
+		g = g.focus(id)
+		g.sort()
+	}
+
+	if c.Format == "json" {
+		outputJSON(g)
+		return nil
+	}
+
+	g.writeDOT(os.Stdout)
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(RootCmd, &graphCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+		Format:             "dot",
+	}))
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraphResources() *graphResources {
+	str := func(s string) *string { return &s }
+	strs := func(s ...string) *[]string { return &s }
+
+	return &graphResources{
+		nlbs: []*exov2.NetworkLoadBalancer{{
+			ID:   str("nlb1"),
+			Name: str("web-lb"),
+			Services: []*exov2.NetworkLoadBalancerService{
+				{ID: str("svc1"), Name: str("https"), InstancePoolID: str("pool1")},
+			},
+		}},
+		instancePools: []*exov2.InstancePool{{
+			ID:                str("pool1"),
+			Name:              str("web"),
+			TemplateID:        str("tpl1"),
+			SecurityGroupIDs:  strs("sg1"),
+			PrivateNetworkIDs: strs("pn1"),
+			InstanceIDs:       strs("i1"),
+		}},
+		instances: []*exov2.Instance{
+			{ID: str("i1"), Name: str("web-1"), TemplateID: str("tpl1"), SecurityGroupIDs: strs("sg1")},
+			{ID: str("i2"), Name: str("db"), TemplateID: str("tpl2"), SecurityGroupIDs: strs("sg2")},
+		},
+		securityGroups:  map[string]string{"sg1": "web", "sg2": "db"},
+		privateNetworks: map[string]string{"pn1": "backend"},
+		templates:       map[string]string{"tpl1": "Linux Ubuntu 22.04 LTS 64-bit"},
+	}
+}
+
+func Test_buildResourceGraph(t *testing.T) {
+	g := buildResourceGraph(testGraphResources())
+
+	require.Equal(t, []graphNode{
+		{ID: "nlb1", Type: graphNodeTypeNLB, Name: "web-lb"},
+		{ID: "svc1", Type: graphNodeTypeNLBService, Name: "https"},
+		{ID: "pool1", Type: graphNodeTypeInstancePool, Name: "web"},
+		{ID: "i2", Type: graphNodeTypeInstance, Name: "db"},
+		{ID: "i1", Type: graphNodeTypeInstance, Name: "web-1"},
+		{ID: "tpl1", Type: graphNodeTypeTemplate, Name: "Linux Ubuntu 22.04 LTS 64-bit"},
+		{ID: "tpl2", Type: graphNodeTypeTemplate, Name: "tpl2"},
+		{ID: "sg2", Type: graphNodeTypeSecurityGroup, Name: "db"},
+		{ID: "sg1", Type: graphNodeTypeSecurityGroup, Name: "web"},
+		{ID: "pn1", Type: graphNodeTypePrivateNetwork, Name: "backend"},
+	}, g.Nodes)
+
+	require.Equal(t, []graphEdge{
+		{From: "nlb1", To: "svc1"},
+		{From: "svc1", To: "pool1"},
+		{From: "pool1", To: "i1"},
+		{From: "pool1", To: "tpl1"},
+		{From: "pool1", To: "sg1"},
+		{From: "pool1", To: "pn1"},
+		{From: "i2", To: "tpl2"},
+		{From: "i2", To: "sg2"},
+		{From: "i1", To: "tpl1"},
+		{From: "i1", To: "sg1"},
+	}, g.Edges)
+
+	var buf bytes.Buffer
+	g.writeDOT(&buf)
+	require.Contains(t, buf.String(), `"nlb1" [label="nlb\nweb-lb", shape=doubleoctagon];`)
+	require.Contains(t, buf.String(), `"svc1" -> "pool1";`)
+}
+
+func Test_resourceGraph_focus(t *testing.T) {
+	g := buildResourceGraph(testGraphResources())
+
+	_, err := g.findNode("web")
+	require.Error(t, err) // Instance Pool and Security Group
+
+	id, err := g.findNode("backend")
+	require.NoError(t, err)
+
+	sub := g.focus(id)
+	sub.sort()
+
+	ids := make([]string, len(sub.Nodes))
+	for i, n := range sub.Nodes {
+		ids[i] = n.ID
+	}
+	require.Equal(t, []string{"nlb1", "svc1", "pool1", "pn1"}, ids)
+
+	id, err = g.findNode("i1")
+	require.NoError(t, err)
+
+	sub = g.focus(id)
+	sub.sort()
+
+	ids = make([]string, len(sub.Nodes))
+	for i, n := range sub.Nodes {
+		ids[i] = n.ID
+	}
+	require.Equal(t, []string{"nlb1", "svc1", "pool1", "i1", "tpl1", "sg1"}, ids)
+}
@@ -9,9 +9,10 @@ import (
 )
 
 type apiKeyItem struct {
-	Key  string `json:"key"`
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Key        string `json:"key"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Operations int    `json:"operations_count" outputLabel:"Operations"`
 }
 
 type apiKeyListItemOutput []apiKeyItem
@@ -23,7 +24,8 @@ func (o *apiKeyListItemOutput) toTable() { outputTable(o) }
 var apiKeyListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List API keys",
-	Long: fmt.Sprintf(`This command lists existing API keys.
+	Long: fmt.Sprintf(`This command lists existing API keys. For restricted keys, the number
+of operations allowed is reported (unrestricted keys report 0).
 
 	Supported output template annotations: %s`,
 		strings.Join(outputterTemplateAnnotations(&apiKeyListItemOutput{}), ", ")),
@@ -39,9 +41,10 @@ var apiKeyListCmd = &cobra.Command{
 		o := make(apiKeyListItemOutput, 0, r.Count)
 		for _, i := range r.APIKeys {
 			o = append(o, apiKeyItem{
-				Name: i.Name,
-				Key:  i.Key,
-				Type: string(i.Type),
+				Name:       i.Name,
+				Key:        i.Key,
+				Type:       string(i.Type),
+				Operations: len(i.Operations),
 			})
 		}
 
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+)
+
+var iamMemberCmd = &cobra.Command{
+	Use:   "member",
+	Short: "Organization members management",
+}
+
+// errIAMMemberPermission is returned when the API key used isn't allowed to
+// access the organization members.
+var errIAMMemberPermission = errors.New("permission denied: managing the organization members " +
+	"requires an unrestricted API key of an organization owner")
+
+// iamMemberError returns a clear error in place of the API errors returned
+// when the API key used isn't allowed to access the organization members.
+func iamMemberError(err error) error {
+	if egoerr, ok := err.(*egoscale.ErrorResponse); ok {
+		switch egoerr.ErrorCode {
+		case egoscale.Unauthorized, egoscale.ErrorCode(403):
+			return errIAMMemberPermission
+		}
+	}
+
+	return err
+}
+
+func init() {
+	iamCmd.AddCommand(iamMemberCmd)
+}
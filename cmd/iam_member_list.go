@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/exoscale/egoscale"
+	"github.com/spf13/cobra"
+)
+
+type iamMemberItem struct {
+	Email   string `json:"email"`
+	Name    string `json:"name"`
+	Role    string `json:"role"`
+	State   string `json:"state"`
+	Created string `json:"created"`
+}
+
+type iamMemberListOutput []iamMemberItem
+
+func (o *iamMemberListOutput) toJSON()  { outputJSON(o) }
+func (o *iamMemberListOutput) toText()  { outputText(o) }
+func (o *iamMemberListOutput) toTable() { outputTable(o) }
+
+var iamMemberListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List organization members",
+	Long: fmt.Sprintf(`This command lists the members of the organization, e.g. for periodic
+access reviews (see the "--output-format json" flag).
+
+Listing the organization members requires an unrestricted API key of an
+organization owner.
+
+	Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&iamMemberListOutput{}), ", ")),
+	Aliases: gListAlias,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := cs.RequestWithContext(gContext, &egoscale.ListUsers{})
+		if err != nil {
+			return iamMemberError(err)
+		}
+
+		r := resp.(*egoscale.ListUsersResponse)
+
+		o := make(iamMemberListOutput, 0, r.Count)
+		for _, u := range r.User {
+			o = append(o, iamMemberItem{
+				Email:   u.Email,
+				Name:    strings.TrimSpace(u.FirstName + " " + u.LastName),
+				Role:    u.RoleName,
+				State:   u.State,
+				Created: u.Created,
+			})
+		}
+
+		return output(&o, err)
+	},
+}
+
+func init() {
+	iamMemberCmd.AddCommand(iamMemberListCmd)
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/exoscale/egoscale"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIAMMemberError(t *testing.T) {
+	require.Equal(t, errIAMMemberPermission, iamMemberError(&egoscale.ErrorResponse{ErrorCode: egoscale.Unauthorized}))
+	require.Equal(t, errIAMMemberPermission, iamMemberError(&egoscale.ErrorResponse{ErrorCode: egoscale.ErrorCode(403)}))
+
+	err := &egoscale.ErrorResponse{ErrorCode: egoscale.InternalError}
+	require.Equal(t, err, iamMemberError(err))
+
+	other := errors.New("boom")
+	require.Equal(t, other, iamMemberError(other))
+}
@@ -0,0 +1,531 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path"
+	"strings"
+
+	"github.com/exoscale/egoscale"
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	defaultInitSecurityGroup = "ssh"
+	initDefaultAccountName   = "default"
+	initSSHPort              = 22
+	initSSHRuleDescription   = "SSH access (exo init)"
+)
+
+type initOutput struct {
+	Account       string `json:"account"`
+	Zone          string `json:"zone"`
+	SSHKey        string `json:"ssh_key"`
+	SecurityGroup string `json:"security_group"`
+	ConfigFile    string `json:"config_file"`
+}
+
+func (o *initOutput) toJSON()  { outputJSON(o) }
+func (o *initOutput) toText()  { outputText(o) }
+func (o *initOutput) toTable() { outputTable(o) }
+
+type initCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"init"`
+
+	APIKey            string `cli-flag:"api-key" cli-usage:"API key"`
+	APISecret         string `cli-flag:"api-secret" cli-usage:"API secret (\"-\" to read it from the standard input)"`
+	Name              string `cli-usage:"account name (default: the organization name)"`
+	SecurityGroup     string `cli-usage:"name of the Security Group allowing SSH access from the current IP address"`
+	SkipSSHKey        bool   `cli-flag:"skip-ssh-key" cli-usage:"don't register an SSH key"`
+	SkipSecurityGroup bool   `cli-usage:"don't create the SSH access Security Group"`
+	SSHKey            string `cli-flag:"ssh-key" cli-usage:"name of the SSH key to register (default: USER@HOST)"`
+	SSHPublicKeyFile  string `cli-flag:"ssh-public-key" cli-usage:"public key file of the SSH key to register (default: generated key)"`
+	Zone              string `cli-short:"z" cli-usage:"default zone"`
+}
+
+func (c *initCmd) cmdAliases() []string { return nil }
+
+func (c *initCmd) cmdShort() string { return "Set up the exo CLI" }
+
+func (c *initCmd) cmdLong() string {
+	return fmt.Sprintf(`This command walks through the setup of the exo CLI:
+
+  1. API credentials: the API key and secret are checked, and saved as a new
+     account of the configuration file (created if missing). An account
+     already configured is used as is if no API key is specified.
+  2. Default zone, selected from the list of available zones.
+  3. SSH key (optional): an existing public key ("--ssh-public-key") or a
+     generated key pair (stored in the configuration directory) is
+     registered, and set as the account default SSH key.
+  4. Security Group (optional): a Security Group (default: %q) allowing
+     SSH access from the current public IP address is created.
+
+Every step can be driven by flags for automation (see also the global
+"--non-interactive" flag), in which case the optional steps are performed
+unless skipped using the "--skip-ssh-key" and "--skip-security-group" flags.
+Re-running the command is safe: the resources already set up are reused.
+
+Supported output template annotations: %s`,
+		defaultInitSecurityGroup,
+		strings.Join(outputterTemplateAnnotations(&initOutput{}), ", "))
+}
+
+func (c *initCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if gNoWait {
+		return errNoWaitUnsupported("by this command")
+	}
+
+	return nil
+}
+
+func (c *initCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	reader := bufio.NewReader(gStdin)
+
+	acc, err := c.setupAccount(reader)
+	if err != nil {
+		return err
+	}
+
+	// The API client is (re)built from the account set up, as the command
+	// may run without any configuration.
+	current := *acc
+	gCurrentAccount = &current
+	setAccountDefaults(gCurrentAccount)
+	ignoreClientBuild = false
+	cs = nil
+	buildClient()
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, gCurrentAccount.DefaultZone))
+
+	out := initOutput{
+		Account:    gCurrentAccount.Name,
+		Zone:       gCurrentAccount.DefaultZone,
+		ConfigFile: gConfigFilePath,
+	}
+
+	if c.runStep(c.SkipSSHKey, "Register an SSH key to log into the Compute instances?") {
+		if out.SSHKey, err = c.setupSSHKey(ctx, gCurrentAccount); err != nil {
+			return err
+		}
+	}
+
+	if c.runStep(c.SkipSecurityGroup, "Create a Security Group allowing SSH access from your IP address?") {
+		if out.SecurityGroup, err = c.setupSecurityGroup(ctx, gCurrentAccount.DefaultZone); err != nil {
+			return err
+		}
+	}
+
+	if !gQuiet {
+		return c.outputFunc(&out, nil)
+	}
+
+	return nil
+}
+
+// runStep returns true if an optional step is to be performed: unless
+// skipped, the user is prompted in interactive mode.
+func (c *initCmd) runStep(skip bool, question string) bool {
+	if skip {
+		return false
+	}
+
+	if !isInteractive() {
+		return true
+	}
+
+	return askQuestion(question)
+}
+
+// setupAccount returns the account to set up, saving it to the
+// configuration file if it isn't configured yet.
+func (c *initCmd) setupAccount(reader *bufio.Reader) (*account, error) {
+	configured := gAllAccount != nil && gConfigFilePath != envAccountName && gCurrentAccount.Key != ""
+	if c.APIKey == "" && configured {
+		fmt.Fprintf(os.Stderr, "Using the configured account %q\n", gCurrentAccount.Name)
+		return gCurrentAccount, nil
+	}
+
+	var err error
+
+	acc := &account{
+		Name:        c.Name,
+		Key:         c.APIKey,
+		Secret:      c.APISecret,
+		Endpoint:    defaultEndpoint,
+		Environment: defaultEnvironment,
+		DefaultZone: c.Zone,
+	}
+
+	// API credentials defined by environment variables are saved as is.
+	if acc.Key == "" && gConfigFilePath == envAccountName {
+		acc.Key, acc.Secret = gCurrentAccount.Key, gCurrentAccount.Secret
+	}
+
+	if acc.Key == "" {
+		if !isInteractive() {
+			return nil, errors.New(`the "--api-key" and "--api-secret" flags are required in non-interactive mode`)
+		}
+		if acc.Key, err = readInput(reader, "API Key", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case acc.Secret == "-":
+		if acc.Secret, err = reader.ReadString('\n'); err != nil && acc.Secret == "" {
+			return nil, fmt.Errorf("unable to read the API secret from standard input: %s", err)
+		}
+		acc.Secret = strings.TrimSpace(acc.Secret)
+
+	case acc.Secret == "" && isInteractive():
+		if acc.Secret, err = readInput(reader, "API Secret", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if acc.Key == "" || acc.Secret == "" {
+		return nil, errors.New("API key and secret are required")
+	}
+
+	if existing := findAccountByKey(gAllAccount, acc.Key); existing != nil && gConfigFilePath != envAccountName {
+		fmt.Fprintf(os.Stderr, "API key %s is already configured as account %q\n", acc.Key, existing.Name)
+		return existing, nil
+	}
+
+	client := egoscale.NewClient(acc.Endpoint, acc.Key, acc.Secret)
+
+	var organization string
+	err = decorateAsyncOperation("Checking API credentials...", func() error {
+		organization, err = checkAPICredentials(client)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid API credentials: %s", err)
+	}
+
+	acc.Account = organization
+	if acc.Name == "" {
+		acc.Name = organization
+		if acc.Name == "" {
+			acc.Name = initDefaultAccountName
+		}
+		if isInteractive() {
+			if acc.Name, err = readInput(reader, "Account name", acc.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if acc.Account == "" {
+		acc.Account = acc.Name
+	}
+
+	zones, err := listZoneNames(client)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list zones: %s", err)
+	}
+
+	switch {
+	case acc.DefaultZone != "":
+		if !isInList(zones, acc.DefaultZone) {
+			return nil, fmt.Errorf("invalid zone %q (supported: %s)", acc.DefaultZone, strings.Join(zones, ", "))
+		}
+
+	case isInteractive():
+		if acc.DefaultZone, err = chooseZone(client, zones); err != nil {
+			return nil, err
+		}
+
+	default:
+		acc.DefaultZone = defaultZone
+	}
+
+	acc.DNSEndpoint = buildDNSAPIEndpoint(acc.Endpoint)
+
+	if err := saveAccount(acc, true, false); err != nil {
+		return nil, err
+	}
+
+	return getAccountByName(acc.Name), nil
+}
+
+// setupSSHKey registers the SSH key used to log into the Compute instances
+// (unless already registered) and sets it as the account default SSH key,
+// returning its name.
+func (c *initCmd) setupSSHKey(ctx context.Context, acc *account) (string, error) {
+	publicKey, err := c.sshPublicKey()
+	if err != nil {
+		return "", err
+	}
+
+	name := c.SSHKey
+	if name == "" {
+		name = defaultInitSSHKeyName()
+	}
+
+	keys, err := cs.ListSSHKeys(ctx, acc.DefaultZone)
+	if err != nil {
+		return "", fmt.Errorf("unable to list SSH keys: %s", err)
+	}
+
+	registered, err := initRegisteredSSHKey(keys, name, ssh.FingerprintLegacyMD5(publicKey))
+	if err != nil {
+		return "", err
+	}
+
+	if registered == "" {
+		err = decorateAsyncOperation(fmt.Sprintf("Registering SSH key %q...", name), func() error {
+			_, err := cs.RegisterSSHKey(ctx, acc.DefaultZone, name, string(ssh.MarshalAuthorizedKey(publicKey)))
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		registered = name
+	}
+
+	if stored := getAccountByName(acc.Name); stored != nil && stored.DefaultSSHKey != registered {
+		stored.DefaultSSHKey = registered
+		if err := saveConfig(gConfig.ConfigFileUsed(), nil); err != nil {
+			return "", err
+		}
+	}
+
+	return registered, nil
+}
+
+// sshPublicKey returns the public key of the SSH key to register: the one
+// specified by flag, or a key pair stored in the configuration directory,
+// generated if it doesn't exist yet.
+func (c *initCmd) sshPublicKey() (ssh.PublicKey, error) {
+	publicKeyFile := c.SSHPublicKeyFile
+	if publicKeyFile == "" {
+		privateKeyFile := path.Join(gConfigFolder, "ssh", "id_rsa")
+		publicKeyFile = privateKeyFile + ".pub"
+
+		if _, err := os.Stat(publicKeyFile); os.IsNotExist(err) {
+			if err := generateSSHKeyPair(privateKeyFile); err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(os.Stderr, "Generated SSH key pair %s\n", privateKeyFile)
+		}
+	}
+
+	data, err := os.ReadFile(publicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH public key file %s: %s", publicKeyFile, err)
+	}
+
+	return publicKey, nil
+}
+
+// setupSecurityGroup creates the Security Group allowing SSH access from the
+// current public IP address (unless it already exists), and adds the
+// corresponding rule if missing. The Security Group name is returned.
+func (c *initCmd) setupSecurityGroup(ctx context.Context, zone string) (string, error) {
+	name := c.SecurityGroup
+	if name == "" {
+		name = defaultInitSecurityGroup
+	}
+
+	cidr, err := getMyCIDR(false)
+	if err != nil {
+		return "", fmt.Errorf("unable to determine your public IP address: %s", err)
+	}
+	network := cidr.IPNet
+
+	securityGroup, err := cs.FindSecurityGroup(ctx, zone, name)
+	if err != nil {
+		if !errors.Is(err, exoapi.ErrNotFound) {
+			return "", fmt.Errorf("error retrieving Security Group: %s", err)
+		}
+
+		description := "SSH access (created by exo init)"
+		err = decorateAsyncOperation(fmt.Sprintf("Creating Security Group %q...", name), func() error {
+			securityGroup, err = cs.CreateSecurityGroup(ctx, zone, &exov2.SecurityGroup{
+				Name:        &name,
+				Description: &description,
+			})
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if !initHasSSHRule(securityGroup, &network) {
+		var (
+			description   = initSSHRuleDescription
+			flowDirection = "ingress"
+			protocol      = "tcp"
+			port          = uint16(initSSHPort)
+		)
+
+		err = decorateAsyncOperation(fmt.Sprintf("Allowing SSH access from %s...", network.String()), func() error {
+			_, err := securityGroup.AddRule(ctx, &exov2.SecurityGroupRule{
+				Description:   &description,
+				FlowDirection: &flowDirection,
+				Protocol:      &protocol,
+				StartPort:     &port,
+				EndPort:       &port,
+				Network:       &network,
+			})
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+// findAccountByKey returns the account of the configuration conf using the
+// API key key, or nil if there is none.
+func findAccountByKey(conf *config, key string) *account {
+	if conf == nil {
+		return nil
+	}
+
+	for i := range conf.Accounts {
+		if conf.Accounts[i].Key == key {
+			return &conf.Accounts[i]
+		}
+	}
+
+	return nil
+}
+
+// listZoneNames returns the names of the zones available to the client.
+func listZoneNames(client *egoscale.Client) ([]string, error) {
+	res, err := client.ListWithContext(gContext, &egoscale.Zone{})
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make([]string, len(res))
+	for i, z := range res {
+		zones[i] = strings.ToLower(z.(*egoscale.Zone).Name)
+	}
+
+	return zones, nil
+}
+
+// defaultInitSSHKeyName returns the default name of the SSH key registered
+// by "exo init", i.e. USER@HOST.
+func defaultInitSSHKeyName() string {
+	username := "exo"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		username = u.Username
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return username
+	}
+
+	return username + "@" + hostname
+}
+
+// generateSSHKeyPair generates an RSA SSH key pair, stored in the files
+// privateKeyFile and privateKeyFile.pub.
+func generateSSHKeyPair(privateKeyFile string) error {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		return fmt.Errorf("error generating SSH private key: %s", err)
+	}
+
+	publicKey, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return fmt.Errorf("error generating SSH public key: %s", err)
+	}
+
+	if err := os.MkdirAll(path.Dir(privateKeyFile), 0o700); err != nil {
+		return fmt.Errorf("error writing SSH private key file: %s", err)
+	}
+
+	if err := os.WriteFile(
+		privateKeyFile,
+		pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		}),
+		0o600,
+	); err != nil {
+		return fmt.Errorf("error writing SSH private key file: %s", err)
+	}
+
+	if err := os.WriteFile(privateKeyFile+".pub", ssh.MarshalAuthorizedKey(publicKey), 0o644); err != nil {
+		return fmt.Errorf("error writing SSH public key file: %s", err)
+	}
+
+	return nil
+}
+
+// initRegisteredSSHKey returns the name of the registered SSH key matching
+// the fingerprint, or an empty string if the key is to be registered. An
+// error is returned if the name is already used by a different key.
+func initRegisteredSSHKey(keys []*exov2.SSHKey, name, fingerprint string) (string, error) {
+	for _, k := range keys {
+		if k.Fingerprint != nil && *k.Fingerprint == fingerprint {
+			return *k.Name, nil
+		}
+	}
+
+	for _, k := range keys {
+		if k.Name != nil && *k.Name == name {
+			return "", fmt.Errorf(
+				"an SSH key named %q is already registered with a different public key, use the --ssh-key flag to specify another name",
+				name)
+		}
+	}
+
+	return "", nil
+}
+
+// initHasSSHRule returns true if the Security Group has an ingress rule
+// allowing SSH access from the network.
+func initHasSSHRule(securityGroup *exov2.SecurityGroup, network *net.IPNet) bool {
+	for _, r := range securityGroup.Rules {
+		if defaultString(r.FlowDirection, "") != "ingress" ||
+			defaultString(r.Protocol, "") != "tcp" ||
+			r.Network == nil || r.Network.String() != network.String() ||
+			r.StartPort == nil || r.EndPort == nil {
+			continue
+		}
+
+		if *r.StartPort <= initSSHPort && *r.EndPort >= initSSHPort {
+			return true
+		}
+	}
+
+	return false
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(RootCmd, &initCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func Test_initRegisteredSSHKey(t *testing.T) {
+	newKey := func(name, fingerprint string) *exov2.SSHKey {
+		return &exov2.SSHKey{Name: &name, Fingerprint: &fingerprint}
+	}
+
+	keys := []*exov2.SSHKey{
+		newKey("alice@laptop", "aa:bb"),
+		newKey("ci", "cc:dd"),
+	}
+
+	name, err := initRegisteredSSHKey(keys, "bob@desktop", "cc:dd")
+	require.NoError(t, err)
+	require.Equal(t, "ci", name)
+
+	name, err = initRegisteredSSHKey(keys, "bob@desktop", "ee:ff")
+	require.NoError(t, err)
+	require.Empty(t, name)
+
+	_, err = initRegisteredSSHKey(keys, "alice@laptop", "ee:ff")
+	require.Error(t, err)
+}
+
+func Test_initHasSSHRule(t *testing.T) {
+	_, network, _ := net.ParseCIDR("203.0.113.10/32")
+	_, other, _ := net.ParseCIDR("198.51.100.1/32")
+
+	newRule := func(direction, protocol string, start, end uint16, network *net.IPNet) *exov2.SecurityGroupRule {
+		return &exov2.SecurityGroupRule{
+			FlowDirection: &direction,
+			Protocol:      &protocol,
+			StartPort:     &start,
+			EndPort:       &end,
+			Network:       network,
+		}
+	}
+
+	require.False(t, initHasSSHRule(&exov2.SecurityGroup{}, network))
+	require.False(t, initHasSSHRule(&exov2.SecurityGroup{Rules: []*exov2.SecurityGroupRule{
+		newRule("ingress", "tcp", 22, 22, other),
+		newRule("egress", "tcp", 22, 22, network),
+		newRule("ingress", "udp", 22, 22, network),
+		newRule("ingress", "tcp", 80, 443, network),
+	}}, network))
+	require.True(t, initHasSSHRule(&exov2.SecurityGroup{Rules: []*exov2.SecurityGroupRule{
+		newRule("ingress", "tcp", 1, 1024, network),
+	}}, network))
+}
+
+func Test_findAccountByKey(t *testing.T) {
+	conf := &config{Accounts: []account{{Name: "prod", Key: "EXOprod"}, {Name: "dev", Key: "EXOdev"}}}
+
+	require.Nil(t, findAccountByKey(nil, "EXOprod"))
+	require.Nil(t, findAccountByKey(conf, "EXOother"))
+	require.Equal(t, "dev", findAccountByKey(conf, "EXOdev").Name)
+}
+
+func Test_generateSSHKeyPair(t *testing.T) {
+	privateKeyFile := filepath.Join(t.TempDir(), "ssh", "id_rsa")
+	require.NoError(t, generateSSHKeyPair(privateKeyFile))
+
+	info, err := os.Stat(privateKeyFile)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	privateKey, err := os.ReadFile(privateKeyFile)
+	require.NoError(t, err)
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	require.NoError(t, err)
+
+	publicKey, err := os.ReadFile(privateKeyFile + ".pub")
+	require.NoError(t, err)
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey(publicKey)
+	require.NoError(t, err)
+	require.Equal(t, ssh.FingerprintLegacyMD5(signer.PublicKey()), ssh.FingerprintLegacyMD5(parsed))
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+// instanceCloneArtifacts tracks the intermediate resources created while
+// cloning a Compute instance, in order to clean them up or report them. The
+// artifacts are accessed by the interrupt hook (see onInterrupt())
+// concurrently with the steps creating them.
+type instanceCloneArtifacts struct {
+	mu           sync.Mutex
+	snapshotZone string
+	snapshotID   string
+	templateZone string
+	templateID   string
+}
+
+func (a *instanceCloneArtifacts) setSnapshotID(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.snapshotID = id
+}
+
+func (a *instanceCloneArtifacts) setTemplateID(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.templateID = id
+}
+
+// remaining returns the description of the artifacts still existing.
+func (a *instanceCloneArtifacts) remaining() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	list := make([]string, 0)
+
+	if a.snapshotID != "" {
+		list = append(list, fmt.Sprintf("snapshot %s (zone %s)", a.snapshotID, a.snapshotZone))
+	}
+	if a.templateID != "" {
+		list = append(list, fmt.Sprintf("template %s (zone %s)", a.templateID, a.templateZone))
+	}
+
+	return list
+}
+
+// remainingError returns err completed with the description of the artifacts
+// still existing, if any.
+func (a *instanceCloneArtifacts) remainingError(err error, description string) error {
+	if remaining := a.remaining(); err != nil && len(remaining) > 0 {
+		return fmt.Errorf("%s\nthe following %s: %s", err, description, strings.Join(remaining, ", "))
+	}
+	return err
+}
+
+// cleanup deletes the artifacts still existing.
+func (a *instanceCloneArtifacts) cleanup(message string) error {
+	steps := a.cleanupSteps()
+	if len(steps) == 0 {
+		return nil
+	}
+
+	// decorateAsyncSteps doesn't start new operations once the CLI has been
+	// interrupted or has timed out, and doesn't wait for the deletions to
+	// complete in "--no-wait" mode.
+	if gContext.Err() != nil || gNoWait {
+		for _, step := range steps {
+			if err := step.run(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return decorateAsyncSteps(message, steps...)
+}
+
+// cleanupSteps returns the steps deleting the artifacts still existing.
+func (a *instanceCloneArtifacts) cleanupSteps() []asyncStep {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	steps := make([]asyncStep, 0)
+
+	// The global context is cancelled once the CLI is interrupted or has
+	// timed out, the artifacts have to be deleted regardless.
+	ctx := func(zone string) context.Context {
+		return exoapi.WithEndpoint(context.Background(), exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+	}
+
+	if templateID := a.templateID; templateID != "" {
+		steps = append(steps, asyncStep{
+			name: fmt.Sprintf("Deleting template %s in zone %s", templateID, a.templateZone),
+			run: func() error {
+				if err := cs.DeleteTemplate(ctx(a.templateZone), a.templateZone, templateID); err != nil {
+					return err
+				}
+				a.setTemplateID("")
+				return nil
+			},
+		})
+	}
+
+	if snapshotID := a.snapshotID; snapshotID != "" {
+		steps = append(steps, asyncStep{
+			name: fmt.Sprintf("Deleting snapshot %s in zone %s", snapshotID, a.snapshotZone),
+			run: func() error {
+				if err := cs.DeleteSnapshot(ctx(a.snapshotZone), a.snapshotZone, snapshotID); err != nil {
+					return err
+				}
+				a.setSnapshotID("")
+				return nil
+			},
+		})
+	}
+
+	return steps
+}
+
+type instanceCloneCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"clone"`
+
+	Instance string `cli-arg:"#" cli-usage:"INSTANCE-NAME|ID"`
+
+	KeepArtifacts bool   `cli-usage:"don't delete the intermediate snapshot and template once the instance is created"`
+	Name          string `cli-usage:"cloned instance name (default: source instance name)"`
+	TargetZone    string `cli-usage:"zone to create the cloned instance into"`
+	Zone          string `cli-short:"z" cli-usage:"source instance zone"`
+}
+
+func (c *instanceCloneCmd) cmdAliases() []string { return nil }
+
+func (c *instanceCloneCmd) cmdShort() string { return "Clone a Compute instance to another zone" }
+
+func (c *instanceCloneCmd) cmdLong() string {
+	return fmt.Sprintf(`This command clones a Compute instance, possibly into another zone, by
+creating a snapshot of the source instance disk, exporting it, registering it
+as a template in the target zone, and creating a new instance from this
+template.
+
+The cloned instance has the same type, disk size, IPv6 setting, SSH key,
+labels, cloud-init user data and Security Groups as the source instance.
+Zone-local resources (Private Networks, Elastic IPs, Anti-Affinity Groups and
+Deploy Target) are not attached to the cloned instance.
+
+The intermediate snapshot and template are deleted once the instance has been
+created, unless the "--keep-artifacts" flag is specified. If the operation
+fails or is interrupted, the intermediate artifacts which could not be deleted
+are reported (as well as the cloned instance if it has been created).
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instanceShowOutput{}), ", "))
+}
+
+func (c *instanceCloneCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceCloneCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	if c.TargetZone == "" {
+		cmdExitOnUsageError(cmd, "no target zone specified")
+	}
+
+	// Snapshot export and template registration can take a _long time_,
+	// raising the Exoscale API client timeout as a precaution.
+	cs.Client.SetTimeout(30 * time.Minute)
+
+	srcCtx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+	dstCtx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.TargetZone))
+
+	source, err := cs.FindInstance(srcCtx, c.Zone, c.Instance)
+	if err != nil {
+		return err
+	}
+
+	srcTemplate, err := cs.GetTemplate(srcCtx, c.Zone, *source.TemplateID)
+	if err != nil {
+		return fmt.Errorf("error retrieving source instance template: %s", err)
+	}
+
+	if c.Name == "" {
+		c.Name = *source.Name
+	}
+
+	templateName := fmt.Sprintf("%s-clone-%d", *source.Name, time.Now().Unix())
+	templateDescription := fmt.Sprintf("Clone of instance %s (zone %s)", *source.ID, c.Zone)
+
+	var (
+		artifacts = instanceCloneArtifacts{snapshotZone: c.Zone, templateZone: c.TargetZone}
+		snapshot  *egoscale.Snapshot
+		export    *egoscale.SnapshotExport
+		template  *egoscale.Template
+		instance  *egoscale.Instance
+
+		// Guards instance, read by the interrupt hook concurrently with the
+		// step creating it.
+		instanceMu sync.Mutex
+	)
+
+	// If the CLI gets interrupted, the intermediate artifacts are cleaned up
+	// before exiting, and the resources left are reported.
+	defer onInterrupt(func() {
+		if !c.KeepArtifacts {
+			if err := artifacts.cleanup("Cleaning up intermediate artifacts..."); err != nil {
+				fmt.Fprintf(os.Stderr, "error cleaning up intermediate artifacts: %s\n", err)
+			}
+		}
+		if remaining := artifacts.remaining(); len(remaining) > 0 {
+			fmt.Fprintf(os.Stderr, "Intermediate artifacts remain: %s\n", strings.Join(remaining, ", "))
+		}
+		instanceMu.Lock()
+		defer instanceMu.Unlock()
+		if instance != nil && instance.ID != nil {
+			fmt.Fprintf(os.Stderr, "Instance %s (zone %s) has been created\n", *instance.ID, c.TargetZone)
+		}
+	})()
+
+	steps := []asyncStep{
+		{
+			name: fmt.Sprintf("Creating snapshot of instance %q", *source.Name),
+			run: func() (err error) {
+				if snapshot, err = source.CreateSnapshot(srcCtx); err == nil {
+					artifacts.setSnapshotID(*snapshot.ID)
+				}
+				return
+			},
+		},
+		{
+			name: "Exporting snapshot",
+			run: func() (err error) {
+				export, err = snapshot.Export(srcCtx)
+				return
+			},
+		},
+		{
+			name: fmt.Sprintf("Registering template in zone %s", c.TargetZone),
+			run: func() (err error) {
+				template, err = cs.RegisterTemplate(dstCtx, c.TargetZone, &egoscale.Template{
+					BootMode:        srcTemplate.BootMode,
+					Checksum:        export.MD5sum,
+					DefaultUser:     srcTemplate.DefaultUser,
+					Description:     &templateDescription,
+					Name:            &templateName,
+					PasswordEnabled: srcTemplate.PasswordEnabled,
+					SSHKeyEnabled:   srcTemplate.SSHKeyEnabled,
+					URL:             export.PresignedURL,
+				})
+				if err == nil {
+					artifacts.setTemplateID(*template.ID)
+				}
+				return
+			},
+		},
+		{
+			name: fmt.Sprintf("Creating instance %q in zone %s", c.Name, c.TargetZone),
+			run: func() error {
+				created, err := cs.CreateInstance(dstCtx, c.TargetZone, &egoscale.Instance{
+					DiskSize:         source.DiskSize,
+					IPv6Enabled:      source.IPv6Enabled,
+					InstanceTypeID:   source.InstanceTypeID,
+					Labels:           source.Labels,
+					Name:             &c.Name,
+					SSHKey:           source.SSHKey,
+					SecurityGroupIDs: source.SecurityGroupIDs,
+					TemplateID:       template.ID,
+					UserData:         source.UserData,
+				})
+				instanceMu.Lock()
+				instance = created
+				instanceMu.Unlock()
+				return err
+			},
+		},
+	}
+
+	err = decorateAsyncSteps(fmt.Sprintf("Cloning instance %q to zone %s...", *source.Name, c.TargetZone), steps...)
+
+	// In "--no-wait" mode the instance creation has only been submitted, the
+	// artifacts are handled as on success before returning the noWaitError.
+	var noWaitErr noWaitError
+	if errors.As(err, &noWaitErr) {
+		err = nil
+	}
+
+	if !c.KeepArtifacts {
+		cleanupErr := artifacts.cleanup("Cleaning up intermediate artifacts...")
+		if err == nil && cleanupErr != nil {
+			err = fmt.Errorf("error cleaning up intermediate artifacts: %s", cleanupErr)
+		}
+	}
+
+	if err != nil {
+		return artifacts.remainingError(err, "intermediate artifacts remain")
+	}
+	if remaining := artifacts.remaining(); len(remaining) > 0 {
+		fmt.Fprintf(os.Stderr, "Intermediate artifacts kept: %s\n", strings.Join(remaining, ", "))
+	}
+
+	if noWaitErr.id != "" {
+		return noWaitErr
+	}
+
+	if !gQuiet {
+		return output(showInstance(c.TargetZone, *instance.ID))
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(computeInstanceCmd, &instanceCloneCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
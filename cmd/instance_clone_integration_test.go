@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationInstanceCloneCleanupInterrupted(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	// The artifacts must be deleted even though the command has been
+	// interrupted (or has timed out).
+	ctx, cancel := context.WithCancel(gContext)
+	cancel()
+	gContext = ctx
+
+	artifacts := instanceCloneArtifacts{
+		snapshotZone: "ch-gva-2",
+		snapshotID:   testSnapshotID,
+		templateZone: "ch-gva-2",
+		templateID:   "7c8d9e0f-1a2b-4c3d-8e4f-5a6b7c8d9e0f",
+	}
+	require.NoError(t, artifacts.cleanup("Cleaning up intermediate artifacts..."))
+	require.Empty(t, artifacts.remaining())
+
+	server.request(http.MethodDelete, "/template/7c8d9e0f-1a2b-4c3d-8e4f-5a6b7c8d9e0f", 0)
+	server.request(http.MethodDelete, "/snapshot/"+testSnapshotID, 0)
+}
+
+func TestIntegrationInstanceCloneCleanupError(t *testing.T) {
+	setupIntegrationTest(t)
+
+	savedQuiet := gQuiet
+	t.Cleanup(func() { gQuiet = savedQuiet })
+	gQuiet = true
+
+	artifacts := instanceCloneArtifacts{
+		snapshotZone: "ch-gva-2",
+		snapshotID:   testSnapshotID,
+		templateZone: "ch-gva-2",
+		templateID:   "7c8d9e0f-1a2b-4c3d-8e4f-5a6b7c8d9e0f",
+	}
+	err := artifacts.cleanup("Cleaning up intermediate artifacts...")
+	require.Error(t, err)
+	require.Contains(t, artifacts.remainingError(err, "intermediate artifacts remain").Error(),
+		"the following intermediate artifacts remain: snapshot "+testSnapshotID+" (zone ch-gva-2), "+
+			"template 7c8d9e0f-1a2b-4c3d-8e4f-5a6b7c8d9e0f (zone ch-gva-2)")
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_instanceCloneArtifacts_remaining(t *testing.T) {
+	artifacts := instanceCloneArtifacts{snapshotZone: "ch-gva-2", templateZone: "de-fra-1"}
+	require.Empty(t, artifacts.remaining())
+	require.Empty(t, artifacts.cleanupSteps())
+
+	artifacts.snapshotID = "snap"
+	artifacts.templateID = "tpl"
+	require.Equal(t, []string{
+		"snapshot snap (zone ch-gva-2)",
+		"template tpl (zone de-fra-1)",
+	}, artifacts.remaining())
+
+	// The template must be deleted before the snapshot it's registered from.
+	steps := artifacts.cleanupSteps()
+	require.Len(t, steps, 2)
+	require.Equal(t, "Deleting template tpl in zone de-fra-1", steps[0].name)
+	require.Equal(t, "Deleting snapshot snap in zone ch-gva-2", steps[1].name)
+}
+
+func Test_instanceCloneArtifacts_remainingError(t *testing.T) {
+	artifacts := instanceCloneArtifacts{snapshotZone: "ch-gva-2", templateZone: "de-fra-1"}
+	require.NoError(t, artifacts.remainingError(nil, "intermediate artifacts remain"))
+
+	err := errors.New("error creating instance")
+	require.Equal(t, err, artifacts.remainingError(err, "intermediate artifacts remain"))
+
+	artifacts.templateID = "tpl"
+	require.NoError(t, artifacts.remainingError(nil, "intermediate artifacts remain"))
+	require.EqualError(t, artifacts.remainingError(err, "intermediate artifacts remain"),
+		"error creating instance\nthe following intermediate artifacts remain: template tpl (zone de-fra-1)")
+}
+
+func Test_instanceCloneArtifacts_concurrentAccess(t *testing.T) {
+	// The interrupt hook reads the artifacts while the steps record them.
+	artifacts := instanceCloneArtifacts{snapshotZone: "ch-gva-2", templateZone: "de-fra-1"}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		artifacts.setSnapshotID("snap")
+		artifacts.setTemplateID("tpl")
+	}()
+	_ = artifacts.remaining()
+	_ = artifacts.cleanupSteps()
+	<-done
+
+	require.Len(t, artifacts.remaining(), 2)
+}
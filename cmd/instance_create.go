@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"os"
 	"path"
 	"strings"
@@ -25,9 +29,18 @@ type instanceCreateCmd struct {
 	Name string `cli-arg:"#" cli-usage:"NAME"`
 
 	AntiAffinityGroups []string          `cli-flag:"anti-affinity-group" cli-usage:"instance Anti-Affinity Group NAME|ID (can be specified multiple times)"`
+	CleanupTemplate    bool              `cli-usage:"delete the temporary template registered from the snapshot specified with --from-snapshot once the instance is created"`
 	CloudInitFile      string            `cli-flag:"cloud-init" cli-usage:"instance cloud-init user data configuration file path"`
+	CloudInitTemplate  string            `cli-usage:"instance cloud-init user data configuration template file path"`
+	CloudInitVars      map[string]string `cli-flag:"cloud-init-var" cli-usage:"cloud-init user data template variable (format: key=value)"`
+	CopyConfig         bool              `cli-usage:"copy the type and Security Groups of the instance the snapshot specified with --from-snapshot belongs to"`
+	DNS                string            `cli-flag:"dns" cli-usage:"FQDN of the DNS A/AAAA records to create for the instance"`
+	DNSUpdate          bool              `cli-flag:"dns-update" cli-usage:"update the DNS records specified with --dns if they already exist"`
 	DeployTarget       string            `cli-usage:"instance Deploy Target NAME|ID"`
 	DiskSize           int64             `cli-usage:"instance disk size"`
+	FallbackType       bool              `cli-usage:"retry with the nearest instance type of the same family if the instance type is unavailable in the zone"`
+	Files              []string          `cli-flag:"file" cli-usage:"local file to write on the instance (format: LOCAL:PATH[:MODE], can be specified multiple times)"`
+	FromSnapshot       string            `cli-usage:"ID of a snapshot to create the instance from"`
 	IPv6               bool              `cli-flag:"ipv6" cli-usage:"enable IPv6 on instance"`
 	InstanceType       string            `cli-usage:"instance type (format: [FAMILY.]SIZE)"`
 	Labels             map[string]string `cli-flag:"label" cli-usage:"instance label (format: key=value)"`
@@ -43,14 +56,44 @@ func (c *instanceCreateCmd) cmdAliases() []string { return gCreateAlias }
 
 func (c *instanceCreateCmd) cmdShort() string { return "Create a Compute instance" }
 
+func (c *instanceCreateCmd) cmdDryRunSupported() {}
+
 func (c *instanceCreateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command creates a Compute instance.
 
+Using the "--dns FQDN" flag, an A record (and an AAAA record if IPv6 is
+enabled) pointing to the instance public IP address is created in the
+matching DNS domain hosted in the account once the instance is running. The
+records FQDN is stored in the "%s" instance label, so that they can be
+deleted along with the instance using the "--delete-dns" flag of the
+"exo compute instance delete" command.
+
+Using the "--from-snapshot SNAPSHOT-ID" flag, the instance is created from
+a snapshot of another instance in the same zone: the snapshot is exported and
+registered as a temporary template, which is deleted once the instance is
+created if the "--cleanup-template" flag is specified. The instance disk size
+defaults to the one of the snapshot source instance, and the new instance
+inherits nothing else from it unless the "--copy-config" flag is specified to
+also copy its type and Security Groups (unless explicitly set using the
+"--instance-type" and "--security-group" flags).
+
+Using the "--dry-run" flag, the instance creation request is printed instead
+of being sent to the API (unless "--cloud-init-template" is specified, in
+which case the rendered user data is printed). No single-use SSH key is
+registered in this mode, and "--from-snapshot" is not supported.
+
+%s
+
+%s
+
 Supported Compute instance type families: %s
 
 Supported Compute instance type sizes: %s
 
 Supported output template annotations: %s`,
+		instanceDNSLabel,
+		cloudInitTemplateHelp("zone", "name"),
+		cloudInitFilesHelp,
 		strings.Join(instanceTypeFamilies, ", "),
 		strings.Join(instanceTypeSizes, ", "),
 		strings.Join(outputterTemplateAnnotations(&instanceShowOutput{}), ", "))
@@ -58,16 +101,74 @@ Supported output template annotations: %s`,
 
 func (c *instanceCreateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.FromSnapshot == "" && (c.CleanupTemplate || c.CopyConfig) {
+		cmdExitOnUsageError(cmd, fmt.Sprintf(
+			"--%s and --%s flags require the --%s flag",
+			mustCLICommandFlagName(c, &c.CleanupTemplate),
+			mustCLICommandFlagName(c, &c.CopyConfig),
+			mustCLICommandFlagName(c, &c.FromSnapshot),
+		))
+	}
+
+	if c.FromSnapshot != "" && cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Template)) {
+		cmdExitOnUsageError(cmd, fmt.Sprintf(
+			"--%s and --%s flags are mutually exclusive",
+			mustCLICommandFlagName(c, &c.FromSnapshot),
+			mustCLICommandFlagName(c, &c.Template),
+		))
+	}
+
+	if gDryRun && c.FromSnapshot != "" {
+		cmdExitOnUsageError(cmd, fmt.Sprintf(
+			"--dry-run flag is not supported with --%s",
+			mustCLICommandFlagName(c, &c.FromSnapshot),
+		))
+	}
+
+	return validateCloudInitFlags(c.CloudInitFile, c.CloudInitTemplate, c.CloudInitVars, false)
 }
 
-func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
+func (c *instanceCreateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	var (
 		singleUseSSHPrivateKey *rsa.PrivateKey
 		singleUseSSHPublicKey  ssh.PublicKey
 		sshKey                 *egoscale.SSHKey
+		userData               []byte
+		err                    error
 	)
 
+	if c.CloudInitTemplate != "" {
+		userData, err = renderCloudInitTemplate(
+			c.CloudInitTemplate,
+			c.CloudInitVars,
+			map[string]string{"zone": c.Zone, "name": c.Name},
+		)
+		if err != nil {
+			return fmt.Errorf("error rendering cloud-init user data template: %s", err)
+		}
+	}
+
+	if len(c.Files) > 0 {
+		if c.CloudInitFile != "" {
+			if userData, err = ioutil.ReadFile(c.CloudInitFile); err != nil {
+				return fmt.Errorf("error reading cloud-init user data: %s", err)
+			}
+		}
+
+		if userData, err = addCloudInitFiles(userData, c.Files); err != nil {
+			return fmt.Errorf("error adding files to cloud-init user data: %s", err)
+		}
+	}
+
+	if gDryRun && c.CloudInitTemplate != "" {
+		fmt.Print(string(userData))
+		return nil
+	}
+
 	instance := &egoscale.Instance{
 		DeployTargetID: func() (v *string) {
 			if c.DeployTarget != "" {
@@ -94,6 +195,103 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
+	var (
+		snapshot       *egoscale.Snapshot
+		source         *egoscale.Instance
+		sourceTemplate *egoscale.Template
+		artifacts      = instanceCloneArtifacts{templateZone: c.Zone}
+	)
+
+	if c.FromSnapshot != "" {
+		if snapshot, err = cs.GetSnapshot(ctx, c.Zone, c.FromSnapshot); err != nil {
+			return fmt.Errorf("error retrieving snapshot: %s", err)
+		}
+
+		// The source instance is only required to copy its configuration
+		// and to default to its disk size: the snapshot can be orphaned
+		// (i.e. its source instance deleted) otherwise.
+		diskSizeSet := cmd.Flags().Changed(mustCLICommandFlagName(c, &c.DiskSize))
+		if source, err = snapshotSourceInstance(ctx, c.Zone, snapshot); err != nil {
+			if c.CopyConfig || !diskSizeSet {
+				return fmt.Errorf("error retrieving snapshot source instance: %s", err)
+			}
+			source = nil
+		}
+
+		if source == nil {
+			if c.CopyConfig {
+				return fmt.Errorf(
+					"the source instance of snapshot %q no longer exists, its configuration cannot be copied (--%s)",
+					*snapshot.ID,
+					mustCLICommandFlagName(c, &c.CopyConfig),
+				)
+			}
+			if !diskSizeSet {
+				return fmt.Errorf(
+					"the source instance of snapshot %q no longer exists, the disk size must be specified using the --%s flag",
+					*snapshot.ID,
+					mustCLICommandFlagName(c, &c.DiskSize),
+				)
+			}
+		}
+
+		// The source instance template is only used to inherit the temporary
+		// template properties (boot mode, default user...), which can be
+		// left to their default values if it has been deleted since.
+		if source != nil && source.TemplateID != nil {
+			if template, err := cs.GetTemplate(ctx, c.Zone, *source.TemplateID); err == nil {
+				sourceTemplate = template
+			}
+		}
+
+		if source != nil {
+			if !diskSizeSet {
+				c.DiskSize = *source.DiskSize
+			} else if c.DiskSize < *source.DiskSize {
+				return fmt.Errorf(
+					"snapshot %q requires a disk of at least %d GiB, but a disk size of %d GiB was requested",
+					*snapshot.ID,
+					*source.DiskSize,
+					c.DiskSize,
+				)
+			}
+		}
+
+		if c.CopyConfig {
+			instance.SecurityGroupIDs = source.SecurityGroupIDs
+			if !cmd.Flags().Changed(mustCLICommandFlagName(c, &c.InstanceType)) {
+				instance.InstanceTypeID = source.InstanceTypeID
+			}
+		}
+
+		// Snapshot export and template registration can take a _long time_,
+		// raising the Exoscale API client timeout as a precaution.
+		cs.Client.SetTimeout(30 * time.Minute)
+	} else {
+		templates, err := cs.ListTemplates(ctx, c.Zone, c.TemplateVisibility, "")
+		if err != nil {
+			return fmt.Errorf("error retrieving templates: %s", err)
+		}
+		for _, template := range templates {
+			if *template.ID == c.Template || *template.Name == c.Template {
+				if err := validateTemplateDiskSize(
+					*template.Name,
+					defaultInt64(template.Size, 0),
+					c.DiskSize,
+					mustCLICommandFlagName(c, &c.DiskSize),
+				); err != nil {
+					return err
+				}
+
+				instance.TemplateID = template.ID
+				break
+			}
+		}
+		if instance.TemplateID == nil {
+			return fmt.Errorf("no template %q found with visibility %s", c.Template, c.TemplateVisibility)
+		}
+	}
+
 	if l := len(c.AntiAffinityGroups); l > 0 {
 		antiAffinityGroupIDs := make([]string, l)
 		for i := range c.AntiAffinityGroups {
@@ -114,11 +312,13 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		instance.DeployTargetID = deployTarget.ID
 	}
 
-	instanceType, err := cs.FindInstanceType(ctx, c.Zone, c.InstanceType)
-	if err != nil {
-		return fmt.Errorf("error retrieving instance type: %s", err)
+	if instance.InstanceTypeID == nil {
+		instanceType, err := cs.FindInstanceType(ctx, c.Zone, c.InstanceType)
+		if err != nil {
+			return fmt.Errorf("error retrieving instance type: %s", err)
+		}
+		instance.InstanceTypeID = instanceType.ID
 	}
-	instance.InstanceTypeID = instanceType.ID
 
 	privateNetworks := make([]*egoscale.PrivateNetwork, len(c.PrivateNetworks))
 	if l := len(c.PrivateNetworks); l > 0 {
@@ -148,7 +348,7 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	}
 
 	// Generating a single-use SSH key pair for this instance.
-	if instance.SSHKey == nil {
+	if instance.SSHKey == nil && !gDryRun {
 		singleUseSSHPrivateKey, err = rsa.GenerateKey(rand.Reader, 2048)
 		if err != nil {
 			return fmt.Errorf("error generating SSH private key: %s", err)
@@ -175,21 +375,7 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		instance.SSHKey = sshKey.Name
 	}
 
-	templates, err := cs.ListTemplates(ctx, c.Zone, c.TemplateVisibility, "")
-	if err != nil {
-		return fmt.Errorf("error retrieving templates: %s", err)
-	}
-	for _, template := range templates {
-		if *template.ID == c.Template || *template.Name == c.Template {
-			instance.TemplateID = template.ID
-			break
-		}
-	}
-	if instance.TemplateID == nil {
-		return fmt.Errorf("no template %q found with visibility %s", c.Template, c.TemplateVisibility)
-	}
-
-	if c.CloudInitFile != "" {
+	if c.CloudInitFile != "" && len(c.Files) == 0 {
 		userData, err := getUserDataFromFile(c.CloudInitFile)
 		if err != nil {
 			return fmt.Errorf("error parsing cloud-init user data: %s", err)
@@ -197,52 +383,236 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		instance.UserData = &userData
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Creating instance %q...", c.Name), func() {
-		instance, err = cs.CreateInstance(ctx, c.Zone, instance)
+	if userData != nil {
+		encodedUserData, err := getUserData(userData)
 		if err != nil {
-			return
+			return fmt.Errorf("error parsing cloud-init user data: %s", err)
 		}
+		instance.UserData = &encodedUserData
+	}
 
-		for _, p := range privateNetworks {
-			if err = instance.AttachPrivateNetwork(ctx, p, nil); err != nil {
-				return
-			}
+	var dnsDomain, dnsRecordName string
+	if c.DNS != "" {
+		// Fail early if the DNS domain isn't hosted in the account.
+		if dnsDomain, dnsRecordName, err = instanceDNSDomain(c.DNS); err != nil {
+			return err
+		}
+
+		// Keep track of the records name, so that only these records are
+		// deleted along with the instance.
+		labels := map[string]string{instanceDNSLabel: dnsRecordFQDN(dnsRecordName, dnsDomain)}
+		for k, v := range c.Labels {
+			labels[k] = v
 		}
+		instance.Labels = &labels
+	}
+
+	if gDryRun {
+		return outputDryRun(instance)
+	}
+
+	steps := make([]asyncStep, 0)
+
+	if snapshot != nil {
+		var export *egoscale.SnapshotExport
+
+		steps = append(steps,
+			asyncStep{
+				name: fmt.Sprintf("Exporting snapshot %s", *snapshot.ID),
+				run: func() (err error) {
+					export, err = snapshot.Export(ctx)
+					return
+				},
+			},
+			asyncStep{
+				name: "Registering temporary template",
+				run: func() error {
+					templateName := fmt.Sprintf("%s-snapshot-%d", c.Name, time.Now().Unix())
+					templateDescription := fmt.Sprintf("Snapshot %s", *snapshot.ID)
+					if snapshot.InstanceID != nil {
+						templateDescription += fmt.Sprintf(" of instance %s", *snapshot.InstanceID)
+					}
+
+					// Without source template, the temporary template
+					// only supports SSH key authentication.
+					passwordEnabled, sshKeyEnabled := false, true
+					template := &egoscale.Template{
+						Checksum:        export.MD5sum,
+						Description:     &templateDescription,
+						Name:            &templateName,
+						PasswordEnabled: &passwordEnabled,
+						SSHKeyEnabled:   &sshKeyEnabled,
+						URL:             export.PresignedURL,
+					}
+					if sourceTemplate != nil {
+						template.BootMode = sourceTemplate.BootMode
+						template.DefaultUser = sourceTemplate.DefaultUser
+						template.PasswordEnabled = sourceTemplate.PasswordEnabled
+						template.SSHKeyEnabled = sourceTemplate.SSHKeyEnabled
+					}
+
+					template, err := cs.RegisterTemplate(ctx, c.Zone, template)
+					if err != nil {
+						return err
+					}
+					artifacts.setTemplateID(*template.ID)
+					instance.TemplateID = template.ID
+
+					return nil
+				},
+			},
+		)
+	}
+
+	steps = append(steps, asyncStep{
+		name: fmt.Sprintf("Creating instance %q", c.Name),
+		run: func() error {
+			return createWithInstanceTypeFallback(c.Zone, *instance.InstanceTypeID, c.FallbackType,
+				func(instanceTypeID string) error {
+					instance.InstanceTypeID = &instanceTypeID
+					created, err := cs.CreateInstance(ctx, c.Zone, instance)
+					if err != nil {
+						return err
+					}
+					instance = created
+					return nil
+				})
+		},
 	})
-	if err != nil {
-		return err
+
+	for _, p := range privateNetworks {
+		p := p
+		steps = append(steps, asyncStep{
+			name: fmt.Sprintf("Attaching Private Network %q", *p.Name),
+			run: func() error {
+				return instance.AttachPrivateNetwork(ctx, p, nil)
+			},
+		})
 	}
 
 	if singleUseSSHPrivateKey != nil {
-		privateKeyFilePath := getInstanceSSHKeyPath(*instance.ID)
+		steps = append(steps, asyncStep{
+			name: "Storing single-use SSH key",
+			run: func() error {
+				privateKeyFilePath := getInstanceSSHKeyPath(*instance.ID)
+
+				if err := os.MkdirAll(path.Dir(privateKeyFilePath), 0o700); err != nil {
+					return fmt.Errorf("error writing SSH private key file: %s", err)
+				}
+
+				if err := os.WriteFile(
+					privateKeyFilePath,
+					pem.EncodeToMemory(&pem.Block{
+						Type:  "RSA PRIVATE KEY",
+						Bytes: x509.MarshalPKCS1PrivateKey(singleUseSSHPrivateKey),
+					}),
+					0o600,
+				); err != nil {
+					return fmt.Errorf("error writing SSH private key file: %s", err)
+				}
+
+				if err := cs.DeleteSSHKey(ctx, c.Zone, *sshKey.Name); err != nil {
+					return fmt.Errorf("error deleting SSH key: %s", err)
+				}
+
+				return nil
+			},
+		})
+	}
 
-		if err = os.MkdirAll(path.Dir(privateKeyFilePath), 0o700); err != nil {
-			return fmt.Errorf("error writing SSH private key file: %s", err)
-		}
+	dnsRecords := make([]string, 0)
+	if c.DNS != "" {
+		steps = append(steps, asyncStep{
+			name: fmt.Sprintf("Creating DNS records %q", c.DNS),
+			run: func() error {
+				instance, err := cs.GetInstance(ctx, c.Zone, *instance.ID)
+				if err != nil {
+					return fmt.Errorf("error retrieving instance: %s", err)
+				}
+
+				addresses := map[string]*net.IP{"A": instance.PublicIPAddress, "AAAA": instance.IPv6Address}
+				for _, recordType := range []string{"A", "AAAA"} {
+					if addresses[recordType] == nil {
+						continue
+					}
+
+					record, err := upsertInstanceDNSRecord(
+						dnsDomain,
+						dnsRecordName,
+						recordType,
+						addresses[recordType].String(),
+						c.DNSUpdate,
+					)
+					if err != nil {
+						return fmt.Errorf("error creating DNS record: %s", err)
+					}
+
+					dnsRecords = append(dnsRecords, fmt.Sprintf("%s %s (ID %d)",
+						recordType, dnsRecordFQDN(record.Name, dnsDomain), record.ID))
+				}
+
+				return nil
+			},
+		})
+	}
 
-		if err = os.WriteFile(
-			privateKeyFilePath,
-			pem.EncodeToMemory(&pem.Block{
-				Type:  "RSA PRIVATE KEY",
-				Bytes: x509.MarshalPKCS1PrivateKey(singleUseSSHPrivateKey),
-			}),
-			0o600,
-		); err != nil {
-			return fmt.Errorf("error writing SSH private key file: %s", err)
-		}
+	err = decorateAsyncSteps(fmt.Sprintf("Creating instance %q...", c.Name), steps...)
+
+	// In "--no-wait" mode the last step has only been submitted, the
+	// temporary template is handled as on success before returning the
+	// noWaitError.
+	var noWaitErr noWaitError
+	if errors.As(err, &noWaitErr) {
+		err = nil
+	}
 
-		if err = cs.DeleteSSHKey(ctx, c.Zone, *sshKey.Name); err != nil {
-			return fmt.Errorf("error deleting SSH key: %s", err)
+	if c.CleanupTemplate {
+		cleanupErr := artifacts.cleanup("Cleaning up temporary template...")
+		if err == nil && cleanupErr != nil {
+			err = fmt.Errorf("error deleting temporary template: %s", cleanupErr)
 		}
 	}
 
+	if err != nil {
+		return artifacts.remainingError(err, "temporary template remains")
+	}
+	if remaining := artifacts.remaining(); len(remaining) > 0 {
+		fmt.Fprintf(os.Stderr, "Temporary template kept: %s\n", strings.Join(remaining, ", "))
+	}
+
+	if noWaitErr.id != "" {
+		return noWaitErr
+	}
+
 	if !gQuiet {
-		return output(showInstance(c.Zone, *instance.ID))
+		if err := output(showInstance(c.Zone, *instance.ID)); err != nil {
+			return err
+		}
+
+		// Reported on stderr to avoid altering the instance output format.
+		for _, r := range dnsRecords {
+			_, _ = fmt.Fprintf(os.Stderr, "DNS record: %s\n", r)
+		}
 	}
 
 	return nil
 }
 
+// snapshotSourceInstance returns the instance the snapshot was taken from,
+// or nil if it no longer exists.
+func snapshotSourceInstance(ctx context.Context, zone string, snapshot *egoscale.Snapshot) (*egoscale.Instance, error) {
+	if snapshot.InstanceID == nil {
+		return nil, nil
+	}
+
+	instance, err := cs.GetInstance(ctx, zone, *snapshot.InstanceID)
+	if errors.Is(err, exoapi.ErrNotFound) {
+		return nil, nil
+	}
+
+	return instance, err
+}
+
 func init() {
 	cobra.CheckErr(registerCLICommand(computeInstanceCmd, &instanceCreateCmd{
 		cliCommandSettings: defaultCLICmdSettings(),
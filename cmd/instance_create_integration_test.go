@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSnapshotID       = "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d"
+	testSnapshotSourceID = "6b7c8d9e-0f1a-4b2c-9d3e-4f5a6b7c8d9e"
+)
+
+func TestIntegrationInstanceCreateFromSnapshot(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	_, code := runCLI(t, "-Q", "compute", "instance", "create", "web2",
+		"--from-snapshot", testSnapshotID,
+		"--copy-config",
+		"--cleanup-template",
+		"--ssh-key", "mykey",
+		"-z", "ch-gva-2")
+	require.Equal(t, 0, code)
+
+	server.request(http.MethodPost, "/snapshot/"+testSnapshotID+":export", 0)
+
+	// The temporary template inherits the source instance template properties.
+	template := server.request(http.MethodPost, "/template", 0)
+	requireJSONField(t, template, "url", "https://sos-ch-gva-2.exo.io/snapshots/web1.qcow2?signature=x")
+	requireJSONField(t, template, "checksum", "d41d8cd98f00b204e9800998ecf8427e")
+	requireJSONField(t, template, "description", "Snapshot "+testSnapshotID+" of instance "+testSnapshotSourceID)
+	requireJSONField(t, template, "boot-mode", "uefi")
+	requireJSONField(t, template, "default-user", "ubuntu")
+	requireJSONField(t, template, "ssh-key-enabled", true)
+	requireJSONField(t, template, "password-enabled", false)
+
+	// The disk size defaults to the source instance one, and the source
+	// instance type and Security Groups are copied with --copy-config.
+	instance := server.request(http.MethodPost, "/instance", 0)
+	requireJSONField(t, instance, "disk-size", float64(50))
+	requireJSONField(t, instance, "instance-type.id", "b6cd1ff5-3a2f-4e9d-a4d1-8988c1191fe8")
+	requireJSONField(t, instance, "security-groups", []interface{}{
+		map[string]interface{}{"id": "3e4f5a6b-7c8d-4e9f-8a0b-1c2d3e4f5a6b"},
+	})
+	requireJSONField(t, instance, "template.id", "7c8d9e0f-1a2b-4c3d-8e4f-5a6b7c8d9e0f")
+
+	server.request(http.MethodDelete, "/template/7c8d9e0f-1a2b-4c3d-8e4f-5a6b7c8d9e0f", 0)
+}
+
+func TestIntegrationInstanceCreateFromSnapshotDiskTooSmall(t *testing.T) {
+	setupIntegrationTest(t)
+
+	// The disk can't be smaller than the snapshot source instance one.
+	_, code := runCLI(t, "-Q", "compute", "instance", "create", "web2",
+		"--from-snapshot", testSnapshotID, "--disk-size", "20", "--ssh-key", "mykey", "-z", "ch-gva-2")
+	require.Equal(t, 1, code)
+}
+
+func TestIntegrationInstanceCreateFromOrphanedSnapshot(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	_, code := runCLI(t, "-Q", "compute", "instance", "create", "web2",
+		"--from-snapshot", testSnapshotID,
+		"--disk-size", "20",
+		"--cleanup-template",
+		"--instance-type", "b6cd1ff5-3a2f-4e9d-a4d1-8988c1191fe8",
+		"--ssh-key", "mykey",
+		"-z", "ch-gva-2")
+	require.Equal(t, 0, code)
+
+	server.request(http.MethodPost, "/snapshot/"+testSnapshotID+":export", 0)
+
+	template := server.request(http.MethodPost, "/template", 0)
+	requireJSONField(t, template, "url", "https://sos-ch-gva-2.exo.io/snapshots/web1.qcow2?signature=x")
+	requireJSONField(t, template, "checksum", "d41d8cd98f00b204e9800998ecf8427e")
+	requireJSONField(t, template, "description", "Snapshot "+testSnapshotID+" of instance "+testSnapshotSourceID)
+	requireJSONField(t, template, "ssh-key-enabled", true)
+
+	instance := server.request(http.MethodPost, "/instance", 0)
+	requireJSONField(t, instance, "disk-size", float64(20))
+	requireJSONField(t, instance, "template.id", "7c8d9e0f-1a2b-4c3d-8e4f-5a6b7c8d9e0f")
+
+	// The temporary template is deleted once the instance is created.
+	server.request(http.MethodDelete, "/template/7c8d9e0f-1a2b-4c3d-8e4f-5a6b7c8d9e0f", 0)
+}
+
+func TestIntegrationInstanceCreateFromOrphanedSnapshotErrors(t *testing.T) {
+	setupIntegrationTest(t)
+
+	// The disk size of the deleted source instance is unknown.
+	_, code := runCLI(t, "-Q", "compute", "instance", "create", "web2",
+		"--from-snapshot", testSnapshotID, "--ssh-key", "mykey", "-z", "ch-gva-2")
+	require.Equal(t, 1, code)
+
+	// The configuration of the deleted source instance can't be copied.
+	_, code = runCLI(t, "-Q", "compute", "instance", "create", "web2",
+		"--from-snapshot", testSnapshotID, "--disk-size", "20", "--copy-config", "--ssh-key", "mykey", "-z", "ch-gva-2")
+	require.Equal(t, 1, code)
+}
@@ -14,17 +14,28 @@ type instanceDeleteCmd struct {
 
 	_ bool `cli-cmd:"delete"`
 
-	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Instance string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"instance" cli-pick:"instance"`
 
-	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
-	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+	DNS       string `cli-flag:"dns" cli-usage:"FQDN of the DNS A/AAAA records to delete with --delete-dns (default: the one specified at instance creation)"`
+	DeleteDNS bool   `cli-flag:"delete-dns" cli-usage:"delete the DNS A/AAAA records of the instance"`
+	Force     bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Zone      string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceDeleteCmd) cmdAliases() []string { return gRemoveAlias }
 
 func (c *instanceDeleteCmd) cmdShort() string { return "Delete a Compute instance" }
 
-func (c *instanceDeleteCmd) cmdLong() string { return "" }
+func (c *instanceDeleteCmd) cmdLong() string {
+	return fmt.Sprintf(`This command deletes a Compute instance.
+
+Using the "--delete-dns" flag, the A/AAAA records created using the "--dns"
+flag of the "exo compute instance create" command (whose FQDN is stored in
+the "%s" instance label) are deleted too. The FQDN of the records
+can be specified explicitly using the "--dns" flag. Only the records with
+this exact name pointing to the instance IP addresses are deleted.`,
+		instanceDNSLabel)
+}
 
 func (c *instanceDeleteCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
@@ -39,19 +50,57 @@ func (c *instanceDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	var dnsDomain, dnsRecordName string
+	if c.DeleteDNS {
+		fqdn := c.DNS
+		if fqdn == "" && instance.Labels != nil {
+			fqdn = (*instance.Labels)[instanceDNSLabel]
+		}
+		if fqdn == "" {
+			return fmt.Errorf("instance %q has no %q label, the FQDN of the DNS records to delete must be specified using --dns",
+				c.Instance, instanceDNSLabel)
+		}
+
+		// Fail early if the DNS domain isn't hosted in the account.
+		if dnsDomain, dnsRecordName, err = instanceDNSDomain(fqdn); err != nil {
+			return err
+		}
+	}
+
 	if !c.Force {
 		if !askQuestion(fmt.Sprintf("Are you sure you want to delete instance %q?", c.Instance)) {
 			return nil
 		}
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Deleting instance %q...", c.Instance), func() {
-		err = cs.DeleteInstance(ctx, c.Zone, *instance.ID)
+	err = decorateAsyncOperation(fmt.Sprintf("Deleting instance %q...", c.Instance), func() error {
+		return cs.DeleteInstance(ctx, c.Zone, *instance.ID)
 	})
 	if err != nil {
 		return err
 	}
 
+	if c.DeleteDNS {
+		ips := make([]string, 0)
+		if instance.PublicIPAddress != nil {
+			ips = append(ips, instance.PublicIPAddress.String())
+		}
+		if instance.IPv6Address != nil {
+			ips = append(ips, instance.IPv6Address.String())
+		}
+
+		deleted, err := deleteInstanceDNSRecords(dnsDomain, dnsRecordName, ips...)
+		if err != nil {
+			return err
+		}
+
+		if !gQuiet {
+			for _, r := range deleted {
+				fmt.Printf("DNS record %s %s (ID %d) deleted\n", r.RecordType, r.Name, r.ID)
+			}
+		}
+	}
+
 	instanceDir := path.Join(gConfigFolder, "instances", *instance.ID)
 	if _, err := os.Stat(instanceDir); !os.IsNotExist(err) {
 		if err := os.RemoveAll(instanceDir); err != nil {
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/exoscale/egoscale"
+)
+
+// instanceDNSLabel is the label of the Compute instances created with DNS
+// records, storing the records FQDN.
+const instanceDNSLabel = "exo-dns"
+
+// instanceDNSDomain returns the DNS domain hosted in the current account
+// matching the specified FQDN, along with the record name relative to this
+// domain. If several hosted domains match, the most specific one is
+// returned.
+func instanceDNSDomain(fqdn string) (string, string, error) {
+	fqdn = strings.ToLower(strings.TrimSuffix(fqdn, "."))
+
+	domains, err := csDNS.GetDomains(gContext)
+	if err != nil {
+		return "", "", fmt.Errorf("error retrieving DNS domains: %s", err)
+	}
+
+	var domain string
+	for _, d := range domains {
+		name := strings.ToLower(d.Name)
+		if (fqdn == name || strings.HasSuffix(fqdn, "."+name)) && len(name) > len(domain) {
+			domain = name
+		}
+	}
+
+	if domain == "" {
+		return "", "", fmt.Errorf("no DNS domain matching %q is hosted in this account", fqdn)
+	}
+
+	return domain, strings.TrimSuffix(strings.TrimSuffix(fqdn, domain), "."), nil
+}
+
+// upsertInstanceDNSRecord creates a DNS record of the specified type in
+// domain, or updates the existing one if update is true.
+func upsertInstanceDNSRecord(domain, name, recordType, content string, update bool) (*egoscale.DNSRecord, error) {
+	existing, err := csDNS.GetRecordsWithFilters(gContext, domain, name, recordType)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving DNS records: %s", err)
+	}
+
+	// An empty name (apex record) doesn't filter records by name, so we
+	// have to check for an exact match.
+	for _, r := range existing {
+		if r.Name != name || r.RecordType != recordType {
+			continue
+		}
+
+		if !update {
+			return nil, fmt.Errorf("%s record %q already exists in domain %q (use --dns-update to update it)",
+				recordType, name, domain)
+		}
+
+		return csDNS.UpdateRecord(gContext, domain, egoscale.UpdateDNSRecord{
+			ID:         r.ID,
+			DomainID:   r.DomainID,
+			Name:       name,
+			Content:    content,
+			RecordType: recordType,
+		})
+	}
+
+	return csDNS.CreateRecord(gContext, domain, egoscale.DNSRecord{
+		Name:       name,
+		Content:    content,
+		RecordType: recordType,
+	})
+}
+
+// deleteInstanceDNSRecords deletes the A/AAAA records named name in domain
+// pointing to the specified IP addresses, returning the deleted records.
+func deleteInstanceDNSRecords(domain, name string, ips ...string) ([]egoscale.DNSRecord, error) {
+	records, err := csDNS.GetRecordsWithFilters(gContext, domain, name, "")
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving DNS records: %s", err)
+	}
+
+	deleted := make([]egoscale.DNSRecord, 0)
+	for _, r := range matchInstanceDNSRecords(records, name, ips...) {
+		if err := csDNS.DeleteRecord(gContext, domain, r.ID); err != nil {
+			return deleted, fmt.Errorf("error deleting DNS record %d: %s", r.ID, err)
+		}
+		r.Name = dnsRecordFQDN(r.Name, domain)
+		deleted = append(deleted, r)
+	}
+
+	return deleted, nil
+}
+
+// matchInstanceDNSRecords returns the A/AAAA records among records named
+// name and pointing to one of the specified IP addresses.
+func matchInstanceDNSRecords(records []egoscale.DNSRecord, name string, ips ...string) []egoscale.DNSRecord {
+	matched := make([]egoscale.DNSRecord, 0)
+
+	// An empty name (apex record) doesn't filter records by name, so we
+	// have to check for an exact match.
+	for _, r := range records {
+		if r.Name != name || (r.RecordType != "A" && r.RecordType != "AAAA") {
+			continue
+		}
+
+		for _, ip := range ips {
+			if ip != "" && r.Content == ip {
+				matched = append(matched, r)
+				break
+			}
+		}
+	}
+
+	return matched
+}
+
+// dnsRecordFQDN returns the fully qualified name of a record of domain.
+func dnsRecordFQDN(name, domain string) string {
+	if name == "" {
+		return domain
+	}
+	return name + "." + domain
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/exoscale/egoscale"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_instanceDNSDomain(t *testing.T) {
+	newTestDNSClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/domains" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":"unexpected request %s %s"}`, r.Method, r.URL)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"domain":{"id":1,"name":"example.net"}},
+			{"domain":{"id":2,"name":"eu.example.net"}}
+		]`)
+	})
+
+	tests := []struct {
+		fqdn       string
+		wantDomain string
+		wantName   string
+		wantErr    bool
+	}{
+		{fqdn: "web.example.net", wantDomain: "example.net", wantName: "web"},
+		{fqdn: "Web.EU.example.net.", wantDomain: "eu.example.net", wantName: "web"},
+		{fqdn: "eu.example.net", wantDomain: "eu.example.net", wantName: ""},
+		{fqdn: "web.otherexample.net", wantErr: true},
+		{fqdn: "web.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fqdn, func(t *testing.T) {
+			domain, name, err := instanceDNSDomain(tt.fqdn)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDomain, domain)
+			require.Equal(t, tt.wantName, name)
+			require.Equal(t, strings.ToLower(strings.TrimSuffix(tt.fqdn, ".")), dnsRecordFQDN(name, domain))
+		})
+	}
+}
+
+func Test_matchInstanceDNSRecords(t *testing.T) {
+	records := []egoscale.DNSRecord{
+		{ID: 1, Name: "web", RecordType: "A", Content: "194.182.160.10"},
+		{ID: 2, Name: "web", RecordType: "AAAA", Content: "2a04:c43:e00::1"},
+		{ID: 3, Name: "web", RecordType: "A", Content: "194.182.160.11"},
+		{ID: 4, Name: "web", RecordType: "TXT", Content: "194.182.160.10"},
+		{ID: 5, Name: "", RecordType: "A", Content: "194.182.160.10"},
+		{ID: 6, Name: "alias", RecordType: "A", Content: "194.182.160.10"},
+	}
+
+	ids := func(records []egoscale.DNSRecord) []int64 {
+		v := make([]int64, 0)
+		for _, r := range records {
+			v = append(v, r.ID)
+		}
+		return v
+	}
+
+	require.Equal(t, []int64{1, 2},
+		ids(matchInstanceDNSRecords(records, "web", "194.182.160.10", "2a04:c43:e00::1")))
+
+	// Apex records must only match an empty name.
+	require.Equal(t, []int64{5}, ids(matchInstanceDNSRecords(records, "", "194.182.160.10")))
+
+	require.Empty(t, matchInstanceDNSRecords(records, "web", ""))
+	require.Empty(t, matchInstanceDNSRecords(records, "db", "194.182.160.10"))
+}
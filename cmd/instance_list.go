@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/dustin/go-humanize"
 	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
@@ -17,6 +19,11 @@ type instanceListItemOutput struct {
 	Type      string `json:"type"`
 	IPAddress string `json:"ip_address"`
 	State     string `json:"state"`
+
+	Template       string   `json:"template,omitempty" output:"wide"`
+	Created        string   `json:"created,omitempty" output:"wide"`
+	SSHKey         string   `json:"ssh_key,omitempty" output:"wide" outputLabel:"SSH Key"`
+	SecurityGroups []string `json:"security_groups,omitempty" output:"wide"`
 }
 
 type instanceListOutput []instanceListItemOutput
@@ -30,7 +37,10 @@ type instanceListCmd struct {
 
 	_ bool `cli-cmd:"list"`
 
-	Zone string `cli-short:"z" cli-usage:"zone to filter results to"`
+	CreatedAfter  string            `cli-usage:"only list instances created after the specified time (RFC3339 timestamp, date or relative duration e.g. \"90d\")"`
+	CreatedBefore string            `cli-usage:"only list instances created before the specified time (RFC3339 timestamp, date or relative duration e.g. \"90d\")"`
+	Selector      map[string]string `cli-usage:"only list instances having the specified label (format: key=value, can be repeated)"`
+	Zone          string            `cli-short:"z" cli-usage:"zone to filter results to"`
 }
 
 func (c *instanceListCmd) cmdAliases() []string { return gListAlias }
@@ -40,7 +50,16 @@ func (c *instanceListCmd) cmdShort() string { return "List Compute instances" }
 func (c *instanceListCmd) cmdLong() string {
 	return fmt.Sprintf(`This command lists Compute instances.
 
+Using the "wide" output format ("-O wide"), additional columns are displayed
+(template, creation age, SSH key and Security Groups).
+
+%s
+
+These filters are combined with the "--selector" flag: only instances matching
+all the filters are listed.
+
 Supported output template annotations: %s`,
+		createdAtFilterHelp,
 		strings.Join(outputterTemplateAnnotations(&instanceListItemOutput{}), ", "))
 }
 
@@ -51,6 +70,11 @@ func (c *instanceListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 func (c *instanceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	var zones []string
 
+	createdAt, err := newCreatedAtFilter(c.CreatedBefore, c.CreatedAfter)
+	if err != nil {
+		return err
+	}
+
 	if c.Zone != "" {
 		zones = []string{c.Zone}
 	} else {
@@ -61,7 +85,7 @@ func (c *instanceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	out := make(instanceListOutput, 0)
 	res := make(chan instanceListItemOutput)
-	defer close(res)
+	done := make(chan struct{})
 
 	instanceTypes := make(map[string]*egoscale.InstanceType) // For caching
 
@@ -69,14 +93,25 @@ func (c *instanceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		for instance := range res {
 			out = append(out, instance)
 		}
+		close(done)
 	}()
-	err := forEachZone(zones, func(zone string) error {
+	err = forEachZone(zones, func(zone string) error {
 		list, err := cs.ListInstances(ctx, zone)
 		if err != nil {
 			return fmt.Errorf("unable to list Compute instances in zone %s: %v", zone, err)
 		}
 
+		wide := instanceListWideResolver{
+			ctx:       exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone)),
+			zone:      zone,
+			templates: make(map[string]string),
+		}
+
 		for _, i := range list {
+			if !createdAt.match(i.CreatedAt) || !matchLabels(i.Labels, c.Selector) {
+				continue
+			}
+
 			instanceType, cached := instanceTypes[*i.InstanceTypeID]
 			if !cached {
 				instanceType, err = cs.GetInstanceType(ctx, zone, *i.InstanceTypeID)
@@ -89,7 +124,7 @@ func (c *instanceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 				instanceTypes[*i.InstanceTypeID] = instanceType
 			}
 
-			res <- instanceListItemOutput{
+			item := instanceListItemOutput{
 				ID:        *i.ID,
 				Name:      *i.Name,
 				Zone:      zone,
@@ -97,10 +132,22 @@ func (c *instanceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 				IPAddress: i.PublicIPAddress.String(),
 				State:     *i.State,
 			}
+
+			// The wide format columns require additional API requests,
+			// we only retrieve their information if requested.
+			if gOutputFormat == "wide" {
+				if err := wide.fill(&item, i); err != nil {
+					return err
+				}
+			}
+
+			res <- item
 		}
 
 		return nil
 	})
+	close(res)
+	<-done
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr,
 			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
@@ -109,6 +156,62 @@ func (c *instanceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	return output(&out, nil)
 }
 
+// instanceListWideResolver resolves the information displayed in the
+// "wide" output format columns for the Compute instances of a zone, caching
+// the related resources names.
+type instanceListWideResolver struct {
+	ctx            context.Context
+	zone           string
+	templates      map[string]string
+	securityGroups map[string]string
+}
+
+func (r *instanceListWideResolver) fill(item *instanceListItemOutput, instance *egoscale.Instance) error {
+	if instance.TemplateID != nil {
+		if _, cached := r.templates[*instance.TemplateID]; !cached {
+			// Templates can be deleted after instances creation, in which
+			// case we fall back to displaying the template ID.
+			r.templates[*instance.TemplateID] = *instance.TemplateID
+			if template, err := cs.GetTemplate(r.ctx, r.zone, *instance.TemplateID); err == nil {
+				r.templates[*instance.TemplateID] = *template.Name
+			}
+		}
+		item.Template = r.templates[*instance.TemplateID]
+	}
+
+	if instance.CreatedAt != nil {
+		item.Created = humanize.Time(*instance.CreatedAt)
+	}
+
+	if instance.SSHKey != nil {
+		item.SSHKey = *instance.SSHKey
+	}
+
+	if instance.SecurityGroupIDs != nil {
+		if r.securityGroups == nil {
+			securityGroups, err := cs.ListSecurityGroups(r.ctx, r.zone)
+			if err != nil {
+				return fmt.Errorf("unable to list Security Groups in zone %s: %v", r.zone, err)
+			}
+
+			r.securityGroups = make(map[string]string)
+			for _, sg := range securityGroups {
+				r.securityGroups[*sg.ID] = *sg.Name
+			}
+		}
+
+		for _, id := range *instance.SecurityGroupIDs {
+			name, ok := r.securityGroups[id]
+			if !ok {
+				name = id
+			}
+			item.SecurityGroups = append(item.SecurityGroups, name)
+		}
+	}
+
+	return nil
+}
+
 func init() {
 	cobra.CheckErr(registerCLICommand(computeInstanceCmd, &instanceListCmd{
 		cliCommandSettings: defaultCLICmdSettings(),
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/spf13/cobra"
+)
+
+// instancePoolAutoscaleLeaseLabel is the label of the Instance Pools
+// storing the lease of the autoscaling controller managing the pool, in the
+// "HOLDER EXPIRATION" format (the expiration being an RFC 3339 timestamp).
+const instancePoolAutoscaleLeaseLabel = "exo-autoscale-lease"
+
+// Actions decided by the autoscaling controller.
+const (
+	autoscaleActionNone     = "none"
+	autoscaleActionScaleOut = "scale-out"
+	autoscaleActionScaleIn  = "scale-in"
+)
+
+var instancePoolAutoscaleCmd = &cobra.Command{
+	Use:   "autoscale",
+	Short: "Instance Pools autoscaling",
+	Long: `These commands allow you to scale Instance Pools automatically according
+to a metric, using a controller running locally.`,
+}
+
+func init() {
+	instancePoolCmd.AddCommand(instancePoolAutoscaleCmd)
+}
+
+// instancePoolAutoscaleMetrics are the functions returning the value of the
+// metrics supported by the autoscaling controller, by metric name.
+var instancePoolAutoscaleMetrics = map[string]func(
+	ctx context.Context,
+	zone string,
+	instancePool *exov2.InstancePool,
+) (float64, error){
+	"nlb-unhealthy-ratio": nlbUnhealthyRatio,
+}
+
+// instancePoolAutoscaleMetricNames returns the sorted names of the metrics
+// supported by the autoscaling controller.
+func instancePoolAutoscaleMetricNames() []string {
+	names := make([]string, 0, len(instancePoolAutoscaleMetrics))
+	for name := range instancePoolAutoscaleMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// nlbUnhealthyRatio returns the ratio of the Instance Pool members reported
+// unhealthy by the healthchecks of the Network Load Balancer services
+// targeting the Instance Pool. As the pool is scaled out when the metric
+// value increases, members failing their healthcheck (e.g. because they are
+// overloaded) lead to a scale-out.
+func nlbUnhealthyRatio(ctx context.Context, zone string, instancePool *exov2.InstancePool) (float64, error) {
+	nlbs, err := cs.ListNetworkLoadBalancers(ctx, zone)
+	if err != nil {
+		return 0, err
+	}
+
+	var total, healthy int
+	for _, n := range nlbs {
+		// The services healthcheck status is only reported by the API when
+		// retrieving a Network Load Balancer individually.
+		nlb, err := cs.GetNetworkLoadBalancer(ctx, zone, *n.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, svc := range nlb.Services {
+			if defaultString(svc.InstancePoolID, "") != *instancePool.ID {
+				continue
+			}
+
+			for _, st := range svc.HealthcheckStatus {
+				total++
+				if defaultString(st.Status, "") == "success" {
+					healthy++
+				}
+			}
+		}
+	}
+
+	if total == 0 {
+		return 0, fmt.Errorf("no Network Load Balancer service healthcheck status reported for Instance Pool %q",
+			*instancePool.Name)
+	}
+
+	return float64(total-healthy) / float64(total), nil
+}
+
+// autoscalePolicy represents the scaling policy of an Instance Pool: the
+// pool is scaled out by one member when the metric value is above the
+// scale-out threshold, and scaled in by one member when it is below the
+// scale-in threshold, within the size bounds. After a scaling operation, no
+// other scale-out (resp. scale-in) occurs before the scale-out (resp.
+// scale-in) cooldown period expired.
+type autoscalePolicy struct {
+	min               int64
+	max               int64
+	scaleOutThreshold float64
+	scaleInThreshold  float64
+	scaleOutCooldown  time.Duration
+	scaleInCooldown   time.Duration
+}
+
+func (p *autoscalePolicy) validate() error {
+	switch {
+	case p.min < 1:
+		return errors.New("minimum Instance Pool size is 1")
+	case p.max < p.min:
+		return errors.New("maximum size must be greater than or equal to the minimum size")
+	case p.scaleInThreshold >= p.scaleOutThreshold:
+		return errors.New("scale-in threshold must be lower than the scale-out threshold")
+	case p.scaleOutCooldown < 0 || p.scaleInCooldown < 0:
+		return errors.New("cooldown periods must be positive")
+	}
+
+	return nil
+}
+
+// decide returns the desired size of an Instance Pool of the specified size
+// according to the metric value at time now, lastScaled being the time of
+// the last scaling operation (zero if none), along with the action to
+// perform and the reason of the decision.
+func (p *autoscalePolicy) decide(value float64, size int64, lastScaled, now time.Time) (int64, string, string) {
+	switch {
+	case size < p.min:
+		return p.min, autoscaleActionScaleOut, "size below minimum"
+	case size > p.max:
+		return p.max, autoscaleActionScaleIn, "size above maximum"
+	}
+
+	switch {
+	case value > p.scaleOutThreshold:
+		if size >= p.max {
+			return size, autoscaleActionNone, "metric above scale-out threshold, size at maximum"
+		}
+		if !lastScaled.IsZero() && now.Sub(lastScaled) < p.scaleOutCooldown {
+			return size, autoscaleActionNone, "metric above scale-out threshold, in scale-out cooldown"
+		}
+		return size + 1, autoscaleActionScaleOut, "metric above scale-out threshold"
+
+	case value < p.scaleInThreshold:
+		if size <= p.min {
+			return size, autoscaleActionNone, "metric below scale-in threshold, size at minimum"
+		}
+		if !lastScaled.IsZero() && now.Sub(lastScaled) < p.scaleInCooldown {
+			return size, autoscaleActionNone, "metric below scale-in threshold, in scale-in cooldown"
+		}
+		return size - 1, autoscaleActionScaleIn, "metric below scale-in threshold"
+	}
+
+	return size, autoscaleActionNone, "metric within thresholds"
+}
+
+// autoscaleLease represents the lease of the autoscaling controller managing
+// an Instance Pool.
+type autoscaleLease struct {
+	holder  string
+	expires time.Time
+}
+
+func (l autoscaleLease) String() string {
+	return l.holder + " " + l.expires.UTC().Format(time.RFC3339)
+}
+
+// autoscaleLeaseFromLabels returns the autoscaling controller lease stored in
+// the labels of an Instance Pool, or nil if there is none (or it is invalid).
+func autoscaleLeaseFromLabels(labels *map[string]string) *autoscaleLease {
+	if labels == nil {
+		return nil
+	}
+
+	parts := strings.Fields((*labels)[instancePoolAutoscaleLeaseLabel])
+	if len(parts) != 2 {
+		return nil
+	}
+
+	expires, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return nil
+	}
+
+	return &autoscaleLease{holder: parts[0], expires: expires}
+}
+
+// setInstancePoolAutoscaleLease stores the specified lease in the labels of
+// an Instance Pool, or removes it if lease is nil. The other labels of the
+// Instance Pool are left untouched.
+func setInstancePoolAutoscaleLease(
+	ctx context.Context,
+	zone string,
+	instancePool *exov2.InstancePool,
+	lease *autoscaleLease,
+) error {
+	labels := make(map[string]string)
+	if instancePool.Labels != nil {
+		for k, v := range *instancePool.Labels {
+			labels[k] = v
+		}
+	}
+
+	delete(labels, instancePoolAutoscaleLeaseLabel)
+	if lease != nil {
+		labels[instancePoolAutoscaleLeaseLabel] = lease.String()
+	}
+
+	return cs.UpdateInstancePool(ctx, zone, &exov2.InstancePool{ID: instancePool.ID, Labels: &labels})
+}
+
+// acquireInstancePoolAutoscaleLease acquires (or renews) the lease of the
+// autoscaling controller holder on an Instance Pool until time expires,
+// failing if the Instance Pool is managed by another controller holding an
+// unexpired lease. The up-to-date Instance Pool is returned.
+func acquireInstancePoolAutoscaleLease(
+	ctx context.Context,
+	zone string,
+	instancePoolID string,
+	holder string,
+	expires time.Time,
+) (*exov2.InstancePool, error) {
+	instancePool, err := cs.GetInstancePool(ctx, zone, instancePoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	if l := autoscaleLeaseFromLabels(instancePool.Labels); l != nil && l.holder != holder && time.Now().Before(l.expires) {
+		return nil, fmt.Errorf("Instance Pool %q is already autoscaled by %s (lease expiring at %s)",
+			*instancePool.Name, l.holder, l.expires.Local().Format(time.RFC3339))
+	}
+
+	if err := setInstancePoolAutoscaleLease(ctx, zone, instancePool, &autoscaleLease{
+		holder:  holder,
+		expires: expires,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to acquire autoscaling lease: %w", err)
+	}
+
+	// Ensure that no other controller acquired the lease concurrently.
+	if instancePool, err = cs.GetInstancePool(ctx, zone, instancePoolID); err != nil {
+		return nil, err
+	}
+	if l := autoscaleLeaseFromLabels(instancePool.Labels); l == nil || l.holder != holder {
+		return nil, fmt.Errorf("autoscaling lease of Instance Pool %q acquired by another controller",
+			*instancePool.Name)
+	}
+
+	return instancePool, nil
+}
+
+// releaseInstancePoolAutoscaleLease releases the lease of the autoscaling
+// controller holder on an Instance Pool, if still held.
+func releaseInstancePoolAutoscaleLease(ctx context.Context, zone, instancePoolID, holder string) error {
+	instancePool, err := cs.GetInstancePool(ctx, zone, instancePoolID)
+	if err != nil {
+		return err
+	}
+
+	if l := autoscaleLeaseFromLabels(instancePool.Labels); l == nil || l.holder != holder {
+		return nil
+	}
+
+	return setInstancePoolAutoscaleLease(ctx, zone, instancePool, nil)
+}
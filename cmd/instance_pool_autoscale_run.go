@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+// instancePoolAutoscaleMinInterval is the minimum interval between two
+// evaluations of the autoscaling metric.
+const instancePoolAutoscaleMinInterval = 10 * time.Second
+
+// instancePoolAutoscaleDecision represents a decision of the autoscaling
+// controller, logged as a JSON document on the standard output.
+type instancePoolAutoscaleDecision struct {
+	Time    time.Time `json:"time"`
+	Pool    string    `json:"pool"`
+	Zone    string    `json:"zone"`
+	Metric  string    `json:"metric"`
+	Value   *float64  `json:"value,omitempty"`
+	Size    int64     `json:"size"`
+	Desired int64     `json:"desired"`
+	Action  string    `json:"action"`
+	Reason  string    `json:"reason"`
+	Error   string    `json:"error,omitempty"`
+}
+
+type instancePoolAutoscaleRunCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"run"`
+
+	InstancePool string `cli-arg:"#" cli-usage:"INSTANCE-POOL-NAME|ID"`
+
+	DryRun            bool    `cli-usage:"only log the decisions, without scaling the Instance Pool"`
+	Interval          string  `cli-usage:"interval between two evaluations of the metric"`
+	Max               int64   `cli-usage:"maximum Instance Pool size"`
+	Metric            string  `cli-usage:"metric to scale the Instance Pool on"`
+	Min               int64   `cli-usage:"minimum Instance Pool size"`
+	ScaleInCooldown   string  `cli-usage:"minimum time between a scaling operation and a scale-in"`
+	ScaleInThreshold  float64 `cli-usage:"metric value below which the Instance Pool is scaled in"`
+	ScaleOutCooldown  string  `cli-usage:"minimum time between a scaling operation and a scale-out"`
+	ScaleOutThreshold float64 `cli-usage:"metric value above which the Instance Pool is scaled out"`
+	Zone              string  `cli-short:"z" cli-usage:"Instance Pool zone"`
+}
+
+func (c *instancePoolAutoscaleRunCmd) cmdAliases() []string { return nil }
+
+func (c *instancePoolAutoscaleRunCmd) cmdShort() string {
+	return "Run an Instance Pool autoscaling controller"
+}
+
+func (c *instancePoolAutoscaleRunCmd) cmdLong() string {
+	return fmt.Sprintf(`This command runs an autoscaling controller locally, evaluating the
+specified metric periodically and scaling the Instance Pool accordingly: the
+pool is scaled out by one member when the metric value is above the scale-out
+threshold, and scaled in by one member when it is below the scale-in
+threshold, within the minimum and maximum sizes. After a scaling operation,
+the pool is not scaled again before the cooldown period expired.
+
+Every decision is logged on the standard output as a JSON document (one per
+line). The controller runs until interrupted (e.g. using Ctrl-C).
+
+A single controller can manage an Instance Pool at a time: the controller
+holds a lease stored in the %q label of the Instance Pool, which
+is renewed at every evaluation and released when the controller stops. The
+lease of a controller that didn't stop gracefully expires after 3 intervals.
+
+Supported metrics:
+
+  * nlb-unhealthy-ratio: ratio of the Instance Pool members reported
+    unhealthy by the healthchecks of the Network Load Balancer services
+    targeting the pool (between 0 and 1): the pool is scaled out when too
+    many members fail their healthcheck (e.g. because they are overloaded),
+    and scaled in when (almost) all of them are healthy`,
+		instancePoolAutoscaleLeaseLabel)
+}
+
+func (c *instancePoolAutoscaleRunCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instancePoolAutoscaleRunCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	metric, ok := instancePoolAutoscaleMetrics[c.Metric]
+	if !ok {
+		return fmt.Errorf("unsupported metric %q, supported metrics are: %s",
+			c.Metric, strings.Join(instancePoolAutoscaleMetricNames(), ", "))
+	}
+
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid interval: %w", err)
+	}
+	if interval < instancePoolAutoscaleMinInterval {
+		return fmt.Errorf("minimum interval is %s", instancePoolAutoscaleMinInterval)
+	}
+
+	policy := autoscalePolicy{
+		min:               c.Min,
+		max:               c.Max,
+		scaleOutThreshold: c.ScaleOutThreshold,
+		scaleInThreshold:  c.ScaleInThreshold,
+	}
+	if policy.scaleOutCooldown, err = time.ParseDuration(c.ScaleOutCooldown); err != nil {
+		return fmt.Errorf("invalid scale-out cooldown: %w", err)
+	}
+	if policy.scaleInCooldown, err = time.ParseDuration(c.ScaleInCooldown); err != nil {
+		return fmt.Errorf("invalid scale-in cooldown: %w", err)
+	}
+	if err := policy.validate(); err != nil {
+		return err
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
+	if err != nil {
+		return err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	holder := fmt.Sprintf("%s:%d", hostname, os.Getpid())
+
+	instancePoolID := *instancePool.ID
+	instancePool, err = acquireInstancePoolAutoscaleLease(ctx, c.Zone, instancePoolID, holder,
+		time.Now().Add(3*interval))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The global context is cancelled once the CLI is interrupted, the
+		// lease has to be released regardless.
+		ctx := exoapi.WithEndpoint(context.Background(),
+			exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+		if err := releaseInstancePoolAutoscaleLease(ctx, c.Zone, instancePoolID, holder); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: unable to release the autoscaling lease: %s\n", err)
+		}
+	}()
+
+	log := json.NewEncoder(os.Stdout)
+	var lastScaled time.Time
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		decision := instancePoolAutoscaleDecision{
+			Time:   time.Now(),
+			Pool:   *instancePool.Name,
+			Zone:   c.Zone,
+			Metric: c.Metric,
+			Size:   *instancePool.Size,
+			Action: autoscaleActionNone,
+		}
+		decision.Desired = decision.Size
+
+		// Metric evaluation failures are logged without stopping the
+		// controller, as they are likely transient.
+		if value, err := metric(ctx, c.Zone, instancePool); err != nil {
+			decision.Reason = "metric unavailable"
+			decision.Error = err.Error()
+		} else {
+			decision.Value = &value
+			decision.Desired, decision.Action, decision.Reason = policy.decide(
+				value,
+				decision.Size,
+				lastScaled,
+				decision.Time,
+			)
+		}
+
+		if decision.Action != autoscaleActionNone && !c.DryRun {
+			if err := instancePool.Scale(ctx, decision.Desired); err != nil {
+				decision.Error = err.Error()
+			} else {
+				lastScaled = time.Now()
+			}
+		}
+
+		if err := log.Encode(decision); err != nil {
+			return err
+		}
+
+		select {
+		case <-gContext.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if instancePool, err = acquireInstancePoolAutoscaleLease(ctx, c.Zone, instancePoolID, holder,
+			time.Now().Add(3*interval)); err != nil {
+			return err
+		}
+	}
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instancePoolAutoscaleCmd, &instancePoolAutoscaleRunCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+		Interval:           "60s",
+		Max:                10,
+		Metric:             "nlb-unhealthy-ratio",
+		Min:                1,
+		ScaleInCooldown:    "5m",
+		ScaleInThreshold:   0.05,
+		ScaleOutCooldown:   "3m",
+		ScaleOutThreshold:  0.2,
+	}))
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_autoscalePolicy_decide(t *testing.T) {
+	policy := autoscalePolicy{
+		min:               2,
+		max:               4,
+		scaleOutThreshold: 0.8,
+		scaleInThreshold:  0.3,
+		scaleOutCooldown:  3 * time.Minute,
+		scaleInCooldown:   5 * time.Minute,
+	}
+	require.NoError(t, policy.validate())
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value      float64
+		size       int64
+		lastScaled time.Time
+		desired    int64
+		action     string
+	}{
+		{0.5, 1, time.Time{}, 2, autoscaleActionScaleOut},
+		{0.5, 5, time.Time{}, 4, autoscaleActionScaleIn},
+		{0.5, 3, time.Time{}, 3, autoscaleActionNone},
+		{0.9, 3, time.Time{}, 4, autoscaleActionScaleOut},
+		{0.9, 4, time.Time{}, 4, autoscaleActionNone},
+		{0.9, 3, now.Add(-time.Minute), 3, autoscaleActionNone},
+		{0.9, 3, now.Add(-4 * time.Minute), 4, autoscaleActionScaleOut},
+		{0.1, 3, time.Time{}, 2, autoscaleActionScaleIn},
+		{0.1, 2, time.Time{}, 2, autoscaleActionNone},
+		{0.1, 3, now.Add(-4 * time.Minute), 3, autoscaleActionNone},
+	} {
+		desired, action, _ := policy.decide(tc.value, tc.size, tc.lastScaled, now)
+		require.Equal(t, tc.desired, desired, tc)
+		require.Equal(t, tc.action, action, tc)
+	}
+}
+
+func Test_autoscalePolicy_validate(t *testing.T) {
+	for _, p := range []autoscalePolicy{
+		{min: 0, max: 2, scaleOutThreshold: 0.8, scaleInThreshold: 0.3},
+		{min: 3, max: 2, scaleOutThreshold: 0.8, scaleInThreshold: 0.3},
+		{min: 1, max: 2, scaleOutThreshold: 0.3, scaleInThreshold: 0.8},
+	} {
+		require.Error(t, p.validate(), p)
+	}
+}
+
+func Test_autoscaleLeaseFromLabels(t *testing.T) {
+	expires := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	lease := autoscaleLease{holder: "host:42", expires: expires}
+
+	labels := map[string]string{instancePoolAutoscaleLeaseLabel: lease.String()}
+	require.Equal(t, &lease, autoscaleLeaseFromLabels(&labels))
+
+	require.Nil(t, autoscaleLeaseFromLabels(nil))
+	require.Nil(t, autoscaleLeaseFromLabels(&map[string]string{instancePoolAutoscaleLeaseLabel: "host:42"}))
+	require.Nil(t, autoscaleLeaseFromLabels(&map[string]string{instancePoolAutoscaleLeaseLabel: "host:42 tomorrow"}))
+}
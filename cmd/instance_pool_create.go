@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	egoscale "github.com/exoscale/egoscale/v2"
@@ -16,18 +17,25 @@ type instancePoolCreateCmd struct {
 
 	AntiAffinityGroups []string          `cli-flag:"anti-affinity-group" cli-short:"a" cli-usage:"managed Compute instances Anti-Affinity Group NAME|ID (can be specified multiple times)"`
 	CloudInitFile      string            `cli-flag:"cloud-init" cli-short:"c" cli-usage:"cloud-init user data configuration file path"`
+	CloudInitTemplate  string            `cli-usage:"cloud-init user data configuration template file path"`
+	CloudInitVars      map[string]string `cli-flag:"cloud-init-var" cli-usage:"cloud-init user data template variable (format: key=value)"`
 	DeployTarget       string            `cli-usage:"managed Compute instances Deploy Target NAME|ID"`
 	Description        string            `cli-usage:"Instance Pool description"`
+	DryRun             bool              `cli-usage:"print the rendered cloud-init user data template without creating the Instance Pool"`
 	DiskSize           int64             `cli-flag:"disk" cli-short:"d" cli-usage:"managed Compute instances disk size"`
 	ElasticIPs         []string          `cli-flag:"elastic-ip" cli-short:"e" cli-usage:"managed Compute instances Elastic IP ADDRESS|ID (can be specified multiple times)"`
+	FallbackType       bool              `cli-usage:"retry with the nearest instance type of the same family if the instance type is unavailable in the zone"`
+	Files              []string          `cli-flag:"file" cli-usage:"local file to write on managed Compute instances (format: LOCAL:PATH[:MODE], can be specified multiple times)"`
 	IPv6               bool              `cli-flag:"ipv6" cli-short:"6" cli-usage:"enable IPv6 on managed Compute instances"`
 	InstancePrefix     string            `cli-usage:"string to prefix managed Compute instances names with"`
-	InstanceType       string            `cli-flag:"service-offering" cli-short:"o" cli-usage:"managed Compute instances type"`
+	InstanceType       string            `cli-short:"o" cli-usage:"managed Compute instances type" cli-renamed-from:"1.42.0:service-offering"`
 	Labels             map[string]string `cli-flag:"label" cli-usage:"Instance Pool label (format: key=value)"`
+	NoChecks           bool              `cli-usage:"skip the instance prefix collision check"`
 	PrivateNetworks    []string          `cli-flag:"privnet" cli-short:"p" cli-usage:"managed Compute instances Private Network NAME|ID (can be specified multiple times)"`
 	SSHKey             string            `cli-short:"k" cli-flag:"keypair" cli-usage:"SSH key to deploy on managed Compute instances"`
 	SecurityGroups     []string          `cli-flag:"security-group" cli-short:"s" cli-usage:"managed Compute instances Security Group NAME|ID (can be specified multiple times)"`
 	Size               int64             `cli-usage:"Instance Pool size"`
+	StrictNaming       bool              `cli-usage:"fail if the instance prefix is already used by another Instance Pool in the zone"`
 	Template           string            `cli-short:"t" cli-usage:"managed Compute instances template NAME|ID"`
 	TemplateFilter     string            `cli-usage:"managed Compute instances template filter"`
 	Zone               string            `cli-short:"z" cli-usage:"Instance Pool zone"`
@@ -40,16 +48,61 @@ func (c *instancePoolCreateCmd) cmdShort() string { return "Create an Instance P
 func (c *instancePoolCreateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command creates an Instance Pool.
 
+%s
+
+%s
+
+%s
+
 Supported output template annotations: %s`,
+		cloudInitTemplateHelp("zone", "name"),
+		cloudInitFilesHelp,
+		instancePrefixCheckHelp,
 		strings.Join(outputterTemplateAnnotations(&instancePoolShowOutput{}), ", "))
 }
 
 func (c *instancePoolCreateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	return validateCloudInitFlags(c.CloudInitFile, c.CloudInitTemplate, c.CloudInitVars, c.DryRun)
 }
 
 func (c *instancePoolCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	var (
+		userData []byte
+		err      error
+	)
+	if c.CloudInitTemplate != "" {
+		userData, err = renderCloudInitTemplate(
+			c.CloudInitTemplate,
+			c.CloudInitVars,
+			map[string]string{"zone": c.Zone, "name": c.Name},
+		)
+		if err != nil {
+			return fmt.Errorf("error rendering cloud-init user data template: %s", err)
+		}
+	}
+
+	if len(c.Files) > 0 {
+		if c.CloudInitFile != "" {
+			if userData, err = ioutil.ReadFile(c.CloudInitFile); err != nil {
+				return fmt.Errorf("error reading cloud-init user data: %s", err)
+			}
+		}
+
+		if userData, err = addCloudInitFiles(userData, c.Files); err != nil {
+			return fmt.Errorf("error adding files to cloud-init user data: %s", err)
+		}
+	}
+
+	if c.DryRun {
+		fmt.Print(string(userData))
+		return nil
+	}
+
 	instancePool := &egoscale.InstancePool{
 		DeployTargetID: func() (v *string) {
 			if c.DeployTarget != "" {
@@ -89,11 +142,38 @@ func (c *instancePoolCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
+	if !c.NoChecks {
+		if err := checkInstancePrefixCollisions(ctx, c.Zone, c.InstancePrefix, "", c.StrictNaming); err != nil {
+			return err
+		}
+	}
+
 	zoneV1, err := getZoneByNameOrID(c.Zone)
 	if err != nil {
 		return err
 	}
 
+	templateFilter, err := validateTemplateFilter(c.TemplateFilter)
+	if err != nil {
+		return err
+	}
+
+	template, err := getTemplateByNameOrID(zoneV1.ID, c.Template, templateFilter)
+	if err != nil {
+		return fmt.Errorf("error retrieving template: %s", err)
+	}
+	templateID := template.ID.String()
+	instancePool.TemplateID = &templateID
+
+	if err := validateTemplateDiskSize(
+		template.Name,
+		template.Size,
+		c.DiskSize,
+		mustCLICommandFlagName(c, &c.DiskSize),
+	); err != nil {
+		return err
+	}
+
 	if l := len(c.AntiAffinityGroups); l > 0 {
 		antiAffinityGroupIDs := make([]string, l)
 		for i := range c.AntiAffinityGroups {
@@ -160,19 +240,7 @@ func (c *instancePoolCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		instancePool.SSHKey = &gCurrentAccount.DefaultSSHKey
 	}
 
-	templateFilter, err := validateTemplateFilter(c.TemplateFilter)
-	if err != nil {
-		return err
-	}
-
-	template, err := getTemplateByNameOrID(zoneV1.ID, c.Template, templateFilter)
-	if err != nil {
-		return fmt.Errorf("error retrieving template: %s", err)
-	}
-	templateID := template.ID.String()
-	instancePool.TemplateID = &templateID
-
-	if c.CloudInitFile != "" {
+	if c.CloudInitFile != "" && len(c.Files) == 0 {
 		userData, err := getUserDataFromFile(c.CloudInitFile)
 		if err != nil {
 			return fmt.Errorf("error parsing cloud-init user data: %s", err)
@@ -180,8 +248,25 @@ func (c *instancePoolCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		instancePool.UserData = &userData
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Creating Instance Pool %q...", c.Name), func() {
-		instancePool, err = cs.CreateInstancePool(ctx, c.Zone, instancePool)
+	if userData != nil {
+		encodedUserData, err := getUserData(userData)
+		if err != nil {
+			return fmt.Errorf("error parsing cloud-init user data: %s", err)
+		}
+		instancePool.UserData = &encodedUserData
+	}
+
+	err = decorateAsyncOperation(fmt.Sprintf("Creating Instance Pool %q...", c.Name), func() error {
+		return createWithInstanceTypeFallback(c.Zone, *instancePool.InstanceTypeID, c.FallbackType,
+			func(instanceTypeID string) error {
+				instancePool.InstanceTypeID = &instanceTypeID
+				created, err := cs.CreateInstancePool(ctx, c.Zone, instancePool)
+				if err != nil {
+					return err
+				}
+				instancePool = created
+				return nil
+			})
 	})
 	if err != nil {
 		return err
@@ -2,29 +2,89 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
 
+// defaultInstancePoolDrainTimeout is the default time (in seconds) to wait
+// for the connections to drain once the NLB services referencing an
+// Instance Pool have been removed or reassigned.
+const defaultInstancePoolDrainTimeout = 30
+
+// instancePoolNLBService represents an NLB service referencing an Instance
+// Pool.
+type instancePoolNLBService struct {
+	nlb *egoscale.NetworkLoadBalancer
+	svc *egoscale.NetworkLoadBalancerService
+}
+
+// instancePoolNLBServices returns the NLB services referencing the
+// specified Instance Pool.
+func instancePoolNLBServices(nlbs []*egoscale.NetworkLoadBalancer, instancePoolID string) []instancePoolNLBService {
+	services := make([]instancePoolNLBService, 0)
+
+	for _, nlb := range nlbs {
+		for _, svc := range nlb.Services {
+			if defaultString(svc.InstancePoolID, "") == instancePoolID {
+				services = append(services, instancePoolNLBService{nlb: nlb, svc: svc})
+			}
+		}
+	}
+
+	return services
+}
+
 type instancePoolDeleteCmd struct {
 	_ bool `cli-cmd:"delete"`
 
-	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID"`
+	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"instance-pool"`
 
-	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
-	Zone  string `cli-short:"z" cli-usage:"Instance Pool zone"`
+	Drain        bool   `cli-usage:"remove the NLB services referencing the Instance Pool and wait for connections to drain before deleting it"`
+	DrainTimeout int64  `cli-usage:"time to wait for connections to drain in seconds (with --drain)"`
+	Force        bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	ReassignTo   string `cli-usage:"Instance Pool NAME|ID to reassign the NLB services to instead of removing them (with --drain)"`
+	Zone         string `cli-short:"z" cli-usage:"Instance Pool zone"`
 }
 
 func (c *instancePoolDeleteCmd) cmdAliases() []string { return gRemoveAlias }
 
 func (c *instancePoolDeleteCmd) cmdShort() string { return "Delete an Instance Pool" }
 
-func (c *instancePoolDeleteCmd) cmdLong() string { return "" }
+func (c *instancePoolDeleteCmd) cmdLong() string {
+	return `This command deletes an Instance Pool.
+
+An Instance Pool referenced by Network Load Balancer services cannot be
+deleted, unless the "--drain" flag is specified: the NLB services
+referencing the Instance Pool are then removed first (or reassigned to
+another Instance Pool using the "--reassign-to" flag, the services being
+re-created with the same settings and labels), and the Instance Pool is
+deleted once the connections have been given "--drain-timeout" seconds to
+drain.`
+}
 
 func (c *instancePoolDeleteCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if !c.Drain && (c.ReassignTo != "" || cmd.Flags().Changed(mustCLICommandFlagName(c, &c.DrainTimeout))) {
+		cmdExitOnUsageError(cmd, fmt.Sprintf(
+			"--%s and --%s flags require the --%s flag",
+			mustCLICommandFlagName(c, &c.ReassignTo),
+			mustCLICommandFlagName(c, &c.DrainTimeout),
+			mustCLICommandFlagName(c, &c.Drain),
+		))
+	}
+
+	if c.DrainTimeout < 0 {
+		cmdExitOnUsageError(cmd, "invalid drain timeout value")
+	}
+
+	return nil
 }
 
 func (c *instancePoolDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
@@ -35,41 +95,137 @@ func (c *instancePoolDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	// Ensure the Instance Pool is not attached to an NLB service.
-	nlbs, err := cs.ListNetworkLoadBalancers(gContext, c.Zone)
+	nlbs, err := cs.ListNetworkLoadBalancers(ctx, c.Zone)
 	if err != nil {
 		return fmt.Errorf("unable to list Network Load Balancers: %v", err)
 	}
+	services := instancePoolNLBServices(nlbs, *instancePool.ID)
+
+	// Ensure the Instance Pool is not attached to an NLB service.
+	if len(services) > 0 && !c.Drain {
+		return fmt.Errorf(
+			"Instance Pool %q is still referenced by NLB service %s/%s "+ // nolint:golint
+				`(use the "--drain" flag to remove the NLB services first)`,
+			*instancePool.Name,
+			*services[0].nlb.Name,
+			*services[0].svc.Name,
+		)
+	}
 
-	if !c.Force {
-		if !askQuestion(fmt.Sprintf("Are you sure you want to delete Instance Pool %q?", c.InstancePool)) {
-			return nil
+	var reassignTo *egoscale.InstancePool
+	if c.ReassignTo != "" {
+		if reassignTo, err = cs.FindInstancePool(ctx, c.Zone, c.ReassignTo); err != nil {
+			return fmt.Errorf("error retrieving Instance Pool to reassign the NLB services to: %w", err)
+		}
+		if *reassignTo.ID == *instancePool.ID {
+			return fmt.Errorf("cannot reassign the NLB services to the Instance Pool being deleted")
 		}
 	}
 
-	for _, nlb := range nlbs {
-		for _, svc := range nlb.Services {
-			if svc.InstancePoolID == instancePool.ID {
-				return fmt.Errorf(
-					"Instance Pool %q is still referenced by NLB service %s/%s", // nolint:golint
-					*instancePool.Name,
-					*nlb.Name,
-					*svc.Name,
-				)
-			}
+	question := fmt.Sprintf("Are you sure you want to delete Instance Pool %q?", c.InstancePool)
+	if len(services) > 0 {
+		action := "removed"
+		if reassignTo != nil {
+			action = fmt.Sprintf("reassigned to Instance Pool %q", *reassignTo.Name)
 		}
+		question = fmt.Sprintf("Are you sure you want to delete Instance Pool %q? "+
+			"%d NLB service(s) referencing it will be %s.", c.InstancePool, len(services), action)
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Deleting Instance Pool %q...", c.InstancePool), func() {
-		err = cs.DeleteInstancePool(ctx, c.Zone, *instancePool.ID)
-	})
-	if err != nil {
-		return err
+	if !askQuestion(question) {
+		return nil
 	}
 
-	return nil
+	steps := make([]asyncStep, 0)
+
+	for _, s := range services {
+		s := s
+		if reassignTo == nil {
+			steps = append(steps, asyncStep{
+				name: fmt.Sprintf("Removing NLB service %s/%s", *s.nlb.Name, *s.svc.Name),
+				run: func() error {
+					if err := s.nlb.DeleteService(ctx, s.svc); err != nil {
+						return err
+					}
+
+					if len(nlbServiceLabels(s.nlb, *s.svc.ID)) > 0 {
+						nlbSetServiceLabels(s.nlb, *s.svc.ID, nil)
+						return cs.UpdateNetworkLoadBalancer(ctx, c.Zone, s.nlb)
+					}
+					return nil
+				},
+			})
+			continue
+		}
+
+		steps = append(steps, asyncStep{
+			name: fmt.Sprintf("Reassigning NLB service %s/%s to Instance Pool %q",
+				*s.nlb.Name, *s.svc.Name, *reassignTo.Name),
+			run: func() error {
+				// The Instance Pool of an NLB service cannot be updated, the
+				// service is re-created with the same settings instead.
+				if err := s.nlb.DeleteService(ctx, s.svc); err != nil {
+					return err
+				}
+
+				labels := nlbServiceLabels(s.nlb, *s.svc.ID)
+				svc, err := s.nlb.AddService(ctx, &egoscale.NetworkLoadBalancerService{
+					Description:    s.svc.Description,
+					Healthcheck:    s.svc.Healthcheck,
+					InstancePoolID: reassignTo.ID,
+					Name:           s.svc.Name,
+					Port:           s.svc.Port,
+					Protocol:       s.svc.Protocol,
+					Strategy:       s.svc.Strategy,
+					TargetPort:     s.svc.TargetPort,
+				})
+				if err != nil {
+					return fmt.Errorf("NLB service %s/%s has been deleted but could not be re-created: %w\n"+
+						"it can be re-created using the following command:\n  %s",
+						*s.nlb.Name, *s.svc.Name, err,
+						nlbServiceAddCommand(c.Zone, s.nlb, s.svc, *reassignTo.ID, labels))
+				}
+
+				// The service labels are tied to the service ID.
+				if len(labels) > 0 {
+					nlbSetServiceLabels(s.nlb, *s.svc.ID, nil)
+					nlbSetServiceLabels(s.nlb, *svc.ID, labels)
+					if err := cs.UpdateNetworkLoadBalancer(ctx, c.Zone, s.nlb); err != nil {
+						return fmt.Errorf("unable to migrate NLB service %s/%s labels: %w", *s.nlb.Name, *s.svc.Name, err)
+					}
+				}
+
+				return nil
+			},
+		})
+	}
+
+	if len(services) > 0 && c.DrainTimeout > 0 {
+		steps = append(steps, asyncStep{
+			name: fmt.Sprintf("Waiting %ds for connections to drain", c.DrainTimeout),
+			run: func() error {
+				select {
+				case <-time.After(time.Duration(c.DrainTimeout) * time.Second):
+					return nil
+				case <-gContext.Done():
+					return gContext.Err()
+				}
+			},
+		})
+	}
+
+	steps = append(steps, asyncStep{
+		name: fmt.Sprintf("Deleting Instance Pool %q", *instancePool.Name),
+		run: func() error {
+			return cs.DeleteInstancePool(ctx, c.Zone, *instancePool.ID)
+		},
+	})
+
+	return decorateAsyncSteps(fmt.Sprintf("Deleting Instance Pool %q...", c.InstancePool), steps...)
 }
 
 func init() {
-	cobra.CheckErr(registerCLICommand(instancePoolCmd, &instancePoolDeleteCmd{}))
+	cobra.CheckErr(registerCLICommand(instancePoolCmd, &instancePoolDeleteCmd{
+		DrainTimeout: defaultInstancePoolDrainTimeout,
+	}))
 }
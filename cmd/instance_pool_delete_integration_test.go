@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationInstancePoolDeleteReassign(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	_, code := runCLI(t, "-Q", "instancepool", "delete", "web-pool",
+		"--drain", "--reassign-to", "api-pool", "--drain-timeout", "0", "--force", "-z", "ch-gva-2")
+	require.Equal(t, 0, code)
+
+	service := server.request(http.MethodPost, "/load-balancer/"+testNLBID+"/service", 0)
+	requireJSONField(t, service, "instance-pool.id", "4e5f6a7b-8c9d-4e0f-a1b2-c3d4e5f6a7b8")
+
+	// The service labels are migrated to the re-created service.
+	nlb := server.request(http.MethodPut, "/load-balancer/"+testNLBID, 0)
+	requireJSONField(t, nlb, "labels", map[string]interface{}{
+		"team": "web",
+		"svc.8d4f6b32-0a5c-4f3e-9b7d-2c1e4f6a8b03.env": "prod",
+	})
+
+	server.request(http.MethodDelete, "/instance-pool/9b4c7d2e-1f8a-4b3c-a5d6-e7f8091a2b3c", 0)
+}
+
+func TestIntegrationInstancePoolDeleteReassignError(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	// The Instance Pool must not be deleted if the service can't be
+	// re-created (no fixture matches its deletion).
+	_, code := runCLI(t, "-Q", "instancepool", "delete", "web-pool",
+		"--drain", "--reassign-to", "api-pool", "--drain-timeout", "0", "--force", "-z", "ch-gva-2")
+	require.Equal(t, 1, code)
+	server.request(http.MethodPost, "/load-balancer/"+testNLBID+"/service", 0)
+}
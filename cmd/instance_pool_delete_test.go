@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_instancePoolNLBServices(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	nlbs := []*egoscale.NetworkLoadBalancer{
+		{
+			Name: str("nlb1"),
+			Services: []*egoscale.NetworkLoadBalancerService{
+				{Name: str("http"), InstancePoolID: str("pool1")},
+				{Name: str("https"), InstancePoolID: str("pool2")},
+			},
+		},
+		{Name: str("nlb2")},
+		{
+			Name: str("nlb3"),
+			Services: []*egoscale.NetworkLoadBalancerService{
+				{Name: str("ssh"), InstancePoolID: str("pool1")},
+				{Name: str("none")},
+			},
+		},
+	}
+
+	services := instancePoolNLBServices(nlbs, "pool1")
+	require.Len(t, services, 2)
+	require.Equal(t, "nlb1", *services[0].nlb.Name)
+	require.Equal(t, "http", *services[0].svc.Name)
+	require.Equal(t, "nlb3", *services[1].nlb.Name)
+	require.Equal(t, "ssh", *services[1].svc.Name)
+
+	require.Empty(t, instancePoolNLBServices(nlbs, "pool3"))
+}
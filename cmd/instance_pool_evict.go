@@ -62,9 +62,9 @@ func (c *instancePoolEvictCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		instances[i] = *instance.ID
 	}
 
-	decorateAsyncOperation(
+	err = decorateAsyncOperation(
 		fmt.Sprintf("Evicting instances from Instance Pool %q...", c.InstancePool),
-		func() { err = instancePool.EvictMembers(ctx, instances) },
+		func() error { return instancePool.EvictMembers(ctx, instances) },
 	)
 	if err != nil {
 		return err
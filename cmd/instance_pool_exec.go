@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+const defaultInstancePoolExecParallel int64 = 10
+
+// linePrefixWriter is an io.Writer prefixing every line written with a
+// prefix, allowing several writers to share the same underlying writer
+// without interleaving their lines.
+type linePrefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+func (w *linePrefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the remaining buffered data not terminated by a newline.
+func (w *linePrefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+func (w *linePrefixWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := fmt.Fprintf(w.w, "%s%s", w.prefix, line)
+	return err
+}
+
+type instancePoolExecItemOutput struct {
+	Instance  string `json:"instance"`
+	IPAddress string `json:"ip_address"`
+	ExitCode  int    `json:"exit_code"`
+	Error     string `json:"error,omitempty"`
+}
+
+type instancePoolExecOutput []instancePoolExecItemOutput
+
+func (o *instancePoolExecOutput) toJSON()  { outputJSON(o) }
+func (o *instancePoolExecOutput) toText()  { outputText(o) }
+func (o *instancePoolExecOutput) toTable() { outputTable(o) }
+
+type instancePoolExecCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"exec"`
+
+	InstancePool string   `cli-arg:"#" cli-usage:"INSTANCE-POOL-NAME|ID"`
+	Command      []string `cli-arg:"*" cli-usage:"-- COMMAND ARG"`
+
+	IPv6     bool   `cli-flag:"ipv6" cli-short:"6" cli-usage:"connect to the instances via their IPv6 address"`
+	Login    string `cli-short:"l" cli-usage:"SSH username to use for logging in (default: instance template default username)"`
+	Parallel int64  `cli-short:"p" cli-usage:"maximum number of instances to execute the command on concurrently"`
+	Serial   bool   `cli-usage:"execute the command on one instance at a time (same as --parallel=1)"`
+	SSHOpts  string `cli-flag:"ssh-options" cli-short:"o" cli-usage:"additional options to pass to the ssh(1) command"`
+	Zone     string `cli-short:"z" cli-usage:"Instance Pool zone"`
+}
+
+func (c *instancePoolExecCmd) cmdAliases() []string { return nil }
+
+func (c *instancePoolExecCmd) cmdShort() string {
+	return "Execute a command on all Instance Pool members via SSH"
+}
+
+func (c *instancePoolExecCmd) cmdLong() string {
+	return fmt.Sprintf(`This command executes a command on every member of an Instance Pool via SSH
+(requires the ssh(1) command), concurrently on at most --parallel members at a
+time. The output lines of each member are prefixed with the member instance
+name, and a summary of the command exit code per member is printed once the
+command has completed on all members. The exit status is non-zero if the
+command failed on any member.
+
+As the command is executed non-interactively, the SSH host keys of the
+members must already be known, or the SSH options set accordingly:
+
+    exo instancepool exec -o "-o StrictHostKeyChecking=accept-new" my-pool -- systemctl restart app
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instancePoolExecItemOutput{}), ", "))
+}
+
+func (c *instancePoolExecCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instancePoolExecCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	if len(c.Command) == 0 {
+		cmdExitOnUsageError(cmd, "no command specified")
+	}
+
+	parallel := c.Parallel
+	switch {
+	case c.Serial && cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Parallel)):
+		cmdExitOnUsageError(cmd, "--serial and --parallel flags are mutually exclusive")
+	case c.Serial:
+		parallel = 1
+	case parallel < 1:
+		cmdExitOnUsageError(cmd, "--parallel value must be greater than 0")
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
+	if err != nil {
+		return err
+	}
+
+	if instancePool.InstanceIDs == nil || len(*instancePool.InstanceIDs) == 0 {
+		return fmt.Errorf("Instance Pool %q has no members", c.InstancePool)
+	}
+
+	if c.Login == "" {
+		template, err := cs.GetTemplate(ctx, c.Zone, *instancePool.TemplateID)
+		if err != nil {
+			return fmt.Errorf("error retrieving Instance Pool template: %s", err)
+		}
+		if template.DefaultUser != nil {
+			c.Login = *template.DefaultUser
+		}
+	}
+
+	members := make([]*exov2.Instance, len(*instancePool.InstanceIDs))
+	for i, id := range *instancePool.InstanceIDs {
+		if members[i], err = cs.GetInstance(ctx, c.Zone, id); err != nil {
+			return fmt.Errorf("unable to retrieve Compute instance %q: %s", id, err)
+		}
+	}
+
+	var (
+		out      = make(instancePoolExecOutput, len(members))
+		outputMu sync.Mutex
+		wg       sync.WaitGroup
+		workers  = make(chan struct{}, parallel)
+	)
+
+	for i, member := range members {
+		i, member := i, member
+
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() { <-workers; wg.Done() }()
+			out[i] = c.execOnMember(member, &outputMu)
+		}()
+	}
+	wg.Wait()
+
+	if !gQuiet {
+		if err := c.outputFunc(&out, nil); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, res := range out {
+		if res.ExitCode != 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("command failed on %d/%d Instance Pool members", failed, len(out))
+	}
+
+	return nil
+}
+
+// execOnMember executes the command on an Instance Pool member, writing its
+// output lines prefixed with the member name.
+func (c *instancePoolExecCmd) execOnMember(member *exov2.Instance, outputMu *sync.Mutex) instancePoolExecItemOutput {
+	res := instancePoolExecItemOutput{Instance: *member.Name}
+
+	ssh := instanceSSHCmd{IPv6: c.IPv6, Login: c.Login, SSHOpts: c.SSHOpts}
+	ssh.sshInfo.keyFile = getInstanceSSHKeyPath(*member.ID)
+	// Members can lack a public address, e.g. SKS Nodepool members
+	// created without public IP address.
+	address, addressType := member.PublicIPAddress, "public IPv4"
+	if c.IPv6 {
+		address, addressType = member.IPv6Address, "IPv6"
+	}
+	if address == nil {
+		res.ExitCode, res.Error = -1, fmt.Sprintf("instance has no %s address", addressType)
+		return res
+	}
+	ssh.sshInfo.ipAddress = address.String()
+	res.IPAddress = ssh.sshInfo.ipAddress
+
+	prefix := fmt.Sprintf("[%s] ", *member.Name)
+	stdout := &linePrefixWriter{w: os.Stdout, mu: outputMu, prefix: prefix}
+	stderr := &linePrefixWriter{w: os.Stderr, mu: outputMu, prefix: prefix}
+
+	args := append(ssh.buildSSHCommand()[1:], c.Command...)
+	cmd := exec.Command("ssh", args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	_ = stdout.Flush()
+	_ = stderr.Flush()
+
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			res.ExitCode = exitErr.ExitCode()
+		} else {
+			res.ExitCode, res.Error = -1, err.Error()
+		}
+	}
+
+	return res
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instancePoolCmd, &instancePoolExecCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+		Parallel:           defaultInstancePoolExecParallel,
+	}))
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_linePrefixWriter(t *testing.T) {
+	var (
+		out bytes.Buffer
+		mu  sync.Mutex
+	)
+
+	a := &linePrefixWriter{w: &out, mu: &mu, prefix: "[a] "}
+	b := &linePrefixWriter{w: &out, mu: &mu, prefix: "[b] "}
+
+	_, err := a.Write([]byte("first li"))
+	require.NoError(t, err)
+	_, err = b.Write([]byte("one\ntwo\nthr"))
+	require.NoError(t, err)
+	_, err = a.Write([]byte("ne\nsecond"))
+	require.NoError(t, err)
+	require.NoError(t, a.Flush())
+	require.NoError(t, b.Flush())
+	require.NoError(t, b.Flush())
+
+	require.Equal(t, "[b] one\n[b] two\n[a] first line\n[a] second\n[b] thr\n", out.String())
+}
+
+func Test_instancePoolExecCmd_execOnMember_noAddress(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		id   = "4e5f6a7b-8c9d-4e0f-a1b2-c3d4e5f6a7b8"
+		name = "pool-member"
+	)
+
+	// Members without public IP address are reported as failed instead of
+	// crashing the whole execution.
+	c := &instancePoolExecCmd{Command: []string{"uptime"}}
+	require.Equal(t, instancePoolExecItemOutput{
+		Instance: name,
+		ExitCode: -1,
+		Error:    "instance has no public IPv4 address",
+	}, c.execOnMember(&exov2.Instance{ID: &id, Name: &name}, &mu))
+
+	c.IPv6 = true
+	require.Equal(t, "instance has no IPv6 address", c.execOnMember(&exov2.Instance{ID: &id, Name: &name}, &mu).Error)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const testInstancePoolID = "9b4c7d2e-1f8a-4b3c-a5d6-e7f8091a2b3c"
+
+func TestIntegrationInstancePoolList(t *testing.T) {
+	setupIntegrationTest(t)
+
+	out, code := runCLI(t, "instancepool", "list", "-z", "ch-gva-2", "--selector", "team=web")
+	require.Equal(t, 0, code)
+	requireGoldenOutput(t, out)
+}
+
+func TestIntegrationInstancePoolScale(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	out, code := runCLI(t, "instancepool", "scale", "web-pool", "3", "-f", "-z", "ch-gva-2", "-O", "json")
+	require.Equal(t, 0, code)
+
+	requireJSONField(t, server.request(http.MethodPut, "/instance-pool/"+testInstancePoolID+":scale", 0), "size", 3)
+
+	pool := []byte(outputJSONLine(t, out))
+	requireJSONField(t, pool, "id", testInstancePoolID)
+	requireJSONField(t, pool, "size", 3)
+	requireJSONField(t, pool, "service_offering", "small")
+	requireJSONField(t, pool, "security_groups", []string{"web"})
+}
+
+func TestIntegrationInstancePoolScaleAPIError(t *testing.T) {
+	setupIntegrationTest(t)
+
+	_, code := runCLI(t, "instancepool", "scale", "web-pool", "100", "-f", "-z", "ch-gva-2")
+	require.Equal(t, 1, code)
+}
+
+func TestIntegrationInstancePoolUpdate(t *testing.T) {
+	server := setupIntegrationTest(t)
+	path := "/instance-pool/" + testInstancePoolID
+
+	_, code := runCLI(t, "-Q", "instancepool", "update", "web-pool", "-z", "ch-gva-2",
+		"--name", "web-pool-2",
+		"--description", "Web servers",
+		"--disk", "50",
+		"--ipv6",
+		"--keypair", "deploy",
+		"--label", "env=prod")
+	require.Equal(t, 0, code)
+
+	req := server.request(http.MethodPut, path, 0)
+	requireJSONField(t, req, "name", "web-pool-2")
+	requireJSONField(t, req, "description", "Web servers")
+	requireJSONField(t, req, "disk-size", 50)
+	requireJSONField(t, req, "ipv6-enabled", true)
+	requireJSONField(t, req, "ssh-key.name", "deploy")
+	requireJSONField(t, req, "labels", map[string]interface{}{"env": "prod"})
+
+	// Without label flags, the existing labels are left unchanged.
+	_, code = runCLI(t, "-Q", "instancepool", "update", "web-pool", "-z", "ch-gva-2",
+		"--description", "Web servers")
+	require.Equal(t, 0, code)
+	requireJSONField(t, server.request(http.MethodPut, path, 1), "labels", map[string]interface{}{"team": "web"})
+
+	_, code = runCLI(t, "-Q", "instancepool", "update", "web-pool", "-z", "ch-gva-2", "--clear-labels")
+	require.Equal(t, 0, code)
+	requireJSONField(t, server.request(http.MethodPut, path, 2), "labels", map[string]interface{}{})
+}
+
+func TestIntegrationInstancePoolAutoscaleNLBUnhealthyRatio(t *testing.T) {
+	setupIntegrationTest(t)
+
+	id, name := testInstancePoolID, "web"
+	value, err := nlbUnhealthyRatio(gContext, "ch-gva-2", &exov2.InstancePool{ID: &id, Name: &name})
+	require.NoError(t, err)
+	require.Equal(t, 0.5, value)
+
+	// Members failing their healthcheck must lead to a scale-out.
+	policy := autoscalePolicy{min: 1, max: 10, scaleOutThreshold: 0.2, scaleInThreshold: 0.05}
+	desired, action, _ := policy.decide(value, 4, time.Time{}, time.Now())
+	require.Equal(t, autoscaleActionScaleOut, action)
+	require.Equal(t, int64(5), desired)
+
+	desired, action, _ = policy.decide(0, 4, time.Time{}, time.Now())
+	require.Equal(t, autoscaleActionScaleIn, action)
+	require.Equal(t, int64(3), desired)
+}
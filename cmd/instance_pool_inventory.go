@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+)
+
+// ansibleGroupNameInvalidChars matches the characters not allowed in
+// Ansible inventory group names.
+var ansibleGroupNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// ansibleGroupName returns a valid Ansible inventory group name from v.
+func ansibleGroupName(v string) string {
+	return ansibleGroupNameInvalidChars.ReplaceAllString(v, "_")
+}
+
+// ansibleInventoryGroup represents an Ansible inventory group, as expected
+// by the "ansible-inventory --list" JSON format.
+type ansibleInventoryGroup struct {
+	Hosts    []string `json:"hosts,omitempty"`
+	Children []string `json:"children,omitempty"`
+}
+
+// instancePoolAnsibleInventory returns an Ansible inventory JSON document
+// listing the members of an Instance Pool, grouped by Instance Pool name
+// and zone. Members variables are provided in the "_meta.hostvars" section,
+// so that the document can be used as a dynamic inventory without requiring
+// Ansible to call the script for every host.
+func instancePoolAnsibleInventory(
+	ctx context.Context,
+	zone string,
+	instancePool *egoscale.InstancePool,
+) ([]byte, error) {
+	instances, err := instancePool.Instances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	privateNetworks, err := instancePool.PrivateNetworks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ansibleInventory(zone, instancePool, instances, privateNetworks)
+}
+
+// ansibleInventory returns the Ansible inventory JSON document listing the
+// Instance Pool members instances.
+func ansibleInventory(
+	zone string,
+	instancePool *egoscale.InstancePool,
+	instances []*egoscale.Instance,
+	privateNetworks []*egoscale.PrivateNetwork,
+) ([]byte, error) {
+	poolGroup := ansibleGroupName(*instancePool.Name)
+	zoneGroup := ansibleGroupName(zone)
+
+	groups := map[string]*ansibleInventoryGroup{
+		"all":     {Children: []string{poolGroup, zoneGroup}},
+		poolGroup: {Hosts: make([]string, 0)},
+		zoneGroup: {Hosts: make([]string, 0)},
+	}
+	hostVars := make(map[string]map[string]interface{})
+
+	for _, instance := range instances {
+		vars := map[string]interface{}{
+			"exoscale_id":            *instance.ID,
+			"exoscale_zone":          zone,
+			"exoscale_instance_pool": *instancePool.Name,
+			"exoscale_labels": func() map[string]string {
+				if instance.Labels != nil {
+					return *instance.Labels
+				}
+				return map[string]string{}
+			}(),
+		}
+
+		if instance.PublicIPAddress != nil {
+			vars["ansible_host"] = instance.PublicIPAddress.String()
+			vars["exoscale_public_ip"] = instance.PublicIPAddress.String()
+		}
+
+		if instance.IPv6Address != nil {
+			vars["exoscale_public_ipv6"] = instance.IPv6Address.String()
+		}
+
+		privateIPs := make(map[string]string)
+		for _, privateNetwork := range privateNetworks {
+			for _, lease := range privateNetwork.Leases {
+				if lease.InstanceID != nil && *lease.InstanceID == *instance.ID && lease.IPAddress != nil {
+					privateIPs[*privateNetwork.Name] = lease.IPAddress.String()
+				}
+			}
+		}
+		vars["exoscale_private_ips"] = privateIPs
+
+		groups[poolGroup].Hosts = append(groups[poolGroup].Hosts, *instance.Name)
+		groups[zoneGroup].Hosts = append(groups[zoneGroup].Hosts, *instance.Name)
+		hostVars[*instance.Name] = vars
+	}
+
+	inventory := make(map[string]interface{})
+	for name, group := range groups {
+		inventory[name] = group
+	}
+	inventory["_meta"] = map[string]interface{}{"hostvars": hostVars}
+
+	data, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode Ansible inventory: %s", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// requireAnsibleInventory checks that data is a valid "ansible-inventory
+// --list" JSON document: groups with hosts/children/vars, and the hosts
+// variables in the "_meta.hostvars" section.
+func requireAnsibleInventory(t *testing.T, data []byte) map[string]json.RawMessage {
+	var inventory map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &inventory))
+
+	var meta struct {
+		HostVars map[string]map[string]interface{} `json:"hostvars"`
+	}
+	require.Contains(t, inventory, "_meta")
+	require.NoError(t, json.Unmarshal(inventory["_meta"], &meta))
+	require.NotNil(t, meta.HostVars, "_meta.hostvars")
+
+	for name, raw := range inventory {
+		if name == "_meta" {
+			continue
+		}
+
+		var group map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(raw, &group), "group %s", name)
+		for k := range group {
+			require.Contains(t, []string{"hosts", "children", "vars"}, k, "group %s", name)
+		}
+
+		var g struct {
+			Hosts    []string               `json:"hosts"`
+			Children []string               `json:"children"`
+			Vars     map[string]interface{} `json:"vars"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &g), "group %s", name)
+		for _, host := range g.Hosts {
+			require.Contains(t, meta.HostVars, host, "group %s host", name)
+		}
+		for _, child := range g.Children {
+			require.Contains(t, inventory, child, "group %s child", name)
+		}
+	}
+
+	return inventory
+}
+
+func Test_ansibleInventory(t *testing.T) {
+	var (
+		str = func(s string) *string { return &s }
+		ip  = func(s string) *net.IP { v := net.ParseIP(s); return &v }
+	)
+
+	instancePool := &egoscale.InstancePool{ID: str("p1"), Name: str("web-pool")}
+	instances := []*egoscale.Instance{
+		{
+			ID:              str("i1"),
+			Name:            str("pool-1"),
+			PublicIPAddress: ip("194.182.160.10"),
+			IPv6Address:     ip("2a04:c43:e00::1"),
+			Labels:          &map[string]string{"role": "web"},
+		},
+		{ID: str("i2"), Name: str("pool-2")},
+	}
+	privateNetworks := []*egoscale.PrivateNetwork{{
+		ID:     str("pn1"),
+		Name:   str("backend"),
+		Leases: []*egoscale.PrivateNetworkLease{{InstanceID: str("i2"), IPAddress: ip("10.0.0.2")}},
+	}}
+
+	data, err := ansibleInventory("ch-gva-2", instancePool, instances, privateNetworks)
+	require.NoError(t, err)
+
+	inventory := requireAnsibleInventory(t, data)
+	require.JSONEq(t, `{"children":["web_pool","ch_gva_2"]}`, string(inventory["all"]))
+	require.JSONEq(t, `{"hosts":["pool-1","pool-2"]}`, string(inventory["web_pool"]))
+	require.JSONEq(t, `{"hosts":["pool-1","pool-2"]}`, string(inventory["ch_gva_2"]))
+	require.JSONEq(t, `{"hostvars":{
+		"pool-1":{
+			"ansible_host":"194.182.160.10",
+			"exoscale_id":"i1",
+			"exoscale_instance_pool":"web-pool",
+			"exoscale_labels":{"role":"web"},
+			"exoscale_private_ips":{},
+			"exoscale_public_ip":"194.182.160.10",
+			"exoscale_public_ipv6":"2a04:c43:e00::1",
+			"exoscale_zone":"ch-gva-2"
+		},
+		"pool-2":{
+			"exoscale_id":"i2",
+			"exoscale_instance_pool":"web-pool",
+			"exoscale_labels":{},
+			"exoscale_private_ips":{"backend":"10.0.0.2"},
+			"exoscale_zone":"ch-gva-2"
+		}
+	}}`, string(inventory["_meta"]))
+
+	// An empty Instance Pool yields a valid inventory.
+	data, err = ansibleInventory("ch-gva-2", instancePool, nil, nil)
+	require.NoError(t, err)
+	requireAnsibleInventory(t, data)
+}
@@ -10,11 +10,13 @@ import (
 )
 
 type instancePoolListItemOutput struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Zone  string `json:"zone"`
-	Size  int64  `json:"size"`
-	State string `json:"state"`
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Zone           string            `json:"zone"`
+	Size           int64             `json:"size"`
+	State          string            `json:"state"`
+	InstancePrefix string            `json:"instance_prefix" output:"wide"`
+	Labels         map[string]string `json:"labels"`
 }
 
 type instancePoolListOutput []instancePoolListItemOutput
@@ -26,7 +28,9 @@ func (o *instancePoolListOutput) toTable() { outputTable(o) }
 type instancePoolListCmd struct {
 	_ bool `cli-cmd:"list"`
 
-	Zone string `cli-short:"z" cli-usage:"zone to filter results to"`
+	Selector   map[string]string `cli-usage:"only list Instance Pools having the specified label (format: key=value, can be repeated)"`
+	ShowPrefix bool              `cli-usage:"display the effective instance prefix of the Instance Pools"`
+	Zone       string            `cli-short:"z" cli-usage:"zone to filter results to"`
 }
 
 func (c *instancePoolListCmd) cmdAliases() []string { return gListAlias }
@@ -36,6 +40,14 @@ func (c *instancePoolListCmd) cmdShort() string { return "List Instance Pools" }
 func (c *instancePoolListCmd) cmdLong() string {
 	return fmt.Sprintf(`This command lists Instance Pools.
 
+Results can be filtered on Instance Pool labels using the "--selector" flag,
+in which case only the Instance Pools matching all the specified labels are
+listed.
+
+Using the "--show-prefix" flag (implied by the "wide" output format), the
+effective prefix of the Instance Pools members names is displayed, to audit
+prefix collisions between Instance Pools of a zone.
+
 Supported output template annotations: %s`,
 		strings.Join(outputterTemplateAnnotations(&instancePoolListItemOutput{}), ", "))
 }
@@ -47,6 +59,10 @@ func (c *instancePoolListCmd) cmdPreRun(cmd *cobra.Command, args []string) error
 func (c *instancePoolListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	var zones []string
 
+	if c.ShowPrefix && (gOutputFormat == "" || gOutputFormat == "table") {
+		gOutputFormat = "wide"
+	}
+
 	if c.Zone != "" {
 		zones = []string{c.Zone}
 	} else {
@@ -57,12 +73,13 @@ func (c *instancePoolListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	out := make(instancePoolListOutput, 0)
 	res := make(chan instancePoolListItemOutput)
-	defer close(res)
+	done := make(chan struct{})
 
 	go func() {
 		for instancePool := range res {
 			out = append(out, instancePool)
 		}
+		close(done)
 	}()
 	err := forEachZone(zones, func(zone string) error {
 		list, err := cs.ListInstancePools(ctx, zone)
@@ -71,17 +88,30 @@ func (c *instancePoolListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		}
 
 		for _, i := range list {
+			if !matchLabels(i.Labels, c.Selector) {
+				continue
+			}
+
 			res <- instancePoolListItemOutput{
-				ID:    *i.ID,
-				Name:  *i.Name,
-				Zone:  zone,
-				Size:  *i.Size,
-				State: *i.State,
+				ID:             *i.ID,
+				Name:           *i.Name,
+				Zone:           zone,
+				Size:           *i.Size,
+				State:          *i.State,
+				InstancePrefix: effectiveInstancePrefix(i.InstancePrefix),
+				Labels: func() (v map[string]string) {
+					if i.Labels != nil {
+						v = *i.Labels
+					}
+					return
+				}(),
 			}
 		}
 
 		return nil
 	})
+	close(res)
+	<-done
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr,
 			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
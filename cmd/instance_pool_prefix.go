@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+)
+
+// defaultInstancePrefix is the prefix applied by the API to the names of the
+// members of Instance Pools (including SKS Nodepools) created without an
+// explicit instance prefix.
+const defaultInstancePrefix = "pool"
+
+// instancePrefixCheckHelp is the help text shared by the commands checking
+// for instance prefix collisions.
+const instancePrefixCheckHelp = `Instance Pools (including the ones backing SKS Nodepools) sharing the same
+instance prefix in a zone have colliding member names, which can be confusing
+in monitoring systems: when a collision is detected a warning is displayed,
+or an error is returned if the "--strict-naming" flag is set. This check can
+be skipped using the "--no-checks" flag.`
+
+// effectiveInstancePrefix returns the prefix actually used to name the
+// members of an Instance Pool configured with the instance prefix p.
+func effectiveInstancePrefix(p *string) string {
+	if p == nil || *p == "" {
+		return defaultInstancePrefix
+	}
+	return *p
+}
+
+// instancePrefixCollisions returns the sorted names of the Instance Pools
+// using the instance prefix, ignoring the Instance Pool excludeID (i.e. the
+// one being updated).
+func instancePrefixCollisions(instancePools []*egoscale.InstancePool, prefix, excludeID string) []string {
+	names := make([]string, 0)
+	for _, p := range instancePools {
+		if p.ID != nil && *p.ID == excludeID {
+			continue
+		}
+		if effectiveInstancePrefix(p.InstancePrefix) == prefix {
+			names = append(names, defaultString(p.Name, ""))
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// checkInstancePrefixCollisions looks up the Instance Pools of the zone using
+// the same effective instance prefix as prefix, ignoring the Instance Pool
+// excludeID. Collisions are reported as a warning, or as an error in strict
+// mode.
+func checkInstancePrefixCollisions(ctx context.Context, zone, prefix, excludeID string, strict bool) error {
+	prefix = effectiveInstancePrefix(&prefix)
+
+	instancePools, err := cs.ListInstancePools(ctx, zone)
+	if err != nil {
+		return fmt.Errorf("unable to check instance prefix collisions: %w", err)
+	}
+
+	collisions := instancePrefixCollisions(instancePools, prefix, excludeID)
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("instance prefix %q is already used in zone %s by Instance Pool(s) %s",
+		prefix, zone, strings.Join(collisions, ", "))
+	if strict {
+		return fmt.Errorf("%s (use a different --instance-prefix)", msg)
+	}
+
+	fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+
+	return nil
+}
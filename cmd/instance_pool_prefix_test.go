@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_effectiveInstancePrefix(t *testing.T) {
+	empty, custom := "", "web"
+
+	require.Equal(t, defaultInstancePrefix, effectiveInstancePrefix(nil))
+	require.Equal(t, defaultInstancePrefix, effectiveInstancePrefix(&empty))
+	require.Equal(t, "web", effectiveInstancePrefix(&custom))
+}
+
+func Test_instancePrefixCollisions(t *testing.T) {
+	newPool := func(id, name, prefix string) *egoscale.InstancePool {
+		p := &egoscale.InstancePool{ID: &id, Name: &name}
+		if prefix != "" {
+			p.InstancePrefix = &prefix
+		}
+		return p
+	}
+
+	pools := []*egoscale.InstancePool{
+		newPool("1", "web-b", "web"),
+		newPool("2", "batch", ""),
+		newPool("3", "web-a", "web"),
+		newPool("4", "nodepool-x", "pool"),
+	}
+
+	require.Equal(t, []string{"web-a", "web-b"}, instancePrefixCollisions(pools, "web", ""))
+	require.Equal(t, []string{"web-a"}, instancePrefixCollisions(pools, "web", "1"))
+	require.Equal(t, []string{"batch", "nodepool-x"}, instancePrefixCollisions(pools, defaultInstancePrefix, ""))
+	require.Empty(t, instancePrefixCollisions(pools, "db", ""))
+}
@@ -58,8 +58,8 @@ func (c *instancePoolScaleCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Scaling Instance Pool %q...", c.InstancePool), func() {
-		err = instancePool.Scale(ctx, c.Size)
+	err = decorateAsyncOperation(fmt.Sprintf("Scaling Instance Pool %q...", c.InstancePool), func() error {
+		return instancePool.Scale(ctx, c.Size)
 	})
 	if err != nil {
 		return err
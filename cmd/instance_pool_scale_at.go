@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+	"github.com/vbauerster/mpb/v4"
+	"github.com/vbauerster/mpb/v4/decor"
+)
+
+type instancePoolScaleAtCmd struct {
+	_ bool `cli-cmd:"scale-at"`
+
+	InstancePool string `cli-arg:"#" cli-usage:"INSTANCE-POOL-NAME|ID"`
+	Size         int64  `cli-arg:"#"`
+	Time         string `cli-arg:"#"`
+
+	Detach       bool   `cli-usage:"print a scheduling entry instead of waiting locally until the scaling time"`
+	DetachFormat string `cli-usage:"format of the scheduling entry printed in detached mode (systemd|cron)"`
+	Force        bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Zone         string `cli-short:"z" cli-usage:"Instance Pool zone"`
+}
+
+func (c *instancePoolScaleAtCmd) cmdAliases() []string { return nil }
+
+func (c *instancePoolScaleAtCmd) cmdShort() string {
+	return "Scale an Instance Pool size at a later time"
+}
+
+func (c *instancePoolScaleAtCmd) cmdLong() string {
+	return `This command scales an Instance Pool size at the specified time, which
+can be either absolute (e.g. "18:00" for the next occurrence of 6pm local
+time, or an RFC 3339 timestamp such as "2021-06-01T18:00:00+02:00") or
+relative to the current time (e.g. "+1h30m").
+
+By default, the command waits locally until the scaling time is reached, then
+performs the scaling operation: interrupting the command (e.g. using Ctrl-C)
+cancels the scheduled scaling. Using the "--detach" flag, the command prints
+a scheduling entry instead, which can be used to schedule the scaling using
+either systemd-run(1) or cron(8) depending on the "--detach-format" flag
+value. As cron doesn't support one-shot jobs, the cron entry only runs the
+command during the year of the scaling time, and can be removed afterwards.`
+}
+
+func (c *instancePoolScaleAtCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instancePoolScaleAtCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	if c.Size <= 0 {
+		return errors.New("minimum Instance Pool size is 1")
+	}
+
+	at, err := parseScheduleTime(c.Time, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if c.Detach {
+		entry, err := instancePoolScaleAtEntry(c.DetachFormat, at, c.Zone, c.InstancePool, c.Size)
+		if err != nil {
+			return err
+		}
+		fmt.Println(entry)
+		return nil
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
+	if err != nil {
+		return err
+	}
+
+	if !c.Force {
+		if !askQuestion(fmt.Sprintf(
+			"Are you sure you want to scale Instance Pool %q to %d at %s?",
+			c.InstancePool,
+			c.Size,
+			at.Format(time.RFC1123),
+		)) {
+			return nil
+		}
+	}
+
+	if !waitUntil(at, fmt.Sprintf("Waiting to scale Instance Pool %q to %d at %s...",
+		c.InstancePool, c.Size, at.Format("15:04:05"))) {
+		_, _ = fmt.Fprintf(os.Stderr, "Scaling of Instance Pool %q cancelled\n", c.InstancePool)
+		return errors.New("operation cancelled")
+	}
+
+	err = decorateAsyncOperation(fmt.Sprintf("Scaling Instance Pool %q...", c.InstancePool), func() error {
+		return instancePool.Scale(ctx, c.Size)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !gQuiet {
+		return output(showInstancePool(c.Zone, *instancePool.ID))
+	}
+
+	return nil
+}
+
+// parseScheduleTime parses the time specification v relative to now, which
+// can be either a duration prefixed with "+" (e.g. "+1h30m"), a local time
+// of day in the "HH:MM" format (the next occurrence being returned), or an
+// RFC 3339 timestamp.
+func parseScheduleTime(v string, now time.Time) (time.Time, error) {
+	if strings.HasPrefix(v, "+") {
+		d, err := time.ParseDuration(strings.TrimPrefix(v, "+"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid relative time %q: %s", v, err)
+		}
+		return now.Add(d), nil
+	}
+
+	if t, err := time.ParseInLocation("15:04", v, now.Location()); err == nil {
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(`invalid time %q: expected "+DURATION", "HH:MM" or RFC 3339 timestamp`, v)
+	}
+
+	if !at.After(now) {
+		return time.Time{}, fmt.Errorf("time %s is in the past", at.Format(time.RFC3339))
+	}
+
+	return at, nil
+}
+
+// instancePoolScaleAtEntry returns a scheduling entry in the specified format
+// for running the "exo instancepool scale" command at the time specified.
+func instancePoolScaleAtEntry(format string, at time.Time, zone, instancePool string, size int64) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine exo executable path: %s", err)
+	}
+
+	// The scheduled command has to use the same configuration file and
+	// account as the current one.
+	command := []string{exe}
+	if gConfigFileSelected {
+		command = append(command, "--config", gConfigFilePath)
+	}
+	if gAccountName != "" {
+		command = append(command, "--use-account", gAccountName)
+	}
+	command = append(command,
+		"--quiet", "instancepool", "scale", "--force", "--zone", zone, instancePool, fmt.Sprint(size))
+
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = shellQuote(arg)
+	}
+
+	switch format {
+	case "systemd":
+		return fmt.Sprintf("systemd-run --user --on-calendar=%s %s",
+			shellQuote(at.Local().Format("2006-01-02 15:04:05")),
+			strings.Join(quoted, " "),
+		), nil
+
+	case "cron":
+		// cron doesn't support one-shot jobs: the entry is triggered yearly,
+		// so the command only runs if the current year is the one specified.
+		// Note: "%" characters have to be escaped in crontab entries.
+		at = at.Local()
+		return fmt.Sprintf(`%d %d %d %d * test "$(date +\%%Y)" = %d && %s`,
+			at.Minute(),
+			at.Hour(),
+			at.Day(),
+			at.Month(),
+			at.Year(),
+			strings.ReplaceAll(strings.Join(quoted, " "), "%", `\%`),
+		), nil
+
+	default:
+		return "", fmt.Errorf("unsupported scheduling entry format %q", format)
+	}
+}
+
+// shellQuote returns s quoted for a POSIX shell, if required.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_@%+=:,./-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// waitUntil blocks until the specified time is reached, displaying a
+// progress spinner with the remaining time. If the global context is
+// cancelled (e.g. the user hit Ctrl-C) before the time is reached, false is
+// returned.
+func waitUntil(at time.Time, message string) bool {
+	p := mpb.New(
+		mpb.WithWidth(1),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), decorationsDisabled),
+	)
+
+	if gNoColor && !gQuiet {
+		fmt.Fprintf(os.Stderr, "%s (until %s)\n", message, at.Format(time.RFC3339))
+	}
+
+	spinner := p.AddSpinner(
+		1,
+		mpb.SpinnerOnLeft,
+		mpb.AppendDecorators(
+			decor.Name(message, decor.WC{W: len(message) + 1, C: decor.DidentRight}),
+			decor.Any(func(_ *decor.Statistics) string {
+				return fmt.Sprintf("(%s remaining)", time.Until(at).Round(time.Second))
+			}),
+		),
+		mpb.BarOnComplete("✔"),
+	)
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		spinner.Increment(1)
+		p.Wait()
+		return true
+
+	case <-gContext.Done():
+		spinner.Abort(false)
+		p.Wait()
+		return false
+	}
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instancePoolCmd, &instancePoolScaleAtCmd{
+		DetachFormat: "systemd",
+	}))
+}
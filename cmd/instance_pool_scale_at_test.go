@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseScheduleTime(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, loc)
+
+	tests := []struct {
+		v       string
+		want    time.Time
+		wantErr bool
+	}{
+		{v: "+1h30m", want: time.Date(2021, 6, 1, 13, 30, 0, 0, loc)},
+		{v: "18:00", want: time.Date(2021, 6, 1, 18, 0, 0, 0, loc)},
+		{v: "12:00", want: time.Date(2021, 6, 2, 12, 0, 0, 0, loc)},
+		{v: "08:15", want: time.Date(2021, 6, 2, 8, 15, 0, 0, loc)},
+		{v: "2021-06-01T18:00:00+02:00", want: time.Date(2021, 6, 1, 18, 0, 0, 0, loc)},
+		{v: "2021-06-01T08:00:00+02:00", wantErr: true},
+		{v: "+1 hour", wantErr: true},
+		{v: "tomorrow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.v, func(t *testing.T) {
+			got, err := parseScheduleTime(tt.v, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.want.Equal(got), "expected %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func Test_instancePoolScaleAtEntry(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	savedAccountName, savedConfigFilePath, savedConfigFileSelected := gAccountName, gConfigFilePath, gConfigFileSelected
+	t.Cleanup(func() {
+		gAccountName, gConfigFilePath, gConfigFileSelected = savedAccountName, savedConfigFilePath, savedConfigFileSelected
+	})
+	gAccountName = "my account"
+	gConfigFilePath, gConfigFileSelected = "/home/me/exoscale.toml", false
+
+	at := time.Date(2021, 6, 1, 18, 5, 0, 0, time.Local)
+	command := shellQuote(exe) + ` --use-account 'my account' --quiet instancepool scale --force --zone ch-gva-2 'it'\''s my pool' 3`
+
+	entry, err := instancePoolScaleAtEntry("systemd", at, "ch-gva-2", "it's my pool", 3)
+	require.NoError(t, err)
+	require.Equal(t, `systemd-run --user --on-calendar='2021-06-01 18:05:00' `+command, entry)
+
+	entry, err = instancePoolScaleAtEntry("cron", at, "ch-gva-2", "it's my pool", 3)
+	require.NoError(t, err)
+	require.Equal(t, `5 18 1 6 * test "$(date +\%Y)" = 2021 && `+command, entry)
+
+	_, err = instancePoolScaleAtEntry("at", at, "ch-gva-2", "it's my pool", 3)
+	require.Error(t, err)
+
+	// A configuration file selected using "--config" or EXOSCALE_CONFIG is
+	// forwarded to the scheduled command.
+	gConfigFilePath, gConfigFileSelected = "/home/me/my config.toml", true
+	entry, err = instancePoolScaleAtEntry("systemd", at, "ch-gva-2", "it's my pool", 3)
+	require.NoError(t, err)
+	require.Equal(t, `systemd-run --user --on-calendar='2021-06-01 18:05:00' `+shellQuote(exe)+
+		` --config '/home/me/my config.toml' --use-account 'my account' --quiet instancepool scale --force --zone ch-gva-2 'it'\''s my pool' 3`,
+		entry)
+}
+
+func Test_shellQuote(t *testing.T) {
+	require.Equal(t, "/usr/local/bin/exo", shellQuote("/usr/local/bin/exo"))
+	require.Equal(t, "--zone=ch-gva-2", shellQuote("--zone=ch-gva-2"))
+	require.Equal(t, "''", shellQuote(""))
+	require.Equal(t, "'my pool'", shellQuote("my pool"))
+	require.Equal(t, `'$(rm -rf ~)'`, shellQuote("$(rm -rf ~)"))
+	require.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
@@ -39,10 +39,11 @@ type instancePoolShowCmd struct {
 
 	_ bool `cli-cmd:"show"`
 
-	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID"`
+	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"instance-pool"`
 
-	ShowUserData bool   `cli-flag:"user-data" cli-short:"u" cli-usage:"show cloud-init user data configuration"`
-	Zone         string `cli-short:"z" cli-usage:"Instance Pool zone"`
+	MembersOutput string `cli-usage:"output the Instance Pool members in the specified format instead (ansible-inventory)"`
+	ShowUserData  bool   `cli-flag:"user-data" cli-short:"u" cli-usage:"show cloud-init user data configuration"`
+	Zone          string `cli-short:"z" cli-usage:"Instance Pool zone"`
 }
 
 func (c *instancePoolShowCmd) cmdAliases() []string { return gShowAlias }
@@ -52,6 +53,11 @@ func (c *instancePoolShowCmd) cmdShort() string { return "Show an Instance Pool
 func (c *instancePoolShowCmd) cmdLong() string {
 	return fmt.Sprintf(`This command shows an Instance Pool details.
 
+Using the "--members-output ansible-inventory" flag, the command outputs
+instead an Ansible inventory JSON document listing the Instance Pool members
+grouped by Instance Pool name and zone, suitable for use as an Ansible
+dynamic inventory (see "ansible-inventory --list").
+
 Supported output template annotations: %s`,
 		strings.Join(outputterTemplateAnnotations(&instancePoolShowOutput{}), ", "))
 }
@@ -62,6 +68,27 @@ func (c *instancePoolShowCmd) cmdPreRun(cmd *cobra.Command, args []string) error
 }
 
 func (c *instancePoolShowCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	if c.MembersOutput != "" {
+		if c.MembersOutput != "ansible-inventory" {
+			return fmt.Errorf("unsupported members output format %q", c.MembersOutput)
+		}
+
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+		instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
+		if err != nil {
+			return err
+		}
+
+		inventory, err := instancePoolAnsibleInventory(ctx, c.Zone, instancePool)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(inventory))
+		return nil
+	}
+
 	if c.ShowUserData {
 		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
@@ -76,7 +103,7 @@ func (c *instancePoolShowCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 				return fmt.Errorf("error decoding user data: %s", err)
 			}
 
-			cmd.Print(userData)
+			fmt.Print(userData)
 		}
 
 		return nil
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	exoapi "github.com/exoscale/egoscale/v2/api"
@@ -12,23 +11,29 @@ import (
 type instancePoolUpdateCmd struct {
 	_ bool `cli-cmd:"update"`
 
-	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID"`
+	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"instance-pool"`
 
 	AntiAffinityGroups []string          `cli-flag:"anti-affinity-group" cli-short:"a" cli-usage:"managed Compute instances Anti-Affinity Group NAME|ID (can be specified multiple times)"`
+	ClearLabels        bool              `cli-usage:"remove all Instance Pool labels"`
 	CloudInitFile      string            `cli-flag:"cloud-init" cli-short:"c" cli-usage:"cloud-init user data configuration file path"`
+	CloudInitTemplate  string            `cli-usage:"cloud-init user data configuration template file path"`
+	CloudInitVars      map[string]string `cli-flag:"cloud-init-var" cli-usage:"cloud-init user data template variable (format: key=value)"`
 	DeployTarget       string            `cli-usage:"managed Compute instances Deploy Target NAME|ID"`
 	Description        string            `cli-usage:"Instance Pool description"`
+	DryRun             bool              `cli-usage:"print the rendered cloud-init user data template without updating the Instance Pool"`
 	DiskSize           int64             `cli-flag:"disk" cli-short:"d" cli-usage:"managed Compute instances disk size"`
 	ElasticIPs         []string          `cli-flag:"elastic-ip" cli-short:"e" cli-usage:"managed Compute instances Elastic IP ADDRESS|ID (can be specified multiple times)"`
 	IPv6               bool              `cli-flag:"ipv6" cli-short:"6" cli-usage:"enable IPv6 on managed Compute instances"`
 	InstancePrefix     string            `cli-usage:"string to prefix managed Compute instances names with"`
-	InstanceType       string            `cli-flag:"service-offering" cli-short:"o" cli-usage:"managed Compute instances type"`
+	InstanceType       string            `cli-short:"o" cli-usage:"managed Compute instances type" cli-renamed-from:"1.42.0:service-offering"`
 	Labels             map[string]string `cli-flag:"label" cli-usage:"Instance Pool label (format: key=value)"`
 	Name               string            `cli-short:"n" cli-usage:"Instance Pool name"`
+	NoChecks           bool              `cli-usage:"skip the instance prefix collision check"`
 	PrivateNetworks    []string          `cli-flag:"privnet" cli-short:"p" cli-usage:"managed Compute instances Private Network NAME|ID (can be specified multiple times)"`
 	SSHKey             string            `cli-short:"k" cli-flag:"keypair" cli-usage:"SSH key to deploy on managed Compute instances"`
 	SecurityGroups     []string          `cli-flag:"security-group" cli-short:"s" cli-usage:"managed Compute instances Security Group NAME|ID (can be specified multiple times)"`
-	Size               int64             `cli-usage:"Instance Pool size. Deprecated, replaced by the 'exo instancepool scale' command."`
+	Size               int64             `cli-usage:"Instance Pool size" cli-deprecated:"1.42.0:exo instancepool scale"`
+	StrictNaming       bool              `cli-usage:"fail if the instance prefix is already used by another Instance Pool in the zone"`
 	Template           string            `cli-short:"t" cli-usage:"managed Compute instances template NAME|ID"`
 	TemplateFilter     string            `cli-usage:"managed Compute instances template filter"`
 	Zone               string            `cli-short:"z" cli-usage:"Instance Pool zone"`
@@ -41,14 +46,24 @@ func (c *instancePoolUpdateCmd) cmdShort() string { return "Update an Instance P
 func (c *instancePoolUpdateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command updates an Instance Pool.
 
+%s
+
+%s
+
 Supported output template annotations: %s`,
+		cloudInitTemplateHelp("zone", "name"),
+		instancePrefixCheckHelp,
 		strings.Join(outputterTemplateAnnotations(&instancePoolShowOutput{}), ", "),
 	)
 }
 
 func (c *instancePoolUpdateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	return validateCloudInitFlags(c.CloudInitFile, c.CloudInitTemplate, c.CloudInitVars, c.DryRun)
 }
 
 func (c *instancePoolUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
@@ -56,14 +71,77 @@ func (c *instancePoolUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
-	zoneV1, err := getZoneByNameOrID(c.Zone)
+	instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
 	if err != nil {
 		return err
 	}
 
-	instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
-	if err != nil {
-		return err
+	var userData []byte
+	if c.CloudInitTemplate != "" {
+		name := *instancePool.Name
+		if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Name)) {
+			name = c.Name
+		}
+
+		userData, err = renderCloudInitTemplate(
+			c.CloudInitTemplate,
+			c.CloudInitVars,
+			map[string]string{"zone": c.Zone, "name": name},
+		)
+		if err != nil {
+			return fmt.Errorf("error rendering cloud-init user data template: %s", err)
+		}
+
+		if c.DryRun {
+			fmt.Print(string(userData))
+			return nil
+		}
+	}
+
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Template)) {
+		zoneV1, err := getZoneByNameOrID(c.Zone)
+		if err != nil {
+			return err
+		}
+
+		templateFilter, err := validateTemplateFilter(c.TemplateFilter)
+		if err != nil {
+			return err
+		}
+
+		templateFlagVal, err := cmd.Flags().GetString("template")
+		if err != nil {
+			return err
+		}
+		template, err := getTemplateByNameOrID(zoneV1.ID, templateFlagVal, templateFilter)
+		if err != nil {
+			return fmt.Errorf("error retrieving template: %s", err)
+		}
+		templateID := template.ID.String()
+		instancePool.TemplateID = &templateID
+		updated = true
+	}
+
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.DiskSize)) {
+		instancePool.DiskSize = &c.DiskSize
+		updated = true
+	}
+
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.DiskSize)) ||
+		cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Template)) {
+		template, err := cs.GetTemplate(ctx, c.Zone, *instancePool.TemplateID)
+		if err != nil {
+			return fmt.Errorf("error retrieving template: %s", err)
+		}
+
+		if err := validateTemplateDiskSize(
+			*template.Name,
+			defaultInt64(template.Size, 0),
+			*instancePool.DiskSize,
+			mustCLICommandFlagName(c, &c.DiskSize),
+		); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.AntiAffinityGroups)) {
@@ -93,11 +171,6 @@ func (c *instancePoolUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		updated = true
 	}
 
-	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.DiskSize)) {
-		instancePool.DiskSize = &c.DiskSize
-		updated = true
-	}
-
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.ElasticIPs)) {
 		elasticIPIDs := make([]string, len(c.ElasticIPs))
 		for i, v := range c.ElasticIPs {
@@ -112,6 +185,17 @@ func (c *instancePoolUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.InstancePrefix)) {
+		if !c.NoChecks {
+			if err := checkInstancePrefixCollisions(
+				ctx,
+				c.Zone,
+				c.InstancePrefix,
+				*instancePool.ID,
+				c.StrictNaming,
+			); err != nil {
+				return err
+			}
+		}
 		instancePool.InstancePrefix = &c.InstancePrefix
 		updated = true
 	}
@@ -121,8 +205,12 @@ func (c *instancePoolUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		updated = true
 	}
 
-	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Labels)) {
-		instancePool.Labels = &c.Labels
+	if labels := labelsFromFlags(
+		c.Labels,
+		cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Labels)),
+		c.ClearLabels,
+	); labels != nil {
+		instancePool.Labels = labels
 		updated = true
 	}
 
@@ -171,55 +259,45 @@ func (c *instancePoolUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		updated = true
 	}
 
-	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Template)) {
-		templateFilter, err := validateTemplateFilter(c.TemplateFilter)
-		if err != nil {
-			return err
-		}
-
-		templateFlagVal, err := cmd.Flags().GetString("template")
-		if err != nil {
-			return err
-		}
-		template, err := getTemplateByNameOrID(zoneV1.ID, templateFlagVal, templateFilter)
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.CloudInitFile)) {
+		userData, err := getUserDataFromFile(c.CloudInitFile)
 		if err != nil {
-			return fmt.Errorf("error retrieving template: %s", err)
+			return fmt.Errorf("error parsing cloud-init user data: %s", err)
 		}
-		templateID := template.ID.String()
-		instancePool.TemplateID = &templateID
+		instancePool.UserData = &userData
 		updated = true
 	}
 
-	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.CloudInitFile)) {
-		userData, err := getUserDataFromFile(c.CloudInitFile)
+	if userData != nil {
+		encodedUserData, err := getUserData(userData)
 		if err != nil {
 			return fmt.Errorf("error parsing cloud-init user data: %s", err)
 		}
-		instancePool.UserData = &userData
+		instancePool.UserData = &encodedUserData
 		updated = true
 	}
 
+	scale := cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Size))
+	if updated && scale && gNoWait {
+		return errNoWaitUnsupported("when both updating and scaling the Instance Pool")
+	}
+
 	if updated {
-		decorateAsyncOperation(fmt.Sprintf("Updating Instance Pool %q...", c.InstancePool), func() {
-			if err = cs.UpdateInstancePool(ctx, c.Zone, instancePool); err != nil {
-				return
-			}
+		err = decorateAsyncOperation(fmt.Sprintf("Updating Instance Pool %q...", c.InstancePool), func() error {
+			return cs.UpdateInstancePool(ctx, c.Zone, instancePool)
 		})
 		if err != nil {
 			return err
 		}
 	}
 
-	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Size)) {
-		_, _ = fmt.Fprintln(
-			os.Stderr,
-			`WARNING: the "--size" flag is deprecated and replaced by the `+
-				`"exo instancepool scale" command, it will be removed in a future version.`,
-		)
-
-		decorateAsyncOperation(fmt.Sprintf("Scaling Instance Pool %q...", c.InstancePool), func() {
-			err = instancePool.Scale(ctx, c.Size)
+	if scale {
+		err = decorateAsyncOperation(fmt.Sprintf("Scaling Instance Pool %q...", c.InstancePool), func() error {
+			return instancePool.Scale(ctx, c.Size)
 		})
+		if err != nil {
+			return err
+		}
 	}
 
 	if !gQuiet {
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/exoscale/egoscale"
+	exov2 "github.com/exoscale/egoscale/v2"
+)
+
+// Power actions reported by the "exo compute instance stop/reboot" commands.
+const (
+	instancePowerActionStop                 = "stop"
+	instancePowerActionHardStop             = "hard-stop"
+	instancePowerActionHardStopAfterTimeout = "hard-stop-after-timeout"
+	instancePowerActionReboot               = "reboot"
+	instancePowerActionHardReboot           = "hard-reboot"
+)
+
+type instancePowerActionOutput struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Zone   string `json:"zone"`
+	Action string `json:"action"`
+}
+
+func (o *instancePowerActionOutput) toJSON()  { outputJSON(o) }
+func (o *instancePowerActionOutput) toText()  { outputText(o) }
+func (o *instancePowerActionOutput) toTable() { outputTable(o) }
+
+// errInstanceStopTimeout is returned when the graceful stop of an instance
+// didn't complete in time and no forced power-off was performed.
+var errInstanceStopTimeout = errors.New("instance graceful stop timed out")
+
+// hardStopInstance forcibly powers off a Compute instance, without waiting
+// for its operating system to shut down. The V2 API doesn't support forced
+// stops, the V1 API is used instead.
+func hardStopInstance(ctx context.Context, instance *exov2.Instance) error {
+	id, err := egoscale.ParseUUID(*instance.ID)
+	if err != nil {
+		return err
+	}
+
+	forced := true
+	_, err = cs.RequestWithContext(ctx, &egoscale.StopVirtualMachine{ID: id, Forced: &forced})
+	return err
+}
+
+// gracefulStopInstance stops a Compute instance gracefully, giving up after
+// timeout seconds if strictly positive. It returns true if the graceful stop
+// timed out.
+func gracefulStopInstance(ctx context.Context, instance *exov2.Instance, timeout int64) (bool, error) {
+	if timeout <= 0 {
+		return false, instance.Stop(ctx)
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	err := instance.Stop(stopCtx)
+	if err != nil && stopCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return true, nil
+	}
+
+	return false, err
+}
+
+// askHardStopAfterTimeout asks the user whether to forcibly power off an
+// instance the graceful stop of which timed out. Unlike confirmation
+// prompts, the "--force" flag doesn't grant this one implicitly.
+func askHardStopAfterTimeout(name string) (bool, error) {
+	resp, err := readInput(
+		bufio.NewReader(gStdin),
+		fmt.Sprintf("Instance %q did not stop in time. Force power-off (unsaved data may be lost)?", name),
+		"yN",
+	)
+	if err != nil {
+		return false, err
+	}
+
+	resp = strings.ToLower(resp)
+	return resp == "y" || resp == "yes", nil
+}
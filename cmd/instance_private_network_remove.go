@@ -36,7 +36,15 @@ Supported output template annotations: %s`,
 
 func (c *instancePrivnetRemoveCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if gNoWait && len(c.PrivateNetworks) > 1 {
+		return errNoWaitUnsupported("with several Private Networks")
+	}
+
+	return nil
 }
 
 func (c *instancePrivnetRemoveCmd) cmdRun(cmd *cobra.Command, _ []string) error {
@@ -60,12 +68,13 @@ func (c *instancePrivnetRemoveCmd) cmdRun(cmd *cobra.Command, _ []string) error
 		privateNetworks[i] = privateNetwork
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Updating instance %q Private Networks...", c.Instance), func() {
+	err = decorateAsyncOperation(fmt.Sprintf("Updating instance %q Private Networks...", c.Instance), func() error {
 		for _, privateNetwork := range privateNetworks {
-			if err = instance.DetachPrivateNetwork(ctx, privateNetwork); err != nil {
-				return
+			if err := instance.DetachPrivateNetwork(ctx, privateNetwork); err != nil {
+				return err
 			}
 		}
+		return nil
 	})
 	if err != nil {
 		return err
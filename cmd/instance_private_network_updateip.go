@@ -64,10 +64,8 @@ func (c *instancePrivnetUpdateIPCmd) cmdRun(cmd *cobra.Command, _ []string) erro
 		return fmt.Errorf("instance %q has no IP address in Private Network %q", c.Instance, c.PrivateNetwork)
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Updating instance %q Private Network IP address...", c.Instance), func() {
-		if err = privateNetwork.UpdateInstanceIPAddress(ctx, instance, instanceIPAddress); err != nil {
-			return
-		}
+	err = decorateAsyncOperation(fmt.Sprintf("Updating instance %q Private Network IP address...", c.Instance), func() error {
+		return privateNetwork.UpdateInstanceIPAddress(ctx, instance, instanceIPAddress)
 	})
 	if err != nil {
 		return err
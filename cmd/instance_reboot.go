@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
@@ -15,6 +16,7 @@ type instanceRebootCmd struct {
 	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
 
 	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Hard  bool   `cli-usage:"forcibly power off the instance then start it, without shutting down its operating system"`
 	Zone  string `cli-short:"z" cli-usage:"instance zone"`
 }
 
@@ -22,7 +24,16 @@ func (c *instanceRebootCmd) cmdAliases() []string { return nil }
 
 func (c *instanceRebootCmd) cmdShort() string { return "Reboot a Compute instance" }
 
-func (c *instanceRebootCmd) cmdLong() string { return "" }
+func (c *instanceRebootCmd) cmdLong() string {
+	return fmt.Sprintf(`This command reboots a Compute instance.
+
+The "--hard" flag forcibly powers the instance off then starts it again,
+without shutting down its operating system: unsaved data may be lost and file
+systems corrupted.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instancePowerActionOutput{}), ", "))
+}
 
 func (c *instanceRebootCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
@@ -38,18 +49,43 @@ func (c *instanceRebootCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	}
 
 	if !c.Force {
-		if !askQuestion(fmt.Sprintf("Are you sure you want to reboot instance %q?", c.Instance)) {
+		question := fmt.Sprintf("Are you sure you want to reboot instance %q?", c.Instance)
+		if c.Hard {
+			question = fmt.Sprintf("Are you sure you want to FORCIBLY POWER OFF and restart instance %q "+
+				"(unsaved data may be lost and file systems corrupted)?", c.Instance)
+		}
+		if !askQuestion(question) {
 			return nil
 		}
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Rebooting instance %q...", c.Instance), func() {
-		err = instance.Reboot(ctx)
-	})
+	action := instancePowerActionReboot
+	if c.Hard {
+		action = instancePowerActionHardReboot
+		err = decorateAsyncOperation(fmt.Sprintf("Powering off and restarting instance %q...", c.Instance), func() error {
+			if err = hardStopInstance(ctx, instance); err != nil {
+				return err
+			}
+			return instance.Start(ctx)
+		})
+	} else {
+		err = decorateAsyncOperation(fmt.Sprintf("Rebooting instance %q...", c.Instance), func() error {
+			return instance.Reboot(ctx)
+		})
+	}
 	if err != nil {
 		return err
 	}
 
+	if !gQuiet {
+		return c.outputFunc(&instancePowerActionOutput{
+			ID:     *instance.ID,
+			Name:   *instance.Name,
+			Zone:   c.Zone,
+			Action: action,
+		}, nil)
+	}
+
 	return nil
 }
 
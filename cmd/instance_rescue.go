@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+// instanceRescueProfiles are the alternate boot targets supported by the
+// API when starting a Compute instance.
+var instanceRescueProfiles = []instanceRescueProfileOutput{
+	{Name: "netboot", Description: "boot from the network rescue system (legacy BIOS)"},
+	{Name: "netboot-efi", Description: "boot from the network rescue system (UEFI)"},
+}
+
+func isInstanceRescueProfile(name string) bool {
+	for _, p := range instanceRescueProfiles {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func instanceRescueProfileNames() []string {
+	names := make([]string, len(instanceRescueProfiles))
+	for i, p := range instanceRescueProfiles {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// startInstanceWithRescueProfile starts a Compute instance using the specified
+// rescue profile as boot target, and waits for the operation to complete.
+func startInstanceWithRescueProfile(ctx context.Context, zone, id, profile string) error {
+	body, err := json.Marshal(map[string]string{"rescue-profile": profile})
+	if err != nil {
+		return err
+	}
+
+	signer, err := exoapi.NewSecurityProvider(gCurrentAccount.Key, gCurrentAccount.APISecret())
+	if err != nil {
+		return err
+	}
+
+	// The egoscale Instance.Start() method doesn't support request
+	// parameters: the request body is set here, and the request has to be
+	// signed again as the body is part of the signature.
+	res, err := cs.StartInstanceWithResponse(exoapi.WithZone(ctx, zone), id,
+		func(ctx context.Context, req *http.Request) error {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			req.ContentLength = int64(len(body))
+			req.Header.Set("Content-Type", "application/json")
+			return signer.Intercept(ctx, req)
+		})
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil {
+		return fmt.Errorf("API error: %s: %s", res.Status(), strings.TrimSpace(string(res.Body)))
+	}
+
+	return waitForOperation(ctx, zone, *res.JSON200.Id)
+}
+
+type instanceRescueProfileOutput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type instanceRescueProfilesOutput []instanceRescueProfileOutput
+
+func (o *instanceRescueProfilesOutput) toJSON()  { outputJSON(o) }
+func (o *instanceRescueProfilesOutput) toText()  { outputText(o) }
+func (o *instanceRescueProfilesOutput) toTable() { outputTable(o) }
+
+type instanceRescueProfilesCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"rescue-profiles"`
+}
+
+func (c *instanceRescueProfilesCmd) cmdAliases() []string { return nil }
+
+func (c *instanceRescueProfilesCmd) cmdShort() string {
+	return "List the Compute instance rescue profiles"
+}
+
+func (c *instanceRescueProfilesCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists the rescue profiles (alternate boot targets) a Compute
+instance can be started with using "exo compute instance start --rescue-profile".
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instanceRescueProfileOutput{}), ", "))
+}
+
+func (c *instanceRescueProfilesCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceRescueProfilesCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	out := instanceRescueProfilesOutput(instanceRescueProfiles)
+	return c.outputFunc(&out, nil)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(computeInstanceCmd, &instanceRescueProfilesCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
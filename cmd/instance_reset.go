@@ -75,8 +75,8 @@ func (c *instanceResetCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		}
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Reseting instance %q...", c.Instance), func() {
-		err = instance.Reset(ctx, template, c.DiskSize)
+	err = decorateAsyncOperation(fmt.Sprintf("Reseting instance %q...", c.Instance), func() error {
+		return instance.Reset(ctx, template, c.DiskSize)
 	})
 	if err != nil {
 		return err
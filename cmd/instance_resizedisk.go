@@ -44,14 +44,23 @@ func (c *instanceResizeDiskCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if currentSize := defaultInt64(instance.DiskSize, 0); c.Size <= currentSize {
+		return fmt.Errorf(
+			"instance %q disk can only be grown: current disk size is %d GiB, requested size is %d GiB",
+			c.Instance,
+			currentSize,
+			c.Size,
+		)
+	}
+
 	if !c.Force {
 		if !askQuestion(fmt.Sprintf("Are you sure you want to resize the disk of instance %q?", c.Instance)) {
 			return nil
 		}
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Resizing disk of instance %q...", c.Instance), func() {
-		err = instance.ResizeDisk(ctx, c.Size)
+	err = decorateAsyncOperation(fmt.Sprintf("Resizing disk of instance %q...", c.Instance), func() error {
+		return instance.ResizeDisk(ctx, c.Size)
 	})
 	if err != nil {
 		return err
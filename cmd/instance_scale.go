@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
@@ -14,10 +15,12 @@ type instanceScaleCmd struct {
 	_ bool `cli-cmd:"scale"`
 
 	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
-	Type     string `cli-arg:"#" cli-usage:"SIZE"`
+	Type     string `cli-arg:"#" cli-usage:"[FAMILY.]SIZE"`
 
-	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
-	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+	Force   bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Restart bool   `cli-usage:"stop the instance if running, and start it again once scaled"`
+	Stop    bool   `cli-usage:"stop the instance if running"`
+	Zone    string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceScaleCmd) cmdAliases() []string { return nil }
@@ -25,11 +28,22 @@ func (c *instanceScaleCmd) cmdAliases() []string { return nil }
 func (c *instanceScaleCmd) cmdShort() string { return "Scale a Compute instance" }
 
 func (c *instanceScaleCmd) cmdLong() string {
-	return fmt.Sprintf(`This commands scales a Compute instance to a different size.
+	return fmt.Sprintf(`This commands scales a Compute instance to a different type.
+
+The instance must be stopped to be scaled: using the "--stop" flag a running
+instance is stopped first, and using the "--restart" flag it is also started
+again once scaled. If the scaling fails, an instance stopped using the
+"--restart" flag is started again. The target instance type must be
+authorized for the organization (the minimum requirements of the instance
+template are not exposed by the API, and are only enforced by the API when
+scaling the instance).
+
+Supported Compute instance type families: %s
 
 Supported Compute instance type sizes: %s
 
 Supported output template annotations: %s`,
+		strings.Join(instanceTypeFamilies, ", "),
 		strings.Join(instanceTypeSizes, ", "),
 		strings.Join(outputterTemplateAnnotations(&instanceShowOutput{}), ", "))
 }
@@ -47,22 +61,76 @@ func (c *instanceScaleCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	instanceType, err := cs.FindInstanceType(ctx, c.Zone, c.Type)
+	if err != nil {
+		return fmt.Errorf("error retrieving instance type: %s", err)
+	}
+
+	if *instanceType.ID == *instance.InstanceTypeID {
+		return fmt.Errorf("instance %q is already of type %s", c.Instance, c.Type)
+	}
+
+	if err := validateInstanceScaleType(instanceType); err != nil {
+		return err
+	}
+
+	running := *instance.State == "running"
+	if running && !c.Stop && !c.Restart {
+		return fmt.Errorf(
+			"instance %q is running, stop it first (\"exo compute instance stop\") "+
+				"or use the --stop or --restart flag",
+			c.Instance)
+	}
+	if running && gNoWait {
+		return errNoWaitUnsupported("when the instance has to be stopped first")
+	}
+
 	if !c.Force {
 		if !askQuestion(fmt.Sprintf("Are you sure you want to scale instance %q?", c.Instance)) {
 			return nil
 		}
 	}
 
-	instanceType, err := cs.FindInstanceType(ctx, c.Zone, c.Type)
-	if err != nil {
-		return fmt.Errorf("error retrieving instance type: %s", err)
+	if running {
+		err = decorateAsyncOperation(fmt.Sprintf("Stopping instance %q...", c.Instance), func() error {
+			return instance.Stop(ctx)
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Scaling instance %q...", c.Instance), func() {
-		err = instance.Scale(ctx, instanceType)
+	start := func() error {
+		return decorateAsyncOperation(fmt.Sprintf("Starting instance %q...", c.Instance), func() error {
+			return instance.Start(ctx)
+		})
+	}
+
+	err = decorateAsyncOperation(fmt.Sprintf("Scaling instance %q...", c.Instance), func() error {
+		return instance.Scale(ctx, instanceType)
 	})
 	if err != nil {
-		return err
+		if !running {
+			return err
+		}
+
+		// The instance has been stopped to be scaled, it is started again if
+		// requested rather than being left stopped because of the failure.
+		if c.Restart {
+			if startErr := start(); startErr != nil {
+				return fmt.Errorf("%s\ninstance %q has been left stopped: unable to start it: %s",
+					err, c.Instance, startErr)
+			}
+			return err
+		}
+
+		return fmt.Errorf("%s\ninstance %q has been left stopped", err, c.Instance)
+	}
+
+	if running && c.Restart {
+		if err := start(); err != nil {
+			return err
+		}
 	}
 
 	if !gQuiet {
@@ -72,6 +140,17 @@ func (c *instanceScaleCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// validateInstanceScaleType checks that an instance can be scaled to the
+// instance type t.
+func validateInstanceScaleType(t *egoscale.InstanceType) error {
+	if t.Authorized != nil && !*t.Authorized {
+		return fmt.Errorf("instance type %s.%s is not authorized for this organization",
+			defaultString(t.Family, ""), defaultString(t.Size, ""))
+	}
+
+	return nil
+}
+
 func init() {
 	cobra.CheckErr(registerCLICommand(computeInstanceCmd, &instanceScaleCmd{
 		cliCommandSettings: defaultCLICmdSettings(),
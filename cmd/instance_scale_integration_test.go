@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationInstanceScaleRestartError(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	_, code := runCLI(t, "compute", "instance", "scale", "web1", "medium", "--restart", "-f", "-z", "ch-gva-2")
+	require.Equal(t, 1, code)
+
+	// The instance stopped to be scaled must be started again despite the
+	// scaling failure.
+	server.request(http.MethodPut, "/instance/"+testInstanceID+":stop", 0)
+	server.request(http.MethodPut, "/instance/"+testInstanceID+":scale", 0)
+	server.request(http.MethodPut, "/instance/"+testInstanceID+":start", 0)
+}
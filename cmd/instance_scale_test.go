@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateInstanceScaleType(t *testing.T) {
+	newType := func(size string, authorized bool) *egoscale.InstanceType {
+		family := "standard"
+		return &egoscale.InstanceType{
+			Family:     &family,
+			Size:       &size,
+			Authorized: &authorized,
+		}
+	}
+
+	require.NoError(t, validateInstanceScaleType(newType("small", true)))
+	require.NoError(t, validateInstanceScaleType(&egoscale.InstanceType{}))
+
+	err := validateInstanceScaleType(newType("huge", false))
+	require.EqualError(t, err, "instance type standard.huge is not authorized for this organization")
+}
@@ -34,7 +34,15 @@ Supported output template annotations: %s`,
 
 func (c *instanceSGAddCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if gNoWait && len(c.SecurityGroups) > 1 {
+		return errNoWaitUnsupported("with several Security Groups")
+	}
+
+	return nil
 }
 
 func (c *instanceSGAddCmd) cmdRun(cmd *cobra.Command, _ []string) error {
@@ -58,12 +66,13 @@ func (c *instanceSGAddCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		securityGroups[i] = securityGroup
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Updating instance %q Security Groups...", c.Instance), func() {
+	err = decorateAsyncOperation(fmt.Sprintf("Updating instance %q Security Groups...", c.Instance), func() error {
 		for _, securityGroup := range securityGroups {
-			if err = instance.AttachSecurityGroup(ctx, securityGroup); err != nil {
-				return
+			if err := instance.AttachSecurityGroup(ctx, securityGroup); err != nil {
+				return err
 			}
 		}
+		return nil
 	})
 	if err != nil {
 		return err
@@ -36,7 +36,15 @@ Supported output template annotations: %s`,
 
 func (c *instanceSGRemoveCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if gNoWait && len(c.SecurityGroups) > 1 {
+		return errNoWaitUnsupported("with several Security Groups")
+	}
+
+	return nil
 }
 
 func (c *instanceSGRemoveCmd) cmdRun(cmd *cobra.Command, _ []string) error {
@@ -60,12 +68,13 @@ func (c *instanceSGRemoveCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		securityGroups[i] = securityGroup
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Updating instance %q Security Groups...", c.Instance), func() {
+	err = decorateAsyncOperation(fmt.Sprintf("Updating instance %q Security Groups...", c.Instance), func() error {
 		for _, securityGroup := range securityGroups {
-			if err = instance.DetachSecurityGroup(ctx, securityGroup); err != nil {
-				return
+			if err := instance.DetachSecurityGroup(ctx, securityGroup); err != nil {
+				return err
 			}
 		}
+		return nil
 	})
 	if err != nil {
 		return err
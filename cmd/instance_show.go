@@ -37,7 +37,7 @@ type instanceShowCmd struct {
 
 	_ bool `cli-cmd:"show"`
 
-	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Instance string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"instance" cli-pick:"instance"`
 
 	ShowUserData bool   `cli-flag:"user-data" cli-short:"u" cli-usage:"show instance cloud-init user data configuration"`
 	Zone         string `cli-short:"z" cli-usage:"instance zone"`
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Labels storing the sleep schedule of a Compute instance.
+const (
+	sleepScheduleLabelStop  = "exo-sleep-schedule-stop"
+	sleepScheduleLabelStart = "exo-sleep-schedule-start"
+	sleepScheduleLabelDays  = "exo-sleep-schedule-days"
+	sleepScheduleLabelTZ    = "exo-sleep-schedule-tz"
+)
+
+const (
+	sleepScheduleActionStart = "start"
+	sleepScheduleActionStop  = "stop"
+)
+
+var sleepScheduleWeekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+var computeInstanceSleepScheduleCmd = &cobra.Command{
+	Use:   "sleep-schedule",
+	Short: "Manage Compute instances sleep schedule",
+	Long: `These commands allow you to manage the sleep schedule of Compute instances,
+i.e. the times at which instances are to be stopped and started (e.g. to stop
+development instances overnight).
+
+The sleep schedule of an instance is stored in its labels, and is enforced by
+the "exo compute sleep-schedule run" command intended to be run periodically
+(e.g. from a cron or CI job).
+`,
+}
+
+func init() {
+	computeInstanceCmd.AddCommand(computeInstanceSleepScheduleCmd)
+}
+
+// sleepSchedule represents the sleep schedule of a Compute instance: the
+// instance is to be stopped at the Stop time and started at the Start time
+// (both in the "HH:MM" format, either being optional) on the specified Days,
+// in the TZ time zone.
+type sleepSchedule struct {
+	Stop  string
+	Start string
+	Days  string
+	TZ    string
+
+	days     [7]bool
+	location *time.Location
+}
+
+// newSleepSchedule returns a validated sleepSchedule.
+func newSleepSchedule(stop, start, days, tz string) (*sleepSchedule, error) {
+	s := sleepSchedule{Stop: stop, Start: start, Days: days, TZ: tz}
+
+	if stop == "" && start == "" {
+		return nil, errors.New("at least one of the stop or start times must be specified")
+	}
+
+	for _, v := range []string{stop, start} {
+		if v == "" {
+			continue
+		}
+		if _, _, err := parseSleepScheduleTime(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if s.days, err = parseSleepScheduleDays(days); err != nil {
+		return nil, err
+	}
+
+	if tz == "" {
+		return nil, errors.New("a time zone must be specified")
+	}
+	if s.location, err = time.LoadLocation(tz); err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %s", tz, err)
+	}
+
+	return &s, nil
+}
+
+// sleepScheduleFromLabels returns the sleep schedule stored in the labels of
+// a Compute instance, or nil if the instance has no sleep schedule.
+func sleepScheduleFromLabels(labels *map[string]string) (*sleepSchedule, error) {
+	if !hasSleepScheduleLabels(labels) {
+		return nil, nil
+	}
+
+	l := *labels
+	return newSleepSchedule(
+		l[sleepScheduleLabelStop],
+		l[sleepScheduleLabelStart],
+		l[sleepScheduleLabelDays],
+		l[sleepScheduleLabelTZ],
+	)
+}
+
+// hasSleepScheduleLabels returns true if the specified labels contain a
+// sleep schedule, valid or not.
+func hasSleepScheduleLabels(labels *map[string]string) bool {
+	return labels != nil &&
+		((*labels)[sleepScheduleLabelStop] != "" || (*labels)[sleepScheduleLabelStart] != "")
+}
+
+// applyLabels sets the sleep schedule labels in the specified labels,
+// replacing any existing sleep schedule.
+func (s *sleepSchedule) applyLabels(labels map[string]string) {
+	removeSleepScheduleLabels(labels)
+
+	for k, v := range map[string]string{
+		sleepScheduleLabelStop:  s.Stop,
+		sleepScheduleLabelStart: s.Start,
+		sleepScheduleLabelDays:  s.Days,
+		sleepScheduleLabelTZ:    s.TZ,
+	} {
+		if v != "" {
+			labels[k] = v
+		}
+	}
+}
+
+// removeSleepScheduleLabels removes the sleep schedule labels from the
+// specified labels.
+func removeSleepScheduleLabels(labels map[string]string) {
+	for _, k := range []string{
+		sleepScheduleLabelStop,
+		sleepScheduleLabelStart,
+		sleepScheduleLabelDays,
+		sleepScheduleLabelTZ,
+	} {
+		delete(labels, k)
+	}
+}
+
+// sleepScheduleEvent represents an occurrence of a scheduled action.
+type sleepScheduleEvent struct {
+	action string
+	at     time.Time
+}
+
+// events returns the scheduled events occurring on the day of t (in the
+// schedule time zone).
+func (s *sleepSchedule) events(t time.Time) []sleepScheduleEvent {
+	t = t.In(s.location)
+	if !s.days[t.Weekday()] {
+		return nil
+	}
+
+	events := make([]sleepScheduleEvent, 0, 2)
+	for action, v := range map[string]string{sleepScheduleActionStop: s.Stop, sleepScheduleActionStart: s.Start} {
+		if v == "" {
+			continue
+		}
+		hour, minute, _ := parseSleepScheduleTime(v)
+		events = append(events, sleepScheduleEvent{
+			action: action,
+			at:     time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, s.location),
+		})
+	}
+
+	return events
+}
+
+// last returns the last scheduled event occurred at or before now, or nil if
+// none occurred during the past week.
+func (s *sleepSchedule) last(now time.Time) *sleepScheduleEvent {
+	var last *sleepScheduleEvent
+
+	for i := 0; i <= 7; i++ {
+		for _, e := range s.events(now.In(s.location).AddDate(0, 0, -i)) {
+			e := e
+			if !e.at.After(now) && (last == nil || e.at.After(last.at)) {
+				last = &e
+			}
+		}
+		if last != nil {
+			break
+		}
+	}
+
+	return last
+}
+
+// next returns the next scheduled event occurring after now, or nil if none
+// occurs during the next week.
+func (s *sleepSchedule) next(now time.Time) *sleepScheduleEvent {
+	var next *sleepScheduleEvent
+
+	for i := 0; i <= 7; i++ {
+		for _, e := range s.events(now.In(s.location).AddDate(0, 0, i)) {
+			e := e
+			if e.at.After(now) && (next == nil || e.at.Before(next.at)) {
+				next = &e
+			}
+		}
+		if next != nil {
+			break
+		}
+	}
+
+	return next
+}
+
+// parseSleepScheduleTime parses a time of day in the "HH:MM" format.
+func parseSleepScheduleTime(v string) (int, int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, expected format HH:MM", v)
+	}
+
+	return t.Hour(), t.Minute(), nil
+}
+
+// parseSleepScheduleDays parses a list of week days, specified as a comma-
+// separated list of days and days ranges (e.g. "mon-fri", "mon,wed,fri" or
+// "fri-mon"). An empty value means every day of the week.
+func parseSleepScheduleDays(v string) ([7]bool, error) {
+	var days [7]bool
+
+	if v == "" {
+		for i := range days {
+			days[i] = true
+		}
+		return days, nil
+	}
+
+	weekday := func(d string) (int, error) {
+		for i, wd := range sleepScheduleWeekdays {
+			if strings.ToLower(strings.TrimSpace(d)) == wd {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("invalid day %q, supported values are: %s", d, strings.Join(sleepScheduleWeekdays, ", "))
+	}
+
+	for _, part := range strings.Split(v, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+
+		from, err := weekday(bounds[0])
+		if err != nil {
+			return days, err
+		}
+
+		to := from
+		if len(bounds) == 2 {
+			if to, err = weekday(bounds[1]); err != nil {
+				return days, err
+			}
+		}
+
+		for i := from; ; i = (i + 1) % 7 {
+			days[i] = true
+			if i == to {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type instanceSleepScheduleListItemOutput struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Zone       string `json:"zone"`
+	State      string `json:"state"`
+	Stop       string `json:"stop"`
+	Start      string `json:"start"`
+	Days       string `json:"days"`
+	TZ         string `json:"tz" outputLabel:"TZ"`
+	NextAction string `json:"next_action"`
+}
+
+type instanceSleepScheduleListOutput []instanceSleepScheduleListItemOutput
+
+func (o *instanceSleepScheduleListOutput) toJSON()  { outputJSON(o) }
+func (o *instanceSleepScheduleListOutput) toText()  { outputText(o) }
+func (o *instanceSleepScheduleListOutput) toTable() { outputTable(o) }
+
+type instanceSleepScheduleListCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"list"`
+
+	Zone string `cli-short:"z" cli-usage:"zone to filter results to"`
+}
+
+func (c *instanceSleepScheduleListCmd) cmdAliases() []string { return gListAlias }
+
+func (c *instanceSleepScheduleListCmd) cmdShort() string {
+	return "List Compute instances having a sleep schedule"
+}
+
+func (c *instanceSleepScheduleListCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists the Compute instances having a sleep schedule, along
+with the next scheduled action.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instanceSleepScheduleListItemOutput{}), ", "))
+}
+
+func (c *instanceSleepScheduleListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceSleepScheduleListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	zones := allZones
+	if c.Zone != "" {
+		zones = []string{c.Zone}
+	}
+
+	instances, err := listSleepScheduledInstances(zones)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	out := make(instanceSleepScheduleListOutput, 0, len(instances))
+	for _, i := range instances {
+		labels := *i.instance.Labels
+
+		days := labels[sleepScheduleLabelDays]
+		if days == "" {
+			days = "all"
+		}
+
+		item := instanceSleepScheduleListItemOutput{
+			ID:    *i.instance.ID,
+			Name:  *i.instance.Name,
+			Zone:  i.zone,
+			State: *i.instance.State,
+			Stop:  labels[sleepScheduleLabelStop],
+			Start: labels[sleepScheduleLabelStart],
+			Days:  days,
+			TZ:    labels[sleepScheduleLabelTZ],
+		}
+
+		switch {
+		case i.err != nil:
+			item.NextAction = fmt.Sprintf("invalid schedule: %s", i.err)
+		case i.schedule.next(now) != nil:
+			next := i.schedule.next(now)
+			item.NextAction = fmt.Sprintf("%s at %s", next.action, next.at.Format("Mon 2006-01-02 15:04 MST"))
+		default:
+			item.NextAction = "n/a"
+		}
+
+		out = append(out, item)
+	}
+
+	return c.outputFunc(&out, nil)
+}
+
+// sleepScheduledInstance represents a Compute instance having a sleep
+// schedule. If the sleep schedule stored in the instance labels is invalid,
+// err is set instead of schedule.
+type sleepScheduledInstance struct {
+	zone     string
+	instance *egoscale.Instance
+	schedule *sleepSchedule
+	err      error
+}
+
+// listSleepScheduledInstances returns the Compute instances having a sleep
+// schedule in the specified zones, sorted by zone and name.
+func listSleepScheduledInstances(zones []string) ([]sleepScheduledInstance, error) {
+	var (
+		instances   = make([]sleepScheduledInstance, 0)
+		instancesMu sync.Mutex
+	)
+
+	err := forEachZone(zones, func(zone string) error {
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+		list, err := cs.ListInstances(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("unable to list Compute instances in zone %s: %v", zone, err)
+		}
+
+		for _, instance := range list {
+			if !hasSleepScheduleLabels(instance.Labels) {
+				continue
+			}
+
+			schedule, err := sleepScheduleFromLabels(instance.Labels)
+
+			instancesMu.Lock()
+			instances = append(instances, sleepScheduledInstance{
+				zone:     zone,
+				instance: instance,
+				schedule: schedule,
+				err:      err,
+			})
+			instancesMu.Unlock()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].zone != instances[j].zone {
+			return instances[i].zone < instances[j].zone
+		}
+		return *instances[i].instance.Name < *instances[j].instance.Name
+	})
+
+	return instances, nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(computeInstanceSleepScheduleCmd, &instanceSleepScheduleListCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type instanceSleepScheduleSetCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"set"`
+
+	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Days  string `cli-usage:"days of the week the schedule applies to (e.g. \"mon-fri\", \"mon,wed,fri\"; default: every day)"`
+	Start string `cli-usage:"time of day to start the instance at (format: HH:MM)"`
+	Stop  string `cli-usage:"time of day to stop the instance at (format: HH:MM)"`
+	TZ    string `cli-flag:"tz" cli-usage:"time zone of the start/stop times (e.g. \"Europe/Zurich\")"`
+	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+}
+
+func (c *instanceSleepScheduleSetCmd) cmdAliases() []string { return nil }
+
+func (c *instanceSleepScheduleSetCmd) cmdShort() string {
+	return "Set a Compute instance sleep schedule"
+}
+
+func (c *instanceSleepScheduleSetCmd) cmdLong() string {
+	return fmt.Sprintf(`This command sets the sleep schedule of a Compute instance, replacing
+any existing one. The schedule is stored in the instance labels (%s).
+
+Example:
+
+    exo compute instance sleep-schedule set my-dev-instance \
+        --stop 19:00 \
+        --start 08:00 \
+        --days mon-fri \
+        --tz Europe/Zurich
+
+Note: the schedule is not enforced by the Exoscale platform, but by the
+"exo compute sleep-schedule run" command which must be run periodically.
+
+Supported output template annotations: %s`,
+		strings.Join([]string{
+			sleepScheduleLabelStop,
+			sleepScheduleLabelStart,
+			sleepScheduleLabelDays,
+			sleepScheduleLabelTZ,
+		}, ", "),
+		strings.Join(outputterTemplateAnnotations(&instanceShowOutput{}), ", "))
+}
+
+func (c *instanceSleepScheduleSetCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceSleepScheduleSetCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	schedule, err := newSleepSchedule(c.Stop, c.Start, c.Days, c.TZ)
+	if err != nil {
+		return err
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		return err
+	}
+
+	labels := make(map[string]string)
+	if instance.Labels != nil {
+		for k, v := range *instance.Labels {
+			labels[k] = v
+		}
+	}
+	schedule.applyLabels(labels)
+	instance.Labels = &labels
+
+	err = decorateAsyncOperation(fmt.Sprintf("Updating instance %q sleep schedule...", c.Instance), func() error {
+		return cs.UpdateInstance(ctx, c.Zone, instance)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !gQuiet {
+		return output(showInstance(c.Zone, *instance.ID))
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(computeInstanceSleepScheduleCmd, &instanceSleepScheduleSetCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+		TZ:                 "UTC",
+	}))
+}
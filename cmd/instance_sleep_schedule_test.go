@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSleepScheduleDays(t *testing.T) {
+	days, err := parseSleepScheduleDays("mon-fri")
+	require.NoError(t, err)
+	require.Equal(t, [7]bool{false, true, true, true, true, true, false}, days)
+
+	days, err = parseSleepScheduleDays("fri-mon,wed")
+	require.NoError(t, err)
+	require.Equal(t, [7]bool{true, true, false, true, false, true, true}, days)
+
+	days, err = parseSleepScheduleDays("")
+	require.NoError(t, err)
+	require.Equal(t, [7]bool{true, true, true, true, true, true, true}, days)
+
+	for _, v := range []string{"monday", "mon-", "mon,,fri"} {
+		_, err := parseSleepScheduleDays(v)
+		require.Error(t, err, v)
+	}
+}
+
+func Test_newSleepSchedule(t *testing.T) {
+	for _, tc := range []struct{ stop, start, days, tz string }{
+		{"", "", "", "UTC"},
+		{"25:00", "", "", "UTC"},
+		{"19:00", "8h", "", "UTC"},
+		{"19:00", "08:00", "weekdays", "UTC"},
+		{"19:00", "08:00", "", ""},
+		{"19:00", "08:00", "", "Mars/Olympus_Mons"},
+	} {
+		_, err := newSleepSchedule(tc.stop, tc.start, tc.days, tc.tz)
+		require.Error(t, err, tc)
+	}
+
+	labels := map[string]string{"env": "dev", sleepScheduleLabelDays: "sat"}
+	schedule, err := newSleepSchedule("19:00", "", "", "Europe/Zurich")
+	require.NoError(t, err)
+	schedule.applyLabels(labels)
+	require.Equal(t, map[string]string{
+		"env":                  "dev",
+		sleepScheduleLabelStop: "19:00",
+		sleepScheduleLabelTZ:   "Europe/Zurich",
+	}, labels)
+
+	fromLabels, err := sleepScheduleFromLabels(&labels)
+	require.NoError(t, err)
+	require.Equal(t, schedule, fromLabels)
+
+	removeSleepScheduleLabels(labels)
+	require.Equal(t, map[string]string{"env": "dev"}, labels)
+	fromLabels, err = sleepScheduleFromLabels(&labels)
+	require.NoError(t, err)
+	require.Nil(t, fromLabels)
+}
+
+func Test_sleepScheduleDueAction(t *testing.T) {
+	schedule, err := newSleepSchedule("19:00", "08:00", "mon-fri", "Europe/Zurich")
+	require.NoError(t, err)
+
+	zurich, _ := time.LoadLocation("Europe/Zurich")
+	at := func(v string) time.Time {
+		ts, err := time.ParseInLocation("2006-01-02 15:04", v, zurich)
+		require.NoError(t, err)
+		return ts
+	}
+
+	for _, tc := range []struct {
+		now, state, action, result string
+	}{
+		// Wednesday
+		{"2021-06-02 12:00", "running", sleepScheduleActionStart, sleepScheduleResultUpToDate},
+		{"2021-06-02 12:00", "stopped", sleepScheduleActionStart, ""},
+		{"2021-06-02 19:00", "running", sleepScheduleActionStop, ""},
+		{"2021-06-02 07:59", "running", sleepScheduleActionStop, ""},
+		{"2021-06-02 20:00", "starting", sleepScheduleActionStop, sleepScheduleResultSkipped},
+		// Sunday: last action is Friday's stop
+		{"2021-06-06 12:00", "stopped", sleepScheduleActionStop, sleepScheduleResultUpToDate},
+		{"2021-06-06 12:00", "running", sleepScheduleActionStop, ""},
+	} {
+		action, result, _ := sleepScheduleDueAction(schedule, tc.state, at(tc.now))
+		require.Equal(t, tc.action, action, tc)
+		require.Equal(t, tc.result, result, tc)
+	}
+
+	next := schedule.next(at("2021-06-04 20:00"))
+	require.Equal(t, sleepScheduleActionStart, next.action)
+	require.Equal(t, at("2021-06-07 08:00"), next.at)
+
+	schedule, err = newSleepSchedule("", "08:00", "sat", "UTC")
+	require.NoError(t, err)
+	action, result, _ := sleepScheduleDueAction(schedule, "stopped", time.Date(2021, 6, 4, 12, 0, 0, 0, time.UTC))
+	require.Equal(t, sleepScheduleActionStart, action)
+	require.Equal(t, "", result)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type instanceSleepScheduleUnsetCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"unset"`
+
+	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Zone string `cli-short:"z" cli-usage:"instance zone"`
+}
+
+func (c *instanceSleepScheduleUnsetCmd) cmdAliases() []string { return nil }
+
+func (c *instanceSleepScheduleUnsetCmd) cmdShort() string {
+	return "Remove a Compute instance sleep schedule"
+}
+
+func (c *instanceSleepScheduleUnsetCmd) cmdLong() string {
+	return `This command removes the sleep schedule of a Compute instance. The current
+state of the instance is left unchanged.`
+}
+
+func (c *instanceSleepScheduleUnsetCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceSleepScheduleUnsetCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		return err
+	}
+
+	if !hasSleepScheduleLabels(instance.Labels) {
+		return fmt.Errorf("instance %q has no sleep schedule", c.Instance)
+	}
+
+	labels := make(map[string]string)
+	for k, v := range *instance.Labels {
+		labels[k] = v
+	}
+	removeSleepScheduleLabels(labels)
+	instance.Labels = &labels
+
+	err = decorateAsyncOperation(fmt.Sprintf("Removing instance %q sleep schedule...", c.Instance), func() error {
+		return cs.UpdateInstance(ctx, c.Zone, instance)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !gQuiet {
+		return output(showInstance(c.Zone, *instance.ID))
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(computeInstanceSleepScheduleCmd, &instanceSleepScheduleUnsetCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -2,11 +2,16 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/kballard/go-shellquote"
 	"github.com/spf13/cobra"
@@ -18,17 +23,72 @@ type instanceSSHCmd struct {
 	sshInfo struct {
 		ipAddress string
 		keyFile   string
+		tunnels   []sshTunnel
 	} `cli-cmd:"-"`
 	_ bool `cli-cmd:"ssh"`
 
 	Instance string `cli-arg:"#" cli-usage:"INSTANCE-NAME|ID"`
 
-	IPv6        bool   `cli-flag:"ipv6" cli-short:"6" cli-help:"connect to the instance via its IPv6 address"`
-	Login       string `cli-short:"l" cli-help:"SSH username to use for logging in (default: instance template default username)"`
-	PrintCmd    bool   `cli-flag:"print-command" cli-usage:"print the SSH command that would be executed instead of executing it"`
-	PrintConfig bool   `cli-flag:"print-ssh-config" cli-usage:"print the corresponding SSH information in a format compatible with ssh_config(5)"`
-	SSHOpts     string `cli-flag:"ssh-options" cli-short:"o" cli-usage:"additional options to pass to the ssh(1) command"`
-	Zone        string `cli-short:"z" cli-usage:"instance zone"`
+	Background  bool     `cli-usage:"only set up the tunnels specified using --tunnel, without opening a shell"`
+	IPv6        bool     `cli-flag:"ipv6" cli-short:"6" cli-help:"connect to the instance via its IPv6 address"`
+	Login       string   `cli-short:"l" cli-help:"SSH username to use for logging in (default: instance template default username)"`
+	PrintCmd    bool     `cli-flag:"print-command" cli-usage:"print the SSH command that would be executed instead of executing it"`
+	PrintConfig bool     `cli-flag:"print-ssh-config" cli-usage:"print the corresponding SSH information in a format compatible with ssh_config(5)"`
+	SSHOpts     string   `cli-flag:"ssh-options" cli-short:"o" cli-usage:"additional options to pass to the ssh(1) command"`
+	Tunnels     []string `cli-flag:"tunnel" cli-short:"L" cli-usage:"forward a local port to a remote host via the instance (format: LOCALPORT:REMOTEHOST:REMOTEPORT). Can be repeated"`
+	Zone        string   `cli-short:"z" cli-usage:"instance zone"`
+}
+
+// sshTunnel represents a local port forwarded to a remote host port via SSH,
+// the remote host being possibly resolved to a different address.
+type sshTunnel struct {
+	localPort  int
+	host       string
+	address    string
+	remotePort int
+}
+
+func (t sshTunnel) String() string {
+	return fmt.Sprintf("%d:%s:%d", t.localPort, t.address, t.remotePort)
+}
+
+// parseSSHTunnel parses a "LOCALPORT:REMOTEHOST:REMOTEPORT" tunnel
+// specification, REMOTEHOST being possibly an IPv6 address in brackets.
+func parseSSHTunnel(v string) (sshTunnel, error) {
+	var tunnel sshTunnel
+
+	i, j := strings.Index(v, ":"), strings.LastIndex(v, ":")
+	if i < 0 || i == j {
+		return tunnel, fmt.Errorf("invalid tunnel %q, expected format LOCALPORT:REMOTEHOST:REMOTEPORT", v)
+	}
+
+	parsePort := func(p string) (int, error) {
+		port, err := strconv.Atoi(p)
+		if err != nil || port < 1 || port > 65535 {
+			return 0, fmt.Errorf("invalid tunnel %q: invalid port %q", v, p)
+		}
+		return port, nil
+	}
+
+	var err error
+	if tunnel.localPort, err = parsePort(v[:i]); err != nil {
+		return tunnel, err
+	}
+	if tunnel.remotePort, err = parsePort(v[j+1:]); err != nil {
+		return tunnel, err
+	}
+
+	tunnel.host = v[i+1 : j]
+	tunnel.address = tunnel.host
+	if tunnel.host == "" {
+		return tunnel, fmt.Errorf("invalid tunnel %q: no remote host specified", v)
+	}
+	if strings.Contains(tunnel.host, ":") &&
+		!(strings.HasPrefix(tunnel.host, "[") && strings.HasSuffix(tunnel.host, "]")) {
+		return tunnel, fmt.Errorf("invalid tunnel %q: IPv6 addresses must be enclosed in brackets", v)
+	}
+
+	return tunnel, nil
 }
 
 func (c *instanceSSHCmd) buildSSHCommand() []string {
@@ -46,6 +106,16 @@ func (c *instanceSSHCmd) buildSSHCommand() []string {
 		cmd = append(cmd, "-l", c.Login)
 	}
 
+	if len(c.sshInfo.tunnels) > 0 {
+		cmd = append(cmd, "-o", "ExitOnForwardFailure=yes")
+		for _, t := range c.sshInfo.tunnels {
+			cmd = append(cmd, "-L", t.String())
+		}
+		if c.Background {
+			cmd = append(cmd, "-N")
+		}
+	}
+
 	if c.SSHOpts != "" {
 		opts, err := shellquote.Split(c.SSHOpts)
 		if err == nil {
@@ -68,6 +138,18 @@ func (c *instanceSSHCmd) cmdLong() string {
 To pass custom SSH options:
 
     exo compute instance ssh -o "-p 2222 -A" my-instance
+
+To reach services not exposed publicly, local ports can be forwarded to
+remote hosts via the instance using the "--tunnel" flag (format
+LOCALPORT:REMOTEHOST:REMOTEPORT, can be repeated). REMOTEHOST can be the name
+of a Compute instance attached to one of the instance's Private Networks
+(resolved using the Private Network DHCP leases), or any host name or IP
+address reachable from the instance:
+
+    exo compute instance ssh my-bastion --tunnel 5432:my-database:5432
+
+Using the "--background" flag, only the tunnels are set up without opening a
+shell on the instance, until interrupted using Ctrl-C.
 `
 }
 
@@ -104,6 +186,23 @@ func (c *instanceSSHCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		c.sshInfo.ipAddress = instance.IPv6Address.String()
 	}
 
+	if c.Background && len(c.Tunnels) == 0 {
+		return fmt.Errorf("--background requires at least one --tunnel")
+	}
+
+	for _, v := range c.Tunnels {
+		tunnel, err := parseSSHTunnel(v)
+		if err != nil {
+			return err
+		}
+
+		if tunnel.address, err = c.resolveTunnelHost(ctx, instance, tunnel.host); err != nil {
+			return err
+		}
+
+		c.sshInfo.tunnels = append(c.sshInfo.tunnels, tunnel)
+	}
+
 	sshCmd := c.buildSSHCommand()
 
 	switch {
@@ -127,15 +226,67 @@ func (c *instanceSSHCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return nil
 
 	default:
+		for _, t := range c.sshInfo.tunnels {
+			remote := t.host
+			if t.address != t.host {
+				remote = fmt.Sprintf("%s (%s)", t.host, t.address)
+			}
+			fmt.Fprintf(os.Stderr, "Forwarding localhost:%d to %s:%d via %s\n",
+				t.localPort, remote, t.remotePort, c.Instance)
+		}
+		if c.Background {
+			fmt.Fprintln(os.Stderr, "Press Ctrl-C to close the tunnels.")
+		}
+
 		cmd := exec.Command("ssh", sshCmd[1:]...)
 		cmd.Stdin = os.Stdin
 		cmd.Stderr = os.Stderr
 		cmd.Stdout = os.Stdout
 
-		return cmd.Run()
+		err := cmd.Run()
+		if c.Background && gContext.Err() != nil {
+			// Interrupted by the user.
+			return nil
+		}
+
+		return err
 	}
 }
 
+// resolveTunnelHost resolves a tunnel remote host matching the name of a
+// Compute instance attached to one of the instance Private Networks to the
+// instance Private Network IP address, found in the Private Network leases.
+// Other hosts are returned as is, to be resolved by the instance.
+func (c *instanceSSHCmd) resolveTunnelHost(ctx context.Context, instance *egoscale.Instance, host string) (string, error) {
+	if host == "localhost" || net.ParseIP(strings.Trim(host, "[]")) != nil ||
+		instance.PrivateNetworkIDs == nil {
+		return host, nil
+	}
+
+	target, err := cs.FindInstance(ctx, c.Zone, host)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return host, nil
+		}
+		return "", err
+	}
+
+	for _, id := range *instance.PrivateNetworkIDs {
+		privateNetwork, err := cs.GetPrivateNetwork(ctx, c.Zone, id)
+		if err != nil {
+			return "", fmt.Errorf("unable to retrieve Private Network %s: %w", id, err)
+		}
+
+		for _, lease := range privateNetwork.Leases {
+			if lease.InstanceID != nil && *lease.InstanceID == *target.ID && lease.IPAddress != nil {
+				return lease.IPAddress.String(), nil
+			}
+		}
+	}
+
+	return host, nil
+}
+
 func init() {
 	cobra.CheckErr(registerCLICommand(computeInstanceCmd, &instanceSSHCmd{
 		cliCommandSettings: defaultCLICmdSettings(),
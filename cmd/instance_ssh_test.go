@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSSHTunnel(t *testing.T) {
+	tunnel, err := parseSSHTunnel("5432:my-database:5433")
+	require.NoError(t, err)
+	require.Equal(t, sshTunnel{localPort: 5432, host: "my-database", address: "my-database", remotePort: 5433}, tunnel)
+	require.Equal(t, "5432:my-database:5433", tunnel.String())
+
+	tunnel, err = parseSSHTunnel("8080:[fd00::1]:80")
+	require.NoError(t, err)
+	require.Equal(t, "[fd00::1]", tunnel.host)
+
+	for _, v := range []string{
+		"5432",
+		"5432:my-database",
+		"5432::5432",
+		"x:my-database:5432",
+		"5432:my-database:70000",
+		"0:my-database:5432",
+		"8080:fd00::1:80",
+	} {
+		_, err := parseSSHTunnel(v)
+		require.Error(t, err, v)
+	}
+}
+
+func Test_instanceSSHCmd_buildSSHCommand_tunnels(t *testing.T) {
+	c := instanceSSHCmd{Background: true, Login: "ubuntu"}
+	c.sshInfo.ipAddress = "198.51.100.1"
+	c.sshInfo.tunnels = []sshTunnel{
+		{localPort: 5432, host: "my-database", address: "10.0.0.5", remotePort: 5432},
+		{localPort: 8080, host: "localhost", address: "localhost", remotePort: 80},
+	}
+
+	require.Equal(t, []string{
+		"ssh",
+		"-l", "ubuntu",
+		"-o", "ExitOnForwardFailure=yes",
+		"-L", "5432:10.0.0.5:5432",
+		"-L", "8080:localhost:80",
+		"-N",
+		"198.51.100.1",
+	}, c.buildSSHCommand())
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
@@ -14,19 +15,38 @@ type instanceStartCmd struct {
 
 	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
 
-	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
-	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+	Force         bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Reboot        bool   `cli-usage:"stop the instance first if it is running"`
+	RescueProfile string `cli-usage:"rescue profile to boot the instance with (see \"exo compute instance rescue-profiles\")"`
+	Zone          string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceStartCmd) cmdAliases() []string { return nil }
 
 func (c *instanceStartCmd) cmdShort() string { return "Start a Compute instance" }
 
-func (c *instanceStartCmd) cmdLong() string { return "" }
+func (c *instanceStartCmd) cmdLong() string {
+	return fmt.Sprintf(`This command starts a Compute instance.
+
+Using the "--rescue-profile" flag, the instance is booted into an alternate
+boot target (supported profiles: %s) instead of its disk. As the rescue
+profile only applies when the instance boots, running instances must be
+stopped beforehand, or the "--reboot" flag specified to stop them first.`,
+		strings.Join(instanceRescueProfileNames(), ", "))
+}
 
 func (c *instanceStartCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.RescueProfile != "" && !isInstanceRescueProfile(c.RescueProfile) {
+		return fmt.Errorf("invalid rescue profile %q, supported values are: %s",
+			c.RescueProfile, strings.Join(instanceRescueProfileNames(), ", "))
+	}
+
+	return nil
 }
 
 func (c *instanceStartCmd) cmdRun(_ *cobra.Command, _ []string) error {
@@ -43,8 +63,39 @@ func (c *instanceStartCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		}
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Starting instance %q...", c.Instance), func() {
-		err = instance.Start(ctx)
+	if *instance.State == "running" {
+		if c.RescueProfile == "" {
+			return fmt.Errorf("instance %q is already running", c.Instance)
+		}
+		if !c.Reboot {
+			return fmt.Errorf(
+				"instance %q is running, stop it first (\"exo compute instance stop\") "+
+					"or use the --reboot flag to start it with the rescue profile %q",
+				c.Instance, c.RescueProfile)
+		}
+		if gNoWait {
+			return errNoWaitUnsupported("when the instance has to be stopped first")
+		}
+
+		err = decorateAsyncOperation(fmt.Sprintf("Stopping instance %q...", c.Instance), func() error {
+			return instance.Stop(ctx)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.RescueProfile != "" {
+		err = decorateAsyncOperation(
+			fmt.Sprintf("Starting instance %q with rescue profile %q...", c.Instance, c.RescueProfile),
+			func() error {
+				return startInstanceWithRescueProfile(ctx, c.Zone, *instance.ID, c.RescueProfile)
+			})
+		return err
+	}
+
+	err = decorateAsyncOperation(fmt.Sprintf("Starting instance %q...", c.Instance), func() error {
+		return instance.Start(ctx)
 	})
 	if err != nil {
 		return err
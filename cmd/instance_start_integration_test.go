@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testInstanceID = "4e5f6a7b-8c9d-4e0f-a1b2-c3d4e5f6a7b8"
+
+func TestIntegrationInstanceStartRescueReboot(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	_, code := runCLI(t, "compute", "instance", "start", "web1",
+		"--rescue-profile", "netboot", "--reboot", "-f", "-z", "ch-gva-2")
+	require.Equal(t, 0, code)
+
+	server.request(http.MethodPut, "/instance/"+testInstanceID+":stop", 0)
+	requireJSONField(t, server.request(http.MethodPut, "/instance/"+testInstanceID+":start", 0),
+		"rescue-profile", "netboot")
+}
+
+func TestIntegrationInstanceStartRescueRunning(t *testing.T) {
+	setupIntegrationTest(t)
+
+	_, code := runCLI(t, "compute", "instance", "start", "web1", "--rescue-profile", "netboot", "-f", "-z", "ch-gva-2")
+	require.Equal(t, 1, code)
+}
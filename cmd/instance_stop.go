@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
@@ -14,15 +15,32 @@ type instanceStopCmd struct {
 
 	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
 
-	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
-	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+	Force            bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Hard             bool   `cli-usage:"forcibly power off the instance without shutting down its operating system"`
+	HardAfterTimeout bool   `cli-usage:"forcibly power off the instance if the graceful stop times out (with --stop-timeout)"`
+	StopTimeout      int64  `cli-usage:"maximum time to wait for the instance to stop gracefully in seconds (0: no timeout)"`
+	Zone             string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceStopCmd) cmdAliases() []string { return nil }
 
 func (c *instanceStopCmd) cmdShort() string { return "Stop a Compute instance" }
 
-func (c *instanceStopCmd) cmdLong() string { return "" }
+func (c *instanceStopCmd) cmdLong() string {
+	return fmt.Sprintf(`This command stops a Compute instance.
+
+By default the instance operating system is asked to shut down gracefully. The
+"--hard" flag forcibly powers the instance off instead, like unplugging a
+physical server: unsaved data may be lost and file systems corrupted.
+
+With "--stop-timeout", the command gives up waiting for the graceful stop
+after the specified number of seconds, and either offers to power the
+instance off (interactive mode) or does so without asking with
+"--hard-after-timeout".
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instancePowerActionOutput{}), ", "))
+}
 
 func (c *instanceStopCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
@@ -30,6 +48,16 @@ func (c *instanceStopCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 }
 
 func (c *instanceStopCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	if c.Hard && (c.StopTimeout > 0 || c.HardAfterTimeout) {
+		return fmt.Errorf("--hard is mutually exclusive with --stop-timeout and --hard-after-timeout")
+	}
+	if c.HardAfterTimeout && c.StopTimeout <= 0 {
+		return fmt.Errorf("--hard-after-timeout requires --stop-timeout")
+	}
+	if gNoWait && c.StopTimeout > 0 {
+		return errNoWaitUnsupported("with --stop-timeout")
+	}
+
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
 	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
@@ -38,18 +66,62 @@ func (c *instanceStopCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	}
 
 	if !c.Force {
-		if !askQuestion(fmt.Sprintf("Are you sure you want to stop instance %q?", c.Instance)) {
+		question := fmt.Sprintf("Are you sure you want to stop instance %q?", c.Instance)
+		if c.Hard {
+			question = fmt.Sprintf("Are you sure you want to FORCIBLY POWER OFF instance %q "+
+				"(unsaved data may be lost and file systems corrupted)?", c.Instance)
+		}
+		if !askQuestion(question) {
 			return nil
 		}
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Stopping instance %q...", c.Instance), func() {
-		err = instance.Stop(ctx)
-	})
+	action := instancePowerActionStop
+	if c.Hard {
+		action = instancePowerActionHardStop
+		err = decorateAsyncOperation(fmt.Sprintf("Powering off instance %q...", c.Instance), func() error {
+			return hardStopInstance(ctx, instance)
+		})
+	} else {
+		var timedOut bool
+		err = decorateAsyncOperation(fmt.Sprintf("Stopping instance %q...", c.Instance), func() error {
+			timedOut, err = gracefulStopInstance(ctx, instance, c.StopTimeout)
+			return err
+		})
+		if err == nil && timedOut {
+			hard := c.HardAfterTimeout
+			if !hard {
+				if !isInteractive() {
+					return fmt.Errorf("%w after %ds: use --hard-after-timeout to power it off automatically",
+						errInstanceStopTimeout, c.StopTimeout)
+				}
+				if hard, err = askHardStopAfterTimeout(c.Instance); err != nil {
+					return err
+				}
+				if !hard {
+					return fmt.Errorf("%w after %ds", errInstanceStopTimeout, c.StopTimeout)
+				}
+			}
+
+			action = instancePowerActionHardStopAfterTimeout
+			err = decorateAsyncOperation(fmt.Sprintf("Powering off instance %q...", c.Instance), func() error {
+				return hardStopInstance(ctx, instance)
+			})
+		}
+	}
 	if err != nil {
 		return err
 	}
 
+	if !gQuiet {
+		return c.outputFunc(&instancePowerActionOutput{
+			ID:     *instance.ID,
+			Name:   *instance.Name,
+			Zone:   c.Zone,
+			Action: action,
+		}, nil)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationInstanceStop(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	out, code := runCLI(t, "compute", "instance", "stop", "web1", "-f", "-z", "ch-gva-2", "-O", "json")
+	require.Equal(t, 0, code)
+
+	server.request(http.MethodPut, "/instance/"+testInstanceID+":stop", 0)
+	requireJSONField(t, []byte(outputJSONLine(t, out)), "action", instancePowerActionStop)
+}
+
+func TestIntegrationInstanceStopTimeout(t *testing.T) {
+	setupIntegrationTest(t)
+
+	// Without --hard-after-timeout the instance must not be powered off in
+	// non-interactive mode.
+	_, code := runCLI(t, "compute", "instance", "stop", "web1", "--stop-timeout", "1", "-f", "-z", "ch-gva-2")
+	require.Equal(t, 1, code)
+}
@@ -49,8 +49,8 @@ func (c *computeInstanceTemplateDeleteCmd) cmdRun(_ *cobra.Command, _ []string)
 		}
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Deleting template %s...", c.TemplateID), func() {
-		err = cs.DeleteTemplate(ctx, c.Zone, *template.ID)
+	err = decorateAsyncOperation(fmt.Sprintf("Deleting template %s...", c.TemplateID), func() error {
+		return cs.DeleteTemplate(ctx, c.Zone, *template.ID)
 	})
 	if err != nil {
 		return err
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"sync"
 
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
@@ -21,14 +25,31 @@ func (o *computeInstanceTemplateListOutput) toJSON()  { outputJSON(o) }
 func (o *computeInstanceTemplateListOutput) toText()  { outputText(o) }
 func (o *computeInstanceTemplateListOutput) toTable() { outputTable(o) }
 
+type computeInstanceTemplateListAllZonesItemOutput struct {
+	Name         string   `json:"name"`
+	Family       string   `json:"family"`
+	Checksum     string   `json:"checksum"`
+	Zones        []string `json:"zones"`
+	MissingZones []string `json:"missing_zones"`
+}
+
+type computeInstanceTemplateListAllZonesOutput []computeInstanceTemplateListAllZonesItemOutput
+
+func (o *computeInstanceTemplateListAllZonesOutput) toJSON()  { outputJSON(o) }
+func (o *computeInstanceTemplateListAllZonesOutput) toText()  { outputText(o) }
+func (o *computeInstanceTemplateListAllZonesOutput) toTable() { outputTable(o) }
+
 type computeInstanceTemplateListCmd struct {
 	cliCommandSettings `cli-cmd:"-"`
 
 	_ bool `cli-cmd:"list"`
 
-	Family     string `cli-short:"f" cli-usage:"template family to filter results to"`
-	Visibility string `cli-short:"v" cli-usage:"template visibility (public|private)"`
-	Zone       string `cli-short:"z" cli-usage:"zone to filter results to (default: current account's default zone)"`
+	CreatedAfter    string `cli-usage:"only list templates created after the specified time (RFC3339 timestamp, date or relative duration e.g. \"90d\")"`
+	CreatedBefore   string `cli-usage:"only list templates created before the specified time (RFC3339 timestamp, date or relative duration e.g. \"90d\")"`
+	Family          string `cli-short:"f" cli-usage:"template family to filter results to"`
+	RequireAllZones bool   `cli-usage:"with \"--zone all\", exit with an error if a template is missing from some zones"`
+	Visibility      string `cli-short:"v" cli-usage:"template visibility (public|private)"`
+	Zone            string `cli-short:"z" cli-usage:"zone to filter results to, or \"all\" for all zones (default: current account's default zone)"`
 }
 
 func (c *computeInstanceTemplateListCmd) cmdAliases() []string { return nil }
@@ -38,8 +59,20 @@ func (c *computeInstanceTemplateListCmd) cmdShort() string { return "List Comput
 func (c *computeInstanceTemplateListCmd) cmdLong() string {
 	return fmt.Sprintf(`This command lists available Compute instance templates.
 
-	Supported output template annotations: %s`,
-		strings.Join(outputterTemplateAnnotations(&computeInstanceTemplateListItemOutput{}), ", "))
+Using "--zone all", templates are listed in all zones and grouped by name and
+checksum, showing the zones each template is available (or missing) in. The
+"--require-all-zones" flag makes the command exit with an error if some
+templates are missing from at least one zone, e.g. to verify that a custom
+template has been registered everywhere.
+
+%s
+
+Supported output template annotations: %s
+
+Supported output template annotations ("--zone all"): %s`,
+		createdAtFilterHelp,
+		strings.Join(outputterTemplateAnnotations(&computeInstanceTemplateListItemOutput{}), ", "),
+		strings.Join(outputterTemplateAnnotations(&computeInstanceTemplateListAllZonesItemOutput{}), ", "))
 }
 
 func (c *computeInstanceTemplateListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
@@ -48,12 +81,25 @@ func (c *computeInstanceTemplateListCmd) cmdPreRun(cmd *cobra.Command, args []st
 }
 
 func (c *computeInstanceTemplateListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	createdAt, err := newCreatedAtFilter(c.CreatedBefore, c.CreatedAfter)
+	if err != nil {
+		return err
+	}
+
+	if c.Zone == "all" {
+		return c.listAllZones(createdAt)
+	}
+
+	if c.RequireAllZones {
+		return errors.New(`"--require-all-zones" can only be used with "--zone all"`)
+	}
+
 	ctx := exoapi.WithEndpoint(
 		gContext,
-		exoapi.NewReqEndpoint(gCurrentAccount.Environment, gCurrentAccount.DefaultZone),
+		exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone),
 	)
 
-	templates, err := cs.ListTemplates(ctx, gCurrentAccount.DefaultZone, c.Visibility, c.Family)
+	templates, err := cs.ListTemplates(ctx, c.Zone, c.Visibility, c.Family)
 	if err != nil {
 		return err
 	}
@@ -61,6 +107,10 @@ func (c *computeInstanceTemplateListCmd) cmdRun(_ *cobra.Command, _ []string) er
 	out := make(computeInstanceTemplateListOutput, 0)
 
 	for _, t := range templates {
+		if !createdAt.match(t.CreatedAt) {
+			continue
+		}
+
 		out = append(out, computeInstanceTemplateListItemOutput{
 			ID:           *t.ID,
 			Name:         *t.Name,
@@ -72,6 +122,108 @@ func (c *computeInstanceTemplateListCmd) cmdRun(_ *cobra.Command, _ []string) er
 	return output(&out, nil)
 }
 
+// listAllZones lists the templates of all zones, grouped by name and
+// checksum since a same template has a different ID in every zone.
+func (c *computeInstanceTemplateListCmd) listAllZones(createdAt createdAtFilter) error {
+	type templateKey struct{ name, checksum string }
+
+	var (
+		mu        sync.Mutex
+		templates = make(map[templateKey]*computeInstanceTemplateListAllZonesItemOutput)
+	)
+
+	err := forEachZone(allZones, func(zone string) error {
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+		list, err := cs.ListTemplates(ctx, zone, c.Visibility, c.Family)
+		if err != nil {
+			return fmt.Errorf("unable to list templates in zone %s: %v", zone, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, t := range list {
+			if !createdAt.match(t.CreatedAt) {
+				continue
+			}
+
+			k := templateKey{name: *t.Name, checksum: defaultString(t.Checksum, "")}
+			if _, ok := templates[k]; !ok {
+				templates[k] = &computeInstanceTemplateListAllZonesItemOutput{
+					Name:     k.name,
+					Family:   defaultString(t.Family, ""),
+					Checksum: k.checksum,
+				}
+			}
+			templates[k].Zones = append(templates[k].Zones, zone)
+		}
+
+		return nil
+	})
+	if err != nil {
+		// Reporting missing zones based on incomplete results would be
+		// misleading, so we fail in this case.
+		if c.RequireAllZones {
+			return err
+		}
+		_, _ = fmt.Fprintf(os.Stderr,
+			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
+	}
+
+	list := make([]*computeInstanceTemplateListAllZonesItemOutput, 0, len(templates))
+	for _, t := range templates {
+		list = append(list, t)
+	}
+
+	out, incomplete := templatesZonesCoverage(list, allZones)
+	if err := output(&out, nil); err != nil {
+		return err
+	}
+
+	if c.RequireAllZones && len(incomplete) > 0 {
+		return fmt.Errorf("%d template(s) missing from some zones: %s",
+			len(incomplete), strings.Join(incomplete, ", "))
+	}
+
+	return nil
+}
+
+// templatesZonesCoverage fills in the zones among zones each template
+// grouped across zones is missing from, returning the templates sorted by
+// name and checksum along with the sorted names of the templates missing
+// from at least one zone.
+func templatesZonesCoverage(
+	templates []*computeInstanceTemplateListAllZonesItemOutput,
+	zones []string,
+) (computeInstanceTemplateListAllZonesOutput, []string) {
+	out := make(computeInstanceTemplateListAllZonesOutput, 0, len(templates))
+	incomplete := make([]string, 0)
+	for _, t := range templates {
+		sort.Strings(t.Zones)
+		t.MissingZones = make([]string, 0)
+		for _, zone := range zones {
+			if i := sort.SearchStrings(t.Zones, zone); i == len(t.Zones) || t.Zones[i] != zone {
+				t.MissingZones = append(t.MissingZones, zone)
+			}
+		}
+		if len(t.MissingZones) > 0 {
+			incomplete = append(incomplete, t.Name)
+		}
+		out = append(out, *t)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Checksum < out[j].Checksum
+	})
+	sort.Strings(incomplete)
+
+	return out, incomplete
+}
+
 func init() {
 	cobra.CheckErr(registerCLICommand(computeInstanceTemplateCmd, &computeInstanceTemplateListCmd{
 		cliCommandSettings: defaultCLICmdSettings(),
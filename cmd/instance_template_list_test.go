@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_templatesZonesCoverage(t *testing.T) {
+	zones := []string{"at-vie-1", "ch-gva-2", "de-fra-1"}
+
+	out, incomplete := templatesZonesCoverage([]*computeInstanceTemplateListAllZonesItemOutput{
+		{Name: "ubuntu", Checksum: "b", Zones: []string{"de-fra-1"}},
+		{Name: "debian", Checksum: "c", Zones: []string{"de-fra-1", "at-vie-1", "ch-gva-2"}},
+		{Name: "ubuntu", Checksum: "a", Zones: []string{"ch-gva-2", "at-vie-1"}},
+	}, zones)
+
+	require.Equal(t, computeInstanceTemplateListAllZonesOutput{
+		{
+			Name:         "debian",
+			Checksum:     "c",
+			Zones:        []string{"at-vie-1", "ch-gva-2", "de-fra-1"},
+			MissingZones: []string{},
+		},
+		{
+			Name:         "ubuntu",
+			Checksum:     "a",
+			Zones:        []string{"at-vie-1", "ch-gva-2"},
+			MissingZones: []string{"de-fra-1"},
+		},
+		{
+			Name:         "ubuntu",
+			Checksum:     "b",
+			Zones:        []string{"de-fra-1"},
+			MissingZones: []string{"at-vie-1", "ch-gva-2"},
+		},
+	}, out)
+	require.Equal(t, []string{"ubuntu", "ubuntu"}, incomplete)
+
+	out, incomplete = templatesZonesCoverage(nil, zones)
+	require.Empty(t, out)
+	require.Empty(t, incomplete)
+}
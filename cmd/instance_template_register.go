@@ -141,8 +141,9 @@ func (c *computeInstanceTemplateRegisterCmd) cmdRun(cmd *cobra.Command, _ []stri
 		template.URL = &c.URL
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Registering template %q...", c.Name), func() {
+	err = decorateAsyncOperation(fmt.Sprintf("Registering template %q...", c.Name), func() error {
 		template, err = cs.RegisterTemplate(ctx, c.Zone, template)
+		return err
 	})
 	if err != nil {
 		return err
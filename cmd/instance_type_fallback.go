@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+)
+
+// instanceTypeFallbacksMax is the maximum number of alternative instance
+// types of the same family suggested when an instance type is unavailable.
+const instanceTypeFallbacksMax = 3
+
+// instanceTypeUnavailableErrors are the (lowercase) substrings of the API
+// errors returned when an instance type is unavailable in a zone.
+var instanceTypeUnavailableErrors = []string{
+	"insufficient capacity",
+	"type unavailable",
+	"type is unavailable",
+	"type not available",
+	"type is not available",
+}
+
+// instanceTypesCache caches the Compute instance types per zone for the
+// duration of the CLI execution.
+var instanceTypesCache = struct {
+	sync.Mutex
+	zones map[string][]*egoscale.InstanceType
+}{zones: make(map[string][]*egoscale.InstanceType)}
+
+// listInstanceTypesCached returns the Compute instance types available in
+// the specified zone, retrieving them from the API only once per zone.
+func listInstanceTypesCached(zone string) ([]*egoscale.InstanceType, error) {
+	instanceTypesCache.Lock()
+	instanceTypes, cached := instanceTypesCache.zones[zone]
+	instanceTypesCache.Unlock()
+	if cached {
+		return instanceTypes, nil
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+	instanceTypes, err := cs.ListInstanceTypes(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceTypesCache.Lock()
+	instanceTypesCache.zones[zone] = instanceTypes
+	instanceTypesCache.Unlock()
+
+	return instanceTypes, nil
+}
+
+// isInstanceTypeUnavailableError returns true if err is returned by the API
+// because an instance type is unavailable in a zone.
+func isInstanceTypeUnavailableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, v := range instanceTypeUnavailableErrors {
+		if strings.Contains(msg, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// instanceTypeName returns the name of an instance type in the
+// "FAMILY.SIZE" format.
+func instanceTypeName(t *egoscale.InstanceType) string {
+	return fmt.Sprintf("%s.%s", defaultString(t.Family, ""), defaultString(t.Size, ""))
+}
+
+// nearestInstanceTypes returns up to max authorized instance types of the
+// same family as the instance type t, sorted by increasing difference of
+// memory then number of CPUs with t.
+func nearestInstanceTypes(instanceTypes []*egoscale.InstanceType, t *egoscale.InstanceType, max int) []*egoscale.InstanceType {
+	abs := func(v int64) int64 {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}
+
+	nearest := make([]*egoscale.InstanceType, 0)
+	for _, it := range instanceTypes {
+		if defaultString(it.Family, "") != defaultString(t.Family, "") ||
+			defaultString(it.ID, "") == defaultString(t.ID, "") ||
+			(it.Authorized != nil && !*it.Authorized) {
+			continue
+		}
+		nearest = append(nearest, it)
+	}
+
+	memory, cpus := defaultInt64(t.Memory, 0), defaultInt64(t.CPUs, 0)
+	sort.SliceStable(nearest, func(i, j int) bool {
+		mi, mj := abs(defaultInt64(nearest[i].Memory, 0)-memory), abs(defaultInt64(nearest[j].Memory, 0)-memory)
+		if mi != mj {
+			return mi < mj
+		}
+		return abs(defaultInt64(nearest[i].CPUs, 0)-cpus) < abs(defaultInt64(nearest[j].CPUs, 0)-cpus)
+	})
+
+	if len(nearest) > max {
+		nearest = nearest[:max]
+	}
+
+	return nearest
+}
+
+// instanceTypeFallbacks represents the alternatives to an instance type
+// unavailable in a zone.
+type instanceTypeFallbacks struct {
+	zone         string
+	instanceType *egoscale.InstanceType
+
+	// sameFamily are the nearest instance types of the same family in the
+	// same zone.
+	sameFamily []*egoscale.InstanceType
+
+	// otherZones are the other zones where the instance type is available.
+	otherZones []string
+}
+
+func (f *instanceTypeFallbacks) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Instance type %q is unavailable in zone %s, possible alternatives:\n",
+		instanceTypeName(f.instanceType), f.zone)
+
+	if len(f.sameFamily) > 0 {
+		names := make([]string, len(f.sameFamily))
+		for i, t := range f.sameFamily {
+			names[i] = instanceTypeName(t)
+		}
+		fmt.Fprintf(&b, "  * other types in zone %s: %s\n", f.zone, strings.Join(names, ", "))
+	}
+
+	if len(f.otherZones) > 0 {
+		fmt.Fprintf(&b, "  * %s in other zones: %s\n", instanceTypeName(f.instanceType), strings.Join(f.otherZones, ", "))
+	}
+
+	if len(f.sameFamily) > 0 {
+		fmt.Fprintf(&b, `Use the "--fallback-type" flag to retry automatically with %q.`,
+			instanceTypeName(f.sameFamily[0]))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// findInstanceTypeFallbacks returns the alternatives to the instance type
+// instanceTypeID unavailable in the specified zone.
+func findInstanceTypeFallbacks(zone, instanceTypeID string) (*instanceTypeFallbacks, error) {
+	instanceTypes, err := listInstanceTypesCached(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	fallbacks := instanceTypeFallbacks{zone: zone, otherZones: make([]string, 0)}
+	for _, t := range instanceTypes {
+		if defaultString(t.ID, "") == instanceTypeID {
+			fallbacks.instanceType = t
+			break
+		}
+	}
+	if fallbacks.instanceType == nil {
+		return nil, fmt.Errorf("unknown instance type %q", instanceTypeID)
+	}
+
+	fallbacks.sameFamily = nearestInstanceTypes(instanceTypes, fallbacks.instanceType, instanceTypeFallbacksMax)
+
+	var mu sync.Mutex
+	// Zones where the instance types cannot be retrieved are ignored.
+	_ = forEachZone(allZones, func(z string) error {
+		if z == zone {
+			return nil
+		}
+
+		instanceTypes, err := listInstanceTypesCached(z)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range instanceTypes {
+			if instanceTypeName(t) == instanceTypeName(fallbacks.instanceType) &&
+				(t.Authorized == nil || *t.Authorized) {
+				mu.Lock()
+				fallbacks.otherZones = append(fallbacks.otherZones, z)
+				mu.Unlock()
+				break
+			}
+		}
+
+		return nil
+	})
+	sort.Strings(fallbacks.otherZones)
+
+	return &fallbacks, nil
+}
+
+// createWithInstanceTypeFallback calls the create function with the instance
+// type instanceTypeID. If it fails because the instance type is unavailable
+// in the specified zone, the alternatives are reported in the returned error,
+// or if fallback is true the create function is called again with the nearest
+// alternative instance type of the same family.
+func createWithInstanceTypeFallback(
+	zone string,
+	instanceTypeID string,
+	fallback bool,
+	create func(instanceTypeID string) error,
+) error {
+	err := create(instanceTypeID)
+	if !isInstanceTypeUnavailableError(err) {
+		return err
+	}
+
+	fallbacks, fallbacksErr := findInstanceTypeFallbacks(zone, instanceTypeID)
+	if fallbacksErr != nil {
+		return err
+	}
+
+	if fallback && len(fallbacks.sameFamily) > 0 {
+		fmt.Fprintf(os.Stderr, "warning: instance type %q is unavailable in zone %s, retrying with %q\n",
+			instanceTypeName(fallbacks.instanceType), zone, instanceTypeName(fallbacks.sameFamily[0]))
+		return create(*fallbacks.sameFamily[0].ID)
+	}
+
+	return fmt.Errorf("%w\n\n%s", err, fallbacks)
+}
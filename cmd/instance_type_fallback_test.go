@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func testInstanceType(id, family, size string, cpus, memory int64) *egoscale.InstanceType {
+	return &egoscale.InstanceType{
+		ID:     &id,
+		Family: &family,
+		Size:   &size,
+		CPUs:   &cpus,
+		Memory: &memory,
+	}
+}
+
+func Test_isInstanceTypeUnavailableError(t *testing.T) {
+	require.True(t, isInstanceTypeUnavailableError(errors.New("API error: Insufficient capacity in zone")))
+	require.True(t, isInstanceTypeUnavailableError(errors.New("invalid request: instance type is not available")))
+	require.False(t, isInstanceTypeUnavailableError(errors.New("invalid request: invalid disk size")))
+	require.False(t, isInstanceTypeUnavailableError(nil))
+}
+
+func Test_nearestInstanceTypes(t *testing.T) {
+	unauthorized := false
+	gpu := testInstanceType("g", "gpu", "medium", 2, 4<<30)
+	small := testInstanceType("s", "standard", "small", 2, 2<<30)
+	medium := testInstanceType("m", "standard", "medium", 2, 4<<30)
+	large := testInstanceType("l", "standard", "large", 4, 8<<30)
+	extraLarge := testInstanceType("xl", "standard", "extra-large", 4, 16<<30)
+	huge := testInstanceType("h", "standard", "huge", 8, 32<<30)
+	huge.Authorized = &unauthorized
+
+	types := []*egoscale.InstanceType{gpu, small, medium, large, extraLarge, huge}
+
+	require.Equal(t, []*egoscale.InstanceType{small, large, extraLarge}, nearestInstanceTypes(types, medium, 3))
+	require.Equal(t, []*egoscale.InstanceType{medium}, nearestInstanceTypes(types, large, 1))
+}
+
+func Test_createWithInstanceTypeFallback(t *testing.T) {
+	defer func() { instanceTypesCache.zones = make(map[string][]*egoscale.InstanceType) }()
+
+	medium := testInstanceType("m", "standard", "medium", 2, 4<<30)
+	large := testInstanceType("l", "standard", "large", 4, 8<<30)
+	for _, zone := range allZones {
+		instanceTypesCache.zones[zone] = []*egoscale.InstanceType{large}
+	}
+	instanceTypesCache.zones["ch-gva-2"] = []*egoscale.InstanceType{medium, large}
+
+	unavailable := errors.New("API error: insufficient capacity")
+
+	t.Run("suggestions", func(t *testing.T) {
+		tried := make([]string, 0)
+		err := createWithInstanceTypeFallback("ch-gva-2", "l", false, func(id string) error {
+			tried = append(tried, id)
+			return unavailable
+		})
+		require.True(t, errors.Is(err, unavailable))
+		require.Contains(t, err.Error(), `other types in zone ch-gva-2: standard.medium`)
+		require.Contains(t, err.Error(), `standard.large in other zones: `)
+		require.Equal(t, []string{"l"}, tried)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		tried := make([]string, 0)
+		err := createWithInstanceTypeFallback("ch-gva-2", "l", true, func(id string) error {
+			tried = append(tried, id)
+			if id == "l" {
+				return unavailable
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"l", "m"}, tried)
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		other := errors.New("invalid request: invalid disk size")
+		err := createWithInstanceTypeFallback("ch-gva-2", "l", true, func(string) error { return other })
+		require.Equal(t, other, err)
+	})
+}
@@ -13,7 +13,7 @@ type instanceUpdateCmd struct {
 
 	_ bool `cli-cmd:"update"`
 
-	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Instance string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"instance"`
 
 	CloudInitFile string            `cli-flag:"cloud-init" cli-short:"c" cli-usage:"instance cloud-init user data configuration file path"`
 	Labels        map[string]string `cli-flag:"label" cli-usage:"instance label (format: key=value)"`
@@ -68,10 +68,8 @@ func (c *instanceUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	if updated {
-		decorateAsyncOperation(fmt.Sprintf("Updating instance %q...", c.Instance), func() {
-			if err = cs.UpdateInstance(ctx, c.Zone, instance); err != nil {
-				return
-			}
+		err = decorateAsyncOperation(fmt.Sprintf("Updating instance %q...", c.Instance), func() error {
+			return cs.UpdateInstance(ctx, c.Zone, instance)
 		})
 		if err != nil {
 			return err
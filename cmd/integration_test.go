@@ -0,0 +1,382 @@
+package cmd
+
+//go:generate go test -run ^TestIntegration -record .
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/exoscale/egoscale"
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+)
+
+// The integration tests run CLI commands end-to-end against an HTTP server
+// replaying recorded Exoscale API fixtures (testdata/fixtures/<test>.json),
+// asserting on the rendered output, the exit code and the API requests
+// payloads.
+//
+// Fixtures can be re-recorded against a sandbox account (which must contain
+// the resources referenced by the tests) using "go generate" with the
+// EXOSCALE_API_KEY and EXOSCALE_API_SECRET environment variables set. The
+// expected command outputs (testdata/golden/<test>.golden) can be updated
+// using the -update flag.
+var (
+	testRecordFixtures = flag.Bool("record", false, "record API fixtures against a sandbox account")
+	testUpdateGolden   = flag.Bool("update", false, "update golden output files")
+)
+
+const (
+	testFixturesDir = "testdata/fixtures"
+	testGoldenDir   = "testdata/golden"
+)
+
+// apiFixture represents a recorded API request/response exchange.
+type apiFixture struct {
+	Request struct {
+		Method string          `json:"method"`
+		Path   string          `json:"path"`
+		Body   json.RawMessage `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status int             `json:"status"`
+		Body   json.RawMessage `json:"body,omitempty"`
+	} `json:"response"`
+}
+
+// apiRequest represents an API request received during a test run.
+type apiRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// apiReplayServer is an HTTP server replaying recorded API fixtures. Requests
+// are matched by method and path in the order of the fixtures; once all the
+// fixtures matching a request have been consumed the last one is replayed
+// (e.g. to satisfy async operations polling).
+type apiReplayServer struct {
+	*httptest.Server
+
+	t        *testing.T
+	fixtures []apiFixture
+	used     []bool
+	requests []apiRequest
+	mu       sync.Mutex
+}
+
+func newAPIReplayServer(t *testing.T, fixtures []apiFixture) *apiReplayServer {
+	s := &apiReplayServer{
+		t:        t,
+		fixtures: fixtures,
+		used:     make([]bool, len(fixtures)),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *apiReplayServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("unable to read request body: %s", err)
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/"+exoapi.Prefix)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, apiRequest{Method: r.Method, Path: path, Body: body})
+
+	match := -1
+	for i, f := range s.fixtures {
+		if f.Request.Method != r.Method || f.Request.Path != path {
+			continue
+		}
+		match = i
+		if !s.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		s.t.Errorf("unexpected API request: %s %s", r.Method, path)
+		http.Error(w, `{"message":"no fixture matching request"}`, http.StatusNotFound)
+		return
+	}
+	s.used[match] = true
+
+	f := s.fixtures[match]
+	if len(f.Request.Body) > 0 {
+		// The handler doesn't run in the test goroutine, in which FailNow
+		// must be called: only report the mismatch.
+		assert.JSONEq(s.t, string(f.Request.Body), string(body), "%s %s request body", r.Method, path)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(f.Response.Status)
+	_, _ = w.Write(f.Response.Body)
+}
+
+// request returns the body of the n-th request (starting at 0) received matching
+// the specified method and path.
+func (s *apiReplayServer) request(method, path string, n int) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.requests {
+		if r.Method == method && r.Path == path {
+			if n == 0 {
+				return r.Body
+			}
+			n--
+		}
+	}
+
+	s.t.Fatalf("no API request received matching %s %s", method, path)
+	return nil
+}
+
+// replayTransport is an http.RoundTripper redirecting the API requests to
+// a local server, regardless of the zone endpoint they have been issued to.
+type replayTransport struct {
+	host string
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = t.host
+	req.Host = t.host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// recordTransport is an http.RoundTripper forwarding the API requests to the
+// actual API and recording the exchanges as fixtures.
+type recordTransport struct {
+	fixtures []apiFixture
+	mu       sync.Mutex
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var f apiFixture
+
+	f.Request.Method = req.Method
+	f.Request.Path = strings.TrimPrefix(req.URL.Path, "/"+exoapi.Prefix)
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if len(body) > 0 {
+			f.Request.Body = body
+		}
+	}
+
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	f.Response.Status = res.StatusCode
+	if len(body) > 0 {
+		f.Response.Body = body
+	}
+
+	t.mu.Lock()
+	t.fixtures = append(t.fixtures, f)
+	t.mu.Unlock()
+
+	return res, nil
+}
+
+// setupIntegrationTest sets up the global API client to either replay the
+// current test's API fixtures or record them if the -record flag is set.
+// The replay server is returned in replay mode, nil otherwise.
+func setupIntegrationTest(t *testing.T) *apiReplayServer {
+	fixtureFile := filepath.Join(testFixturesDir, t.Name()+".json")
+
+	apiKey, apiSecret := "EXOtest", "test"
+	if *testRecordFixtures {
+		apiKey, apiSecret = os.Getenv("EXOSCALE_API_KEY"), os.Getenv("EXOSCALE_API_SECRET")
+		if apiKey == "" || apiSecret == "" {
+			t.Fatal("EXOSCALE_API_KEY and EXOSCALE_API_SECRET must be set to record fixtures")
+		}
+	}
+
+	var (
+		transport http.RoundTripper
+		server    *apiReplayServer
+		opts      []exov2.ClientOpt
+	)
+
+	if *testRecordFixtures {
+		recorder := &recordTransport{}
+		transport = recorder
+		t.Cleanup(func() {
+			data, err := json.MarshalIndent(recorder.fixtures, "", "  ")
+			require.NoError(t, err)
+			require.NoError(t, os.MkdirAll(testFixturesDir, 0o755))
+			require.NoError(t, ioutil.WriteFile(fixtureFile, append(data, '\n'), 0o644))
+		})
+	} else {
+		data, err := ioutil.ReadFile(fixtureFile)
+		require.NoError(t, err, "unable to read API fixtures")
+
+		var fixtures []apiFixture
+		require.NoError(t, json.Unmarshal(data, &fixtures), "unable to parse API fixtures")
+
+		server = newAPIReplayServer(t, fixtures)
+		transport = &replayTransport{host: strings.TrimPrefix(server.URL, "http://")}
+		opts = append(opts, exov2.ClientOptWithPollInterval(time.Millisecond))
+	}
+
+	httpClient := &http.Client{Transport: transport}
+	opts = append(opts, exov2.ClientOptWithHTTPClient(httpClient))
+	client, err := exov2.NewClient(apiKey, apiSecret, opts...)
+	require.NoError(t, err)
+
+	savedCS, savedHTTPClient, savedContext, savedAccount := cs, csHTTPClient, gContext, gCurrentAccount
+	t.Cleanup(func() {
+		cs, csHTTPClient, gContext, gCurrentAccount = savedCS, savedHTTPClient, savedContext, savedAccount
+	})
+
+	csHTTPClient = httpClient
+
+	cs = egoscale.NewClient(defaultEndpoint, apiKey, apiSecret, egoscale.WithoutV2Client())
+	cs.Client = client
+	gContext = context.Background()
+	gCurrentAccount = &account{
+		Name:        "test",
+		Key:         apiKey,
+		Secret:      apiSecret,
+		DefaultZone: defaultZone,
+		Endpoint:    defaultEndpoint,
+		Environment: defaultEnvironment,
+	}
+
+	return server
+}
+
+// resetCommandFlags resets the flags of the command tree to their default
+// values, as the commands state persists across executions.
+// Note: map flags (e.g. --label) can't be reset and must not be specified
+// in more than one test.
+func resetCommandFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			_ = v.Replace(nil)
+		} else if f.Value.Type() != "stringToString" {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+
+	for _, c := range cmd.Commands() {
+		resetCommandFlags(c)
+	}
+}
+
+// runCLI executes the CLI with the specified arguments, returning the
+// content printed to the standard output and the process exit code.
+func runCLI(t *testing.T, args ...string) (string, int) {
+	resetCommandFlags(RootCmd)
+	RootCmd.SetArgs(args)
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		out <- buf.String()
+	}()
+
+	err = RootCmd.Execute()
+	w.Close()
+	if err != nil {
+		t.Logf("command error: %s", err)
+	}
+
+	return <-out, exitCode(gContext, err)
+}
+
+// outputJSONLine returns the last line of a command output containing
+// a JSON document, discarding the async operations progress feedback.
+func outputJSONLine(t *testing.T, out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if l := strings.TrimSpace(lines[i]); strings.HasPrefix(l, "{") || strings.HasPrefix(l, "[") {
+			return l
+		}
+	}
+
+	t.Fatalf("no JSON found in command output:\n%s", out)
+	return ""
+}
+
+// requireGoldenOutput asserts that the command output matches the current
+// test's golden file.
+func requireGoldenOutput(t *testing.T, out string) {
+	goldenFile := filepath.Join(testGoldenDir, t.Name()+".golden")
+
+	if *testUpdateGolden || *testRecordFixtures {
+		require.NoError(t, os.MkdirAll(testGoldenDir, 0o755))
+		require.NoError(t, ioutil.WriteFile(goldenFile, []byte(out), 0o644))
+	}
+
+	expected, err := ioutil.ReadFile(goldenFile)
+	require.NoError(t, err, "unable to read golden output")
+	require.Equal(t, string(expected), out)
+}
+
+// requireJSONField asserts that the JSON document data has the expected value
+// at the specified dot-separated path.
+func requireJSONField(t *testing.T, data []byte, path string, expected interface{}) {
+	var v interface{}
+	require.NoError(t, json.Unmarshal(data, &v))
+
+	for _, k := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		require.True(t, ok, "%s: not a JSON object", path)
+		v = m[k]
+	}
+
+	require.Equal(t, fmt.Sprint(expected), fmt.Sprint(v), path)
+}
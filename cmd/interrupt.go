@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// exitCodeInterrupted is the exit code of the CLI when a command is
+// interrupted by the user (e.g. using Ctrl-C) or terminated by a signal.
+const exitCodeInterrupted = 130
+
+// asyncOperation represents an asynchronous operation in progress, along with
+// the IDs of the API operations submitted while performing it.
+type asyncOperation struct {
+	message      string
+	operationIDs []string
+}
+
+// asyncOperations keeps track of the asynchronous operations performed using
+// decorateAsyncOperation(), in order to report to the user which operations
+// were in progress if the CLI gets interrupted.
+var asyncOperations = struct {
+	sync.Mutex
+	next      int
+	inflight  map[int]*asyncOperation
+	completed int
+}{
+	inflight: make(map[int]*asyncOperation),
+}
+
+var interruptOnce sync.Once
+
+// interruptHooks are the functions run before exiting the CLI when it gets
+// interrupted, e.g. to clean up the intermediate resources of a multi-step
+// operation.
+var interruptHooks = struct {
+	sync.Mutex
+	next  int
+	hooks map[int]func()
+}{
+	hooks: make(map[int]func()),
+}
+
+// onInterrupt registers fn to be run by exitInterrupted() before exiting the
+// CLI, and returns a function unregistering it. Hooks are run in the reverse
+// order of their registration.
+func onInterrupt(fn func()) func() {
+	interruptHooks.Lock()
+	defer interruptHooks.Unlock()
+
+	interruptHooks.next++
+	id := interruptHooks.next
+	interruptHooks.hooks[id] = fn
+
+	return func() {
+		interruptHooks.Lock()
+		defer interruptHooks.Unlock()
+
+		delete(interruptHooks.hooks, id)
+	}
+}
+
+// runInterruptHooks runs the registered interrupt hooks.
+func runInterruptHooks() {
+	interruptHooks.Lock()
+	hooks := make([]func(), 0, len(interruptHooks.hooks))
+	for i := interruptHooks.next; i > 0; i-- {
+		if fn, ok := interruptHooks.hooks[i]; ok {
+			hooks = append(hooks, fn)
+		}
+	}
+	interruptHooks.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// asyncOperationStarted records the start of an asynchronous operation
+// described by message, returning an identifier to be passed to
+// asyncOperationCompleted() once the operation is done.
+func asyncOperationStarted(message string) int {
+	asyncOperations.Lock()
+	defer asyncOperations.Unlock()
+
+	asyncOperations.next++
+	asyncOperations.inflight[asyncOperations.next] = &asyncOperation{message: strings.TrimSuffix(message, "...")}
+
+	return asyncOperations.next
+}
+
+// recordAsyncOperationID inspects the response to an API request, and if it
+// is an API V2 asynchronous operation submitted while asynchronous operations
+// are in progress, records its ID on the latest operation started.
+func recordAsyncOperationID(r *http.Request, res *http.Response) {
+	if res == nil || r.Method == http.MethodGet || res.StatusCode != http.StatusOK {
+		return
+	}
+
+	asyncOperations.Lock()
+	defer asyncOperations.Unlock()
+
+	if len(asyncOperations.inflight) == 0 {
+		return
+	}
+
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	var op struct {
+		ID    string `json:"id"`
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(body, &op); err != nil || op.ID == "" || op.State == "" {
+		return
+	}
+
+	for i := asyncOperations.next; i > 0; i-- {
+		if o, ok := asyncOperations.inflight[i]; ok {
+			o.operationIDs = append(o.operationIDs, op.ID)
+			return
+		}
+	}
+}
+
+// asyncOperationCompleted records the completion of an asynchronous
+// operation previously registered using asyncOperationStarted().
+func asyncOperationCompleted(id int) {
+	asyncOperations.Lock()
+	defer asyncOperations.Unlock()
+
+	delete(asyncOperations.inflight, id)
+	asyncOperations.completed++
+}
+
+// reportInterruptedOperations writes to w a summary of the asynchronous
+// operations that were either in progress or completed when the CLI got
+// interrupted.
+func reportInterruptedOperations(w io.Writer) {
+	asyncOperations.Lock()
+	defer asyncOperations.Unlock()
+
+	_, _ = fmt.Fprintln(w, "Operation interrupted.")
+
+	if len(asyncOperations.inflight) > 0 {
+		_, _ = fmt.Fprintln(w, "The following operations were in progress and might still be running "+
+			"(the related resources may still be created server-side):")
+		for i := 1; i <= asyncOperations.next; i++ {
+			o, ok := asyncOperations.inflight[i]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case len(o.operationIDs) == 1:
+				_, _ = fmt.Fprintf(w, "  * %s (operation ID: %s)\n", o.message, o.operationIDs[0])
+			case len(o.operationIDs) > 1:
+				_, _ = fmt.Fprintf(w, "  * %s (operation IDs: %s)\n", o.message, strings.Join(o.operationIDs, ", "))
+			default:
+				_, _ = fmt.Fprintf(w, "  * %s\n", o.message)
+			}
+		}
+		_, _ = fmt.Fprintln(w, `Please check the status of the related resources (e.g. using the `+
+			`corresponding "show" command) before retrying.`)
+	}
+
+	if asyncOperations.completed > 0 {
+		_, _ = fmt.Fprintf(w, "%d operation(s) completed before the interruption.\n", asyncOperations.completed)
+	}
+}
+
+// exitInterrupted reports the asynchronous operations in progress, runs the
+// interrupt hooks and exits the CLI with the exitCodeInterrupted status code.
+// If called concurrently, the report is only printed once.
+func exitInterrupted() {
+	interruptOnce.Do(func() {
+		reportInterruptedOperations(os.Stderr)
+		runInterruptHooks()
+		os.Exit(exitCodeInterrupted)
+	})
+}
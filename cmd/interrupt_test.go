@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_reportInterruptedOperations(t *testing.T) {
+	completed := asyncOperationStarted("Creating instance \"a\"...")
+	asyncOperationCompleted(completed)
+	inflight := asyncOperationStarted("Creating SKS cluster \"b\"...")
+	defer asyncOperationCompleted(inflight)
+
+	var out strings.Builder
+	reportInterruptedOperations(&out)
+
+	require.Contains(t, out.String(), "Operation interrupted.")
+	require.Contains(t, out.String(), "may still be created server-side")
+	require.Contains(t, out.String(), `  * Creating SKS cluster "b"`+"\n")
+	require.NotContains(t, out.String(), `Creating instance "a"`)
+	require.Contains(t, out.String(), "operation(s) completed before the interruption")
+}
+
+func Test_reportInterruptedOperations_operationIDs(t *testing.T) {
+	id := asyncOperationStarted("Creating instance \"a\"...")
+	defer asyncOperationCompleted(id)
+
+	r, err := http.NewRequest(http.MethodPost, "https://api.example.net/v2/instance", nil)
+	require.NoError(t, err)
+	res := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"id":"op-1","state":"pending"}`)),
+	}
+	recordAsyncOperationID(r, res)
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"op-1","state":"pending"}`, string(body))
+
+	var out strings.Builder
+	reportInterruptedOperations(&out)
+
+	require.Contains(t, out.String(), `  * Creating instance "a" (operation ID: op-1)`+"\n")
+}
+
+func Test_runInterruptHooks(t *testing.T) {
+	var calls []string
+
+	defer onInterrupt(func() { calls = append(calls, "first") })()
+	remove := onInterrupt(func() { calls = append(calls, "removed") })
+	defer onInterrupt(func() { calls = append(calls, "last") })()
+	remove()
+
+	runInterruptHooks()
+
+	require.Equal(t, []string{"last", "first"}, calls)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 
@@ -25,10 +26,47 @@ const (
 	limitSKSClusters      = "sks_cluster"
 )
 
+// limitsResourceNames are the names of the resources accepted by the
+// "--resource" flag, by resource limit type.
+var limitsResourceNames = map[string]string{
+	limitComputeInstances: "instance",
+	limitGPUs:             "gpu",
+	limitSnapshots:        "snapshot",
+	limitTemplates:        "template",
+	limitIPAddresses:      "elastic-ip",
+	limitPrivateNetworks:  "private-network",
+	limitNLBs:             "nlb",
+	limitIAMAPIKeys:       "iam-key",
+	limitSOSBuckets:       "sos-bucket",
+	limitSKSClusters:      "sks-cluster",
+}
+
+// limitsSupportedResources returns the sorted names of the resources
+// accepted by the "--resource" flag.
+func limitsSupportedResources() []string {
+	names := make([]string, 0, len(limitsResourceNames))
+	for _, name := range limitsResourceNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 type LimitsItemOutput struct {
-	Resource string `json:"resource"`
-	Used     int    `json:"used"`
-	Max      int    `json:"max"`
+	Resource  string `json:"resource"`
+	Used      int    `json:"used"`
+	Max       int    `json:"max"`
+	Remaining int    `json:"remaining"`
+}
+
+// usage returns the usage of the resource in percent of the limit.
+func (o *LimitsItemOutput) usage() int {
+	if o.Max <= 0 {
+		return 100
+	}
+
+	return o.Used * 100 / o.Max
 }
 
 type LimitsOutput []LimitsItemOutput
@@ -40,11 +78,37 @@ func (o *LimitsOutput) toTable() { outputTable(o) }
 var limitsCmd = &cobra.Command{
 	Use:   "limits",
 	Short: "Current account limits",
-	Long: fmt.Sprintf(`This command lists the safety limits currently enforced on your account.
+	Long: fmt.Sprintf(`This command lists the safety limits currently enforced on your account,
+along with the current usage of the resources.
+
+Using the "--warn-threshold PERCENT" flag, the command exits with an error
+if the usage of any listed resource is at or above the specified percentage
+of its limit, e.g. to check that enough resources remain before a deployment.
+
+Supported resources (for the "--resource" flag): %s
 
 Supported output template annotations: %s`,
+		strings.Join(limitsSupportedResources(), ", "),
 		strings.Join(outputterTemplateAnnotations(&LimitsOutput{}), ", ")),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		resources, err := cmd.Flags().GetStringSlice("resource")
+		if err != nil {
+			return err
+		}
+		for _, r := range resources {
+			if !isInList(limitsSupportedResources(), r) {
+				return fmt.Errorf("unsupported resource %q", r)
+			}
+		}
+
+		warnThreshold, err := cmd.Flags().GetInt("warn-threshold")
+		if err != nil {
+			return err
+		}
+		if warnThreshold < 0 || warnThreshold > 100 {
+			return fmt.Errorf("warning threshold must be a percentage between 0 and 100")
+		}
+
 		var curUsage sync.Map
 
 		// Global resources ///////////////////////////////////////////////
@@ -158,6 +222,7 @@ Supported output template annotations: %s`,
 		}
 
 		out := LimitsOutput{}
+		exceeded := make([]string, 0)
 
 		limits, err := cs.ListWithContext(gContext, &egoscale.ResourceLimit{})
 		if err != nil {
@@ -167,20 +232,45 @@ Supported output template annotations: %s`,
 		for _, key := range limits {
 			limit := key.(*egoscale.ResourceLimit)
 
+			if len(resources) > 0 && !isInList(resources, limitsResourceNames[limit.ResourceTypeName]) {
+				continue
+			}
+
 			cur, ok := curUsage.Load(limit.ResourceTypeName)
 			if ok {
-				out = append(out, LimitsItemOutput{
-					Resource: resourceLimitLabels[limit.ResourceTypeName],
-					Used:     cur.(int),
-					Max:      int(limit.Max),
-				})
+				item := LimitsItemOutput{
+					Resource:  resourceLimitLabels[limit.ResourceTypeName],
+					Used:      cur.(int),
+					Max:       int(limit.Max),
+					Remaining: int(limit.Max) - cur.(int),
+				}
+				if item.Remaining < 0 {
+					item.Remaining = 0
+				}
+				out = append(out, item)
+
+				if warnThreshold > 0 && item.usage() >= warnThreshold {
+					exceeded = append(exceeded, fmt.Sprintf("%s (%d%%)", item.Resource, item.usage()))
+				}
 			}
 		}
 
-		return output(&out, nil)
+		if err := output(&out, nil); err != nil {
+			return err
+		}
+
+		if len(exceeded) > 0 {
+			return fmt.Errorf("resources usage at or above %d%% of the limit: %s",
+				warnThreshold, strings.Join(exceeded, ", "))
+		}
+
+		return nil
 	},
 }
 
 func init() {
+	limitsCmd.Flags().StringSlice("resource", nil, "only list the specified resource (can be specified multiple times)")
+	limitsCmd.Flags().Int("warn-threshold", 0,
+		"exit with an error if any resource usage is at or above the specified percentage of its limit")
 	RootCmd.AddCommand(limitsCmd)
 }
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitsItemOutputUsage(t *testing.T) {
+	require.Equal(t, 50, (&LimitsItemOutput{Used: 10, Max: 20}).usage())
+	require.Equal(t, 100, (&LimitsItemOutput{Used: 20, Max: 20}).usage())
+	require.Equal(t, 100, (&LimitsItemOutput{Used: 0, Max: 0}).usage())
+}
+
+func TestLimitsSupportedResources(t *testing.T) {
+	resources := limitsSupportedResources()
+	require.Len(t, resources, len(limitsResourceNames))
+	require.Contains(t, resources, "instance")
+}
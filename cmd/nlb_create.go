@@ -23,6 +23,8 @@ func (c *nlbCreateCmd) cmdAliases() []string { return gCreateAlias }
 
 func (c *nlbCreateCmd) cmdShort() string { return "Create a Network Load Balancer" }
 
+func (c *nlbCreateCmd) cmdDryRunSupported() {}
+
 func (c *nlbCreateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command creates a Network Load Balancer.
 
@@ -52,11 +54,16 @@ func (c *nlbCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		Name: &c.Name,
 	}
 
+	if gDryRun {
+		return outputDryRun(nlb)
+	}
+
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
 	var err error
-	decorateAsyncOperation(fmt.Sprintf("Creating Network Load Balancer %q...", c.Name), func() {
+	err = decorateAsyncOperation(fmt.Sprintf("Creating Network Load Balancer %q...", c.Name), func() error {
 		nlb, err = cs.CreateNetworkLoadBalancer(ctx, c.Zone, nlb)
+		return err
 	})
 	if err != nil {
 		return err
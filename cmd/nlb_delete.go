@@ -10,7 +10,7 @@ import (
 type nlbDeleteCmd struct {
 	_ bool `cli-cmd:"delete"`
 
-	NetworkLoadBalancer string `cli-arg:"#" cli-usage:"NAME|ID"`
+	NetworkLoadBalancer string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"nlb"`
 
 	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
 	Zone  string `cli-short:"z" cli-usage:"Network Load Balancer zone"`
@@ -28,13 +28,11 @@ func (c *nlbDeleteCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 }
 
 func (c *nlbDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
-	if !c.Force {
-		if !askQuestion(fmt.Sprintf(
-			"Are you sure you want to delete Network Load Balancer %q?",
-			c.NetworkLoadBalancer,
-		)) {
-			return nil
-		}
+	if !askQuestion(fmt.Sprintf(
+		"Are you sure you want to delete Network Load Balancer %q?",
+		c.NetworkLoadBalancer,
+	)) {
+		return nil
 	}
 
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
@@ -44,8 +42,8 @@ func (c *nlbDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Deleting Network Load Balancer %q...", c.NetworkLoadBalancer), func() {
-		err = cs.DeleteNetworkLoadBalancer(ctx, c.Zone, *nlb.ID)
+	err = decorateAsyncOperation(fmt.Sprintf("Deleting Network Load Balancer %q...", c.NetworkLoadBalancer), func() error {
+		return cs.DeleteNetworkLoadBalancer(ctx, c.Zone, *nlb.ID)
 	})
 	if err != nil {
 		return err
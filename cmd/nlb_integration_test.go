@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testNLBID = "2a9d1f5c-3b7e-4c61-9d1a-5f0e8b2c4a10"
+
+func TestIntegrationNLBShow(t *testing.T) {
+	setupIntegrationTest(t)
+
+	out, code := runCLI(t, "nlb", "show", "web-nlb", "-z", "ch-gva-2")
+	require.Equal(t, 0, code)
+	requireGoldenOutput(t, out)
+}
+
+func TestIntegrationNLBShowJSON(t *testing.T) {
+	setupIntegrationTest(t)
+
+	out, code := runCLI(t, "nlb", "show", "web-nlb", "-z", "ch-gva-2", "-O", "json")
+	require.Equal(t, 0, code)
+	require.JSONEq(t, `{
+		"id": "2a9d1f5c-3b7e-4c61-9d1a-5f0e8b2c4a10",
+		"name": "web-nlb",
+		"description": "Web frontends",
+		"created_at": "2021-06-01 10:00:00 +0000 UTC",
+		"zone": "ch-gva-2",
+		"ip_address": "194.182.160.10",
+		"state": "running",
+		"services": [{
+			"id": "7c3e5a21-9f4b-4e2d-8a6c-1b0d3e5f7a92",
+			"name": "http",
+			"description": "",
+			"instance_pool_id": "",
+			"protocol": "",
+			"port": 0,
+			"target_port": 0,
+			"strategy": "",
+			"healthcheck": {"mode": "", "port": 0, "interval": 0, "timeout": 0, "retries": 0, "uri": "", "tls_sni": ""},
+			"healthcheck_status": null,
+			"state": "",
+			"labels": null
+		}],
+		"labels": {"team": "web"}
+	}`, outputJSONLine(t, out))
+}
+
+func TestIntegrationNLBShowNotFound(t *testing.T) {
+	setupIntegrationTest(t)
+
+	out, code := runCLI(t, "nlb", "show", "api-nlb", "-z", "ch-gva-2")
+	require.Equal(t, 1, code)
+	require.Empty(t, out)
+}
+
+func TestIntegrationNLBServiceAdd(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	out, code := runCLI(t, "nlb", "service", "add", "web-nlb", "https",
+		"-z", "ch-gva-2",
+		"--description", "TLS traffic",
+		"--instance-pool", "web-pool",
+		"--port", "443",
+		"--target-port", "8443",
+		"--strategy", "source-hash",
+		"-O", "json")
+	require.Equal(t, 0, code)
+
+	payload := server.request(http.MethodPost, "/load-balancer/"+testNLBID+"/service", 0)
+	requireJSONField(t, payload, "healthcheck.port", 8443)
+	requireJSONField(t, payload, "instance-pool.id", "9b4c7d2e-1f8a-4b3c-a5d6-e7f8091a2b3c")
+
+	svc := []byte(outputJSONLine(t, out))
+	requireJSONField(t, svc, "id", "5e8f2b14-6a3d-4c9e-b7f1-0a2c4d6e8b33")
+	requireJSONField(t, svc, "name", "https")
+	requireJSONField(t, svc, "strategy", "source-hash")
+	requireJSONField(t, svc, "target_port", 8443)
+}
@@ -56,12 +56,13 @@ func (c *nlbListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	out := make(nlbListOutput, 0)
 	res := make(chan nlbListItemOutput)
-	defer close(res)
+	done := make(chan struct{})
 
 	go func() {
 		for nlb := range res {
 			out = append(out, nlb)
 		}
+		close(done)
 	}()
 	err := forEachZone(zones, func(zone string) error {
 		list, err := cs.ListNetworkLoadBalancers(ctx, zone)
@@ -80,6 +81,8 @@ func (c *nlbListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 		return nil
 	})
+	close(res)
+	<-done
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr,
 			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
@@ -1,6 +1,13 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/spf13/cobra"
+)
 
 var nlbServiceCmd = &cobra.Command{
 	Use:     "service",
@@ -8,6 +15,149 @@ var nlbServiceCmd = &cobra.Command{
 	Aliases: []string{"svc"},
 }
 
+// nlbServiceLabelPrefix is the prefix of the Network Load Balancer labels
+// used to store its services labels: since the API doesn't support labels
+// on services, the CLI stores them in the parent Network Load Balancer
+// labels using keys formatted as "svc.<SERVICE-ID>.<KEY>".
+const nlbServiceLabelPrefix = "svc."
+
+func nlbServiceLabelKeyPrefix(serviceID string) string {
+	return nlbServiceLabelPrefix + serviceID + "."
+}
+
+// nlbServiceLabels returns the labels of a Network Load Balancer service.
+func nlbServiceLabels(nlb *egoscale.NetworkLoadBalancer, serviceID string) map[string]string {
+	labels := make(map[string]string)
+
+	if nlb.Labels != nil {
+		prefix := nlbServiceLabelKeyPrefix(serviceID)
+		for k, v := range *nlb.Labels {
+			if strings.HasPrefix(k, prefix) {
+				labels[strings.TrimPrefix(k, prefix)] = v
+			}
+		}
+	}
+
+	return labels
+}
+
+// nlbSetServiceLabels replaces the labels of a Network Load Balancer
+// service in the Network Load Balancer labels. The Network Load Balancer
+// must then be updated for the change to be applied.
+func nlbSetServiceLabels(nlb *egoscale.NetworkLoadBalancer, serviceID string, labels map[string]string) {
+	prefix := nlbServiceLabelKeyPrefix(serviceID)
+	nlbLabels := make(map[string]string)
+
+	if nlb.Labels != nil {
+		for k, v := range *nlb.Labels {
+			if !strings.HasPrefix(k, prefix) {
+				nlbLabels[k] = v
+			}
+		}
+	}
+
+	for k, v := range labels {
+		nlbLabels[prefix+k] = v
+	}
+
+	nlb.Labels = &nlbLabels
+}
+
+// nlbOwnLabels returns the Network Load Balancer labels, excluding the ones
+// storing its services labels.
+func nlbOwnLabels(labels *map[string]string) map[string]string {
+	own := make(map[string]string)
+
+	if labels != nil {
+		for k, v := range *labels {
+			if !strings.HasPrefix(k, nlbServiceLabelPrefix) {
+				own[k] = v
+			}
+		}
+	}
+
+	return own
+}
+
+// nlbServicesLabels returns the Network Load Balancer labels storing its
+// services labels.
+func nlbServicesLabels(labels *map[string]string) map[string]string {
+	svcLabels := make(map[string]string)
+
+	if labels != nil {
+		for k, v := range *labels {
+			if strings.HasPrefix(k, nlbServiceLabelPrefix) {
+				svcLabels[k] = v
+			}
+		}
+	}
+
+	return svcLabels
+}
+
 func init() {
 	nlbCmd.AddCommand(nlbServiceCmd)
 }
+
+// nlbServiceAddCommand returns the "exo nlb service add" command re-creating
+// the Network Load Balancer service svc with the specified Instance Pool and
+// labels, e.g. to report the settings of a service that has been deleted.
+func nlbServiceAddCommand(
+	zone string,
+	nlb *egoscale.NetworkLoadBalancer,
+	svc *egoscale.NetworkLoadBalancerService,
+	instancePoolID string,
+	labels map[string]string,
+) string {
+	args := []string{"exo", "nlb", "service", "add", "--zone", zone, *nlb.ID, defaultString(svc.Name, "")}
+
+	flag := func(name, value string) {
+		if value != "" {
+			args = append(args, "--"+name, value)
+		}
+	}
+
+	flag("instance-pool", instancePoolID)
+	flag("description", defaultString(svc.Description, ""))
+	if svc.Port != nil {
+		flag("port", fmt.Sprint(*svc.Port))
+	}
+	if svc.TargetPort != nil {
+		flag("target-port", fmt.Sprint(*svc.TargetPort))
+	}
+	flag("protocol", defaultString(svc.Protocol, ""))
+	flag("strategy", defaultString(svc.Strategy, ""))
+
+	if hc := svc.Healthcheck; hc != nil {
+		flag("healthcheck-mode", defaultString(hc.Mode, ""))
+		if hc.Port != nil {
+			flag("healthcheck-port", fmt.Sprint(*hc.Port))
+		}
+		if hc.Interval != nil {
+			flag("healthcheck-interval", fmt.Sprint(int64(hc.Interval.Seconds())))
+		}
+		if hc.Timeout != nil {
+			flag("healthcheck-timeout", fmt.Sprint(int64(hc.Timeout.Seconds())))
+		}
+		if hc.Retries != nil {
+			flag("healthcheck-retries", fmt.Sprint(*hc.Retries))
+		}
+		flag("healthcheck-uri", defaultString(hc.URI, ""))
+		flag("healthcheck-tls-sni", defaultString(hc.TLSSNI, ""))
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		flag("label", k+"="+labels[k])
+	}
+
+	for i, arg := range args {
+		args[i] = shellQuote(arg)
+	}
+
+	return strings.Join(args, " ")
+}
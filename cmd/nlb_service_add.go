@@ -17,36 +17,52 @@ type nlbServiceAddCmd struct {
 	NetworkLoadBalancer string `cli-arg:"#" cli-usage:"LOAD-BALANCER-NAME|ID"`
 	Name                string `cli-arg:"#" cli-usage:"SERVICE-NAME"`
 
-	Description         string `cli-usage:"service description"`
-	HealthcheckInterval int64  `cli-usage:"service health checking interval in seconds"`
-	HealthcheckMode     string `cli-usage:"service health checking mode (tcp|http|https)"`
-	HealthcheckPort     int64  `cli-usage:"service health checking port (defaults to target port)"`
-	HealthcheckRetries  int64  `cli-usage:"service health checking retries"`
-	HealthcheckTLSSNI   string `cli-flag:"healthcheck-tls-sni" cli-usage:"service health checking server name to present with SNI in https mode"`
-	HealthcheckTimeout  int64  `cli-usage:"service health checking timeout in seconds"`
-	HealthcheckURI      string `cli-usage:"service health checking URI (required in http(s) mode)"`
-	InstancePool        string `cli-usage:"name or ID of the Instance Pool to forward traffic to"`
-	Port                int64  `cli-usage:"service port"`
-	Protocol            string `cli-usage:"service network protocol (tcp|udp)"`
-	Strategy            string `cli-usage:"load balancing strategy (round-robin|source-hash)"`
-	TargetPort          int64  `cli-usage:"port to forward traffic to on target instances (defaults to service port)"`
-	Zone                string `cli-short:"z" cli-usage:"Network Load Balancer zone"`
+	Description         string            `cli-usage:"service description"`
+	HealthcheckInterval int64             `cli-usage:"service health checking interval in seconds"`
+	HealthcheckMode     string            `cli-usage:"service health checking mode (tcp|http|https)"`
+	HealthcheckPort     int64             `cli-usage:"service health checking port (defaults to target port)"`
+	HealthcheckRetries  int64             `cli-usage:"service health checking retries"`
+	HealthcheckTLSSNI   string            `cli-flag:"healthcheck-tls-sni" cli-usage:"service health checking server name to present with SNI in https mode"`
+	HealthcheckTimeout  int64             `cli-usage:"service health checking timeout in seconds"`
+	HealthcheckURI      string            `cli-usage:"service health checking URI (required in http(s) mode)"`
+	InstancePool        string            `cli-usage:"name or ID of the Instance Pool to forward traffic to"`
+	Labels              map[string]string `cli-flag:"label" cli-usage:"service label (format: key=value)"`
+	Port                int64             `cli-usage:"service port"`
+	Protocol            string            `cli-usage:"service network protocol (tcp|udp)"`
+	Strategy            string            `cli-usage:"load balancing strategy (round-robin|source-hash)"`
+	TargetPort          int64             `cli-usage:"port to forward traffic to on target instances (defaults to service port)"`
+	Zone                string            `cli-short:"z" cli-usage:"Network Load Balancer zone"`
 }
 
 func (c *nlbServiceAddCmd) cmdAliases() []string { return nil }
 
 func (c *nlbServiceAddCmd) cmdShort() string { return "Add a service to a Network Load Balancer" }
 
+func (c *nlbServiceAddCmd) cmdDryRunSupported() {}
+
 func (c *nlbServiceAddCmd) cmdLong() string {
 	return fmt.Sprintf(`This command adds a service to a Network Load Balancer.
 
+Note: Network Load Balancer services labels are stored in the Network Load
+Balancer labels, using keys formatted as "svc.<SERVICE-ID>.<KEY>".
+
 Supported output template annotations: %s`,
 		strings.Join(outputterTemplateAnnotations(&nlbServiceShowOutput{}), ", "))
 }
 
 func (c *nlbServiceAddCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	// The service labels are set by updating the Network Load Balancer once
+	// the service has been added.
+	if gNoWait && len(c.Labels) > 0 {
+		return errNoWaitUnsupported(fmt.Sprintf("with the --%s flag", mustCLICommandFlagName(c, &c.Labels)))
+	}
+
+	return nil
 }
 
 func (c *nlbServiceAddCmd) cmdRun(_ *cobra.Command, _ []string) error {
@@ -115,8 +131,20 @@ func (c *nlbServiceAddCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	}
 	service.InstancePoolID = instancePool.ID
 
-	decorateAsyncOperation(fmt.Sprintf("Adding service %q...", c.Name), func() {
-		service, err = nlb.AddService(ctx, service)
+	if gDryRun {
+		return outputDryRun(service)
+	}
+
+	err = decorateAsyncOperation(fmt.Sprintf("Adding service %q...", c.Name), func() error {
+		if service, err = nlb.AddService(ctx, service); err != nil {
+			return err
+		}
+
+		if len(c.Labels) > 0 {
+			nlbSetServiceLabels(nlb, *service.ID, c.Labels)
+			err = cs.UpdateNetworkLoadBalancer(ctx, c.Zone, nlb)
+		}
+		return err
 	})
 	if err != nil {
 		return err
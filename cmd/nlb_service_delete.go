@@ -46,8 +46,23 @@ func (c *nlbServiceDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	for _, s := range nlb.Services {
 		if *s.ID == c.Service || *s.Name == c.Service {
 			s := s
-			decorateAsyncOperation(fmt.Sprintf("Deleting service %q...", c.Service), func() {
-				err = nlb.DeleteService(ctx, s)
+
+			// The service labels are removed by updating the Network Load
+			// Balancer once the service has been deleted.
+			if gNoWait && len(nlbServiceLabels(nlb, *s.ID)) > 0 {
+				return errNoWaitUnsupported("when deleting a service with labels")
+			}
+
+			err = decorateAsyncOperation(fmt.Sprintf("Deleting service %q...", c.Service), func() error {
+				if err = nlb.DeleteService(ctx, s); err != nil {
+					return err
+				}
+
+				if len(nlbServiceLabels(nlb, *s.ID)) > 0 {
+					nlbSetServiceLabels(nlb, *s.ID, nil)
+					err = cs.UpdateNetworkLoadBalancer(ctx, c.Zone, nlb)
+				}
+				return err
 			})
 			if err != nil {
 				return err
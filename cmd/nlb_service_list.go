@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type nlbServiceListItemOutput struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Protocol   string            `json:"protocol"`
+	Port       uint16            `json:"port"`
+	TargetPort uint16            `json:"target_port"`
+	State      string            `json:"state"`
+	Labels     map[string]string `json:"labels"`
+}
+
+type nlbServiceListOutput []nlbServiceListItemOutput
+
+func (o *nlbServiceListOutput) toJSON()  { outputJSON(o) }
+func (o *nlbServiceListOutput) toText()  { outputText(o) }
+func (o *nlbServiceListOutput) toTable() { outputTable(o) }
+
+type nlbServiceListCmd struct {
+	_ bool `cli-cmd:"list"`
+
+	NetworkLoadBalancer string `cli-arg:"#" cli-usage:"LOAD-BALANCER-NAME|ID"`
+
+	Selector map[string]string `cli-usage:"only list services having the specified label (format: key=value, can be repeated)"`
+	Zone     string            `cli-short:"z" cli-usage:"Network Load Balancer zone"`
+}
+
+func (c *nlbServiceListCmd) cmdAliases() []string { return gListAlias }
+
+func (c *nlbServiceListCmd) cmdShort() string { return "List Network Load Balancer services" }
+
+func (c *nlbServiceListCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists Network Load Balancer services.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&nlbServiceListItemOutput{}), ", "))
+}
+
+func (c *nlbServiceListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *nlbServiceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	nlb, err := cs.FindNetworkLoadBalancer(ctx, c.Zone, c.NetworkLoadBalancer)
+	if err != nil {
+		return err
+	}
+
+	out := make(nlbServiceListOutput, 0)
+	for _, svc := range nlb.Services {
+		labels := nlbServiceLabels(nlb, *svc.ID)
+		if !matchLabels(&labels, c.Selector) {
+			continue
+		}
+
+		out = append(out, nlbServiceListItemOutput{
+			ID:         *svc.ID,
+			Name:       *svc.Name,
+			Protocol:   *svc.Protocol,
+			Port:       *svc.Port,
+			TargetPort: *svc.TargetPort,
+			State:      *svc.State,
+			Labels:     labels,
+		})
+	}
+
+	return output(&out, nil)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(nlbServiceCmd, &nlbServiceListCmd{}))
+}
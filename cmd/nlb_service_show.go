@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -40,6 +41,7 @@ type nlbServiceShowOutput struct {
 	Healthcheck       nlbServiceHealthcheckShowOutput `json:"healthcheck"`
 	HealthcheckStatus []nlbServerStatusShowOutput     `json:"healthcheck_status"`
 	State             string                          `json:"state"`
+	Labels            map[string]string               `json:"labels"`
 }
 
 func (o *nlbServiceShowOutput) toJSON() { outputJSON(o) }
@@ -85,6 +87,19 @@ func (o *nlbServiceShowOutput) toTable() {
 		return "n/a"
 	}()})
 	t.Append([]string{"State", o.State})
+	t.Append([]string{"Labels", func() string {
+		if len(o.Labels) == 0 {
+			return "n/a"
+		}
+
+		labels := make([]string, 0, len(o.Labels))
+		for k, v := range o.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+
+		return strings.Join(labels, "\n")
+	}()})
 }
 
 type nlbServiceShowCmd struct {
@@ -146,6 +161,7 @@ func showNLBService(zone, nlbRef, svcRef string) (outputter, error) {
 		TargetPort:     *svc.TargetPort,
 		Strategy:       *svc.Strategy,
 		State:          *svc.State,
+		Labels:         nlbServiceLabels(nlb, *svc.ID),
 
 		Healthcheck: nlbServiceHealthcheckShowOutput{
 			Mode:     *svc.Healthcheck.Mode,
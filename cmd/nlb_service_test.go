@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nlbServiceLabels(t *testing.T) {
+	nlb := &egoscale.NetworkLoadBalancer{Labels: &map[string]string{
+		"env":              "prod",
+		"svc.s1.role":      "web",
+		"svc.s1.tier":      "frontend",
+		"svc.s2.role":      "api",
+		"svc.s10.role":     "admin",
+		"svc.s1.sub.key":   "nested",
+		"owner.svc.s1.foo": "bar",
+	}}
+
+	require.Equal(t, map[string]string{"role": "web", "tier": "frontend", "sub.key": "nested"},
+		nlbServiceLabels(nlb, "s1"))
+	require.Empty(t, nlbServiceLabels(nlb, "s3"))
+	require.Empty(t, nlbServiceLabels(&egoscale.NetworkLoadBalancer{}, "s1"))
+
+	require.Equal(t, map[string]string{"env": "prod", "owner.svc.s1.foo": "bar"}, nlbOwnLabels(nlb.Labels))
+	require.Len(t, nlbServicesLabels(nlb.Labels), 5)
+
+	// Setting a service labels replaces all its existing labels, leaving
+	// the other services and Network Load Balancer labels untouched.
+	nlbSetServiceLabels(nlb, "s1", map[string]string{"role": "www"})
+	require.Equal(t, map[string]string{
+		"env":              "prod",
+		"svc.s1.role":      "www",
+		"svc.s2.role":      "api",
+		"svc.s10.role":     "admin",
+		"owner.svc.s1.foo": "bar",
+	}, *nlb.Labels)
+
+	nlbSetServiceLabels(nlb, "s2", nil)
+	require.Empty(t, nlbServiceLabels(nlb, "s2"))
+	require.Equal(t, map[string]string{"role": "admin"}, nlbServiceLabels(nlb, "s10"))
+}
+
+func Test_nlbServiceAddCommand(t *testing.T) {
+	var (
+		str      = func(s string) *string { return &s }
+		port     = func(v uint16) *uint16 { return &v }
+		retries  = int64(2)
+		interval = 10 * time.Second
+		timeout  = 5 * time.Second
+	)
+
+	nlb := &egoscale.NetworkLoadBalancer{ID: str("nlb1"), Name: str("web-nlb")}
+	svc := &egoscale.NetworkLoadBalancerService{
+		Description: str("Web traffic"),
+		Healthcheck: &egoscale.NetworkLoadBalancerServiceHealthcheck{
+			Interval: &interval,
+			Mode:     str("https"),
+			Port:     port(8443),
+			Retries:  &retries,
+			TLSSNI:   str("example.net"),
+			Timeout:  &timeout,
+			URI:      str("/health"),
+		},
+		Name:       str("https"),
+		Port:       port(443),
+		Protocol:   str("tcp"),
+		Strategy:   str("source-hash"),
+		TargetPort: port(8443),
+	}
+
+	require.Equal(t, "exo nlb service add --zone ch-gva-2 nlb1 https --instance-pool pool1 "+
+		"--description 'Web traffic' --port 443 --target-port 8443 --protocol tcp --strategy source-hash "+
+		"--healthcheck-mode https --healthcheck-port 8443 --healthcheck-interval 10 --healthcheck-timeout 5 "+
+		"--healthcheck-retries 2 --healthcheck-uri /health --healthcheck-tls-sni example.net "+
+		"--label env=prod --label 'owner=web team'",
+		nlbServiceAddCommand("ch-gva-2", nlb, svc, "pool1", map[string]string{"owner": "web team", "env": "prod"}))
+
+	require.Equal(t, "exo nlb service add --zone ch-gva-2 nlb1 http --instance-pool pool1",
+		nlbServiceAddCommand("ch-gva-2", nlb, &egoscale.NetworkLoadBalancerService{Name: str("http")}, "pool1", nil))
+}
@@ -17,20 +17,22 @@ type nlbServiceUpdateCmd struct {
 	NetworkLoadBalancer string `cli-arg:"#" cli-usage:"LOAD-BALANCER-NAME|ID"`
 	Service             string `cli-arg:"#" cli-usage:"SERVICE-NAME|ID"`
 
-	Description         string `cli-usage:"service description"`
-	HealthcheckInterval int64  `cli-usage:"service health checking interval in seconds"`
-	HealthcheckMode     string `cli-usage:"service health checking mode (tcp|http|https)"`
-	HealthcheckPort     int64  `cli-usage:"service health checking port"`
-	HealthcheckRetries  int64  `cli-usage:"service health checking retries"`
-	HealthcheckTLSSNI   string `cli-flag:"healthcheck-tls-sni" cli-usage:"service health checking server name to present with SNI in https mode"`
-	HealthcheckTimeout  int64  `cli-usage:"service health checking timeout in seconds"`
-	HealthcheckURI      string `cli-usage:"service health checking URI (required in http(s) mode)"`
-	Name                string `cli-usage:"service name"`
-	Port                int64  `cli-usage:"service port"`
-	Protocol            string `cli-usage:"service network protocol (tcp|udp)"`
-	Strategy            string `cli-usage:"load balancing strategy (round-robin|source-hash)"`
-	TargetPort          int64  `cli-usage:"port to forward traffic to on target instances"`
-	Zone                string `cli-short:"z" cli-usage:"Network Load Balancer zone"`
+	Description         string            `cli-usage:"service description"`
+	DryRun              bool              `cli-usage:"print the changes that would be applied without updating the service"`
+	HealthcheckInterval int64             `cli-usage:"service health checking interval in seconds"`
+	HealthcheckMode     string            `cli-usage:"service health checking mode (tcp|http|https)"`
+	HealthcheckPort     int64             `cli-usage:"service health checking port"`
+	HealthcheckRetries  int64             `cli-usage:"service health checking retries"`
+	HealthcheckTLSSNI   string            `cli-flag:"healthcheck-tls-sni" cli-usage:"service health checking server name to present with SNI in https mode"`
+	HealthcheckTimeout  int64             `cli-usage:"service health checking timeout in seconds"`
+	HealthcheckURI      string            `cli-usage:"service health checking URI (required in http(s) mode)"`
+	Labels              map[string]string `cli-flag:"label" cli-usage:"service label (format: key=value)"`
+	Name                string            `cli-usage:"service name"`
+	Port                int64             `cli-usage:"service port"`
+	Protocol            string            `cli-usage:"service network protocol (tcp|udp)"`
+	Strategy            string            `cli-usage:"load balancing strategy (round-robin|source-hash)"`
+	TargetPort          int64             `cli-usage:"port to forward traffic to on target instances"`
+	Zone                string            `cli-short:"z" cli-usage:"Network Load Balancer zone"`
 }
 
 func (c *nlbServiceUpdateCmd) cmdAliases() []string { return nil }
@@ -40,6 +42,9 @@ func (c *nlbServiceUpdateCmd) cmdShort() string { return "Update a Network Load
 func (c *nlbServiceUpdateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command updates a Network Load Balancer service.
 
+Using the "--dry-run" flag, the changes to be applied to the service are
+printed instead of being performed.
+
 Supported output template annotations: %s`,
 		strings.Join(outputterTemplateAnnotations(&nlbServiceShowOutput{}), ", "))
 }
@@ -72,6 +77,10 @@ func (c *nlbServiceUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		return errors.New("service not found")
 	}
 
+	current := diffNormalize(service)
+	currentLabels := nlbServiceLabels(nlb, *service.ID)
+	labels := currentLabels
+
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Description)) {
 		service.Description = &c.Description
 		updated = true
@@ -115,6 +124,10 @@ func (c *nlbServiceUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		updated = true
 	}
 
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Labels)) {
+		labels = c.Labels
+	}
+
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Name)) {
 		service.Name = &c.Name
 		updated = true
@@ -142,12 +155,33 @@ func (c *nlbServiceUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		updated = true
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Updating service %q...", c.Service), func() {
+	if c.DryRun {
+		changes := computeDiff(current, service, diffOptions{})
+		changes = append(changes, computeDiff(
+			map[string]interface{}{"Labels": currentLabels},
+			map[string]interface{}{"Labels": labels},
+			diffOptions{},
+		)...)
+		return output(&changes, nil)
+	}
+
+	if gNoWait && updated && cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Labels)) {
+		return errNoWaitUnsupported(fmt.Sprintf("when updating both the service and its labels (--%s flag)",
+			mustCLICommandFlagName(c, &c.Labels)))
+	}
+
+	err = decorateAsyncOperation(fmt.Sprintf("Updating service %q...", c.Service), func() error {
 		if updated {
 			if err = nlb.UpdateService(ctx, service); err != nil {
-				return
+				return err
 			}
 		}
+
+		if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Labels)) {
+			nlbSetServiceLabels(nlb, *service.ID, labels)
+			err = cs.UpdateNetworkLoadBalancer(ctx, c.Zone, nlb)
+		}
+		return err
 	})
 	if err != nil {
 		return err
@@ -81,7 +81,7 @@ func (o *nlbShowOutput) toTable() {
 type nlbShowCmd struct {
 	_ bool `cli-cmd:"show"`
 
-	NetworkLoadBalancer string `cli-arg:"#" cli-usage:"NAME|ID"`
+	NetworkLoadBalancer string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"nlb"`
 
 	Zone string `cli-short:"z" cli-usage:"Network Load Balancer zone"`
 }
@@ -131,12 +131,7 @@ func showNLB(zone, ref string) (outputter, error) {
 		IPAddress:    nlb.IPAddress.String(),
 		State:        *nlb.State,
 		Services:     svcOut,
-		Labels: func() (v map[string]string) {
-			if nlb.Labels != nil {
-				v = *nlb.Labels
-			}
-			return
-		}(),
+		Labels:       nlbOwnLabels(nlb.Labels),
 	}
 
 	return &out, nil
@@ -11,7 +11,7 @@ import (
 type nlbUpdateCmd struct {
 	_ bool `cli-cmd:"update"`
 
-	NetworkLoadBalancer string `cli-arg:"#" cli-usage:"NAME|ID"`
+	NetworkLoadBalancer string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"nlb"`
 
 	Description string            `cli-usage:"Network Load Balancer description"`
 	Labels      map[string]string `cli-flag:"label" cli-usage:"Network Load Balancer label (format: key=value)"`
@@ -52,7 +52,13 @@ func (c *nlbUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Labels)) {
-		nlb.Labels = &c.Labels
+		// Preserve the labels storing the services labels, see
+		// nlbServiceLabelPrefix.
+		labels := nlbServicesLabels(nlb.Labels)
+		for k, v := range c.Labels {
+			labels[k] = v
+		}
+		nlb.Labels = &labels
 		updated = true
 	}
 
@@ -62,12 +68,10 @@ func (c *nlbUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	if updated {
-		decorateAsyncOperation(
+		err = decorateAsyncOperation(
 			fmt.Sprintf("Updating Network Load Balancer %q...", c.NetworkLoadBalancer),
-			func() {
-				if err = cs.UpdateNetworkLoadBalancer(ctx, c.Zone, nlb); err != nil {
-					return
-				}
+			func() error {
+				return cs.UpdateNetworkLoadBalancer(ctx, c.Zone, nlb)
 			})
 		if err != nil {
 			return err
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -23,6 +24,8 @@ import (
 // also use struct tags to modify the output logic:
 //   * output:"-" is similar to package encoding/json, i.e. that a field with
 //     this tag will not be displayed
+//   * output:"wide" marks a field to be displayed in table format only when
+//     the "wide" output format is requested, other formats being unaffected
 //   * outputLabel:"..." overrides the string displayed as label, which by
 //     default is the field's CamelCase named split with spaces
 type outputter interface {
@@ -42,18 +45,41 @@ func output(o outputter, err error) error {
 		return nil
 	}
 
+	if gOutputQuery != "" {
+		return outputQuery(o)
+	}
+
+	if err := resolveOutputTemplate(); err != nil {
+		return err
+	}
+
 	if gOutputTemplate != "" {
 		o.toText()
 		return nil
 	}
 
+	if err := checkOutputColumns(o, gOutputFormat); err != nil {
+		return err
+	}
+
 	switch gOutputFormat {
 	case "json":
 		o.toJSON()
 
+	case "ndjson":
+		outputNDJSON(o)
+
+	case "yaml":
+		outputYAML(o)
+
+	case "csv":
+		outputCSV(o)
+
 	case "text":
 		o.toText()
 
+	// The "wide" format is a table variant including additional columns,
+	// see outputTableFieldSkipped().
 	default:
 		o.toTable()
 	}
@@ -108,9 +134,14 @@ func outputText(o interface{}) {
 		tpl = strings.Join(tplFields, "\t")
 	}
 
-	t, err := template.New("out").Parse(tpl)
+	// Reported along with template errors, as the template may have been
+	// written for another command.
+	annotations := strings.Join(outputterTemplateAnnotations(o), ", ")
+
+	t, err := template.New("out").Funcs(outputTemplateFuncs).Parse(tpl)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: unable to encode output in plaintext using template: %s\n", err)
+		fmt.Fprintf(os.Stderr, "error: unable to encode output in plaintext using template: %s "+
+			"(supported annotations: %s)\n", err, annotations)
 		os.Exit(1)
 	}
 
@@ -119,7 +150,8 @@ func outputText(o interface{}) {
 	if v := reflect.ValueOf(o); reflect.Indirect(v).Kind() == reflect.Slice {
 		for i := 0; i < reflect.Indirect(v).Len(); i++ {
 			if err := t.Execute(os.Stdout, reflect.Indirect(v).Index(i).Interface()); err != nil {
-				fmt.Fprintf(os.Stderr, "error: unable to encode output using template: %s\n", err)
+				fmt.Fprintf(os.Stderr, "error: unable to encode output using template: %s "+
+					"(supported annotations: %s)\n", err, annotations)
 				os.Exit(1)
 			}
 			fmt.Println()
@@ -128,7 +160,8 @@ func outputText(o interface{}) {
 	}
 
 	if err := t.Execute(os.Stdout, o); err != nil {
-		fmt.Fprintf(os.Stderr, "error: unable to encode output using template: %s\n", err)
+		fmt.Fprintf(os.Stderr, "error: unable to encode output using template: %s "+
+			"(supported annotations: %s)\n", err, annotations)
 		os.Exit(1)
 	}
 }
@@ -155,11 +188,8 @@ func outputTable(o interface{}) {
 	// If the field has an `outputLabel` tag, use its value to override the header label.
 	headers := make([]string, 0)
 	for i := 0; i < t.NumField(); i++ {
-		// Check if the field has to be skipped.
-		if l, ok := t.Field(i).Tag.Lookup("output"); ok {
-			if l == "-" {
-				continue
-			}
+		if outputTableFieldSkipped(t.Field(i)) {
+			continue
 		}
 
 		label := strings.Join(camelcase.Split(t.Field(i).Name), " ")
@@ -180,11 +210,8 @@ func outputTable(o interface{}) {
 
 			for j := 0; j < item.NumField(); j++ {
 				field := item.Field(j)
-				// Check if the field has to be skipped.
-				if l, ok := item.Type().Field(j).Tag.Lookup("output"); ok {
-					if l == "-" {
-						continue
-					}
+				if outputTableFieldSkipped(item.Type().Field(j)) {
+					continue
 				}
 
 				switch field.Kind() {
@@ -237,11 +264,8 @@ func outputTable(o interface{}) {
 	}
 
 	for i := 0; i < t.NumField(); i++ {
-		// Check if the field has to be skipped.
-		if l, ok := t.Field(i).Tag.Lookup("output"); ok {
-			if l == "-" {
-				continue
-			}
+		if outputTableFieldSkipped(t.Field(i)) {
+			continue
 		}
 
 		label := strings.Join(camelcase.Split(t.Field(i).Name), " ")
@@ -290,15 +314,57 @@ func outputTable(o interface{}) {
 	tab.Render()
 }
 
+// outputTableFieldSkipped returns true if the struct field f must not be
+// displayed in table format, i.e. if it is tagged with output:"-", or with
+// output:"wide" and the "wide" output format has not been requested. If
+// columns have been requested using the "--output-columns" flag, only those
+// are displayed (including fields tagged with output:"wide").
+func outputTableFieldSkipped(f reflect.StructField) bool {
+	if f.Tag.Get("output") == "-" {
+		return true
+	}
+
+	if len(gOutputColumns) > 0 {
+		return !outputColumnSelected(outputColumnName(f), gOutputColumns)
+	}
+
+	return f.Tag.Get("output") == "wide" && gOutputFormat != "wide"
+}
+
 // decorateAsyncOperation is a cosmetic helper intended for wrapping long
 // asynchronous operations, outputting progress feedback to the user's
-// terminal.
-func decorateAsyncOperation(message string, fn func()) {
+// terminal, and returns the error returned by fn. If the CLI is interrupted
+// (e.g. using Ctrl-C) while waiting for the operation to complete, the
+// operations in progress are reported to the user and the CLI exits. In
+// "--no-wait" mode, a noWaitError is returned as soon as the operation has
+// been submitted to the API. If the output format is JSON, the progress is
+// reported as JSON events instead (see asyncProgressEvent).
+func decorateAsyncOperation(message string, fn func() error) error {
+	// Don't start new operations once the CLI has been interrupted.
+	if gContext.Err() != nil {
+		if commandTimedOut() {
+			return errCommandTimedOut()
+		}
+		exitInterrupted()
+	}
+	defer suspendCommandTimeout()()
+
+	events := asyncProgressEventsEnabled()
+
 	p := mpb.New(
 		mpb.WithWidth(1),
-		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool { return gQuiet }),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool {
+			return events || decorationsDisabled() || machineReadableOutputFormat()
+		}),
 	)
 
+	// Without decorations (or with a machine-readable output format) the
+	// progress feedback is reduced to the message, reported on the standard
+	// error not to alter the command output.
+	if (gNoColor || machineReadableOutputFormat()) && !gQuiet && !events {
+		fmt.Fprintln(os.Stderr, message)
+	}
+
 	spinner := p.AddSpinner(
 		1,
 		mpb.SpinnerOnLeft,
@@ -309,16 +375,62 @@ func decorateAsyncOperation(message string, fn func()) {
 		mpb.BarOnComplete("✔"),
 	)
 
-	done := make(chan struct{})
-	defer close(done)
+	id := asyncOperationStarted(message)
+	submitted, timeout := asyncOperationSubmitted(), asyncWaitTimeout()
+	if events {
+		asyncProgressStarted(message)
+	}
+
+	var err error
+	done := make(chan struct{}, 1)
 	go func(doneCh chan struct{}) {
-		fn()
+		err = fn()
 		doneCh <- struct{}{}
 	}(done)
 
-	<-done
+	select {
+	case <-done:
+	case <-gContext.Done():
+	case resID := <-submitted:
+		spinner.Increment(1)
+		p.Wait()
+		if events {
+			asyncProgressEnded(asyncProgressSubmitted, nil)
+		}
+		return noWaitError{id: resID}
+	case <-timeout:
+		spinner.Abort(false)
+		p.Wait()
+		if events {
+			asyncProgressEnded(asyncProgressFailure, errors.New("timed out"))
+		}
+		exitWaitTimeout(message)
+	}
+
+	// If the operation returned because of the interruption, we don't know
+	// whether it actually completed on the API side.
+	if gContext.Err() != nil {
+		spinner.Abort(false)
+		p.Wait()
+		if events {
+			asyncProgressEnded(asyncProgressFailure, errors.New("interrupted"))
+		}
+		exitInterrupted()
+	}
+
+	asyncOperationCompleted(id)
 	spinner.Increment(1)
 	p.Wait()
+
+	if events {
+		state := asyncProgressSuccess
+		if err != nil {
+			state = asyncProgressFailure
+		}
+		asyncProgressEnded(state, err)
+	}
+
+	return err
 }
 
 // proxyWriterAt is a variant of the internal mpb.proxyWriterTo struct,
@@ -345,18 +457,22 @@ func (prox *proxyWriterAt) WriteAt(p []byte, off int64) (n int, err error) {
 	return n, err
 }
 
-func init() {
-	RootCmd.AddCommand(&cobra.Command{
-		Use:   "output",
-		Short: "Output formatting usage",
-		Long: `The exo CLI tool allows you to customize its commands output using different
+var outputCmd = &cobra.Command{
+	Use:   "output",
+	Short: "Output formatting usage",
+	Long: `The exo CLI tool allows you to customize its commands output using different
 formats such as table, JSON or text template using the "--output-format" flag
 ("-O" in short version).
 
-By default the "table" format is applied, best suited for human reading. In
-case you need to process a command output with other CLI tools, for example
-in a shell script, you can either use the "json" output format (e.g. to be
-piped into jq):
+By default the "table" format is applied, best suited for human reading.
+Some commands support additional columns that are only displayed using the
+"wide" table format variant:
+
+	$ exo compute instance list -O wide
+
+In case you need to process a command output with other CLI tools, for
+example in a shell script, you can either use the "json" output format (e.g.
+to be piped into jq):
 
 	$ exo config list -O json | jq .
 	[
@@ -370,6 +486,56 @@ piped into jq):
 	  }
 	]
 
+When using the "json" (or "ndjson") format, command errors are also reported
+as a JSON object on the standard error, the CLI exiting with a non-zero
+status:
+
+	$ exo config show bob -O json
+	{"error":"account \"bob\" was not found","command":"config show"}
+
+The "ndjson" format prints list commands output as newline-delimited JSON,
+i.e. one JSON object per line:
+
+	$ exo config list -O ndjson
+	{"name":"alice","default":true}
+	{"name":"bob","default":false}
+
+The "yaml" format prints a command's output in YAML, using the same field
+names as the "json" format:
+
+	$ exo config list -O yaml
+	- name: alice
+	  default: true
+	- name: bob
+	  default: false
+
+The "csv" format prints a command's output in CSV (RFC 4180), e.g. to be
+imported into a spreadsheet. The header row contains the same field names as
+the "json" format, and can be disabled using the "--no-header" flag. List
+values are joined using the separator set with the "--csv-separator" flag
+(default: ";"), and maps are flattened as "key=value" pairs:
+
+	$ exo config list -O csv
+	name,default
+	alice,true
+	bob,false
+
+The "--output-columns" flag restricts the columns displayed in "table",
+"wide" and "csv" formats, identified by the same names as the fields of the
+"json" format (dashes and underscores being interchangeable):
+
+	$ exo compute instance list --output-columns name,ip-address
+
+The "--query" flag applies a JMESPath expression (see https://jmespath.org)
+to the JSON representation of a command's output, removing the need for
+external tools such as jq. The result is printed in JSON format (or NDJSON
+when using "-O ndjson", or YAML when using "-O yaml"):
+
+	$ exo config list --query '[?default].name'
+	[
+	  "alice"
+	]
+
 The "text" format prints a command's output in plain text according to a
 user-defined formatting template provided with the "--output-template" flag:
 
@@ -385,6 +551,30 @@ account:
 	alice*
 	bob
 
+Long templates can be read from a file using the "--output-template-file"
+flag ("-" reading the template from the standard input), supporting the same
+annotations as the "--output-template" flag, or saved under a name using the "exo output template save" command and
+referred to using the "name:" prefix:
+
+	$ exo output template save accounts '{{ .Name }}{{ if .Default }}*{{ end }}'
+	$ exo config list --output-template name:accounts
+
+In addition to the Go templating built-in functions, the following functions
+are available:
+
+	join SEPARATOR LIST  Join the items of a list with a separator
+	upper STRING         Convert a string to upper case
+	lower STRING         Convert a string to lower case
+	trunc LENGTH STRING  Truncate a string (keeping the end if LENGTH is negative)
+	date LAYOUT DATE     Format a date using a Go time layout (e.g. "2006-01-02")
+	default DEFAULT V    Use a default value if V is empty (e.g. a nil pointer)
+
+Like in the Sprig library, the value is the last argument of these functions
+so that they can be used in pipelines:
+
+	$ exo compute instance show my-instance \
+	    --output-template '{{ .SecurityGroups | join "," }} {{ .IPv6Address | default "-" }}'
+
 If no output template is provided, the default is to print all fields
 separated by a tabulation (\t) character so the output can be parsed by a
 delimiter-based processing tool such as cut(1) or AWK.
@@ -398,6 +588,9 @@ return character.
 
 For the complete Go templating reference, see https://godoc.org/text/template
 `,
-	},
-	)
+}
+
+func init() {
+	outputCmd.AddCommand(outputTemplateCmd)
+	RootCmd.AddCommand(outputCmd)
 }
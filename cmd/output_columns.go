@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// gOutputColumns are the columns to display in table and CSV output
+// formats, identified by the JSON name of the outputter fields.
+var gOutputColumns []string
+
+// outputColumnName returns the name identifying the struct field f as an
+// output column, i.e. its JSON name, or an empty string if the field is not
+// part of the JSON representation of the outputter.
+func outputColumnName(f reflect.StructField) string {
+	if f.PkgPath != "" { // Unexported field
+		return ""
+	}
+
+	if tag, ok := f.Tag.Lookup("json"); ok {
+		switch name := strings.Split(tag, ",")[0]; name {
+		case "-":
+			return ""
+		case "":
+		default:
+			return name
+		}
+	}
+
+	return f.Name
+}
+
+// outputColumnSelected returns true if the column name is part of the
+// columns requested, an empty list meaning all columns. Dashes and
+// underscores are interchangeable in column names (e.g. "ip-address" matches
+// the "ip_address" column).
+func outputColumnSelected(name string, columns []string) bool {
+	if len(columns) == 0 {
+		return true
+	}
+
+	normalize := func(s string) string {
+		return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "-", "_")
+	}
+
+	for _, c := range columns {
+		if normalize(c) == normalize(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkOutputColumns returns an error listing the valid columns if the
+// columns requested using the "--output-columns" flag don't all match a
+// column of the outputter o in the specified output format. Output formats
+// not supporting columns selection are ignored.
+func checkOutputColumns(o interface{}, format string) error {
+	if len(gOutputColumns) == 0 {
+		return nil
+	}
+
+	switch format {
+	case "json", "ndjson", "yaml", "text":
+		return nil
+	case "csv":
+		if c, ok := o.(csvRowsOutputter); ok {
+			o = c.csvRows()
+		}
+	}
+
+	t := reflect.Indirect(reflect.ValueOf(o)).Type()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	valid := make([]string, 0)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		// Fields tagged with output:"-" are only rendered in CSV format.
+		if format != "csv" && f.Tag.Get("output") == "-" {
+			continue
+		}
+
+		if name := outputColumnName(f); name != "" {
+			valid = append(valid, name)
+		}
+	}
+
+	for _, c := range gOutputColumns {
+		found := false
+		for _, name := range valid {
+			if outputColumnSelected(name, []string{c}) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("unknown output column %q (valid columns: %s)", c, strings.Join(valid, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkOutputColumns(t *testing.T) {
+	defer func() { gOutputColumns = nil }()
+
+	out := &privnetListDetailsOutput{}
+
+	gOutputColumns = nil
+	require.NoError(t, checkOutputColumns(out, "table"))
+
+	gOutputColumns = []string{"name", "num-instances"}
+	require.NoError(t, checkOutputColumns(out, "table"))
+
+	gOutputColumns = []string{"name", "instances"}
+	require.EqualError(t, checkOutputColumns(out, "table"),
+		`unknown output column "instances" (valid columns: id, name, zone, dhcp, num_instances)`)
+	require.NoError(t, checkOutputColumns(out, "csv"))
+	require.NoError(t, checkOutputColumns(out, "json"))
+}
+
+func Test_outputTableFieldSkipped(t *testing.T) {
+	defer func() { gOutputColumns = nil }()
+
+	typ := reflect.TypeOf(instanceListItemOutput{})
+	field := func(name string) reflect.StructField {
+		f, _ := typ.FieldByName(name)
+		return f
+	}
+
+	gOutputColumns = nil
+	require.False(t, outputTableFieldSkipped(field("IPAddress")))
+	require.True(t, outputTableFieldSkipped(field("Template")))
+
+	gOutputColumns = []string{"name", "template"}
+	require.True(t, outputTableFieldSkipped(field("IPAddress")))
+	require.False(t, outputTableFieldSkipped(field("Name")))
+	require.False(t, outputTableFieldSkipped(field("Template")))
+}
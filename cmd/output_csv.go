@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const defaultOutputCSVSeparator = ";"
+
+var (
+	// gOutputCSVSeparator is the separator used to join list values in CSV
+	// output format.
+	gOutputCSVSeparator string
+
+	// gOutputNoHeader disables the header row in CSV output format.
+	gOutputNoHeader bool
+)
+
+// csvRowsOutputter is implemented by the outputters whose CSV rendering
+// differs from their structure, csvRows() returning the value to render as
+// CSV instead.
+type csvRowsOutputter interface {
+	csvRows() interface{}
+}
+
+// outputCSV prints a CSV (RFC 4180) rendering of o to the terminal.
+func outputCSV(o interface{}) {
+	if c, ok := o.(csvRowsOutputter); ok {
+		o = c.csvRows()
+	}
+
+	if err := writeCSV(os.Stdout, o, gOutputColumns, gOutputCSVSeparator, !gOutputNoHeader); err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to encode output to CSV: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeCSV writes a CSV rendering of o to w: if o is iterable (slice only)
+// each item is written as a row, otherwise o is written as a single row. The
+// columns are the struct fields named after their JSON tag (restricted to the
+// ones listed in columns if not empty), list values being joined with sep and
+// maps flattened as "key=value" pairs.
+func writeCSV(w io.Writer, o interface{}, columns []string, sep string, header bool) error {
+	v := reflect.Indirect(reflect.ValueOf(o))
+
+	rows := []reflect.Value{v}
+	if v.Kind() == reflect.Slice {
+		rows = make([]reflect.Value, v.Len())
+		for i := range rows {
+			rows[i] = reflect.Indirect(v.Index(i))
+		}
+	}
+
+	t := v.Type()
+	if v.Kind() == reflect.Slice {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported output type %s", t)
+	}
+
+	columns, fields := outputCSVColumns(t, columns)
+
+	cw := csv.NewWriter(w)
+
+	if header {
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(fields))
+		for i, f := range fields {
+			value, err := outputCSVValue(row.Field(f).Interface(), sep)
+			if err != nil {
+				return fmt.Errorf("%s: %w", columns[i], err)
+			}
+			record[i] = value
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// outputCSVColumns returns the CSV column names of the struct type t (i.e.
+// the fields JSON names) selected in the list of requested columns, and the
+// index of the corresponding fields.
+func outputCSVColumns(t reflect.Type, requested []string) ([]string, []int) {
+	columns := make([]string, 0, t.NumField())
+	fields := make([]int, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		name := outputColumnName(t.Field(i))
+		if name == "" || !outputColumnSelected(name, requested) {
+			continue
+		}
+
+		columns = append(columns, name)
+		fields = append(fields, i)
+	}
+
+	return columns, fields
+}
+
+// outputCSVValue returns the CSV representation of the value v, based on its
+// JSON encoding so that types implementing json.Marshaler are honored.
+func outputCSVValue(v interface{}, sep string) (string, error) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.UseNumber()
+
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		return "", err
+	}
+
+	return outputCSVFormat(data, sep), nil
+}
+
+func outputCSVFormat(data interface{}, sep string) string {
+	switch v := data.(type) {
+	case nil:
+		return ""
+
+	case string:
+		return v
+
+	case json.Number:
+		return v.String()
+
+	case bool:
+		return fmt.Sprint(v)
+
+	case []interface{}:
+		items := make([]string, len(v))
+		for i := range v {
+			items[i] = outputCSVFormat(v[i], sep)
+		}
+		return strings.Join(items, sep)
+
+	case map[string]interface{}:
+		items := make([]string, 0, len(v))
+		for k := range v {
+			items = append(items, k+"="+outputCSVFormat(v[k], sep))
+		}
+		sort.Strings(items)
+		return strings.Join(items, sep)
+
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeCSV(t *testing.T) {
+	type item struct {
+		Name           string             `json:"name"`
+		Size           int64              `json:"size"`
+		Labels         *map[string]string `json:"labels"`
+		SecurityGroups []string           `json:"security_groups"`
+		Hidden         string             `json:"-"`
+		internal       string
+	}
+
+	labels := map[string]string{"team": "web", "app": "1"}
+	out := []item{
+		{
+			Name:           "web, front",
+			Size:           10,
+			Labels:         &labels,
+			SecurityGroups: []string{"default", "ssh"},
+			Hidden:         "x",
+			internal:       "x",
+		},
+		{Name: "db\nprimary"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, &out, nil, ";", true))
+	require.Equal(t, `name,size,labels,security_groups
+"web, front",10,app=1;team=web,default;ssh
+"db
+primary",0,,
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeCSV(&buf, &out[0], nil, "|", false))
+	require.Equal(t, `"web, front",10,app=1|team=web,default|ssh
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeCSV(&buf, &out[0], []string{"security-groups", "name"}, ";", true))
+	require.Equal(t, `name,security_groups
+"web, front",default;ssh
+`, buf.String())
+
+	require.Error(t, writeCSV(&buf, []string{"a"}, nil, ";", true))
+}
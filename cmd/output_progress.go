@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// States of the asynchronous operations reported by progress events.
+const (
+	asyncProgressRunning   = "running"
+	asyncProgressSubmitted = "submitted"
+	asyncProgressSuccess   = "success"
+	asyncProgressFailure   = "failure"
+)
+
+// asyncProgressEvent represents a progress event of an asynchronous
+// operation performed using decorateAsyncOperation(). When the output format
+// is JSON, progress events are written to the standard error as JSON
+// documents (one per line) instead of rendering a spinner: a "running"
+// event is emitted when the operation starts and every time the state of the
+// underlying API operation changes, followed by a final "success",
+// "failure" (or "submitted" in "--no-wait" mode) event.
+type asyncProgressEvent struct {
+	Time           time.Time `json:"time"`
+	State          string    `json:"state"`
+	Message        string    `json:"message"`
+	OperationID    string    `json:"operation_id,omitempty"`
+	OperationState string    `json:"operation_state,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// asyncProgressEventsOutput is the writer progress events are written to.
+var asyncProgressEventsOutput io.Writer = os.Stderr
+
+// asyncProgress keeps track of the asynchronous operation in progress and
+// of the last known state of the API operations performed, in order to
+// report their state changes.
+var asyncProgress = struct {
+	sync.Mutex
+	message    string
+	operations map[string]string
+}{}
+
+// asyncProgressEventsEnabled returns true if the progress of the
+// asynchronous operations is to be reported as JSON events.
+func asyncProgressEventsEnabled() bool {
+	return gOutputFormat == "json" || gOutputFormat == "ndjson"
+}
+
+// machineReadableOutputFormat returns true if the output format is intended
+// to be parsed, in which case the progress feedback must not be written to
+// the standard output.
+func machineReadableOutputFormat() bool {
+	switch gOutputFormat {
+	case "json", "ndjson", "yaml", "csv", "text":
+		return true
+	}
+
+	return false
+}
+
+func emitAsyncProgressEvent(e asyncProgressEvent) {
+	e.Time = time.Now()
+	_ = json.NewEncoder(asyncProgressEventsOutput).Encode(e)
+}
+
+// asyncProgressStarted reports the start of the asynchronous operation
+// described by message.
+func asyncProgressStarted(message string) {
+	asyncProgress.Lock()
+	defer asyncProgress.Unlock()
+
+	asyncProgress.message = strings.TrimSuffix(message, "...")
+	asyncProgress.operations = make(map[string]string)
+	emitAsyncProgressEvent(asyncProgressEvent{State: asyncProgressRunning, Message: asyncProgress.message})
+}
+
+// asyncProgressEnded reports the end of the asynchronous operation in
+// progress in the specified state.
+func asyncProgressEnded(state string, err error) {
+	asyncProgress.Lock()
+	defer asyncProgress.Unlock()
+
+	e := asyncProgressEvent{State: state, Message: asyncProgress.message}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	emitAsyncProgressEvent(e)
+
+	asyncProgress.message = ""
+	asyncProgress.operations = nil
+}
+
+// recordAsyncOperationState inspects the response to an API request, and if
+// it reports the state of an API V2 asynchronous operation (either when
+// submitted or polled) while an asynchronous operation is in progress,
+// reports the state change as a progress event.
+func recordAsyncOperationState(r *http.Request, res *http.Response) {
+	if !asyncProgressEventsEnabled() || res == nil || res.StatusCode != http.StatusOK ||
+		(r.Method == http.MethodGet && !strings.Contains(r.URL.Path, "/operation/")) {
+		return
+	}
+
+	asyncProgress.Lock()
+	defer asyncProgress.Unlock()
+
+	if asyncProgress.operations == nil {
+		return
+	}
+
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	var op struct {
+		ID    string `json:"id"`
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(body, &op); err != nil || op.ID == "" || op.State == "" {
+		return
+	}
+
+	if asyncProgress.operations[op.ID] == op.State {
+		return
+	}
+	asyncProgress.operations[op.ID] = op.State
+
+	emitAsyncProgressEvent(asyncProgressEvent{
+		State:          asyncProgressRunning,
+		Message:        asyncProgress.message,
+		OperationID:    op.ID,
+		OperationState: op.State,
+	})
+}
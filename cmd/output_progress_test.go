@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_asyncProgressEvents(t *testing.T) {
+	savedOutput, savedFormat := asyncProgressEventsOutput, gOutputFormat
+	defer func() { asyncProgressEventsOutput, gOutputFormat = savedOutput, savedFormat }()
+
+	var buf bytes.Buffer
+	asyncProgressEventsOutput, gOutputFormat = &buf, "json"
+
+	operation := func(method, path, state string) {
+		r, err := http.NewRequest(method, "https://api.example.net/v2"+path, nil)
+		require.NoError(t, err)
+		recordAsyncOperationState(r, &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id":"op1","state":"` + state + `"}`)),
+		})
+	}
+
+	asyncProgressStarted("Creating Network Load Balancer \"web\"...")
+	operation(http.MethodPost, "/load-balancer", "pending")
+	operation(http.MethodGet, "/operation/op1", "pending")
+	operation(http.MethodGet, "/load-balancer/lb1", "pending")
+	operation(http.MethodGet, "/operation/op1", "success")
+	asyncProgressEnded(asyncProgressFailure, errors.New("oops"))
+
+	// Operations performed outside of an async operation aren't reported.
+	operation(http.MethodGet, "/operation/op1", "pending")
+
+	var events []asyncProgressEvent
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var e asyncProgressEvent
+		require.NoError(t, dec.Decode(&e))
+		require.Equal(t, `Creating Network Load Balancer "web"`, e.Message)
+		events = append(events, e)
+	}
+
+	require.Len(t, events, 4)
+	require.Equal(t, asyncProgressRunning, events[0].State)
+	require.Equal(t, "", events[0].OperationID)
+	require.Equal(t, "pending", events[1].OperationState)
+	require.Equal(t, "op1", events[1].OperationID)
+	require.Equal(t, "success", events[2].OperationState)
+	require.Equal(t, asyncProgressFailure, events[3].State)
+	require.Equal(t, "oops", events[3].Error)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jmespath/go-jmespath"
+)
+
+// outputQuery applies the JMESPath expression specified using the global
+// "--query" flag to the JSON representation of o, and prints the result in
+// JSON format ("ndjson" and "yaml" formats being honored).
+func outputQuery(o outputter) error {
+	data, err := outputJSONData(o)
+	if err != nil {
+		return err
+	}
+
+	res, err := jmespath.Search(gOutputQuery, data)
+	if err != nil {
+		var syntaxErr jmespath.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return fmt.Errorf("invalid query: %s at position %d\n%s\navailable top-level keys: %s",
+				syntaxErr.Error(),
+				syntaxErr.Offset,
+				syntaxErr.HighlightLocation(),
+				strings.Join(outputQueryKeys(data), ", "))
+		}
+		return fmt.Errorf("unable to apply query: %s (available top-level keys: %s)",
+			err, strings.Join(outputQueryKeys(data), ", "))
+	}
+
+	if res == nil && !gQuiet {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: query returned no result (available top-level keys: %s)\n",
+			strings.Join(outputQueryKeys(data), ", "))
+	}
+
+	switch gOutputFormat {
+	case "ndjson":
+		outputNDJSON(res)
+		return nil
+	case "yaml":
+		outputYAML(res)
+		return nil
+	}
+
+	outputJSON(res)
+	return nil
+}
+
+// outputJSONData returns the generic representation (i.e. maps, slices and
+// scalar values) of the JSON encoding of o.
+func outputJSONData(o interface{}) (interface{}, error) {
+	j, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode output to JSON: %s", err)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(j, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode JSON output: %s", err)
+	}
+
+	return data, nil
+}
+
+// outputQueryKeys returns the sorted top-level keys of data, i.e. the keys
+// of the object or, for a list, the keys of its first object item.
+func outputQueryKeys(data interface{}) []string {
+	if list, ok := data.([]interface{}); ok {
+		if len(list) == 0 {
+			return []string{"(empty list)"}
+		}
+		data = list[0]
+	}
+
+	obj, ok := data.(map[string]interface{})
+	if !ok {
+		return []string{"(none)"}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// outputNDJSON prints a newline-delimited JSON rendering of o to the
+// terminal: if o is a list each item is printed on its own line, otherwise
+// o is printed on a single line.
+func outputNDJSON(o interface{}) {
+	data, err := outputJSONData(o)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+
+	list, ok := data.([]interface{})
+	if !ok {
+		list = []interface{}{data}
+	}
+
+	for _, item := range list {
+		outputJSON(item)
+	}
+}
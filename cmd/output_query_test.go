@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_outputQueryKeys(t *testing.T) {
+	require.Equal(t, []string{"id", "name"}, outputQueryKeys(map[string]interface{}{"name": "a", "id": "b"}))
+	require.Equal(t, []string{"id"}, outputQueryKeys([]interface{}{map[string]interface{}{"id": "b"}}))
+	require.Equal(t, []string{"(empty list)"}, outputQueryKeys([]interface{}{}))
+	require.Equal(t, []string{"(none)"}, outputQueryKeys("a"))
+}
+
+func Test_outputQuery_syntaxError(t *testing.T) {
+	defer func(v string) { gOutputQuery = v }(gOutputQuery)
+	gOutputQuery = "[?name=="
+
+	out := instanceListOutput{{ID: "a", Name: "b"}}
+	err := outputQuery(&out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at position")
+	require.Contains(t, err.Error(), "available top-level keys: id, ip_address, name, state, type, zone")
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// asyncStep represents a named step of a multi-step asynchronous operation.
+type asyncStep struct {
+	name string
+	run  func() error
+}
+
+type asyncStepState int
+
+const (
+	asyncStepPending asyncStepState = iota
+	asyncStepRunning
+	asyncStepDone
+	asyncStepFailed
+)
+
+// asyncStepsProgress renders the progress of a multi-step operation. On
+// terminals a checklist is redrawn in place, otherwise plain lines are printed
+// sequentially as the steps are started.
+type asyncStepsProgress struct {
+	w       io.Writer
+	tty     bool
+	message string
+	steps   []asyncStep
+	states  []asyncStepState
+	elapsed []time.Duration
+	started time.Time
+	current int
+	drawn   int
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+}
+
+func newAsyncStepsProgress(w io.Writer, tty bool, message string, steps []asyncStep) *asyncStepsProgress {
+	p := &asyncStepsProgress{
+		w:       w,
+		tty:     tty,
+		message: message,
+		steps:   steps,
+		states:  make([]asyncStepState, len(steps)),
+		elapsed: make([]time.Duration, len(steps)),
+		current: -1,
+		stop:    make(chan struct{}),
+	}
+
+	if p.tty {
+		p.draw()
+
+		// Refresh the elapsed time of the current step.
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					p.mu.Lock()
+					p.draw()
+					p.mu.Unlock()
+				case <-p.stop:
+					return
+				}
+			}
+		}()
+	} else {
+		fmt.Fprintln(p.w, message)
+	}
+
+	return p
+}
+
+func (p *asyncStepsProgress) start(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = i
+	p.states[i] = asyncStepRunning
+	p.started = time.Now()
+
+	if p.tty {
+		p.draw()
+		return
+	}
+	fmt.Fprintf(p.w, "[%d/%d] %s...\n", i+1, len(p.steps), p.steps[i].name)
+}
+
+func (p *asyncStepsProgress) end(i int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.elapsed[i] = time.Since(p.started)
+	p.states[i] = asyncStepDone
+	if err != nil {
+		p.states[i] = asyncStepFailed
+	}
+
+	if p.tty {
+		p.draw()
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(p.w, "[%d/%d] %s: failed\n", i+1, len(p.steps), p.steps[i].name)
+	}
+}
+
+func (p *asyncStepsProgress) close() {
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// draw (re)renders the checklist, overwriting the previous rendering.
+func (p *asyncStepsProgress) draw() {
+	var b strings.Builder
+
+	if p.drawn > 0 {
+		fmt.Fprintf(&b, "\033[%dA", p.drawn)
+	}
+
+	fmt.Fprintf(&b, "\r\033[K%s\n", p.message)
+	for i, step := range p.steps {
+		switch p.states[i] {
+		case asyncStepPending:
+			fmt.Fprintf(&b, "\r\033[K  · %s\n", step.name)
+		case asyncStepRunning:
+			fmt.Fprintf(&b, "\r\033[K  ➜ %s %s\n", step.name, time.Since(p.started).Round(time.Second))
+		case asyncStepDone:
+			fmt.Fprintf(&b, "\r\033[K  ✓ %s %s\n", step.name, p.elapsed[i].Round(time.Second))
+		case asyncStepFailed:
+			fmt.Fprintf(&b, "\r\033[K  ✗ %s\n", step.name)
+		}
+	}
+	p.drawn = len(p.steps) + 1
+
+	_, _ = io.WriteString(p.w, b.String())
+}
+
+// decorateAsyncSteps is the multi-step variant of decorateAsyncOperation:
+// the steps are executed sequentially, stopping at the first step returning
+// an error, and their progress is rendered as a checklist. In "--no-wait"
+// mode, a noWaitError is returned as soon as the operation of the last step
+// has been submitted to the API. If the output format is JSON, the progress
+// of each step is reported as JSON events instead (see asyncProgressEvent),
+// and with the other machine-readable output formats the checklist is never
+// rendered on the standard output.
+func decorateAsyncSteps(message string, steps ...asyncStep) error {
+	// Don't start new operations once the CLI has been interrupted.
+	if gContext.Err() != nil {
+		if commandTimedOut() {
+			return errCommandTimedOut()
+		}
+		exitInterrupted()
+	}
+	defer suspendCommandTimeout()()
+
+	events := asyncProgressEventsEnabled()
+
+	// Without decorations (e.g. when the standard output is not a terminal)
+	// or with a machine-readable output format, the progress is reported as
+	// plain lines on the standard error, in order not to alter the command
+	// output.
+	var p *asyncStepsProgress
+	switch {
+	case gQuiet || events:
+		// The progress is either not reported at all, or reported as events.
+	case !gNoColor && !machineReadableOutputFormat():
+		p = newAsyncStepsProgress(os.Stdout, true, message, steps)
+	default:
+		p = newAsyncStepsProgress(os.Stderr, false, message, steps)
+	}
+	if p != nil {
+		defer p.close()
+	}
+
+	for i, step := range steps {
+		if p != nil {
+			p.start(i)
+		}
+		if events {
+			asyncProgressStarted(step.name)
+		}
+
+		id := asyncOperationStarted(step.name)
+		timeout := asyncWaitTimeout()
+
+		// Previous steps have to be completed for the last one to be run.
+		var submitted <-chan string
+		if i == len(steps)-1 {
+			submitted = asyncOperationSubmitted()
+		}
+
+		var err error
+		done := make(chan struct{})
+		go func(run func() error) {
+			err = run()
+			close(done)
+		}(step.run)
+
+		select {
+		case <-done:
+		case <-gContext.Done():
+		case resID := <-submitted:
+			if p != nil {
+				p.end(i, nil)
+			}
+			if events {
+				asyncProgressEnded(asyncProgressSubmitted, nil)
+			}
+			return noWaitError{id: resID}
+		case <-timeout:
+			if p != nil {
+				p.close()
+			}
+			if events {
+				asyncProgressEnded(asyncProgressFailure, errors.New("timed out"))
+			}
+			exitWaitTimeout(step.name)
+		}
+
+		// If the step returned because of the interruption, we don't know
+		// whether it actually completed on the API side.
+		if gContext.Err() != nil {
+			if p != nil {
+				p.close()
+			}
+			if events {
+				asyncProgressEnded(asyncProgressFailure, errors.New("interrupted"))
+			}
+			exitInterrupted()
+		}
+
+		asyncOperationCompleted(id)
+		if p != nil {
+			p.end(i, err)
+		}
+		if events {
+			state := asyncProgressSuccess
+			if err != nil {
+				state = asyncProgressFailure
+			}
+			asyncProgressEnded(state, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
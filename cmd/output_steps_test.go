@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_asyncStepsProgress(t *testing.T) {
+	steps := []asyncStep{{name: "step 1"}, {name: "step 2"}, {name: "step 3"}}
+
+	t.Run("plain", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		p := newAsyncStepsProgress(&buf, false, "Doing things...", steps)
+		p.start(0)
+		p.end(0, nil)
+		p.start(1)
+		p.end(1, errors.New("oops"))
+		p.close()
+
+		require.Equal(t, `Doing things...
+[1/3] step 1...
+[2/3] step 2...
+[2/3] step 2: failed
+`, buf.String())
+	})
+
+	t.Run("tty", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		p := newAsyncStepsProgress(&buf, true, "Doing things...", steps)
+		p.start(0)
+		p.end(0, nil)
+		p.start(1)
+		p.close()
+
+		// Only consider the last rendering of the checklist.
+		out := buf.String()
+		out = out[strings.LastIndex(out, "\033[4A")+len("\033[4A"):]
+		out = strings.ReplaceAll(out, "\r\033[K", "")
+
+		require.Equal(t, `Doing things...
+  ✓ step 1 0s
+  ➜ step 2 0s
+  · step 3
+`, out)
+	})
+}
+
+func Test_decorateAsyncSteps(t *testing.T) {
+	savedContext, savedQuiet := gContext, gQuiet
+	defer func() { gContext, gQuiet = savedContext, savedQuiet }()
+	gContext, gQuiet = context.Background(), true
+
+	var ran []string
+	step := func(name string, err error) asyncStep {
+		return asyncStep{name: name, run: func() error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+
+	require.NoError(t, decorateAsyncSteps("test", step("a", nil), step("b", nil)))
+	require.Equal(t, []string{"a", "b"}, ran)
+
+	ran = nil
+	require.EqualError(t, decorateAsyncSteps("test", step("a", errors.New("oops")), step("b", nil)), "oops")
+	require.Equal(t, []string{"a"}, ran)
+}
+
+func Test_decorateAsyncSteps_events(t *testing.T) {
+	savedContext, savedQuiet, savedNoColor := gContext, gQuiet, gNoColor
+	savedOutput, savedFormat := asyncProgressEventsOutput, gOutputFormat
+	defer func() {
+		gContext, gQuiet, gNoColor = savedContext, savedQuiet, savedNoColor
+		asyncProgressEventsOutput, gOutputFormat = savedOutput, savedFormat
+	}()
+
+	var events bytes.Buffer
+	gContext, gQuiet, gNoColor = context.Background(), false, false
+	asyncProgressEventsOutput, gOutputFormat = &events, "json"
+
+	step := func(name string, err error) asyncStep {
+		return asyncStep{name: name, run: func() error { return err }}
+	}
+
+	// The checklist must not be rendered, the standard output being reserved
+	// to the JSON command output.
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	err = decorateAsyncSteps("test", step("a", nil), step("b", errors.New("oops")))
+	os.Stdout = stdout
+	w.Close()
+	require.EqualError(t, err, "oops")
+
+	written, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Empty(t, written)
+
+	var states []string
+	for _, line := range strings.Split(strings.TrimSpace(events.String()), "\n") {
+		var e asyncProgressEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		states = append(states, e.Message+":"+e.State)
+	}
+	require.Equal(t, []string{"a:running", "a:success", "b:running", "b:failure"}, states)
+}
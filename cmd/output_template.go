@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// outputTemplateNamePrefix is the prefix of the "--output-template" flag
+// value referring to a named template saved using "exo output template save".
+const outputTemplateNamePrefix = "name:"
+
+// outputTemplateFileExt is the file extension of the named output templates.
+const outputTemplateFileExt = ".tmpl"
+
+// outputTemplateFuncsHelp documents the output template functions in the
+// commands supporting output template annotations.
+const outputTemplateFuncsHelp = `Supported output template functions: join, upper, lower, trunc, date, default (see "exo output --help")`
+
+// gOutputTemplateFile is the path of the file to read the output template
+// from, as an alternative to the "--output-template" flag.
+var gOutputTemplateFile string
+
+var outputTemplateNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$`)
+
+// outputTemplateFuncs are the functions available in output templates. Like
+// their Sprig library (https://masterminds.github.io/sprig/) counterparts,
+// the value processed is the last argument so that they can be used in
+// pipelines (e.g. {{ .SecurityGroups | join "," }}), and nil pointers are
+// treated as empty values rather than failing.
+var outputTemplateFuncs = template.FuncMap{
+	"date":    outputTemplateDate,
+	"default": outputTemplateDefault,
+	"join":    outputTemplateJoin,
+	"lower":   func(v interface{}) string { return strings.ToLower(outputTemplateString(v)) },
+	"trunc":   outputTemplateTrunc,
+	"upper":   func(v interface{}) string { return strings.ToUpper(outputTemplateString(v)) },
+}
+
+// outputTemplateDateLayouts are the layouts used to parse the dates
+// represented as strings in the commands outputs.
+var outputTemplateDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999 -0700 MST", // time.Time.String()
+	"2006-01-02T15:04:05-0700",                // Exoscale API V1
+}
+
+// outputTemplateValue returns the value v points to, or nil if v is a nil
+// pointer.
+func outputTemplateValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil
+	}
+
+	return rv.Interface()
+}
+
+// outputTemplateString returns the string representation of v, nil pointers
+// being represented as an empty string.
+func outputTemplateString(v interface{}) string {
+	switch v := outputTemplateValue(v).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// outputTemplateJoin joins the items of the list v using sep.
+func outputTemplateJoin(sep string, v interface{}) string {
+	v = outputTemplateValue(v)
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return outputTemplateString(v)
+	}
+
+	items := make([]string, rv.Len())
+	for i := range items {
+		items[i] = outputTemplateString(rv.Index(i).Interface())
+	}
+
+	return strings.Join(items, sep)
+}
+
+// outputTemplateTrunc truncates the string representation of v to n
+// characters, a negative n keeping the last characters.
+func outputTemplateTrunc(n int, v interface{}) string {
+	r := []rune(outputTemplateString(v))
+
+	switch {
+	case n >= 0 && len(r) > n:
+		return string(r[:n])
+	case n < 0 && len(r) > -n:
+		return string(r[len(r)+n:])
+	}
+
+	return string(r)
+}
+
+// outputTemplateDefault returns def if v is empty (nil pointer, zero value,
+// empty string, slice or map), v otherwise.
+func outputTemplateDefault(def, v interface{}) interface{} {
+	v = outputTemplateValue(v)
+	if v == nil {
+		return def
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		if rv.Len() == 0 {
+			return def
+		}
+	default:
+		if rv.IsZero() {
+			return def
+		}
+	}
+
+	return v
+}
+
+// outputTemplateDate formats the date v (either a time.Time or a string)
+// using the Go time layout.
+func outputTemplateDate(layout string, v interface{}) (string, error) {
+	switch d := outputTemplateValue(v).(type) {
+	case nil:
+		return "", nil
+
+	case time.Time:
+		return d.Format(layout), nil
+
+	case string:
+		if d == "" {
+			return "", nil
+		}
+		for _, l := range outputTemplateDateLayouts {
+			if t, err := time.Parse(l, d); err == nil {
+				return t.Format(layout), nil
+			}
+		}
+		return "", fmt.Errorf("unable to parse date %q", d)
+
+	default:
+		return "", fmt.Errorf("unsupported date type %T", v)
+	}
+}
+
+// outputTemplatesDir returns the path of the directory containing the named
+// output templates.
+func outputTemplatesDir() string {
+	return filepath.Join(gConfigFolder, "templates")
+}
+
+// outputTemplateNames returns the names of the saved output templates.
+func outputTemplateNames() ([]string, error) {
+	files, err := os.ReadDir(outputTemplatesDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), outputTemplateFileExt) {
+			names = append(names, strings.TrimSuffix(f.Name(), outputTemplateFileExt))
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// loadOutputTemplate returns the content of the output template saved
+// under the specified name.
+func loadOutputTemplate(name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(outputTemplatesDir(), name+outputTemplateFileExt))
+	if err == nil {
+		return string(data), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) || !outputTemplateNameRegexp.MatchString(name) {
+		return "", fmt.Errorf("unable to read output template %q: %w", name, err)
+	}
+
+	names, err := outputTemplateNames()
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf(`no output template named %q (no templates saved, see "exo output template save --help")`, name)
+	}
+
+	return "", fmt.Errorf("no output template named %q (available templates: %s)", name, strings.Join(names, ", "))
+}
+
+// readOutputTemplateFile returns the content of the output template file at
+// path ("-" meaning the standard input). The trailing line return of the
+// file is removed, so that the template is applied exactly like when
+// provided using the "--output-template" flag.
+func readOutputTemplateFile(path string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
+
+// resolveOutputTemplate sets the output template to the content of the file
+// specified using the "--output-template-file" flag ("-" meaning the
+// standard input), or to the content of
+// the named template if the "--output-template" flag value has the "name:"
+// prefix.
+func resolveOutputTemplate() error {
+	if gOutputTemplateFile != "" {
+		if gOutputTemplate != "" {
+			return errors.New(`"--output-template" and "--output-template-file" flags are mutually exclusive`)
+		}
+
+		tpl, err := readOutputTemplateFile(gOutputTemplateFile)
+		if err != nil {
+			return fmt.Errorf("unable to read output template file: %w", err)
+		}
+		gOutputTemplate, gOutputTemplateFile = tpl, ""
+	}
+
+	if strings.HasPrefix(gOutputTemplate, outputTemplateNamePrefix) {
+		tpl, err := loadOutputTemplate(strings.TrimPrefix(gOutputTemplate, outputTemplateNamePrefix))
+		if err != nil {
+			return err
+		}
+		gOutputTemplate = tpl
+	}
+
+	return nil
+}
+
+// documentOutputTemplateFuncs appends the output template functions help to
+// the long description of the commands (and their sub-commands) supporting
+// output template annotations.
+func documentOutputTemplateFuncs(cmd *cobra.Command) {
+	if strings.Contains(cmd.Long, "Supported output template annotations") {
+		cmd.Long = strings.TrimRight(cmd.Long, "\n") + "\n\n" + outputTemplateFuncsHelp
+	}
+
+	for _, c := range cmd.Commands() {
+		documentOutputTemplateFuncs(c)
+	}
+}
+
+var outputTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage named output templates",
+}
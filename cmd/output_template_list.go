@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type outputTemplateListItemOutput struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type outputTemplateListOutput []outputTemplateListItemOutput
+
+func (o *outputTemplateListOutput) toJSON()  { outputJSON(o) }
+func (o *outputTemplateListOutput) toText()  { outputText(o) }
+func (o *outputTemplateListOutput) toTable() { outputTable(o) }
+
+type outputTemplateListCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"list"`
+}
+
+func (c *outputTemplateListCmd) cmdAliases() []string { return gListAlias }
+
+func (c *outputTemplateListCmd) cmdShort() string { return "List named output templates" }
+
+func (c *outputTemplateListCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists the output templates saved using "exo output template save".
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&outputTemplateListItemOutput{}), ", "))
+}
+
+func (c *outputTemplateListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *outputTemplateListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	names, err := outputTemplateNames()
+	if err != nil {
+		return err
+	}
+
+	out := make(outputTemplateListOutput, len(names))
+	for i, name := range names {
+		out[i] = outputTemplateListItemOutput{
+			Name: name,
+			Path: filepath.Join(outputTemplatesDir(), name+outputTemplateFileExt),
+		}
+	}
+
+	return c.outputFunc(&out, nil)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(outputTemplateCmd, &outputTemplateListCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
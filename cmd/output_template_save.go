@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+type outputTemplateSaveCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"save"`
+
+	Name     string `cli-arg:"#" cli-usage:"NAME"`
+	Template string `cli-arg:"?" cli-usage:"TEMPLATE"`
+
+	File string `cli-short:"f" cli-usage:"path of the file to read the template from (\"-\" for standard input)"`
+}
+
+func (c *outputTemplateSaveCmd) cmdAliases() []string { return nil }
+
+func (c *outputTemplateSaveCmd) cmdShort() string { return "Save a named output template" }
+
+func (c *outputTemplateSaveCmd) cmdLong() string {
+	return fmt.Sprintf(`This command saves an output template under a name, either provided as
+argument or read from a file using the "--file" flag, to be used later
+using the "--output-template name:NAME" flag:
+
+	$ exo output template save names '{{ .Name }}'
+	$ exo compute instance list --output-template name:names
+
+Named templates are stored in the %q directory.`,
+		outputTemplatesDir())
+}
+
+func (c *outputTemplateSaveCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *outputTemplateSaveCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	if !outputTemplateNameRegexp.MatchString(c.Name) {
+		cmdExitOnUsageError(cmd, fmt.Sprintf("invalid template name %q", c.Name))
+	}
+
+	if (c.Template == "") == (c.File == "") {
+		cmdExitOnUsageError(cmd, `either a TEMPLATE argument or the "--file" flag must be specified`)
+	}
+
+	if c.File != "" {
+		tpl, err := readOutputTemplateFile(c.File)
+		if err != nil {
+			return fmt.Errorf("unable to read template file: %w", err)
+		}
+		c.Template = tpl
+	}
+
+	if _, err := template.New(c.Name).Funcs(outputTemplateFuncs).Parse(c.Template); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	if err := os.MkdirAll(outputTemplatesDir(), 0o700); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filepath.Join(outputTemplatesDir(), c.Name+outputTemplateFileExt), []byte(c.Template), 0o600)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(outputTemplateCmd, &outputTemplateSaveCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
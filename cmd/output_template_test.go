@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_outputTemplateFuncs(t *testing.T) {
+	render := func(tpl string, data interface{}) string {
+		var buf bytes.Buffer
+		require.NoError(t, template.Must(template.New("").Funcs(outputTemplateFuncs).Parse(tpl)).Execute(&buf, data))
+		return buf.String()
+	}
+
+	name := "my-instance"
+	data := struct {
+		Name            *string
+		Description     *string
+		SecurityGroups  []string
+		PrivateNetworks []string
+		Labels          map[string]string
+		Size            int64
+		Created         *time.Time
+	}{
+		Name:           &name,
+		SecurityGroups: []string{"default", "ssh"},
+	}
+
+	require.Equal(t, "default,ssh", render(`{{ .SecurityGroups | join "," }}`, data))
+	require.Equal(t, "", render(`{{ .PrivateNetworks | join "," }}`, data))
+	require.Equal(t, "MY-INSTANCE", render(`{{ .Name | upper }}`, data))
+	require.Equal(t, "", render(`{{ .Description | upper }}{{ .Description | lower }}`, data))
+	require.Equal(t, "my-in|tance|", render(`{{ .Name | trunc 5 }}|{{ trunc -5 .Name }}|{{ trunc 5 .Description }}`, data))
+	require.Equal(t, "my-instance -", render(`{{ .Name | default "-" }} {{ .Description | default "-" }}`, data))
+	require.Equal(t, "n/a n/a 0", render(`{{ .PrivateNetworks | default "n/a" }} {{ .Labels | default "n/a" }} {{ .Size | default 0 }}`, data))
+	require.Equal(t, "", render(`{{ .Created | date "2006-01-02" }}`, data))
+}
+
+func Test_outputTemplateDate(t *testing.T) {
+	date := time.Date(2021, 6, 15, 10, 30, 0, 0, time.UTC)
+
+	for _, v := range []interface{}{
+		date,
+		&date,
+		"2021-06-15T10:30:00Z",
+		"2021-06-15 10:30:00 +0000 UTC",
+		"2021-06-15T10:30:00+0000",
+	} {
+		s, err := outputTemplateDate("2006-01-02", v)
+		require.NoError(t, err)
+		require.Equal(t, "2021-06-15", s)
+	}
+
+	_, err := outputTemplateDate("2006-01-02", "yesterday")
+	require.Error(t, err)
+}
+
+func Test_resolveOutputTemplate(t *testing.T) {
+	configFolder := gConfigFolder
+	defer func() {
+		gConfigFolder, gOutputTemplate, gOutputTemplateFile = configFolder, "", ""
+	}()
+	gConfigFolder = t.TempDir()
+
+	gOutputTemplate = "name:names"
+	require.EqualError(t, resolveOutputTemplate(),
+		`no output template named "names" (no templates saved, see "exo output template save --help")`)
+
+	require.NoError(t, os.MkdirAll(outputTemplatesDir(), 0o700))
+	for name, tpl := range map[string]string{"ids": "{{ .ID }}", "names": "{{ .Name }}"} {
+		require.NoError(t, os.WriteFile(filepath.Join(outputTemplatesDir(), name+".tmpl"), []byte(tpl), 0o600))
+	}
+
+	gOutputTemplate = "name:zones"
+	require.EqualError(t, resolveOutputTemplate(), `no output template named "zones" (available templates: ids, names)`)
+
+	gOutputTemplate = "name:names"
+	require.NoError(t, resolveOutputTemplate())
+	require.Equal(t, "{{ .Name }}", gOutputTemplate)
+
+	gOutputTemplate, gOutputTemplateFile = "", filepath.Join(outputTemplatesDir(), "ids.tmpl")
+	require.NoError(t, resolveOutputTemplate())
+	require.Equal(t, "{{ .ID }}", gOutputTemplate)
+
+	gOutputTemplateFile = filepath.Join(outputTemplatesDir(), "ids.tmpl")
+	require.EqualError(t, resolveOutputTemplate(),
+		`"--output-template" and "--output-template-file" flags are mutually exclusive`)
+}
+
+func Test_readOutputTemplateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tpl")
+	require.NoError(t, os.WriteFile(path, []byte("{{ .Name }}\n{{ .ID }}\n"), 0o600))
+
+	tpl, err := readOutputTemplateFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "{{ .Name }}\n{{ .ID }}", tpl)
+
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	os.Stdin, err = os.Open(path)
+	require.NoError(t, err)
+
+	tpl, err = readOutputTemplateFile("-")
+	require.NoError(t, err)
+	require.Equal(t, "{{ .Name }}\n{{ .ID }}", tpl)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// outputYAML prints a YAML-formatted rendering of o to the terminal. The
+// rendering is based on the JSON encoding of o, so that the YAML output
+// fields are named and ordered as in the JSON output.
+func outputYAML(o interface{}) {
+	y, err := outputYAMLData(o)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(string(y))
+}
+
+// outputYAMLData returns the YAML encoding of o, based on its JSON encoding.
+func outputYAMLData(o interface{}) ([]byte, error) {
+	j, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode output to JSON: %s", err)
+	}
+
+	// JSON being a subset of YAML, decoding it as a YAML node preserves the
+	// order of the objects keys.
+	var node yaml.Node
+	if err := yaml.Unmarshal(j, &node); err != nil {
+		return nil, fmt.Errorf("unable to decode JSON output: %s", err)
+	}
+	outputYAMLResetStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, fmt.Errorf("unable to encode output to YAML: %s", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("unable to encode output to YAML: %s", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// outputYAMLResetStyle resets the style of the node and its children, which
+// inherit the JSON flow style ({...}, [...] and quoted strings) otherwise.
+func outputYAMLResetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		outputYAMLResetStyle(n)
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_outputYAMLData(t *testing.T) {
+	out := instanceListOutput{{ID: "a", Name: "b", State: "running"}}
+	y, err := outputYAMLData(&out)
+	require.NoError(t, err)
+	require.Equal(t, `- id: a
+  name: b
+  zone: ""
+  type: ""
+  ip_address: ""
+  state: running
+`, string(y))
+
+	labels := map[string]string{"team": "web", "app": "123"}
+	y, err = outputYAMLData(&struct {
+		Name           string             `json:"name"`
+		Labels         *map[string]string `json:"labels"`
+		SecurityGroups []string           `json:"security_groups"`
+	}{
+		Name:           "web",
+		Labels:         &labels,
+		SecurityGroups: []string{"default", "true"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `name: web
+labels:
+  app: "123"
+  team: web
+security_groups:
+  - default
+  - "true"
+`, string(y))
+}
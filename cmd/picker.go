@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// gNonInteractive is set by the global "--non-interactive" flag: the CLI
+// never prompts for the missing positional arguments.
+var gNonInteractive bool
+
+// isInteractive returns true if the CLI can prompt the user, i.e. both the
+// standard input and output are terminals and the "--non-interactive" flag
+// is not set.
+func isInteractive() bool {
+	return !gNonInteractive &&
+		term.IsTerminal(int(os.Stdout.Fd())) &&
+		term.IsTerminal(int(gStdin.Fd()))
+}
+
+// pickResource prompts the user to select one of the existing resources of
+// the specified type (see completionResources) in the zone of the command,
+// and returns its name.
+func pickResource(cmd *cobra.Command, resource string) (string, error) {
+	zone := cmdResourceZone(cmd)
+
+	names, err := listResourceNamesCached(resource, zone)
+	if err != nil {
+		return "", fmt.Errorf("unable to list %ss: %w", resource, err)
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no %s found in zone %s", resource, zone)
+	}
+
+	prompt := promptui.Select{
+		Label: fmt.Sprintf("Select %s (zone %s)", strings.ReplaceAll(resource, "-", " "), zone),
+		Items: names,
+		Size:  10,
+		Searcher: func(input string, index int) bool {
+			return strings.Contains(strings.ToLower(names[index]), strings.ToLower(input))
+		},
+	}
+
+	_, name, err := prompt.Run()
+	if err != nil {
+		if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+			return "", errors.New("no selection")
+		}
+		return "", fmt.Errorf("prompt failed: %s", err)
+	}
+
+	return name, nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+type testPickCLICmd struct {
+	_ bool `cli-cmd:"pick"`
+
+	Instance string `cli-arg:"#" cli-pick:"instance"`
+}
+
+func (c *testPickCLICmd) cmdAliases() []string { return nil }
+func (c *testPickCLICmd) cmdShort() string     { return "" }
+func (c *testPickCLICmd) cmdLong() string      { return "" }
+func (c *testPickCLICmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+func (c *testPickCLICmd) cmdRun(_ *cobra.Command, _ []string) error { return nil }
+
+type testInvalidPickCLICmd struct {
+	_ bool `cli-cmd:"pick"`
+
+	Instance string `cli-arg:"#" cli-pick:"unicorn"`
+}
+
+func (c *testInvalidPickCLICmd) cmdAliases() []string                         { return nil }
+func (c *testInvalidPickCLICmd) cmdShort() string                             { return "" }
+func (c *testInvalidPickCLICmd) cmdLong() string                              { return "" }
+func (c *testInvalidPickCLICmd) cmdPreRun(_ *cobra.Command, _ []string) error { return nil }
+func (c *testInvalidPickCLICmd) cmdRun(_ *cobra.Command, _ []string) error    { return nil }
+
+func Test_cliCommandPick(t *testing.T) {
+	require.Error(t, registerCLICommand(&cobra.Command{Use: "root"}, &testInvalidPickCLICmd{}))
+
+	defer func(v bool) { gNonInteractive = v }(gNonInteractive)
+	gNonInteractive = true
+	require.False(t, isInteractive())
+
+	c := &testPickCLICmd{}
+	root := &cobra.Command{Use: "root", SilenceUsage: true, SilenceErrors: true}
+	require.NoError(t, registerCLICommand(root, c))
+
+	// The argument is never prompted for when not running interactively.
+	root.SetArgs([]string{"pick"})
+	require.EqualError(t, root.Execute(), "missing arguments, run with --help for usage")
+
+	root.SetArgs([]string{"pick", "web1"})
+	require.NoError(t, root.Execute())
+	require.Equal(t, "web1", c.Instance)
+}
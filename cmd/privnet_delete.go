@@ -23,11 +23,19 @@ var privnetDeleteCmd = &cobra.Command{
 
 		tasks := make([]task, 0, len(args))
 		for _, arg := range args {
-			cmd, err := deletePrivnet(arg)
+			network, err := getNetwork(arg, nil)
 			if err != nil {
 				return err
 			}
 
+			err = checkResourceReferences(privateNetworkReferencesFinder, []string{network.ZoneName},
+				"Private Network", network.Name, network.ID.String(), force)
+			if err != nil {
+				return err
+			}
+
+			cmd := &egoscale.DeleteNetwork{ID: network.ID}
+
 			if !force {
 				if !askQuestion(fmt.Sprintf("Are you sure you want to delete Private Network %q?", arg)) {
 					continue
@@ -49,17 +57,6 @@ var privnetDeleteCmd = &cobra.Command{
 	},
 }
 
-func deletePrivnet(name string) (*egoscale.DeleteNetwork, error) {
-	addrReq := &egoscale.DeleteNetwork{}
-	var err error
-	network, err := getNetwork(name, nil)
-	if err != nil {
-		return nil, err
-	}
-	addrReq.ID = network.ID
-	return addrReq, nil
-}
-
 func init() {
 	privnetDeleteCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
 	privnetCmd.AddCommand(privnetDeleteCmd)
@@ -2,18 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/exoscale/egoscale"
 	"github.com/spf13/cobra"
 )
 
 type privnetListItemOutput struct {
-	ID           string `json:"id"`
-	Name         string `json:"name"`
-	Zone         string `json:"zone"`
-	DHCP         string `json:"dhcp"`
-	NumInstances int    `json:"num_instances" outputLabel:"Instances"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Zone string `json:"zone"`
 }
 
 type privnetListOutput []privnetListItemOutput
@@ -22,14 +22,38 @@ func (o *privnetListOutput) toJSON()  { outputJSON(o) }
 func (o *privnetListOutput) toText()  { outputText(o) }
 func (o *privnetListOutput) toTable() { outputTable(o) }
 
+type privnetListDetailsItemOutput struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Zone         string   `json:"zone"`
+	DHCP         string   `json:"dhcp"`
+	NumInstances int      `json:"num_instances" outputLabel:"Instances"`
+	Instances    []string `json:"instances" output:"-"`
+}
+
+type privnetListDetailsOutput []privnetListDetailsItemOutput
+
+func (o *privnetListDetailsOutput) toJSON()  { outputJSON(o) }
+func (o *privnetListDetailsOutput) toText()  { outputText(o) }
+func (o *privnetListDetailsOutput) toTable() { outputTable(o) }
+
 func init() {
 	privnetListCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List Private Networks",
 		Long: fmt.Sprintf(`This command lists existing Private Networks.
 
-Supported output template annotations: %s`,
-			strings.Join(outputterTemplateAnnotations(&privnetListOutput{}), ", ")),
+Using the "--details" flag, the DHCP range and the number of Compute instances
+attached to each Private Network are displayed (the attached instances being
+listed in JSON output), at the cost of additional API lookups. Using the
+"--unused" flag, only the Private Networks having no Compute instances
+attached are listed.
+
+Supported output template annotations: %s
+
+Supported output template annotations (--details): %s`,
+			strings.Join(outputterTemplateAnnotations(&privnetListItemOutput{}), ", "),
+			strings.Join(outputterTemplateAnnotations(&privnetListDetailsItemOutput{}), ", ")),
 		Aliases: gListAlias,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			zone, err := cmd.Flags().GetString("zone")
@@ -37,61 +61,144 @@ Supported output template annotations: %s`,
 				return err
 			}
 
-			return output(listPrivnets(zone))
+			details, err := cmd.Flags().GetBool("details")
+			if err != nil {
+				return err
+			}
+
+			unused, err := cmd.Flags().GetBool("unused")
+			if err != nil {
+				return err
+			}
+
+			return output(listPrivnets(zone, details, unused))
 		},
 	}
 
 	privnetListCmd.Flags().StringP("zone", "z", "", "Show Private Networks only in specified zone")
+	privnetListCmd.Flags().BoolP("details", "d", false, "show DHCP range and attached Compute instances")
+	privnetListCmd.Flags().Bool("unused", false, "show only Private Networks with no Compute instances attached")
 	privnetCmd.AddCommand(privnetListCmd)
 }
 
-func listPrivnets(zone string) (outputter, error) {
-	out := privnetListOutput{}
-
+func listPrivnets(zone string, details, unused bool) (outputter, error) {
 	zones, err := cs.ListWithContext(gContext, &egoscale.Zone{})
 	if err != nil {
 		return nil, err
 	}
 
+	zonesByName := make(map[string]*egoscale.Zone)
 	for _, z := range zones {
 		if zone != "" && z.(*egoscale.Zone).Name != zone {
 			continue
 		}
+		zonesByName[z.(*egoscale.Zone).Name] = z.(*egoscale.Zone)
+	}
+
+	zoneNames := make([]string, 0, len(zonesByName))
+	for name := range zonesByName {
+		zoneNames = append(zoneNames, name)
+	}
 
-		req := egoscale.Network{
-			ZoneID:          z.(*egoscale.Zone).ID,
+	var (
+		res   = make([]privnetListDetailsItemOutput, 0)
+		resMu sync.Mutex
+	)
+
+	// The attached Compute instances are looked up once per zone (concurrently
+	// for all zones) rather than once per Private Network.
+	err = forEachZone(zoneNames, func(zoneName string) error {
+		z := zonesByName[zoneName]
+
+		privnets, err := cs.ListWithContext(gContext, &egoscale.Network{
+			ZoneID:          z.ID,
 			Type:            "Isolated",
 			CanUseForDeploy: true,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to list Private Networks in zone %s: %v", zoneName, err)
+		}
+		if len(privnets) == 0 {
+			return nil
 		}
 
-		privnets, err := cs.ListWithContext(gContext, &req)
-		if err != nil {
-			return nil, err
+		var attachments map[string][]string
+		if details || unused {
+			vms, err := cs.ListWithContext(gContext, &egoscale.VirtualMachine{ZoneID: z.ID})
+			if err != nil {
+				return fmt.Errorf("unable to list Compute instances in zone %s: %v", zoneName, err)
+			}
+			attachments = privnetAttachments(vms)
 		}
 
 		for _, p := range privnets {
 			privnet := p.(*egoscale.Network)
 
-			vms, err := privnetDetails(privnet)
-			if err != nil {
-				return nil, err
+			instances := attachments[privnet.ID.String()]
+			if unused && len(instances) > 0 {
+				continue
 			}
-			instances := make([]string, len(vms))
-			for i := range vms {
-				instances[i] = vms[i].Name
+			if instances == nil {
+				instances = []string{}
 			}
 
-			o := privnetListItemOutput{
+			resMu.Lock()
+			res = append(res, privnetListDetailsItemOutput{
 				ID:           privnet.ID.String(),
 				Name:         privnet.Name,
-				Zone:         z.(*egoscale.Zone).Name,
+				Zone:         zoneName,
 				DHCP:         dhcpRange(*privnet),
 				NumInstances: len(instances),
-			}
+				Instances:    instances,
+			})
+			resMu.Unlock()
+		}
 
-			out = append(out, o)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Zone != res[j].Zone {
+			return res[i].Zone < res[j].Zone
 		}
+		return res[i].Name < res[j].Name
+	})
+
+	if details {
+		out := privnetListDetailsOutput(res)
+		return &out, nil
+	}
+
+	out := make(privnetListOutput, len(res))
+	for i, p := range res {
+		out[i] = privnetListItemOutput{ID: p.ID, Name: p.Name, Zone: p.Zone}
 	}
 
 	return &out, nil
 }
+
+// privnetAttachments returns the names of the Compute instances attached to
+// each Private Network, indexed by Private Network ID.
+func privnetAttachments(vms []interface{}) map[string][]string {
+	attachments := make(map[string][]string)
+
+	for _, v := range vms {
+		vm := v.(*egoscale.VirtualMachine)
+
+		for _, nic := range vm.Nic {
+			if nic.IsDefault || nic.NetworkID == nil {
+				continue
+			}
+			attachments[nic.NetworkID.String()] = append(attachments[nic.NetworkID.String()], vm.Name)
+		}
+	}
+
+	for id := range attachments {
+		sort.Strings(attachments[id])
+	}
+
+	return attachments
+}
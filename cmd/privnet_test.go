@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"net"
+	"reflect"
 	"testing"
 
 	"github.com/exoscale/egoscale"
@@ -40,3 +41,33 @@ func TestDhcpRange(t *testing.T) {
 		}
 	}
 }
+
+func TestPrivnetAttachments(t *testing.T) {
+	privnetA := egoscale.MustParseUUID("9e0b9a88-2a6d-4d2a-9c3b-0b2c7a4f3c01")
+	privnetB := egoscale.MustParseUUID("9e0b9a88-2a6d-4d2a-9c3b-0b2c7a4f3c02")
+	public := egoscale.MustParseUUID("9e0b9a88-2a6d-4d2a-9c3b-0b2c7a4f3c03")
+
+	vms := []interface{}{
+		&egoscale.VirtualMachine{Name: "web2", Nic: []egoscale.Nic{
+			{NetworkID: public, IsDefault: true},
+			{NetworkID: privnetA},
+		}},
+		&egoscale.VirtualMachine{Name: "web1", Nic: []egoscale.Nic{
+			{NetworkID: public, IsDefault: true},
+			{NetworkID: privnetA},
+			{NetworkID: privnetB},
+		}},
+		&egoscale.VirtualMachine{Name: "db1", Nic: []egoscale.Nic{
+			{NetworkID: public, IsDefault: true},
+		}},
+	}
+
+	actual := privnetAttachments(vms)
+	expected := map[string][]string{
+		privnetA.String(): {"web1", "web2"},
+		privnetB.String(): {"web1"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
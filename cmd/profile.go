@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// profileSlowestCalls is the number of slowest API calls reported.
+	profileSlowestCalls = 3
+
+	// profileRecentRequestIDs is the number of most recent API request IDs
+	// reported.
+	profileRecentRequestIDs = 5
+)
+
+// apiRequestIDHeaders are the response headers carrying the ID of an API
+// request, to be provided to the support.
+var apiRequestIDHeaders = []string{"X-Request-Id", "X-Trace-Id", "X-Amz-Request-Id"}
+
+// gProfile records the API calls performed during the CLI execution, and
+// gProfileFormat is the format to report them in ("" meaning no report).
+var (
+	gProfile       = newCLIProfile()
+	gProfileFormat string
+)
+
+type profileAPICall struct {
+	Method    string        `json:"method"`
+	URL       string        `json:"url"`
+	Status    int           `json:"status,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	RequestID string        `json:"request_id,omitempty"`
+	Duration  time.Duration `json:"duration_ns"`
+
+	start time.Time
+}
+
+// cliProfile records the API calls performed by the HTTP clients using the
+// cliRoundTripper.
+type cliProfile struct {
+	mu    sync.Mutex
+	start time.Time
+	calls []profileAPICall
+}
+
+func newCLIProfile() *cliProfile {
+	return &cliProfile{start: time.Now()}
+}
+
+// record records an API call performed using the request r, which started at
+// the time start.
+func (p *cliProfile) record(r *http.Request, res *http.Response, err error, start time.Time) {
+	call := profileAPICall{
+		Method:   r.Method,
+		URL:      profileRequestURL(r),
+		Duration: time.Since(start),
+		start:    start,
+	}
+	if res != nil {
+		call.Status = res.StatusCode
+		call.RequestID = apiRequestID(res)
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+
+	p.mu.Lock()
+	p.calls = append(p.calls, call)
+	p.mu.Unlock()
+}
+
+// apiRequestID returns the API request ID reported in the response res
+// headers, if any.
+func apiRequestID(res *http.Response) string {
+	for _, h := range apiRequestIDHeaders {
+		if id := res.Header.Get(h); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// profileRequestURL returns the request URL without its query string, which
+// can contain credentials (e.g. API V1 request signature). The API V1
+// command name is kept as it is the only way to identify such requests.
+func profileRequestURL(r *http.Request) string {
+	u := *r.URL
+	u.RawQuery = ""
+	if command := r.URL.Query().Get("command"); command != "" {
+		u.RawQuery = "command=" + command
+	}
+	return u.String()
+}
+
+type profileReport struct {
+	APICalls     int              `json:"api_calls"`
+	TotalTime    time.Duration    `json:"total_time_ns"`
+	APITime      time.Duration    `json:"api_time_ns"`
+	LocalTime    time.Duration    `json:"local_time_ns"`
+	SlowestCalls []profileAPICall `json:"slowest_calls"`
+
+	RecentRequestIDs []string `json:"recent_request_ids,omitempty"`
+}
+
+// report computes the profile report at the time end. As API calls can be
+// performed concurrently, the API time is the time during which at least one
+// API call was in progress.
+func (p *cliProfile) report(end time.Time) profileReport {
+	p.mu.Lock()
+	calls := make([]profileAPICall, len(p.calls))
+	copy(calls, p.calls)
+	p.mu.Unlock()
+
+	r := profileReport{
+		APICalls:  len(calls),
+		TotalTime: end.Sub(p.start),
+	}
+
+	sort.Slice(calls, func(i, j int) bool { return calls[i].start.Before(calls[j].start) })
+	var spanStart, spanEnd time.Time
+	for _, c := range calls {
+		callEnd := c.start.Add(c.Duration)
+		switch {
+		case spanEnd.IsZero():
+			spanStart, spanEnd = c.start, callEnd
+		case c.start.After(spanEnd):
+			r.APITime += spanEnd.Sub(spanStart)
+			spanStart, spanEnd = c.start, callEnd
+		case callEnd.After(spanEnd):
+			spanEnd = callEnd
+		}
+	}
+	r.APITime += spanEnd.Sub(spanStart)
+	r.LocalTime = r.TotalTime - r.APITime
+
+	for _, c := range calls {
+		if c.RequestID != "" {
+			r.RecentRequestIDs = append(r.RecentRequestIDs, c.RequestID)
+		}
+	}
+	if len(r.RecentRequestIDs) > profileRecentRequestIDs {
+		r.RecentRequestIDs = r.RecentRequestIDs[len(r.RecentRequestIDs)-profileRecentRequestIDs:]
+	}
+
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].Duration > calls[j].Duration })
+	if len(calls) > profileSlowestCalls {
+		calls = calls[:profileSlowestCalls]
+	}
+	r.SlowestCalls = calls
+
+	return r
+}
+
+// write writes the profile report to w in the specified format (text|json).
+func (p *cliProfile) write(w io.Writer, format string, end time.Time) error {
+	r := p.report(end)
+
+	switch format {
+	case "json":
+		return json.NewEncoder(w).Encode(r)
+
+	case "text":
+		fmt.Fprintf(w, "Profile: %d API call(s), total time %s (API: %s, local: %s)\n",
+			r.APICalls,
+			r.TotalTime.Round(time.Millisecond),
+			r.APITime.Round(time.Millisecond),
+			r.LocalTime.Round(time.Millisecond))
+		if len(r.SlowestCalls) > 0 {
+			fmt.Fprintln(w, "Slowest API calls:")
+		}
+		for _, c := range r.SlowestCalls {
+			status := fmt.Sprint(c.Status)
+			if c.Error != "" {
+				status = "error"
+			}
+			fmt.Fprintf(w, "  %s %s %s (%s)\n", c.Duration.Round(time.Millisecond), c.Method, c.URL, status)
+		}
+		if len(r.RecentRequestIDs) > 0 {
+			fmt.Fprintf(w, "Recent API request IDs: %s\n", strings.Join(r.RecentRequestIDs, ", "))
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported profile format %q (supported formats: text, json)", format)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_profileRequestURL(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet,
+		"https://api.exoscale.com/v1?apikey=EXOxxx&command=listZones&signature=secret", nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://api.exoscale.com/v1?command=listZones", profileRequestURL(r))
+
+	r, err = http.NewRequest(http.MethodGet, "https://api-ch-gva-2.exoscale.com/v2/instance?foo=bar", nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://api-ch-gva-2.exoscale.com/v2/instance", profileRequestURL(r))
+}
+
+func Test_cliProfile_report(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	p := &cliProfile{
+		start: start,
+		calls: []profileAPICall{
+			// Concurrent calls: [1s, 3s] and [2s, 4s] => 3s of API time.
+			{Method: "GET", URL: "/a", Duration: 2 * time.Second, start: start.Add(1 * time.Second)},
+			{Method: "GET", URL: "/b", Duration: 2 * time.Second, start: start.Add(2 * time.Second)},
+			{Method: "GET", URL: "/c", Duration: 500 * time.Millisecond, start: start.Add(5 * time.Second)},
+			{Method: "POST", URL: "/d", Duration: 3 * time.Second, start: start.Add(6 * time.Second)},
+		},
+	}
+
+	r := p.report(start.Add(10 * time.Second))
+	require.Equal(t, 4, r.APICalls)
+	require.Equal(t, 10*time.Second, r.TotalTime)
+	require.Equal(t, 6500*time.Millisecond, r.APITime)
+	require.Equal(t, 3500*time.Millisecond, r.LocalTime)
+	require.Len(t, r.SlowestCalls, profileSlowestCalls)
+	require.Equal(t, "/d", r.SlowestCalls[0].URL)
+
+	var out bytes.Buffer
+	require.NoError(t, p.write(&out, "json", start.Add(10*time.Second)))
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.EqualValues(t, 4, decoded["api_calls"])
+
+	require.Error(t, p.write(&out, "yaml", start))
+}
+
+func Test_cliProfile_requestIDs(t *testing.T) {
+	p := newCLIProfile()
+	r, err := http.NewRequest(http.MethodGet, "https://api-ch-gva-2.exoscale.com/v2/instance", nil)
+	require.NoError(t, err)
+
+	response := func(status int, header, requestID string) *http.Response {
+		res := &http.Response{StatusCode: status, Header: http.Header{}}
+		if header != "" {
+			res.Header.Set(header, requestID)
+		}
+		return res
+	}
+
+	p.record(r, response(http.StatusOK, "X-Request-Id", "req-1"), nil, time.Now())
+	p.record(r, response(http.StatusNotFound, "X-Request-Id", "req-2"), nil, time.Now())
+	p.record(r, response(http.StatusOK, "X-Trace-Id", "req-3"), nil, time.Now())
+	p.record(r, nil, errors.New("connection refused"), time.Now())
+
+	for i := 4; i <= 8; i++ {
+		p.record(r, response(http.StatusOK, "X-Amz-Request-Id", fmt.Sprintf("req-%d", i)), nil, time.Now())
+	}
+	p.record(r, response(http.StatusOK, "", ""), nil, time.Now())
+
+	report := p.report(time.Now())
+	require.Equal(t, []string{"req-4", "req-5", "req-6", "req-7", "req-8"}, report.RecentRequestIDs)
+
+	var out bytes.Buffer
+	require.NoError(t, p.write(&out, "text", time.Now()))
+	require.Contains(t, out.String(), "Recent API request IDs: req-4, req-5, req-6, req-7, req-8\n")
+}
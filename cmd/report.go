@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Organization usage reports",
+}
+
+func init() {
+	RootCmd.AddCommand(reportCmd)
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+// reportInstancesNoneGroup is the group of the instances not having the
+// label specified using the "--group-by" flag.
+const reportInstancesNoneGroup = "(none)"
+
+// reportInstancesTotalGroup is the group of the report totals row.
+const reportInstancesTotalGroup = "TOTAL"
+
+// reportInstancesPeriodHours are the number of hours of the supported cost
+// estimation periods.
+var reportInstancesPeriodHours = map[string]float64{
+	"hour":  1,
+	"day":   24,
+	"month": 730,
+}
+
+type reportInstancesItemOutput struct {
+	Group     string  `json:"group"`
+	Instances int     `json:"instances"`
+	Running   int     `json:"running"`
+	Cost      float64 `json:"cost" outputLabel:"Estimated Cost"`
+}
+
+type reportInstancesOutput []reportInstancesItemOutput
+
+func (o *reportInstancesOutput) toJSON()  { outputJSON(o) }
+func (o *reportInstancesOutput) toText()  { outputText(o) }
+func (o *reportInstancesOutput) toTable() { outputTable(o) }
+
+// reportInstance represents a Compute instance for cost attribution.
+type reportInstance struct {
+	labels       map[string]string
+	instanceType string
+	running      bool
+}
+
+type reportInstancesCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"instances"`
+
+	GroupBy string `cli-usage:"instances grouping criteria (format: label:KEY)"`
+	Period  string `cli-usage:"cost estimation period (hour|day|month)"`
+	Pricing string `cli-usage:"path to a JSON file mapping instance types to their hourly price"`
+	Zone    string `cli-short:"z" cli-usage:"zone to restrict the report to"`
+}
+
+func (c *reportInstancesCmd) cmdAliases() []string { return nil }
+
+func (c *reportInstancesCmd) cmdShort() string {
+	return "Report Compute instances estimated cost grouped by label"
+}
+
+func (c *reportInstancesCmd) cmdLong() string {
+	return fmt.Sprintf(`This command reports the number of Compute instances and their estimated
+cost over a period, grouped by the value of a label (e.g. "--group-by
+label:team"), across all zones unless the "--zone" flag is specified.
+
+As the Exoscale API doesn't expose pricing information, the instance type
+prices must be provided in a JSON file using the "--pricing" flag, mapping
+instance types to their hourly price:
+
+    {"standard.small": 0.0171, "standard.medium": 0.0347}
+
+The estimated cost only accounts for the instance type of running instances
+(a month being 730 hours), excluding disk, snapshots and network usage.
+Instances not having the grouping label are reported in the "%s" group,
+and instances whose type is missing from the pricing file are reported on
+the standard error. The report can be exported as CSV using the "-O csv"
+output format.
+
+Supported output template annotations: %s`,
+		reportInstancesNoneGroup,
+		strings.Join(outputterTemplateAnnotations(&reportInstancesItemOutput{}), ", "))
+}
+
+func (c *reportInstancesCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *reportInstancesCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	label := strings.TrimPrefix(c.GroupBy, "label:")
+	if !strings.HasPrefix(c.GroupBy, "label:") || label == "" {
+		cmdExitOnUsageError(cmd, `invalid --group-by value, expected format "label:KEY"`)
+	}
+
+	hours, ok := reportInstancesPeriodHours[c.Period]
+	if !ok {
+		cmdExitOnUsageError(cmd, fmt.Sprintf("invalid --period value %q (supported values: hour, day, month)", c.Period))
+	}
+
+	if c.Pricing == "" {
+		cmdExitOnUsageError(cmd, "no pricing file specified")
+	}
+	prices, err := loadReportInstancesPricing(c.Pricing)
+	if err != nil {
+		return err
+	}
+
+	zones := allZones
+	if c.Zone != "" {
+		zones = []string{c.Zone}
+	}
+
+	var (
+		instances = make([]reportInstance, 0)
+		mu        sync.Mutex
+	)
+
+	err = forEachZone(zones, func(zone string) error {
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+		types, err := cs.ListInstanceTypes(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("unable to list Compute instance types in zone %s: %v", zone, err)
+		}
+		typeNames := make(map[string]string)
+		for _, t := range types {
+			typeNames[*t.ID] = fmt.Sprintf("%s.%s", *t.Family, *t.Size)
+		}
+
+		list, err := cs.ListInstances(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("unable to list Compute instances in zone %s: %v", zone, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, i := range list {
+			instance := reportInstance{
+				instanceType: typeNames[*i.InstanceTypeID],
+				running:      i.State != nil && *i.State == "running",
+			}
+			if i.Labels != nil {
+				instance.labels = *i.Labels
+			}
+			instances = append(instances, instance)
+		}
+
+		return nil
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
+	}
+
+	out, unpriced := reportInstancesAggregate(instances, label, prices, hours)
+	if len(unpriced) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"warning: no price found for instance types %s, the estimated cost is incomplete\n",
+			strings.Join(unpriced, ", "))
+	}
+
+	return c.outputFunc(&out, nil)
+}
+
+// loadReportInstancesPricing loads the instance type hourly prices from the
+// JSON file at path.
+func loadReportInstancesPricing(path string) (map[string]float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read pricing file: %w", err)
+	}
+
+	prices := make(map[string]float64)
+	if err := json.Unmarshal(data, &prices); err != nil {
+		return nil, fmt.Errorf("invalid pricing file %s: %w", path, err)
+	}
+
+	return prices, nil
+}
+
+// reportInstancesAggregate aggregates the instances and their estimated cost
+// over the specified number of hours per value of the label, returning the
+// report rows sorted by group (the "(none)" group and totals last) and the
+// list of running instances types missing from prices.
+func reportInstancesAggregate(
+	instances []reportInstance,
+	label string,
+	prices map[string]float64,
+	hours float64,
+) (reportInstancesOutput, []string) {
+	groups := make(map[string]*reportInstancesItemOutput)
+	none := reportInstancesItemOutput{Group: reportInstancesNoneGroup}
+	total := reportInstancesItemOutput{Group: reportInstancesTotalGroup}
+	unpriced := make(map[string]struct{})
+
+	for _, i := range instances {
+		group := &none
+		if v, ok := i.labels[label]; ok {
+			if groups[v] == nil {
+				groups[v] = &reportInstancesItemOutput{Group: v}
+			}
+			group = groups[v]
+		}
+
+		group.Instances++
+		total.Instances++
+
+		if !i.running {
+			continue
+		}
+		group.Running++
+		total.Running++
+
+		price, ok := prices[i.instanceType]
+		if !ok {
+			unpriced[i.instanceType] = struct{}{}
+			continue
+		}
+		group.Cost += price * hours
+		total.Cost += price * hours
+	}
+
+	out := make(reportInstancesOutput, 0, len(groups)+2)
+	for _, g := range groups {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Group < out[j].Group })
+	out = append(out, none, total)
+
+	for i := range out {
+		out[i].Cost = roundCost(out[i].Cost)
+	}
+
+	unpricedTypes := make([]string, 0, len(unpriced))
+	for t := range unpriced {
+		unpricedTypes = append(unpricedTypes, t)
+	}
+	sort.Strings(unpricedTypes)
+
+	return out, unpricedTypes
+}
+
+// roundCost rounds a cost to the cent.
+func roundCost(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(reportCmd, &reportInstancesCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+		Period:             "month",
+	}))
+}
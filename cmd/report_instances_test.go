@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_reportInstancesAggregate(t *testing.T) {
+	instances := []reportInstance{
+		{labels: map[string]string{"team": "web"}, instanceType: "standard.small", running: true},
+		{labels: map[string]string{"team": "web"}, instanceType: "standard.medium", running: true},
+		{labels: map[string]string{"team": "data"}, instanceType: "standard.medium", running: false},
+		{labels: map[string]string{"env": "prod"}, instanceType: "standard.small", running: true},
+		{instanceType: "gpu.small", running: true},
+	}
+	prices := map[string]float64{"standard.small": 0.01, "standard.medium": 0.02}
+
+	out, unpriced := reportInstancesAggregate(instances, "team", prices, 730)
+	require.Equal(t, reportInstancesOutput{
+		{Group: "data", Instances: 1, Running: 0, Cost: 0},
+		{Group: "web", Instances: 2, Running: 2, Cost: 21.9},
+		{Group: reportInstancesNoneGroup, Instances: 2, Running: 2, Cost: 7.3},
+		{Group: reportInstancesTotalGroup, Instances: 5, Running: 4, Cost: 29.2},
+	}, out)
+	require.Equal(t, []string{"gpu.small"}, unpriced)
+
+	out, unpriced = reportInstancesAggregate(nil, "team", prices, 730)
+	require.Equal(t, reportInstancesOutput{
+		{Group: reportInstancesNoneGroup},
+		{Group: reportInstancesTotalGroup},
+	}, out)
+	require.Empty(t, unpriced)
+}
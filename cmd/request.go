@@ -32,6 +32,8 @@ type taskResponse struct {
 }
 
 func asyncTasks(tasks []task) []taskResponse {
+	defer suspendCommandTimeout()()
+
 	// init results
 	responses := make([]taskResponse, len(tasks))
 
@@ -42,7 +44,7 @@ func asyncTasks(tasks []task) []taskResponse {
 	progress := mpb.NewWithContext(gContext,
 		mpb.WithOutput(os.Stderr),
 		mpb.WithWaitGroup(&taskWG),
-		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool { return gQuiet }),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), decorationsDisabled),
 	)
 
 	taskWG.Add(len(tasks))
@@ -175,6 +177,8 @@ func execSyncTask(task task, id int, c chan taskStatus, resp *taskResponse, sem
 
 // asyncRequest if no response expected send nil
 func asyncRequest(cmd egoscale.AsyncCommand, msg string) (interface{}, error) {
+	defer suspendCommandTimeout()()
+
 	response := cs.Response(cmd)
 
 	if !gQuiet {
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+)
+
+// resourceReference represents a Compute resource referencing another
+// resource (e.g. a Compute instance member of an Anti-Affinity Group).
+type resourceReference struct {
+	Type string
+	Name string
+	ID   string
+	Zone string
+}
+
+func (r resourceReference) String() string {
+	return fmt.Sprintf("%s %q (%s, zone %s)", r.Type, r.Name, r.ID, r.Zone)
+}
+
+// resourceReferencesFinder looks up the Compute resources referencing a
+// resource, using the functions returning the list of IDs of this kind of
+// resource the Compute instances, Instance Pools and SKS Nodepools reference.
+type resourceReferencesFinder struct {
+	instanceIDs     func(*exov2.Instance) *[]string
+	instancePoolIDs func(*exov2.InstancePool) *[]string
+	nodepoolIDs     func(*exov2.SKSNodepool) *[]string
+}
+
+var (
+	antiAffinityGroupReferencesFinder = resourceReferencesFinder{
+		instanceIDs:     func(i *exov2.Instance) *[]string { return i.AntiAffinityGroupIDs },
+		instancePoolIDs: func(p *exov2.InstancePool) *[]string { return p.AntiAffinityGroupIDs },
+		nodepoolIDs:     func(np *exov2.SKSNodepool) *[]string { return np.AntiAffinityGroupIDs },
+	}
+
+	securityGroupReferencesFinder = resourceReferencesFinder{
+		instanceIDs:     func(i *exov2.Instance) *[]string { return i.SecurityGroupIDs },
+		instancePoolIDs: func(p *exov2.InstancePool) *[]string { return p.SecurityGroupIDs },
+		nodepoolIDs:     func(np *exov2.SKSNodepool) *[]string { return np.SecurityGroupIDs },
+	}
+
+	privateNetworkReferencesFinder = resourceReferencesFinder{
+		instanceIDs:     func(i *exov2.Instance) *[]string { return i.PrivateNetworkIDs },
+		instancePoolIDs: func(p *exov2.InstancePool) *[]string { return p.PrivateNetworkIDs },
+		nodepoolIDs:     func(np *exov2.SKSNodepool) *[]string { return np.PrivateNetworkIDs },
+	}
+)
+
+func containsID(ids *[]string, id string) bool {
+	if ids == nil {
+		return false
+	}
+	for _, v := range *ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the references to the resource id among the specified
+// resources of a zone. Compute instances and Instance Pools managed by
+// another resource (i.e. an Instance Pool or an SKS Nodepool) are not
+// reported, as their manager is.
+func (f resourceReferencesFinder) match(
+	zone, id string,
+	instances []*exov2.Instance,
+	instancePools []*exov2.InstancePool,
+	clusters []*exov2.SKSCluster,
+) []resourceReference {
+	refs := make([]resourceReference, 0)
+
+	for _, i := range instances {
+		if i.Manager == nil && containsID(f.instanceIDs(i), id) {
+			refs = append(refs, resourceReference{Type: "Compute instance", Name: *i.Name, ID: *i.ID, Zone: zone})
+		}
+	}
+
+	for _, p := range instancePools {
+		if p.Manager == nil && containsID(f.instancePoolIDs(p), id) {
+			refs = append(refs, resourceReference{Type: "Instance Pool", Name: *p.Name, ID: *p.ID, Zone: zone})
+		}
+	}
+
+	for _, c := range clusters {
+		for _, np := range c.Nodepools {
+			if containsID(f.nodepoolIDs(np), id) {
+				refs = append(refs, resourceReference{
+					Type: "SKS Nodepool",
+					Name: *c.Name + "/" + *np.Name,
+					ID:   *np.ID,
+					Zone: zone,
+				})
+			}
+		}
+	}
+
+	return refs
+}
+
+// find returns the resources referencing the resource id in the specified
+// zones.
+func (f resourceReferencesFinder) find(zones []string, id string) ([]resourceReference, error) {
+	var (
+		refs []resourceReference
+		mu   sync.Mutex
+	)
+
+	err := forEachZone(zones, func(zone string) error {
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+		instances, err := cs.ListInstances(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("unable to list Compute instances in zone %s: %s", zone, err)
+		}
+
+		instancePools, err := cs.ListInstancePools(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("unable to list Instance Pools in zone %s: %s", zone, err)
+		}
+
+		clusters, err := cs.ListSKSClusters(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("unable to list SKS clusters in zone %s: %s", zone, err)
+		}
+
+		zoneRefs := f.match(zone, id, instances, instancePools, clusters)
+
+		mu.Lock()
+		refs = append(refs, zoneRefs...)
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Zone != refs[j].Zone {
+			return refs[i].Zone < refs[j].Zone
+		}
+		if refs[i].Type != refs[j].Type {
+			return refs[i].Type < refs[j].Type
+		}
+		return refs[i].Name < refs[j].Name
+	})
+
+	return refs, nil
+}
+
+// formatResourceReferences returns the references as a bullet list, one
+// reference per line.
+func formatResourceReferences(refs []resourceReference) string {
+	lines := make([]string, len(refs))
+	for i, r := range refs {
+		lines[i] = "  - " + r.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkResourceReferences looks up the resources referencing the resource id
+// (a "kind" named "name") in the specified zones. If references exist, an
+// error listing them is returned unless force is true, in which case they are
+// only printed as a warning: the deletion is then attempted, and will only
+// succeed if the API permits it.
+func checkResourceReferences(f resourceReferencesFinder, zones []string, kind, name, id string, force bool) error {
+	var (
+		refs []resourceReference
+		err  error
+	)
+	err = decorateAsyncOperation(fmt.Sprintf("Looking up resources referencing %s %q...", kind, name), func() error {
+		refs, err = f.find(zones, id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("unable to look up resources referencing %s %q: %s", kind, name, err)
+	}
+
+	if len(refs) == 0 {
+		return nil
+	}
+
+	if !force {
+		return fmt.Errorf("%s %q is in use by:\n%s\nremove these references first, or use --force to attempt the deletion anyway",
+			kind, name, formatResourceReferences(refs))
+	}
+
+	fmt.Fprintf(os.Stderr, "WARNING: %s %q is in use by:\n%s\n", kind, name, formatResourceReferences(refs))
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resourceReferencesFinder_match(t *testing.T) {
+	var (
+		aagID   = "a0cbc63c-06c5-4bd0-8c68-3e6b6ca2fb0b"
+		otherID = "c3ab2b0c-7dd4-4e2a-8e7a-4d4b1a7d8c0e"
+		str     = func(s string) *string { return &s }
+		ids     = func(v ...string) *[]string { return &v }
+	)
+
+	instances := []*exov2.Instance{
+		{ID: str("i1"), Name: str("web1"), AntiAffinityGroupIDs: ids(aagID)},
+		{ID: str("i2"), Name: str("web2"), AntiAffinityGroupIDs: ids(otherID)},
+		{ID: str("i3"), Name: str("db1")},
+		{
+			ID:                   str("i4"),
+			Name:                 str("pool-member"),
+			AntiAffinityGroupIDs: ids(aagID),
+			Manager:              &exov2.InstanceManager{ID: "p1", Type: "instance-pool"},
+		},
+	}
+	instancePools := []*exov2.InstancePool{
+		{ID: str("p1"), Name: str("pool"), AntiAffinityGroupIDs: ids(otherID, aagID)},
+		{ID: str("p2"), Name: str("pool2"), SecurityGroupIDs: ids(aagID)},
+	}
+	clusters := []*exov2.SKSCluster{{
+		Name: str("k8s"),
+		Nodepools: []*exov2.SKSNodepool{
+			{ID: str("np1"), Name: str("workers"), AntiAffinityGroupIDs: ids(aagID)},
+		},
+	}}
+
+	require.Equal(t, []resourceReference{
+		{Type: "Compute instance", Name: "web1", ID: "i1", Zone: "ch-gva-2"},
+		{Type: "Instance Pool", Name: "pool", ID: "p1", Zone: "ch-gva-2"},
+		{Type: "SKS Nodepool", Name: "k8s/workers", ID: "np1", Zone: "ch-gva-2"},
+	}, antiAffinityGroupReferencesFinder.match("ch-gva-2", aagID, instances, instancePools, clusters))
+
+	require.Equal(t, []resourceReference{
+		{Type: "Instance Pool", Name: "pool2", ID: "p2", Zone: "ch-gva-2"},
+	}, securityGroupReferencesFinder.match("ch-gva-2", aagID, instances, instancePools, clusters))
+
+	require.Empty(t, privateNetworkReferencesFinder.match("ch-gva-2", aagID, instances, instancePools, clusters))
+}
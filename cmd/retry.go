@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiRetryBaseWait is the wait duration before the first retry of an API
+// request, doubled at each subsequent retry.
+const apiRetryBaseWait = 500 * time.Millisecond
+
+var (
+	// gAPIRetries is set by the global "--api-retries" flag (or the
+	// "APIRetries" config file key): the number of times idempotent API
+	// requests failing with a transient error are retried.
+	gAPIRetries = 2
+
+	// gAPIRetryMaxWait is set by the global "--api-retry-max-wait" flag (or
+	// the "APIRetryMaxWait" config file key): the maximum duration to wait
+	// between two attempts of an API request.
+	gAPIRetryMaxWait = 10 * time.Second
+)
+
+// apiV1ReadCommandPrefixes are the prefixes of the API V1 commands only
+// reading resources, which are safe to retry.
+var apiV1ReadCommandPrefixes = []string{"list", "get", "query"}
+
+// isRetryableAPIRequest returns true if the API request r is idempotent, i.e.
+// it can safely be retried. The API V1 performing every command using GET
+// requests, only the commands reading resources are considered idempotent.
+func isRetryableAPIRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+
+	command := strings.ToLower(r.URL.Query().Get("command"))
+	if command == "" {
+		return true
+	}
+
+	for _, prefix := range apiV1ReadCommandPrefixes {
+		if strings.HasPrefix(command, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTransientAPIError returns true if an API request failed with a transient
+// error (rate limiting or server-side error) and is worth retrying.
+func isTransientAPIError(res *http.Response, err error) bool {
+	if err != nil {
+		return false
+	}
+
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+}
+
+// apiRetryWait returns the duration to wait before retrying an API request
+// for the nth time (starting at 0): an exponential backoff with full jitter,
+// unless the API specified the duration to wait in the "Retry-After" header
+// of the response res. The duration is capped to gAPIRetryMaxWait.
+func apiRetryWait(n int, res *http.Response) time.Duration {
+	if res != nil {
+		if v, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && v >= 0 {
+			return minDuration(time.Duration(v)*time.Second, gAPIRetryMaxWait)
+		}
+	}
+
+	backoff := apiRetryBaseWait << uint(n)
+	if backoff <= 0 || backoff > gAPIRetryMaxWait {
+		backoff = gAPIRetryMaxWait
+	}
+
+	if backoff <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(backoff) + 1)) // nolint:gosec
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// setAPIRetryFromConfig sets the API requests retry policy from the config
+// file keys, unless overridden by the corresponding global flags.
+func setAPIRetryFromConfig(config *config) error {
+	if config.APIRetries != nil && !RootCmd.PersistentFlags().Changed("api-retries") {
+		gAPIRetries = *config.APIRetries
+	}
+
+	if config.APIRetryMaxWait != "" && !RootCmd.PersistentFlags().Changed("api-retry-max-wait") {
+		d, err := time.ParseDuration(config.APIRetryMaxWait)
+		if err != nil {
+			return fmt.Errorf("invalid APIRetryMaxWait config value %q: %s", config.APIRetryMaxWait, err)
+		}
+		gAPIRetryMaxWait = d
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testRetryTransport is an http.RoundTripper replying to the requests with
+// the specified status codes, in order, and the specified "Retry-After"
+// header value if any.
+type testRetryTransport struct {
+	statuses   []int
+	retryAfter string
+	requests   int
+}
+
+func (t *testRetryTransport) RoundTrip(_ *http.Request) (*http.Response, error) {
+	status := t.statuses[t.requests]
+	t.requests++
+
+	res := &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+	if t.retryAfter != "" {
+		res.Header.Set("Retry-After", t.retryAfter)
+	}
+
+	return res, nil
+}
+
+func Test_isRetryableAPIRequest(t *testing.T) {
+	for url, method := range map[string]string{
+		"https://api.example.net/v2/instance":                           http.MethodGet,
+		"https://api.example.net/v1/?command=listVirtualMachines":       http.MethodGet,
+		"https://api.example.net/v1/?command=queryAsyncJobResult&id=42": http.MethodGet,
+	} {
+		r, err := http.NewRequest(method, url, nil)
+		require.NoError(t, err)
+		require.True(t, isRetryableAPIRequest(r), url)
+	}
+
+	for url, method := range map[string]string{
+		"https://api.example.net/v2/instance":                        http.MethodPost,
+		"https://api.example.net/v1/?command=deployVirtualMachine":   http.MethodGet,
+		"https://api.example.net/v1/?command=destroyVirtualMachine":  http.MethodGet,
+		"https://api.example.net/v2/instance/42:reboot":              http.MethodPut,
+		"https://api.example.net/v1/?command=authorizeSecurityGroup": http.MethodGet,
+	} {
+		r, err := http.NewRequest(method, url, nil)
+		require.NoError(t, err)
+		require.False(t, isRetryableAPIRequest(r), url)
+	}
+}
+
+func Test_apiRetryWait(t *testing.T) {
+	defer func(v time.Duration) { gAPIRetryMaxWait = v }(gAPIRetryMaxWait)
+	gAPIRetryMaxWait = 2 * time.Second
+
+	for n := 0; n < 10; n++ {
+		wait := apiRetryWait(n, nil)
+		require.GreaterOrEqual(t, wait, time.Duration(0))
+		require.LessOrEqual(t, wait, gAPIRetryMaxWait)
+	}
+
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"1"}}}
+	require.Equal(t, time.Second, apiRetryWait(0, res))
+	res.Header.Set("Retry-After", "60")
+	require.Equal(t, gAPIRetryMaxWait, apiRetryWait(0, res))
+}
+
+func Test_cliRoundTripper_retry(t *testing.T) {
+	defer func(retries int, wait time.Duration) {
+		gAPIRetries, gAPIRetryMaxWait = retries, wait
+	}(gAPIRetries, gAPIRetryMaxWait)
+	gAPIRetries = 2
+	gAPIRetryMaxWait = time.Millisecond
+
+	t.Run("transient errors", func(t *testing.T) {
+		transport := &testRetryTransport{statuses: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusOK}}
+		r, _ := http.NewRequest(http.MethodGet, "https://api.example.net/v2/instance", nil)
+
+		res, err := newCLIRoundTripper(transport, nil).RoundTrip(r)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, 3, transport.requests)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		transport := &testRetryTransport{statuses: []int{503, 503, 503, 200}}
+		r, _ := http.NewRequest(http.MethodGet, "https://api.example.net/v2/instance", nil)
+
+		res, err := newCLIRoundTripper(transport, nil).RoundTrip(r)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		require.Equal(t, 3, transport.requests)
+	})
+
+	t.Run("non-idempotent request", func(t *testing.T) {
+		transport := &testRetryTransport{statuses: []int{503, 200}}
+		r, _ := http.NewRequest(http.MethodPost, "https://api.example.net/v2/instance", nil)
+
+		res, err := newCLIRoundTripper(transport, nil).RoundTrip(r)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		require.Equal(t, 1, transport.requests)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		gAPIRetryMaxWait = time.Minute
+
+		ctx, cancel := context.WithCancel(context.Background())
+		transport := &testRetryTransport{statuses: []int{503, 200}, retryAfter: "30"}
+		r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.net/v2/instance", nil)
+
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err := newCLIRoundTripper(transport, nil).RoundTrip(r)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Equal(t, 1, transport.requests)
+	})
+}
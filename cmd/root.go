@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
@@ -10,6 +13,8 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/exoscale/egoscale"
 	"github.com/spf13/cobra"
@@ -22,6 +27,11 @@ var gConfig *viper.Viper
 var gConfigFolder string
 var gConfigFilePath string
 
+// gConfigFileSelected is true if the configuration file has been selected
+// using the "--config" flag or the EXOSCALE_CONFIG environment variable,
+// rather than looked up in the default locations.
+var gConfigFileSelected bool
+
 //current Account information
 var gAccountName string
 var gCurrentAccount = &account{
@@ -71,6 +81,7 @@ var versionCmd = &cobra.Command{
 var (
 	gOutputFormat   string
 	gOutputTemplate string
+	gOutputQuery    string
 
 	gQuiet bool
 )
@@ -81,27 +92,158 @@ func Execute(version, commit string) {
 	gVersion = version
 	gCommit = commit
 
-	// trap Ctrl+C and call cancel on the context
-	ctx, cancel := context.WithCancel(context.Background())
+	hideExpiredDeprecations()
+
+	// Trap Ctrl+C/SIGTERM and cancel the context: in-flight API requests
+	// (performed using gContext) are aborted, and commands waiting for
+	// asynchronous operations report them before exiting. A second signal
+	// terminates the CLI immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	defer func() {
-		signal.Stop(c)
-		cancel()
-	}()
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(c)
 	go func() {
-		select {
-		case <-c:
-			cancel()
-		case <-ctx.Done():
-		}
+		<-c
+		<-c
+		os.Exit(exitCodeInterrupted)
 	}()
 
 	gContext = ctx
 
-	if err := RootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "error: %s\n", err)
-		os.Exit(1)
+	documentOutputTemplateFuncs(RootCmd)
+	enforceDryRunSupport(RootCmd)
+	registerAccountDefaultsHook(RootCmd)
+
+	cmd, err := RootCmd.ExecuteC()
+
+	if gProfileFormat != "" {
+		if err := gProfile.write(os.Stderr, gProfileFormat, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		}
+	}
+
+	if err != nil {
+		// The error is most likely caused by the interruption (e.g. "context
+		// canceled"), report the interrupted operations instead.
+		if ctx.Err() != nil {
+			exitInterrupted()
+		}
+
+		// In "--no-wait" mode, the command returned once its asynchronous
+		// operation has been submitted.
+		var noWaitErr noWaitError
+		if errors.As(err, &noWaitErr) {
+			fmt.Println(noWaitErr.id)
+			return
+		}
+
+		if commandTimedOut() {
+			err = errCommandTimedOut()
+		}
+
+		writeCommandError(os.Stderr, cmd, err)
+		os.Exit(exitCode(ctx, err))
+	}
+}
+
+// commandErrorOutput is the JSON representation of a command execution error.
+type commandErrorOutput struct {
+	Error     string `json:"error"`
+	Command   string `json:"command"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// apiRequestIDError is the error returned for a failed API request,
+// annotated with the ID of the request to be provided to the support (see
+// apiRequestIDTransport).
+type apiRequestIDError struct {
+	err       error
+	requestID string
+}
+
+func (e *apiRequestIDError) Error() string { return e.err.Error() }
+
+func (e *apiRequestIDError) Unwrap() error { return e.err }
+
+// withAPIRequestID annotates the error err with the API request ID
+// requestID, if any.
+func withAPIRequestID(err error, requestID string) error {
+	if err == nil || requestID == "" {
+		return err
+	}
+	return &apiRequestIDError{err: err, requestID: requestID}
+}
+
+// commandErrorJSON returns true if the command errors are to be reported as
+// JSON objects.
+func commandErrorJSON() bool {
+	return gOutputFormat == "json" || gOutputFormat == "ndjson"
+}
+
+// writeCommandError writes the error returned by the execution of the
+// command cmd to w, as a JSON object if a JSON output format has been
+// requested so that it can be parsed in automation, or as plain text
+// otherwise.
+func writeCommandError(w io.Writer, cmd *cobra.Command, err error) {
+	if commandErrorJSON() {
+		out := commandErrorOutput{Error: err.Error()}
+
+		var reqIDErr *apiRequestIDError
+		if errors.As(err, &reqIDErr) {
+			out.RequestID = reqIDErr.requestID
+		}
+
+		if cmd != nil {
+			out.Command = strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), RootCmd.Name()))
+		}
+
+		if j, jsonErr := json.Marshal(out); jsonErr == nil {
+			fmt.Fprintln(w, string(j))
+			return
+		}
+	}
+
+	var reqIDErr *apiRequestIDError
+	if errors.As(err, &reqIDErr) {
+		fmt.Fprintf(w, "error: %s (request ID: %s)\n", err, reqIDErr.requestID)
+		return
+	}
+
+	fmt.Fprintf(w, "error: %s\n", err)
+}
+
+// exitOnConfigError reports an error occurring while loading the CLI
+// configuration (see writeCommandError()) and exits the CLI.
+func exitOnConfigError(err error) {
+	writeCommandError(os.Stderr, nil, err)
+	os.Exit(1)
+}
+
+// exitCodeNotFound is the exit code of the CLI when a lookup command
+// doesn't find any matching resource.
+const exitCodeNotFound = 3
+
+// notFoundError is the error returned by lookup commands not finding any
+// matching resource, reported with the exitCodeNotFound exit code.
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) }
+
+// exitCode returns the process exit status matching the error returned by
+// a command execution.
+func exitCode(ctx context.Context, err error) int {
+	var nfErr notFoundError
+
+	switch {
+	case err == nil:
+		return 0
+	case ctx.Err() != nil:
+		return exitCodeInterrupted
+	case errors.As(err, &nfErr):
+		return exitCodeNotFound
+	default:
+		return 1
 	}
 }
 
@@ -110,16 +252,46 @@ func init() {
 
 	RootCmd.PersistentFlags().StringVarP(&gConfigFilePath, "config", "C", "", "Specify an alternate config file [env EXOSCALE_CONFIG]")
 	RootCmd.PersistentFlags().StringVarP(&gAccountName, "use-account", "A", "", "Account to use in config file [env EXOSCALE_ACCOUNT]")
-	RootCmd.PersistentFlags().StringVarP(&gOutputFormat, "output-format", "O", "", "Output format (table|json|text), see \"exo output --help\" for more information")
-	RootCmd.PersistentFlags().StringVar(&gOutputTemplate, "output-template", "", "Template to use if output format is \"text\"")
+	RootCmd.PersistentFlags().StringVarP(&gOutputFormat, "output-format", "O", "", "Output format (table|wide|json|ndjson|yaml|csv|text), see \"exo output --help\" for more information")
+	RootCmd.PersistentFlags().StringVar(&gOutputTemplate, "output-template", "", "Template (or \"name:NAME\" of a saved template) to use if output format is \"text\"")
+	RootCmd.PersistentFlags().StringVar(&gOutputTemplateFile, "output-template-file", "", "Path of the file containing the template to use if output format is \"text\" (\"-\" for standard input)")
+	RootCmd.PersistentFlags().StringSliceVar(&gOutputColumns, "output-columns", nil, "Columns to display (JSON field names) if output format is \"table\", \"wide\" or \"csv\"")
+	RootCmd.PersistentFlags().StringVar(&gOutputCSVSeparator, "csv-separator", defaultOutputCSVSeparator, "Separator of list values if output format is \"csv\"")
+	RootCmd.PersistentFlags().BoolVar(&gOutputNoHeader, "no-header", false, "Don't print the header row if output format is \"csv\"")
+	RootCmd.PersistentFlags().StringVar(&gOutputQuery, "query", "", "JMESPath expression to apply to the JSON output, see \"exo output --help\"")
 	RootCmd.PersistentFlags().BoolVarP(&gQuiet, "quiet", "Q", false, "Quiet mode (disable non-essential command output)")
+	RootCmd.PersistentFlags().BoolVar(&gNoWait, "no-wait", false, "Don't wait for asynchronous operations to complete, print the ID of the resource instead")
+	RootCmd.PersistentFlags().DurationVar(&gWaitTimeout, "wait-timeout", 0, "Maximum time to wait for asynchronous operations to complete (e.g. \"5m\")")
+	RootCmd.PersistentFlags().DurationVar(&gTimeout, "timeout", 0,
+		"Maximum command execution time, excluding the asynchronous operations waits (e.g. \"60s\") [config Timeout]")
+	RootCmd.PersistentFlags().IntVar(&gAPIRetries, "api-retries", gAPIRetries,
+		"Number of retries of read-only API requests failing with a transient error (0 to disable retries)")
+	RootCmd.PersistentFlags().DurationVar(&gAPIRetryMaxWait, "api-retry-max-wait", gAPIRetryMaxWait,
+		"Maximum time to wait between two attempts of an API request")
+	RootCmd.PersistentFlags().BoolVar(&gNoColor, "no-color", false,
+		"Disable the output colors and decorations (spinners, progress bars...) [env NO_COLOR]")
+	RootCmd.PersistentFlags().BoolVar(&gNonInteractive, "non-interactive", false, "Never prompt for the missing arguments")
+	RootCmd.PersistentFlags().BoolVar(&gForce, "force", false, "Don't prompt for confirmation of destructive operations [env EXO_FORCE]")
+	RootCmd.PersistentFlags().BoolVar(&gDryRun, "dry-run", false,
+		"Print the API request payload instead of performing the operation (supported commands only)")
+	RootCmd.PersistentFlags().StringVar(&gProfileFormat, "profile", "",
+		"Print a summary of the API calls performed on the standard error (use --profile=json for JSON output)")
+	RootCmd.PersistentFlags().Lookup("profile").NoOptDefVal = "text"
+	RootCmd.PersistentFlags().BoolVar(&gDebug, "debug", false,
+		"Trace the API requests performed on the standard error [env EXOSCALE_DEBUG]")
+	RootCmd.PersistentFlags().BoolVar(&gDebugBody, "debug-body", false,
+		"Trace the API requests including the requests/responses bodies (credentials are redacted), implies --debug")
+	RootCmd.PersistentFlags().StringVar(&gDebugFile, "debug-file", "",
+		"Write the API requests trace to the specified file instead of the standard error, implies --debug")
 	RootCmd.AddCommand(versionCmd)
 
+	cobra.OnInitialize(initColor)
+
 	// Don't attempt to load client configuration in testing mode.
 	// FIXME: stop using global configurations, see if this can be replaced
 	//   with rootCmd.PersistentPreRun or something.
 	if !strings.HasSuffix(os.Args[0], ".test") {
-		cobra.OnInitialize(initConfig, buildClient)
+		cobra.OnInitialize(initConfig, initDebugLog, buildClient, initCommandTimeout)
 	}
 }
 
@@ -138,71 +310,19 @@ func initConfig() {
 			panic(fmt.Sprintf("unknown flag '%s'", flag))
 		}
 
+		// Command-line flags take precedence over environment variables.
+		if pflag.Changed {
+			continue
+		}
+
 		if value, ok := os.LookupEnv(env); ok {
 			if err := pflag.Value.Set(value); err != nil {
-				log.Fatal(err)
+				exitOnConfigError(err)
 			}
 		}
 	}
 
-	endpointFromEnv := readFromEnv(
-		"EXOSCALE_API_ENDPOINT",
-		"EXOSCALE_COMPUTE_API_ENDPOINT",
-		"EXOSCALE_ENDPOINT",
-		"EXOSCALE_COMPUTE_ENDPOINT",
-		"CLOUDSTACK_ENDPOINT")
-
-	sosEndpointFromEnv := readFromEnv(
-		"EXOSCALE_STORAGE_API_ENDPOINT",
-		"EXOSCALE_SOS_ENDPOINT",
-	)
-
-	apiKeyFromEnv := readFromEnv(
-		"EXOSCALE_API_KEY",
-		"EXOSCALE_KEY",
-		"CLOUDSTACK_KEY",
-		"CLOUDSTACK_API_KEY",
-	)
-
-	apiSecretFromEnv := readFromEnv(
-		"EXOSCALE_API_SECRET",
-		"EXOSCALE_SECRET",
-		"EXOSCALE_SECRET_KEY",
-		"CLOUDSTACK_SECRET",
-		"CLOUDSTACK_SECRET_KEY",
-	)
-
-	apiEnvironmentFromEnv := readFromEnv("EXOSCALE_API_ENVIRONMENT")
-
-	if apiKeyFromEnv != "" && apiSecretFromEnv != "" {
-		gCurrentAccount.Name = "<environment variables>"
-		gConfigFilePath = "<environment variables>"
-		gCurrentAccount.Account = "unknown"
-		gCurrentAccount.Key = apiKeyFromEnv
-		gCurrentAccount.Secret = apiSecretFromEnv
-
-		if apiEnvironmentFromEnv != "" {
-			gCurrentAccount.Environment = apiEnvironmentFromEnv
-		}
-		if endpointFromEnv != "" {
-			gCurrentAccount.Endpoint = endpointFromEnv
-		}
-		if sosEndpointFromEnv != "" {
-			gCurrentAccount.SosEndpoint = sosEndpointFromEnv
-		}
-		gCurrentAccount.DNSEndpoint = buildDNSAPIEndpoint(gCurrentAccount.Endpoint)
-
-		gAllAccount = &config{
-			DefaultAccount: gCurrentAccount.Name,
-			Accounts:       []account{*gCurrentAccount},
-		}
-
-		buildClient()
-
-		return
-	}
-
-	config := &config{}
+	env := readAccountEnv()
 
 	usr, err := user.Current()
 	if err != nil {
@@ -218,7 +338,7 @@ func initConfig() {
 
 	cfgdir, err := os.UserConfigDir()
 	if err != nil {
-		log.Fatalf("could not find configuration directory: %s", err)
+		exitOnConfigError(fmt.Errorf("could not find configuration directory: %s", err))
 	}
 	gConfigFolder = path.Join(cfgdir, "exoscale")
 
@@ -228,8 +348,25 @@ func initConfig() {
 	}
 
 	if gConfigFilePath != "" {
+		gConfigFileSelected = true
+
 		// Use config file from the flag.
+		// Like the shell, "~" expands to $HOME rather than the user database
+		// home directory.
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = usr.HomeDir
+		}
+		if gConfigFilePath, err = expandConfigFilePath(gConfigFilePath, homeDir); err != nil {
+			exitOnConfigError(fmt.Errorf("invalid configuration file path: %s", err))
+		}
+		gConfigFolder = filepath.Dir(gConfigFilePath)
 		gConfig.SetConfigFile(gConfigFilePath)
+
+		// Files without extension (e.g. injected secrets) are TOML files.
+		if filepath.Ext(gConfigFilePath) == "" {
+			gConfig.SetConfigType("toml")
+		}
 	} else {
 		gConfig.SetConfigName("exoscale")
 		gConfig.AddConfigPath(gConfigFolder)
@@ -239,19 +376,92 @@ func initConfig() {
 		gConfig.AddConfigPath(".")
 	}
 
-	nonCredentialCmds := []string{"config", "version", "status"}
+	if !initConfigFile(env) {
+		if !env.hasCredentials() {
+			return
+		}
+
+		// No usable configuration file: the account is entirely defined
+		// by the environment variables.
+		gConfigFilePath = envAccountName
+		gCurrentAccount = &account{Name: envAccountName, Account: "unknown"}
+		gAllAccount = &config{DefaultAccount: envAccountName}
+	} else if env.isSet() {
+		// Override a copy of the configured account, so that the
+		// environment variables never end up written to the configuration
+		// file.
+		acc := *gCurrentAccount
+		gCurrentAccount = &acc
+	}
+
+	// Precedence of the account settings: command flags (applied by the
+	// commands themselves), environment variables, configuration file,
+	// defaults.
+	env.apply(gCurrentAccount)
+	setAccountDefaults(gCurrentAccount)
+
+	// The API secret kept in a secret store is retrieved once and for all,
+	// the commands not requiring credentials remaining usable without it
+	// (e.g. to reconfigure the account).
+	if err := gCurrentAccount.resolveSecret(); err != nil && !isNonCredentialCmd(nonCredentialCmds...) {
+		exitOnConfigError(err)
+	}
+
+	if gCurrentAccount.Name == envAccountName {
+		gAllAccount.Accounts = []account{*gCurrentAccount}
+	} else {
+		gAllAccount.snapshot(gConfig.GetString("defaultAccount"))
+	}
+
+	if gOutputFormat == "" {
+		gOutputFormat = defaultOutputFormat
+	}
+}
+
+// expandConfigFilePath returns the absolute path of the configuration file
+// path p, expanding a leading "~" to the home directory homeDir (the shell
+// doesn't expand it in environment variables or "--config=~/..." flags).
+func expandConfigFilePath(p, homeDir string) (string, error) {
+	if p == "~" || strings.HasPrefix(p, "~/") || strings.HasPrefix(p, "~"+string(filepath.Separator)) {
+		p = filepath.Join(homeDir, p[1:])
+	}
+
+	return filepath.Abs(p)
+}
+
+// initConfigFile reads the configuration file and selects the current
+// account, returning false if no account could be loaded. If the API
+// credentials are set in the environment variables, the configuration file
+// is optional.
+func initConfigFile(env accountEnv) bool {
+	config := &config{}
 
 	if err := gConfig.ReadInConfig(); err != nil {
+		// An invalid configuration file is never ignored, as it would be
+		// overwritten when saving the configuration.
+		if _, ok := err.(viper.ConfigParseError); ok {
+			exitOnConfigError(fmt.Errorf("unable to parse configuration file %q: %s",
+				gConfig.ConfigFileUsed(), strings.TrimPrefix(err.Error(), "While parsing config: ")))
+		}
+
+		if env.hasCredentials() {
+			return false
+		}
+
 		if isNonCredentialCmd(nonCredentialCmds...) {
 			ignoreClientBuild = true
-			return
+			return false
 		}
 
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			log.Fatal(`error: the exo CLI must be configured before usage, please run "exo config"`)
+			exitOnConfigError(errors.New(`the exo CLI must be configured before usage, please run "exo init"`))
 		}
 
-		log.Fatal(err)
+		if os.IsNotExist(err) {
+			exitOnConfigError(fmt.Errorf("configuration file %q not found", gConfig.ConfigFileUsed()))
+		}
+
+		exitOnConfigError(err)
 	}
 
 	// All the stored data (e.g. ssh keys) will be put next to the config file.
@@ -259,27 +469,42 @@ func initConfig() {
 	gConfigFolder = filepath.Dir(gConfigFilePath)
 
 	if err := gConfig.Unmarshal(config); err != nil {
-		log.Fatal(fmt.Errorf("couldn't read config: %s", err))
+		exitOnConfigError(fmt.Errorf("couldn't read config: %s", err))
+	}
+
+	outputFormatFlag := gOutputFormat
+	if gOutputFormat == "" {
+		gOutputFormat = config.DefaultOutputFormat
+	}
+
+	if err := setAPIRetryFromConfig(config); err != nil {
+		exitOnConfigError(err)
+	}
+
+	if err := setTimeoutFromConfig(config); err != nil {
+		exitOnConfigError(err)
 	}
 
 	if len(config.Accounts) == 0 {
+		if env.hasCredentials() {
+			return false
+		}
+
 		if isNonCredentialCmd(nonCredentialCmds...) {
 			ignoreClientBuild = true
-			return
+			return false
 		}
 
-		log.Fatalf("no accounts were found into %q", gConfig.ConfigFileUsed())
-		return
+		exitOnConfigError(fmt.Errorf("no accounts were found into %q", gConfig.ConfigFileUsed()))
+		return false
 	}
 
 	if config.DefaultAccount == "" && gAccountName == "" {
-		log.Fatalf("default account not defined")
-	}
-
-	if gOutputFormat == "" {
-		if gOutputFormat = config.DefaultOutputFormat; gOutputFormat == "" {
-			gOutputFormat = defaultOutputFormat
+		if env.hasCredentials() {
+			return false
 		}
+
+		exitOnConfigError(errors.New("default account not defined"))
 	}
 
 	if gAccountName == "" {
@@ -297,47 +522,78 @@ func initConfig() {
 	}
 
 	if gCurrentAccount.Name == "" {
-		log.Fatalf("error: could't find any configured account named %q", gAccountName)
+		exitOnConfigError(fmt.Errorf("could't find any configured account named %q", gAccountName))
 	}
 
-	if gCurrentAccount.Endpoint == "" {
-		if gCurrentAccount.ComputeEndpoint != "" {
-			gCurrentAccount.Endpoint = gCurrentAccount.ComputeEndpoint
-		} else {
-			gCurrentAccount.Endpoint = defaultEndpoint
-		}
+	// The account default output format prevails over the global one.
+	if outputFormatFlag == "" && gCurrentAccount.DefaultOutputFormat != "" {
+		gOutputFormat = gCurrentAccount.DefaultOutputFormat
 	}
 
-	if gCurrentAccount.Environment == "" {
-		gCurrentAccount.Environment = defaultEnvironment
-	}
+	return true
+}
 
-	if gCurrentAccount.DefaultZone == "" {
-		gCurrentAccount.DefaultZone = defaultZone
-	}
+// registerAccountDefaultsHook sets a persistent pre-run hook applying the
+// current account defaults (see applyAccountDefaults()) on the command cmd.
+// Cobra only running the closest persistent pre-run hook of the command
+// executed, the descendant commands defining their own hook are wrapped too.
+func registerAccountDefaultsHook(cmd *cobra.Command) {
+	if cmd.PersistentPreRun != nil || cmd.PersistentPreRunE != nil || !cmd.HasParent() {
+		preRun, preRunE := cmd.PersistentPreRun, cmd.PersistentPreRunE
+
+		cmd.PersistentPreRun = nil
+		cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+			if err := applyAccountDefaults(c); err != nil {
+				return err
+			}
+
+			switch {
+			case preRunE != nil:
+				return preRunE(c, args)
+			case preRun != nil:
+				preRun(c, args)
+			}
 
-	if gCurrentAccount.DNSEndpoint == "" {
-		gCurrentAccount.DNSEndpoint = buildDNSAPIEndpoint(gCurrentAccount.Endpoint)
+			return nil
+		}
 	}
 
-	if gCurrentAccount.DefaultTemplate == "" {
-		gCurrentAccount.DefaultTemplate = defaultTemplate
+	for _, c := range cmd.Commands() {
+		registerAccountDefaultsHook(c)
 	}
+}
+
+// applyAccountDefaults sets the flags of the command cmd not specified on
+// the command line to the current account defaults: the flags of the
+// account "defaults" table are set as if they were specified on the command
+// line, and the template flag of the creation commands (i.e. defaulting to
+// the built-in default template) is set to the account default template.
+func applyAccountDefaults(cmd *cobra.Command) error {
+	for name, value := range gCurrentAccount.Defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
 
-	if gCurrentAccount.SosEndpoint == "" {
-		gCurrentAccount.SosEndpoint = defaultSosEndpoint
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("invalid default value %q of flag --%s of account %q: %s",
+				value, name, gCurrentAccount.Name, err)
+		}
 	}
 
-	if gCurrentAccount.RunstatusEndpoint == "" {
-		gCurrentAccount.RunstatusEndpoint = defaultRunstatusEndpoint
+	if flag := cmd.Flags().Lookup("template"); flag != nil && !flag.Changed && flag.DefValue == defaultTemplate &&
+		gCurrentAccount.DefaultTemplate != "" {
+		if err := flag.Value.Set(gCurrentAccount.DefaultTemplate); err != nil {
+			return err
+		}
 	}
 
-	gCurrentAccount.Endpoint = strings.TrimRight(gCurrentAccount.Endpoint, "/")
-	gCurrentAccount.DNSEndpoint = strings.TrimRight(gCurrentAccount.DNSEndpoint, "/")
-	gCurrentAccount.SosEndpoint = strings.TrimRight(gCurrentAccount.SosEndpoint, "/")
-	gCurrentAccount.RunstatusEndpoint = strings.TrimRight(gCurrentAccount.RunstatusEndpoint, "/")
+	return nil
 }
 
+// nonCredentialCmds are the commands usable without API credentials.
+var nonCredentialCmds = []string{"config", "init", "output", "version", "status", cobra.ShellCompRequestCmd}
+
 func isNonCredentialCmd(cmds ...string) bool {
 	for _, cmd := range cmds {
 		if getCmdPosition(cmd) == 1 {
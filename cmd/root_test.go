@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeCommandError(t *testing.T) {
+	outputFormat := gOutputFormat
+	defer func() { gOutputFormat = outputFormat }()
+
+	cmd, _, err := RootCmd.Find([]string{"config", "list"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	gOutputFormat = "table"
+	writeCommandError(&buf, cmd, errors.New(`no account "bob"`))
+	require.Equal(t, "error: no account \"bob\"\n", buf.String())
+
+	buf.Reset()
+	gOutputFormat = "json"
+	writeCommandError(&buf, cmd, errors.New(`no account "bob"`))
+	require.JSONEq(t, `{"error":"no account \"bob\"","command":"config list"}`, buf.String())
+
+	buf.Reset()
+	writeCommandError(&buf, nil, errors.New("oops"))
+	require.JSONEq(t, `{"error":"oops","command":""}`, buf.String())
+}
+
+func Test_applyAccountDefaults(t *testing.T) {
+	savedAccount := gCurrentAccount
+	t.Cleanup(func() { gCurrentAccount = savedAccount })
+
+	gCurrentAccount = &account{
+		Name:            "prod",
+		DefaultTemplate: "Linux Debian 11 64-bit",
+		Defaults:        map[string]string{"zone": "de-fra-1", "disk-size": "20", "unknown": "x"},
+	}
+
+	newCmd := func(templateDefault string, args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("zone", "", "")
+		cmd.Flags().Int64("disk-size", 10, "")
+		cmd.Flags().String("template", templateDefault, "")
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	// Explicit flags always win.
+	cmd := newCmd(defaultTemplate, "--zone", "ch-gva-2")
+	require.NoError(t, applyAccountDefaults(cmd))
+	zone, _ := cmd.Flags().GetString("zone")
+	require.Equal(t, "ch-gva-2", zone)
+	diskSize, _ := cmd.Flags().GetInt64("disk-size")
+	require.Equal(t, int64(20), diskSize)
+	template, _ := cmd.Flags().GetString("template")
+	require.Equal(t, "Linux Debian 11 64-bit", template)
+
+	// The default template only applies to the creation commands.
+	cmd = newCmd("")
+	require.NoError(t, applyAccountDefaults(cmd))
+	template, _ = cmd.Flags().GetString("template")
+	require.Equal(t, "", template)
+	zone, _ = cmd.Flags().GetString("zone")
+	require.Equal(t, "de-fra-1", zone)
+
+	gCurrentAccount.Defaults = map[string]string{"disk-size": "big"}
+	require.Error(t, applyAccountDefaults(newCmd("")))
+}
+
+func Test_registerAccountDefaultsHook(t *testing.T) {
+	savedAccount := gCurrentAccount
+	t.Cleanup(func() { gCurrentAccount = savedAccount })
+
+	gCurrentAccount = &account{Defaults: map[string]string{"zone": "de-fra-1"}}
+
+	var zone string
+	ownHookCalled := false
+
+	root := &cobra.Command{Use: "root"}
+	parent := &cobra.Command{
+		Use:              "parent",
+		PersistentPreRun: func(_ *cobra.Command, _ []string) { ownHookCalled = true },
+	}
+	child := &cobra.Command{
+		Use: "child",
+		Run: func(cmd *cobra.Command, _ []string) { zone, _ = cmd.Flags().GetString("zone") },
+	}
+	child.Flags().String("zone", "", "")
+	parent.AddCommand(child)
+	root.AddCommand(parent)
+
+	registerAccountDefaultsHook(root)
+
+	root.SetArgs([]string{"parent", "child"})
+	require.NoError(t, root.Execute())
+	require.True(t, ownHookCalled)
+	require.Equal(t, "de-fra-1", zone)
+}
+
+func Test_initConfig_accountDefaultOutputFormat(t *testing.T) {
+	savedAccount, savedAllAccount, savedConfig, savedAccountName := gCurrentAccount, gAllAccount, gConfig, gAccountName
+	savedConfigFilePath, savedConfigFolder, savedOutputFormat := gConfigFilePath, gConfigFolder, gOutputFormat
+	t.Cleanup(func() {
+		gCurrentAccount, gAllAccount, gConfig, gAccountName = savedAccount, savedAllAccount, savedConfig, savedAccountName
+		gConfigFilePath, gConfigFolder, gOutputFormat = savedConfigFilePath, savedConfigFolder, savedOutputFormat
+	})
+
+	setAccountEnv(t, nil)
+
+	configFile := filepath.Join(t.TempDir(), "exoscale.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`defaultaccount = "prod"
+defaultOutputFormat = "yaml"
+
+[[accounts]]
+name = "prod"
+key = "EXOconfig"
+secret = "secret"
+defaultOutputFormat = "json"
+
+[[accounts]]
+name = "dev"
+key = "EXOdev"
+secret = "secret"
+`), 0o600))
+
+	RootCmd.Flags().AddFlagSet(RootCmd.PersistentFlags())
+
+	for _, tt := range []struct {
+		accountName, outputFormatFlag, expected string
+	}{
+		{"prod", "", "json"},
+		{"prod", "table", "table"},
+		{"dev", "", "yaml"},
+	} {
+		gConfig = viper.New()
+		gConfigFilePath = configFile
+		gAccountName = tt.accountName
+		gCurrentAccount = &account{}
+		gOutputFormat = tt.outputFormatFlag
+
+		initConfig()
+
+		require.Equal(t, tt.expected, gOutputFormat, "account %s, flag %q", tt.accountName, tt.outputFormatFlag)
+	}
+}
+
+func Test_expandConfigFilePath(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	home := filepath.Join(string(filepath.Separator)+"home", "alice")
+
+	for _, tt := range []struct {
+		path, expected string
+	}{
+		{"~", home},
+		{"~/exoscale.toml", filepath.Join(home, "exoscale.toml")},
+		{"~/.config/exoscale/prod.toml", filepath.Join(home, ".config", "exoscale", "prod.toml")},
+		{"exoscale.toml", filepath.Join(wd, "exoscale.toml")},
+		{"../ci/exoscale.toml", filepath.Join(filepath.Dir(wd), "ci", "exoscale.toml")},
+		{"~ci/exoscale.toml", filepath.Join(wd, "~ci", "exoscale.toml")},
+		{filepath.Join(home, "exoscale.toml"), filepath.Join(home, "exoscale.toml")},
+	} {
+		actual, err := expandConfigFilePath(tt.path, home)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, actual, tt.path)
+	}
+}
+
+func Test_initConfig_configFile(t *testing.T) {
+	savedAccount, savedAllAccount, savedConfig, savedAccountName := gCurrentAccount, gAllAccount, gConfig, gAccountName
+	savedConfigFilePath, savedConfigFolder, savedOutputFormat := gConfigFilePath, gConfigFolder, gOutputFormat
+	t.Cleanup(func() {
+		gCurrentAccount, gAllAccount, gConfig, gAccountName = savedAccount, savedAllAccount, savedConfig, savedAccountName
+		gConfigFilePath, gConfigFolder, gOutputFormat = savedConfigFilePath, savedConfigFolder, savedOutputFormat
+	})
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+
+	writeConfig := func(name, accountName string) string {
+		configFile := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(configFile), 0o700))
+		require.NoError(t, os.WriteFile(configFile, []byte(`defaultaccount = "`+accountName+`"
+
+[[accounts]]
+name = "`+accountName+`"
+key = "EXO`+accountName+`"
+secret = "secret"
+`), 0o600))
+		return configFile
+	}
+	envConfigFile := writeConfig(filepath.Join("env", "exoscale.toml"), "env")
+	flagConfigFile := writeConfig(filepath.Join("flag", "exoscale"), "flag")
+
+	RootCmd.Flags().AddFlagSet(RootCmd.PersistentFlags())
+	configFlag := RootCmd.Flags().Lookup("config")
+	t.Cleanup(func() { configFlag.Changed = false })
+
+	load := func() {
+		gConfig = viper.New()
+		gAccountName = ""
+		gCurrentAccount = &account{}
+		gOutputFormat = ""
+
+		initConfig()
+	}
+
+	// Relative paths are resolved from the current directory.
+	setAccountEnv(t, map[string]string{"EXOSCALE_CONFIG": filepath.Join("env", "exoscale.toml")})
+	gConfigFilePath = ""
+	load()
+	require.Equal(t, "env", gCurrentAccount.Name)
+	require.True(t, filepath.IsAbs(gConfigFilePath))
+	require.Equal(t, envConfigFile, gConfigFilePath)
+	require.Equal(t, filepath.Dir(envConfigFile), gConfigFolder)
+
+	// The "--config" flag takes precedence over the environment variable,
+	// and files without extension are read as TOML files.
+	require.NoError(t, RootCmd.Flags().Set("config", filepath.Join("flag", "exoscale")))
+	load()
+	require.Equal(t, "flag", gCurrentAccount.Name)
+	require.Equal(t, flagConfigFile, gConfigFilePath)
+
+	// Saving the configuration writes the selected file.
+	require.NoError(t, saveConfig(gConfig.ConfigFileUsed(), &config{Accounts: []account{{
+		Name:        "added",
+		Key:         "EXOadded",
+		Secret:      "secret",
+		Environment: defaultEnvironment,
+		DefaultZone: defaultZone,
+	}}}))
+	content, err := os.ReadFile(flagConfigFile)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `name = "added"`)
+	content, err = os.ReadFile(envConfigFile)
+	require.NoError(t, err)
+	require.NotContains(t, string(content), `name = "added"`)
+}
+
+// Test_initConfig_configFileParseError loads an invalid configuration file in
+// a sub-process, as the CLI exits immediately.
+func Test_initConfig_configFileParseError(t *testing.T) {
+	if configFile := os.Getenv("EXO_TEST_CONFIG_FILE"); configFile != "" {
+		_ = os.Unsetenv("EXOSCALE_CONFIG")
+		RootCmd.Flags().AddFlagSet(RootCmd.PersistentFlags())
+		gConfig = viper.New()
+		gConfigFilePath = configFile
+		initConfig()
+		os.Exit(0)
+	}
+
+	configFile := filepath.Join(t.TempDir(), "exoscale.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte("[[accounts]\nname = \"prod\"\n"), 0o600))
+
+	cmd := exec.Command(os.Args[0], "-test.run=^Test_initConfig_configFileParseError$")
+	cmd.Env = append(os.Environ(), "EXO_TEST_CONFIG_FILE="+configFile)
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected command to fail, got: %v", err)
+	require.Contains(t, stderr.String(), fmt.Sprintf("error: unable to parse configuration file %q: ", configFile))
+}
+
+func Test_apiRequestIDTransport(t *testing.T) {
+	savedOutputFormat := gOutputFormat
+	t.Cleanup(func() { gOutputFormat = savedOutputFormat })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "4f0c3c7e-9b1d-4d6a-8f43-1c2b3a4d5e6f")
+		if r.URL.Path == "/v2/template/x" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		http.Error(w, `{"message":"invalid type"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &apiRequestIDTransport{
+		next: exoapi.NewAPIErrorHandlerMiddleware(newCLIRoundTripper(http.DefaultTransport, nil)),
+	}}
+
+	// Only the errors of failed API requests carry a request ID.
+	res, err := client.Get(server.URL + "/v2/template/x")
+	require.NoError(t, err)
+	_ = res.Body.Close()
+
+	_, err = client.Get(server.URL + "/v2/instance/x:scale")
+	require.Error(t, err)
+	var reqIDErr *apiRequestIDError
+	require.True(t, errors.As(err, &reqIDErr))
+	require.Equal(t, "4f0c3c7e-9b1d-4d6a-8f43-1c2b3a4d5e6f", reqIDErr.requestID)
+	require.True(t, errors.Is(err, exoapi.ErrInvalidRequest))
+
+	cmd, _, findErr := RootCmd.Find([]string{"config", "list"})
+	require.NoError(t, findErr)
+
+	var buf bytes.Buffer
+	gOutputFormat = "table"
+	writeCommandError(&buf, cmd, err)
+	require.Equal(t, fmt.Sprintf("error: %s (request ID: 4f0c3c7e-9b1d-4d6a-8f43-1c2b3a4d5e6f)\n", err), buf.String())
+
+	buf.Reset()
+	gOutputFormat = "json"
+	writeCommandError(&buf, cmd, err)
+	require.JSONEq(t,
+		fmt.Sprintf(`{"error":%q,"command":"config list","request_id":"4f0c3c7e-9b1d-4d6a-8f43-1c2b3a4d5e6f"}`, err),
+		buf.String())
+
+	// An error unrelated to a failed API request must not be annotated, even
+	// if a previous API request failed.
+	buf.Reset()
+	writeCommandError(&buf, cmd, notFoundError("instance not found"))
+	require.JSONEq(t, `{"error":"instance not found","command":"config list"}`, buf.String())
+
+	require.Equal(t, exitCodeNotFound,
+		exitCode(context.Background(), withAPIRequestID(notFoundError("instance not found"), "x")))
+	require.Nil(t, withAPIRequestID(nil, "x"))
+	require.Equal(t, "oops", withAPIRequestID(errors.New("oops"), "").Error())
+}
+
+func Test_globalFlagsNotShadowed(t *testing.T) {
+	// The commands local "--force" and "--dry-run" flags have the same
+	// meaning as the global ones.
+	equivalent := map[string]bool{"dry-run": true, "force": true}
+
+	var visit func(cmd *cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if g := RootCmd.PersistentFlags().Lookup(f.Name); g != nil && !equivalent[f.Name] {
+				require.Same(t, g, f, "%q flag shadows the global flag", cmd.CommandPath()+" --"+f.Name)
+			}
+		})
+		for _, c := range cmd.Commands() {
+			visit(c)
+		}
+	}
+
+	for _, c := range RootCmd.Commands() {
+		visit(c)
+	}
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// secretStoreFile is the default secret store: the API secret is stored
+	// in plaintext in the configuration file.
+	secretStoreFile = "file"
+
+	// secretStoreKeychain is the secret store backed by the OS keychain
+	// (macOS Keychain, Windows Credential Manager or Secret Service).
+	secretStoreKeychain = "keychain"
+
+	// secretStoreService is the service name the API secrets are stored
+	// under in the OS keychain.
+	secretStoreService = "exoscale-cli"
+)
+
+var secretStores = []string{secretStoreFile, secretStoreKeychain}
+
+// errSecretNotFound is returned by secret stores when no secret is stored
+// for an account.
+var errSecretNotFound = errors.New("secret not found")
+
+// secretStore represents a backend storing the API secrets outside of the
+// configuration file, the secrets being identified by account name.
+type secretStore interface {
+	get(name string) (string, error)
+	set(name, secret string) error
+	delete(name string) error
+}
+
+// newSecretStore returns the secret store backend named store.
+var newSecretStore = func(store string) (secretStore, error) {
+	switch store {
+	case secretStoreKeychain:
+		s, err := newKeychainSecretStore()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"no OS keychain available (%s), please use the %q secret store instead", err, secretStoreFile)
+		}
+		return s, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported secret store %q (supported: %s)",
+			store, strings.Join(secretStores, ", "))
+	}
+}
+
+// validateSecretStore returns the secret store reference to record in the
+// configuration file for the secret store named store, the file secret store
+// being the absence of reference. An error is returned if the secret store
+// is not available on this system.
+func validateSecretStore(store string) (string, error) {
+	if store == "" || store == secretStoreFile {
+		return "", nil
+	}
+
+	if _, err := newSecretStore(store); err != nil {
+		return "", err
+	}
+
+	return store, nil
+}
+
+// storeSecret stores the API secret of the account in its secret store, if
+// it is not stored in the configuration file.
+func (a account) storeSecret() error {
+	if a.SecretStore == "" {
+		return nil
+	}
+
+	s, err := newSecretStore(a.SecretStore)
+	if err != nil {
+		return err
+	}
+
+	if err := s.set(a.Name, a.Secret); err != nil {
+		return fmt.Errorf("unable to store the API secret of account %q in the %s: %s", a.Name, a.SecretStore, err)
+	}
+
+	return nil
+}
+
+// resolveSecret retrieves the API secret of the account from its secret
+// store, if it is not stored in the configuration file and hasn't already
+// been retrieved.
+func (a *account) resolveSecret() error {
+	if a.SecretStore == "" || a.Secret != "" {
+		return nil
+	}
+
+	s, err := newSecretStore(a.SecretStore)
+	if err != nil {
+		return err
+	}
+
+	secret, err := s.get(a.Name)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the API secret of account %q from the %s: %s", a.Name, a.SecretStore, err)
+	}
+	a.Secret = secret
+
+	return nil
+}
+
+// deleteSecret deletes the API secret of the account from its secret store,
+// if it is not stored in the configuration file.
+func (a account) deleteSecret() error {
+	if a.SecretStore == "" {
+		return nil
+	}
+
+	s, err := newSecretStore(a.SecretStore)
+	if err != nil {
+		return err
+	}
+
+	if err := s.delete(a.Name); err != nil && !errors.Is(err, errSecretNotFound) {
+		return fmt.Errorf("unable to delete the API secret of account %q from the %s: %s", a.Name, a.SecretStore, err)
+	}
+
+	return nil
+}
+
+// secretStoreCommand is the function running the commands of the secret
+// stores backed by an external command, overridden in tests.
+var secretStoreCommand = func(stdin string, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", &secretStoreCommandError{err: err, msg: msg}
+		}
+		return "", &secretStoreCommandError{err: err}
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// secretStoreCommandError represents the failure of a secret store command.
+type secretStoreCommandError struct {
+	err error
+	msg string
+}
+
+func (e *secretStoreCommandError) Error() string {
+	if e.msg != "" {
+		return fmt.Sprintf("%s: %s", e.err, e.msg)
+	}
+	return e.err.Error()
+}
+
+// exitCode returns the exit code of the failed command, or -1 if it could
+// not be run.
+func (e *secretStoreCommandError) exitCode() int {
+	var exitErr *exec.ExitError
+	if errors.As(e.err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
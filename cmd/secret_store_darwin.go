@@ -0,0 +1,67 @@
+//go:build darwin
+// +build darwin
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// securityErrItemNotFound is the exit code of the macOS "security" command
+// when the requested keychain item doesn't exist.
+const securityErrItemNotFound = 44
+
+// keychainSecretStore stores the API secrets in the macOS Keychain using the
+// "security" command.
+type keychainSecretStore struct{}
+
+func newKeychainSecretStore() (secretStore, error) {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil, errors.New(`"security" command not found`)
+	}
+
+	return &keychainSecretStore{}, nil
+}
+
+func (s *keychainSecretStore) get(name string) (string, error) {
+	secret, err := secretStoreCommand("", "security",
+		"find-generic-password", "-s", secretStoreService, "-a", name, "-w")
+	return secret, keychainError(err)
+}
+
+func (s *keychainSecretStore) set(name, secret string) error {
+	// The secret is passed through the standard input of the "security"
+	// interactive mode, as command arguments are visible to other users.
+	_, err := secretStoreCommand(
+		fmt.Sprintf("add-generic-password -U -s %s -a %s -l %s -w %s\n",
+			keychainQuote(secretStoreService),
+			keychainQuote(name),
+			keychainQuote("Exoscale CLI account "+name),
+			keychainQuote(secret)),
+		"security", "-i")
+	return keychainError(err)
+}
+
+func (s *keychainSecretStore) delete(name string) error {
+	_, err := secretStoreCommand("", "security",
+		"delete-generic-password", "-s", secretStoreService, "-a", name)
+	return keychainError(err)
+}
+
+// keychainError returns errSecretNotFound if err reports a missing keychain
+// item, err otherwise.
+func keychainError(err error) error {
+	var cmdErr *secretStoreCommandError
+	if errors.As(err, &cmdErr) && cmdErr.exitCode() == securityErrItemNotFound {
+		return errSecretNotFound
+	}
+	return err
+}
+
+// keychainQuote quotes s for the "security" interactive mode.
+func keychainQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// memSecretStore is an in-memory secret store.
+type memSecretStore map[string]string
+
+func (s memSecretStore) get(name string) (string, error) {
+	secret, ok := s[name]
+	if !ok {
+		return "", errSecretNotFound
+	}
+	return secret, nil
+}
+
+func (s memSecretStore) set(name, secret string) error {
+	s[name] = secret
+	return nil
+}
+
+func (s memSecretStore) delete(name string) error {
+	if _, ok := s[name]; !ok {
+		return errSecretNotFound
+	}
+	delete(s, name)
+	return nil
+}
+
+// useMemSecretStore replaces the keychain secret store by an in-memory
+// secret store for the duration of the test t.
+func useMemSecretStore(t *testing.T) memSecretStore {
+	store := make(memSecretStore)
+
+	savedNewSecretStore := newSecretStore
+	t.Cleanup(func() { newSecretStore = savedNewSecretStore })
+
+	newSecretStore = func(name string) (secretStore, error) {
+		if name != secretStoreKeychain {
+			return savedNewSecretStore(name)
+		}
+		return store, nil
+	}
+
+	return store
+}
+
+func Test_validateSecretStore(t *testing.T) {
+	useMemSecretStore(t)
+
+	store, err := validateSecretStore("")
+	require.NoError(t, err)
+	require.Equal(t, "", store)
+
+	store, err = validateSecretStore(secretStoreFile)
+	require.NoError(t, err)
+	require.Equal(t, "", store)
+
+	store, err = validateSecretStore(secretStoreKeychain)
+	require.NoError(t, err)
+	require.Equal(t, secretStoreKeychain, store)
+
+	_, err = validateSecretStore("vault")
+	require.EqualError(t, err, `unsupported secret store "vault" (supported: file, keychain)`)
+}
+
+func Test_account_secretStore(t *testing.T) {
+	store := useMemSecretStore(t)
+
+	// Secrets stored in the configuration file are left alone.
+	acc := account{Name: "file", Secret: "s3cr3t"}
+	require.NoError(t, acc.storeSecret())
+	require.NoError(t, acc.resolveSecret())
+	require.NoError(t, acc.deleteSecret())
+	require.Empty(t, store)
+
+	acc = account{Name: "prod", Secret: "s3cr3t", SecretStore: secretStoreKeychain}
+	require.NoError(t, acc.storeSecret())
+	require.Equal(t, memSecretStore{"prod": "s3cr3t"}, store)
+
+	acc.Secret = ""
+	require.NoError(t, acc.resolveSecret())
+	require.Equal(t, "s3cr3t", acc.Secret)
+	require.Equal(t, "s3cr3t", account{Name: "prod", SecretStore: secretStoreKeychain}.APISecret())
+
+	require.NoError(t, acc.deleteSecret())
+	require.Empty(t, store)
+
+	// Deleting a missing secret is not an error.
+	require.NoError(t, acc.deleteSecret())
+
+	acc.Secret = ""
+	require.EqualError(t, acc.resolveSecret(),
+		`unable to retrieve the API secret of account "prod" from the keychain: secret not found`)
+}
+
+func Test_saveAccount_secretStore(t *testing.T) {
+	savedAccount, savedAllAccount, savedConfig, savedAccountName := gCurrentAccount, gAllAccount, gConfig, gAccountName
+	savedConfigFilePath, savedConfigFolder, savedOutputFormat := gConfigFilePath, gConfigFolder, gOutputFormat
+	t.Cleanup(func() {
+		gCurrentAccount, gAllAccount, gConfig, gAccountName = savedAccount, savedAllAccount, savedConfig, savedAccountName
+		gConfigFilePath, gConfigFolder, gOutputFormat = savedConfigFilePath, savedConfigFolder, savedOutputFormat
+	})
+
+	setAccountEnv(t, nil)
+	store := useMemSecretStore(t)
+
+	configFile := filepath.Join(t.TempDir(), "exoscale.toml")
+	require.NoError(t, os.WriteFile(configFile, nil, 0o600))
+
+	gConfig = viper.New()
+	gConfig.SetConfigFile(configFile)
+	gConfigFilePath = configFile
+	gAllAccount = nil
+
+	require.NoError(t, saveAccount(&account{
+		Name:        "prod",
+		Key:         "EXOabc",
+		Secret:      "s3cr3t",
+		Environment: defaultEnvironment,
+		DefaultZone: defaultZone,
+		SecretStore: secretStoreKeychain,
+	}, true, false))
+	require.Equal(t, memSecretStore{"prod": "s3cr3t"}, store)
+
+	// Only the reference to the secret store is written to the
+	// configuration file.
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `secretStore = "keychain"`)
+	require.NotContains(t, string(content), "s3cr3t")
+
+	// The secret is retrieved from the secret store when loading the
+	// account.
+	RootCmd.Flags().AddFlagSet(RootCmd.PersistentFlags())
+
+	gConfig = viper.New()
+	gAccountName = ""
+	gCurrentAccount = &account{}
+	gOutputFormat = ""
+
+	initConfig()
+
+	require.Equal(t, "prod", gCurrentAccount.Name)
+	require.Equal(t, "s3cr3t", gCurrentAccount.Secret)
+
+	// Saving the configuration again doesn't write the retrieved secret.
+	require.NoError(t, saveConfig(configFile, nil))
+	content, err = os.ReadFile(configFile)
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "s3cr3t")
+}
@@ -0,0 +1,64 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// keychainSecretStore stores the API secrets using the Secret Service D-Bus
+// API (GNOME Keyring, KWallet...) through the "secret-tool" command.
+type keychainSecretStore struct{}
+
+func newKeychainSecretStore() (secretStore, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, errors.New(`"secret-tool" command not found`)
+	}
+
+	// Without D-Bus session bus (e.g. on headless servers) there is no
+	// Secret Service to talk to.
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+		if runtimeDir == "" {
+			return nil, errors.New("no D-Bus session bus")
+		}
+		if _, err := os.Stat(filepath.Join(runtimeDir, "bus")); err != nil {
+			return nil, errors.New("no D-Bus session bus")
+		}
+	}
+
+	return &keychainSecretStore{}, nil
+}
+
+func (s *keychainSecretStore) get(name string) (string, error) {
+	secret, err := secretStoreCommand("", "secret-tool",
+		"lookup", "service", secretStoreService, "account", name)
+	if err != nil {
+		// "secret-tool lookup" fails silently if there is no such secret.
+		var cmdErr *secretStoreCommandError
+		if errors.As(err, &cmdErr) && cmdErr.msg == "" {
+			return "", errSecretNotFound
+		}
+		return "", err
+	}
+
+	return secret, nil
+}
+
+func (s *keychainSecretStore) set(name, secret string) error {
+	// The secret is read by "secret-tool" from its standard input, as
+	// command arguments are visible to other users.
+	_, err := secretStoreCommand(secret, "secret-tool",
+		"store", "--label=Exoscale CLI account "+name, "service", secretStoreService, "account", name)
+	return err
+}
+
+func (s *keychainSecretStore) delete(name string) error {
+	_, err := secretStoreCommand("", "secret-tool",
+		"clear", "service", secretStoreService, "account", name)
+	return err
+}
@@ -0,0 +1,143 @@
+//go:build windows
+// +build windows
+
+package cmd
+
+import (
+	"errors"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+
+	// credMaxBlobSize is the maximum size of a credential blob.
+	credMaxBlobSize = 5 * 512
+)
+
+var (
+	modAdvapi32     = windows.NewLazySystemDLL("advapi32.dll")
+	procCredReadW   = modAdvapi32.NewProc("CredReadW")
+	procCredWriteW  = modAdvapi32.NewProc("CredWriteW")
+	procCredDeleteW = modAdvapi32.NewProc("CredDeleteW")
+	procCredFree    = modAdvapi32.NewProc("CredFree")
+)
+
+// credential is the CREDENTIALW structure of the Windows Credential
+// Management API.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// keychainSecretStore stores the API secrets in the Windows Credential
+// Manager, as generic credentials.
+type keychainSecretStore struct{}
+
+func newKeychainSecretStore() (secretStore, error) {
+	if err := procCredReadW.Find(); err != nil {
+		return nil, err
+	}
+
+	return &keychainSecretStore{}, nil
+}
+
+func (s *keychainSecretStore) get(name string) (string, error) {
+	target, err := windows.UTF16PtrFromString(credTarget(name))
+	if err != nil {
+		return "", err
+	}
+
+	var cred *credential
+	r, _, err := procCredReadW.Call(
+		uintptr(unsafe.Pointer(target)),
+		credTypeGeneric,
+		0,
+		uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		return "", credError(err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred))) // nolint:errcheck
+
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+
+	blob := (*[credMaxBlobSize]byte)(unsafe.Pointer(cred.CredentialBlob))[:cred.CredentialBlobSize:cred.CredentialBlobSize]
+	return string(blob), nil
+}
+
+func (s *keychainSecretStore) set(name, secret string) error {
+	if len(secret) > credMaxBlobSize {
+		return errors.New("secret too long")
+	}
+
+	target, err := windows.UTF16PtrFromString(credTarget(name))
+	if err != nil {
+		return err
+	}
+
+	userName, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           userName,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	if r, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return credError(err)
+	}
+
+	return nil
+}
+
+func (s *keychainSecretStore) delete(name string) error {
+	target, err := windows.UTF16PtrFromString(credTarget(name))
+	if err != nil {
+		return err
+	}
+
+	if r, _, err := procCredDeleteW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); r == 0 {
+		return credError(err)
+	}
+
+	return nil
+}
+
+// credTarget returns the Credential Manager target name of the API secret
+// of the account name.
+func credTarget(name string) string {
+	return secretStoreService + ":" + name
+}
+
+// credError returns errSecretNotFound if err reports a missing credential,
+// err otherwise.
+func credError(err error) error {
+	if errors.Is(err, windows.ERROR_NOT_FOUND) {
+		return errSecretNotFound
+	}
+	return err
+}
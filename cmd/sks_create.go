@@ -15,6 +15,15 @@ var (
 	defaultSKSClusterServiceLevel = "pro"
 	sksClusterAddonExoscaleCCM    = "exoscale-cloud-controller"
 	sksClusterAddonMetricsServer  = "metrics-server"
+	sksClusterCNINone             = "none"
+
+	// sksClusterAddons are the SKS cluster add-ons supported by the API,
+	// all deployed by default.
+	sksClusterAddons = []string{sksClusterAddonExoscaleCCM, sksClusterAddonMetricsServer}
+
+	// sksClusterCNIs are the Container Network Interface plugins supported
+	// by the API (see the SksClusterCni enum of the API specification).
+	sksClusterCNIs = []string{"calico"}
 )
 
 type sksCreateCmd struct {
@@ -22,7 +31,9 @@ type sksCreateCmd struct {
 
 	Name string `cli-arg:"#" cli-usage:"NAME"`
 
+	AddOns                     []string          `cli-flag:"addon" cli-usage:"SKS cluster add-on to deploy in the cluster control plane (can be specified multiple times, default: all add-ons)"`
 	AutoUpgrade                bool              `cli-usage:"enable automatic upgrading of the SKS cluster control plane Kubernetes version"`
+	CNI                        string            `cli-flag:"cni" cli-usage:"Container Network Interface plugin to deploy in the cluster control plane (calico|none)"`
 	Description                string            `cli-usage:"SKS cluster description"`
 	KubernetesVersion          string            `cli-usage:"SKS cluster control plane Kubernetes version"`
 	Labels                     map[string]string `cli-flag:"label" cli-usage:"SKS cluster label (format: key=value)"`
@@ -48,6 +59,8 @@ func (c *sksCreateCmd) cmdAliases() []string { return gCreateAlias }
 
 func (c *sksCreateCmd) cmdShort() string { return "Create an SKS cluster" }
 
+func (c *sksCreateCmd) cmdDryRunSupported() {}
+
 func (c *sksCreateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command creates an SKS cluster.
 
@@ -63,7 +76,14 @@ If you do not want to use a Cloud Controller Manager, add the
 "--no-exoscale-ccm" option to the command. This cannot be changed once the
 cluster has been created.
 
+The cluster add-ons deployed can be selected using the "--addon" flag
+(supported add-ons: %s), and the Container Network Interface plugin using
+the "--cni" flag (supported plugins: %s, or "none" to deploy no CNI plugin).
+The CNI plugin cannot be changed once the cluster has been created.
+
 Supported output template annotations: %s`,
+		strings.Join(sksClusterAddons, ", "),
+		strings.Join(sksClusterCNIs, ", "),
 		strings.Join(outputterTemplateAnnotations(&sksShowOutput{}), ", "))
 }
 
@@ -72,10 +92,75 @@ func (c *sksCreateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	return cliCommandDefaultPreRun(c, cmd, args)
 }
 
-func (c *sksCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
+// clusterAddOnsAndCNI returns the add-ons and CNI plugin to request the SKS
+// cluster creation with, according to the command flags.
+func (c *sksCreateCmd) clusterAddOnsAndCNI(cmd *cobra.Command) (*[]string, *string, error) {
+	var (
+		addOns *[]string
+		cni    *string
+	)
+
+	switch {
+	case cmd.Flags().Changed(mustCLICommandFlagName(c, &c.CNI)):
+		if c.NoCNI {
+			return nil, nil, errors.New("--cni and --no-cni flags are mutually exclusive")
+		}
+		if c.CNI != sksClusterCNINone {
+			if !isInList(sksClusterCNIs, c.CNI) {
+				return nil, nil, fmt.Errorf(
+					"unsupported CNI plugin %q (supported plugins: %s, none)",
+					c.CNI,
+					strings.Join(sksClusterCNIs, ", "))
+			}
+			cni = &c.CNI
+		}
+	case !c.NoCNI:
+		cni = &defaultSKSClusterCNI
+	}
+
+	list := make([]string, 0)
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.AddOns)) {
+		if c.NoExoscaleCCM || c.NoMetricsServer {
+			return nil, nil, errors.New(
+				"--addon flag cannot be used with --no-exoscale-ccm and --no-metrics-server flags")
+		}
+		for _, addOn := range c.AddOns {
+			if !isInList(sksClusterAddons, addOn) {
+				return nil, nil, fmt.Errorf(
+					"unsupported add-on %q (supported add-ons: %s)",
+					addOn,
+					strings.Join(sksClusterAddons, ", "))
+			}
+			if !isInList(list, addOn) {
+				list = append(list, addOn)
+			}
+		}
+	} else {
+		for _, addOn := range sksClusterAddons {
+			if (addOn == sksClusterAddonExoscaleCCM && c.NoExoscaleCCM) ||
+				(addOn == sksClusterAddonMetricsServer && c.NoMetricsServer) {
+				continue
+			}
+			list = append(list, addOn)
+		}
+	}
+	if len(list) > 0 {
+		addOns = &list
+	}
+
+	return addOns, cni, nil
+}
+
+func (c *sksCreateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	addOns, cni, err := c.clusterAddOnsAndCNI(cmd)
+	if err != nil {
+		return err
+	}
+
 	cluster := &egoscale.SKSCluster{
+		AddOns:      addOns,
 		AutoUpgrade: &c.AutoUpgrade,
-		CNI:         &defaultSKSClusterCNI,
+		CNI:         cni,
 		Description: func() (v *string) {
 			if c.Description != "" {
 				v = &c.Description
@@ -95,32 +180,6 @@ func (c *sksCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
-	if c.NoCNI {
-		cluster.CNI = nil
-	}
-
-	addOns := map[string]struct{}{
-		sksClusterAddonExoscaleCCM:   {},
-		sksClusterAddonMetricsServer: {},
-	}
-	cluster.AddOns = func() (v *[]string) {
-		if c.NoExoscaleCCM {
-			delete(addOns, sksClusterAddonExoscaleCCM)
-		}
-		if c.NoMetricsServer {
-			delete(addOns, sksClusterAddonMetricsServer)
-		}
-
-		if len(addOns) > 0 {
-			list := make([]string, 0)
-			for k := range addOns {
-				list = append(list, k)
-			}
-			v = &list
-		}
-		return
-	}()
-
 	if *cluster.Version == "latest" {
 		versions, err := cs.ListSKSClusterVersions(ctx)
 		if err != nil || len(versions) == 0 {
@@ -132,16 +191,9 @@ func (c *sksCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		cluster.Version = &versions[0]
 	}
 
-	var err error
-	decorateAsyncOperation(fmt.Sprintf("Creating SKS cluster %q...", *cluster.Name), func() {
-		cluster, err = cs.CreateSKSCluster(ctx, c.Zone, cluster)
-	})
-	if err != nil {
-		return err
-	}
-
+	var nodepool *egoscale.SKSNodepool
 	if c.NodepoolSize > 0 {
-		nodepool := &egoscale.SKSNodepool{
+		nodepool = &egoscale.SKSNodepool{
 			Description: func() (v *string) {
 				if c.NodepoolDescription != "" {
 					v = &c.NodepoolDescription
@@ -219,13 +271,32 @@ func (c *sksCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 			}
 			nodepool.SecurityGroupIDs = &nodepoolSecurityGroupIDs
 		}
+	}
+
+	if gDryRun {
+		return outputDryRun(map[string]interface{}{"cluster": cluster, "nodepool": nodepool})
+	}
+
+	steps := []asyncStep{{
+		name: fmt.Sprintf("Creating SKS cluster %q", *cluster.Name),
+		run: func() (err error) {
+			cluster, err = cs.CreateSKSCluster(ctx, c.Zone, cluster)
+			return
+		},
+	}}
 
-		decorateAsyncOperation(fmt.Sprintf("Adding Nodepool %q...", *nodepool.Name), func() {
-			_, err = cluster.AddNodepool(ctx, nodepool)
+	if nodepool != nil {
+		steps = append(steps, asyncStep{
+			name: fmt.Sprintf("Adding Nodepool %q", *nodepool.Name),
+			run: func() (err error) {
+				_, err = cluster.AddNodepool(ctx, nodepool)
+				return
+			},
 		})
-		if err != nil {
-			return err
-		}
+	}
+
+	if err := decorateAsyncSteps(fmt.Sprintf("Creating SKS cluster %q...", *cluster.Name), steps...); err != nil {
+		return err
 	}
 
 	if !gQuiet {
@@ -237,6 +308,7 @@ func (c *sksCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 func init() {
 	cobra.CheckErr(registerCLICommand(sksCmd, &sksCreateCmd{
+		CNI:                  defaultSKSClusterCNI,
 		KubernetesVersion:    "latest",
 		NodepoolDiskSize:     50,
 		NodepoolInstanceType: defaultServiceOffering,
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sksCreateCmd_clusterAddOnsAndCNI(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantAddOns []string
+		wantCNI    string
+		wantErr    bool
+	}{
+		{
+			name:       "defaults",
+			wantAddOns: []string{sksClusterAddonExoscaleCCM, sksClusterAddonMetricsServer},
+			wantCNI:    "calico",
+		},
+		{
+			name:       "explicit CNI",
+			args:       []string{"--cni", "calico"},
+			wantAddOns: []string{sksClusterAddonExoscaleCCM, sksClusterAddonMetricsServer},
+			wantCNI:    "calico",
+		},
+		{
+			name:       "no CNI",
+			args:       []string{"--cni", "none"},
+			wantAddOns: []string{sksClusterAddonExoscaleCCM, sksClusterAddonMetricsServer},
+		},
+		{
+			name:       "legacy no CNI",
+			args:       []string{"--no-cni"},
+			wantAddOns: []string{sksClusterAddonExoscaleCCM, sksClusterAddonMetricsServer},
+		},
+		{
+			name:       "explicit add-ons",
+			args:       []string{"--addon", sksClusterAddonMetricsServer, "--addon", sksClusterAddonMetricsServer},
+			wantAddOns: []string{sksClusterAddonMetricsServer},
+			wantCNI:    "calico",
+		},
+		{
+			name:    "legacy no add-ons",
+			args:    []string{"--no-exoscale-ccm", "--no-metrics-server"},
+			wantCNI: "calico",
+		},
+		{name: "unsupported CNI", args: []string{"--cni", "flannel"}, wantErr: true},
+		{name: "CNI not supported by the API", args: []string{"--cni", "cilium"}, wantErr: true},
+		{name: "unsupported add-on", args: []string{"--addon", "dashboard"}, wantErr: true},
+		{name: "conflicting CNI flags", args: []string{"--cni", "calico", "--no-cni"}, wantErr: true},
+		{
+			name:    "conflicting add-on flags",
+			args:    []string{"--addon", sksClusterAddonMetricsServer, "--no-exoscale-ccm"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &sksCreateCmd{CNI: defaultSKSClusterCNI}
+			root := &cobra.Command{Use: "root"}
+			require.NoError(t, registerCLICommand(root, c))
+			cmd, _, err := root.Find([]string{"create"})
+			require.NoError(t, err)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+			require.NoError(t, cliCommandDefaultPreRun(c, cmd, []string{"test"}))
+
+			addOns, cni, err := c.clusterAddOnsAndCNI(cmd)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.wantAddOns == nil {
+				require.Nil(t, addOns)
+			} else {
+				require.Equal(t, tt.wantAddOns, *addOns)
+			}
+
+			if tt.wantCNI == "" {
+				require.Nil(t, cni)
+			} else {
+				require.Equal(t, tt.wantCNI, *cni)
+			}
+		})
+	}
+}
@@ -11,7 +11,7 @@ import (
 type sksDeleteCmd struct {
 	_ bool `cli-cmd:"delete"`
 
-	Cluster string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Cluster string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"sks-cluster"`
 
 	Force           bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
 	DeleteNodepools bool   `cli-flag:"nodepools" cli-short:"n" cli-usage:"delete existing Nodepools before deleting the SKS cluster"`
@@ -40,21 +40,23 @@ func (c *sksDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	if len(cluster.Nodepools) > 0 {
 		nodepoolsRemaining := len(cluster.Nodepools)
 
+		if c.DeleteNodepools && gNoWait {
+			return errNoWaitUnsupported("when deleting the Nodepools")
+		}
+
 		if c.DeleteNodepools {
 			for _, nodepool := range cluster.Nodepools {
 				nodepool := nodepool
 
-				if !c.Force {
-					if !askQuestion(fmt.Sprintf(
-						"Are you sure you want to delete Nodepool %q?",
-						*nodepool.Name),
-					) {
-						continue
-					}
+				if !askQuestion(fmt.Sprintf(
+					"Are you sure you want to delete Nodepool %q?",
+					*nodepool.Name),
+				) {
+					continue
 				}
 
-				decorateAsyncOperation(fmt.Sprintf("Deleting Nodepool %q...", *nodepool.Name), func() {
-					err = cluster.DeleteNodepool(ctx, nodepool)
+				err = decorateAsyncOperation(fmt.Sprintf("Deleting Nodepool %q...", *nodepool.Name), func() error {
+					return cluster.DeleteNodepool(ctx, nodepool)
 				})
 				if err != nil {
 					return err
@@ -69,14 +71,12 @@ func (c *sksDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		}
 	}
 
-	if !c.Force {
-		if !askQuestion(fmt.Sprintf("Are you sure you want to delete SKS cluster %q?", *cluster.Name)) {
-			return nil
-		}
+	if !askQuestion(fmt.Sprintf("Are you sure you want to delete SKS cluster %q?", *cluster.Name)) {
+		return nil
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Deleting SKS cluster %q...", *cluster.Name), func() {
-		err = cs.DeleteSKSCluster(ctx, c.Zone, *cluster.ID)
+	err = decorateAsyncOperation(fmt.Sprintf("Deleting SKS cluster %q...", *cluster.Name), func() error {
+		return cs.DeleteSKSCluster(ctx, c.Zone, *cluster.ID)
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/exoscale/cli/table"
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cobra"
+)
+
+// sksDescribeParallelism is the maximum number of concurrent API requests
+// performed to retrieve the Nodepools members in "--deep" mode.
+const sksDescribeParallelism = 10
+
+type sksDescribeInstanceOutput struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+type sksDescribeNodepoolOutput struct {
+	ID             string                      `json:"id"`
+	Name           string                      `json:"name"`
+	Version        string                      `json:"version"`
+	Size           int64                       `json:"size"`
+	State          string                      `json:"state"`
+	InstancePoolID string                      `json:"instance_pool_id"`
+	Instances      []sksDescribeInstanceOutput `json:"instances,omitempty"`
+}
+
+type sksDescribeOutput struct {
+	ID               string                      `json:"id"`
+	Name             string                      `json:"name"`
+	Description      string                      `json:"description"`
+	CreationDate     string                      `json:"creation_date"`
+	Zone             string                      `json:"zone"`
+	Endpoint         string                      `json:"endpoint"`
+	Version          string                      `json:"version"`
+	LatestVersion    string                      `json:"latest_version"`
+	UpgradeAvailable bool                        `json:"upgrade_available"`
+	ServiceLevel     string                      `json:"service_level"`
+	CNI              string                      `json:"cni"`
+	AddOns           []string                    `json:"addons"`
+	State            string                      `json:"state"`
+	Labels           map[string]string           `json:"labels"`
+	Nodepools        []sksDescribeNodepoolOutput `json:"nodepools"`
+}
+
+func (o *sksDescribeOutput) toJSON() { outputJSON(o) }
+func (o *sksDescribeOutput) toText() { outputText(o) }
+func (o *sksDescribeOutput) toTable() {
+	t := table.NewTable(os.Stdout)
+	t.SetHeader([]string{"SKS Cluster"})
+	t.Append([]string{"ID", o.ID})
+	t.Append([]string{"Name", o.Name})
+	t.Append([]string{"Description", o.Description})
+	t.Append([]string{"Zone", o.Zone})
+	t.Append([]string{"Creation Date", o.CreationDate})
+	t.Append([]string{"Endpoint", o.Endpoint})
+	t.Append([]string{"Version", o.Version})
+	t.Append([]string{"Latest Version", func() string {
+		if o.UpgradeAvailable {
+			return fmt.Sprintf("%s (upgrade available)", o.LatestVersion)
+		}
+		return defaultString(&o.LatestVersion, "n/a")
+	}()})
+	t.Append([]string{"Service Level", o.ServiceLevel})
+	t.Append([]string{"CNI", o.CNI})
+	t.Append([]string{"Add-Ons", strings.Join(o.AddOns, "\n")})
+	t.Append([]string{"State", o.State})
+	t.Append([]string{"Labels", func() string {
+		if len(o.Labels) > 0 {
+			labels := make([]string, 0, len(o.Labels))
+			for k, v := range o.Labels {
+				labels = append(labels, fmt.Sprintf("%s:%s", k, v))
+			}
+			sort.Strings(labels)
+			return strings.Join(labels, "\n")
+		}
+		return "n/a"
+	}()})
+	t.Render()
+
+	if len(o.Nodepools) == 0 {
+		return
+	}
+
+	fmt.Println()
+	t = table.NewTable(os.Stdout)
+	t.SetHeader([]string{"Nodepool", "ID", "Version", "Size", "State"})
+	for _, np := range o.Nodepools {
+		t.Append([]string{np.Name, np.ID, np.Version, fmt.Sprint(np.Size), np.State})
+	}
+	t.Render()
+
+	deep := false
+	for _, np := range o.Nodepools {
+		deep = deep || len(np.Instances) > 0
+	}
+	if !deep {
+		return
+	}
+
+	fmt.Println()
+	t = table.NewTable(os.Stdout)
+	t.SetHeader([]string{"Nodepool", "Instance", "ID", "State"})
+	for _, np := range o.Nodepools {
+		for _, i := range np.Instances {
+			t.Append([]string{np.Name, i.Name, i.ID, i.State})
+		}
+	}
+	t.Render()
+}
+
+type sksDescribeCmd struct {
+	_ bool `cli-cmd:"describe"`
+
+	Cluster string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"sks-cluster"`
+
+	Deep bool   `cli-usage:"retrieve the state of the Nodepools member instances"`
+	Zone string `cli-short:"z" cli-usage:"SKS cluster zone"`
+}
+
+func (c *sksDescribeCmd) cmdAliases() []string { return nil }
+
+func (c *sksDescribeCmd) cmdShort() string {
+	return "Describe an SKS cluster along with its Nodepools"
+}
+
+func (c *sksDescribeCmd) cmdLong() string {
+	return fmt.Sprintf(`This command describes an SKS cluster in a single document: the cluster
+details, its add-ons, the latest Kubernetes version available for upgrade
+and its Nodepools with their size and state. Using the "--deep" flag, the
+state of the Nodepools member instances is also reported.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&sksDescribeOutput{}), ", "))
+}
+
+func (c *sksDescribeCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *sksDescribeCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	return output(describeSKSCluster(c.Zone, c.Cluster, c.Deep))
+}
+
+func describeSKSCluster(zone, c string, deep bool) (outputter, error) {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+	cluster, err := cs.FindSKSCluster(ctx, zone, c)
+	if err != nil {
+		return nil, err
+	}
+
+	out := sksDescribeOutput{
+		AddOns: func() (v []string) {
+			if cluster.AddOns != nil {
+				v = *cluster.AddOns
+			}
+			return
+		}(),
+		CNI:          defaultString(cluster.CNI, "-"),
+		CreationDate: cluster.CreatedAt.String(),
+		Description:  defaultString(cluster.Description, ""),
+		Endpoint:     *cluster.Endpoint,
+		ID:           *cluster.ID,
+		Labels: func() (v map[string]string) {
+			if cluster.Labels != nil {
+				v = *cluster.Labels
+			}
+			return
+		}(),
+		Name:         *cluster.Name,
+		Nodepools:    make([]sksDescribeNodepoolOutput, len(cluster.Nodepools)),
+		ServiceLevel: *cluster.ServiceLevel,
+		State:        *cluster.State,
+		Version:      *cluster.Version,
+		Zone:         zone,
+	}
+
+	for i, np := range cluster.Nodepools {
+		out.Nodepools[i] = sksDescribeNodepoolOutput{
+			ID:             *np.ID,
+			Name:           *np.Name,
+			Version:        defaultString(np.Version, ""),
+			Size:           defaultInt64(np.Size, 0),
+			State:          defaultString(np.State, ""),
+			InstancePoolID: defaultString(np.InstancePoolID, ""),
+		}
+	}
+
+	// The versions and the Nodepools Instance Pools are retrieved
+	// concurrently.
+	meg := new(multierror.Group)
+
+	meg.Go(func() error {
+		versions, err := cs.ListSKSClusterVersions(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to list SKS cluster versions: %w", err)
+		}
+		out.LatestVersion = sksLatestVersion(versions)
+		out.UpgradeAvailable = out.LatestVersion != "" && !versionAtLeast(out.Version, out.LatestVersion)
+		return nil
+	})
+
+	instanceIDs := make([][]string, len(cluster.Nodepools))
+	if deep {
+		for i, np := range cluster.Nodepools {
+			i, np := i, np
+			if np.InstancePoolID == nil {
+				continue
+			}
+
+			meg.Go(func() error {
+				instancePool, err := cs.GetInstancePool(ctx, zone, *np.InstancePoolID)
+				if err != nil {
+					return fmt.Errorf("unable to retrieve Nodepool %q Instance Pool: %w", *np.Name, err)
+				}
+				if instancePool.InstanceIDs != nil {
+					instanceIDs[i] = *instancePool.InstanceIDs
+				}
+				return nil
+			})
+		}
+	}
+
+	if err := meg.Wait().ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	if deep {
+		describeSKSNodepoolsInstances(out.Nodepools, instanceIDs, func(id string) (*egoscale.Instance, error) {
+			return cs.GetInstance(ctx, zone, id)
+		})
+	}
+
+	return &out, nil
+}
+
+// describeSKSNodepoolsInstances fills the Nodepools member instances from
+// their IDs using the getInstance function, which is called concurrently.
+// Instances that cannot be retrieved are reported with their error as state.
+func describeSKSNodepoolsInstances(
+	nodepools []sksDescribeNodepoolOutput,
+	instanceIDs [][]string,
+	getInstance func(string) (*egoscale.Instance, error),
+) {
+	var (
+		wg      sync.WaitGroup
+		workers = make(chan struct{}, sksDescribeParallelism)
+	)
+
+	for i := range nodepools {
+		nodepools[i].Instances = make([]sksDescribeInstanceOutput, len(instanceIDs[i]))
+
+		for j, id := range instanceIDs[i] {
+			res := &nodepools[i].Instances[j]
+			res.ID = id
+
+			wg.Add(1)
+			workers <- struct{}{}
+			go func(id string) {
+				defer func() { <-workers; wg.Done() }()
+
+				instance, err := getInstance(id)
+				if err != nil {
+					res.State = fmt.Sprintf("error: %s", err)
+					return
+				}
+				res.Name = defaultString(instance.Name, "")
+				res.State = defaultString(instance.State, "")
+			}(id)
+		}
+	}
+
+	wg.Wait()
+}
+
+// sksLatestVersion returns the latest version of the specified SKS cluster
+// versions.
+func sksLatestVersion(versions []string) string {
+	latest := ""
+
+	for _, v := range versions {
+		if latest == "" || !versionAtLeast(latest, v) {
+			latest = v
+		}
+	}
+
+	return latest
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(sksCmd, &sksDescribeCmd{}))
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sksLatestVersion(t *testing.T) {
+	require.Equal(t, "1.21.10", sksLatestVersion([]string{"1.20.4", "1.21.10", "1.21.9", "1.19.12"}))
+	require.Equal(t, "", sksLatestVersion(nil))
+}
+
+func Test_describeSKSNodepoolsInstances(t *testing.T) {
+	var calls int32
+
+	nodepools := []sksDescribeNodepoolOutput{{Name: "a"}, {Name: "b"}}
+	describeSKSNodepoolsInstances(
+		nodepools,
+		[][]string{{"i1", "i2"}, {"i3"}},
+		func(id string) (*egoscale.Instance, error) {
+			atomic.AddInt32(&calls, 1)
+			if id == "i2" {
+				return nil, errors.New("not found")
+			}
+			name, state := "node-"+id, "running"
+			return &egoscale.Instance{ID: &id, Name: &name, State: &state}, nil
+		},
+	)
+
+	require.Equal(t, int32(3), calls)
+	require.Equal(t, []sksDescribeInstanceOutput{
+		{ID: "i1", Name: "node-i1", State: "running"},
+		{ID: "i2", State: "error: not found"},
+	}, nodepools[0].Instances)
+	require.Equal(t, []sksDescribeInstanceOutput{
+		{ID: "i3", Name: "node-i3", State: "running"},
+	}, nodepools[1].Instances)
+}
@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	exoapi "github.com/exoscale/egoscale/v2/api"
@@ -19,7 +27,7 @@ type sksKubeconfigCmd struct {
 
 	ExecCredential bool     `cli-short:"x" cli-usage:"output an ExecCredential object to use with a kubeconfig user.exec mode"`
 	Groups         []string `cli-flag:"group" cli-short:"g" cli-usage:"client certificate group. Can be specified multiple times. Defaults to system:masters"`
-	TTL            int64    `cli-short:"t" cli-usage:"client certificate validity duration in seconds"`
+	TTL            string   `cli-short:"t" cli-usage:"client certificate validity duration (e.g. \"12h\", or a number of seconds)"`
 	Zone           string   `cli-short:"z" cli-usage:"SKS cluster zone"`
 }
 
@@ -47,8 +55,10 @@ Example usage:
         -t $((86400 * 7)) > $HOME/.kube/my-cluster.config
     $ kubeconfig --kubeconfig=$HOME/.kube/my-cluster.config get pods
 
-Note: if no TTL value is specified, the API applies a default value as a
-safety measure. Please look up the API documentation for more information.
+The "-t|--ttl" flag value is either a duration (e.g. "12h", "168h") or a
+number of seconds, and must be between 1 minute and 30 days. If no TTL value
+is specified, the API applies a default value as a safety measure. Please look
+up the API documentation for more information.
 
 ## Using exo CLI as Kubernetes credential plugin
 
@@ -94,6 +104,11 @@ Notes:
   shell environment variable.
 * You can specify the "--group" flag in the user.exec.args section referencing
   a non-admin group to restrict the privileges of the operator using kubectl.
+* In this mode, the generated credentials are cached on disk (in the exo CLI
+  configuration directory) until shortly before their expiration, in order
+  to avoid performing an API request on every kubectl invocation. Specifying
+  a short TTL value (e.g. "--ttl=1h") limits the lifetime of the cached
+  credentials.
 
 [1]: https://kubernetes.io/docs/reference/access-authn-authz/authentication/#x509-client-certs
 [2]: https://kubernetes.io/docs/reference/access-authn-authz/authentication/#client-go-credential-plugins
@@ -106,19 +121,45 @@ func (c *sksKubeconfigCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 }
 
 func (c *sksKubeconfigCmd) cmdRun(_ *cobra.Command, _ []string) error {
-	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+	ttl, err := parseSKSKubeconfigTTL(c.TTL)
+	if err != nil {
+		return err
+	}
 
 	// We cannot use the flag's default here as it would be additive
 	if len(c.Groups) == 0 {
 		c.Groups = []string{"system:masters"}
 	}
+	if err := validateSKSKubeconfigGroups(c.Groups); err != nil {
+		return err
+	}
+
+	var cacheFile string
+	if c.ExecCredential {
+		cacheFile = sksExecCredentialCacheFile(
+			gAccountName,
+			gCurrentAccount.Key,
+			gCurrentAccount.Environment,
+			c.Zone,
+			c.Cluster,
+			c.User,
+			c.Groups,
+			ttl,
+		)
+		if ec, ok := sksExecCredentialFromCache(cacheFile, time.Now()); ok {
+			fmt.Print(string(ec))
+			return nil
+		}
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
 	cluster, err := cs.FindSKSCluster(ctx, c.Zone, c.Cluster)
 	if err != nil {
 		return err
 	}
 
-	b64Kubeconfig, err := cluster.RequestKubeconfig(ctx, c.User, c.Groups, time.Duration(c.TTL)*time.Second)
+	b64Kubeconfig, err := cluster.RequestKubeconfig(ctx, c.User, c.Groups, ttl)
 	if err != nil {
 		return fmt.Errorf("error retrieving kubeconfig: %s", err)
 	}
@@ -142,6 +183,9 @@ func (c *sksKubeconfigCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	if err := yaml.Unmarshal(kubeconfig, &k); err != nil {
 		return fmt.Errorf("error decoding kubeconfig content: %s", err)
 	}
+	if len(k.Users) == 0 {
+		return errors.New("error decoding kubeconfig content: no user found")
+	}
 
 	ecClientCertificateData, err := base64.StdEncoding.DecodeString(k.Users[0].User["client-certificate-data"])
 	if err != nil {
@@ -153,22 +197,165 @@ func (c *sksKubeconfigCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("error decoding kubeconfig content: %s", err)
 	}
 
+	status := map[string]string{
+		"clientCertificateData": string(ecClientCertificateData),
+		"clientKeyData":         string(ecClientKeyData),
+	}
+
+	expiration, err := sksCertificateExpiration(ecClientCertificateData)
+	if err != nil {
+		return fmt.Errorf("error decoding kubeconfig content: %s", err)
+	}
+	status["expirationTimestamp"] = expiration.UTC().Format(time.RFC3339)
+
 	ecOut, err := json.Marshal(map[string]interface{}{
 		"apiVersion": "client.authentication.k8s.io/v1beta1",
 		"kind":       "ExecCredential",
-		"status": map[string]string{
-			"clientCertificateData": string(ecClientCertificateData),
-			"clientKeyData":         string(ecClientKeyData),
-		},
+		"status":     status,
 	})
 	if err != nil {
 		return fmt.Errorf("error encoding exec credential content: %s", err)
 	}
 
+	// Failing to cache the credentials is not fatal, we'll just request new
+	// ones on the next invocation.
+	if err := sksExecCredentialToCache(cacheFile, ecOut); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: unable to cache exec credential: %s\n", err)
+	}
+
 	fmt.Print(string(ecOut))
 	return nil
 }
 
+const (
+	sksKubeconfigMinTTL = time.Minute
+	sksKubeconfigMaxTTL = 30 * 24 * time.Hour
+
+	// sksExecCredentialRenewBefore represents the remaining validity duration
+	// under which cached exec credentials are not used anymore.
+	sksExecCredentialRenewBefore = 5 * time.Minute
+)
+
+// parseSKSKubeconfigTTL parses a kubeconfig client certificate TTL value,
+// expressed either as a duration (e.g. "12h") or as a number of seconds. An
+// empty value returns a zero duration, leaving the API apply its default TTL.
+func parseSKSKubeconfigTTL(v string) (time.Duration, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	var ttl time.Duration
+	if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+		ttl = time.Duration(seconds) * time.Second
+	} else if ttl, err = time.ParseDuration(v); err != nil {
+		return 0, fmt.Errorf("invalid TTL value %q: expected a duration (e.g. \"12h\") or a number of seconds", v)
+	}
+
+	if ttl < sksKubeconfigMinTTL || ttl > sksKubeconfigMaxTTL {
+		return 0, fmt.Errorf("invalid TTL value %q: must be between %s and %s",
+			v, sksKubeconfigMinTTL, sksKubeconfigMaxTTL)
+	}
+
+	return ttl, nil
+}
+
+// validateSKSKubeconfigGroups checks that kubeconfig client certificate
+// groups are not empty and not duplicated.
+func validateSKSKubeconfigGroups(groups []string) error {
+	seen := make(map[string]struct{})
+	for _, g := range groups {
+		if strings.TrimSpace(g) == "" {
+			return errors.New("invalid group: value cannot be empty")
+		}
+		if _, ok := seen[g]; ok {
+			return fmt.Errorf("invalid group %q: specified multiple times", g)
+		}
+		seen[g] = struct{}{}
+	}
+
+	return nil
+}
+
+// sksCertificateExpiration returns the expiration time of a PEM-encoded
+// X.509 certificate.
+func sksCertificateExpiration(data []byte) (time.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, errors.New("invalid client certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return cert.NotAfter, nil
+}
+
+// sksExecCredentialCacheFile returns the path of the file caching the exec
+// credential matching the specified parameters. The API key and environment
+// are part of the cache key, the account name alone not identifying the
+// credentials (e.g. when provided using environment variables).
+func sksExecCredentialCacheFile(
+	account, apiKey, environment, zone, cluster, user string,
+	groups []string,
+	ttl time.Duration,
+) string {
+	key := sha256.Sum256([]byte(strings.Join([]string{
+		account,
+		apiKey,
+		environment,
+		zone,
+		cluster,
+		user,
+		strings.Join(groups, ","),
+		ttl.String(),
+	}, "\x00")))
+
+	return path.Join(gConfigFolder, "sks-credentials", fmt.Sprintf("%x.json", key))
+}
+
+// sksExecCredentialFromCache returns the exec credential stored in the
+// specified cache file if it is still valid for longer than
+// sksExecCredentialRenewBefore at time now.
+func sksExecCredentialFromCache(file string, now time.Time) ([]byte, bool) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+
+	var ec struct {
+		Status struct {
+			ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(data, &ec); err != nil {
+		return nil, false
+	}
+
+	if ec.Status.ExpirationTimestamp.Sub(now) < sksExecCredentialRenewBefore {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// sksExecCredentialToCache stores an exec credential in the specified cache
+// file, readable by the current user only.
+func sksExecCredentialToCache(file string, ec []byte) error {
+	if err := os.MkdirAll(path.Dir(file), 0o700); err != nil {
+		return err
+	}
+
+	unlock, err := lockFile(file)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeFileAtomic(file, ec, 0o600)
+}
+
 func init() {
 	cobra.CheckErr(registerCLICommand(sksCmd, &sksKubeconfigCmd{}))
 }
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSKSKubeconfigTTL(t *testing.T) {
+	for v, expected := range map[string]time.Duration{
+		"":       0,
+		"3600":   time.Hour,
+		"12h":    12 * time.Hour,
+		"720h":   30 * 24 * time.Hour,
+		"1m":     time.Minute,
+		"1h30m0": 0,
+		"30s":    0,
+		"721h":   0,
+		"-1":     0,
+	} {
+		ttl, err := parseSKSKubeconfigTTL(v)
+		if expected == 0 && v != "" {
+			require.Error(t, err, v)
+			continue
+		}
+		require.NoError(t, err, v)
+		require.Equal(t, expected, ttl, v)
+	}
+}
+
+func Test_validateSKSKubeconfigGroups(t *testing.T) {
+	require.NoError(t, validateSKSKubeconfigGroups([]string{"system:masters", "dev"}))
+	require.Error(t, validateSKSKubeconfigGroups([]string{"dev", " "}))
+	require.Error(t, validateSKSKubeconfigGroups([]string{"dev", "dev"}))
+}
+
+func Test_sksExecCredentialCache(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	file := path.Join(t.TempDir(), "sks-credentials", "test.json")
+
+	_, ok := sksExecCredentialFromCache(file, now)
+	require.False(t, ok)
+
+	ec := []byte(`{"kind":"ExecCredential","status":{"expirationTimestamp":"2021-06-01T13:00:00Z"}}`)
+	require.NoError(t, sksExecCredentialToCache(file, ec))
+
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	cached, ok := sksExecCredentialFromCache(file, now)
+	require.True(t, ok)
+	require.Equal(t, ec, cached)
+
+	_, ok = sksExecCredentialFromCache(file, now.Add(56*time.Minute))
+	require.False(t, ok)
+}
+
+func Test_sksExecCredentialCacheFile(t *testing.T) {
+	file := func(account, apiKey, environment string) string {
+		return sksExecCredentialCacheFile(account, apiKey, environment,
+			"ch-gva-2", "cluster", "admin", []string{"system:masters"}, time.Hour)
+	}
+
+	require.Equal(t, file("default", "EXOa", "api"), file("default", "EXOa", "api"))
+
+	// Different credentials never share a cached exec credential, even
+	// using the same account name (or none, with environment variables).
+	require.NotEqual(t, file("default", "EXOa", "api"), file("default", "EXOb", "api"))
+	require.NotEqual(t, file("", "EXOa", "api"), file("", "EXOb", "api"))
+	require.NotEqual(t, file("default", "EXOa", "api"), file("default", "EXOa", "ppapi"))
+}
@@ -55,12 +55,13 @@ func (c *sksListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	out := make(sksClusterListOutput, 0)
 	res := make(chan sksClusterListItemOutput)
-	defer close(res)
+	done := make(chan struct{})
 
 	go func() {
 		for cluster := range res {
 			out = append(out, cluster)
 		}
+		close(done)
 	}()
 	err := forEachZone(zones, func(zone string) error {
 		list, err := cs.ListSKSClusters(ctx, zone)
@@ -78,6 +79,8 @@ func (c *sksListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 		return nil
 	})
+	close(res)
+	<-done
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr,
 			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
@@ -1,6 +1,22 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+// SKS Nodepool Compute instances public IP address assignment modes.
+const (
+	sksNodepoolPublicIPAssignmentInet4 = "inet4"
+	sksNodepoolPublicIPAssignmentNone  = "none"
+)
 
 var sksNodepoolCmd = &cobra.Command{
 	Use:     "nodepool",
@@ -8,6 +24,144 @@ var sksNodepoolCmd = &cobra.Command{
 	Aliases: []string{"np"},
 }
 
+// sksClusterSupportsPrivateNodes returns an error if the SKS cluster doesn't
+// support Nodepools without public IP address, i.e. if it doesn't use an
+// Exoscale-managed CNI plugin routing the pods traffic over the Nodepools
+// Private Networks, or the "pro" service level.
+func sksClusterSupportsPrivateNodes(cluster *egoscale.SKSCluster) error {
+	if defaultString(cluster.CNI, "") == "" {
+		return fmt.Errorf(
+			"cluster %q doesn't use an Exoscale-managed CNI plugin, which is required for Nodepools "+
+				"without public IP address: create a cluster using \"exo sks create --cni calico\"",
+			*cluster.Name)
+	}
+
+	if level := defaultString(cluster.ServiceLevel, ""); level != defaultSKSClusterServiceLevel {
+		return fmt.Errorf(
+			"cluster %q uses the %q service level, Nodepools without public IP address require the %q "+
+				"service level: create a cluster using \"exo sks create --service-level %s\"",
+			*cluster.Name, level, defaultSKSClusterServiceLevel, defaultSKSClusterServiceLevel)
+	}
+
+	return nil
+}
+
+// sksNodepoolCreateRequestBody returns the API request body creating the
+// Nodepool with the specified public IP assignment mode.
+func sksNodepoolCreateRequestBody(nodepool *egoscale.SKSNodepool, publicIPAssignment string) map[string]interface{} {
+	refs := func(ids *[]string) []map[string]string {
+		list := make([]map[string]string, len(*ids))
+		for i, id := range *ids {
+			list[i] = map[string]string{"id": id}
+		}
+		return list
+	}
+
+	body := map[string]interface{}{
+		"disk-size":            *nodepool.DiskSize,
+		"instance-type":        map[string]string{"id": *nodepool.InstanceTypeID},
+		"name":                 *nodepool.Name,
+		"public-ip-assignment": publicIPAssignment,
+		"size":                 *nodepool.Size,
+	}
+
+	if nodepool.AntiAffinityGroupIDs != nil {
+		body["anti-affinity-groups"] = refs(nodepool.AntiAffinityGroupIDs)
+	}
+	if nodepool.DeployTargetID != nil {
+		body["deploy-target"] = map[string]string{"id": *nodepool.DeployTargetID}
+	}
+	if nodepool.Description != nil {
+		body["description"] = *nodepool.Description
+	}
+	if nodepool.InstancePrefix != nil {
+		body["instance-prefix"] = *nodepool.InstancePrefix
+	}
+	if nodepool.Labels != nil {
+		body["labels"] = *nodepool.Labels
+	}
+	if nodepool.PrivateNetworkIDs != nil {
+		body["private-networks"] = refs(nodepool.PrivateNetworkIDs)
+	}
+	if nodepool.SecurityGroupIDs != nil {
+		body["security-groups"] = refs(nodepool.SecurityGroupIDs)
+	}
+
+	return body
+}
+
+// addSKSNodepoolWithPublicIPAssignment adds a Nodepool to an SKS cluster
+// with the specified public IP assignment mode. The egoscale
+// SKSCluster.AddNodepool() method doesn't support this parameter, so the
+// request is performed using the low-level API client.
+func addSKSNodepoolWithPublicIPAssignment(
+	ctx context.Context,
+	zone string,
+	cluster *egoscale.SKSCluster,
+	nodepool *egoscale.SKSNodepool,
+	publicIPAssignment string,
+) (*egoscale.SKSNodepool, error) {
+	body, err := json.Marshal(sksNodepoolCreateRequestBody(nodepool, publicIPAssignment))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := cs.CreateSksNodepoolWithBodyWithResponse(
+		exoapi.WithZone(ctx, zone),
+		*cluster.ID,
+		"application/json",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, fmt.Errorf("API error: %s: %s", res.Status(), strings.TrimSpace(string(res.Body)))
+	}
+
+	if err := waitForOperation(ctx, zone, *res.JSON200.Id); err != nil {
+		return nil, err
+	}
+
+	// Nodepool names are unique within an SKS cluster.
+	cluster, err = cs.GetSKSCluster(ctx, zone, *cluster.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, np := range cluster.Nodepools {
+		if *np.Name == *nodepool.Name {
+			return np, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Nodepool %q not found after creation", *nodepool.Name) // nolint:golint
+}
+
+// getSKSNodepoolPublicIPAssignment returns the public IP assignment mode of
+// an SKS Nodepool, which is not exposed by the egoscale SKSNodepool type.
+func getSKSNodepoolPublicIPAssignment(ctx context.Context, zone, clusterID, nodepoolID string) (string, error) {
+	res, err := cs.GetSksNodepoolWithResponse(exoapi.WithZone(ctx, zone), clusterID, nodepoolID)
+	if err != nil {
+		return "", err
+	}
+	if res.JSON200 == nil {
+		return "", fmt.Errorf("API error: %s: %s", res.Status(), strings.TrimSpace(string(res.Body)))
+	}
+
+	var nodepool struct {
+		PublicIPAssignment string `json:"public-ip-assignment"`
+	}
+	if err := json.Unmarshal(res.Body, &nodepool); err != nil {
+		return "", err
+	}
+
+	if nodepool.PublicIPAssignment == "" {
+		return sksNodepoolPublicIPAssignmentInet4, nil
+	}
+
+	return nodepool.PublicIPAssignment, nil
+}
+
 func init() {
 	sksCmd.AddCommand(sksNodepoolCmd)
 }
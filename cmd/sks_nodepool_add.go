@@ -19,12 +19,16 @@ type sksNodepoolAddCmd struct {
 	DeployTarget       string            `cli-usage:"Nodepool Deploy Target NAME|ID"`
 	Description        string            `cli-usage:"Nodepool description"`
 	DiskSize           int64             `cli-usage:"Nodepool Compute instances disk size"`
+	FallbackType       bool              `cli-usage:"retry with the nearest instance type of the same family if the instance type is unavailable in the zone"`
 	InstancePrefix     string            `cli-usage:"string to prefix Nodepool member names with"`
 	InstanceType       string            `cli-usage:"Nodepool Compute instances type"`
 	Labels             map[string]string `cli-flag:"label" cli-usage:"Nodepool label (format: key=value)"`
+	NoChecks           bool              `cli-usage:"skip the instance prefix collision check"`
+	NoPublicIP         bool              `cli-flag:"no-public-ip" cli-usage:"don't assign a public IP address to the Nodepool Compute instances (requires --private-network)"`
 	PrivateNetworks    []string          `cli-flag:"private-network" cli-usage:"Nodepool Private Network NAME|ID (can be specified multiple times)"`
 	SecurityGroups     []string          `cli-flag:"security-group" cli-usage:"Nodepool Security Group NAME|ID (can be specified multiple times)"`
 	Size               int64             `cli-usage:"Nodepool size"`
+	StrictNaming       bool              `cli-usage:"fail if the instance prefix is already used by another Instance Pool in the zone"`
 	Zone               string            `cli-short:"z" cli-usage:"SKS cluster zone"`
 }
 
@@ -35,7 +39,15 @@ func (c *sksNodepoolAddCmd) cmdShort() string { return "Add a Nodepool to an SKS
 func (c *sksNodepoolAddCmd) cmdLong() string {
 	return fmt.Sprintf(`This command adds a Nodepool to an SKS cluster.
 
+Using the "--no-public-ip" flag, the Nodepool Compute instances are created
+without public IP address, for private cluster designs: the Nodepool must be
+attached to at least one Private Network, and the cluster must use an
+Exoscale-managed CNI plugin and the "pro" service level.
+
+%s
+
 Supported output template annotations: %s`,
+		instancePrefixCheckHelp,
 		strings.Join(outputterTemplateAnnotations(&sksNodepoolShowOutput{}), ", "))
 }
 
@@ -44,7 +56,11 @@ func (c *sksNodepoolAddCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	return cliCommandDefaultPreRun(c, cmd, args)
 }
 
-func (c *sksNodepoolAddCmd) cmdRun(_ *cobra.Command, _ []string) error {
+func (c *sksNodepoolAddCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	if c.NoPublicIP && len(c.PrivateNetworks) == 0 {
+		cmdExitOnUsageError(cmd, "--no-public-ip requires at least one --private-network")
+	}
+
 	nodepool := &egoscale.SKSNodepool{
 		Description: func() (v *string) {
 			if c.Description != "" {
@@ -76,6 +92,43 @@ func (c *sksNodepoolAddCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("error retrieving cluster: %s", err)
 	}
 
+	if c.NoPublicIP {
+		if err := sksClusterSupportsPrivateNodes(cluster); err != nil {
+			return err
+		}
+	}
+
+	if !c.NoChecks {
+		if err := checkInstancePrefixCollisions(ctx, c.Zone, c.InstancePrefix, "", c.StrictNaming); err != nil {
+			return err
+		}
+	}
+
+	// All the Nodepools of an SKS cluster are using the same template: if
+	// the cluster has existing Nodepools, we can check early that the
+	// requested disk size is compatible with it.
+	for _, np := range cluster.Nodepools {
+		if np.TemplateID == nil {
+			continue
+		}
+
+		template, err := cs.GetTemplate(ctx, c.Zone, *np.TemplateID)
+		if err != nil {
+			return fmt.Errorf("error retrieving template: %s", err)
+		}
+
+		if err := validateTemplateDiskSize(
+			*template.Name,
+			defaultInt64(template.Size, 0),
+			c.DiskSize,
+			mustCLICommandFlagName(c, &c.DiskSize),
+		); err != nil {
+			return err
+		}
+
+		break
+	}
+
 	if l := len(c.AntiAffinityGroups); l > 0 {
 		nodepoolAntiAffinityGroupIDs := make([]string, l)
 		for i := range c.AntiAffinityGroups {
@@ -126,8 +179,27 @@ func (c *sksNodepoolAddCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		nodepool.SecurityGroupIDs = &nodepoolSecurityGroupIDs
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Adding Nodepool %q...", *nodepool.Name), func() {
-		nodepool, err = cluster.AddNodepool(ctx, nodepool)
+	err = decorateAsyncOperation(fmt.Sprintf("Adding Nodepool %q...", *nodepool.Name), func() error {
+		return createWithInstanceTypeFallback(c.Zone, *nodepool.InstanceTypeID, c.FallbackType,
+			func(instanceTypeID string) error {
+				nodepool.InstanceTypeID = &instanceTypeID
+
+				var (
+					added *egoscale.SKSNodepool
+					err   error
+				)
+				if c.NoPublicIP {
+					added, err = addSKSNodepoolWithPublicIPAssignment(ctx, c.Zone, cluster, nodepool,
+						sksNodepoolPublicIPAssignmentNone)
+				} else {
+					added, err = cluster.AddNodepool(ctx, nodepool)
+				}
+				if err != nil {
+					return err
+				}
+				nodepool = added
+				return nil
+			})
 	})
 	if err != nil {
 		return err
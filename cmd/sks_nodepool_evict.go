@@ -83,8 +83,8 @@ func (c *sksNodepoolEvictCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		nodes[i] = *instance.ID
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Evicting Nodes from Nodepool %q...", c.Nodepool), func() {
-		err = cluster.EvictNodepoolMembers(ctx, nodepool, nodes)
+	err = decorateAsyncOperation(fmt.Sprintf("Evicting Nodes from Nodepool %q...", c.Nodepool), func() error {
+		return cluster.EvictNodepoolMembers(ctx, nodepool, nodes)
 	})
 	if err != nil {
 		return err
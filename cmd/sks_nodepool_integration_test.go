@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationSKSNodepoolShow(t *testing.T) {
+	setupIntegrationTest(t)
+
+	out, code := runCLI(t, "sks", "nodepool", "show", "prod", "workers", "-z", "ch-gva-2")
+	require.Equal(t, 0, code)
+	requireGoldenOutput(t, out)
+}
+
+func TestIntegrationSKSNodepoolScale(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	out, code := runCLI(t, "sks", "nodepool", "scale", "prod", "workers", "3", "-f", "-z", "ch-gva-2", "-O", "json")
+	require.Equal(t, 0, code)
+
+	requireJSONField(t, server.request(
+		http.MethodPut,
+		"/sks-cluster/1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f/nodepool/6f5e4d3c-2b1a-4098-8765-43210fedcba9:scale",
+		0,
+	), "size", 3)
+
+	np := []byte(outputJSONLine(t, out))
+	requireJSONField(t, np, "size", 3)
+	requireJSONField(t, np, "state", "scaling")
+	requireJSONField(t, np, "instance_type", "small")
+	requireJSONField(t, np, "template", "Linux Ubuntu 20.04 LTS 64-bit")
+}
@@ -58,12 +58,13 @@ func (c *sksNodepoolListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 	out := make(sksNodepoolListOutput, 0)
 	res := make(chan sksNodepoolListItemOutput)
-	defer close(res)
+	done := make(chan struct{})
 
 	go func() {
 		for cluster := range res {
 			out = append(out, cluster)
 		}
+		close(done)
 	}()
 	err := forEachZone(zones, func(zone string) error {
 		list, err := cs.ListSKSClusters(ctx, zone)
@@ -86,6 +87,8 @@ func (c *sksNodepoolListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 
 		return nil
 	})
+	close(res)
+	<-done
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr,
 			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
@@ -71,8 +71,8 @@ func (c *sksNodepoolScaleCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return errors.New("Nodepool not found") // nolint:golint
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Scaling Nodepool %q...", c.Nodepool), func() {
-		err = cluster.ScaleNodepool(ctx, nodepool, c.Size)
+	err = decorateAsyncOperation(fmt.Sprintf("Scaling Nodepool %q...", c.Nodepool), func() error {
+		return cluster.ScaleNodepool(ctx, nodepool, c.Size)
 	})
 	if err != nil {
 		return err
@@ -23,6 +23,7 @@ type sksNodepoolShowOutput struct {
 	AntiAffinityGroups []string          `json:"anti_affinity_groups"`
 	SecurityGroups     []string          `json:"security_groups"`
 	PrivateNetworks    []string          `json:"private_networks"`
+	PublicIPAssignment string            `json:"public_ip_assignment" outputLabel:"Public IP Assignment"`
 	Version            string            `json:"version"`
 	Size               int64             `json:"size"`
 	State              string            `json:"state"`
@@ -129,6 +130,10 @@ func showSKSNodepool(zone, c, np string) (outputter, error) {
 		out.PrivateNetworks = append(out.PrivateNetworks, *privateNetwork.Name)
 	}
 
+	if out.PublicIPAssignment, err = getSKSNodepoolPublicIPAssignment(ctx, zone, *cluster.ID, *nodepool.ID); err != nil {
+		return nil, fmt.Errorf("error retrieving public IP assignment: %s", err)
+	}
+
 	serviceOffering, err := cs.GetInstanceType(ctx, zone, *nodepool.InstanceTypeID)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving service offering: %s", err)
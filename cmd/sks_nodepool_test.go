@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sksClusterSupportsPrivateNodes(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	require.NoError(t, sksClusterSupportsPrivateNodes(&egoscale.SKSCluster{
+		Name:         str("prod"),
+		CNI:          str("calico"),
+		ServiceLevel: str("pro"),
+	}))
+
+	err := sksClusterSupportsPrivateNodes(&egoscale.SKSCluster{
+		Name:         str("prod"),
+		ServiceLevel: str("pro"),
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--cni")
+
+	err = sksClusterSupportsPrivateNodes(&egoscale.SKSCluster{
+		Name:         str("dev"),
+		CNI:          str("calico"),
+		ServiceLevel: str("starter"),
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--service-level pro")
+}
+
+func Test_sksNodepoolCreateRequestBody(t *testing.T) {
+	var (
+		name           = "private"
+		diskSize int64 = 50
+		size     int64 = 3
+		typeID         = "b6cd1ff5-3a2f-4e9d-a4d1-8988c1191fe8"
+	)
+
+	body, err := json.Marshal(sksNodepoolCreateRequestBody(&egoscale.SKSNodepool{
+		DiskSize:          &diskSize,
+		InstanceTypeID:    &typeID,
+		Labels:            &map[string]string{"role": "worker"},
+		Name:              &name,
+		PrivateNetworkIDs: &[]string{"pn1"},
+		Size:              &size,
+	}, sksNodepoolPublicIPAssignmentNone))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"disk-size": 50,
+		"instance-type": {"id": "b6cd1ff5-3a2f-4e9d-a4d1-8988c1191fe8"},
+		"labels": {"role": "worker"},
+		"name": "private",
+		"private-networks": [{"id": "pn1"}],
+		"public-ip-assignment": "none",
+		"size": 3
+	}`, string(body))
+}
@@ -24,8 +24,10 @@ type sksNodepoolUpdateCmd struct {
 	InstanceType       string            `cli-usage:"Nodepool Compute instances type"`
 	Labels             map[string]string `cli-flag:"label" cli-usage:"Nodepool label (format: key=value)"`
 	Name               string            `cli-usage:"Nodepool name"`
+	NoChecks           bool              `cli-usage:"skip the instance prefix collision check"`
 	PrivateNetworks    []string          `cli-flag:"private-network" cli-usage:"Nodepool Private Network NAME|ID (can be specified multiple times)"`
 	SecurityGroups     []string          `cli-flag:"security-group" cli-usage:"Nodepool Security Group NAME|ID (can be specified multiple times)"`
+	StrictNaming       bool              `cli-usage:"fail if the instance prefix is already used by another Instance Pool in the zone"`
 	Zone               string            `cli-short:"z" cli-usage:"SKS cluster zone"`
 }
 
@@ -36,7 +38,10 @@ func (c *sksNodepoolUpdateCmd) cmdShort() string { return "Update an SKS cluster
 func (c *sksNodepoolUpdateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command updates an SKS Nodepool.
 
+%s
+
 Supported output template annotations: %s`,
+		instancePrefixCheckHelp,
 		strings.Join(outputterTemplateAnnotations(&sksNodepoolShowOutput{}), ", "),
 	)
 }
@@ -102,6 +107,17 @@ func (c *sksNodepoolUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.InstancePrefix)) {
+		if !c.NoChecks {
+			if err := checkInstancePrefixCollisions(
+				ctx,
+				c.Zone,
+				c.InstancePrefix,
+				defaultString(nodepool.InstancePoolID, ""),
+				c.StrictNaming,
+			); err != nil {
+				return err
+			}
+		}
 		nodepool.InstancePrefix = &c.InstancePrefix
 		updated = true
 	}
@@ -152,10 +168,8 @@ func (c *sksNodepoolUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	if updated {
-		decorateAsyncOperation(fmt.Sprintf("Updating Nodepool %q...", c.Nodepool), func() {
-			if err = cluster.UpdateNodepool(ctx, nodepool); err != nil {
-				return
-			}
+		err = decorateAsyncOperation(fmt.Sprintf("Updating Nodepool %q...", c.Nodepool), func() error {
+			return cluster.UpdateNodepool(ctx, nodepool)
 		})
 		if err != nil {
 			return err
@@ -40,10 +40,10 @@ func (c *sksRotateCCMCredentialsCmd) cmdRun(_ *cobra.Command, _ []string) error
 		return err
 	}
 
-	decorateAsyncOperation(
+	err = decorateAsyncOperation(
 		fmt.Sprintf("Rotating SKS cluster %q Exoscale CCM credentials...", c.Cluster),
-		func() {
-			err = cluster.RotateCCMCredentials(ctx)
+		func() error {
+			return cluster.RotateCCMCredentials(ctx)
 		},
 	)
 	if err != nil {
@@ -79,7 +79,7 @@ func (o *sksShowOutput) toTable() {
 type sksShowCmd struct {
 	_ bool `cli-cmd:"show"`
 
-	Cluster string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Cluster string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"sks-cluster" cli-pick:"sks-cluster"`
 
 	Zone string `cli-short:"z" cli-usage:"SKS cluster zone"`
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -11,9 +12,11 @@ import (
 type sksUpdateCmd struct {
 	_ bool `cli-cmd:"update"`
 
-	Cluster string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Cluster string `cli-arg:"#" cli-usage:"NAME|ID" cli-complete:"sks-cluster"`
 
+	AddOns      []string          `cli-flag:"addon" cli-usage:"(not supported, the SKS cluster add-ons are immutable)"`
 	AutoUpgrade bool              `cli-usage:"enable automatic upgrading of the SKS cluster control plane Kubernetes version"`
+	CNI         string            `cli-flag:"cni" cli-usage:"(not supported, the SKS cluster CNI plugin is immutable)"`
 	Description string            `cli-usage:"SKS cluster description"`
 	Labels      map[string]string `cli-flag:"label" cli-usage:"SKS cluster label (format: key=value)"`
 	Name        string            `cli-usage:"SKS cluster name"`
@@ -40,6 +43,15 @@ func (c *sksUpdateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 func (c *sksUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	var updated bool
 
+	// The add-ons and CNI plugin flags are only declared to provide users
+	// with a helpful error message.
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.CNI)) {
+		return errors.New("the CNI plugin of an SKS cluster cannot be changed once the cluster has been created")
+	}
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.AddOns)) {
+		return errors.New("the add-ons of an SKS cluster cannot be changed once the cluster has been created")
+	}
+
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
 	cluster, err := cs.FindSKSCluster(ctx, c.Zone, c.Cluster)
@@ -68,8 +80,8 @@ func (c *sksUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	if updated {
-		decorateAsyncOperation(fmt.Sprintf("Updating SKS cluster %q...", c.Cluster), func() {
-			err = cs.UpdateSKSCluster(ctx, c.Zone, cluster)
+		err = decorateAsyncOperation(fmt.Sprintf("Updating SKS cluster %q...", c.Cluster), func() error {
+			return cs.UpdateSKSCluster(ctx, c.Zone, cluster)
 		})
 		if err != nil {
 			return err
@@ -85,4 +97,9 @@ func (c *sksUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 
 func init() {
 	cobra.CheckErr(registerCLICommand(sksCmd, &sksUpdateCmd{}))
+
+	if cmd, _, err := sksCmd.Find([]string{"update"}); err == nil {
+		cobra.CheckErr(cmd.Flags().MarkHidden("addon"))
+		cobra.CheckErr(cmd.Flags().MarkHidden("cni"))
+	}
 }
@@ -46,8 +46,8 @@ func (c *sksUpgradeCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Upgrading SKS cluster %q...", c.Cluster), func() {
-		err = cs.UpgradeSKSCluster(ctx, c.Zone, *cluster.ID, c.Version)
+	err = decorateAsyncOperation(fmt.Sprintf("Upgrading SKS cluster %q...", c.Cluster), func() error {
+		return cs.UpgradeSKSCluster(ctx, c.Zone, *cluster.ID, c.Version)
 	})
 	if err != nil {
 		return err
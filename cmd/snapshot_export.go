@@ -142,7 +142,7 @@ func downloadExportedSnapshot(filePath, url string) (string, error) {
 	progress := mpb.NewWithContext(gContext,
 		mpb.WithWidth(64),
 		mpb.WithRefreshRate(180*time.Millisecond),
-		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool { return gQuiet }),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), decorationsDisabled),
 	)
 
 	bar := progress.AddBar(
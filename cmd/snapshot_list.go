@@ -1,15 +1,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/exoscale/egoscale"
-
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
 
+// snapshotLookupParallelism is the maximum number of concurrent instance
+// lookups performed to detect orphaned snapshots.
+const snapshotLookupParallelism = 10
+
 type snapshotListItemOutput struct {
 	ID       string `json:"id"`
 	Date     string `json:"date"`
@@ -24,22 +34,97 @@ func (o *snapshotListOutput) toJSON()  { outputJSON(o) }
 func (o *snapshotListOutput) toText()  { outputText(o) }
 func (o *snapshotListOutput) toTable() { outputTable(o) }
 
+type snapshotListZonesItemOutput struct {
+	ID         string `json:"id"`
+	Name       string `json:"name" output:"wide"`
+	Date       string `json:"date"`
+	Zone       string `json:"zone"`
+	Instance   string `json:"instance"`
+	InstanceID string `json:"instance_id" output:"wide"`
+	State      string `json:"state"`
+	Orphaned   bool   `json:"orphaned"`
+}
+
+type snapshotListZonesOutput []snapshotListZonesItemOutput
+
+func (o *snapshotListZonesOutput) toJSON()  { outputJSON(o) }
+func (o *snapshotListZonesOutput) toText()  { outputText(o) }
+func (o *snapshotListZonesOutput) toTable() { outputTable(o) }
+
 func init() {
-	snapshotCmd.AddCommand(&cobra.Command{
+	snapshotListCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List snapshots",
 		Long: fmt.Sprintf(`This command lists existing Compute instance disk snapshots.
 
-Supported output template annotations: %s`,
-			strings.Join(outputterTemplateAnnotations(&snapshotListOutput{}), ", ")),
+%s
+
+Using the "--all-zones" flag, the snapshots of all zones are listed along
+with their zone. The "--orphaned" flag lists only the snapshots whose source
+instance no longer exists (in the current account's default zone, or in all
+zones with "--all-zones"), which keep being billed until deleted: see the
+"exo vm snapshot prune" command to delete them.
+
+Supported output template annotations: %s
+
+Supported output template annotations ("--all-zones"/"--orphaned"): %s`,
+			createdAtFilterHelp,
+			strings.Join(outputterTemplateAnnotations(&snapshotListOutput{}), ", "),
+			strings.Join(outputterTemplateAnnotations(&snapshotListZonesItemOutput{}), ", ")),
 		Aliases: gListAlias,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return output(listSnapshots(args))
+			before, _ := cmd.Flags().GetString("created-before")
+			after, _ := cmd.Flags().GetString("created-after")
+			createdAt, err := newCreatedAtFilter(before, after)
+			if err != nil {
+				return err
+			}
+
+			allZonesFlag, _ := cmd.Flags().GetBool("all-zones")
+			orphaned, _ := cmd.Flags().GetBool("orphaned")
+
+			if allZonesFlag || orphaned {
+				if len(args) > 0 {
+					return errors.New(`instances cannot be specified with "--all-zones" or "--orphaned"`)
+				}
+
+				zones := []string{gCurrentAccount.DefaultZone}
+				if allZonesFlag {
+					zones = allZones
+				}
+
+				out, err := listZonesSnapshots(zones, createdAt, orphaned)
+				if err != nil {
+					_, _ = fmt.Fprintf(os.Stderr,
+						"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
+				}
+
+				return output(&out, nil)
+			}
+
+			return output(listSnapshots(args, createdAt))
 		},
-	})
+	}
+	snapshotListCmd.Flags().Bool("all-zones", false, "list the snapshots of all zones")
+	snapshotListCmd.Flags().Bool("orphaned", false, "only list the snapshots whose source instance no longer exists")
+	snapshotListCmd.Flags().String("created-after", "",
+		`only list snapshots created after the specified time (RFC3339 timestamp, date or relative duration e.g. "90d")`)
+	snapshotListCmd.Flags().String("created-before", "",
+		`only list snapshots created before the specified time (RFC3339 timestamp, date or relative duration e.g. "90d")`)
+	snapshotCmd.AddCommand(snapshotListCmd)
 }
 
-func listSnapshots(instances []string) (outputter, error) {
+// snapshotCreatedAt returns the creation time of a snapshot, or nil if it
+// can't be parsed.
+func snapshotCreatedAt(snapshot *egoscale.Snapshot) *time.Time {
+	t, err := time.Parse("2006-01-02T15:04:05-0700", snapshot.Created)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func listSnapshots(instances []string, createdAt createdAtFilter) (outputter, error) {
 	out := snapshotListOutput{}
 
 	if len(instances) == 0 {
@@ -50,6 +135,9 @@ func listSnapshots(instances []string) (outputter, error) {
 
 		for _, s := range snapshots {
 			snapshot := s.(*egoscale.Snapshot)
+			if !createdAt.match(snapshotCreatedAt(snapshot)) {
+				continue
+			}
 			instance := snapshotVMName(*snapshot)
 
 			out = append(out, snapshotListItemOutput{
@@ -85,6 +173,9 @@ func listSnapshots(instances []string) (outputter, error) {
 
 		for _, s := range snapshots {
 			snapshot := s.(*egoscale.Snapshot)
+			if !createdAt.match(snapshotCreatedAt(snapshot)) {
+				continue
+			}
 
 			out = append(out, snapshotListItemOutput{
 				ID:       snapshot.ID.String(),
@@ -104,3 +195,146 @@ func snapshotVMName(snapshot egoscale.Snapshot) string {
 	names := strings.SplitN(snapshot.Name, "_"+snapshot.VolumeName+"_", 2)
 	return names[0]
 }
+
+// listZonesSnapshots lists the snapshots of the specified zones matching the
+// createdAt filter, only the orphaned ones if orphanedOnly is true. The zones
+// are processed concurrently. If an error occurs in some zones, the
+// snapshots of the other zones are returned along with the error.
+func listZonesSnapshots(zones []string, createdAt createdAtFilter, orphanedOnly bool) (snapshotListZonesOutput, error) {
+	var (
+		mu  sync.Mutex
+		out = make(snapshotListZonesOutput, 0)
+	)
+
+	err := forEachZone(zones, func(zone string) error {
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+		snapshots, err := cs.ListSnapshots(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("unable to list snapshots in zone %s: %v", zone, err)
+		}
+
+		// Only the snapshots matching the creation time filter are
+		// kept, to limit the instance lookups.
+		filtered := make([]*exov2.Snapshot, 0, len(snapshots))
+		for _, s := range snapshots {
+			if createdAt.match(s.CreatedAt) {
+				filtered = append(filtered, s)
+			}
+		}
+
+		instances, err := cs.ListInstances(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("unable to list instances in zone %s: %v", zone, err)
+		}
+
+		names := make(map[string]string, len(instances))
+		for _, i := range instances {
+			names[*i.ID] = defaultString(i.Name, "")
+		}
+
+		err = resolveSnapshotsInstances(filtered, names, func(id string) (*exov2.Instance, error) {
+			return cs.GetInstance(ctx, zone, id)
+		})
+		if err != nil {
+			return fmt.Errorf("unable to look up snapshots instances in zone %s: %v", zone, err)
+		}
+
+		items := make(snapshotListZonesOutput, 0, len(filtered))
+		for _, s := range filtered {
+			item := snapshotListZonesItemOutput{
+				ID:         *s.ID,
+				Name:       defaultString(s.Name, ""),
+				Zone:       zone,
+				InstanceID: defaultString(s.InstanceID, ""),
+				State:      defaultString(s.State, ""),
+			}
+			if s.CreatedAt != nil {
+				item.Date = s.CreatedAt.UTC().String()
+			}
+
+			name, ok := names[item.InstanceID]
+			item.Instance = name
+			item.Orphaned = item.InstanceID != "" && !ok
+
+			if orphanedOnly && !item.Orphaned {
+				continue
+			}
+			items = append(items, item)
+		}
+
+		mu.Lock()
+		out = append(out, items...)
+		mu.Unlock()
+
+		return nil
+	})
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Zone != out[j].Zone {
+			return out[i].Zone < out[j].Zone
+		}
+		return out[i].Date < out[j].Date
+	})
+
+	return out, err
+}
+
+// resolveSnapshotsInstances looks up the source instances of the snapshots
+// missing from the names map (indexed by instance ID), adding the ones found
+// to it: the instances still missing afterwards no longer exist. Each
+// instance is looked up once, concurrently using the getInstance function.
+// Lookup errors other than a non-existent instance are returned, so that
+// snapshots are never reported as orphaned by mistake.
+func resolveSnapshotsInstances(
+	snapshots []*exov2.Snapshot,
+	names map[string]string,
+	getInstance func(string) (*exov2.Instance, error),
+) error {
+	lookups := make(map[string]bool)
+	for _, s := range snapshots {
+		if s.InstanceID == nil {
+			continue
+		}
+		if _, ok := names[*s.InstanceID]; !ok {
+			lookups[*s.InstanceID] = true
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		workers = make(chan struct{}, snapshotLookupParallelism)
+		errs    []error
+	)
+
+	for id := range lookups {
+		id := id
+
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() { <-workers; wg.Done() }()
+
+			instance, err := getInstance(id)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err == nil:
+				names[id] = defaultString(instance.Name, "")
+			case !errors.Is(err, exoapi.ErrNotFound):
+				errs = append(errs, fmt.Errorf("instance %s: %v", id, err))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
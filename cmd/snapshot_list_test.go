@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resolveSnapshotsInstances(t *testing.T) {
+	snapshot := func(instanceID string) *egoscale.Snapshot {
+		return &egoscale.Snapshot{InstanceID: &instanceID}
+	}
+
+	var calls int32
+	getInstance := func(id string) (*egoscale.Instance, error) {
+		atomic.AddInt32(&calls, 1)
+		switch id {
+		case "i2":
+			name := "web2"
+			return &egoscale.Instance{ID: &id, Name: &name}, nil
+		case "i4":
+			return nil, errors.New("API error")
+		default:
+			return nil, exoapi.ErrNotFound
+		}
+	}
+
+	// Listed instances are not looked up, and each missing instance is
+	// looked up only once.
+	names := map[string]string{"i1": "web1"}
+	require.NoError(t, resolveSnapshotsInstances(
+		[]*egoscale.Snapshot{snapshot("i1"), snapshot("i2"), snapshot("i3"), snapshot("i3"), {}},
+		names,
+		getInstance,
+	))
+	require.Equal(t, int32(2), calls)
+	require.Equal(t, map[string]string{"i1": "web1", "i2": "web2"}, names)
+
+	// Lookup errors must not be mistaken for deleted instances.
+	err := resolveSnapshotsInstances([]*egoscale.Snapshot{snapshot("i4")}, map[string]string{}, getInstance)
+	require.EqualError(t, err, "instance i4: API error")
+}
+
+func TestIntegrationSnapshotPruneOrphaned(t *testing.T) {
+	server := setupIntegrationTest(t)
+
+	_, code := runCLI(t, "vm", "snapshot", "prune", "--orphaned", "--older-than", "2021-01-02", "--force")
+	require.Equal(t, 0, code)
+
+	// Only the snapshot older than the limit of the deleted instance must
+	// be deleted.
+	server.request(http.MethodDelete, "/snapshot/5a1b2c3d-0000-4000-8000-000000000003", 0)
+	for _, r := range server.requests {
+		if r.Method == http.MethodDelete {
+			require.Equal(t, "/snapshot/5a1b2c3d-0000-4000-8000-000000000003", r.Path)
+		}
+	}
+}
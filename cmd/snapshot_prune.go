@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cobra"
+)
+
+var snapshotPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete orphaned or old snapshots",
+	Long: `This command deletes the snapshots whose source instance no longer exists
+using the "--orphaned" flag, and/or the snapshots created before a point in
+time using the "--older-than" flag (RFC3339 timestamp, date or relative
+duration e.g. "30d"). If both flags are specified, only the snapshots
+matching both criteria are deleted.
+
+The snapshots of the current account's default zone are pruned, or of all
+zones using the "--all-zones" flag. Use the "exo vm snapshot list" command
+with the same flags to review the snapshots to be deleted beforehand.
+
+Example:
+
+    # Delete the snapshots of deleted instances older than 30 days
+    exo vm snapshot prune --orphaned --older-than 30d --all-zones
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The snapshots are deleted concurrently, in a single wrapped
+		// operation.
+		if gNoWait {
+			return errNoWaitUnsupported("by this command")
+		}
+
+		allZonesFlag, err := cmd.Flags().GetBool("all-zones")
+		if err != nil {
+			return err
+		}
+
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		olderThan, err := cmd.Flags().GetString("older-than")
+		if err != nil {
+			return err
+		}
+
+		orphaned, err := cmd.Flags().GetBool("orphaned")
+		if err != nil {
+			return err
+		}
+
+		if !orphaned && olderThan == "" {
+			cmdExitOnUsageError(cmd, `at least one of the "--orphaned" and "--older-than" flags must be specified`)
+		}
+
+		var createdAt createdAtFilter
+		if olderThan != "" {
+			if createdAt.before, err = parseTimeFilter(olderThan, time.Now()); err != nil {
+				return fmt.Errorf("--older-than: %s", err)
+			}
+		}
+
+		zones := []string{gCurrentAccount.DefaultZone}
+		if allZonesFlag {
+			zones = allZones
+		}
+
+		// Unlike listing, pruning based on incomplete results is not
+		// an option.
+		snapshots, err := listZonesSnapshots(zones, createdAt, orphaned)
+		if err != nil {
+			return err
+		}
+
+		if len(snapshots) == 0 {
+			if !gQuiet {
+				fmt.Println("No snapshot to prune")
+			}
+			return nil
+		}
+
+		if !force {
+			if !askQuestion(fmt.Sprintf("Are you sure you want to delete %d snapshot(s)?", len(snapshots))) {
+				return nil
+			}
+		}
+
+		return pruneSnapshots(snapshots)
+	},
+}
+
+// pruneSnapshots deletes the specified snapshots concurrently, returning the
+// errors of the deletions that failed.
+func pruneSnapshots(snapshots snapshotListZonesOutput) error {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		workers = make(chan struct{}, snapshotLookupParallelism)
+		errs    *multierror.Error
+	)
+
+	return decorateAsyncOperation(fmt.Sprintf("Deleting %d snapshot(s)...", len(snapshots)), func() error {
+		for _, s := range snapshots {
+			s := s
+
+			wg.Add(1)
+			workers <- struct{}{}
+			go func() {
+				defer func() { <-workers; wg.Done() }()
+
+				ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, s.Zone))
+				if err := cs.DeleteSnapshot(ctx, s.Zone, s.ID); err != nil {
+					mu.Lock()
+					errs = multierror.Append(errs, fmt.Errorf("unable to delete snapshot %s in zone %s: %v", s.ID, s.Zone, err))
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		return errs.ErrorOrNil()
+	})
+}
+
+func init() {
+	snapshotPruneCmd.Flags().Bool("all-zones", false, "prune the snapshots of all zones")
+	snapshotPruneCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
+	snapshotPruneCmd.Flags().String("older-than", "",
+		`only delete the snapshots created before the specified time (RFC3339 timestamp, date or relative duration e.g. "30d")`)
+	snapshotPruneCmd.Flags().Bool("orphaned", false, "only delete the snapshots whose source instance no longer exists")
+	snapshotCmd.AddCommand(snapshotPruneCmd)
+}
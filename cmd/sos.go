@@ -57,13 +57,6 @@ var sosCmd = &cobra.Command{
 	TraverseChildren: true,
 
 	Hidden: true,
-	PersistentPreRun: func(_ *cobra.Command, _ []string) {
-		fmt.Fprintln(os.Stderr,
-			`**********************************************************************
-The "exo sos" commands are deprecated and replaced by "exo storage",
-they will be removed in a future version.
-**********************************************************************`)
-	},
 }
 
 type sosClient struct {
@@ -208,5 +201,6 @@ func init() {
 	minio.MaxRetry = minioMaxRetry
 
 	RootCmd.AddCommand(sosCmd)
+	cmdDeprecateCommand(sosCmd, "exo storage", "1.42.0")
 	sosCmd.PersistentFlags().String("certs-file", "", "Path to file containing additional SOS API X.509 certificates")
 }
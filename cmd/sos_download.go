@@ -112,6 +112,7 @@ var downloadCmd = &cobra.Command{
 			mpb.WithWidth(64),
 			// override default 120ms refresh rate
 			mpb.WithRefreshRate(180*time.Millisecond),
+			mpb.ContainerOptOn(mpb.WithOutput(nil), decorationsDisabled),
 		)
 
 		bar := progress.AddBar(objectStat.Size,
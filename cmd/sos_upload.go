@@ -149,7 +149,7 @@ var sosUploadCmd = &cobra.Command{
 			mpb.WithWidth(64),
 			// override default 120ms refresh rate
 			mpb.WithRefreshRate(180*time.Millisecond),
-			mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool { return gQuiet }),
+			mpb.ContainerOptOn(mpb.WithOutput(nil), decorationsDisabled),
 		)
 		taskWG.Add(lenFileToUpload)
 
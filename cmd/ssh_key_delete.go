@@ -42,8 +42,8 @@ func (c *computeSSHKeyDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	}
 
 	var err error
-	decorateAsyncOperation(fmt.Sprintf("Deleting SSH key %s...", c.Name), func() {
-		err = cs.DeleteSSHKey(ctx, gCurrentAccount.DefaultZone, c.Name)
+	err = decorateAsyncOperation(fmt.Sprintf("Deleting SSH key %s...", c.Name), func() error {
+		return cs.DeleteSSHKey(ctx, gCurrentAccount.DefaultZone, c.Name)
 	})
 	if err != nil {
 		return err
@@ -57,8 +57,9 @@ func (c *computeSSHKeyRegisterCmd) cmdRun(cmd *cobra.Command, _ []string) error
 		return err
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Registering SSH key %q...", c.Name), func() {
+	err = decorateAsyncOperation(fmt.Sprintf("Registering SSH key %q...", c.Name), func() error {
 		sshKey, err = cs.RegisterSSHKey(ctx, gCurrentAccount.DefaultZone, c.Name, string(publicKey))
+		return err
 	})
 	if err != nil {
 		return err
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/exoscale/cli/table"
@@ -19,6 +21,9 @@ const (
 	twitterURL        = "https://twitter.com/exoscalestatus"
 )
 
+// statusServiceOperational is the state of the services operating normally.
+const statusServiceOperational = "operational"
+
 // ServiceStatus represents the state of a service
 type ServiceStatus struct {
 	State string `json:"state"`
@@ -42,39 +47,53 @@ type RunStatus struct {
 	Status map[string]ServiceStatus `json:"status"`
 }
 
-func init() {
-	RootCmd.AddCommand(&cobra.Command{
-		Use:   "status",
-		Short: "Exoscale status",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return statusShow()
-		},
-	})
+type statusServiceOutput struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
 }
 
-func statusShow() error {
-	status, err := fetchRunStatus(jsonStatusURL)
-	if err != nil {
-		return err
-	}
+type statusIncidentOutput struct {
+	Title   string    `json:"title"`
+	Status  string    `json:"status"`
+	Message string    `json:"message"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
 
+type statusMaintenanceOutput struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Date        time.Time `json:"date"`
+}
+
+type statusOutput struct {
+	Status       string                    `json:"status"`
+	Services     []statusServiceOutput     `json:"services"`
+	Incidents    []statusIncidentOutput    `json:"incidents"`
+	Maintenances []statusMaintenanceOutput `json:"maintenances"`
+}
+
+func (o *statusOutput) toJSON() { outputJSON(o) }
+func (o *statusOutput) toText() { outputText(o) }
+func (o *statusOutput) toTable() {
 	t := table.NewTable(os.Stdout)
 	t.SetHeader([]string{"Exoscale Status"})
 
+	t.Append([]string{"Status", o.Status})
+
 	buf := bytes.NewBuffer(nil)
 	st := table.NewEmbeddedTable(buf)
-	for service, status := range status.Status {
-		st.Append([]string{service, status.State})
+	for _, s := range o.Services {
+		st.Append([]string{s.Name, s.State})
 	}
 	st.Render()
-
 	t.Append([]string{"Services", buf.String()})
 
 	buf = bytes.NewBuffer([]byte("n/a"))
-	if len(status.Incidents) > 0 {
+	if len(o.Incidents) > 0 {
 		buf.Reset()
 		it := table.NewEmbeddedTable(buf)
-		for _, i := range status.Incidents {
+		for _, i := range o.Incidents {
 			it.Append([]string{i.Title, i.Status, fmt.Sprint(i.Created), fmt.Sprint(i.Updated)})
 		}
 		it.Render()
@@ -82,10 +101,10 @@ func statusShow() error {
 	t.Append([]string{"Incidents", buf.String()})
 
 	buf = bytes.NewBuffer([]byte("n/a"))
-	if len(status.UpcomingMaintenances) > 0 {
+	if len(o.Maintenances) > 0 {
 		buf.Reset()
 		mt := table.NewEmbeddedTable(buf)
-		for _, m := range status.UpcomingMaintenances {
+		for _, m := range o.Maintenances {
 			mt.Append([]string{m.Title, m.Description, fmt.Sprint(m.Date)})
 		}
 		mt.Render()
@@ -95,13 +114,123 @@ func statusShow() error {
 	t.Render()
 
 	fmt.Println("Updates available at", twitterURL)
+}
 
-	return nil
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Exoscale status",
+	Long: fmt.Sprintf(`This command shows the status of the Exoscale services, as reported by
+%s.
+
+Using the "--zone" flag, only the services of the specified zone (and the
+services not specific to a zone) are reported. The command exits with an
+error if any reported service is not operational, e.g. to check the
+platform health before a deployment.
+
+Supported output template annotations: %s`,
+		statusURL,
+		strings.Join(outputterTemplateAnnotations(&statusOutput{}), ", ")),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zone, err := cmd.Flags().GetString("zone")
+		if err != nil {
+			return err
+		}
+
+		status, err := fetchRunStatus(jsonStatusURL)
+		if err != nil {
+			return err
+		}
+
+		out := newStatusOutput(status, zone)
+		if err := output(out, nil); err != nil {
+			return err
+		}
+
+		if out.Status != statusServiceOperational {
+			return fmt.Errorf("some Exoscale services are not operational")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	statusCmd.Flags().StringP("zone", "z", "", "only report the services of the specified zone")
+	RootCmd.AddCommand(statusCmd)
+}
+
+// statusServiceInZone returns true if the service is relevant to the
+// specified zone, i.e. if it is either specific to that zone or not specific
+// to any known zone. All services are relevant if zone is empty.
+func statusServiceInZone(service, zone string) bool {
+	if zone == "" {
+		return true
+	}
+
+	service = strings.ToLower(service)
+	if strings.Contains(service, strings.ToLower(zone)) {
+		return true
+	}
+
+	for _, z := range allZones {
+		if strings.Contains(service, z) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newStatusOutput returns the output of the status of the services relevant
+// to the specified zone (all services if empty).
+func newStatusOutput(status *RunStatus, zone string) *statusOutput {
+	out := statusOutput{
+		Status:       statusServiceOperational,
+		Services:     make([]statusServiceOutput, 0),
+		Incidents:    make([]statusIncidentOutput, 0),
+		Maintenances: make([]statusMaintenanceOutput, 0),
+	}
+
+	for name, s := range status.Status {
+		if !statusServiceInZone(name, zone) {
+			continue
+		}
+
+		out.Services = append(out.Services, statusServiceOutput{Name: name, State: s.State})
+		if s.State != statusServiceOperational {
+			out.Status = "degraded"
+		}
+	}
+	sort.Slice(out.Services, func(i, j int) bool { return out.Services[i].Name < out.Services[j].Name })
+
+	for _, i := range status.Incidents {
+		out.Incidents = append(out.Incidents, statusIncidentOutput{
+			Title:   i.Title,
+			Status:  i.Status,
+			Message: i.Message,
+			Created: i.Created,
+			Updated: i.Updated,
+		})
+	}
+
+	for _, m := range status.UpcomingMaintenances {
+		out.Maintenances = append(out.Maintenances, statusMaintenanceOutput{
+			Title:       m.Title,
+			Description: m.Description,
+			Date:        m.Date,
+		})
+	}
+
+	return &out
 }
 
 func fetchRunStatus(url string) (*RunStatus, error) {
-	// XXX need gContext
-	r, err := http.Get(url)
+	req, err := http.NewRequestWithContext(gContext, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_statusServiceInZone(t *testing.T) {
+	require.True(t, statusServiceInZone("Compute ch-gva-2", ""))
+	require.True(t, statusServiceInZone("Compute CH-GVA-2", "ch-gva-2"))
+	require.True(t, statusServiceInZone("Portal", "ch-gva-2"))
+	require.False(t, statusServiceInZone("Compute de-fra-1", "ch-gva-2"))
+}
+
+func Test_newStatusOutput(t *testing.T) {
+	status := &RunStatus{Status: map[string]ServiceStatus{
+		"Portal":           {State: "operational"},
+		"Compute ch-gva-2": {State: "operational"},
+		"Compute de-fra-1": {State: "major_outage"},
+	}}
+
+	out := newStatusOutput(status, "")
+	require.Equal(t, "degraded", out.Status)
+	require.Len(t, out.Services, 3)
+	require.Equal(t, "Compute ch-gva-2", out.Services[0].Name)
+
+	out = newStatusOutput(status, "ch-gva-2")
+	require.Equal(t, statusServiceOperational, out.Status)
+	require.Equal(t, []statusServiceOutput{
+		{Name: "Compute ch-gva-2", State: "operational"},
+		{Name: "Portal", State: "operational"},
+	}, out.Services)
+}
+
+func Test_fetchRunStatus(t *testing.T) {
+	gContext = context.Background()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", statusContentPage)
+		_, _ = w.Write([]byte(`{"status":{"Portal":{"state":"operational"}},"incidents":[{"title":"Outage","status":"resolved"}]}`))
+	}))
+	defer ts.Close()
+
+	status, err := fetchRunStatus(ts.URL)
+	require.NoError(t, err)
+	require.Equal(t, "operational", status.Status["Portal"].State)
+	require.Len(t, status.Incidents, 1)
+	require.Equal(t, "Outage", status.Incidents[0].Title)
+}
@@ -134,6 +134,7 @@ We apologize for the inconvenience.
 type storageClient struct {
 	*s3.Client
 
+	account   *account
 	zone      string
 	certsFile string
 }
@@ -243,6 +244,13 @@ func (c *storageClient) copyObject(bucket, key string) (*s3.CopyObjectInput, err
 
 type storageClientOpt func(*storageClient) error
 
+// storageClientOptWithAccount sets the account to use instead of the current
+// account. If the zone is to be set using an option, this option must be
+// specified first.
+func storageClientOptWithAccount(a *account) storageClientOpt {
+	return func(c *storageClient) error { c.account = a; c.zone = a.DefaultZone; return nil }
+}
+
 func storageClientOptWithZone(zone string) storageClientOpt {
 	return func(c *storageClient) error { c.zone = zone; return nil }
 }
@@ -259,9 +267,9 @@ func storageClientOptZoneFromBucket(bucket string) storageClientOpt {
 				awsconfig.WithEndpointResolver(aws.EndpointResolverFunc(
 					func(service, region string) (aws.Endpoint, error) {
 						sosURL := strings.Replace(
-							gCurrentAccount.SosEndpoint,
+							c.account.SosEndpoint,
 							"{zone}",
-							gCurrentAccount.DefaultZone,
+							c.account.DefaultZone,
 							1,
 						)
 						return aws.Endpoint{URL: sosURL}, nil
@@ -286,7 +294,8 @@ func storageClientOptZoneFromBucket(bucket string) storageClientOpt {
 func newStorageClient(opts ...storageClientOpt) (*storageClient, error) {
 	var (
 		client = storageClient{
-			zone: gCurrentAccount.DefaultZone,
+			account: gCurrentAccount,
+			zone:    gCurrentAccount.DefaultZone,
 		}
 
 		caCerts io.Reader
@@ -313,7 +322,7 @@ func newStorageClient(opts ...storageClientOpt) (*storageClient, error) {
 
 			awsconfig.WithEndpointResolver(aws.EndpointResolverFunc(
 				func(service, region string) (aws.Endpoint, error) {
-					sosURL := strings.Replace(gCurrentAccount.SosEndpoint, "{zone}", client.zone, 1)
+					sosURL := strings.Replace(client.account.SosEndpoint, "{zone}", client.zone, 1)
 					return aws.Endpoint{
 						URL:           sosURL,
 						SigningRegion: client.zone,
@@ -321,8 +330,8 @@ func newStorageClient(opts ...storageClientOpt) (*storageClient, error) {
 				})),
 
 			awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-				gCurrentAccount.Key,
-				gCurrentAccount.APISecret(),
+				client.account.Key,
+				client.account.APISecret(),
 				"")),
 
 			awsconfig.WithCustomCABundle(caCerts),
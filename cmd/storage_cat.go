@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+const defaultStorageCatMaxSize = "4MiB"
+
+var storageCatCmd = &cobra.Command{
+	Use:   "cat sos://BUCKET/OBJECT",
+	Short: "Print an object content",
+	Long: `This command prints the content of an object to the standard output.
+
+As a safeguard, objects larger than the size specified using the
+"--max-size" flag (default: ` + defaultStorageCatMaxSize + `) are not printed; "--max-size 0"
+disables the limit. A specific version of an object stored in a bucket with
+versioning enabled can be printed using the "--version-id" flag.
+`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bucket, key, err := parseStorageObjectPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		certsFile, err := cmd.Flags().GetString("certs-file")
+		if err != nil {
+			return err
+		}
+
+		maxSizeFlag, err := cmd.Flags().GetString("max-size")
+		if err != nil {
+			return err
+		}
+		maxSize, err := humanize.ParseBytes(maxSizeFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --max-size value: %s", err)
+		}
+
+		versionID, err := cmd.Flags().GetString("version-id")
+		if err != nil {
+			return err
+		}
+
+		storage, err := newStorageClient(
+			storageClientOptWithCertsFile(certsFile),
+			storageClientOptZoneFromBucket(bucket),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to initialize storage client: %s", err)
+		}
+
+		return storage.catObject(bucket, key, versionID, int64(maxSize), os.Stdout)
+	},
+}
+
+func init() {
+	storageCatCmd.Flags().String("max-size", defaultStorageCatMaxSize,
+		"maximum size of the object to print (e.g. 10MiB, 0 for no limit)")
+	storageCatCmd.Flags().String("version-id", "", "version of the object to print")
+	storageCmd.AddCommand(storageCatCmd)
+}
+
+// catObject writes the content of the object (or of a specific version of the
+// object if versionID is not empty) to w, failing if the object is larger than
+// maxSize bytes (a zero maxSize meaning no limit).
+func (c *storageClient) catObject(bucket, key, versionID string, maxSize int64, w io.Writer) error {
+	input := s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if versionID != "" {
+		input.VersionId = aws.String(versionID)
+	}
+
+	object, err := c.GetObject(gContext, &input)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve object: %s", err)
+	}
+	defer object.Body.Close()
+
+	if maxSize > 0 && object.ContentLength > maxSize {
+		return fmt.Errorf(
+			"object size (%s) exceeds the maximum size (%s), use --max-size to override",
+			humanize.IBytes(uint64(object.ContentLength)),
+			humanize.IBytes(uint64(maxSize)))
+	}
+
+	if _, err := io.Copy(w, object.Body); err != nil {
+		return fmt.Errorf("unable to read object: %s", err)
+	}
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -89,16 +91,16 @@ argument with "/":
 			return fmt.Errorf("unable to initialize storage client: %s", err)
 		}
 
+		// The objects deleted are reported even if some could not be.
 		deleted, err := storage.deleteObjects(bucket, prefix, recursive)
-		if err != nil {
-			return fmt.Errorf("unable to delete objects: %s", err)
-		}
-
 		if verbose {
 			for _, o := range deleted {
 				fmt.Println(aws.ToString(o.Key))
 			}
 		}
+		if err != nil {
+			return fmt.Errorf("unable to delete objects: %s", err)
+		}
 
 		return nil
 	},
@@ -122,9 +124,13 @@ func (c *storageClient) deleteObjects(bucket, prefix string, recursive bool) ([]
 	}
 
 	// The S3 DeleteObjects API call is limited to 1000 keys per call, as a
-	// precaution we're batching deletes.
+	// precaution we're batching deletes. A failing batch doesn't prevent the
+	// following ones from being processed, the errors being reported once
+	// all the batches have been processed.
 	maxKeys := 1000
 	deleted := make([]s3types.DeletedObject, 0)
+	failed := make([]s3types.Error, 0)
+	batchErrors := make([]string, 0)
 
 	for i := 0; i < len(deleteList); i += maxKeys {
 		j := i + maxKeys
@@ -137,11 +143,47 @@ func (c *storageClient) deleteObjects(bucket, prefix string, recursive bool) ([]
 			Delete: &s3types.Delete{Objects: deleteList[i:j]},
 		})
 		if err != nil {
-			return nil, err
+			// Once the CLI has been interrupted, no need to go on.
+			if gContext.Err() != nil {
+				return deleted, err
+			}
+			batchErrors = append(batchErrors, fmt.Sprintf("unable to delete %d object(s) (%s to %s): %s",
+				j-i, aws.ToString(deleteList[i].Key), aws.ToString(deleteList[j-1].Key), err))
+			continue
 		}
 
 		deleted = append(deleted, res.Deleted...)
+		failed = append(failed, res.Errors...)
+	}
+
+	if len(failed) > 0 {
+		batchErrors = append(batchErrors, c.deleteObjectsError(bucket, failed).Error())
+	}
+	if len(batchErrors) > 0 {
+		return deleted, errors.New(strings.Join(batchErrors, "\n"))
 	}
 
 	return deleted, nil
 }
+
+// deleteObjectsError returns an error describing the objects which failed
+// to be deleted, detailing the retention or legal hold preventing the
+// deletion of objects stored in buckets with object lock enabled.
+func (c *storageClient) deleteObjectsError(bucket string, errs []s3types.Error) error {
+	messages := make([]string, len(errs))
+
+	for i, e := range errs {
+		key := aws.ToString(e.Key)
+		messages[i] = fmt.Sprintf("%s: %s", key, aws.ToString(e.Message))
+
+		if aws.ToString(e.Code) == "AccessDenied" {
+			if retention, legalHold, err := c.getObjectLockStatus(bucket, key); err == nil {
+				if msg := storageObjectLockDeleteError(key, retention, legalHold, time.Now()); msg != "" {
+					messages[i] = msg
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("%d object(s) could not be deleted:\n%s", len(errs), strings.Join(messages, "\n"))
+}
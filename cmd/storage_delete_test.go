@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func hasQueryParam(r *http.Request, name string) bool {
+	_, ok := r.URL.Query()[name]
+	return ok
+}
+
+func Test_storageClient_deleteObjects(t *testing.T) {
+	const objects = 2500
+
+	var (
+		batches [][]string
+		mu      sync.Mutex
+	)
+
+	storage := newTestStorageClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+			fmt.Fprint(w, `<ListBucketResult><IsTruncated>false</IsTruncated>`)
+			for i := 0; i < objects; i++ {
+				fmt.Fprintf(w, `<Contents><Key>data/%04d</Key><Size>1</Size></Contents>`, i)
+			}
+			fmt.Fprint(w, `</ListBucketResult>`)
+
+		case r.Method == http.MethodPost && hasQueryParam(r, "delete"):
+			body, _ := ioutil.ReadAll(r.Body)
+			var req struct {
+				Objects []struct{ Key string } `xml:"Object"`
+			}
+			if err := xml.Unmarshal(body, &req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			mu.Lock()
+			batch := len(batches)
+			keys := make([]string, len(req.Objects))
+			for i, o := range req.Objects {
+				keys[i] = o.Key
+			}
+			batches = append(batches, keys)
+			mu.Unlock()
+
+			switch batch {
+			case 0: // The first batch fails altogether.
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+
+			case 1: // The second batch fails for one object.
+				fmt.Fprint(w, `<DeleteResult>`)
+				for _, k := range keys[1:] {
+					fmt.Fprintf(w, `<Deleted><Key>%s</Key></Deleted>`, k)
+				}
+				fmt.Fprintf(w, `<Error><Key>%s</Key><Code>InternalError</Code><Message>oops</Message></Error>`, keys[0])
+				fmt.Fprint(w, `</DeleteResult>`)
+
+			default:
+				fmt.Fprint(w, `<DeleteResult>`)
+				for _, k := range keys {
+					fmt.Fprintf(w, `<Deleted><Key>%s</Key></Deleted>`, k)
+				}
+				fmt.Fprint(w, `</DeleteResult>`)
+			}
+
+		default:
+			http.Error(w, "unexpected request", http.StatusNotFound)
+		}
+	})
+
+	deleted, err := storage.deleteObjects("my-bucket", "data/", true)
+
+	// The failing batches don't prevent the following ones from being
+	// processed, and all the errors are reported.
+	require.Len(t, batches, 3)
+	require.Len(t, batches[0], 1000)
+	require.Len(t, batches[2], 500)
+	require.Len(t, deleted, 999+500)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unable to delete 1000 object(s) (data/0000 to data/0999)")
+	require.Contains(t, err.Error(), "1 object(s) could not be deleted:\ndata/1000: oops")
+}
+
+func Test_storageClient_requireObjectLock(t *testing.T) {
+	var status int
+	var response string
+
+	storage := newTestStorageClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !hasQueryParam(r, "object-lock") {
+			http.Error(w, "unexpected request", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, response)
+	})
+
+	status, response = http.StatusOK,
+		`<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>`
+	require.NoError(t, storage.requireObjectLock("my-bucket"))
+
+	status, response = http.StatusNotFound,
+		`<Error><Code>ObjectLockConfigurationNotFoundError</Code><Message>not found</Message></Error>`
+	err := storage.requireObjectLock("my-bucket")
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), `bucket "my-bucket" doesn't have object lock enabled`), err.Error())
+
+	// Other errors must not be reported as object lock being disabled.
+	status, response = http.StatusForbidden,
+		`<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`
+	err = storage.requireObjectLock("my-bucket")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unable to retrieve bucket "my-bucket" object lock configuration`)
+	require.Contains(t, err.Error(), "AccessDenied")
+}
@@ -206,9 +206,7 @@ func (c *storageClient) downloadFile(bucket string, object *s3types.Object, dst
 	maxFilenameLen := 16
 
 	pb := mpb.NewWithContext(gContext,
-		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool {
-			return gQuiet
-		}),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), decorationsDisabled),
 	)
 
 	bar := pb.AddBar(
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+)
+
+var storageLegalHoldCmd = &cobra.Command{
+	Use:   "legal-hold",
+	Short: "Manage objects legal hold",
+	Long: `These commands allow you to place or remove a legal hold on objects stored
+in buckets created with object lock enabled (see "exo storage mb --object-lock").
+
+An object under legal hold cannot be deleted or overwritten until the legal
+hold is removed, regardless of its retention settings.
+`,
+}
+
+func init() {
+	storageCmd.AddCommand(storageLegalHoldCmd)
+
+	for _, status := range []s3types.ObjectLockLegalHoldStatus{
+		s3types.ObjectLockLegalHoldStatusOn,
+		s3types.ObjectLockLegalHoldStatusOff,
+	} {
+		storageLegalHoldCmd.AddCommand(newStorageLegalHoldCmd(status))
+	}
+}
+
+func newStorageLegalHoldCmd(status s3types.ObjectLockLegalHoldStatus) *cobra.Command {
+	verb := strings.ToLower(string(status))
+
+	return &cobra.Command{
+		Use:   verb + " sos://BUCKET/OBJECT",
+		Short: fmt.Sprintf("Turn %s an object legal hold", verb),
+		Long: fmt.Sprintf(`This command turns %s the legal hold of an object.
+
+Supported output template annotations: %s`,
+			verb,
+			strings.Join(outputterTemplateAnnotations(&storageRetentionShowOutput{}), ", ")),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				cmdExitOnUsageError(cmd, "invalid arguments")
+			}
+
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, key, err := parseStorageObjectPath(args[0])
+			if err != nil {
+				return err
+			}
+
+			certsFile, err := cmd.Flags().GetString("certs-file")
+			if err != nil {
+				return err
+			}
+
+			storage, err := newStorageClient(
+				storageClientOptWithCertsFile(certsFile),
+				storageClientOptZoneFromBucket(bucket),
+			)
+			if err != nil {
+				return fmt.Errorf("unable to initialize storage client: %s", err)
+			}
+
+			if err := storage.setObjectLegalHold(bucket, key, status); err != nil {
+				return fmt.Errorf("unable to set object legal hold: %s", err)
+			}
+
+			if !gQuiet {
+				return output(storage.showObjectRetention(bucket, key))
+			}
+
+			return nil
+		},
+	}
+}
+
+func (c *storageClient) setObjectLegalHold(bucket, key string, status s3types.ObjectLockLegalHoldStatus) error {
+	if err := c.requireObjectLock(bucket); err != nil {
+		return err
+	}
+
+	_, err := c.PutObjectLegalHold(gContext, &s3.PutObjectLegalHoldInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		LegalHold: &s3types.ObjectLockLegalHold{Status: status},
+	})
+
+	return err
+}
@@ -1,15 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/dustin/go-humanize"
 	"github.com/exoscale/egoscale"
 	"github.com/spf13/cobra"
@@ -44,6 +47,66 @@ func (o *storageListObjectsOutput) toTable() {
 	}
 }
 
+type storageListSummaryItemOutput struct {
+	Prefix  string `json:"prefix"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+}
+
+type storageListSummaryTotalOutput struct {
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+}
+
+type storageListSummaryOutput struct {
+	Prefixes []storageListSummaryItemOutput `json:"prefixes"`
+	Total    storageListSummaryTotalOutput  `json:"total"`
+}
+
+func (o *storageListSummaryOutput) toJSON() { outputJSON(o) }
+func (o *storageListSummaryOutput) toText() { outputText(&o.Prefixes) }
+func (o *storageListSummaryOutput) toTable() {
+	table := tabwriter.NewWriter(os.Stdout,
+		0,
+		0,
+		1,
+		' ',
+		tabwriter.TabIndent)
+	defer table.Flush()
+
+	for _, p := range o.Prefixes {
+		_, _ = fmt.Fprintf(table, "%6s \t%d\t%s\n", humanize.IBytes(uint64(p.Size)), p.Objects, p.Prefix)
+	}
+
+	_, _ = fmt.Fprintf(table, "%6s \t%d\t%s\n", humanize.IBytes(uint64(o.Total.Size)), o.Total.Objects, "TOTAL")
+}
+
+// storageListSummaryCSVRowOutput is the CSV rendering of a summary entry,
+// the grand total being repeated on each row so that it isn't mistaken for
+// an entry.
+type storageListSummaryCSVRowOutput struct {
+	Prefix       string `json:"prefix"`
+	Objects      int64  `json:"objects"`
+	Size         int64  `json:"size"`
+	TotalObjects int64  `json:"total_objects"`
+	TotalSize    int64  `json:"total_size"`
+}
+
+func (o *storageListSummaryOutput) csvRows() interface{} {
+	rows := make([]storageListSummaryCSVRowOutput, len(o.Prefixes))
+	for i, p := range o.Prefixes {
+		rows[i] = storageListSummaryCSVRowOutput{
+			Prefix:       p.Prefix,
+			Objects:      p.Objects,
+			Size:         p.Size,
+			TotalObjects: o.Total.Objects,
+			TotalSize:    o.Total.Size,
+		}
+	}
+
+	return &rows
+}
+
 type storageListBucketsItemOutput struct {
 	Name    string `json:"name"`
 	Zone    string `json:"zone"`
@@ -79,12 +142,26 @@ If no argument is passed, this commands lists existing buckets. If a prefix is
 specified (e.g. "sos://my-bucket/.../") the command lists the objects stored
 in the bucket under the corresponding prefix.
 
+When the "--summarize" flag is specified, instead of listing the objects the
+command reports the number of objects and their total size per first-level
+prefix found under the specified (recursively listed) prefix, sorted by
+decreasing size. Objects located directly under the specified prefix are
+reported under the "." entry. The grand total is reported separately from
+the per-prefix entries: as a "total" object in the "json" and "yaml" output
+formats, and as "total_objects"/"total_size" columns in the "csv" output
+format. For reporting pipelines, the summary can be exported using e.g. the
+"csv" or "json" output formats:
+
+	exo storage list --summarize sos://my-bucket/ -O csv
+
 Supported output template annotations:
 
   * When listing buckets: %s
-  * When listing objects: %s`,
+  * When listing objects: %s
+  * When summarizing objects: %s`,
 		strings.Join(outputterTemplateAnnotations(&storageListBucketsItemOutput{}), ", "),
-		strings.Join(outputterTemplateAnnotations(&storageListObjectsItemOutput{}), ", ")),
+		strings.Join(outputterTemplateAnnotations(&storageListObjectsItemOutput{}), ", "),
+		strings.Join(outputterTemplateAnnotations(&storageListSummaryItemOutput{}), ", ")),
 	Aliases: gListAlias,
 
 	PreRun: func(cmd *cobra.Command, args []string) {
@@ -118,6 +195,15 @@ Supported output template annotations:
 			return err
 		}
 
+		summarize, err := cmd.Flags().GetBool("summarize")
+		if err != nil {
+			return err
+		}
+
+		if summarize && (recursive || stream) {
+			return errors.New("--summarize is mutually exclusive with --recursive and --stream")
+		}
+
 		parts := strings.SplitN(args[0], "/", 2)
 		bucket = parts[0]
 		if len(parts) > 1 {
@@ -132,6 +218,10 @@ Supported output template annotations:
 			return fmt.Errorf("unable to initialize storage client: %v", err)
 		}
 
+		if summarize {
+			return output(storage.summarizeObjects(bucket, prefix))
+		}
+
 		return output(storage.listObjects(bucket, prefix, recursive, stream))
 	},
 }
@@ -141,6 +231,8 @@ func init() {
 		"list bucket recursively")
 	storageListCmd.Flags().BoolP("stream", "s", false,
 		"stream listed files instead of waiting for complete listing (useful for large buckets)")
+	storageListCmd.Flags().Bool("summarize", false,
+		"report objects count and total size per first-level prefix instead of listing objects")
 	storageCmd.AddCommand(storageListCmd)
 }
 
@@ -223,3 +315,73 @@ func (c *storageClient) listObjects(bucket, prefix string, recursive, stream boo
 
 	return &out, nil
 }
+
+// summarizeObjects aggregates the count and total size of the objects stored
+// in the bucket under the specified prefix, grouped by first-level prefix.
+// Objects listing pages are processed as they are retrieved, so that only
+// the per-prefix counters are kept in memory regardless of the bucket size.
+func (c *storageClient) summarizeObjects(bucket, prefix string) (outputter, error) {
+	if prefix != "" && prefix != "/" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	summary := newStorageObjectsSummary(prefix)
+
+	err := c.forEachObject(bucket, prefix, true, func(o *s3types.Object) error {
+		summary.add(aws.ToString(o.Key), o.Size)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary.output(), nil
+}
+
+// storageObjectsSummary aggregates the count and total size of objects
+// grouped by first-level prefix under a prefix.
+type storageObjectsSummary struct {
+	prefix string
+	groups map[string]*storageListSummaryItemOutput
+}
+
+func newStorageObjectsSummary(prefix string) *storageObjectsSummary {
+	return &storageObjectsSummary{
+		prefix: prefix,
+		groups: make(map[string]*storageListSummaryItemOutput),
+	}
+}
+
+// add accounts for the object identified by key of the specified size.
+func (s *storageObjectsSummary) add(key string, size int64) {
+	group := "."
+	if parts := strings.SplitN(strings.TrimPrefix(key, s.prefix), "/", 2); len(parts) > 1 {
+		group = parts[0] + "/"
+	}
+
+	if _, ok := s.groups[group]; !ok {
+		s.groups[group] = &storageListSummaryItemOutput{Prefix: group}
+	}
+	s.groups[group].Objects++
+	s.groups[group].Size += size
+}
+
+// output returns the summary entries sorted by decreasing size, along with
+// their grand total.
+func (s *storageObjectsSummary) output() *storageListSummaryOutput {
+	out := storageListSummaryOutput{Prefixes: make([]storageListSummaryItemOutput, 0, len(s.groups))}
+	for _, g := range s.groups {
+		out.Prefixes = append(out.Prefixes, *g)
+		out.Total.Objects += g.Objects
+		out.Total.Size += g.Size
+	}
+
+	sort.Slice(out.Prefixes, func(i, j int) bool {
+		if out.Prefixes[i].Size == out.Prefixes[j].Size {
+			return out.Prefixes[i].Prefix < out.Prefixes[j].Prefix
+		}
+		return out.Prefixes[i].Size > out.Prefixes[j].Size
+	})
+
+	return &out
+}
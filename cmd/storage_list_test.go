@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStorageClient returns a storage client sending its requests to the
+// HTTP handler h.
+func newTestStorageClient(t *testing.T, h http.HandlerFunc) *storageClient {
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+
+	savedContext, savedAccount := gContext, gCurrentAccount
+	t.Cleanup(func() { gContext, gCurrentAccount = savedContext, savedAccount })
+	gContext = context.Background()
+	gCurrentAccount = &account{
+		Key:         "EXOtest",
+		Secret:      "test",
+		DefaultZone: "ch-gva-2",
+		SosEndpoint: server.URL,
+	}
+
+	storage, err := newStorageClient()
+	require.NoError(t, err)
+
+	return storage
+}
+
+func Test_storageClient_summarizeObjects(t *testing.T) {
+	// The requests are checked from the test goroutine, as require can't
+	// stop the test from the HTTP handler goroutine.
+	prefixes := make(chan string, 10)
+
+	storage := newTestStorageClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Query().Get("list-type") != "2" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		prefixes <- r.URL.Query().Get("prefix")
+
+		fmt.Fprint(w, `<ListBucketResult><IsTruncated>false</IsTruncated>`)
+		for key, size := range map[string]int{
+			"data/index.html":          10,
+			"data/logs/2021/06/01.log": 100,
+			"data/logs/2021/06/02.log": 200,
+			"data/images/a.png":        300,
+			"data/robots.txt":          5,
+		} {
+			fmt.Fprintf(w, `<Contents><Key>%s</Key><Size>%d</Size></Contents>`, key, size)
+		}
+		fmt.Fprint(w, `</ListBucketResult>`)
+	})
+
+	out, err := storage.summarizeObjects("test", "data")
+	require.NoError(t, err)
+	require.Equal(t, "data/", <-prefixes)
+	require.Equal(t, &storageListSummaryOutput{
+		Prefixes: []storageListSummaryItemOutput{
+			{Prefix: "images/", Objects: 1, Size: 300},
+			{Prefix: "logs/", Objects: 2, Size: 300},
+			{Prefix: ".", Objects: 2, Size: 15},
+		},
+		Total: storageListSummaryTotalOutput{Objects: 5, Size: 615},
+	}, out)
+}
+
+func Test_storageObjectsSummary(t *testing.T) {
+	summary := newStorageObjectsSummary("data/")
+	summary.add("data/index.html", 10)
+	summary.add("data/logs/2021/06/01.log", 100)
+	summary.add("data/logs/2021/06/02.log", 200)
+	summary.add("data/images/a.png", 300)
+	summary.add("data/robots.txt", 5)
+
+	out := summary.output()
+	require.Equal(t, []storageListSummaryItemOutput{
+		{Prefix: "images/", Objects: 1, Size: 300},
+		{Prefix: "logs/", Objects: 2, Size: 300},
+		{Prefix: ".", Objects: 2, Size: 15},
+	}, out.Prefixes)
+
+	// The grand total is reported separately from the summary entries.
+	require.Equal(t, storageListSummaryTotalOutput{Objects: 5, Size: 615}, out.Total)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, out.csvRows(), nil, ";", true))
+	require.Equal(t, `prefix,objects,size,total_objects,total_size
+images/,1,300,5,615
+logs/,2,300,5,615
+.,2,15,5,615
+`, buf.String())
+
+	require.Empty(t, newStorageObjectsSummary("").output().Prefixes)
+}
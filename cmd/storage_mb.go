@@ -49,6 +49,11 @@ Supported output template annotations: %s`,
 			return err
 		}
 
+		objectLock, err := cmd.Flags().GetBool("object-lock")
+		if err != nil {
+			return err
+		}
+
 		storage, err := newStorageClient(
 			storageClientOptWithCertsFile(certsFile),
 			storageClientOptWithZone(zone),
@@ -57,7 +62,7 @@ Supported output template annotations: %s`,
 			return fmt.Errorf("unable to initialize storage client: %s", err)
 		}
 
-		if err := storage.createBucket(bucket, acl); err != nil {
+		if err := storage.createBucket(bucket, acl, objectLock); err != nil {
 			return fmt.Errorf("unable to create bucket: %s", err)
 		}
 
@@ -72,12 +77,17 @@ Supported output template annotations: %s`,
 func init() {
 	storageMbCmd.Flags().String("acl", "",
 		fmt.Sprintf("canned ACL to set on bucket (%s)", strings.Join(s3BucketCannedACLToStrings(), "|")))
+	storageMbCmd.Flags().Bool("object-lock", false,
+		"enable object lock on the bucket, required for objects retention (cannot be disabled afterwards)")
 	storageMbCmd.Flags().StringP("zone", "z", "", "bucket zone")
 	storageCmd.AddCommand(storageMbCmd)
 }
 
-func (c *storageClient) createBucket(name, acl string) error {
-	s3Bucket := s3.CreateBucketInput{Bucket: aws.String(name)}
+func (c *storageClient) createBucket(name, acl string, objectLock bool) error {
+	s3Bucket := s3.CreateBucketInput{
+		Bucket:                     aws.String(name),
+		ObjectLockEnabledForBucket: objectLock,
+	}
 
 	if acl != "" {
 		if !isInList(s3BucketCannedACLToStrings(), acl) {
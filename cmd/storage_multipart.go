@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/spf13/cobra"
+)
+
+// storageMultipartAbortTimeout is the maximum duration of the abortion of
+// the multipart upload of an interrupted upload.
+const storageMultipartAbortTimeout = 30 * time.Second
+
+var storageMultipartCmd = &cobra.Command{
+	Use:   "multipart",
+	Short: "Manage incomplete multipart uploads",
+	Long: `These commands allow you to manage the incomplete multipart uploads of a
+bucket, left behind by interrupted uploads of large files. The parts already
+uploaded are stored (and billed) until the multipart upload is aborted.
+`,
+}
+
+func init() {
+	storageCmd.AddCommand(storageMultipartCmd)
+}
+
+// storageMultipartUpload represents an incomplete multipart upload.
+type storageMultipartUpload struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+	Parts     int
+	Size      int64
+}
+
+// listMultipartUploads returns the incomplete multipart uploads of a bucket,
+// sorted by initiation date.
+func (c *storageClient) listMultipartUploads(bucket string) ([]*storageMultipartUpload, error) {
+	uploads := make([]*storageMultipartUpload, 0)
+
+	var keyMarker, uploadIDMarker *string
+	for {
+		res, err := c.ListMultipartUploads(gContext, &s3.ListMultipartUploadsInput{
+			Bucket:         aws.String(bucket),
+			KeyMarker:      keyMarker,
+			UploadIdMarker: uploadIDMarker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range res.Uploads {
+			upload := storageMultipartUpload{
+				Key:      aws.ToString(u.Key),
+				UploadID: aws.ToString(u.UploadId),
+			}
+			if u.Initiated != nil {
+				upload.Initiated = *u.Initiated
+			}
+			uploads = append(uploads, &upload)
+		}
+
+		if !res.IsTruncated {
+			break
+		}
+		keyMarker, uploadIDMarker = res.NextKeyMarker, res.NextUploadIdMarker
+	}
+
+	sort.SliceStable(uploads, func(i, j int) bool { return uploads[i].Initiated.Before(uploads[j].Initiated) })
+
+	return uploads, nil
+}
+
+// getMultipartUploadParts sets the number and total size of the parts
+// uploaded so far of an incomplete multipart upload.
+func (c *storageClient) getMultipartUploadParts(bucket string, upload *storageMultipartUpload) error {
+	upload.Parts, upload.Size = 0, 0
+
+	paginator := s3.NewListPartsPaginator(c.Client, &s3.ListPartsInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(upload.Key),
+		UploadId: aws.String(upload.UploadID),
+	})
+	for paginator.HasMorePages() {
+		res, err := paginator.NextPage(gContext)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range res.Parts {
+			upload.Parts++
+			upload.Size += p.Size
+		}
+	}
+
+	return nil
+}
+
+// abortMultipartUpload aborts an incomplete multipart upload, deleting the
+// parts uploaded so far.
+func (c *storageClient) abortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	_, err := c.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+
+	return err
+}
+
+// abortInterruptedMultipartUpload aborts the multipart upload of an upload
+// which failed or was interrupted by the user. As the global context is
+// cancelled once the CLI is interrupted, a dedicated context is used.
+func (c *storageClient) abortInterruptedMultipartUpload(bucket, key, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), storageMultipartAbortTimeout)
+	defer cancel()
+
+	if err := c.abortMultipartUpload(ctx, bucket, key, uploadID); err != nil {
+		fmt.Fprintf(os.Stderr,
+			"warning: unable to abort the incomplete multipart upload %s of %s (see %q): %s\n",
+			uploadID, key, "exo storage multipart abort", err)
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var storageMultipartAbortCmd = &cobra.Command{
+	Use:   "abort sos://BUCKET[/OBJECT UPLOAD-ID]",
+	Short: "Abort incomplete multipart uploads",
+	Long: `This command aborts incomplete multipart uploads, deleting the parts
+uploaded so far. Either a specific upload is specified by its object and
+upload ID, or all the multipart uploads of the bucket are aborted using the
+"--all" flag, optionally restricted to the uploads initiated before a point
+in time using the "--older-than" flag (RFC3339 timestamp, date or relative
+duration e.g. "7d").
+
+Examples:
+
+    # Abort a specific multipart upload
+    exo storage multipart abort sos://my-bucket/backup.tar 2~a1B2c3D4e5
+
+    # Abort all the multipart uploads initiated more than a week ago
+    exo storage multipart abort sos://my-bucket --all --older-than 7d
+`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 || len(args) > 2 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+
+		args[0] = strings.TrimPrefix(args[0], storageBucketPrefix)
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+
+		certsFile, err := cmd.Flags().GetString("certs-file")
+		if err != nil {
+			return err
+		}
+
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		olderThan, err := cmd.Flags().GetString("older-than")
+		if err != nil {
+			return err
+		}
+
+		var (
+			bucket, key, uploadID string
+			before                time.Time
+		)
+		switch {
+		case all && len(args) == 1:
+			bucket = args[0]
+			if olderThan != "" {
+				if before, err = parseTimeFilter(olderThan, time.Now()); err != nil {
+					return fmt.Errorf("--older-than: %s", err)
+				}
+			}
+
+		case !all && len(args) == 2:
+			if bucket, key, err = parseStorageObjectPath(args[0]); err != nil {
+				return err
+			}
+			uploadID = args[1]
+			if olderThan != "" {
+				return errors.New("--older-than flag requires --all")
+			}
+
+		default:
+			cmdExitOnUsageError(cmd, "either an object and an upload ID or the --all flag must be specified")
+		}
+
+		storage, err := newStorageClient(
+			storageClientOptWithCertsFile(certsFile),
+			storageClientOptZoneFromBucket(bucket),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to initialize storage client: %s", err)
+		}
+
+		uploads := []*storageMultipartUpload{{Key: key, UploadID: uploadID}}
+		if all {
+			if uploads, err = storage.listMultipartUploads(bucket); err != nil {
+				return fmt.Errorf("unable to list multipart uploads: %s", err)
+			}
+
+			uploads = storageMultipartUploadsInitiatedBefore(uploads, before)
+
+			if len(uploads) == 0 {
+				if !gQuiet {
+					fmt.Println("No multipart upload to abort")
+				}
+				return nil
+			}
+		}
+
+		if !force {
+			question := fmt.Sprintf("Are you sure you want to abort the multipart upload %s of %s%s/%s?",
+				uploadID, storageBucketPrefix, bucket, key)
+			if all {
+				question = fmt.Sprintf("Are you sure you want to abort %d multipart upload(s) of %s%s?",
+					len(uploads), storageBucketPrefix, bucket)
+			}
+			if !askQuestion(question) {
+				return nil
+			}
+		}
+
+		for _, u := range uploads {
+			if err := storage.abortMultipartUpload(gContext, bucket, u.Key, u.UploadID); err != nil {
+				return fmt.Errorf("unable to abort multipart upload %s of %s: %s", u.UploadID, u.Key, err)
+			}
+
+			if !gQuiet {
+				fmt.Printf("Aborted multipart upload %s of %s%s/%s\n", u.UploadID, storageBucketPrefix, bucket, u.Key)
+			}
+		}
+
+		return nil
+	},
+}
+
+// storageMultipartUploadsInitiatedBefore returns the multipart uploads
+// initiated before the specified time, or all of them if it is zero.
+func storageMultipartUploadsInitiatedBefore(
+	uploads []*storageMultipartUpload,
+	before time.Time,
+) []*storageMultipartUpload {
+	if before.IsZero() {
+		return uploads
+	}
+
+	filtered := make([]*storageMultipartUpload, 0, len(uploads))
+	for _, u := range uploads {
+		if u.Initiated.Before(before) {
+			filtered = append(filtered, u)
+		}
+	}
+
+	return filtered
+}
+
+func init() {
+	storageMultipartAbortCmd.Flags().Bool("all", false, "abort all the multipart uploads of the bucket")
+	storageMultipartAbortCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
+	storageMultipartAbortCmd.Flags().String("older-than", "",
+		"only abort the multipart uploads initiated before the specified time (requires --all)")
+	storageMultipartCmd.AddCommand(storageMultipartAbortCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/exoscale/cli/table"
+	"github.com/spf13/cobra"
+)
+
+type storageMultipartListItemOutput struct {
+	Key       string `json:"key"`
+	UploadID  string `json:"upload_id"`
+	Initiated string `json:"initiated"`
+	Parts     int    `json:"parts"`
+	Size      int64  `json:"size"`
+}
+
+type storageMultipartListOutput []storageMultipartListItemOutput
+
+func (o *storageMultipartListOutput) toJSON() { outputJSON(o) }
+func (o *storageMultipartListOutput) toText() { outputText(o) }
+func (o *storageMultipartListOutput) toTable() {
+	t := table.NewTable(os.Stdout)
+	defer t.Render()
+	t.SetHeader([]string{"Key", "Upload ID", "Initiated", "Parts", "Size"})
+
+	for _, u := range *o {
+		t.Append([]string{u.Key, u.UploadID, u.Initiated, fmt.Sprint(u.Parts), humanize.IBytes(uint64(u.Size))})
+	}
+}
+
+var storageMultipartListCmd = &cobra.Command{
+	Use:     "list sos://BUCKET",
+	Aliases: gListAlias,
+	Short:   "List incomplete multipart uploads",
+	Long: fmt.Sprintf(`This command lists the incomplete multipart uploads of a bucket, along
+with the number and total size of the parts uploaded so far.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&storageMultipartListItemOutput{}), ", ")),
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+
+		args[0] = strings.TrimPrefix(args[0], storageBucketPrefix)
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bucket := args[0]
+
+		certsFile, err := cmd.Flags().GetString("certs-file")
+		if err != nil {
+			return err
+		}
+
+		storage, err := newStorageClient(
+			storageClientOptWithCertsFile(certsFile),
+			storageClientOptZoneFromBucket(bucket),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to initialize storage client: %s", err)
+		}
+
+		uploads, err := storage.listMultipartUploads(bucket)
+		if err != nil {
+			return fmt.Errorf("unable to list multipart uploads: %s", err)
+		}
+
+		out := make(storageMultipartListOutput, 0, len(uploads))
+		for _, u := range uploads {
+			if err := storage.getMultipartUploadParts(bucket, u); err != nil {
+				return fmt.Errorf("unable to list multipart upload %s parts: %s", u.UploadID, err)
+			}
+
+			out = append(out, storageMultipartListItemOutput{
+				Key:       u.Key,
+				UploadID:  u.UploadID,
+				Initiated: u.Initiated.UTC().Format(time.RFC3339),
+				Parts:     u.Parts,
+				Size:      u.Size,
+			})
+		}
+
+		return output(&out, nil)
+	},
+}
+
+func init() {
+	storageMultipartCmd.AddCommand(storageMultipartListCmd)
+}
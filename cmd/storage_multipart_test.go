@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_storageMultipartUploadsInitiatedBefore(t *testing.T) {
+	now := time.Now()
+	uploads := []*storageMultipartUpload{
+		{Key: "old", UploadID: "1", Initiated: now.AddDate(0, 0, -10)},
+		{Key: "recent", UploadID: "2", Initiated: now.Add(-time.Hour)},
+	}
+
+	require.Equal(t, uploads, storageMultipartUploadsInitiatedBefore(uploads, time.Time{}))
+
+	filtered := storageMultipartUploadsInitiatedBefore(uploads, now.AddDate(0, 0, -7))
+	require.Len(t, filtered, 1)
+	require.Equal(t, "old", filtered[0].Key)
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+)
+
+var storageMvCmd = &cobra.Command{
+	Use:     "mv sos://BUCKET/OBJECT|PREFIX/ sos://BUCKET/[OBJECT|PREFIX/]",
+	Aliases: []string{"move", "rename"},
+	Short:   "Move objects",
+	Long: `This command moves (renames) objects, using a server-side copy followed by
+the deletion of the source object.
+
+If the destination path ends with "/", the object is moved under this
+"directory" prefix keeping its name. To move all the objects under a
+"directory" prefix, suffix the source path with "/" and use the "--recursive"
+flag:
+
+    exo storage mv sos://my-bucket/old-name sos://my-bucket/new-name
+    exo storage mv -r sos://my-bucket/old-directory/ sos://my-bucket/new-directory/
+
+A specific version of an object stored in a bucket with versioning enabled
+can be moved using the "--version-id" flag, in which case this version is
+permanently deleted from the source object.
+`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srcBucket, srcKey, err := parseStorageMvPath(args[0])
+		if err != nil {
+			return err
+		}
+		if srcKey == "" {
+			return fmt.Errorf("invalid source path %q, expected format %sBUCKET/OBJECT|PREFIX/",
+				args[0], storageBucketPrefix)
+		}
+
+		dstBucket, dstKey, err := parseStorageMvPath(args[1])
+		if err != nil {
+			return err
+		}
+
+		certsFile, err := cmd.Flags().GetString("certs-file")
+		if err != nil {
+			return err
+		}
+
+		recursive, err := cmd.Flags().GetBool("recursive")
+		if err != nil {
+			return err
+		}
+
+		verbose, err := cmd.Flags().GetBool("verbose")
+		if err != nil {
+			return err
+		}
+
+		versionID, err := cmd.Flags().GetString("version-id")
+		if err != nil {
+			return err
+		}
+
+		isPrefix := strings.HasSuffix(srcKey, "/")
+		switch {
+		case recursive && !isPrefix:
+			return fmt.Errorf("the source path must end with \"/\" with --recursive")
+		case !recursive && isPrefix:
+			return fmt.Errorf("the source path is a prefix, use --recursive to move all objects under it")
+		case recursive && versionID != "":
+			return fmt.Errorf("--version-id and --recursive flags are mutually exclusive")
+		case recursive && dstKey != "" && !strings.HasSuffix(dstKey, "/"):
+			return fmt.Errorf("the destination path must end with \"/\" with --recursive")
+		}
+
+		storage, err := newStorageClient(
+			storageClientOptWithCertsFile(certsFile),
+			storageClientOptZoneFromBucket(srcBucket),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to initialize storage client: %s", err)
+		}
+
+		moves := make(map[string]string)
+		if recursive {
+			err = storage.forEachObject(srcBucket, srcKey, true, func(o *s3types.Object) error {
+				key := aws.ToString(o.Key)
+				moves[key] = dstKey + strings.TrimPrefix(key, srcKey)
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("error listing objects to move: %s", err)
+			}
+		} else {
+			moves[srcKey] = storageMvDestinationKey(srcKey, dstKey)
+		}
+
+		srcKeys := make([]string, 0, len(moves))
+		for src := range moves {
+			srcKeys = append(srcKeys, src)
+		}
+		sort.Strings(srcKeys)
+
+		for _, src := range srcKeys {
+			dst := moves[src]
+			if srcBucket == dstBucket && src == dst {
+				return fmt.Errorf("cannot move object %q onto itself", src)
+			}
+
+			if err := storage.moveObject(srcBucket, src, versionID, dstBucket, dst); err != nil {
+				return fmt.Errorf("unable to move object %q: %s", src, err)
+			}
+
+			if verbose {
+				fmt.Printf("%s%s/%s -> %s%s/%s\n", storageBucketPrefix, srcBucket, src, storageBucketPrefix, dstBucket, dst)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	storageMvCmd.Flags().BoolP("recursive", "r", false, "move objects recursively")
+	storageMvCmd.Flags().BoolP("verbose", "v", false, "output moved objects")
+	storageMvCmd.Flags().String("version-id", "", "version of the object to move")
+	storageCmd.AddCommand(storageMvCmd)
+}
+
+// parseStorageMvPath parses a "sos://BUCKET/[KEY]" path, returning the bucket
+// name and the (possibly empty) object key or prefix.
+func parseStorageMvPath(v string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(v, storageBucketPrefix), "/", 2)
+	if parts[0] == "" {
+		return "", "", fmt.Errorf("invalid path %q, expected format %sBUCKET/[OBJECT|PREFIX/]", v, storageBucketPrefix)
+	}
+
+	if len(parts) == 1 {
+		return parts[0], "", nil
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// storageMvDestinationKey returns the key of an object moved to the
+// destination: a destination "directory" prefix (i.e. empty or ending with
+// "/") keeps the source object name.
+func storageMvDestinationKey(srcKey, dst string) string {
+	if dst == "" || strings.HasSuffix(dst, "/") {
+		return dst + path.Base(srcKey)
+	}
+
+	return dst
+}
+
+// moveObject copies the source object (or a specific version of it if
+// versionID is not empty) to the destination server-side, preserving its
+// metadata, headers and ACL, then deletes the source object (version).
+func (c *storageClient) moveObject(srcBucket, srcKey, versionID, dstBucket, dstKey string) error {
+	copySource := srcBucket + "/" + srcKey
+	if versionID != "" {
+		copySource += "?versionId=" + url.QueryEscape(versionID)
+	}
+
+	aclInput := s3.GetObjectAclInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	}
+	if versionID != "" {
+		aclInput.VersionId = aws.String(versionID)
+	}
+
+	// Object ACL are reset during a CopyObject operation,
+	// we must set them explicitly on the copied object.
+	acl, err := c.GetObjectAcl(gContext, &aclInput)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve object ACL: %s", err)
+	}
+
+	copyObject := s3.CopyObjectInput{
+		Bucket:            aws.String(dstBucket),
+		Key:               aws.String(dstKey),
+		CopySource:        aws.String(copySource),
+		MetadataDirective: s3types.MetadataDirectiveCopy,
+	}
+	storageACLToCopyObject(acl, &copyObject)
+
+	if _, err := c.CopyObject(gContext, &copyObject); err != nil {
+		return fmt.Errorf("unable to copy object: %s", err)
+	}
+
+	deleteObject := s3.DeleteObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	}
+	if versionID != "" {
+		deleteObject.VersionId = aws.String(versionID)
+	}
+
+	if _, err := c.DeleteObject(gContext, &deleteObject); err != nil {
+		return fmt.Errorf("object copied to %s%s/%s but the source could not be deleted: %s",
+			storageBucketPrefix, dstBucket, dstKey, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseStorageMvPath(t *testing.T) {
+	bucket, key, err := parseStorageMvPath("sos://my-bucket/a/b")
+	require.NoError(t, err)
+	require.Equal(t, "my-bucket", bucket)
+	require.Equal(t, "a/b", key)
+
+	bucket, key, err = parseStorageMvPath("sos://my-bucket")
+	require.NoError(t, err)
+	require.Equal(t, "my-bucket", bucket)
+	require.Equal(t, "", key)
+
+	_, _, err = parseStorageMvPath("sos:///a")
+	require.Error(t, err)
+}
+
+func Test_storageMvDestinationKey(t *testing.T) {
+	require.Equal(t, "new", storageMvDestinationKey("dir/old", "new"))
+	require.Equal(t, "other/old", storageMvDestinationKey("dir/old", "other/"))
+	require.Equal(t, "old", storageMvDestinationKey("dir/old", ""))
+}
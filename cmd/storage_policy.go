@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/exoscale/cli/table"
+	"github.com/spf13/cobra"
+)
+
+const (
+	storagePolicyVersion        = "2012-10-17"
+	storagePolicyResourcePrefix = "arn:aws:s3:::"
+)
+
+var storagePolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Manage buckets policy",
+	Long: `These commands allow you to manage the policy of a bucket.
+
+Bucket policies are JSON documents (using the AWS IAM policy syntax) allowing
+fine-grained access control to a bucket, such as restricting access to
+specific API keys or source IP addresses.
+
+Notes:
+
+  * Bucket policies are evaluated in addition to the bucket ACL: an "Allow"
+    statement can grant access not granted by the ACL, and a "Deny"
+    statement can revoke access granted by the ACL.
+`,
+}
+
+func init() {
+	storageCmd.AddCommand(storagePolicyCmd)
+}
+
+// storagePolicyStringList is a list of strings that can be represented in
+// a policy document either as a single string or as a list of strings.
+type storagePolicyStringList []string
+
+func (l *storagePolicyStringList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = storagePolicyStringList{s}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return errors.New("expected a string or a list of strings")
+	}
+	*l = list
+
+	return nil
+}
+
+// storagePolicyPrincipal is the principal of a policy statement, either the
+// "*" wildcard (i.e. anyone) or a list of AWS-style principals.
+type storagePolicyPrincipal struct {
+	AWS storagePolicyStringList `json:"AWS"`
+}
+
+func (p *storagePolicyPrincipal) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s != "*" {
+			return fmt.Errorf(`invalid principal %q, expected "*" or {"AWS": [...]}`, s)
+		}
+		p.AWS = storagePolicyStringList{"*"}
+		return nil
+	}
+
+	type principal storagePolicyPrincipal
+	var v principal
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf(`invalid principal, expected "*" or {"AWS": [...]}: %s`, err)
+	}
+	*p = storagePolicyPrincipal(v)
+
+	return nil
+}
+
+// anyone returns true if the principal matches anyone.
+func (p *storagePolicyPrincipal) anyone() bool {
+	return p != nil && isInList(p.AWS, "*")
+}
+
+type storagePolicyStatement struct {
+	Sid       string                            `json:"Sid,omitempty"`
+	Effect    string                            `json:"Effect"`
+	Principal *storagePolicyPrincipal           `json:"Principal"`
+	Action    storagePolicyStringList           `json:"Action"`
+	Resource  storagePolicyStringList           `json:"Resource"`
+	Condition map[string]map[string]interface{} `json:"Condition,omitempty"`
+}
+
+// sourceIPs returns the source IP addresses/networks the statement is
+// restricted to, if any.
+func (s *storagePolicyStatement) sourceIPs() []string {
+	ips := make([]string, 0)
+
+	for op, conditions := range s.Condition {
+		if op != "IpAddress" {
+			continue
+		}
+		for k, v := range conditions {
+			if !strings.EqualFold(k, "aws:SourceIp") {
+				continue
+			}
+			switch v := v.(type) {
+			case string:
+				ips = append(ips, v)
+			case []string:
+				ips = append(ips, v...)
+			case []interface{}:
+				for _, ip := range v {
+					ips = append(ips, fmt.Sprint(ip))
+				}
+			}
+		}
+	}
+
+	return ips
+}
+
+type storagePolicy struct {
+	Version   string                   `json:"Version"`
+	ID        string                   `json:"Id,omitempty"`
+	Statement []storagePolicyStatement `json:"Statement"`
+}
+
+// parseStoragePolicy parses and validates a bucket policy document.
+func parseStoragePolicy(bucket string, data []byte) (*storagePolicy, error) {
+	var policy storagePolicy
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("invalid policy document: %s", err)
+	}
+
+	if err := policy.validate(bucket); err != nil {
+		return nil, fmt.Errorf("invalid policy document: %s", err)
+	}
+
+	return &policy, nil
+}
+
+// validate checks that the policy document is valid for the specified
+// bucket.
+func (p *storagePolicy) validate(bucket string) error {
+	if p.Version != storagePolicyVersion {
+		return fmt.Errorf("unsupported version %q, expected %q", p.Version, storagePolicyVersion)
+	}
+
+	if len(p.Statement) == 0 {
+		return errors.New("at least one statement is required")
+	}
+
+	for i, s := range p.Statement {
+		name := fmt.Sprintf("statement #%d", i+1)
+		if s.Sid != "" {
+			name = fmt.Sprintf("statement %q", s.Sid)
+		}
+
+		if s.Effect != "Allow" && s.Effect != "Deny" {
+			return fmt.Errorf("%s: invalid effect %q, expected \"Allow\" or \"Deny\"", name, s.Effect)
+		}
+
+		if s.Principal == nil || len(s.Principal.AWS) == 0 {
+			return fmt.Errorf("%s: principal is required", name)
+		}
+
+		if len(s.Action) == 0 {
+			return fmt.Errorf("%s: at least one action is required", name)
+		}
+		for _, action := range s.Action {
+			if action != "*" && !strings.HasPrefix(action, "s3:") {
+				return fmt.Errorf("%s: invalid action %q, expected \"s3:ACTION\" or \"*\"", name, action)
+			}
+		}
+
+		if len(s.Resource) == 0 {
+			return fmt.Errorf("%s: at least one resource is required", name)
+		}
+		for _, resource := range s.Resource {
+			if resource != storagePolicyResourcePrefix+bucket &&
+				!strings.HasPrefix(resource, storagePolicyResourcePrefix+bucket+"/") {
+				return fmt.Errorf("%s: invalid resource %q, expected %q or %q",
+					name, resource, storagePolicyResourcePrefix+bucket, storagePolicyResourcePrefix+bucket+"/*")
+			}
+		}
+
+		for _, ip := range s.sourceIPs() {
+			if net.ParseIP(ip) == nil {
+				if _, _, err := net.ParseCIDR(ip); err != nil {
+					return fmt.Errorf("%s: invalid source IP address %q", name, ip)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// public returns true if the policy grants access to the bucket to anyone,
+// regardless of the bucket ACL.
+func (p *storagePolicy) public() bool {
+	for _, s := range p.Statement {
+		if s.Effect == "Allow" && s.Principal.anyone() && len(s.Condition) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// restrictsPublic returns true if the policy revokes access to the bucket
+// to anyone, regardless of the bucket ACL.
+func (p *storagePolicy) restrictsPublic() bool {
+	for _, s := range p.Statement {
+		if s.Effect == "Deny" && s.Principal.anyone() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newStoragePolicy returns a simple bucket policy allowing access to the
+// bucket to the specified API keys (or anyone if none are specified), only
+// from the specified source IP addresses if any.
+func newStoragePolicy(bucket string, keys, sourceIPs []string) *storagePolicy {
+	statement := storagePolicyStatement{
+		Effect:    "Allow",
+		Principal: &storagePolicyPrincipal{AWS: storagePolicyStringList{"*"}},
+		Action:    storagePolicyStringList{"s3:*"},
+		Resource: storagePolicyStringList{
+			storagePolicyResourcePrefix + bucket,
+			storagePolicyResourcePrefix + bucket + "/*",
+		},
+	}
+
+	if len(keys) > 0 {
+		statement.Principal.AWS = keys
+	}
+
+	if len(sourceIPs) > 0 {
+		statement.Condition = map[string]map[string]interface{}{
+			"IpAddress": {"aws:SourceIp": sourceIPs},
+		}
+	}
+
+	return &storagePolicy{
+		Version:   storagePolicyVersion,
+		Statement: []storagePolicyStatement{statement},
+	}
+}
+
+type storagePolicyStatementOutput struct {
+	Sid        string   `json:"sid,omitempty"`
+	Effect     string   `json:"effect"`
+	Principals []string `json:"principals"`
+	Actions    []string `json:"actions"`
+	Resources  []string `json:"resources"`
+	SourceIPs  []string `json:"source_ips,omitempty"`
+}
+
+type storagePolicyShowOutput struct {
+	Bucket     string                         `json:"bucket"`
+	Public     bool                           `json:"public"`
+	Statements []storagePolicyStatementOutput `json:"statements"`
+}
+
+func (o *storagePolicyShowOutput) toJSON() { outputJSON(o) }
+func (o *storagePolicyShowOutput) toText() { outputText(o) }
+func (o *storagePolicyShowOutput) toTable() {
+	t := table.NewTable(os.Stdout)
+	t.SetHeader([]string{"Effect", "Principals", "Actions", "Resources", "Source IPs"})
+	defer t.Render()
+
+	for _, s := range o.Statements {
+		t.Append([]string{
+			s.Effect,
+			strings.Join(s.Principals, "\n"),
+			strings.Join(s.Actions, "\n"),
+			strings.Join(s.Resources, "\n"),
+			strings.Join(s.SourceIPs, "\n"),
+		})
+	}
+}
+
+func storagePolicyShowOutputFromPolicy(bucket string, policy *storagePolicy) *storagePolicyShowOutput {
+	out := storagePolicyShowOutput{
+		Bucket:     bucket,
+		Public:     policy.public(),
+		Statements: make([]storagePolicyStatementOutput, 0, len(policy.Statement)),
+	}
+
+	for _, s := range policy.Statement {
+		sourceIPs := s.sourceIPs()
+		sort.Strings(sourceIPs)
+
+		out.Statements = append(out.Statements, storagePolicyStatementOutput{
+			Sid:        s.Sid,
+			Effect:     s.Effect,
+			Principals: s.Principal.AWS,
+			Actions:    s.Action,
+			Resources:  s.Resource,
+			SourceIPs:  sourceIPs,
+		})
+	}
+
+	return &out
+}
+
+// storageIsNoSuchBucketPolicyError returns true if err is returned by the
+// S3 API because no policy is set on a bucket.
+func storageIsNoSuchBucketPolicyError(err error) bool {
+	var apiErr interface{ ErrorCode() string }
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode() == "NoSuchBucketPolicy"
+	}
+
+	return false
+}
+
+// getBucketPolicy returns the policy of a bucket, or nil if the bucket has
+// no policy set.
+func (c *storageClient) getBucketPolicy(bucket string) (*storagePolicy, error) {
+	res, err := c.GetBucketPolicy(gContext, &s3.GetBucketPolicyInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		if storageIsNoSuchBucketPolicyError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var policy storagePolicy
+	if err := json.Unmarshal([]byte(aws.ToString(res.Policy)), &policy); err != nil {
+		return nil, fmt.Errorf("unable to parse bucket policy: %s", err)
+	}
+
+	return &policy, nil
+}
+
+func (c *storageClient) showBucketPolicy(bucket string) (outputter, error) {
+	policy, err := c.getBucketPolicy(bucket)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve bucket policy: %s", err)
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("bucket %q has no policy set", bucket)
+	}
+
+	return storagePolicyShowOutputFromPolicy(bucket, policy), nil
+}
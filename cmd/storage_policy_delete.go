@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/spf13/cobra"
+)
+
+var storagePolicyDeleteCmd = &cobra.Command{
+	Use:     "delete sos://BUCKET",
+	Aliases: gDeleteAlias,
+	Short:   "Delete a bucket policy",
+	Long: `This command deletes the policy of a bucket, leaving access to the bucket
+controlled by its ACL only.
+
+A warning is displayed if deleting the policy changes whether the bucket is
+publicly accessible or not.
+`,
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+
+		args[0] = strings.TrimPrefix(args[0], storageBucketPrefix)
+
+		return nil
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bucket := args[0]
+
+		cmdSetForceFromFlag(cmd)
+
+		certsFile, err := cmd.Flags().GetString("certs-file")
+		if err != nil {
+			return err
+		}
+
+		storage, err := newStorageClient(
+			storageClientOptWithCertsFile(certsFile),
+			storageClientOptZoneFromBucket(bucket),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to initialize storage client: %s", err)
+		}
+
+		policy, err := storage.getBucketPolicy(bucket)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve bucket policy: %s", err)
+		}
+		if policy == nil {
+			return fmt.Errorf("bucket %q has no policy set", bucket)
+		}
+
+		aclPublic, err := storage.isBucketACLPublic(bucket)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve bucket ACL: %s", err)
+		}
+
+		if warning := storagePolicyDeleteWarning(policy, aclPublic); warning != "" {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+
+		if !askQuestion(fmt.Sprintf("Are you sure you want to delete bucket %s policy?", bucket)) {
+			return nil
+		}
+
+		if err := storage.deleteBucketPolicy(bucket); err != nil {
+			return fmt.Errorf("unable to delete bucket policy: %s", err)
+		}
+
+		if !gQuiet {
+			fmt.Println("Policy deleted successfully")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	storagePolicyDeleteCmd.Flags().BoolP("force", "f", false, cmdFlagForceHelp)
+	storagePolicyCmd.AddCommand(storagePolicyDeleteCmd)
+}
+
+// storagePolicyDeleteWarning returns a warning message if deleting the
+// specified policy changes whether the bucket is publicly accessible given
+// its ACL, or an empty string otherwise.
+func storagePolicyDeleteWarning(policy *storagePolicy, aclPublic bool) string {
+	publicBefore := policy.public() || (aclPublic && !policy.restrictsPublic())
+
+	switch {
+	case publicBefore && !aclPublic:
+		return "the bucket is currently public through its policy: " +
+			"deleting the policy will make it private, as its ACL doesn't grant public access"
+
+	case !publicBefore && aclPublic:
+		return "the bucket is currently restricted by its policy: " +
+			"deleting the policy will make it public, as its ACL grants public access"
+	}
+
+	return ""
+}
+
+// isBucketACLPublic returns true if the bucket ACL grants access to anyone.
+func (c *storageClient) isBucketACLPublic(bucket string) (bool, error) {
+	acl, err := c.GetBucketAcl(gContext, &s3.GetBucketAclInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return false, err
+	}
+
+	for _, grant := range acl.Grants {
+		if grant.Grantee != nil && aws.ToString(grant.Grantee.URI) == storageACLGranteeAllUsers {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (c *storageClient) deleteBucketPolicy(bucket string) error {
+	_, err := c.DeleteBucketPolicy(gContext, &s3.DeleteBucketPolicyInput{Bucket: aws.String(bucket)})
+	return err
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/spf13/cobra"
+)
+
+var storagePolicySetCmd = &cobra.Command{
+	Use:   "set sos://BUCKET [POLICY-FILE]",
+	Short: "Set a bucket policy",
+	Long: fmt.Sprintf(`This command sets the policy of a bucket, replacing the existing one.
+
+The policy can be specified either as a JSON policy document file ("-" to
+read it from the standard input), or built from the --allow-key and
+--source-ip flags: the resulting policy allows full access to the bucket
+to the specified API keys (or anyone if no key is specified), restricted to
+the specified source IP addresses/networks if any.
+
+Examples:
+
+    exo storage policy set sos://my-bucket policy.json
+
+    exo storage policy set sos://my-bucket \
+        --allow-key EXO1234567890abcdef12345678 \
+        --source-ip 192.0.2.0/24
+
+The policy document is validated before being applied to the bucket.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&storagePolicyShowOutput{}), ", ")),
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+
+		args[0] = strings.TrimPrefix(args[0], storageBucketPrefix)
+
+		if len(args) == 2 && (cmd.Flags().Changed("allow-key") || cmd.Flags().Changed("source-ip")) {
+			cmdExitOnUsageError(cmd, "a policy document file cannot be specified with --allow-key/--source-ip")
+		}
+
+		if len(args) == 1 && !cmd.Flags().Changed("allow-key") && !cmd.Flags().Changed("source-ip") {
+			cmdExitOnUsageError(cmd, "either a policy document file or --allow-key/--source-ip must be specified")
+		}
+
+		return nil
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bucket := args[0]
+
+		certsFile, err := cmd.Flags().GetString("certs-file")
+		if err != nil {
+			return err
+		}
+
+		var policy *storagePolicy
+		if len(args) == 2 {
+			var data []byte
+			if args[1] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[1])
+			}
+			if err != nil {
+				return fmt.Errorf("unable to read policy document: %s", err)
+			}
+
+			if policy, err = parseStoragePolicy(bucket, data); err != nil {
+				return err
+			}
+		} else {
+			keys, err := cmd.Flags().GetStringSlice("allow-key")
+			if err != nil {
+				return err
+			}
+
+			sourceIPs, err := cmd.Flags().GetStringSlice("source-ip")
+			if err != nil {
+				return err
+			}
+
+			policy = newStoragePolicy(bucket, keys, sourceIPs)
+			if err := policy.validate(bucket); err != nil {
+				return err
+			}
+		}
+
+		storage, err := newStorageClient(
+			storageClientOptWithCertsFile(certsFile),
+			storageClientOptZoneFromBucket(bucket),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to initialize storage client: %s", err)
+		}
+
+		if err := storage.setBucketPolicy(bucket, policy); err != nil {
+			return fmt.Errorf("unable to set bucket policy: %s", err)
+		}
+
+		if !gQuiet {
+			return output(storage.showBucketPolicy(bucket))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	storagePolicySetCmd.Flags().StringSlice("allow-key", nil,
+		"API key to allow access to the bucket to (can be repeated multiple times)")
+	storagePolicySetCmd.Flags().StringSlice("source-ip", nil,
+		"IP address or network (CIDR notation) to restrict access to the bucket from (can be repeated multiple times)")
+	storagePolicyCmd.AddCommand(storagePolicySetCmd)
+}
+
+func (c *storageClient) setBucketPolicy(bucket string, policy *storagePolicy) error {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.PutBucketPolicy(gContext, &s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucket),
+		Policy: aws.String(string(data)),
+	})
+
+	return err
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var storagePolicyShowCmd = &cobra.Command{
+	Use:     "show sos://BUCKET",
+	Aliases: gShowAlias,
+	Short:   "Show a bucket policy",
+	Long: fmt.Sprintf(`This command shows a summary of the statements of a bucket policy.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&storagePolicyShowOutput{}), ", ")),
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+
+		args[0] = strings.TrimPrefix(args[0], storageBucketPrefix)
+
+		return nil
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bucket := args[0]
+
+		certsFile, err := cmd.Flags().GetString("certs-file")
+		if err != nil {
+			return err
+		}
+
+		storage, err := newStorageClient(
+			storageClientOptWithCertsFile(certsFile),
+			storageClientOptZoneFromBucket(bucket),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to initialize storage client: %s", err)
+		}
+
+		return output(storage.showBucketPolicy(bucket))
+	},
+}
+
+func init() {
+	storagePolicyCmd.AddCommand(storagePolicyShowCmd)
+}
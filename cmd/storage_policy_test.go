@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseStoragePolicy(t *testing.T) {
+	policy, err := parseStoragePolicy("my-bucket", []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "public-read",
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": "s3:GetObject",
+      "Resource": "arn:aws:s3:::my-bucket/*"
+    },
+    {
+      "Effect": "Deny",
+      "Principal": {"AWS": ["EXOa", "EXOb"]},
+      "Action": ["s3:PutObject", "s3:DeleteObject"],
+      "Resource": ["arn:aws:s3:::my-bucket", "arn:aws:s3:::my-bucket/*"],
+      "Condition": {"IpAddress": {"aws:SourceIp": ["192.0.2.0/24", "198.51.100.1"]}}
+    }
+  ]
+}`))
+	require.NoError(t, err)
+	require.True(t, policy.public())
+	require.False(t, policy.restrictsPublic())
+
+	out := storagePolicyShowOutputFromPolicy("my-bucket", policy)
+	require.Len(t, out.Statements, 2)
+	require.Equal(t, []string{"*"}, out.Statements[0].Principals)
+	require.Equal(t, []string{"s3:GetObject"}, out.Statements[0].Actions)
+	require.Equal(t, []string{"EXOa", "EXOb"}, out.Statements[1].Principals)
+	require.Equal(t, []string{"192.0.2.0/24", "198.51.100.1"}, out.Statements[1].SourceIPs)
+
+	for name, doc := range map[string]string{
+		"unknown field":   `{"Version": "2012-10-17", "Statements": []}`,
+		"bad version":     `{"Version": "2008-01-01", "Statement": []}`,
+		"no statement":    `{"Version": "2012-10-17", "Statement": []}`,
+		"bad effect":      `{"Version": "2012-10-17", "Statement": [{"Effect": "Maybe", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::my-bucket"}]}`,
+		"no principal":    `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::my-bucket"}]}`,
+		"bad principal":   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "EXOa", "Action": "s3:*", "Resource": "arn:aws:s3:::my-bucket"}]}`,
+		"bad action":      `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "ec2:*", "Resource": "arn:aws:s3:::my-bucket"}]}`,
+		"other bucket":    `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::my-bucket-2"}]}`,
+		"bad source IP":   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::my-bucket", "Condition": {"IpAddress": {"aws:SourceIp": "nope"}}}]}`,
+		"invalid JSON":    `{`,
+		"string resource": `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:*", "Resource": 42}]}`,
+	} {
+		_, err := parseStoragePolicy("my-bucket", []byte(doc))
+		require.Error(t, err, name)
+	}
+}
+
+func Test_newStoragePolicy(t *testing.T) {
+	policy := newStoragePolicy("my-bucket", []string{"EXOa"}, []string{"192.0.2.0/24"})
+	require.NoError(t, policy.validate("my-bucket"))
+	require.False(t, policy.public())
+	require.Equal(t, []string{"192.0.2.0/24"}, policy.Statement[0].sourceIPs())
+
+	require.True(t, newStoragePolicy("my-bucket", nil, nil).public())
+	require.False(t, newStoragePolicy("my-bucket", nil, []string{"192.0.2.1"}).public())
+}
+
+func Test_storagePolicyDeleteWarning(t *testing.T) {
+	public := newStoragePolicy("my-bucket", nil, nil)
+	restricted := newStoragePolicy("my-bucket", []string{"EXOa"}, nil)
+	deny := newStoragePolicy("my-bucket", nil, []string{"192.0.2.1"})
+	deny.Statement[0].Effect = "Deny"
+
+	require.Contains(t, storagePolicyDeleteWarning(public, false), "make it private")
+	require.Empty(t, storagePolicyDeleteWarning(public, true))
+	require.Empty(t, storagePolicyDeleteWarning(restricted, false))
+	require.Empty(t, storagePolicyDeleteWarning(restricted, true))
+	require.Contains(t, storagePolicyDeleteWarning(deny, true), "make it public")
+	require.Empty(t, storagePolicyDeleteWarning(deny, false))
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/exoscale/cli/table"
+	"github.com/spf13/cobra"
+)
+
+var storageRetentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Manage objects retention",
+	Long: `These commands allow you to manage the retention of objects stored in
+buckets created with object lock enabled (see "exo storage mb --object-lock").
+
+A retained object cannot be deleted or overwritten until its retention
+expires. In "governance" mode the retention can be lifted by users with
+specific permissions, whereas in "compliance" mode it cannot be shortened
+nor removed by anyone.
+`,
+}
+
+func init() {
+	storageCmd.AddCommand(storageRetentionCmd)
+}
+
+// parseStorageObjectPath parses a "[sos://]BUCKET/KEY" path, returning the
+// bucket and object key.
+func parseStorageObjectPath(v string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(v, storageBucketPrefix), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.HasSuffix(parts[1], "/") {
+		return "", "", fmt.Errorf("invalid object path %q, expected format %sBUCKET/OBJECT", v, storageBucketPrefix)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// parseStorageRetentionDate parses a retention date, either formatted as
+// RFC3339 or as a calendar date (in which case UTC midnight is assumed).
+func parseStorageRetentionDate(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q, expected format YYYY-MM-DD or RFC3339", v)
+}
+
+// requireObjectLock returns an error if the specified bucket doesn't have
+// object lock enabled.
+func (c *storageClient) requireObjectLock(bucket string) error {
+	res, err := c.GetObjectLockConfiguration(gContext, &s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if !storageIsBucketObjectLockNotFoundError(err) {
+			return fmt.Errorf("unable to retrieve bucket %q object lock configuration: %w", bucket, err)
+		}
+	} else if res.ObjectLockConfiguration != nil &&
+		res.ObjectLockConfiguration.ObjectLockEnabled == s3types.ObjectLockEnabledEnabled {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"bucket %q doesn't have object lock enabled (object lock can only be enabled at bucket creation, see %q)",
+		bucket,
+		"exo storage mb --object-lock",
+	)
+}
+
+// getObjectLockStatus returns the retention settings and legal hold status
+// of an object. An object without retention returns a nil retention.
+func (c *storageClient) getObjectLockStatus(bucket, key string) (*s3types.ObjectLockRetention, bool, error) {
+	var retention *s3types.ObjectLockRetention
+
+	res, err := c.GetObjectRetention(gContext, &s3.GetObjectRetentionInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if !storageIsObjectLockNotFoundError(err) {
+			return nil, false, err
+		}
+	} else {
+		retention = res.Retention
+	}
+
+	legalHold, err := c.GetObjectLegalHold(gContext, &s3.GetObjectLegalHoldInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if !storageIsObjectLockNotFoundError(err) {
+			return nil, false, err
+		}
+		return retention, false, nil
+	}
+
+	return retention,
+		legalHold.LegalHold != nil && legalHold.LegalHold.Status == s3types.ObjectLockLegalHoldStatusOn,
+		nil
+}
+
+// storageIsObjectLockNotFoundError returns true if err is returned by the
+// S3 API because no retention or legal hold is set on an object.
+func storageIsObjectLockNotFoundError(err error) bool {
+	var apiErr interface{ ErrorCode() string }
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode() == "NoSuchObjectLockConfiguration"
+	}
+
+	return false
+}
+
+// storageIsBucketObjectLockNotFoundError returns true if err is returned by
+// the S3 API because object lock is not enabled on a bucket.
+func storageIsBucketObjectLockNotFoundError(err error) bool {
+	var apiErr interface{ ErrorCode() string }
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ObjectLockConfigurationNotFound", "ObjectLockConfigurationNotFoundError":
+			return true
+		}
+	}
+
+	return false
+}
+
+// storageObjectLockDeleteError returns a description of the reason why
+// an object cannot be deleted due to its retention or legal hold, or an
+// empty string if the object is not locked.
+func storageObjectLockDeleteError(
+	key string,
+	retention *s3types.ObjectLockRetention,
+	legalHold bool,
+	now time.Time,
+) string {
+	switch {
+	case legalHold:
+		return fmt.Sprintf("%s: object is under legal hold", key)
+
+	case retention != nil && retention.RetainUntilDate != nil && retention.RetainUntilDate.After(now):
+		return fmt.Sprintf("%s: object is retained in %s mode until %s",
+			key,
+			strings.ToLower(string(retention.Mode)),
+			retention.RetainUntilDate.UTC().Format(time.RFC3339))
+	}
+
+	return ""
+}
+
+type storageRetentionShowOutput struct {
+	Path        string `json:"path"`
+	Mode        string `json:"mode"`
+	RetainUntil string `json:"retain_until"`
+	LegalHold   bool   `json:"legal_hold"`
+}
+
+func (o *storageRetentionShowOutput) toJSON() { outputJSON(o) }
+func (o *storageRetentionShowOutput) toText() { outputText(o) }
+func (o *storageRetentionShowOutput) toTable() {
+	t := table.NewTable(os.Stdout)
+	defer t.Render()
+	t.SetHeader([]string{"Retention"})
+
+	t.Append([]string{"Path", o.Path})
+	t.Append([]string{"Mode", defaultString(&o.Mode, "n/a")})
+	t.Append([]string{"Retain Until", defaultString(&o.RetainUntil, "n/a")})
+	t.Append([]string{"Legal Hold", fmt.Sprint(o.LegalHold)})
+}
+
+var storageRetentionShowCmd = &cobra.Command{
+	Use:   "show sos://BUCKET/OBJECT",
+	Short: "Show an object retention",
+	Long: fmt.Sprintf(`This command shows the retention settings and legal hold status of an
+object.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&storageRetentionShowOutput{}), ", ")),
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+
+		return nil
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bucket, key, err := parseStorageObjectPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		certsFile, err := cmd.Flags().GetString("certs-file")
+		if err != nil {
+			return err
+		}
+
+		storage, err := newStorageClient(
+			storageClientOptWithCertsFile(certsFile),
+			storageClientOptZoneFromBucket(bucket),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to initialize storage client: %s", err)
+		}
+
+		return output(storage.showObjectRetention(bucket, key))
+	},
+}
+
+func init() {
+	storageRetentionCmd.AddCommand(storageRetentionShowCmd)
+}
+
+func (c *storageClient) showObjectRetention(bucket, key string) (outputter, error) {
+	if err := c.requireObjectLock(bucket); err != nil {
+		return nil, err
+	}
+
+	retention, legalHold, err := c.getObjectLockStatus(bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve object retention: %s", err)
+	}
+
+	out := storageRetentionShowOutput{
+		Path:      key,
+		LegalHold: legalHold,
+	}
+
+	if retention != nil {
+		out.Mode = strings.ToLower(string(retention.Mode))
+		if retention.RetainUntilDate != nil {
+			out.RetainUntil = retention.RetainUntilDate.UTC().Format(time.RFC3339)
+		}
+	}
+
+	return &out, nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+)
+
+var storageRetentionSetCmd = &cobra.Command{
+	Use:   "set sos://BUCKET/OBJECT",
+	Short: "Set an object retention",
+	Long: fmt.Sprintf(`This command sets the retention of an object.
+
+Example:
+
+    exo storage retention set sos://my-bucket/invoices/2021-06.pdf \
+        --mode compliance \
+        --until 2031-06-30
+
+Notes:
+
+  * The retention date can be specified either as a date (YYYY-MM-DD) or
+    as a RFC3339-formatted timestamp.
+  * In compliance mode, an existing retention cannot be shortened.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&storageRetentionShowOutput{}), ", ")),
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmdExitOnUsageError(cmd, "invalid arguments")
+		}
+
+		return cmdCheckRequiredFlags(cmd, []string{"mode", "until"})
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bucket, key, err := parseStorageObjectPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		certsFile, err := cmd.Flags().GetString("certs-file")
+		if err != nil {
+			return err
+		}
+
+		mode, err := cmd.Flags().GetString("mode")
+		if err != nil {
+			return err
+		}
+		if mode != "governance" && mode != "compliance" {
+			return fmt.Errorf("invalid retention mode %q, supported values are: governance, compliance", mode)
+		}
+
+		rawUntil, err := cmd.Flags().GetString("until")
+		if err != nil {
+			return err
+		}
+		until, err := parseStorageRetentionDate(rawUntil)
+		if err != nil {
+			return err
+		}
+		if !until.After(time.Now()) {
+			return fmt.Errorf("retention date %s is in the past", rawUntil)
+		}
+
+		bypassGovernance, err := cmd.Flags().GetBool("bypass-governance")
+		if err != nil {
+			return err
+		}
+
+		storage, err := newStorageClient(
+			storageClientOptWithCertsFile(certsFile),
+			storageClientOptZoneFromBucket(bucket),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to initialize storage client: %s", err)
+		}
+
+		if err := storage.setObjectRetention(bucket, key, mode, until, bypassGovernance); err != nil {
+			return fmt.Errorf("unable to set object retention: %s", err)
+		}
+
+		if !gQuiet {
+			return output(storage.showObjectRetention(bucket, key))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	storageRetentionSetCmd.Flags().String("mode", "", "retention mode (governance|compliance)")
+	storageRetentionSetCmd.Flags().String("until", "", "retention expiration date (YYYY-MM-DD or RFC3339)")
+	storageRetentionSetCmd.Flags().Bool("bypass-governance", false,
+		"allow shortening or removing an existing governance mode retention")
+	storageRetentionCmd.AddCommand(storageRetentionSetCmd)
+}
+
+func (c *storageClient) setObjectRetention(bucket, key, mode string, until time.Time, bypassGovernance bool) error {
+	if err := c.requireObjectLock(bucket); err != nil {
+		return err
+	}
+
+	_, err := c.PutObjectRetention(gContext, &s3.PutObjectRetentionInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Retention: &s3types.ObjectLockRetention{
+			Mode:            s3types.ObjectLockRetentionMode(strings.ToUpper(mode)),
+			RetainUntilDate: aws.Time(until),
+		},
+		BypassGovernanceRetention: bypassGovernance,
+	})
+
+	return err
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseStorageObjectPath(t *testing.T) {
+	bucket, key, err := parseStorageObjectPath("sos://my-bucket/some/object")
+	require.NoError(t, err)
+	require.Equal(t, "my-bucket", bucket)
+	require.Equal(t, "some/object", key)
+
+	for _, v := range []string{"sos://my-bucket", "sos://my-bucket/", "sos://my-bucket/dir/", "/object"} {
+		_, _, err = parseStorageObjectPath(v)
+		require.Error(t, err, v)
+	}
+}
+
+func Test_parseStorageRetentionDate(t *testing.T) {
+	d, err := parseStorageRetentionDate("2031-06-30")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2031, 6, 30, 0, 0, 0, 0, time.UTC), d)
+
+	d, err = parseStorageRetentionDate("2031-06-30T12:00:00+02:00")
+	require.NoError(t, err)
+	require.True(t, d.Equal(time.Date(2031, 6, 30, 10, 0, 0, 0, time.UTC)))
+
+	_, err = parseStorageRetentionDate("30/06/2031")
+	require.Error(t, err)
+}
+
+func Test_storageObjectLockDeleteError(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	retention := &s3types.ObjectLockRetention{
+		Mode:            s3types.ObjectLockRetentionModeCompliance,
+		RetainUntilDate: aws.Time(time.Date(2031, 6, 30, 0, 0, 0, 0, time.UTC)),
+	}
+	require.Equal(t,
+		"a: object is retained in compliance mode until 2031-06-30T00:00:00Z",
+		storageObjectLockDeleteError("a", retention, false, now))
+
+	require.Equal(t,
+		"a: object is under legal hold",
+		storageObjectLockDeleteError("a", nil, true, now))
+
+	expired := &s3types.ObjectLockRetention{
+		Mode:            s3types.ObjectLockRetentionModeGovernance,
+		RetainUntilDate: aws.Time(now.Add(-time.Hour)),
+	}
+	require.Empty(t, storageObjectLockDeleteError("a", expired, false, now))
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	s3manager "github.com/aws/aws-sdk-go-v2/feature/s3/manager"
@@ -20,11 +23,14 @@ import (
 )
 
 type storageUploadConfig struct {
-	bucket    string
-	prefix    string
-	acl       string
-	recursive bool
-	dryRun    bool
+	bucket       string
+	prefix       string
+	acl          string
+	headers      map[string]*string
+	metadata     map[string]string
+	storageClass string
+	recursive    bool
+	dryRun       bool
 }
 
 var storageUploadCmd = &cobra.Command{
@@ -46,6 +52,17 @@ Examples:
 
     # Upload a directory recursively
     exo storage upload -r my-files/ sos://my-bucket
+
+    # Upload files with custom headers and metadata
+    exo storage upload \
+        --cache-control "max-age=3600" \
+        --meta owner=alice \
+        -r public/ sos://my-bucket/www/
+
+Unless specified using the "--content-type" flag, the objects Content-Type
+is detected from the files extension, or from their content if the extension
+is unknown. Headers, metadata and storage class flags are applied to every
+uploaded object, including during recursive uploads.
 `,
 
 	PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -91,6 +108,30 @@ Examples:
 			return err
 		}
 
+		headers := storageHeadersFromCmdFlags(cmd.Flags())
+		if err := validateStorageUploadHeaders(headers); err != nil {
+			return err
+		}
+
+		metadata, err := cmd.Flags().GetStringToString("meta")
+		if err != nil {
+			return err
+		}
+		for k := range metadata {
+			if k == "" || strings.ContainsAny(k, storageMetadataForbiddenCharset) {
+				return fmt.Errorf("invalid metadata key %q", k)
+			}
+		}
+
+		storageClass, err := cmd.Flags().GetString("storage-class")
+		if err != nil {
+			return err
+		}
+		if storageClass != "" && !isInList(s3StorageClassToStrings(), storageClass) {
+			return fmt.Errorf("invalid storage class %q, supported values are: %s",
+				storageClass, strings.Join(s3StorageClassToStrings(), ", "))
+		}
+
 		dstParts := strings.SplitN(dst, "/", 2)
 		bucket = dstParts[0]
 		if len(dstParts) > 1 {
@@ -116,11 +157,14 @@ Examples:
 		}
 
 		return storage.uploadFiles(sources, &storageUploadConfig{
-			bucket:    bucket,
-			prefix:    prefix,
-			acl:       acl,
-			recursive: recursive,
-			dryRun:    dryRun,
+			bucket:       bucket,
+			prefix:       prefix,
+			acl:          acl,
+			headers:      headers,
+			metadata:     metadata,
+			storageClass: storageClass,
+			recursive:    recursive,
+			dryRun:       dryRun,
 		})
 	},
 }
@@ -132,6 +176,22 @@ func init() {
 		"simulate files upload, don't actually do it")
 	storageUploadCmd.Flags().BoolP("recursive", "r", false,
 		"upload directories recursively")
+	storageUploadCmd.Flags().String(strings.ToLower(storageObjectHeaderCacheControl), "",
+		`value for "Cache-Control" header`)
+	storageUploadCmd.Flags().String(strings.ToLower(storageObjectHeaderContentDisposition), "",
+		`value for "Content-Disposition" header`)
+	storageUploadCmd.Flags().String(strings.ToLower(storageObjectHeaderContentEncoding), "",
+		`value for "Content-Encoding" header`)
+	storageUploadCmd.Flags().String(strings.ToLower(storageObjectHeaderContentLanguage), "",
+		`value for "Content-Language" header`)
+	storageUploadCmd.Flags().String(strings.ToLower(storageObjectHeaderContentType), "",
+		`value for "Content-Type" header (default: detected from file)`)
+	storageUploadCmd.Flags().String(strings.ToLower(storageObjectHeaderExpires), "",
+		`value for "Expires" header (RFC822 format)`)
+	storageUploadCmd.Flags().StringToString("meta", nil,
+		"object metadata (format: KEY=VALUE, can be repeated)")
+	storageUploadCmd.Flags().String("storage-class", "",
+		fmt.Sprintf("object storage class, if supported by the endpoint (%s)", strings.Join(s3StorageClassToStrings(), "|")))
 	storageCmd.AddCommand(storageUploadCmd)
 }
 
@@ -217,7 +277,7 @@ func (c *storageClient) uploadFiles(sources []string, config *storageUploadConfi
 					return nil
 				}
 
-				return c.uploadFile(config.bucket, filePath, key, config.acl)
+				return c.uploadFile(config.bucket, filePath, key, config)
 			})
 			if err != nil {
 				return err
@@ -247,7 +307,7 @@ func (c *storageClient) uploadFiles(sources []string, config *storageUploadConfi
 				continue
 			}
 
-			if err := c.uploadFile(config.bucket, src, key, config.acl); err != nil {
+			if err := c.uploadFile(config.bucket, src, key, config); err != nil {
 				return err
 			}
 		}
@@ -256,13 +316,11 @@ func (c *storageClient) uploadFiles(sources []string, config *storageUploadConfi
 	return nil
 }
 
-func (c *storageClient) uploadFile(bucket, file, key, acl string) error {
+func (c *storageClient) uploadFile(bucket, file, key string, config *storageUploadConfig) error {
 	maxFilenameLen := 16
 
 	pb := mpb.NewWithContext(gContext,
-		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool {
-			return gQuiet
-		}),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), decorationsDisabled),
 	)
 
 	file = path.Clean(file)
@@ -288,14 +346,16 @@ func (c *storageClient) uploadFile(bucket, file, key, acl string) error {
 		return err
 	}
 
-	var contentType string
-	buf := make([]byte, 512) // http.DetectContentType() only looks at the first 512 bytes of the file.
-	if _, err = f.Read(buf); err != nil {
-		return err
-	}
-	contentType = http.DetectContentType(buf)
-	if _, err = f.Seek(0, 0); err != nil {
-		return err
+	contentType := mime.TypeByExtension(path.Ext(file))
+	if contentType == "" {
+		buf := make([]byte, 512) // http.DetectContentType() only looks at the first 512 bytes of the file.
+		if _, err = f.Read(buf); err != nil && err != io.EOF {
+			return err
+		}
+		contentType = http.DetectContentType(buf)
+		if _, err = f.Seek(0, 0); err != nil {
+			return err
+		}
 	}
 
 	putObjectInput := s3.PutObjectInput{
@@ -305,16 +365,55 @@ func (c *storageClient) uploadFile(bucket, file, key, acl string) error {
 		ContentType: aws.String(contentType),
 	}
 
-	if acl != "" {
-		putObjectInput.ACL = s3types.ObjectCannedACL(acl)
+	if config.acl != "" {
+		putObjectInput.ACL = s3types.ObjectCannedACL(config.acl)
+	}
+
+	if config.storageClass != "" {
+		putObjectInput.StorageClass = s3types.StorageClass(config.storageClass)
+	}
+
+	if len(config.metadata) > 0 {
+		putObjectInput.Metadata = config.metadata
+	}
+
+	if v, ok := config.headers[storageObjectHeaderCacheControl]; ok {
+		putObjectInput.CacheControl = v
+	}
+	if v, ok := config.headers[storageObjectHeaderContentDisposition]; ok {
+		putObjectInput.ContentDisposition = v
+	}
+	if v, ok := config.headers[storageObjectHeaderContentEncoding]; ok {
+		putObjectInput.ContentEncoding = v
+	}
+	if v, ok := config.headers[storageObjectHeaderContentLanguage]; ok {
+		putObjectInput.ContentLanguage = v
+	}
+	if v, ok := config.headers[storageObjectHeaderContentType]; ok {
+		putObjectInput.ContentType = v
+	}
+	if v, ok := config.headers[storageObjectHeaderExpires]; ok {
+		// Already validated by validateStorageUploadHeaders().
+		t, _ := time.Parse(time.RFC822, aws.ToString(v))
+		putObjectInput.Expires = &t
 	}
 
 	_, err = s3manager.
-		NewUploader(c.Client).
+		NewUploader(c.Client, func(u *s3manager.Uploader) {
+			// The uploader aborts the multipart upload of a failed upload
+			// using the upload context, which is cancelled once the CLI is
+			// interrupted: the multipart upload is aborted below instead.
+			u.LeavePartsOnError = true
+		}).
 		Upload(gContext, &putObjectInput)
 
 	pb.Wait()
 
+	var mpErr s3manager.MultiUploadFailure
+	if errors.As(err, &mpErr) && mpErr.UploadID() != "" {
+		c.abortInterruptedMultipartUpload(bucket, key, mpErr.UploadID())
+	}
+
 	if errors.Is(err, context.Canceled) {
 		fmt.Fprintf(os.Stderr, "\rUpload interrupted by user\n")
 		return nil
@@ -322,3 +421,43 @@ func (c *storageClient) uploadFile(bucket, file, key, acl string) error {
 
 	return err
 }
+
+// validateStorageUploadHeaders checks that the object headers values
+// specified by the user are valid, in order to reject them before starting
+// the transfer.
+func validateStorageUploadHeaders(headers map[string]*string) error {
+	for k, v := range headers {
+		for _, r := range aws.ToString(v) {
+			if r < ' ' || r == 0x7f {
+				return fmt.Errorf("invalid %q header value: control characters are not allowed", k)
+			}
+		}
+
+		var err error
+		switch k {
+		case storageObjectHeaderContentType, storageObjectHeaderContentDisposition:
+			_, _, err = mime.ParseMediaType(aws.ToString(v))
+
+		case storageObjectHeaderExpires:
+			if _, err := time.Parse(time.RFC822, aws.ToString(v)); err != nil {
+				return fmt.Errorf(`invalid "Expires" header value %q, expecting RFC822 format`, aws.ToString(v))
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %q header value %q: %s", k, aws.ToString(v), err)
+		}
+	}
+
+	return nil
+}
+
+func s3StorageClassToStrings() []string {
+	s3StorageClasses := s3types.StorageClassStandard.Values()
+
+	list := make([]string, len(s3StorageClasses))
+	for i, v := range s3StorageClasses {
+		list[i] = string(v)
+	}
+
+	return list
+}
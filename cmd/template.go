@@ -80,6 +80,25 @@ func getTemplateByNameOrID(zoneID *egoscale.UUID, v string, templateFilter strin
 	return template, nil
 }
 
+// validateTemplateDiskSize checks that the disk size requested (in GiB) is
+// large enough to accommodate the template specified (of size in bytes),
+// and returns an error mentioning the flag to use to set the disk size
+// otherwise. The template size is rounded up to the next GiB.
+func validateTemplateDiskSize(templateName string, templateSize, diskSize int64, diskSizeFlag string) error {
+	if minSize := (templateSize + 1<<30 - 1) >> 30; diskSize < minSize {
+		return fmt.Errorf(
+			"template %q requires a disk of at least %d GiB, but a disk size of %d GiB was requested "+
+				`(use the "--%s" flag to set a larger disk size)`,
+			templateName,
+			minSize,
+			diskSize,
+			diskSizeFlag,
+		)
+	}
+
+	return nil
+}
+
 func findTemplates(zoneID *egoscale.UUID, templateFilter string, filters ...string) ([]egoscale.Template, error) {
 	allOS := make(map[string]*egoscale.Template)
 
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateTemplateDiskSize(t *testing.T) {
+	const gib = int64(1 << 30)
+
+	require.NoError(t, validateTemplateDiskSize("ubuntu", 10*gib, 10, "disk-size"))
+	require.NoError(t, validateTemplateDiskSize("ubuntu", 10*gib, 50, "disk-size"))
+	require.NoError(t, validateTemplateDiskSize("ubuntu", 0, 10, "disk-size"))
+
+	// Partial GiBs must be rounded up.
+	require.NoError(t, validateTemplateDiskSize("ubuntu", 10*gib+gib/2, 11, "disk-size"))
+	require.EqualError(t, validateTemplateDiskSize("ubuntu", 10*gib+gib/2, 10, "disk-size"),
+		`template "ubuntu" requires a disk of at least 11 GiB, but a disk size of 10 GiB was requested `+
+			`(use the "--disk-size" flag to set a larger disk size)`)
+	require.Error(t, validateTemplateDiskSize("ubuntu", 10*gib+1, 10, "disk"))
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// gTimeout is set by the global "--timeout" flag (or the "Timeout" config
+// file key): if non-zero, the command is aborted once this duration has
+// elapsed. The time spent waiting for asynchronous operations to complete
+// (bounded by the "--wait-timeout" flag instead) or for the user to answer
+// a prompt doesn't count toward this timeout.
+var gTimeout time.Duration
+
+// commandTimeout tracks the state of the command execution timeout.
+var commandTimeout struct {
+	sync.Mutex
+	timer     *time.Timer
+	suspended int
+	expired   bool
+}
+
+// initCommandTimeout wraps gContext so that it gets cancelled once gTimeout
+// has elapsed. A timer is used rather than context.WithTimeout() in order to
+// support suspending the timeout (see suspendCommandTimeout()).
+func initCommandTimeout() {
+	if gTimeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(gContext)
+	gContext = ctx
+
+	commandTimeout.Lock()
+	defer commandTimeout.Unlock()
+
+	commandTimeout.timer = time.AfterFunc(gTimeout, func() {
+		commandTimeout.Lock()
+		commandTimeout.expired = true
+		commandTimeout.Unlock()
+		cancel()
+	})
+}
+
+// suspendCommandTimeout suspends the command execution timeout until the
+// returned function is called, at which point the timeout is re-armed for
+// the full gTimeout duration. Suspensions can be nested or concurrent.
+func suspendCommandTimeout() (resume func()) {
+	commandTimeout.Lock()
+	defer commandTimeout.Unlock()
+
+	if commandTimeout.timer == nil || commandTimeout.expired {
+		return func() {}
+	}
+
+	if commandTimeout.suspended++; commandTimeout.suspended == 1 {
+		commandTimeout.timer.Stop()
+	}
+
+	return func() {
+		commandTimeout.Lock()
+		defer commandTimeout.Unlock()
+
+		if commandTimeout.suspended--; commandTimeout.suspended == 0 && !commandTimeout.expired {
+			commandTimeout.timer.Reset(gTimeout)
+		}
+	}
+}
+
+// commandTimedOut returns true if the command execution timeout expired.
+func commandTimedOut() bool {
+	commandTimeout.Lock()
+	defer commandTimeout.Unlock()
+
+	return commandTimeout.expired
+}
+
+// errCommandTimedOut returns the error reported when the command execution
+// timeout expired.
+func errCommandTimedOut() error {
+	return fmt.Errorf("operation timed out after %s", gTimeout)
+}
+
+// setTimeoutFromConfig sets gTimeout from the configuration, unless the
+// "--timeout" flag has been specified.
+func setTimeoutFromConfig(config *config) error {
+	if config.Timeout != "" && !RootCmd.PersistentFlags().Changed("timeout") {
+		d, err := time.ParseDuration(config.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid Timeout config value %q: %s", config.Timeout, err)
+		}
+		gTimeout = d
+	}
+
+	return nil
+}
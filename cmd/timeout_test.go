@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func resetCommandTimeout(t *testing.T, d time.Duration) {
+	ctx, timeout := gContext, gTimeout
+	t.Cleanup(func() {
+		gContext, gTimeout = ctx, timeout
+
+		// The timer might have been re-armed by a deferred resume function.
+		commandTimeout.Lock()
+		defer commandTimeout.Unlock()
+		if commandTimeout.timer != nil {
+			commandTimeout.timer.Stop()
+		}
+		commandTimeout.timer, commandTimeout.suspended, commandTimeout.expired = nil, 0, false
+	})
+
+	gContext, gTimeout = context.Background(), d
+	initCommandTimeout()
+}
+
+func Test_commandTimeout(t *testing.T) {
+	resetCommandTimeout(t, 50*time.Millisecond)
+
+	select {
+	case <-gContext.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled")
+	}
+	require.True(t, commandTimedOut())
+	require.EqualError(t, errCommandTimedOut(), "operation timed out after 50ms")
+}
+
+func Test_suspendCommandTimeout(t *testing.T) {
+	resetCommandTimeout(t, 50*time.Millisecond)
+
+	resume := suspendCommandTimeout()
+	defer suspendCommandTimeout()() // nested suspension
+	time.Sleep(150 * time.Millisecond)
+	require.NoError(t, gContext.Err())
+	require.False(t, commandTimedOut())
+	resume()
+}
+
+func Test_commandTimeout_disabled(t *testing.T) {
+	resetCommandTimeout(t, 0)
+
+	suspendCommandTimeout()()
+	require.Nil(t, gContext.Done())
+	require.False(t, commandTimedOut())
+}
+
+func Test_setTimeoutFromConfig(t *testing.T) {
+	defer func(v time.Duration) { gTimeout = v }(gTimeout)
+
+	require.NoError(t, setTimeoutFromConfig(&config{Timeout: "90s"}))
+	require.Equal(t, 90*time.Second, gTimeout)
+
+	require.Error(t, setTimeoutFromConfig(&config{Timeout: "soon"}))
+}
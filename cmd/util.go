@@ -1,5 +1,12 @@
 package cmd
 
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
 // isInList returns true if v exists in the specified list, false otherwise.
 func isInList(list []string, v string) bool {
 	for _, lv := range list {
@@ -32,6 +39,15 @@ func defaultString(s *string, def string) string {
 	return def
 }
 
+// defaultInt64 returns the value of the int64 pointer i if not nil, otherwise the default value specified.
+func defaultInt64(i *int64, def int64) int64 {
+	if i != nil {
+		return *i
+	}
+
+	return def
+}
+
 // defaultBool returns the value of the bool pointer b if not nil, otherwise the default value specified.
 func defaultBool(b *bool, def bool) bool {
 	if b != nil {
@@ -40,3 +56,195 @@ func defaultBool(b *bool, def bool) bool {
 
 	return def
 }
+
+// matchLabels returns true if all the key/value pairs of the selector are
+// present in labels, false otherwise. An empty selector matches everything.
+func matchLabels(labels *map[string]string, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels == nil {
+			return false
+		}
+
+		if lv, ok := (*labels)[k]; !ok || lv != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+var relativeTimeRe = regexp.MustCompile(`^(\d+)([mhdw])$`)
+
+// createdAtFilterHelp documents the "--created-before"/"--created-after"
+// list commands filters.
+const createdAtFilterHelp = `The "--created-before" and "--created-after" flags filter the listed
+resources by creation time, specified either as a RFC3339 timestamp (e.g.
+"2021-06-01T12:00:00Z"), a date in the local timezone (e.g. "2021-06-01") or
+a duration relative to now (e.g. "90d" for 90 days ago, supported units:
+"m" for minutes, "h" for hours, "d" for days and "w" for weeks).`
+
+// parseTimeFilter parses a point in time specified either as a RFC3339
+// timestamp, a date (interpreted in the local timezone) or a duration
+// relative to now (e.g. "90d" for 90 days ago, supported units: m for
+// minutes, h for hours, d for days and w for weeks).
+func parseTimeFilter(v string, now time.Time) (time.Time, error) {
+	if m := relativeTimeRe.FindStringSubmatch(v); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration %q: %s", v, err)
+		}
+
+		switch m[2] {
+		case "m":
+			return now.Add(-time.Duration(n) * time.Minute), nil
+		case "h":
+			return now.Add(-time.Duration(n) * time.Hour), nil
+		case "d":
+			return now.AddDate(0, 0, -n), nil
+		default: // "w"
+			return now.AddDate(0, 0, -7*n), nil
+		}
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf(
+		"invalid time %q: expected a RFC3339 timestamp (e.g. 2021-06-01T12:00:00Z), "+
+			"a date (e.g. 2021-06-01) or a relative duration (e.g. 90d, 12h, 2w)", v)
+}
+
+// createdAtFilter represents the "--created-before"/"--created-after" list
+// commands filters. A zero time means no bound.
+type createdAtFilter struct {
+	before time.Time
+	after  time.Time
+}
+
+// newCreatedAtFilter returns a createdAtFilter from the "--created-before"
+// and "--created-after" flags values (see parseTimeFilter() for the
+// supported formats), empty values meaning no bound.
+func newCreatedAtFilter(before, after string) (createdAtFilter, error) {
+	var (
+		f   createdAtFilter
+		now = time.Now()
+		err error
+	)
+
+	if before != "" {
+		if f.before, err = parseTimeFilter(before, now); err != nil {
+			return f, fmt.Errorf("--created-before: %s", err)
+		}
+	}
+
+	if after != "" {
+		if f.after, err = parseTimeFilter(after, now); err != nil {
+			return f, fmt.Errorf("--created-after: %s", err)
+		}
+	}
+
+	if !f.before.IsZero() && !f.after.IsZero() && !f.after.Before(f.before) {
+		return f, fmt.Errorf("--created-after must be earlier than --created-before")
+	}
+
+	return f, nil
+}
+
+// isSet returns true if at least one bound is set.
+func (f createdAtFilter) isSet() bool {
+	return !f.before.IsZero() || !f.after.IsZero()
+}
+
+// match returns true if the creation time t is within the filter bounds. If
+// the creation time is unknown (nil), only an unset filter matches.
+func (f createdAtFilter) match(t *time.Time) bool {
+	if !f.isSet() {
+		return true
+	}
+	if t == nil {
+		return false
+	}
+
+	if !f.before.IsZero() && !t.Before(f.before) {
+		return false
+	}
+	if !f.after.IsZero() && !t.After(f.after) {
+		return false
+	}
+
+	return true
+}
+
+// labelsFromFlags returns the labels to set on a resource update operation
+// based on the "--label"/"--clear-labels" flags values, or nil if the
+// resource labels are to be left unchanged. Clearing the labels takes
+// precedence over the labels specified.
+func labelsFromFlags(labels map[string]string, labelsChanged, clearLabels bool) *map[string]string {
+	if clearLabels {
+		return &map[string]string{}
+	}
+
+	if labelsChanged {
+		return &labels
+	}
+
+	return nil
+}
+
+// closestMatch returns the candidate closest to v according to the
+// Levenshtein distance, or an empty string if no candidate is close enough
+// to be considered as a likely typo of v.
+func closestMatch(v string, candidates []string) string {
+	var (
+		match   string
+		minDist = len(v)/2 + 1
+	)
+
+	for _, c := range candidates {
+		if d := levenshteinDistance(v, c); d < minDist {
+			match, minDist = c, d
+		}
+	}
+
+	return match
+}
+
+// levenshteinDistance returns the Levenshtein distance between strings a and b.
+func levenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matchLabels(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "web"}
+
+	require.True(t, matchLabels(&labels, nil))
+	require.True(t, matchLabels(nil, nil))
+	require.True(t, matchLabels(&labels, map[string]string{"env": "prod"}))
+	require.True(t, matchLabels(&labels, map[string]string{"env": "prod", "team": "web"}))
+	require.False(t, matchLabels(&labels, map[string]string{"env": "dev"}))
+	require.False(t, matchLabels(&labels, map[string]string{"env": "prod", "owner": "alice"}))
+	require.False(t, matchLabels(nil, map[string]string{"env": "prod"}))
+}
+
+func Test_parseTimeFilter(t *testing.T) {
+	now := time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		v       string
+		want    time.Time
+		wantErr bool
+	}{
+		{v: "30m", want: now.Add(-30 * time.Minute)},
+		{v: "12h", want: now.Add(-12 * time.Hour)},
+		{v: "90d", want: time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)},
+		{v: "2w", want: time.Date(2021, 6, 16, 12, 0, 0, 0, time.UTC)},
+		{v: "2021-06-01T08:00:00+02:00", want: time.Date(2021, 6, 1, 6, 0, 0, 0, time.UTC)},
+		{v: "2021-06-01", want: time.Date(2021, 6, 1, 0, 0, 0, 0, time.Local)},
+		{v: "90", wantErr: true},
+		{v: "-90d", wantErr: true},
+		{v: "3y", wantErr: true},
+		{v: "01/06/2021", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTimeFilter(tt.v, now)
+		if tt.wantErr {
+			require.Error(t, err, tt.v)
+			continue
+		}
+		require.NoError(t, err, tt.v)
+		require.True(t, tt.want.Equal(got), "%s: expected %s, got %s", tt.v, tt.want, got)
+	}
+}
+
+func Test_createdAtFilter(t *testing.T) {
+	var (
+		before = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+		after  = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		in     = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		old    = time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+		recent = time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC)
+	)
+
+	require.True(t, createdAtFilter{}.match(nil))
+	require.True(t, createdAtFilter{}.match(&in))
+
+	f := createdAtFilter{before: before, after: after}
+	require.True(t, f.match(&in))
+	require.False(t, f.match(&old))
+	require.False(t, f.match(&recent))
+	require.False(t, f.match(&before))
+	require.False(t, f.match(nil))
+
+	require.True(t, createdAtFilter{before: before}.match(&old))
+	require.True(t, createdAtFilter{after: after}.match(&recent))
+
+	_, err := newCreatedAtFilter("2021-01-01T00:00:00Z", "2021-06-01T00:00:00Z")
+	require.Error(t, err)
+	_, err = newCreatedAtFilter("90 days", "")
+	require.Error(t, err)
+}
+
+func Test_labelsFromFlags(t *testing.T) {
+	labels := map[string]string{"env": "prod"}
+
+	require.Nil(t, labelsFromFlags(nil, false, false))
+	require.Equal(t, &labels, labelsFromFlags(labels, true, false))
+	require.Equal(t, &map[string]string{}, labelsFromFlags(nil, false, true))
+	require.Equal(t, &map[string]string{}, labelsFromFlags(labels, true, true))
+}
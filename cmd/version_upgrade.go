@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// versionUpgradeReleaseURL is the URL of the GitHub API endpoint describing
+// the latest release of the CLI.
+var versionUpgradeReleaseURL = "https://api.github.com/repos/exoscale/cli/releases/latest"
+
+// githubRelease represents a GitHub release, as returned by the GitHub API.
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// assetURL returns the download URL of the release asset name.
+func (r *githubRelease) assetURL(name string) (string, error) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL, nil
+		}
+	}
+
+	return "", fmt.Errorf("release %s has no asset %q", r.TagName, name)
+}
+
+var versionUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade exo to the latest version",
+	Long: `This command checks the latest release of exo published on GitHub, and if
+it is more recent than the running version downloads it, verifies its SHA256
+checksum and replaces the running executable with it.
+
+Using the "--check-only" flag, the command only reports whether an upgrade is
+available, exiting with status 1 if so (e.g. for use in MOTD scripts).
+`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		checkOnly, err := cmd.Flags().GetBool("check-only")
+		if err != nil {
+			return err
+		}
+
+		release, err := fetchLatestRelease(gContext, versionUpgradeReleaseURL)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve the latest release: %w", err)
+		}
+		latest := strings.TrimPrefix(release.TagName, "v")
+
+		newer, err := isNewerVersion(latest, gVersion)
+		if err != nil {
+			return err
+		}
+
+		if !newer {
+			fmt.Printf("exo is up to date (version %s)\n", gVersion)
+			return nil
+		}
+
+		if checkOnly {
+			fmt.Printf("exo version %s is available (running version %s)\n", latest, gVersion)
+			os.Exit(1)
+		}
+
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("unable to locate the running executable: %w", err)
+		}
+		if exe, err = filepath.EvalSymlinks(exe); err != nil {
+			return fmt.Errorf("unable to locate the running executable: %w", err)
+		}
+
+		archiveName := versionUpgradeArchiveName(latest, runtime.GOOS, runtime.GOARCH)
+		archive, err := downloadReleaseAsset(gContext, release, archiveName)
+		if err != nil {
+			return err
+		}
+
+		checksums, err := downloadReleaseAsset(gContext, release, versionUpgradeChecksumsName(latest))
+		if err != nil {
+			return err
+		}
+		if err := verifyReleaseAssetChecksum(archiveName, archive, checksums); err != nil {
+			return err
+		}
+
+		binary, err := extractReleaseBinary(archiveName, archive)
+		if err != nil {
+			return fmt.Errorf("unable to extract exo from %s: %w", archiveName, err)
+		}
+
+		if err := replaceExecutable(exe, binary); err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				return fmt.Errorf("unable to replace %s: permission denied. Re-run the command with "+
+					"sufficient privileges (e.g. using sudo), or download exo %s manually from %s",
+					exe, latest, "https://github.com/exoscale/cli/releases")
+			}
+			return fmt.Errorf("unable to replace %s: %w", exe, err)
+		}
+
+		if !gQuiet {
+			fmt.Printf("exo upgraded from version %s to %s\n", gVersion, latest)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	versionUpgradeCmd.Flags().Bool("check-only", false,
+		"only report whether an upgrade is available (exit status 1 if so)")
+	versionCmd.AddCommand(versionUpgradeCmd)
+}
+
+// fetchLatestRelease returns the GitHub release described at url.
+func fetchLatestRelease(ctx context.Context, url string) (*githubRelease, error) {
+	data, err := httpGet(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var release githubRelease
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, err
+	}
+	if release.TagName == "" {
+		return nil, errors.New("release has no tag")
+	}
+
+	return &release, nil
+}
+
+// downloadReleaseAsset returns the content of the asset name of a release.
+func downloadReleaseAsset(ctx context.Context, release *githubRelease, name string) ([]byte, error) {
+	url, err := release.assetURL(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := httpGet(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download %s: %w", name, err)
+	}
+
+	return data, nil
+}
+
+// httpGet returns the body of the response to a GET request to url.
+func httpGet(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", fmt.Sprintf("exoscale-cli/%s", gVersion))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", res.Status)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// isNewerVersion returns true if the version latest is more recent than the
+// version current.
+func isNewerVersion(latest, current string) (bool, error) {
+	l, err := parseVersion(latest)
+	if err != nil {
+		return false, err
+	}
+
+	c, err := parseVersion(current)
+	if err != nil {
+		return false, fmt.Errorf("unable to compare with the running version (development build?): %w", err)
+	}
+
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i], nil
+		}
+	}
+
+	return false, nil
+}
+
+// versionUpgradeArchiveName returns the name of the release archive of the
+// CLI version for the specified platform. ARM builds use the ARMv6 archive,
+// compatible with the more recent ARM versions.
+func versionUpgradeArchiveName(version, goos, goarch string) string {
+	if goarch == "arm" {
+		goarch = "armv6"
+	}
+
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+
+	return fmt.Sprintf("exoscale-cli_%s_%s_%s.%s", version, goos, goarch, ext)
+}
+
+// versionUpgradeChecksumsName returns the name of the release asset listing
+// the SHA256 checksums of the release archives of the CLI version.
+func versionUpgradeChecksumsName(version string) string {
+	return fmt.Sprintf("exoscale-cli_%s_checksums.txt", version)
+}
+
+// verifyReleaseAssetChecksum checks that the SHA256 checksum of the release
+// asset name matches the one published in the release checksums file.
+func verifyReleaseAssetChecksum(name string, data, checksums []byte) error {
+	sum := sha256.Sum256(data)
+
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[1] != name {
+			continue
+		}
+
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum mismatch for %s", name)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no published checksum for %s", name)
+}
+
+// extractReleaseBinary returns the content of the exo executable from the
+// release archive name.
+func extractReleaseBinary(name string, archive []byte) ([]byte, error) {
+	if strings.HasSuffix(name, ".zip") {
+		r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range r.File {
+			if path.Base(f.Name) != "exo.exe" {
+				continue
+			}
+
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+
+			return io.ReadAll(rc)
+		}
+
+		return nil, errors.New("executable not found in archive")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if h.Typeflag == tar.TypeReg && path.Base(h.Name) == "exo" {
+			return io.ReadAll(tr)
+		}
+	}
+
+	return nil, errors.New("executable not found in archive")
+}
+
+// replaceExecutable atomically replaces the executable at path with binary.
+func replaceExecutable(path string, binary []byte) error {
+	write := func() error { return writeFileAtomic(path, binary, 0o755) }
+
+	// A running executable can't be overwritten on Windows, but it can be
+	// renamed.
+	if runtime.GOOS == "windows" {
+		return replaceRenamedFile(path, write)
+	}
+
+	return write()
+}
+
+// replaceRenamedFile renames the file at path with a ".old" suffix before
+// calling write to write the new file, restoring the renamed file if write
+// fails.
+func replaceRenamedFile(path string, write func() error) error {
+	old := path + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return err
+	}
+
+	if err := write(); err != nil {
+		if restoreErr := os.Rename(old, path); restoreErr != nil {
+			return fmt.Errorf("%s (unable to restore the previous version from %s: %s)", err, old, restoreErr)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_isNewerVersion(t *testing.T) {
+	for _, tt := range []struct {
+		latest, current string
+		newer           bool
+	}{
+		{"1.43.0", "1.42.1", true},
+		{"v1.43.0", "1.43.0", false},
+		{"1.43.0", "1.43.1", false},
+		{"2.0.0", "1.99.99", true},
+		{"1.43.0", "1.43.0-rc1", false},
+	} {
+		newer, err := isNewerVersion(tt.latest, tt.current)
+		require.NoError(t, err)
+		require.Equal(t, tt.newer, newer, "%s > %s", tt.latest, tt.current)
+	}
+
+	_, err := isNewerVersion("1.43.0", "dev")
+	require.Error(t, err)
+}
+
+func Test_versionUpgradeArchiveName(t *testing.T) {
+	require.Equal(t, "exoscale-cli_1.43.0_linux_amd64.tar.gz", versionUpgradeArchiveName("1.43.0", "linux", "amd64"))
+	require.Equal(t, "exoscale-cli_1.43.0_linux_armv6.tar.gz", versionUpgradeArchiveName("1.43.0", "linux", "arm"))
+	require.Equal(t, "exoscale-cli_1.43.0_windows_amd64.zip", versionUpgradeArchiveName("1.43.0", "windows", "amd64"))
+}
+
+func Test_verifyReleaseAssetChecksum(t *testing.T) {
+	data := []byte("archive")
+	checksums := []byte(fmt.Sprintf("%x  exoscale-cli_1.43.0_linux_amd64.tar.gz\n%x  other.zip\n",
+		sha256.Sum256(data), sha256.Sum256([]byte("other"))))
+
+	require.NoError(t, verifyReleaseAssetChecksum("exoscale-cli_1.43.0_linux_amd64.tar.gz", data, checksums))
+	require.EqualError(t, verifyReleaseAssetChecksum("other.zip", data, checksums), "checksum mismatch for other.zip")
+	require.EqualError(t, verifyReleaseAssetChecksum("missing.zip", data, checksums), "no published checksum for missing.zip")
+}
+
+func Test_extractReleaseBinary(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{"LICENSE": "license", "exo": "binary"} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o755,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	binary, err := extractReleaseBinary("exoscale-cli_1.43.0_linux_amd64.tar.gz", buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "binary", string(binary))
+}
+
+func Test_fetchLatestRelease(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name":"v1.43.0","assets":[{"name":"a.zip","browser_download_url":"https://example.net/a.zip"}]}`))
+	}))
+	defer ts.Close()
+
+	release, err := fetchLatestRelease(context.Background(), ts.URL)
+	require.NoError(t, err)
+	require.Equal(t, "v1.43.0", release.TagName)
+
+	url, err := release.assetURL("a.zip")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.net/a.zip", url)
+
+	_, err = release.assetURL("b.zip")
+	require.Error(t, err)
+}
+
+func Test_replaceRenamedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exo")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0o755))
+
+	// The previous version is restored if the new one can't be written.
+	err := replaceRenamedFile(path, func() error { return errors.New("no space left on device") })
+	require.EqualError(t, err, "no space left on device")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "old", string(data))
+
+	require.NoError(t, replaceRenamedFile(path, func() error {
+		return writeFileAtomic(path, []byte("new"), 0o755)
+	}))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "new", string(data))
+	data, err = os.ReadFile(path + ".old")
+	require.NoError(t, err)
+	require.Equal(t, "old", string(data))
+}
@@ -94,6 +94,12 @@ func getUserDataFromFile(path string) (string, error) {
 		return "", err
 	}
 
+	return getUserData(data)
+}
+
+// getUserData returns the specified user data encoded for the API, checking
+// that it doesn't exceed the maximum length allowed.
+func getUserData(data []byte) (string, error) {
 	userData, err := encodeUserData(data)
 	if err != nil {
 		return "", err
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// exitCodeWaitTimeout is the exit code of the CLI when an asynchronous
+// operation doesn't complete within the duration set by the "--wait-timeout"
+// flag.
+const exitCodeWaitTimeout = 5
+
+var (
+	// gNoWait is set by the global "--no-wait" flag: the CLI exits as soon as
+	// an asynchronous operation has been submitted to the API instead of
+	// waiting for it to complete.
+	gNoWait bool
+
+	// gWaitTimeout is set by the global "--wait-timeout" flag: if non-zero,
+	// the CLI stops waiting for asynchronous operations to complete after
+	// this duration.
+	gWaitTimeout time.Duration
+)
+
+// asyncOperationsSubmitted receives the ID of the resource referenced by the
+// API V2 asynchronous operations submitted when in "--no-wait" mode.
+var asyncOperationsSubmitted = make(chan string, 1)
+
+// recordAsyncOperationSubmitted inspects the response to an API request, and
+// if it is an API V2 asynchronous operation notifies asyncOperationsSubmitted
+// of the ID of the resource it references (or the operation ID if it doesn't
+// reference any).
+func recordAsyncOperationSubmitted(r *http.Request, res *http.Response) {
+	if !gNoWait || res == nil || r.Method == http.MethodGet || res.StatusCode != http.StatusOK {
+		return
+	}
+
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	var op struct {
+		ID        string `json:"id"`
+		State     string `json:"state"`
+		Reference *struct {
+			ID string `json:"id"`
+		} `json:"reference"`
+	}
+	if err := json.Unmarshal(body, &op); err != nil || op.ID == "" || op.State == "" {
+		return
+	}
+
+	id := op.ID
+	if op.Reference != nil && op.Reference.ID != "" {
+		id = op.Reference.ID
+	}
+
+	select {
+	case asyncOperationsSubmitted <- id:
+	default:
+	}
+}
+
+// asyncOperationSubmitted returns a channel receiving the ID of the resource
+// referenced by the next asynchronous operation submitted, or a nil channel
+// (i.e. blocking forever) if not in "--no-wait" mode.
+func asyncOperationSubmitted() <-chan string {
+	if !gNoWait {
+		return nil
+	}
+
+	// Discard operations submitted outside of the async operations wrappers.
+	select {
+	case <-asyncOperationsSubmitted:
+	default:
+	}
+
+	return asyncOperationsSubmitted
+}
+
+// asyncWaitTimeout returns a channel receiving a value once the duration set
+// by the "--wait-timeout" flag has elapsed, or a nil channel (i.e. blocking
+// forever) if no timeout is set.
+func asyncWaitTimeout() <-chan time.Time {
+	if gWaitTimeout <= 0 {
+		return nil
+	}
+
+	return time.After(gWaitTimeout)
+}
+
+// noWaitError is returned by the asynchronous operations wrappers in
+// "--no-wait" mode once the operation has been submitted to the API: the
+// command returns without waiting for it to complete (still running its own
+// cleanup), and Execute() prints the ID of the resource referenced by the
+// operation before exiting successfully.
+type noWaitError struct {
+	id string
+}
+
+func (e noWaitError) Error() string { return fmt.Sprintf("operation submitted for resource %s", e.id) }
+
+// errNoWaitUnsupported returns the error reported in "--no-wait" mode by the
+// commands performing several asynchronous operations in a row, which can't
+// return as soon as the first one has been submitted without skipping the
+// following ones.
+func errNoWaitUnsupported(reason string) error {
+	return fmt.Errorf("--no-wait flag is not supported %s", reason)
+}
+
+// exitWaitTimeout reports the asynchronous operation that didn't complete
+// within the duration set by the "--wait-timeout" flag and exits the CLI with
+// the exitCodeWaitTimeout status code.
+func exitWaitTimeout(message string) {
+	writeCommandError(os.Stderr, nil, fmt.Errorf(
+		"timed out after %s waiting for operation %q to complete, it might still be running",
+		gWaitTimeout, strings.TrimSuffix(message, "...")))
+	os.Exit(exitCodeWaitTimeout)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_recordAsyncOperationSubmitted(t *testing.T) {
+	gNoWait = true
+	defer func() { gNoWait = false }()
+
+	response := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+	post, _ := http.NewRequest(http.MethodPost, "https://api.example.net/v2/load-balancer", nil)
+	get, _ := http.NewRequest(http.MethodGet, "https://api.example.net/v2/operation/op", nil)
+
+	submitted := asyncOperationSubmitted()
+
+	res := response(http.StatusOK, `{"id":"op","state":"pending","reference":{"id":"nlb"}}`)
+	recordAsyncOperationSubmitted(post, res)
+	require.Equal(t, "nlb", <-submitted)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"reference"`)
+
+	recordAsyncOperationSubmitted(post, response(http.StatusOK, `{"id":"op","state":"pending"}`))
+	require.Equal(t, "op", <-submitted)
+
+	for _, tc := range []struct {
+		req *http.Request
+		res *http.Response
+	}{
+		{get, response(http.StatusOK, `{"id":"op","state":"pending","reference":{"id":"nlb"}}`)},
+		{post, response(http.StatusBadRequest, `{"id":"op","state":"pending"}`)},
+		{post, response(http.StatusOK, `{"id":"nlb","name":"test"}`)},
+		{post, response(http.StatusOK, `not JSON`)},
+	} {
+		recordAsyncOperationSubmitted(tc.req, tc.res)
+		select {
+		case id := <-submitted:
+			t.Fatalf("unexpected operation submitted: %s", id)
+		default:
+		}
+	}
+
+	gNoWait = false
+	require.Nil(t, asyncOperationSubmitted())
+}
+
+func Test_decorateAsyncOperation_noWait(t *testing.T) {
+	savedContext, savedQuiet := gContext, gQuiet
+	defer func() { gContext, gQuiet, gNoWait = savedContext, savedQuiet, false }()
+	gContext, gQuiet, gNoWait = context.Background(), true, true
+
+	post, _ := http.NewRequest(http.MethodPost, "https://api.example.net/v2/instance", nil)
+	submit := func() {
+		recordAsyncOperationSubmitted(post, &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id":"op","state":"pending","reference":{"id":"instance"}}`)),
+		})
+	}
+
+	// The operation is still running when the wrapper returns.
+	wait := make(chan struct{})
+	defer close(wait)
+
+	err := decorateAsyncOperation("test", func() error {
+		submit()
+		<-wait
+		return nil
+	})
+	var noWaitErr noWaitError
+	require.ErrorAs(t, err, &noWaitErr)
+	require.Equal(t, "instance", noWaitErr.id)
+
+	// Only the last step doesn't get waited for.
+	var ran []string
+	err = decorateAsyncSteps("test",
+		asyncStep{name: "a", run: func() error { submit(); ran = append(ran, "a"); return nil }},
+		asyncStep{name: "b", run: func() error { submit(); <-wait; return nil }},
+	)
+	require.ErrorAs(t, err, &noWaitErr)
+	require.Equal(t, "instance", noWaitErr.id)
+	require.Equal(t, []string{"a"}, ran)
+}
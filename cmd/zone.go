@@ -38,19 +38,23 @@ func (o zoneListOutput) Len() int           { return len(o) }
 func (o zoneListOutput) Swap(x, y int)      { o[x], o[y] = o[y], o[x] }
 func (o zoneListOutput) Less(x, y int) bool { return o[x].Name < o[y].Name }
 
-func init() {
-	RootCmd.AddCommand(&cobra.Command{
-		Use:     "zone",
-		Aliases: []string{"zones"},
-		Short:   "List all available zones",
-		Long: fmt.Sprintf(`This command lists available Exoscale zones.
+var zoneCmd = &cobra.Command{
+	Use:     "zone",
+	Aliases: []string{"zones"},
+	Short:   "List all available zones",
+	Long: fmt.Sprintf(`This command lists available Exoscale zones.
+
+See the "list" sub-command to list the zones endpoints.
 
 Supported output template annotations: %s`,
-			strings.Join(outputterTemplateAnnotations(&zoneListOutput{}), ", ")),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return output(listZones())
-		},
-	})
+		strings.Join(outputterTemplateAnnotations(&zoneListOutput{}), ", ")),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return output(listZones())
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(zoneCmd)
 }
 
 func listZones() (outputter, error) {
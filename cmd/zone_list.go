@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+// zonePingTimeout is the maximum duration of the connection to a zone API
+// endpoint when measuring its latency.
+const zonePingTimeout = 5 * time.Second
+
+type zoneEndpointsListItemOutput struct {
+	Name        string `json:"name"`
+	APIEndpoint string `json:"api_endpoint" outputLabel:"API Endpoint"`
+	SOSEndpoint string `json:"sos_endpoint" outputLabel:"SOS Endpoint"`
+}
+
+type zoneEndpointsListOutput []zoneEndpointsListItemOutput
+
+func (o *zoneEndpointsListOutput) toJSON()  { outputJSON(o) }
+func (o *zoneEndpointsListOutput) toText()  { outputText(o) }
+func (o *zoneEndpointsListOutput) toTable() { outputTable(o) }
+
+type zonePingListItemOutput struct {
+	Name        string `json:"name"`
+	APIEndpoint string `json:"api_endpoint" outputLabel:"API Endpoint"`
+	SOSEndpoint string `json:"sos_endpoint" outputLabel:"SOS Endpoint"`
+	Latency     string `json:"latency"`
+}
+
+type zonePingListOutput []zonePingListItemOutput
+
+func (o *zonePingListOutput) toJSON()  { outputJSON(o) }
+func (o *zonePingListOutput) toText()  { outputText(o) }
+func (o *zonePingListOutput) toTable() { outputTable(o) }
+
+type zoneListCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"list"`
+
+	Ping bool `cli-usage:"measure the latency to each zone API endpoint"`
+}
+
+func (c *zoneListCmd) cmdAliases() []string { return gListAlias }
+
+func (c *zoneListCmd) cmdShort() string { return "List zones endpoints" }
+
+func (c *zoneListCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists the zones available in the current account
+environment, along with their API and Storage (SOS) endpoints.
+
+Using the "--ping" flag, the latency to each zone API endpoint (time to
+establish a TCP connection to the HTTPS port) is measured concurrently, and
+the zones are listed from the closest to the farthest: the closest zone is
+usually the best default zone for the account configuration.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&zonePingListItemOutput{}), ", "))
+}
+
+func (c *zoneListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *zoneListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(
+		gContext,
+		exoapi.NewReqEndpoint(gCurrentAccount.Environment, gCurrentAccount.DefaultZone),
+	)
+
+	zones, err := cs.ListZones(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Strings(zones)
+
+	if !c.Ping {
+		out := make(zoneEndpointsListOutput, 0, len(zones))
+		for _, zone := range zones {
+			out = append(out, zoneEndpointsListItemOutput{
+				Name:        zone,
+				APIEndpoint: zoneAPIEndpoint(zone),
+				SOSEndpoint: zoneSOSEndpoint(zone),
+			})
+		}
+
+		return output(&out, nil)
+	}
+
+	// A zero latency means that the zone API endpoint is unreachable.
+	latencies := make(map[string]time.Duration)
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, zone := range zones {
+		wg.Add(1)
+		go func(zone string) {
+			defer wg.Done()
+
+			latency, err := pingZoneEndpoint(gContext, net.JoinHostPort(zoneAPIHost(zone), "443"))
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			latencies[zone] = latency
+			mu.Unlock()
+		}(zone)
+	}
+	wg.Wait()
+
+	// Zones are listed from the closest to the farthest, unreachable ones last.
+	sort.SliceStable(zones, func(i, j int) bool {
+		li, lj := latencies[zones[i]], latencies[zones[j]]
+		if li == 0 || lj == 0 {
+			return lj == 0 && li != 0
+		}
+		return li < lj
+	})
+
+	out := make(zonePingListOutput, 0, len(zones))
+	for _, zone := range zones {
+		latency := "unreachable"
+		if l, ok := latencies[zone]; ok {
+			latency = l.Round(time.Millisecond).String()
+		}
+
+		out = append(out, zonePingListItemOutput{
+			Name:        zone,
+			APIEndpoint: zoneAPIEndpoint(zone),
+			SOSEndpoint: zoneSOSEndpoint(zone),
+			Latency:     latency,
+		})
+	}
+
+	return output(&out, nil)
+}
+
+// zoneAPIHost returns the host of the API endpoint of the specified zone in
+// the current account environment.
+func zoneAPIHost(zone string) string {
+	endpoint := exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone)
+	return endpoint.Host()
+}
+
+// zoneAPIEndpoint returns the URL of the API endpoint of the specified zone
+// in the current account environment.
+func zoneAPIEndpoint(zone string) string {
+	return "https://" + zoneAPIHost(zone) + "/v2"
+}
+
+// zoneSOSEndpoint returns the URL of the Storage (SOS) endpoint of the
+// specified zone for the current account.
+func zoneSOSEndpoint(zone string) string {
+	return strings.Replace(gCurrentAccount.SosEndpoint, "{zone}", zone, 1)
+}
+
+// pingZoneEndpoint returns the time required to establish a TCP connection
+// to the specified address ("HOST:PORT").
+func pingZoneEndpoint(ctx context.Context, address string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, zonePingTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, err
+	}
+	latency := time.Since(start)
+	_ = conn.Close()
+
+	return latency, nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(zoneCmd, &zoneListCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPingZoneEndpoint(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := l.Addr().String()
+
+	latency, err := pingZoneEndpoint(context.Background(), address)
+	require.NoError(t, err)
+	require.Greater(t, int64(latency), int64(0))
+
+	require.NoError(t, l.Close())
+	_, err = pingZoneEndpoint(context.Background(), address)
+	require.Error(t, err)
+}
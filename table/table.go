@@ -8,6 +8,10 @@ import (
 	"golang.org/x/term"
 )
 
+// Plain disables the rich formatting of the tables rendered to a terminal,
+// e.g. when the CLI output decorations are disabled.
+var Plain bool
+
 // Table wraps tableWriter.Table
 type Table struct {
 	*tablewriter.Table
@@ -21,7 +25,7 @@ func NewTable(fd *os.File) *Table {
 	t.SetAutoWrapText(false)
 
 	// Rich formatting
-	if term.IsTerminal(int(fd.Fd())) {
+	if !Plain && term.IsTerminal(int(fd.Fd())) {
 		t.SetCenterSeparator("┼")
 		t.SetColumnSeparator("│")
 		t.SetRowSeparator("─")